@@ -11,6 +11,6 @@ var version = "dev"
 func main() {
 	cli.SetVersion(version)
 	if err := cli.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
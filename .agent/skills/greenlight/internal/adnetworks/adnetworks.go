@@ -0,0 +1,205 @@
+// Package adnetworks knows the major ad networks an app can bundle: the
+// pods, Swift packages and npm packages that bring them in, and the
+// SKAdNetwork identifiers they attribute installs with.
+package adnetworks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/deps"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// Google is the name of the Google Mobile Ads network (AdMob, Ad Manager),
+// which needs GADApplicationIdentifier in Info.plist.
+const Google = "Google Mobile Ads"
+
+// Network is an ad network and the dependencies that bundle its SDK.
+// Mediation adapters pull in the network's own SDK, so a mediated network
+// shows up as a dependency too.
+type Network struct {
+	Name string
+	// Pods are CocoaPods names, Packages substrings of Swift package
+	// repository URLs (lowercase), NPM React Native package names.
+	Pods     []string
+	Packages []string
+	NPM      []string
+	// SKAdNetworkIDs are the network's own SKAdNetwork identifiers. Networks
+	// also publish longer lists covering the demand partners they buy from
+	// (ListURL).
+	SKAdNetworkIDs []string
+	ListURL        string
+}
+
+// Networks are the ad networks greenlight detects.
+var Networks = []Network{
+	{
+		Name:           Google,
+		Pods:           []string{"Google-Mobile-Ads-SDK"},
+		Packages:       []string{"googleads/swift-package-manager-google-mobile-ads"},
+		NPM:            []string{"react-native-google-mobile-ads", "expo-ads-admob", "@react-native-firebase/admob"},
+		SKAdNetworkIDs: []string{"cstr6suwn9.skadnetwork"},
+		ListURL:        "https://developers.google.com/admob/ios/3p-skadnetworks",
+	},
+	{
+		Name:           "AppLovin",
+		Pods:           []string{"AppLovinSDK"},
+		Packages:       []string{"applovin/applovin-max-swift-package"},
+		NPM:            []string{"react-native-applovin-max"},
+		SKAdNetworkIDs: []string{"ludvb6z3bs.skadnetwork"},
+		ListURL:        "https://skadnetwork-ids.applovin.com/v1/skadnetworkids.json",
+	},
+	{
+		Name:           "ironSource",
+		Pods:           []string{"IronSourceSDK"},
+		NPM:            []string{"ironsource-mediation", "@ironsource/react-native-mediation"},
+		SKAdNetworkIDs: []string{"su67r6k2v3.skadnetwork"},
+		ListURL:        "https://developers.is.com/ironsource-mobile/ios/managing-skadnetwork-ids/",
+	},
+	{
+		Name:           "Unity Ads",
+		Pods:           []string{"UnityAds"},
+		NPM:            []string{"react-native-unity-ads"},
+		SKAdNetworkIDs: []string{"4dzt52r2t5.skadnetwork"},
+		ListURL:        "https://docs.unity.com/ads/en-us/manual/ConfiguringSKAdNetwork",
+	},
+	{
+		Name:           "Meta Audience Network",
+		Pods:           []string{"FBAudienceNetwork"},
+		NPM:            []string{"react-native-fbads"},
+		SKAdNetworkIDs: []string{"v9wttpbfk9.skadnetwork", "n38lu8286q.skadnetwork"},
+		ListURL:        "https://developers.facebook.com/docs/audience-network/setting-up/platform-setup/ios/skadnetwork",
+	},
+	{
+		Name:           "Liftoff Monetize (Vungle)",
+		Pods:           []string{"VungleAds", "VungleSDK-iOS"},
+		SKAdNetworkIDs: []string{"gta9lk7p23.skadnetwork"},
+		ListURL:        "https://support.vungle.com/hc/en-us/articles/360002925791",
+	},
+	{
+		Name:           "Mintegral",
+		Pods:           []string{"MintegralAdSDK"},
+		SKAdNetworkIDs: []string{"kbd757ywx3.skadnetwork"},
+		ListURL:        "https://dev.mintegral.com/doc/index.html?file=sdk-m_sdk-ios&lang=en",
+	},
+	{
+		Name:           "Pangle",
+		Pods:           []string{"Ads-Global"},
+		SKAdNetworkIDs: []string{"22mmun2rn5.skadnetwork", "238da6jt44.skadnetwork"},
+		ListURL:        "https://www.pangleglobal.com/integration/ios-skadnetwork",
+	},
+	{
+		Name:           "Chartboost",
+		Pods:           []string{"ChartboostSDK"},
+		SKAdNetworkIDs: []string{"f38h382jlk.skadnetwork"},
+		ListURL:        "https://docs.chartboost.com/en/monetization/integrate/ios/skadnetwork/",
+	},
+	{
+		Name:           "InMobi",
+		Pods:           []string{"InMobiSDK"},
+		SKAdNetworkIDs: []string{"wzmmz9fp6w.skadnetwork"},
+		ListURL:        "https://support.inmobi.com/monetize/sdk-documentation/ios-guidelines/skadnetwork-support",
+	},
+	{
+		Name:           "AdColony",
+		Pods:           []string{"AdColony"},
+		SKAdNetworkIDs: []string{"4pfyvq9l8r.skadnetwork"},
+		ListURL:        "https://github.com/AdColony/AdColony-iOS-SDK/wiki/SKAdNetwork",
+	},
+}
+
+// Detected is a network found among a project's dependencies.
+type Detected struct {
+	Network
+	// File is the lock file or manifest pinning the SDK, relative to the
+	// project, and Line its line there.
+	File string
+	Line int
+}
+
+// skipDirs hold installed dependencies; they are read from lock files.
+var skipDirs = map[string]bool{
+	"node_modules": true, ".git": true, "Pods": true,
+	"build": true, "dist": true, ".expo": true,
+	"DerivedData": true, "vendor": true,
+}
+
+// Detect lists the ad networks the project under root depends on, from its
+// Podfile.lock, Package.resolved and package.json files, in Networks order.
+func Detect(root string) []Detected {
+	found := map[string]Detected{}
+	add := func(n Network, file string, line int) {
+		if _, ok := found[n.Name]; !ok {
+			rel, _ := filepath.Rel(root, file)
+			found[n.Name] = Detected{Network: n, File: filepath.ToSlash(rel), Line: line}
+		}
+	}
+	sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		var pins []deps.Dep
+		switch info.Name() {
+		case "Podfile.lock":
+			pins, _ = deps.Pods(path)
+		case "Package.resolved":
+			pins, _ = deps.Packages(path)
+		case "package.json":
+			pins, _ = deps.NPM(path)
+		default:
+			return nil
+		}
+		for _, pin := range pins {
+			for _, n := range Networks {
+				if n.uses(info.Name(), pin) {
+					add(n, path, pin.Line)
+				}
+			}
+		}
+		return nil
+	})
+
+	var out []Detected
+	for _, n := range Networks {
+		if d, ok := found[n.Name]; ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// uses reports whether pin, from the lock file or manifest named file,
+// brings in the network's SDK.
+func (n Network) uses(file string, pin deps.Dep) bool {
+	switch file {
+	case "Podfile.lock":
+		return contains(n.Pods, pin.Name)
+	case "Package.resolved":
+		url := strings.ToLower(pin.URL)
+		for _, p := range n.Packages {
+			if strings.Contains(url, p) {
+				return true
+			}
+		}
+	case "package.json":
+		return contains(n.NPM, pin.Name)
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package adnetworks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Formats are the formats WriteItems writes: an Info.plist fragment, or
+// JSON for expo.ios.infoPlist.
+var Formats = []string{"plist", "json"}
+
+// WriteItems writes SKAdNetworkItems listing the identifiers of networks
+// and the already declared ones, each once, in format (plist or json). In
+// a plist each network's identifiers are preceded by a comment naming it.
+func WriteItems(w io.Writer, networks []Detected, declared []string, format string) error {
+	type group struct {
+		name string
+		ids  []string
+	}
+	seen := map[string]bool{}
+	var groups []group
+	for _, n := range networks {
+		g := group{name: n.Name}
+		for _, id := range n.SKAdNetworkIDs {
+			if !seen[id] {
+				seen[id] = true
+				g.ids = append(g.ids, id)
+			}
+		}
+		if len(g.ids) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	others := group{name: "Already declared"}
+	sorted := append([]string(nil), declared...)
+	sort.Strings(sorted)
+	for _, id := range sorted {
+		if !seen[id] {
+			seen[id] = true
+			others.ids = append(others.ids, id)
+		}
+	}
+	if len(others.ids) > 0 {
+		groups = append(groups, others)
+	}
+
+	switch format {
+	case "plist":
+		var b strings.Builder
+		b.WriteString("<key>SKAdNetworkItems</key>\n<array>\n")
+		for _, g := range groups {
+			fmt.Fprintf(&b, "\t<!-- %s -->\n", g.name)
+			for _, id := range g.ids {
+				fmt.Fprintf(&b, "\t<dict>\n\t\t<key>SKAdNetworkIdentifier</key>\n\t\t<string>%s</string>\n\t</dict>\n", id)
+			}
+		}
+		b.WriteString("</array>\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	case "json":
+		type item struct {
+			ID string `json:"SKAdNetworkIdentifier"`
+		}
+		doc := struct {
+			Items []item `json:"SKAdNetworkItems"`
+		}{Items: []item{}}
+		for _, g := range groups {
+			for _, id := range g.ids {
+				doc.Items = append(doc.Items, item{id})
+			}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	}
+	return fmt.Errorf("unknown format %q (use plist or json)", format)
+}
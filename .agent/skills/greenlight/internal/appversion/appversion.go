@@ -0,0 +1,60 @@
+// Package appversion validates and compares CFBundleShortVersionString and
+// CFBundleVersion values the way App Store Connect does at upload.
+package appversion
+
+import (
+	"strconv"
+	"strings"
+)
+
+// maxComponents is the number of period-separated integers App Store Connect
+// accepts in a version or build number.
+const maxComponents = 3
+
+// Validate returns why App Store Connect would reject v as a version string
+// or build number, or "" if it is acceptable.
+func Validate(v string) string {
+	if v == "" {
+		return "it is empty"
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) > maxComponents {
+		return "it has " + strconv.Itoa(len(parts)) + " components"
+	}
+	for _, p := range parts {
+		if p == "" {
+			return "it has an empty component"
+		}
+		for _, r := range p {
+			if r < '0' || r > '9' {
+				return "\"" + p + "\" is not a non-negative integer"
+			}
+		}
+	}
+	return ""
+}
+
+// Compare compares two versions numerically, component by component, with
+// missing components treated as 0: "1.2" equals "1.2.0" and is less than
+// "1.10". Non-numeric components compare as 0.
+func Compare(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		x, y := component(pa, i), component(pb, i)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func component(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
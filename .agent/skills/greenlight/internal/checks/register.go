@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"context"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() { scan.Register(scanner{}) }
+
+// scanner runs every App Store Connect check against the target's app, if it
+// has a client and app ID. Commands that need tiers, brand terms or build
+// cross-checks use a Runner directly.
+type scanner struct{}
+
+func (scanner) Name() string { return "asc" }
+
+func (scanner) Applies(t scan.Target) bool { return t.Client != nil && t.AppID != "" }
+
+func (s scanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	if !s.Applies(t) {
+		return nil, nil
+	}
+	r := NewRunner(t.Client)
+	if t.ProjectPath != "" {
+		r.SetProjectPath(t.ProjectPath)
+		r.SetPaths(t.Paths)
+	}
+	r.SetSelection(t.Selection)
+	results, err := r.Run(ctx, t.AppID, "", int(TierPattern))
+	if err != nil {
+		return nil, err
+	}
+	return ScanFindings(results.Findings), nil
+}
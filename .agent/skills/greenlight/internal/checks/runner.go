@@ -2,31 +2,102 @@ package checks
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
 
-	"github.com/RevylAI/greenlight/internal/asc"
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/findingorder"
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/internal/triage"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/scan"
 )
 
 // Check is an individual compliance check function.
 type Check func(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error
 
+// DefaultCheckTimeout bounds each check, so one slow API call or website
+// can't hold up the rest of the scan.
+const DefaultCheckTimeout = 90 * time.Second
+
 // Runner orchestrates all checks across tiers.
 type Runner struct {
-	client  *asc.Client
-	verbose bool
-	checks  map[Tier][]namedCheck
+	client     *asc.Client
+	checks     map[Tier][]namedCheck
+	brandTerms []string
+
+	staleBuildDays int
+	checkTimeout   time.Duration
+
+	// Version and build number of the local project or IPA, if given.
+	localSource  string
+	localVersion string
+	localBuild   string
+
+	// projectPath is the local project whose entitlements are compared with
+	// the App ID and whose code the regional rule packs read, if given.
+	projectPath string
+	// paths narrows the project files the regional rule packs read.
+	paths scan.Paths
+
+	// competitorSearch enables the iTunes Search API lookup of the app's
+	// category competitors.
+	competitorSearch bool
+
+	selection scan.Selection
 }
 
 type namedCheck struct {
+	id   string
 	name string
 	fn   Check
 }
 
-func NewRunner(client *asc.Client, verbose bool) *Runner {
+// CheckInfo describes a registered check.
+type CheckInfo struct {
+	Tier Tier   `json:"tier"`
+	ID   string `json:"id"` // for --only and --skip, e.g. "url-reachability"
+	Name string `json:"name"`
+	// RuleID is the rule ID of the check's findings, e.g.
+	// "asc/url-reachability".
+	RuleID string `json:"rule_id"`
+}
+
+// CheckFailedRuleID is the rule ID of the finding recorded when a check
+// fails to run.
+const CheckFailedRuleID = "asc/check-failed"
+
+// ruleID is the rule ID of a check's findings. It is fixed per check, so
+// locales and metadata text in finding titles never become part of it.
+func ruleID(checkID string) string {
+	return "asc/" + checkID
+}
+
+var nonIDRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// checkID derives a check's ID from its name: "URL reachability" is
+// "url-reachability".
+func checkID(name string) string {
+	return strings.Trim(nonIDRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// Catalog lists every check by tier, in the order they run.
+func Catalog() []CheckInfo {
+	return NewRunner(nil).Checks()
+}
+
+func NewRunner(client *asc.Client) *Runner {
 	r := &Runner{
-		client:  client,
-		verbose: verbose,
-		checks:  make(map[Tier][]namedCheck),
+		client: client,
+		checks: make(map[Tier][]namedCheck),
+
+		staleBuildDays: defaultStaleBuildDays,
+		checkTimeout:   DefaultCheckTimeout,
 	}
 	r.registerChecks()
 	return r
@@ -38,23 +109,124 @@ func (r *Runner) registerChecks() {
 	r.register(TierMetadata, "App name length", checkAppNameLength)
 	r.register(TierMetadata, "Version prepared", checkVersionPrepared)
 	r.register(TierMetadata, "Metadata completeness", checkMetadataCompleteness)
-	r.register(TierMetadata, "Screenshots uploaded", checkScreenshots)
+	r.register(TierMetadata, "Keyword quality", checkKeywordQuality)
+	r.register(TierMetadata, "Screenshots uploaded", r.checkScreenshots)
 	r.register(TierMetadata, "Screenshot dimensions", checkScreenshotDimensions)
+	r.register(TierMetadata, "App previews", checkAppPreviews)
 	r.register(TierMetadata, "Build processed", checkBuildProcessed)
+	r.register(TierMetadata, "Build freshness", r.checkBuildFreshness)
+	r.register(TierMetadata, "Version consistency", r.checkVersionConsistency)
+	r.register(TierMetadata, "App ID capabilities", r.checkCapabilities)
 	r.register(TierMetadata, "Age rating declared", checkAgeRating)
 	r.register(TierMetadata, "Encryption compliance", checkEncryption)
 	r.register(TierMetadata, "Territory availability", checkTerritoryAvailability)
+	r.register(TierMetadata, "Regional regulations", r.checkRegionalRegulations)
 	r.register(TierMetadata, "Pricing consistency", checkPricingConsistency)
 
 	// Tier 2: Content analysis
 	r.register(TierContent, "Platform references", checkPlatformReferences)
 	r.register(TierContent, "Placeholder content", checkPlaceholderContent)
+	r.register(TierContent, "Review notes", checkReviewNotes)
+	r.register(TierContent, "Subscription disclosures", checkSubscriptionDisclosures)
+	r.register(TierContent, "Gambling vs age rating", checkGamblingAgeRating)
+	r.register(TierContent, "Medical disclaimer", checkMedicalDisclaimer)
+	r.register(TierContent, "Media licensing", checkMediaLicensing)
+	r.register(TierContent, "Trademark and branding", r.checkTrademarks)
+	r.register(TierContent, "Localized name conflicts", checkLocalizedNames)
+	r.register(TierContent, "Competitor metadata", r.checkCompetitorMetadata)
+	r.register(TierContent, "Common misspellings", checkCommonMisspellings)
 	r.register(TierContent, "URL reachability", checkURLReachability)
+	r.register(TierContent, "Support URL content", checkSupportURLContent)
 	r.register(TierContent, "TestFlight external testing", checkTestFlightExternal)
 }
 
+// AddBrandTerms extends the competitor/brand terms flagged by the trademark check.
+func (r *Runner) AddBrandTerms(terms ...string) {
+	for _, t := range terms {
+		if t = strings.TrimSpace(t); t != "" {
+			r.brandTerms = append(r.brandTerms, t)
+		}
+	}
+}
+
+// SetStaleBuildDays sets the age at which the latest processed build is
+// flagged as stale. Values below 1 are ignored.
+func (r *Runner) SetStaleBuildDays(days int) {
+	if days > 0 {
+		r.staleBuildDays = days
+	}
+}
+
+// SetCheckTimeout sets how long each check may run before it is stopped
+// and reported as failed. Zero or negative values are ignored.
+func (r *Runner) SetCheckTimeout(d time.Duration) {
+	if d > 0 {
+		r.checkTimeout = d
+	}
+}
+
+// SetLocalVersion supplies the version and build number of the project or
+// IPA about to be uploaded (source names it in findings), so they can be
+// checked against App Store Connect.
+func (r *Runner) SetLocalVersion(source, version, build string) {
+	r.localSource = source
+	if !strings.Contains(version, "$") {
+		r.localVersion = strings.TrimSpace(version)
+	}
+	if !strings.Contains(build, "$") {
+		r.localBuild = strings.TrimSpace(build)
+	}
+}
+
+// SetProjectPath supplies the local project, whose entitlements are compared
+// with the capabilities enabled on the App ID and whose code narrows the
+// regional rule packs.
+func (r *Runner) SetProjectPath(path string) {
+	r.projectPath = path
+}
+
+// SetPaths narrows the project files read with --include and --exclude
+// patterns.
+func (r *Runner) SetPaths(p scan.Paths) {
+	r.paths = p
+}
+
+// SetCompetitorSearch enables the competitor metadata check, which sends the
+// app's name, subtitle and keywords to the iTunes Search API.
+func (r *Runner) SetCompetitorSearch(on bool) {
+	r.competitorSearch = on
+}
+
+// SetSelection limits the run to the checks and findings sel selects.
+func (r *Runner) SetSelection(sel scan.Selection) {
+	r.selection = sel
+}
+
+// Checks lists the runner's checks by tier, in the order they run.
+func (r *Runner) Checks() []CheckInfo {
+	var out []CheckInfo
+	for tier := TierMetadata; tier <= TierPattern; tier++ {
+		for _, c := range r.checks[tier] {
+			out = append(out, CheckInfo{Tier: tier, ID: c.id, Name: c.name, RuleID: ruleID(c.id)})
+		}
+	}
+	return out
+}
+
+// SortFindings puts findings in report order: severity, then tier and title.
+func SortFindings(findings []Finding) {
+	findingorder.Sort(findings, func(f Finding) findingorder.Key {
+		return findingorder.Key{
+			Severity: f.Severity,
+			Source:   fmt.Sprint(int(f.Tier)),
+			Title:    f.Title,
+			Detail:   f.Detail,
+		}
+	})
+}
+
 func (r *Runner) register(tier Tier, name string, fn Check) {
-	r.checks[tier] = append(r.checks[tier], namedCheck{name: name, fn: fn})
+	r.checks[tier] = append(r.checks[tier], namedCheck{id: checkID(name), name: name, fn: fn})
 }
 
 // Run executes all checks up to the specified max tier.
@@ -62,6 +234,10 @@ func (r *Runner) Run(ctx context.Context, appID, buildNum string, maxTier int) (
 	results := &Results{
 		AppID: appID,
 	}
+	var ids []string
+	for _, c := range r.Checks() {
+		ids = append(ids, c.ID)
+	}
 
 	for tier := TierMetadata; int(tier) <= maxTier; tier++ {
 		checks, ok := r.checks[tier]
@@ -70,25 +246,58 @@ func (r *Runner) Run(ctx context.Context, appID, buildNum string, maxTier int) (
 		}
 
 		for _, check := range checks {
-			if r.verbose {
-				fmt.Printf("  [tier %d] running: %s\n", tier, check.name)
+			if err := ctx.Err(); err != nil {
+				return nil, err
 			}
-
-			if err := check.fn(ctx, r.client, appID, &results.Findings); err != nil {
-				if r.verbose {
-					fmt.Printf("  [tier %d] error in %s: %v\n", tier, check.name, err)
+			if !r.selection.Runs(check.id, ids) {
+				slog.DebugContext(ctx, "check skipped", "tier", int(tier), "check", check.name)
+				continue
+			}
+			start, before := time.Now(), len(results.Findings)
+			checkCtx, cancel := context.WithTimeout(ctx, r.checkTimeout)
+			err := check.fn(checkCtx, r.client, appID, &results.Findings)
+			if ctx.Err() == nil && errors.Is(checkCtx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("timed out after %s; its findings may be incomplete", r.checkTimeout)
+			}
+			cancel()
+			slog.DebugContext(ctx, "check finished", "tier", int(tier), "check", check.name,
+				"findings", len(results.Findings)-before, "duration", time.Since(start))
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
 				}
+				slog.InfoContext(ctx, "check failed", "tier", int(tier), "check", check.name, "error", err)
 				// Non-fatal: record as a finding rather than aborting
 				results.Findings = append(results.Findings, Finding{
 					Tier:     tier,
+					RuleID:   CheckFailedRuleID,
 					Severity: SeverityWarn,
 					Title:    fmt.Sprintf("Check '%s' failed to run", check.name),
 					Detail:   err.Error(),
 				})
 			}
+			for i := before; i < len(results.Findings); i++ {
+				results.Findings[i].Check = check.id
+				if results.Findings[i].RuleID == "" {
+					results.Findings[i].RuleID = ruleID(check.id)
+				}
+			}
 		}
 	}
 
+	kept := results.Findings[:0]
+	for _, f := range results.Findings {
+		f.GuidelineTitle, f.GuidelineURL = guidelines.Reference(f.Guideline)
+		f.Fingerprint = findingid.Fingerprint(f.RuleID, "", "", f.Title)
+		f.Risk = risk.Score(f.Severity, f.Guideline)
+		hint := triage.For("asc", f.RuleID, f.Check, f.Guideline)
+		f.Effort, f.Owner = string(hint.Effort), string(hint.Owner)
+		if r.selection.Keeps(f.Guideline, "asc", f.Check, f.RuleID) {
+			kept = append(kept, f)
+		}
+	}
+	results.Findings = kept
+	SortFindings(results.Findings)
 	results.ComputeSummary()
 	return results, nil
 }
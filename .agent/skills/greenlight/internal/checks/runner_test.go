@@ -0,0 +1,198 @@
+package checks
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/RevylAI/greenlight/pkg/asc/asctest"
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+const testAppID = "6449000001"
+
+// runChecks runs the only checks, or every check up to tier 4 less the one
+// that searches the public App Store, against the sample app edited by edit
+// on a fake App Store Connect. setup, if set, runs before the checks.
+func runChecks(t *testing.T, edit func(*asctest.App), setup func(*asctest.Server), only ...string) *Results {
+	t.Helper()
+	srv := asctest.NewServer()
+	t.Cleanup(srv.Close)
+	app := srv.SampleApp(testAppID, "Acme", "com.acme.app")
+	if edit != nil {
+		edit(&app)
+	}
+	srv.AddApp(app)
+	if setup != nil {
+		setup(srv)
+	}
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	r := NewRunner(client)
+	sel, err := scan.NewSelection(only, []string{"localized-name-conflicts"})
+	if err != nil {
+		t.Fatalf("NewSelection: %v", err)
+	}
+	r.SetSelection(sel)
+	results, err := r.Run(context.Background(), testAppID, "", int(TierPattern))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return results
+}
+
+func findingsOf(results *Results, check string) []Finding {
+	var out []Finding
+	for _, f := range results.Findings {
+		if f.Check == check {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestRunnerSampleAppPasses(t *testing.T) {
+	var srv *asctest.Server
+	results := runChecks(t, nil, func(s *asctest.Server) { srv = s })
+	if !results.Summary.Passed {
+		t.Errorf("sample app did not pass: %+v", results.Summary)
+	}
+	for _, f := range results.Findings {
+		if f.Severity != SeverityInfo {
+			t.Errorf("unexpected %s finding from %s: %s", f.Severity, f.Check, f.Title)
+		}
+		if f.RuleID == "" || f.Fingerprint == "" || f.Check == "" {
+			t.Errorf("finding %q is missing its rule ID, fingerprint or check", f.Title)
+		}
+	}
+	if n := srv.Count("/apps/" + testAppID); n == 0 {
+		t.Error("no request for the app")
+	}
+}
+
+func TestRunnerFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(*asctest.App)
+		check    string
+		severity Severity
+		title    string // substring of the finding's title
+	}{
+		{
+			name:     "missing description",
+			edit:     func(a *asctest.App) { a.Versions[0].Localizations[0].Attributes.Description = "" },
+			check:    "metadata-completeness",
+			severity: SeverityCritical,
+			title:    "Description is empty",
+		},
+		{
+			name: "real-money gambling rated 4+",
+			edit: func(a *asctest.App) {
+				a.Versions[0].Localizations[0].Attributes.Description += " Play real-money slot machines every day."
+			},
+			check:    "gambling-vs-age-rating",
+			severity: SeverityCritical,
+			title:    "Real-money gambling",
+		},
+		{
+			name: "ambiguous gambling word",
+			edit: func(a *asctest.App) {
+				a.Versions[0].Localizations[0].Attributes.Description += " Book appointment slots in seconds."
+			},
+			check:    "gambling-vs-age-rating",
+			severity: SeverityWarn,
+			title:    "Possible gambling",
+		},
+		{
+			name:     "brand in the name",
+			edit:     func(a *asctest.App) { a.Infos[0].Localizations[0].Attributes.Name = "Acme Instagram Planner" },
+			check:    "trademark-and-branding",
+			severity: SeverityWarn,
+			title:    "Instagram",
+		},
+		{
+			name:     "platform in review notes",
+			edit:     func(a *asctest.App) { a.Versions[0].ReviewDetail.Attributes.Notes = "Same flow as our Android app." },
+			check:    "review-notes",
+			severity: SeverityWarn,
+			title:    "Android mentioned in App Review notes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := runChecks(t, tt.edit, nil, tt.check)
+			found := findingsOf(results, tt.check)
+			for _, f := range found {
+				if f.Severity == tt.severity && strings.Contains(f.Title, tt.title) {
+					if want := "asc/" + tt.check; f.RuleID != want {
+						t.Errorf("rule ID = %q, want %q", f.RuleID, want)
+					}
+					return
+				}
+			}
+			t.Errorf("no %s finding from %s with %q in its title; got %+v", tt.severity, tt.check, tt.title, found)
+		})
+	}
+}
+
+func TestRunnerForBrandPhrasing(t *testing.T) {
+	results := runChecks(t, func(a *asctest.App) {
+		a.Infos[0].Localizations[0].Attributes.Name = "Acme Planner for Instagram"
+	}, nil, "trademark-and-branding")
+	if found := findingsOf(results, "trademark-and-branding"); len(found) > 0 {
+		t.Errorf("\"for Instagram\" was reported: %+v", found)
+	}
+}
+
+func TestRunnerRecordsFailedChecks(t *testing.T) {
+	results := runChecks(t, nil, func(srv *asctest.Server) {
+		srv.Inject(asctest.Fault{Path: "/apps/*/appInfos", Status: 500})
+	}, "age-rating-declared", "metadata-completeness")
+
+	var failed []string
+	for _, f := range results.Findings {
+		if strings.HasPrefix(f.Title, "Check '") && strings.HasSuffix(f.Title, "' failed to run") {
+			failed = append(failed, f.Check)
+			if f.Severity != SeverityWarn || f.RuleID != CheckFailedRuleID || !strings.Contains(f.Detail, "500") {
+				t.Errorf("failed check finding = %+v, want a WARN with the API error", f)
+			}
+		}
+	}
+	if len(failed) == 0 {
+		t.Fatalf("no failed-check finding when appInfos answers 500; got %+v", results.Findings)
+	}
+	for _, id := range failed {
+		if id != "age-rating-declared" && id != "metadata-completeness" {
+			t.Errorf("check %s ran, want only the selected checks", id)
+		}
+	}
+}
+
+func TestRunnerRetriesTransientFaults(t *testing.T) {
+	var srv *asctest.Server
+	results := runChecks(t, nil, func(s *asctest.Server) {
+		srv = s
+		s.Inject(asctest.Fault{Path: "/apps/*/appInfos", Status: 503, Times: 2})
+	}, "age-rating-declared")
+	for _, f := range results.Findings {
+		if strings.Contains(f.Title, "failed to run") {
+			t.Errorf("check failed despite retries: %s", f.Detail)
+		}
+	}
+	if n := srv.Count("/apps/*/appInfos"); n < 3 {
+		t.Errorf("appInfos requests = %d, want at least 3 (two faults, then success)", n)
+	}
+}
+
+func TestRunnerSelection(t *testing.T) {
+	results := runChecks(t, func(a *asctest.App) {
+		a.Versions[0].Localizations[0].Attributes.Description = ""
+	}, nil, "app-name-length")
+	for _, f := range results.Findings {
+		if f.Check != "app-name-length" {
+			t.Errorf("finding from unselected check %s: %s", f.Check, f.Title)
+		}
+	}
+}
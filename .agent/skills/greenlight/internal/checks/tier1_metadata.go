@@ -3,20 +3,23 @@ package checks
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
-	"github.com/RevylAI/greenlight/internal/asc"
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/codescan"
 )
 
 // checkAppExists verifies the app is accessible via the API.
 func checkAppExists(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	app, err := client.GetApp(appID)
+	app, err := client.GetApp(ctx, appID)
 	if err != nil {
 		*findings = append(*findings, Finding{
 			Tier:     TierMetadata,
-			Severity: SeverityBlock,
+			Severity: SeverityCritical,
 			Title:    "App not found",
 			Detail:   fmt.Sprintf("Could not access app %s. Verify the app ID and API key permissions.", appID),
 			Fix:      "Check that your API key has App Manager access and the app ID is correct.",
@@ -27,7 +30,7 @@ func checkAppExists(ctx context.Context, client *asc.Client, appID string, findi
 	if app.Attributes.Name == "" {
 		*findings = append(*findings, Finding{
 			Tier:      TierMetadata,
-			Severity:  SeverityBlock,
+			Severity:  SeverityCritical,
 			Guideline: "2.3",
 			Title:     "App name is empty",
 			Detail:    "The app has no name set in App Store Connect.",
@@ -40,7 +43,7 @@ func checkAppExists(ctx context.Context, client *asc.Client, appID string, findi
 
 // checkVersionPrepared verifies a version exists in a submittable state.
 func checkVersionPrepared(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
+	versions, err := client.GetAppStoreVersions(ctx, appID)
 	if err != nil {
 		return err
 	}
@@ -48,7 +51,7 @@ func checkVersionPrepared(ctx context.Context, client *asc.Client, appID string,
 	if len(versions) == 0 {
 		*findings = append(*findings, Finding{
 			Tier:     TierMetadata,
-			Severity: SeverityBlock,
+			Severity: SeverityCritical,
 			Title:    "No version found",
 			Detail:   "No App Store version in a submittable state.",
 			Fix:      "Create a new version in App Store Connect.",
@@ -82,12 +85,12 @@ const (
 
 // checkMetadataCompleteness verifies all required metadata fields and their length limits.
 func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
+	versions, err := client.GetAppStoreVersions(ctx, appID)
 	if err != nil || len(versions) == 0 {
 		return err
 	}
 
-	localizations, err := client.GetVersionLocalizations(versions[0].ID)
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
 	if err != nil {
 		return err
 	}
@@ -95,7 +98,7 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 	if len(localizations) == 0 {
 		*findings = append(*findings, Finding{
 			Tier:      TierMetadata,
-			Severity:  SeverityBlock,
+			Severity:  SeverityCritical,
 			Guideline: "2.3",
 			Title:     "No localizations found",
 			Detail:    "Your app version has no localized metadata.",
@@ -113,7 +116,7 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 		if desc == "" {
 			*findings = append(*findings, Finding{
 				Tier:      TierMetadata,
-				Severity:  SeverityBlock,
+				Severity:  SeverityCritical,
 				Guideline: "2.3",
 				Title:     fmt.Sprintf("[%s] Description is empty", locale),
 				Detail:    "A description is required for App Store submission.",
@@ -122,7 +125,7 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 		} else if len(desc) > maxDescriptionLength {
 			*findings = append(*findings, Finding{
 				Tier:      TierMetadata,
-				Severity:  SeverityBlock,
+				Severity:  SeverityCritical,
 				Guideline: "2.3",
 				Title:     fmt.Sprintf("[%s] Description exceeds %d character limit (%d chars)", locale, maxDescriptionLength, len(desc)),
 				Detail:    "App Store Connect enforces a maximum description length.",
@@ -141,12 +144,12 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 				Detail:    "Keywords help users discover your app and are recommended.",
 				Fix:       "Add relevant keywords separated by commas.",
 			})
-		} else if len(kw) > maxKeywordsLength {
+		} else if n := utf8.RuneCountInString(kw); n > maxKeywordsLength {
 			*findings = append(*findings, Finding{
 				Tier:      TierMetadata,
-				Severity:  SeverityBlock,
+				Severity:  SeverityCritical,
 				Guideline: "2.3",
-				Title:     fmt.Sprintf("[%s] Keywords exceed %d character limit (%d chars)", locale, maxKeywordsLength, len(kw)),
+				Title:     fmt.Sprintf("[%s] Keywords exceed %d character limit (%d chars)", locale, maxKeywordsLength, n),
 				Detail:    "Keywords field has a strict 100-character limit including commas and spaces.",
 				Fix:       "Shorten your keywords to 100 characters. Remove less important terms or use shorter synonyms.",
 			})
@@ -169,7 +172,7 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 		if pt != "" && len(pt) > maxPromotionalTextLength {
 			*findings = append(*findings, Finding{
 				Tier:      TierMetadata,
-				Severity:  SeverityBlock,
+				Severity:  SeverityCritical,
 				Guideline: "2.3",
 				Title:     fmt.Sprintf("[%s] Promotional text exceeds %d character limit (%d chars)", locale, maxPromotionalTextLength, len(pt)),
 				Detail:    "Promotional text has a 170-character limit.",
@@ -181,7 +184,7 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 		if strings.TrimSpace(attrs.SupportURL) == "" {
 			*findings = append(*findings, Finding{
 				Tier:      TierMetadata,
-				Severity:  SeverityBlock,
+				Severity:  SeverityCritical,
 				Guideline: "1.5",
 				Title:     fmt.Sprintf("[%s] Support URL is missing", locale),
 				Detail:    "A support URL is required for App Store submission.",
@@ -193,57 +196,236 @@ func checkMetadataCompleteness(ctx context.Context, client *asc.Client, appID st
 	return nil
 }
 
-// checkScreenshots verifies screenshot sets exist.
-func checkScreenshots(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
-	if err != nil || len(versions) == 0 {
+// checkBuildProcessed verifies a build is processed and ready.
+func checkBuildProcessed(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	builds, err := client.GetBuilds(ctx, appID)
+	if err != nil {
 		return err
 	}
 
-	localizations, err := client.GetVersionLocalizations(versions[0].ID)
-	if err != nil || len(localizations) == 0 {
+	if len(builds) == 0 {
+		*findings = append(*findings, Finding{
+			Tier:     TierMetadata,
+			Severity: SeverityCritical,
+			Title:    "No builds found",
+			Detail:   "No builds have been uploaded to App Store Connect.",
+			Fix:      "Upload a build using Xcode, xcodebuild, or 'asc publish appstore'.",
+		})
+		return nil
+	}
+
+	latest := builds[0]
+	if latest.Attributes.ProcessingState != "VALID" {
+		*findings = append(*findings, Finding{
+			Tier:     TierMetadata,
+			Severity: SeverityCritical,
+			Title:    fmt.Sprintf("Build %s is in state: %s", latest.Attributes.Version, latest.Attributes.ProcessingState),
+			Detail:   "The build must be in VALID state before submission.",
+			Fix:      "Wait for build processing to complete, or upload a new build if processing failed.",
+		})
+	}
+
+	return nil
+}
+
+const (
+	defaultStaleBuildDays   = 30
+	testFlightBuildLifetime = 90 * 24 * time.Hour
+	buildExpiryWarning      = 14 * 24 * time.Hour
+)
+
+// checkBuildFreshness flags a stale latest build, a build close to its
+// TestFlight expiry, and a version attached to an older build than the
+// newest upload — the usual causes of "submitted the wrong build".
+func (r *Runner) checkBuildFreshness(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	builds, err := client.GetBuilds(ctx, appID)
+	if err != nil || len(builds) == 0 {
 		return err
 	}
 
-	// Check screenshots for the primary localization
-	primaryLoc := localizations[0]
-	sets, err := client.GetScreenshotSets(primaryLoc.ID)
+	var valid *asc.Build
+	for i := range builds {
+		if builds[i].Attributes.ProcessingState == "VALID" {
+			valid = &builds[i]
+			break
+		}
+	}
+
+	if valid != nil {
+		uploaded, err := time.Parse(time.RFC3339, valid.Attributes.UploadedDate)
+		if err == nil {
+			age := time.Since(uploaded)
+			days := int(age.Hours() / 24)
+			if days >= r.staleBuildDays {
+				*findings = append(*findings, Finding{
+					Tier:      TierMetadata,
+					Severity:  SeverityWarn,
+					Guideline: "2.1",
+					Title:     fmt.Sprintf("Latest build %s is %d days old", valid.Attributes.Version, days),
+					Detail:    fmt.Sprintf("The newest processed build was uploaded %s. Submitting an old build often means shipping code that no longer matches your repo or your metadata.", uploaded.Format("Jan 2, 2006")),
+					Fix:       "Archive and upload a fresh build from the commit you intend to ship.",
+				})
+			}
+
+			expires := uploaded.Add(testFlightBuildLifetime)
+			if t, err := time.Parse(time.RFC3339, valid.Attributes.ExpirationDate); err == nil {
+				expires = t
+			}
+			left := time.Until(expires)
+			switch {
+			case valid.Attributes.Expired || left <= 0:
+				*findings = append(*findings, Finding{
+					Tier:     TierMetadata,
+					Severity: SeverityWarn,
+					Title:    fmt.Sprintf("Build %s has expired in TestFlight", valid.Attributes.Version),
+					Detail:   "Testers can no longer install this build, so nobody can reproduce what App Review will see.",
+					Fix:      "Upload a new build before submitting.",
+				})
+			case left < buildExpiryWarning:
+				*findings = append(*findings, Finding{
+					Tier:     TierMetadata,
+					Severity: SeverityInfo,
+					Title:    fmt.Sprintf("Build %s expires in TestFlight in %d days", valid.Attributes.Version, int(left.Hours()/24)+1),
+					Detail:   fmt.Sprintf("TestFlight builds expire %d days after upload (%s for this one).", int(testFlightBuildLifetime.Hours()/24), expires.Format("Jan 2, 2006")),
+					Fix:      "Upload a fresh build if review or beta testing may run past the expiry date.",
+				})
+			}
+		}
+	}
+
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+	attached, err := client.GetVersionBuild(ctx, versions[0].ID)
+	if err != nil || attached == nil {
+		return err
+	}
+	newest := builds[0]
+	if attached.ID != newest.ID {
+		*findings = append(*findings, Finding{
+			Tier:     TierMetadata,
+			Severity: SeverityWarn,
+			Title:    fmt.Sprintf("Version %s uses build %s, but build %s is newer", versions[0].Attributes.VersionString, attached.Attributes.Version, newest.Attributes.Version),
+			Detail:   fmt.Sprintf("Build %s was uploaded %s, after the build attached to this version. Submitting now ships the older build.", newest.Attributes.Version, newest.Attributes.UploadedDate),
+			Fix:      "Select the intended build under App Store Connect → Version → Build, or confirm the older build is deliberate.",
+		})
+	}
+
+	return nil
+}
+
+// checkVersionConsistency cross-checks the version being prepared, the
+// released version, uploaded build numbers and (when given) the local
+// project or IPA — upload rejects versions that don't increase and build
+// numbers already used in the same version.
+func (r *Runner) checkVersionConsistency(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
 	if err != nil {
 		return err
 	}
 
-	if len(sets) == 0 {
+	var released string
+	var pending *asc.AppStoreVersion
+	for i, v := range versions {
+		if v.Attributes.AppStoreState == "READY_FOR_SALE" {
+			if released == "" || appversion.Compare(v.Attributes.VersionString, released) > 0 {
+				released = v.Attributes.VersionString
+			}
+		} else if pending == nil {
+			pending = &versions[i]
+		}
+	}
+
+	if pending != nil && released != "" && appversion.Compare(pending.Attributes.VersionString, released) <= 0 {
 		*findings = append(*findings, Finding{
 			Tier:      TierMetadata,
-			Severity:  SeverityBlock,
-			Guideline: "2.3",
-			Title:     "No screenshots uploaded",
-			Detail:    "At least one set of screenshots is required for submission.",
-			Fix:       "Upload screenshots for at least iPhone 6.7\" and 5.5\" display sizes.",
+			Severity:  SeverityCritical,
+			Guideline: "2.1",
+			Title:     fmt.Sprintf("Version %s is not higher than released version %s", pending.Attributes.VersionString, released),
+			Detail:    "Each App Store version must be higher than the version already on sale; builds for this version will be rejected at upload.",
+			Fix:       fmt.Sprintf("Change the version in App Store Connect and CFBundleShortVersionString to something higher than %s.", released),
 		})
-		return nil
 	}
 
-	// Check for required device types
-	requiredTypes := map[string]string{
-		"APP_IPHONE_67": "iPhone 6.7\" (iPhone 15 Pro Max, 16 Pro Max)",
-		"APP_IPHONE_55": "iPhone 5.5\" (iPhone 8 Plus)",
+	if r.localVersion != "" {
+		if pending != nil && appversion.Compare(r.localVersion, pending.Attributes.VersionString) != 0 {
+			*findings = append(*findings, Finding{
+				Tier:     TierMetadata,
+				Severity: SeverityWarn,
+				Title:    fmt.Sprintf("%s version %s does not match App Store Connect version %s", r.localSource, r.localVersion, pending.Attributes.VersionString),
+				Detail:   "Builds are grouped by CFBundleShortVersionString; an upload with a different version cannot be selected for the version you are preparing.",
+				Fix:      fmt.Sprintf("Set CFBundleShortVersionString (MARKETING_VERSION) to %s, or rename the version in App Store Connect.", pending.Attributes.VersionString),
+			})
+		}
+		if released != "" && appversion.Compare(r.localVersion, released) <= 0 {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityCritical,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("%s version %s is not higher than released version %s", r.localSource, r.localVersion, released),
+				Detail:    "App Store Connect rejects uploads whose CFBundleShortVersionString is not higher than the version already on sale.",
+				Fix:       fmt.Sprintf("Bump CFBundleShortVersionString (MARKETING_VERSION) above %s.", released),
+			})
+		}
 	}
 
-	foundTypes := make(map[string]bool)
-	for _, set := range sets {
-		foundTypes[set.Attributes.ScreenshotDisplayType] = true
+	builds, err := client.GetBuildTrains(ctx, appID, 50)
+	if err != nil {
+		return err
 	}
 
-	for typeKey, typeName := range requiredTypes {
-		if !foundTypes[typeKey] {
+	// Uploads are newest first; within a version, each build number should
+	// be higher than the one uploaded before it.
+	reported := map[string]bool{}
+	for i, newer := range builds {
+		for _, older := range builds[i+1:] {
+			if older.AppVersion != newer.AppVersion || reported[newer.AppVersion] {
+				continue
+			}
+			if appversion.Compare(newer.Attributes.Version, older.Attributes.Version) <= 0 {
+				reported[newer.AppVersion] = true
+				*findings = append(*findings, Finding{
+					Tier:     TierMetadata,
+					Severity: SeverityWarn,
+					Title:    fmt.Sprintf("Build numbers are not incrementing for version %s", newer.AppVersion),
+					Detail:   fmt.Sprintf("Build %s was uploaded after build %s. Non-incrementing build numbers make it easy to attach or test the wrong build, and CI scripts that derive them are likely broken.", newer.Attributes.Version, older.Attributes.Version),
+					Fix:      "Derive CFBundleVersion from a monotonically increasing counter (e.g. the CI build number or agvtool).",
+				})
+			}
+			break
+		}
+	}
+
+	if r.localBuild != "" {
+		train := r.localVersion
+		if train == "" && pending != nil {
+			train = pending.Attributes.VersionString
+		}
+		var highest string
+		for _, b := range builds {
+			if train != "" && appversion.Compare(b.AppVersion, train) != 0 {
+				continue
+			}
+			if highest == "" || appversion.Compare(b.Attributes.Version, highest) > 0 {
+				highest = b.Attributes.Version
+			}
+		}
+		if highest != "" && appversion.Compare(r.localBuild, highest) <= 0 {
+			title := fmt.Sprintf("%s build number %s is not higher than uploaded build %s", r.localSource, r.localBuild, highest)
+			if appversion.Compare(r.localBuild, highest) == 0 {
+				title = fmt.Sprintf("%s build number %s has already been uploaded", r.localSource, r.localBuild)
+			}
+			if train != "" {
+				title += " for version " + train
+			}
 			*findings = append(*findings, Finding{
 				Tier:      TierMetadata,
-				Severity:  SeverityWarn,
-				Guideline: "2.3",
-				Title:     fmt.Sprintf("Missing screenshots for %s", typeName),
-				Detail:    "Screenshots for this device size may be required depending on your app's supported devices.",
-				Fix:       fmt.Sprintf("Upload screenshots for %s display type.", typeName),
+				Severity:  SeverityCritical,
+				Guideline: "2.1",
+				Title:     title,
+				Detail:    "App Store Connect rejects uploads whose CFBundleVersion is not higher than every build already uploaded for the same version.",
+				Fix:       fmt.Sprintf("Bump CFBundleVersion (CURRENT_PROJECT_VERSION) above %s.", highest),
 			})
 		}
 	}
@@ -251,41 +433,62 @@ func checkScreenshots(ctx context.Context, client *asc.Client, appID string, fin
 	return nil
 }
 
-// checkBuildProcessed verifies a build is processed and ready.
-func checkBuildProcessed(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	builds, err := client.GetBuilds(appID)
+// checkCapabilities compares the capabilities the local entitlements enable
+// with those enabled on the App ID in the Developer portal. Signing fails for
+// entitlements the App ID lacks; capabilities only on the App ID are unused.
+func (r *Runner) checkCapabilities(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	if r.projectPath == "" {
+		return nil
+	}
+	app, err := client.GetApp(ctx, appID)
+	if err != nil || app.Attributes.BundleID == "" {
+		return err
+	}
+	bundle, err := client.FindBundleID(ctx, app.Attributes.BundleID)
+	if err != nil || bundle == nil {
+		return err
+	}
+	caps, err := client.GetBundleIDCapabilities(ctx, bundle.ID)
 	if err != nil {
 		return err
 	}
-
-	if len(builds) == 0 {
-		*findings = append(*findings, Finding{
-			Tier:     TierMetadata,
-			Severity: SeverityBlock,
-			Title:    "No builds found",
-			Detail:   "No builds have been uploaded to App Store Connect.",
-			Fix:      "Upload a build using Xcode, xcodebuild, or 'asc publish appstore'.",
-		})
-		return nil
+	enabled := make(map[string]bool)
+	for _, c := range caps {
+		enabled[c.Attributes.CapabilityType] = true
 	}
 
-	latest := builds[0]
-	if latest.Attributes.ProcessingState != "VALID" {
-		*findings = append(*findings, Finding{
-			Tier:     TierMetadata,
-			Severity: SeverityBlock,
-			Title:    fmt.Sprintf("Build %s is in state: %s", latest.Attributes.Version, latest.Attributes.ProcessingState),
-			Detail:   "The build must be in VALID state before submission.",
-			Fix:      "Wait for build processing to complete, or upload a new build if processing failed.",
-		})
+	declared := make(map[string]bool)
+	for _, d := range codescan.DeclaredCapabilities(r.projectPath) {
+		declared[d.Name] = true
+		if !enabled[d.PortalType] {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityWarn,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("%s is in the entitlements but not enabled on App ID %s", d.Name, app.Attributes.BundleID),
+				Detail:    fmt.Sprintf("%s declares %s, but the App ID doesn't have the capability, so the distribution provisioning profile won't include it and export or upload fails.", d.File, d.Entitlement),
+				Fix:       fmt.Sprintf("Enable %s for %s in Certificates, Identifiers & Profiles and regenerate the profile, or remove the entitlement.", d.Name, app.Attributes.BundleID),
+			})
+		}
+	}
+	for _, c := range codescan.Capabilities {
+		// ICLOUD also covers iCloud Documents and key-value storage.
+		if enabled[c.PortalType] && !declared[c.Name] && c.PortalType != "ICLOUD" {
+			*findings = append(*findings, Finding{
+				Tier:     TierMetadata,
+				Severity: SeverityInfo,
+				Title:    fmt.Sprintf("%s is enabled on App ID %s but not in the entitlements", c.Name, app.Attributes.BundleID),
+				Detail:   "The App ID has the capability, but the project's entitlements don't use it. Dangling capabilities are harmless to signing but are often left over from removed features.",
+				Fix:      fmt.Sprintf("Disable %s on the App ID if the app no longer uses it.", c.Name),
+			})
+		}
 	}
-
 	return nil
 }
 
 // checkAgeRating verifies age rating has been declared.
 func checkAgeRating(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	infos, err := client.GetAppInfos(appID)
+	infos, err := client.GetAppInfos(ctx, appID)
 	if err != nil {
 		return err
 	}
@@ -305,7 +508,7 @@ func checkAgeRating(ctx context.Context, client *asc.Client, appID string, findi
 	if info.Attributes.AppStoreAgeRating == "" {
 		*findings = append(*findings, Finding{
 			Tier:      TierMetadata,
-			Severity:  SeverityBlock,
+			Severity:  SeverityCritical,
 			Guideline: "1.3",
 			Title:     "Age rating not declared",
 			Detail:    "An age rating questionnaire must be completed before submission.",
@@ -316,9 +519,10 @@ func checkAgeRating(ctx context.Context, client *asc.Client, appID string, findi
 	return nil
 }
 
-// checkEncryption verifies encryption compliance status.
+// checkEncryption verifies encryption compliance status and the territory
+// obligations that come with non-exempt encryption.
 func checkEncryption(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	builds, err := client.GetBuilds(appID)
+	builds, err := client.GetBuilds(ctx, appID)
 	if err != nil || len(builds) == 0 {
 		return err
 	}
@@ -333,75 +537,40 @@ func checkEncryption(ctx context.Context, client *asc.Client, appID string, find
 			Detail:    "You haven't declared whether your app uses non-exempt encryption.",
 			Fix:       "Set ITSAppUsesNonExemptEncryption in Info.plist or declare in App Store Connect.",
 		})
+		return nil
 	}
 
-	return nil
-}
-
-// Required screenshot dimensions for each display type.
-var requiredScreenshotDimensions = map[string]struct {
-	name   string
-	width  int
-	height int
-}{
-	"APP_IPHONE_67":  {"iPhone 6.7\"", 1290, 2796},
-	"APP_IPHONE_65":  {"iPhone 6.5\"", 1284, 2778},
-	"APP_IPHONE_55":  {"iPhone 5.5\"", 1242, 2208},
-	"APP_IPAD_PRO_3GEN_129": {"iPad Pro 12.9\"", 2048, 2732},
-	"APP_IPAD_PRO_129":      {"iPad Pro 12.9\" (2nd gen)", 2048, 2732},
-}
-
-// checkScreenshotDimensions validates that uploaded screenshots have correct dimensions.
-func checkScreenshotDimensions(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
-	if err != nil || len(versions) == 0 {
-		return err
+	if !*latest.Attributes.UsesNonExemptEncryption {
+		return nil
 	}
 
-	localizations, err := client.GetVersionLocalizations(versions[0].ID)
-	if err != nil || len(localizations) == 0 {
-		return err
-	}
+	// Any App Store distribution exports the build from the US.
+	*findings = append(*findings, Finding{
+		Tier:      TierMetadata,
+		Severity:  SeverityInfo,
+		Guideline: "5.0",
+		Title:     "Annual encryption self-classification report may be required",
+		Detail:    "The build uses non-exempt encryption, and distributing it on the App Store exports it from the US. US export regulations (EAR 740.17(b)(1)) require an annual self-classification report to BIS, due February 1.",
+		Fix:       "Submit the annual self-classification report to BIS and NSA, or confirm the encryption qualifies for a full exemption.",
+	})
 
-	primaryLoc := localizations[0]
-	sets, err := client.GetScreenshotSets(primaryLoc.ID)
-	if err != nil || len(sets) == 0 {
-		return nil // other checks handle missing screenshots
+	// Non-exempt encryption carries territory-specific obligations.
+	territories, err := client.GetAppAvailability(ctx, appID)
+	if err != nil {
+		return nil // non-fatal
 	}
 
-	for _, set := range sets {
-		displayType := set.Attributes.ScreenshotDisplayType
-		expectedDims, ok := requiredScreenshotDimensions[displayType]
-		if !ok {
-			continue
-		}
-
-		screenshots, err := client.GetScreenshots(set.ID)
-		if err != nil {
-			continue
-		}
-
-		for _, ss := range screenshots {
-			if ss.Attributes.ImageAsset == nil {
-				continue
-			}
-			w := ss.Attributes.ImageAsset.Width
-			h := ss.Attributes.ImageAsset.Height
-
-			// Check both portrait and landscape orientations
-			validPortrait := w == expectedDims.width && h == expectedDims.height
-			validLandscape := w == expectedDims.height && h == expectedDims.width
-			if !validPortrait && !validLandscape {
-				*findings = append(*findings, Finding{
-					Tier:      TierMetadata,
-					Severity:  SeverityBlock,
-					Guideline: "2.3",
-					Title:     fmt.Sprintf("Screenshot wrong dimensions for %s: %dx%d", expectedDims.name, w, h),
-					Detail:    fmt.Sprintf("Expected %dx%d (portrait) or %dx%d (landscape) for %s.", expectedDims.width, expectedDims.height, expectedDims.height, expectedDims.width, expectedDims.name),
-					Fix:       fmt.Sprintf("Re-capture screenshots at the correct resolution for %s.", expectedDims.name),
-				})
-				break // one finding per set is enough
-			}
+	for _, t := range territories {
+		if t.ID == "FRA" {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityWarn,
+				Guideline: "5.0",
+				Title:     "Non-exempt encryption distributed in France",
+				Detail:    "Apps with non-exempt encryption sold in France need an encryption declaration filed with ANSSI, uploaded to App Store Connect. Without it the build can't be distributed on the French storefront.",
+				Fix:       "File the French encryption declaration and upload the approval in App Store Connect → App Information → App Encryption Documentation, or remove France from availability.",
+			})
+			break
 		}
 	}
 
@@ -410,7 +579,7 @@ func checkScreenshotDimensions(ctx context.Context, client *asc.Client, appID st
 
 // checkTestFlightExternal checks if external TestFlight testing is configured.
 func checkTestFlightExternal(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	groups, err := client.GetBetaGroups(appID)
+	groups, err := client.GetBetaGroups(ctx, appID)
 	if err != nil {
 		// Non-fatal — API may not have access
 		return nil
@@ -440,7 +609,7 @@ func checkTestFlightExternal(ctx context.Context, client *asc.Client, appID stri
 
 // checkTerritoryAvailability verifies the app is available in territories.
 func checkTerritoryAvailability(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	territories, err := client.GetAppAvailability(appID)
+	territories, err := client.GetAppAvailability(ctx, appID)
 	if err != nil {
 		return nil // non-fatal
 	}
@@ -448,7 +617,7 @@ func checkTerritoryAvailability(ctx context.Context, client *asc.Client, appID s
 	if len(territories) == 0 {
 		*findings = append(*findings, Finding{
 			Tier:      TierMetadata,
-			Severity:  SeverityBlock,
+			Severity:  SeverityCritical,
 			Title:     "App not available in any territory",
 			Detail:    "The app has no territory availability configured.",
 			Fix:       "Set territory availability in App Store Connect → Pricing and Availability.",
@@ -468,7 +637,7 @@ func checkTerritoryAvailability(ctx context.Context, client *asc.Client, appID s
 
 // checkPricingConsistency verifies pricing is set up.
 func checkPricingConsistency(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	prices, err := client.GetAppPriceSchedule(appID)
+	prices, err := client.GetAppPriceSchedule(ctx, appID)
 	if err != nil {
 		// The price schedule endpoint can fail if no pricing is configured
 		// This isn't necessarily an error for free apps
@@ -490,7 +659,7 @@ func checkPricingConsistency(ctx context.Context, client *asc.Client, appID stri
 
 // checkAppNameLength validates the app name length against App Store limits.
 func checkAppNameLength(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	app, err := client.GetApp(appID)
+	app, err := client.GetApp(ctx, appID)
 	if err != nil {
 		return nil
 	}
@@ -499,7 +668,7 @@ func checkAppNameLength(ctx context.Context, client *asc.Client, appID string, f
 	if len(name) > maxAppNameLength {
 		*findings = append(*findings, Finding{
 			Tier:      TierMetadata,
-			Severity:  SeverityBlock,
+			Severity:  SeverityCritical,
 			Guideline: "2.3",
 			Title:     fmt.Sprintf("App name exceeds %d character limit (%d chars)", maxAppNameLength, len(name)),
 			Detail:    "App Store app names are limited to 30 characters.",
@@ -510,46 +679,164 @@ func checkAppNameLength(ctx context.Context, client *asc.Client, appID string, f
 	return nil
 }
 
-// checkURLReachability verifies that support/marketing URLs are reachable.
-func checkURLReachability(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
+// Keyword terms that are rejected (pricing/superlatives) or simply wasted
+// because the App Store already indexes them.
+var (
+	blockedKeywordTerms = map[string]bool{"free": true, "best": true, "#1": true, "top": true, "cheap": true, "sale": true, "discount": true}
+	wastedKeywordTerms  = map[string]bool{"app": true, "apps": true, "iphone": true, "ipad": true, "ios": true, "apple": true}
+)
+
+// checkKeywordQuality analyzes each localization's keyword field for wasted and
+// disallowed terms and suggests an optimized replacement.
+func checkKeywordQuality(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
 	if err != nil || len(versions) == 0 {
 		return err
 	}
 
-	localizations, err := client.GetVersionLocalizations(versions[0].ID)
-	if err != nil || len(localizations) == 0 {
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil {
 		return err
 	}
 
-	httpClient := &http.Client{Timeout: 10 * time.Second}
+	// Words from the name and subtitle are already indexed per locale.
+	indexed := make(map[string]map[string]bool)
+	var appName string
+	if app, err := client.GetApp(ctx, appID); err == nil {
+		appName = app.Attributes.Name
+	}
+	if infos, err := client.GetAppInfos(ctx, appID); err == nil && len(infos) > 0 {
+		if locs, err := client.GetAppInfoLocalizations(ctx, infos[0].ID); err == nil {
+			for _, l := range locs {
+				indexed[l.Attributes.Locale] = wordSet(l.Attributes.Name + " " + l.Attributes.Subtitle)
+			}
+		}
+	}
 
 	for _, loc := range localizations {
-		urls := map[string]string{
-			"Support URL":   loc.Attributes.SupportURL,
-			"Marketing URL": loc.Attributes.MarketingURL,
+		locale := loc.Attributes.Locale
+		raw := loc.Attributes.Keywords
+		if strings.TrimSpace(raw) == "" {
+			continue // checkMetadataCompleteness reports empty keywords
 		}
 
-		for name, url := range urls {
-			if url == "" {
+		nameWords, ok := indexed[locale]
+		if !ok {
+			nameWords = wordSet(appName)
+		}
+
+		var (
+			kept       []string
+			keptSet    = make(map[string]bool)
+			duplicates []string
+			inName     []string
+			plurals    []string
+			blocked    []string
+			wasted     []string
+			whitespace int
+		)
+
+		for _, part := range strings.Split(raw, ",") {
+			trimmed := strings.TrimSpace(part)
+			whitespace += utf8.RuneCountInString(part) - utf8.RuneCountInString(trimmed)
+			if trimmed == "" {
 				continue
 			}
-			resp, err := httpClient.Get(url)
-			if err != nil || resp.StatusCode >= 400 {
-				*findings = append(*findings, Finding{
-					Tier:      TierContent,
-					Severity:  SeverityWarn,
-					Guideline: "2.3",
-					Title:     fmt.Sprintf("[%s] %s is unreachable: %s", loc.Attributes.Locale, name, url),
-					Detail:    "Apple verifies that URLs in your metadata are accessible during review.",
-					Fix:       "Ensure the URL is live and returns a 200 status code.",
-				})
-			}
-			if resp != nil {
-				resp.Body.Close()
+			term := strings.ToLower(trimmed)
+
+			switch {
+			case blockedKeywordTerms[term]:
+				blocked = append(blocked, trimmed)
+			case wastedKeywordTerms[term]:
+				wasted = append(wasted, trimmed)
+			case keptSet[term]:
+				duplicates = append(duplicates, trimmed)
+			case nameWords[term]:
+				inName = append(inName, trimmed)
+			case keptSet[singular(term)] || hasPluralOf(keptSet, term):
+				plurals = append(plurals, trimmed)
+			default:
+				kept = append(kept, trimmed)
+				keptSet[term] = true
 			}
 		}
+
+		if len(blocked) > 0 {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityWarn,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] Disallowed keyword terms: %s", locale, strings.Join(blocked, ", ")),
+				Detail:    "Pricing terms and superlatives like \"free\" or \"best\" are not allowed in keywords.",
+				Fix:       "Remove these terms from the keywords field.",
+			})
+		}
+
+		var waste []string
+		if len(duplicates) > 0 {
+			waste = append(waste, "duplicates: "+strings.Join(duplicates, ", "))
+		}
+		if len(inName) > 0 {
+			waste = append(waste, "already in name/subtitle: "+strings.Join(inName, ", "))
+		}
+		if len(plurals) > 0 {
+			waste = append(waste, "plural/singular variants: "+strings.Join(plurals, ", "))
+		}
+		if len(wasted) > 0 {
+			waste = append(waste, "indexed automatically: "+strings.Join(wasted, ", "))
+		}
+		if whitespace > 0 {
+			waste = append(waste, fmt.Sprintf("%d characters of whitespace", whitespace))
+		}
+		if len(waste) == 0 {
+			continue
+		}
+
+		suggested := strings.Join(kept, ",")
+		suggestedLen := utf8.RuneCountInString(suggested)
+		*findings = append(*findings, Finding{
+			Tier:      TierMetadata,
+			Severity:  SeverityInfo,
+			Guideline: "2.3.7",
+			Title:     fmt.Sprintf("[%s] Keyword field wastes %d of %d characters", locale, utf8.RuneCountInString(raw)-suggestedLen, maxKeywordsLength),
+			Detail:    "Wasted: " + strings.Join(waste, "; ") + ".",
+			Fix:       fmt.Sprintf("Use %q (%d chars) and fill the freed space with new terms.", suggested, suggestedLen),
+		})
 	}
 
 	return nil
 }
+
+// wordSet returns the lowercase words in s.
+func wordSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[w] = true
+	}
+	return set
+}
+
+// singular strips common English plural suffixes.
+func singular(term string) string {
+	switch {
+	case strings.HasSuffix(term, "ies") && len(term) > 4:
+		return strings.TrimSuffix(term, "ies") + "y"
+	case strings.HasSuffix(term, "es") && len(term) > 4 && strings.ContainsAny(term[len(term)-3:len(term)-2], "sxz"):
+		return strings.TrimSuffix(term, "es")
+	case strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") && len(term) > 3:
+		return strings.TrimSuffix(term, "s")
+	}
+	return term
+}
+
+// hasPluralOf reports whether an already-kept term is the plural of term.
+func hasPluralOf(kept map[string]bool, term string) bool {
+	for k := range kept {
+		if k != term && singular(k) == term {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// App preview duration limits.
+const (
+	minPreviewDuration = 15 * time.Second
+	maxPreviewDuration = 30 * time.Second
+	maxPreviewsPerSet  = 3
+)
+
+// previewSpec is the device class and accepted frame sizes (portrait; the
+// landscape equivalents are accepted too) for a preview type.
+type previewSpec struct {
+	name  string
+	class string
+	sizes [][2]int
+}
+
+var (
+	iphoneModernPreview  = [][2]int{{886, 1920}}
+	iphoneClassicPreview = [][2]int{{1080, 1920}}
+	ipadPreview          = [][2]int{{1200, 1600}, {900, 1200}}
+)
+
+var previewSpecs = map[string]previewSpec{
+	"IPHONE_67":         {"iPhone 6.7\"", "iPhone", iphoneModernPreview},
+	"IPHONE_65":         {"iPhone 6.5\"", "iPhone", iphoneModernPreview},
+	"IPHONE_61":         {"iPhone 6.1\"", "iPhone", iphoneModernPreview},
+	"IPHONE_58":         {"iPhone 5.8\"", "iPhone", iphoneModernPreview},
+	"IPHONE_55":         {"iPhone 5.5\"", "iPhone", iphoneClassicPreview},
+	"IPHONE_47":         {"iPhone 4.7\"", "iPhone", [][2]int{{750, 1334}}},
+	"IPHONE_40":         {"iPhone 4\"", "iPhone", iphoneClassicPreview},
+	"IPAD_PRO_3GEN_129": {"iPad Pro 12.9\"", "iPad", ipadPreview},
+	"IPAD_PRO_129":      {"iPad Pro 12.9\" (2nd gen)", "iPad", ipadPreview},
+	"IPAD_PRO_3GEN_11":  {"iPad Pro 11\"", "iPad", ipadPreview},
+	"IPAD_105":          {"iPad 10.5\"", "iPad", ipadPreview},
+	"IPAD_97":           {"iPad 9.7\"", "iPad", ipadPreview},
+	"DESKTOP":           {"Mac", "Mac", [][2]int{{1080, 1920}}},
+	"APPLE_TV":          {"Apple TV", "Apple TV", [][2]int{{1080, 1920}}},
+	"APPLE_VISION_PRO":  {"Apple Vision Pro", "Apple Vision Pro", [][2]int{{2160, 3840}}},
+}
+
+// screenshotClass maps a screenshot display type to its device class.
+func screenshotClass(displayType string) string {
+	switch {
+	case strings.HasPrefix(displayType, "APP_IPHONE"):
+		return "iPhone"
+	case strings.HasPrefix(displayType, "APP_IPAD"):
+		return "iPad"
+	case strings.HasPrefix(displayType, "APP_DESKTOP"):
+		return "Mac"
+	case strings.HasPrefix(displayType, "APP_APPLE_TV"):
+		return "Apple TV"
+	case strings.HasPrefix(displayType, "APP_APPLE_VISION_PRO"):
+		return "Apple Vision Pro"
+	}
+	return ""
+}
+
+// checkAppPreviews validates app preview videos: processing state, count per
+// set, duration and frame size, and device classes that have screenshots
+// but no previews.
+func checkAppPreviews(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil || len(localizations) == 0 {
+		return err
+	}
+
+	primaryLoc := localizations[0]
+	sets, err := client.GetPreviewSets(ctx, primaryLoc.ID)
+	if err != nil || len(sets) == 0 {
+		return err // previews are optional
+	}
+
+	previewClasses := make(map[string]bool)
+	for _, set := range sets {
+		spec, ok := previewSpecs[set.Attributes.PreviewType]
+		if !ok {
+			spec = previewSpec{name: set.Attributes.PreviewType}
+		}
+		previews, err := client.GetPreviews(ctx, set.ID)
+		if err != nil {
+			continue
+		}
+		if len(previews) > 0 && spec.class != "" {
+			previewClasses[spec.class] = true
+		}
+
+		if len(previews) > maxPreviewsPerSet {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityWarn,
+				Guideline: "2.3",
+				Title:     fmt.Sprintf("%d app previews for %s (limit %d)", len(previews), spec.name, maxPreviewsPerSet),
+				Detail:    fmt.Sprintf("Each device size and localization can have at most %d app previews.", maxPreviewsPerSet),
+				Fix:       "Remove the extra previews in App Store Connect → Version → App Previews and Screenshots.",
+			})
+		}
+
+		for _, p := range previews {
+			file := p.Attributes.FileName
+			if file == "" {
+				file = p.ID
+			}
+
+			state := ""
+			if s := p.DeliveryState(); s != nil {
+				state = s.State
+			}
+			switch state {
+			case "FAILED":
+				detail := "App Store Connect could not process the video."
+				if s := p.DeliveryState(); len(s.Errors) > 0 {
+					detail += " " + s.Errors[0].Description
+				}
+				*findings = append(*findings, Finding{
+					Tier:      TierMetadata,
+					Severity:  SeverityCritical,
+					Guideline: "2.3",
+					Title:     fmt.Sprintf("App preview %s failed processing (%s)", file, spec.name),
+					Detail:    detail,
+					Fix:       "Delete the preview and upload a re-encoded video (H.264 or ProRes 422, 30fps, stereo AAC audio).",
+				})
+				continue
+			case "AWAITING_UPLOAD":
+				*findings = append(*findings, Finding{
+					Tier:      TierMetadata,
+					Severity:  SeverityCritical,
+					Guideline: "2.3",
+					Title:     fmt.Sprintf("App preview %s upload never finished (%s)", file, spec.name),
+					Detail:    "The preview was reserved but the video was not uploaded. Incomplete previews block submission.",
+					Fix:       "Delete the preview and upload it again.",
+				})
+				continue
+			case "UPLOAD_COMPLETE", "PROCESSING":
+				*findings = append(*findings, Finding{
+					Tier:      TierMetadata,
+					Severity:  SeverityWarn,
+					Guideline: "2.3",
+					Title:     fmt.Sprintf("App preview %s is still processing (%s)", file, spec.name),
+					Detail:    "Previews usually finish processing within minutes. The version can't be submitted while a preview is processing, and one stuck for hours rarely recovers.",
+					Fix:       "Wait for processing to finish; if it stays stuck, delete the preview and upload it again.",
+				})
+				continue
+			}
+
+			if p.Attributes.VideoURL == "" {
+				continue
+			}
+			video, err := client.ProbeVideo(ctx, p.Attributes.VideoURL)
+			if err != nil {
+				continue
+			}
+
+			if video.Duration < minPreviewDuration || video.Duration > maxPreviewDuration {
+				*findings = append(*findings, Finding{
+					Tier:      TierMetadata,
+					Severity:  SeverityWarn,
+					Guideline: "2.3",
+					Title:     fmt.Sprintf("App preview %s is %.0fs (%s)", file, video.Duration.Seconds(), spec.name),
+					Detail:    fmt.Sprintf("App previews must be between %.0f and %.0f seconds long.", minPreviewDuration.Seconds(), maxPreviewDuration.Seconds()),
+					Fix:       "Trim or extend the video to 15–30 seconds and upload it again.",
+				})
+			}
+
+			if len(spec.sizes) > 0 && video.Width > 0 && !previewSizeAccepted(spec.sizes, video.Width, video.Height) {
+				*findings = append(*findings, Finding{
+					Tier:      TierMetadata,
+					Severity:  SeverityWarn,
+					Guideline: "2.3",
+					Title:     fmt.Sprintf("App preview %s has wrong resolution for %s: %dx%d", file, spec.name, video.Width, video.Height),
+					Detail:    fmt.Sprintf("Accepted resolutions for %s: %s.", spec.name, formatPreviewSizes(spec.sizes)),
+					Fix:       fmt.Sprintf("Export the preview at an accepted %s resolution.", spec.name),
+				})
+			}
+		}
+	}
+
+	if len(previewClasses) == 0 {
+		return nil
+	}
+
+	// Device classes the listing has screenshots for but no previews
+	screenshotSets, err := client.GetScreenshotSets(ctx, primaryLoc.ID)
+	if err != nil {
+		return nil
+	}
+	missing := make(map[string]bool)
+	for _, set := range screenshotSets {
+		if class := screenshotClass(set.Attributes.ScreenshotDisplayType); class != "" && !previewClasses[class] {
+			missing[class] = true
+		}
+	}
+	var have []string
+	for class := range previewClasses {
+		have = append(have, class)
+	}
+	sort.Strings(have)
+	var classes []string
+	for class := range missing {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		*findings = append(*findings, Finding{
+			Tier:      TierMetadata,
+			Severity:  SeverityInfo,
+			Guideline: "2.3",
+			Title:     fmt.Sprintf("No app previews for %s", class),
+			Detail:    fmt.Sprintf("The listing has %s screenshots and %s previews, but no %s previews, so %s customers see a static listing.", class, strings.Join(have, "/"), class, class),
+			Fix:       fmt.Sprintf("Upload an app preview for the largest %s display size.", class),
+		})
+	}
+
+	return nil
+}
+
+func previewSizeAccepted(sizes [][2]int, w, h int) bool {
+	for _, s := range sizes {
+		if (w == s[0] && h == s[1]) || (w == s[1] && h == s[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPreviewSizes(sizes [][2]int) string {
+	var parts []string
+	for _, s := range sizes {
+		parts = append(parts, fmt.Sprintf("%dx%d or %dx%d", s[0], s[1], s[1], s[0]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,113 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+)
+
+// euTerritories are the EU and EEA storefronts GDPR applies to.
+var euTerritories = map[string]bool{
+	"AUT": true, "BEL": true, "BGR": true, "HRV": true, "CYP": true, "CZE": true, "DNK": true,
+	"EST": true, "FIN": true, "FRA": true, "DEU": true, "GRC": true, "HUN": true, "IRL": true,
+	"ITA": true, "LVA": true, "LTU": true, "LUX": true, "MLT": true, "NLD": true, "POL": true,
+	"PRT": true, "ROU": true, "SVK": true, "SVN": true, "ESP": true, "SWE": true,
+	"ISL": true, "LIE": true, "NOR": true,
+}
+
+// checkRegionalRegulations runs the rule packs for the territories the app
+// is available in: the ICP filing in China mainland, purchase disclosures
+// in South Korea, the age rating in Brazil and GDPR consent in the EU. With
+// a project, the packs only report what its code doesn't already cover.
+func (r *Runner) checkRegionalRegulations(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	territories, err := client.GetAppAvailability(ctx, appID)
+	if err != nil || len(territories) == 0 {
+		return nil // non-fatal; checkTerritoryAvailability reports it
+	}
+	available := make(map[string]bool, len(territories))
+	var eu []string
+	for _, t := range territories {
+		available[t.ID] = true
+		if euTerritories[t.ID] {
+			eu = append(eu, t.ID)
+		}
+	}
+
+	var project *codescan.RegionalSignals
+	if r.projectPath != "" {
+		s := codescan.ReadRegionalSignals(r.projectPath, r.paths)
+		project = &s
+	}
+
+	// The ICP filing number is entered in App Store Connect, and the API
+	// doesn't return it, so this is a reminder rather than a finding.
+	if available["CHN"] && (project == nil || project.Networked) {
+		detail := "Apps that connect to the internet need an ICP (Internet Content Provider) filing number from the Ministry of Industry and Information Technology to be distributed in China mainland. The number is entered in App Store Connect → App Information, which the API doesn't expose, so greenlight can't tell whether it's there."
+		if project != nil {
+			detail = "The app makes network requests and is available in China mainland. " + detail
+		}
+		*findings = append(*findings, Finding{
+			Tier:      TierMetadata,
+			Severity:  SeverityInfo,
+			Guideline: "5.0",
+			Title:     "[China mainland] Confirm the ICP filing number",
+			Detail:    detail,
+			Fix:       "Check that the ICP filing number is entered in App Store Connect → App Information. If the app has none, obtain one through a Chinese entity or hosting provider, or remove China mainland from availability.",
+		})
+	}
+
+	if available["KOR"] && (project == nil || project.Purchases && !project.KoreaDisclosure) {
+		sev, detail := SeverityInfo, "If the app sells in-app purchases, Korean e-commerce law requires the seller's business information and the terms for withdrawing a purchase (청약철회) and refunds to be shown before purchase."
+		if project != nil {
+			sev = SeverityWarn
+			detail = "The app sells in-app purchases on the Korean storefront, but no Korean withdrawal (청약철회), refund or seller business information was found. Korean e-commerce law requires them to be shown before purchase."
+		}
+		*findings = append(*findings, Finding{
+			Tier:      TierMetadata,
+			Severity:  sev,
+			Guideline: "5.0",
+			Title:     "[South Korea] Purchase disclosures under Korean e-commerce law",
+			Detail:    detail,
+			Fix:       "Show the withdrawal and refund terms, in Korean, next to the purchase button, and the seller's business name, registration and mail-order business numbers in the app or its support page. Games with paid random items must also show their odds.",
+		})
+	}
+
+	if available["BRA"] {
+		if infos, err := client.GetAppInfos(ctx, appID); err == nil && len(infos) > 0 && infos[0].Attributes.BrazilAgeRating == "" {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityWarn,
+				Guideline: "2.3.6",
+				Title:     "[Brazil] No Brazilian age rating",
+				Detail:    "The app is available in Brazil but has no Brazilian age rating (ClassInd). Brazil requires one for every app on the storefront; it is derived from the age rating questionnaire.",
+				Fix:       "Complete or update the age rating questionnaire in App Store Connect → App Information and check the rating shown for Brazil.",
+			})
+		}
+	}
+
+	if len(eu) > 0 && (project == nil || project.Tracking != "" && !project.Consent) {
+		storefronts := fmt.Sprintf("%d EU/EEA storefronts", len(eu))
+		if len(eu) == 1 {
+			storefronts = "1 EU/EEA storefront"
+		}
+		sev := SeverityInfo
+		detail := fmt.Sprintf("The app is available in %s. If it uses analytics or advertising SDKs, GDPR and the ePrivacy Directive require consent before they collect personal data.", storefronts)
+		if project != nil {
+			sev = SeverityWarn
+			detail = fmt.Sprintf("The app uses %s and is available in %s (%s), but no consent management flow was found. GDPR and the ePrivacy Directive require consent before analytics or advertising SDKs collect personal data.", project.Tracking, storefronts, strings.Join(eu, ", "))
+		}
+		*findings = append(*findings, Finding{
+			Tier:      TierMetadata,
+			Severity:  sev,
+			Guideline: "5.1.1",
+			Title:     "[EU] GDPR consent for analytics and advertising",
+			Detail:    detail,
+			Fix:       "Ask for consent with an IAB TCF-registered consent platform (Google UMP, OneTrust, Didomi, Usercentrics) before starting analytics or ad SDKs, and let users change their choice in settings.",
+		})
+	}
+
+	return nil
+}
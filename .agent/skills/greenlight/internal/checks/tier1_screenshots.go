@@ -0,0 +1,360 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// Accepted screenshot sizes (portrait; landscape is accepted too) for each
+// display type. The 6.7" slot also takes 6.9" screenshots, and the 12.9"
+// iPad slot 13" ones.
+var requiredScreenshotDimensions = map[string]struct {
+	name  string
+	sizes [][2]int
+}{
+	"APP_IPHONE_67":         {"iPhone 6.9\"", [][2]int{{1320, 2868}, {1290, 2796}}},
+	"APP_IPHONE_65":         {"iPhone 6.5\"", [][2]int{{1284, 2778}, {1242, 2688}}},
+	"APP_IPHONE_55":         {"iPhone 5.5\"", [][2]int{{1242, 2208}}},
+	"APP_IPAD_PRO_3GEN_129": {"iPad 13\"", [][2]int{{2064, 2752}, {2048, 2732}}},
+	"APP_IPAD_PRO_129":      {"iPad Pro 12.9\" (2nd gen)", [][2]int{{2048, 2732}}},
+}
+
+// Screenshots App Store Connect requires before submission, by device
+// family: any one of types satisfies a requirement. Smaller sizes are
+// scaled down from these, so 5.5" screenshots are no longer required.
+var requiredScreenshotTypes = []struct {
+	family string
+	name   string
+	types  []string
+}{
+	{"iPhone", "iPhone 6.9\" or 6.5\" (iPhone 16 Pro Max, 11 Pro Max)", []string{"APP_IPHONE_67", "APP_IPHONE_65"}},
+	{"iPad", "iPad 13\" (iPad Pro)", []string{"APP_IPAD_PRO_3GEN_129", "APP_IPAD_PRO_129"}},
+}
+
+// deviceFamilies is which devices the app runs on, and how that was
+// learned.
+type deviceFamilies struct {
+	iPhone, iPad bool
+	source       string
+}
+
+func (d deviceFamilies) supports(family string) bool {
+	return (family == "iPhone" && d.iPhone) || (family == "iPad" && d.iPad)
+}
+
+// deviceFamilies reads the device families from the local project when one
+// was given. Otherwise every app is taken to run on iPhone, and on iPad
+// when iPad screenshots were uploaded for any localization.
+func (r *Runner) deviceFamilies(locales []localeScreenshots) deviceFamilies {
+	if r.projectPath != "" {
+		if d, ok := projectDeviceFamilies(r.projectPath); ok {
+			return d
+		}
+	}
+	d := deviceFamilies{iPhone: true, source: "assumed without --project"}
+	for _, l := range locales {
+		for _, set := range l.sets {
+			if strings.HasPrefix(set.Attributes.ScreenshotDisplayType, "APP_IPAD") {
+				d.iPad = true
+			}
+		}
+	}
+	return d
+}
+
+// projectDeviceFamilies reads TARGETED_DEVICE_FAMILY from the Xcode
+// project, or ios.supportsTablet and ios.isTabletOnly from an Expo
+// app.json.
+func projectDeviceFamilies(root string) (deviceFamilies, bool) {
+	d := deviceFamilies{source: "TARGETED_DEVICE_FAMILY in the Xcode project"}
+	found := false
+	for _, path := range xcodeproj.Find(root) {
+		p, err := xcodeproj.Load(path)
+		if err != nil {
+			continue
+		}
+		if iPhone, iPad, ok := p.DeviceFamilies(); ok {
+			d.iPhone, d.iPad, found = d.iPhone || iPhone, d.iPad || iPad, true
+		}
+	}
+	if found {
+		return d, true
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "app.json"))
+	if err != nil {
+		return deviceFamilies{}, false
+	}
+	var appJSON struct {
+		Expo struct {
+			IOS *struct {
+				SupportsTablet bool `json:"supportsTablet"`
+				IsTabletOnly   bool `json:"isTabletOnly"`
+			} `json:"ios"`
+		} `json:"expo"`
+	}
+	if json.Unmarshal(data, &appJSON) != nil || appJSON.Expo.IOS == nil {
+		return deviceFamilies{}, false
+	}
+	ios := appJSON.Expo.IOS
+	return deviceFamilies{
+		iPhone: !ios.IsTabletOnly,
+		iPad:   ios.SupportsTablet || ios.IsTabletOnly,
+		source: "ios settings in app.json",
+	}, true
+}
+
+// screenshotLocaleWorkers is how many localizations' screenshots are
+// fetched at once.
+const screenshotLocaleWorkers = 4
+
+// localeScreenshots is one version localization's screenshot sets.
+type localeScreenshots struct {
+	locale  string
+	primary bool
+	sets    []localeScreenshotSet
+}
+
+type localeScreenshotSet struct {
+	asc.ScreenshotSet
+	screenshots []asc.Screenshot
+}
+
+// label prefixes a finding title with the locale, except for the primary
+// localization, whose findings read as they would for a single-language
+// listing.
+func (l localeScreenshots) label(title string) string {
+	if l.primary {
+		return title
+	}
+	return fmt.Sprintf("[%s] %s", l.locale, title)
+}
+
+// loadLocaleScreenshots fetches the screenshot sets and screenshots of every
+// localization of the latest version, primary localization first. It
+// returns nil when there is no version or localization to check.
+func loadLocaleScreenshots(ctx context.Context, client *asc.Client, appID string) ([]localeScreenshots, error) {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil || len(localizations) == 0 {
+		return nil, err
+	}
+
+	primary := 0
+	if app, err := client.GetApp(ctx, appID); err == nil {
+		for i, loc := range localizations {
+			if loc.Attributes.Locale == app.Attributes.PrimaryLocale {
+				primary = i
+				break
+			}
+		}
+	}
+	localizations[0], localizations[primary] = localizations[primary], localizations[0]
+
+	out := make([]localeScreenshots, len(localizations))
+	errs := make([]error, len(localizations))
+	sem := make(chan struct{}, screenshotLocaleWorkers)
+	var wg sync.WaitGroup
+	for i, loc := range localizations {
+		out[i] = localeScreenshots{locale: loc.Attributes.Locale, primary: i == 0}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			sets, err := client.GetScreenshotSets(ctx, loc.ID)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, set := range sets {
+				// A set whose screenshots can't be listed still counts
+				// as present.
+				screenshots, _ := client.GetScreenshots(ctx, set.ID)
+				out[i].sets = append(out[i].sets, localeScreenshotSet{ScreenshotSet: set, screenshots: screenshots})
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Without the primary localization's sets nothing can be judged; other
+	// localizations that failed are left out.
+	if errs[0] != nil {
+		return nil, errs[0]
+	}
+	kept := out[:0]
+	for i, l := range out {
+		if errs[i] == nil {
+			kept = append(kept, l)
+		}
+	}
+	return kept, nil
+}
+
+// checkScreenshots verifies screenshot sets exist for the display types the
+// app's device families require in every localization, and flags
+// localizations whose screenshots are the same files as another
+// localization's.
+func (r *Runner) checkScreenshots(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	locales, err := loadLocaleScreenshots(ctx, client, appID)
+	if err != nil || len(locales) == 0 {
+		return err
+	}
+	primary := locales[0]
+
+	if len(primary.sets) == 0 {
+		*findings = append(*findings, Finding{
+			Tier:      TierMetadata,
+			Severity:  SeverityCritical,
+			Guideline: "2.3",
+			Title:     "No screenshots uploaded",
+			Detail:    "At least one set of screenshots is required for submission.",
+			Fix:       "Upload screenshots for at least the iPhone 6.9\" display size, and iPad 13\" if the app runs on iPad.",
+		})
+		return nil
+	}
+	families := r.deviceFamilies(locales)
+
+	for _, l := range locales {
+		if !l.primary && len(l.sets) == 0 {
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityInfo,
+				Guideline: "2.3",
+				Title:     l.label("No localized screenshots"),
+				Detail:    fmt.Sprintf("This localization has no screenshots of its own, so its storefronts show the %s screenshots, in that language.", primary.locale),
+				Fix:       fmt.Sprintf("Upload screenshots of the app running in %s, or remove the localization if the app isn't translated.", l.locale),
+			})
+			continue
+		}
+
+		foundTypes := make(map[string]bool)
+		for _, set := range l.sets {
+			foundTypes[set.Attributes.ScreenshotDisplayType] = true
+		}
+	nextType:
+		for _, required := range requiredScreenshotTypes {
+			if !families.supports(required.family) {
+				continue
+			}
+			for _, t := range required.types {
+				if foundTypes[t] {
+					continue nextType
+				}
+			}
+			f := Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityWarn,
+				Guideline: "2.3",
+				Title:     l.label(fmt.Sprintf("Missing screenshots for %s", required.name)),
+				Detail:    fmt.Sprintf("The app runs on %s (from %s), and App Store Connect won't accept a submission without screenshots for this size.", required.family, families.source),
+				Fix:       fmt.Sprintf("Upload screenshots for the %s display type.", required.name),
+			}
+			if !l.primary {
+				f.Severity = SeverityInfo
+				f.Detail = fmt.Sprintf("This localization has screenshots, but none for this size; %s screenshots are shown instead if it has them.", primary.locale)
+			}
+			*findings = append(*findings, f)
+		}
+	}
+
+	// A localization whose screenshots are all files already uploaded for
+	// another one claims to be localized but shows the same images.
+	byLocale := make(map[string]map[string]bool)
+	for _, l := range locales {
+		sums := make(map[string]bool)
+		for _, set := range l.sets {
+			for _, ss := range set.screenshots {
+				if sum := ss.Attributes.SourceFileChecksum; sum != "" {
+					sums[sum] = true
+				}
+			}
+		}
+		byLocale[l.locale] = sums
+	}
+	for i, l := range locales {
+		sums := byLocale[l.locale]
+		if l.primary || len(sums) == 0 {
+			continue
+		}
+		for _, other := range locales[:i] {
+			if !subset(sums, byLocale[other.locale]) {
+				continue
+			}
+			*findings = append(*findings, Finding{
+				Tier:      TierMetadata,
+				Severity:  SeverityInfo,
+				Guideline: "2.3",
+				Title:     l.label(fmt.Sprintf("Screenshots identical to %s", other.locale)),
+				Detail:    fmt.Sprintf("Every screenshot uploaded for %s is a file already uploaded for %s, so the localized listing shows untranslated screens.", l.locale, other.locale),
+				Fix:       fmt.Sprintf("Capture screenshots with the app running in %s, or delete this localization's screenshot sets so the %s ones are shown as a fallback.", l.locale, primary.locale),
+			})
+			break
+		}
+	}
+
+	return nil
+}
+
+// subset reports whether every key of a is in b.
+func subset(a, b map[string]bool) bool {
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkScreenshotDimensions validates that every localization's uploaded
+// screenshots have correct dimensions.
+func checkScreenshotDimensions(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	locales, err := loadLocaleScreenshots(ctx, client, appID)
+	if err != nil || len(locales) == 0 {
+		return nil // other checks handle missing screenshots
+	}
+
+	for _, l := range locales {
+		for _, set := range l.sets {
+			expectedDims, ok := requiredScreenshotDimensions[set.Attributes.ScreenshotDisplayType]
+			if !ok {
+				continue
+			}
+
+			for _, ss := range set.screenshots {
+				if ss.Attributes.ImageAsset == nil {
+					continue
+				}
+				w := ss.Attributes.ImageAsset.Width
+				h := ss.Attributes.ImageAsset.Height
+
+				if !previewSizeAccepted(expectedDims.sizes, w, h) {
+					*findings = append(*findings, Finding{
+						Tier:      TierMetadata,
+						Severity:  SeverityCritical,
+						Guideline: "2.3",
+						Title:     l.label(fmt.Sprintf("Screenshot wrong dimensions for %s: %dx%d", expectedDims.name, w, h)),
+						Detail:    fmt.Sprintf("Expected %s for %s.", formatPreviewSizes(expectedDims.sizes), expectedDims.name),
+						Fix:       fmt.Sprintf("Re-capture screenshots at the correct resolution for %s.", expectedDims.name),
+					})
+					break // one finding per set is enough
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/itunes"
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+const (
+	// maxCompetitorSearches bounds the category searches per scan.
+	maxCompetitorSearches = 4
+	// minCompetitorRatings is how many ratings make a search result a top
+	// competitor whose name is checked against the metadata.
+	minCompetitorRatings = 1000
+	// minStackedTerms is how many popular category terms make a name or
+	// subtitle read as keyword stuffing.
+	minStackedTerms = 4
+)
+
+// genericNameWords describe what an app does rather than who makes it, so a
+// competitor brand made only of them ("Habit Tracker") isn't a trademark.
+var genericNameWords = map[string]bool{
+	"app": true, "apps": true, "pro": true, "plus": true, "lite": true, "free": true, "hd": true, "my": true, "the": true, "and": true,
+	"to": true, "do": true, "todo": true, "list": true, "lists": true, "notes": true, "note": true, "calendar": true, "planner": true,
+	"tracker": true, "timer": true, "habit": true, "habits": true, "budget": true, "money": true, "fitness": true, "workout": true,
+	"weather": true, "radar": true, "photo": true, "photos": true, "video": true, "editor": true, "camera": true, "scanner": true,
+	"pdf": true, "music": true, "radio": true, "podcast": true, "podcasts": true, "maps": true, "translator": true, "vpn": true,
+	"widget": true, "widgets": true, "chat": true, "news": true, "games": true, "game": true, "puzzle": true, "sleep": true,
+	"meditation": true, "diet": true, "recipes": true, "scan": true, "reader": true, "browser": true, "wallet": true,
+}
+
+// stopWords don't count towards stacked category terms.
+var stopWords = map[string]bool{"and": true, "the": true, "for": true, "with": true, "your": true, "you": true, "app": true, "pro": true}
+
+// listSeparatorRe matches the separators of a name written as a list of
+// search terms: "Planner, Calendar, To Do | Notes".
+var listSeparatorRe = regexp.MustCompile(`[,|/•;]`)
+
+// checkCompetitorMetadata searches the iTunes Search API in the app's
+// category for its top competitors, and flags their names in the app's
+// name, subtitle and keywords and names built from the category's popular
+// search terms (2.3.7). It only runs when competitor search is enabled, as
+// it sends the app's keywords to Apple's public search API.
+func (r *Runner) checkCompetitorMetadata(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	if !r.competitorSearch {
+		return nil
+	}
+	app, err := client.GetApp(ctx, appID)
+	if err != nil {
+		return err
+	}
+	infos, err := client.GetAppInfos(ctx, appID)
+	if err != nil || len(infos) == 0 {
+		return err
+	}
+	category, err := client.GetAppInfoPrimaryCategory(ctx, infos[0].ID)
+	if err != nil {
+		return err
+	}
+	if category == nil || itunes.GenreID(category.ID) == 0 {
+		return nil // no category to search
+	}
+
+	locale := app.Attributes.PrimaryLocale
+	name, subtitle, keywords := app.Attributes.Name, "", ""
+	if locs, err := client.GetAppInfoLocalizations(ctx, infos[0].ID); err == nil {
+		for _, l := range locs {
+			if l.Attributes.Locale == locale {
+				if l.Attributes.Name != "" {
+					name = l.Attributes.Name
+				}
+				subtitle = l.Attributes.Subtitle
+			}
+		}
+	}
+	if versions, err := client.GetAppStoreVersions(ctx, appID); err == nil && len(versions) > 0 {
+		if locs, err := client.GetVersionLocalizations(ctx, versions[0].ID); err == nil {
+			for _, l := range locs {
+				if l.Attributes.Locale == locale {
+					keywords = l.Attributes.Keywords
+				}
+			}
+		}
+	}
+
+	// Search the category for what the app describes itself as, then its
+	// first keywords.
+	ownBrand := brandOf(name)
+	var terms []string
+	seenTerm := map[string]bool{}
+	addTerm := func(t string) {
+		t = strings.TrimSpace(strings.Trim(strings.TrimSpace(t), "-–—|:·"))
+		if t != "" && !seenTerm[strings.ToLower(t)] && len(terms) < maxCompetitorSearches {
+			seenTerm[strings.ToLower(t)] = true
+			terms = append(terms, t)
+		}
+	}
+	addTerm(strings.TrimPrefix(name, ownBrand))
+	addTerm(subtitle)
+	for _, k := range strings.Split(keywords, ",") {
+		addTerm(k)
+	}
+	if len(terms) == 0 {
+		terms = []string{name}
+	}
+
+	country := itunes.Country(locale)
+	if country == "" {
+		country = "us"
+	}
+	search := itunes.NewClient(&http.Client{Timeout: 15 * time.Second})
+	var competitors []itunes.App
+	seenApp := map[int64]bool{}
+	for _, term := range terms {
+		results, err := search.Search(ctx, itunes.Query{Term: term, Country: country, GenreID: itunes.GenreID(category.ID), Limit: 50})
+		if err != nil {
+			return err
+		}
+		for _, a := range results {
+			if a.BundleID == app.Attributes.BundleID || seenApp[a.TrackID] {
+				continue
+			}
+			seenApp[a.TrackID] = true
+			competitors = append(competitors, a)
+		}
+	}
+	if len(competitors) == 0 {
+		return nil
+	}
+	sort.SliceStable(competitors, func(i, j int) bool {
+		return competitors[i].UserRatingCount > competitors[j].UserRatingCount
+	})
+	genre := competitors[0].PrimaryGenre
+
+	// Words in several competitors' names describe the category.
+	popular := map[string]int{}
+	for _, c := range competitors {
+		for w := range wordSet(c.Name) {
+			popular[w]++
+		}
+	}
+
+	known := append(append([]string{}, defaultBrandTerms...), r.brandTerms...)
+	var brands []itunes.App
+	seenBrand := map[string]bool{}
+	for _, c := range competitors {
+		b := brandOf(c.Name)
+		key := strings.ToLower(b)
+		if c.UserRatingCount < minCompetitorRatings || len([]rune(b)) < 3 || strings.EqualFold(b, ownBrand) || seenBrand[key] {
+			continue
+		}
+		seenBrand[key] = true
+		distinctive := false
+		for w := range wordSet(b) {
+			if !genericNameWords[w] && popular[w] < 3 {
+				distinctive = true
+			}
+		}
+		for _, k := range known {
+			if strings.EqualFold(k, b) {
+				distinctive = false // the trademark check reports it
+			}
+		}
+		if distinctive {
+			brands = append(brands, c)
+		}
+	}
+
+	fields := []struct{ name, value string }{{"app name", name}, {"subtitle", subtitle}, {"keywords", keywords}}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		for _, c := range brands {
+			b := brandOf(c.Name)
+			if !containsTerm(f.value, b) {
+				continue
+			}
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityCritical,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] Competitor name %q in %s", locale, b, f.name),
+				Detail:    fmt.Sprintf("%q is %s by %s, a top %s app with %d ratings (%s). Names, subtitles and keywords may not include other apps' names or trademarks.", b, c.Name, c.Seller, genre, c.UserRatingCount, c.URL),
+				Fix:       fmt.Sprintf("Remove %q from the %s.", b, f.name),
+			})
+		}
+	}
+
+	for _, f := range fields[:2] {
+		if f.value == "" {
+			continue
+		}
+		if n := len(listSeparatorRe.FindAllString(f.value, -1)); n >= 2 {
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] %s reads as a list of search terms", locale, capitalize(f.name)),
+				Detail:    fmt.Sprintf("%q — names and subtitles made of terms separated by commas, slashes or bars are rejected as keyword stuffing.", f.value),
+				Fix:       fmt.Sprintf("Rewrite the %s as a short phrase and move the search terms to the keywords field.", f.name),
+			})
+		}
+		brandWords := wordSet(ownBrand)
+		var stacked []string
+		for w := range wordSet(f.value) {
+			if popular[w] >= 3 && len(w) >= 3 && !stopWords[w] && !brandWords[w] {
+				stacked = append(stacked, w)
+			}
+		}
+		if len(stacked) >= minStackedTerms {
+			sort.Strings(stacked)
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] %s stacks popular %s terms: %s", locale, capitalize(f.name), genre, strings.Join(stacked, ", ")),
+				Detail:    fmt.Sprintf("%q — each of these words appears in the names of several top %s apps. Names and subtitles strung together from popular search terms are rejected as keyword stuffing.", f.value, genre),
+				Fix:       "Keep one or two terms that describe the app and move the rest to the keywords field.",
+			})
+		}
+	}
+
+	return nil
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
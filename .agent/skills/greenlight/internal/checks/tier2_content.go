@@ -3,9 +3,13 @@ package checks
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
 
-	"github.com/RevylAI/greenlight/internal/asc"
+	"github.com/RevylAI/greenlight/internal/spellcheck"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/codescan"
 )
 
 // Patterns that reference competing platforms — a common rejection trigger.
@@ -42,12 +46,12 @@ var placeholderPatterns = []string{
 
 // checkPlatformReferences scans metadata for references to competing platforms.
 func checkPlatformReferences(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
+	versions, err := client.GetAppStoreVersions(ctx, appID)
 	if err != nil || len(versions) == 0 {
 		return err
 	}
 
-	localizations, err := client.GetVersionLocalizations(versions[0].ID)
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
 	if err != nil {
 		return err
 	}
@@ -67,7 +71,7 @@ func checkPlatformReferences(ctx context.Context, client *asc.Client, appID stri
 				if strings.Contains(lower, pp.pattern) {
 					*findings = append(*findings, Finding{
 						Tier:      TierContent,
-						Severity:  SeverityBlock,
+						Severity:  SeverityCritical,
 						Guideline: "2.3",
 						Title:     fmt.Sprintf("[%s] %s mentions %s in %s", locale, pp.name, pp.name, fieldName),
 						Detail:    "Referencing competing platforms in App Store metadata is a common rejection reason.",
@@ -83,12 +87,12 @@ func checkPlatformReferences(ctx context.Context, client *asc.Client, appID stri
 
 // checkPlaceholderContent scans metadata for placeholder text.
 func checkPlaceholderContent(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
-	versions, err := client.GetAppStoreVersions(appID)
+	versions, err := client.GetAppStoreVersions(ctx, appID)
 	if err != nil || len(versions) == 0 {
 		return err
 	}
 
-	localizations, err := client.GetVersionLocalizations(versions[0].ID)
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
 	if err != nil {
 		return err
 	}
@@ -108,7 +112,7 @@ func checkPlaceholderContent(ctx context.Context, client *asc.Client, appID stri
 				if strings.Contains(lower, pattern) {
 					*findings = append(*findings, Finding{
 						Tier:      TierContent,
-						Severity:  SeverityBlock,
+						Severity:  SeverityCritical,
 						Guideline: "2.1",
 						Title:     fmt.Sprintf("[%s] Placeholder content detected in %s", locale, fieldName),
 						Detail:    fmt.Sprintf("Found '%s' — Apple rejects apps with placeholder or incomplete content.", pattern),
@@ -121,3 +125,745 @@ func checkPlaceholderContent(ctx context.Context, client *asc.Client, appID stri
 
 	return nil
 }
+
+// futureFeatureRe matches promises of functionality that isn't in this build.
+var futureFeatureRe = regexp.MustCompile(`(?i)\b(coming (soon|later|in (a|an|the) (future|next|upcoming) (update|release|version))|in (a|an|the) (future|next|upcoming) (update|release|version)|will be (added|available|enabled|supported) (soon|later|in)|not (yet )?(available|implemented|supported) yet|planned for (a )?(future|later) (update|release))\b`)
+
+// checkReviewNotes runs the platform-reference and placeholder checks over
+// the App Review notes and attachment names, and flags notes that promise
+// features for a later update.
+func checkReviewNotes(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	detail, err := client.GetReviewDetail(ctx, versions[0].ID)
+	if err != nil || detail == nil {
+		return err
+	}
+	attachments, err := client.GetReviewAttachments(ctx, detail.ID)
+	if err != nil {
+		return err
+	}
+
+	fields := []struct{ name, value string }{
+		{"App Review notes", detail.Attributes.Notes},
+	}
+	for _, a := range attachments {
+		fields = append(fields, struct{ name, value string }{
+			fmt.Sprintf("review attachment %q", a.Attributes.FileName), a.Attributes.FileName,
+		})
+	}
+
+	for _, field := range fields {
+		lower := strings.ToLower(field.value)
+		for _, pp := range platformPatterns {
+			if strings.Contains(lower, pp.pattern) {
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "2.3",
+					Title:     fmt.Sprintf("%s mentioned in %s", pp.name, field.name),
+					Detail:    "Reviewers read the notes and attachments alongside the metadata; references to competing platforms there invite the same rejection.",
+					Fix:       fmt.Sprintf("Remove the reference to %s from the %s.", pp.name, field.name),
+				})
+			}
+		}
+		for _, pattern := range placeholderPatterns {
+			// Promised features are reported below.
+			if pattern == "coming soon" {
+				continue
+			}
+			if strings.Contains(lower, pattern) {
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "2.1",
+					Title:     fmt.Sprintf("Placeholder content detected in %s", field.name),
+					Detail:    fmt.Sprintf("Found '%s' — notes that look unfinished suggest the app is too.", pattern),
+					Fix:       fmt.Sprintf("Replace placeholder text in the %s with final instructions for the reviewer.", field.name),
+				})
+			}
+		}
+	}
+
+	if m := futureFeatureRe.FindString(detail.Attributes.Notes); m != "" {
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "2.3",
+			Title:     "App Review notes promise features in a future update",
+			Detail:    fmt.Sprintf("Found %q. Apps are reviewed as submitted; pointing reviewers at features that aren't in this build is a common cause of metadata and completeness rejections.", m),
+			Fix:       "Describe only what this build does. Leave planned features out of the notes and metadata until they ship.",
+		})
+	}
+
+	for _, a := range attachments {
+		if st := a.Attributes.AssetDeliveryState; st != nil && st.State == "FAILED" {
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("Review attachment %q failed to upload", a.Attributes.FileName),
+				Detail:    "The reviewer won't be able to open this attachment.",
+				Fix:       "Delete the attachment in App Store Connect → App Review Information and upload it again.",
+			})
+		}
+	}
+
+	return nil
+}
+
+
+var (
+	subscriptionMentionRe = regexp.MustCompile(`(?i)\b(subscription|subscribe|auto[- ]renew\w*|premium membership|pro plan)\b`)
+	freeTrialRe           = regexp.MustCompile(`(?i)\bfree[- ]trial\b`)
+	trialDurationRe       = regexp.MustCompile(`(?i)(\d+|one|two|three|seven|fourteen|thirty)[- ]?(day|week|month|year)`)
+	termsOfUseRe          = regexp.MustCompile(`(?i)(terms of (use|service)|\bEULA\b|apple\.com/legal/internet-services/itunes/dev/stdeula)`)
+	privacyPolicyRe       = regexp.MustCompile(`(?i)privacy policy`)
+	priceMentionRe        = regexp.MustCompile(`(?i)([$€£¥]\s?\d|\d+[.,]\d{2}\s?(usd|eur|gbp)|per (week|month|year)|/(wk|week|mo|month|yr|year)\b|\b(weekly|monthly|annual|yearly)\b)`)
+)
+
+// checkSubscriptionDisclosures applies guideline 3.1.2 to subscription marketing copy:
+// Terms of Use and privacy policy links, billing terms, and free-trial length.
+// The patterns are English, so other locales are skipped rather than
+// reported for missing English phrases.
+func checkSubscriptionDisclosures(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil {
+		return err
+	}
+
+	for _, loc := range localizations {
+		locale := loc.Attributes.Locale
+		if lang, _, _ := strings.Cut(strings.ToLower(locale), "-"); lang != "en" {
+			continue
+		}
+		fields := []struct{ name, value string }{
+			{"description", loc.Attributes.Description},
+			{"what's new", loc.Attributes.WhatsNew},
+			{"promotional text", loc.Attributes.PromotionalText},
+		}
+
+		for _, field := range fields {
+			if freeTrialRe.MatchString(field.value) && !trialDurationRe.MatchString(field.value) {
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "3.1.2",
+					Title:     fmt.Sprintf("[%s] Free trial mentioned in %s without trial length", locale, field.name),
+					Detail:    "Free trial claims must state the trial duration and what happens when it ends.",
+					Fix:       fmt.Sprintf("Add the trial length and renewal price to the %s (e.g. \"7-day free trial, then $4.99/month\").", field.name),
+				})
+			}
+		}
+
+		desc := loc.Attributes.Description
+		if !subscriptionMentionRe.MatchString(desc) {
+			continue
+		}
+
+		if !termsOfUseRe.MatchString(desc) {
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "3.1.2",
+				Title:     fmt.Sprintf("[%s] Subscription described without Terms of Use link", locale),
+				Detail:    "Apps offering auto-renewable subscriptions must include a functional link to the Terms of Use (EULA) in the App Store description or the EULA field.",
+				Fix:       "Add a Terms of Use link to the description, or set a custom EULA in App Store Connect → App Information.",
+			})
+		}
+		if !privacyPolicyRe.MatchString(desc) {
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityInfo,
+				Guideline: "3.1.2",
+				Title:     fmt.Sprintf("[%s] Subscription described without privacy policy mention", locale),
+				Detail:    "Reviewers look for a privacy policy link alongside subscription terms. The privacy policy URL field alone is usually sufficient, but linking it in the description avoids follow-up questions.",
+				Fix:       "Mention and link your privacy policy near the subscription terms in the description.",
+			})
+		}
+		if !priceMentionRe.MatchString(desc) {
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityInfo,
+				Guideline: "3.1.2",
+				Title:     fmt.Sprintf("[%s] Subscription described without price or billing period", locale),
+				Detail:    "The description mentions a subscription but not its price or renewal period. Reviewers compare this against the in-app paywall.",
+				Fix:       "State the subscription length and price (e.g. \"$4.99/month, renews automatically\").",
+			})
+		}
+	}
+
+	return nil
+}
+
+// Gambling-related terms grouped by how Apple treats them.
+var (
+	realMoneyGamblingRe = regexp.MustCompile(`(?i)\b(real[- ]money|cash prizes?|sports ?betting|place (a )?bets?|wager(ing)?|sportsbook|win real cash)\b`)
+	simulatedGamblingRe = regexp.MustCompile(`(?i)\b(slot machines?|(casino|slots?|poker|bingo) games?|social casino|casino slots|free slots|video poker|texas hold'?em|roulette|blackjack|baccarat|play (slots|poker|bingo))\b`)
+	// Words that usually mean gambling but have other uses ("appointment
+	// slots", "the casino district"), reported with less confidence.
+	gamblingWordRe = regexp.MustCompile(`(?i)\b(slots|casino|poker|jackpot|bingo)\b`)
+	lootBoxRe      = regexp.MustCompile(`(?i)\b(loot ?box(es)?|gacha|mystery box(es)?|card packs?|lucky draw|prize wheel|spin the wheel)\b`)
+	// Contests and giveaways count only when a prize is at stake.
+	sweepstakesRe     = regexp.MustCompile(`(?i)\b(sweepstakes?|raffles?|prize draws?)\b|\b(contests?|giveaways?)\b[^.\n]{0,40}\b(prizes?|win(ners?)?)\b|\b(prizes?|win)\b[^.\n]{0,40}\b(contests?|giveaways?)\b`)
+	appleNotSponsorRe = regexp.MustCompile(`(?i)apple (is|inc\.? is) not (a )?(sponsor|involved)`)
+)
+
+// Territories that ban or strictly regulate paid loot boxes.
+var lootBoxRestrictedTerritories = map[string]string{
+	"BEL": "Belgium",
+	"NLD": "Netherlands",
+}
+
+// checkGamblingAgeRating compares gambling, loot-box, and sweepstakes language in
+// metadata against the declared age rating and territory availability.
+func checkGamblingAgeRating(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	if app, err := client.GetApp(ctx, appID); err == nil {
+		text.WriteString(app.Attributes.Name + "\n")
+	}
+	for _, loc := range localizations {
+		text.WriteString(loc.Attributes.Description + "\n")
+		text.WriteString(loc.Attributes.Keywords + "\n")
+		text.WriteString(loc.Attributes.PromotionalText + "\n")
+	}
+	content := text.String()
+
+	realMoney := realMoneyGamblingRe.FindString(content)
+	simulated := simulatedGamblingRe.FindString(content)
+	gamblingWord := ""
+	if simulated == "" {
+		gamblingWord = gamblingWordRe.FindString(content)
+	}
+	lootBox := lootBoxRe.FindString(content)
+	sweepstakes := sweepstakesRe.FindString(content)
+	if realMoney == "" && simulated == "" && gamblingWord == "" && lootBox == "" && sweepstakes == "" {
+		return nil
+	}
+
+	var rating, kidsBand string
+	if infos, err := client.GetAppInfos(ctx, appID); err == nil && len(infos) > 0 {
+		rating = infos[0].Attributes.AppStoreAgeRating
+		kidsBand = infos[0].Attributes.KidsAgeBand
+	}
+	adultRated := rating == "SEVENTEEN_PLUS" || rating == "EIGHTEEN_PLUS"
+
+	if kidsBand != "" && (realMoney != "" || simulated != "" || gamblingWord != "" || lootBox != "") {
+		sev, detail := SeverityCritical, "Gambling mechanics are not allowed in kids apps."
+		if realMoney == "" && simulated == "" && lootBox == "" {
+			sev, detail = SeverityWarn, "If the app has gambling mechanics, they are not allowed in kids apps; otherwise make sure the wording can't be read that way."
+		}
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  sev,
+			Guideline: "1.3",
+			Title:     "Gambling or loot-box content in a Kids Category app",
+			Detail:    fmt.Sprintf("Metadata mentions %q but the app is in the Kids Category (%s). %s", firstNonEmpty(realMoney, simulated, lootBox, gamblingWord), kidsBand, detail),
+			Fix:       "Remove the gambling/loot-box mechanics or leave the Kids Category.",
+		})
+	}
+
+	declared := rating
+	if declared == "" {
+		declared = "not declared"
+	}
+	switch {
+	case adultRated:
+	case realMoney != "":
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityCritical,
+			Guideline: "1.3",
+			Title:     fmt.Sprintf("Real-money gambling with age rating %s", declared),
+			Detail:    fmt.Sprintf("Metadata mentions %q. Apps with real-money gambling must be rated 17+.", realMoney),
+			Fix:       "Answer \"Gambling\" in the age rating questionnaire so the app is rated 17+.",
+		})
+	case simulated != "":
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "1.3",
+			Title:     fmt.Sprintf("Simulated gambling with age rating %s", declared),
+			Detail:    fmt.Sprintf("Metadata mentions %q. Simulated gambling must be declared in the age rating questionnaire: infrequent or mild simulated gambling rates the app at least 12+, frequent or intense simulated gambling 17+.", simulated),
+			Fix:       "Answer the Simulated Gambling question in the age rating questionnaire to match how often it appears in the app.",
+		})
+	case gamblingWord != "":
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "1.3",
+			Title:     fmt.Sprintf("Possible gambling content with age rating %s", declared),
+			Detail:    fmt.Sprintf("Metadata mentions %q, which App Review may read as simulated gambling. If the app has casino-style games, declare Simulated Gambling in the age rating questionnaire.", gamblingWord),
+			Fix:       "Declare simulated gambling in the age rating questionnaire if the app has it, or reword the metadata if it doesn't.",
+		})
+	}
+
+	if realMoney != "" {
+		detail := fmt.Sprintf("Metadata mentions %q. Real-money gaming apps must be licensed in every territory they're offered in, geo-restricted, and free on the App Store.", realMoney)
+		if territories, err := client.GetAppAvailability(ctx, appID); err == nil && len(territories) > 0 {
+			detail += fmt.Sprintf(" The app is currently available in %d territories.", len(territories))
+		}
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "5.3.3",
+			Title:     "Real-money gambling: verify licensing and territory restrictions",
+			Detail:    detail,
+			Fix:       "Limit territory availability to jurisdictions where you hold a gambling license, and include license details in App Review notes.",
+		})
+	}
+
+	if lootBox != "" {
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "3.1.1",
+			Title:     "Loot boxes: disclose odds before purchase",
+			Detail:    fmt.Sprintf("Metadata mentions %q. Apps offering loot boxes or randomized paid items must disclose the odds of receiving each item before purchase.", lootBox),
+			Fix:       "Show drop rates in-app before each purchase of a randomized item.",
+		})
+
+		if territories, err := client.GetAppAvailability(ctx, appID); err == nil {
+			for _, t := range territories {
+				if name, ok := lootBoxRestrictedTerritories[t.ID]; ok {
+					*findings = append(*findings, Finding{
+						Tier:      TierContent,
+						Severity:  SeverityWarn,
+						Guideline: "5.0",
+						Title:     fmt.Sprintf("Loot boxes offered in %s", name),
+						Detail:    fmt.Sprintf("%s restricts paid loot boxes as gambling. Apps have been removed from that storefront for non-compliance.", name),
+						Fix:       fmt.Sprintf("Disable paid randomized items for %s or remove the territory from availability.", name),
+					})
+				}
+			}
+		}
+	}
+
+	if sweepstakes != "" && !appleNotSponsorRe.MatchString(content) {
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "5.3.1",
+			Title:     "Sweepstakes or contest without Apple non-sponsorship disclaimer",
+			Detail:    fmt.Sprintf("Metadata mentions %q. Sweepstakes and contests must be sponsored by the developer, include official rules, and state that Apple is not a sponsor.", sweepstakes),
+			Fix:       "Add \"Apple is not a sponsor of, nor involved in, this contest\" and link the official rules.",
+		})
+	}
+
+	return nil
+}
+
+// checkMedicalDisclaimer looks for health, medication and emergency features
+// in each locale's metadata, requires the description to carry the matching
+// disclaimer, and flags medical claims that guideline 1.4 rejects.
+func checkMedicalDisclaimer(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil {
+		return err
+	}
+
+	// Name and subtitle per locale, from the app info.
+	names := map[string][2]string{}
+	if infos, err := client.GetAppInfos(ctx, appID); err == nil && len(infos) > 0 {
+		if locs, err := client.GetAppInfoLocalizations(ctx, infos[0].ID); err == nil {
+			for _, l := range locs {
+				names[l.Attributes.Locale] = [2]string{l.Attributes.Name, l.Attributes.Subtitle}
+			}
+		}
+	}
+
+	for _, loc := range localizations {
+		locale := loc.Attributes.Locale
+		fields := []struct{ name, value string }{
+			{"name", names[locale][0]},
+			{"subtitle", names[locale][1]},
+			{"description", loc.Attributes.Description},
+			{"promotional text", loc.Attributes.PromotionalText},
+			{"keywords", loc.Attributes.Keywords},
+		}
+
+		var all strings.Builder
+		for _, field := range fields {
+			all.WriteString(field.value + "\n")
+			for _, claim := range codescan.FindMedicalClaims(field.value) {
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityCritical,
+					Guideline: claim.Guideline,
+					Title:     fmt.Sprintf("[%s] Prohibited medical claim in %s: %q", locale, field.name, claim.Text),
+					Detail:    claim.Reason,
+					Fix:       fmt.Sprintf("Remove the claim from the %s, or back it with regulatory clearance and describe it in App Review notes.", field.name),
+				})
+			}
+		}
+
+		claimed := codescan.MakesHealthClaim(all.String())
+		for _, c := range codescan.MedicalCategories {
+			kw := c.Match(all.String())
+			if kw == "" || c.Disclaimed(loc.Attributes.Description) {
+				continue
+			}
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  codescan.DisclaimerSeverity(claimed),
+				Guideline: c.Guideline,
+				Title:     fmt.Sprintf("[%s] No %s disclaimer in description", locale, c.Name),
+				Detail:    fmt.Sprintf("Metadata mentions %q but the description doesn't tell users %s. Reviewers check health apps for disclaimers that keep users from relying on the app where it could put them at risk.", kw, c.Disclaimer),
+				Fix:       c.Fix,
+			})
+		}
+	}
+
+	return nil
+}
+
+// mediaLicenseAttachmentRe matches review attachments that look like rights
+// documentation.
+var mediaLicenseAttachmentRe = regexp.MustCompile(`(?i)(licen[cs]|agreement|contract|rights|authori[sz]ation|permission)`)
+
+// checkMediaLicensing flags metadata describing third-party movie, TV,
+// sports or music streaming, or media downloads, when the App Review notes
+// and attachments say nothing about the rights to the content.
+func checkMediaLicensing(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil {
+		return err
+	}
+
+	var media *codescan.MediaContent
+	var where string
+	for _, loc := range localizations {
+		for _, field := range []struct{ name, value string }{
+			{"description", loc.Attributes.Description},
+			{"keywords", loc.Attributes.Keywords},
+			{"promotional text", loc.Attributes.PromotionalText},
+		} {
+			if mc := codescan.FindMediaContent(field.value); mc != nil && (media == nil || mc.Download && !media.Download) {
+				media = mc
+				where = fmt.Sprintf("The %s %s", loc.Attributes.Locale, field.name)
+			}
+		}
+	}
+	if media == nil {
+		return nil
+	}
+
+	detail, err := client.GetReviewDetail(ctx, versions[0].ID)
+	if err != nil {
+		return err
+	}
+	if detail != nil {
+		if codescan.MentionsMediaLicense(detail.Attributes.Notes) {
+			return nil
+		}
+		attachments, err := client.GetReviewAttachments(ctx, detail.ID)
+		if err != nil {
+			return err
+		}
+		for _, a := range attachments {
+			if mediaLicenseAttachmentRe.MatchString(a.Attributes.FileName) {
+				return nil
+			}
+		}
+	}
+
+	if media.Download {
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityCritical,
+			Guideline: media.Guideline,
+			Title:     "Media downloads without authorization in App Review notes",
+			Detail:    fmt.Sprintf("%s mentions %q. Apps may not save, convert or download media from services such as YouTube, SoundCloud or Vimeo without explicit authorization, and the App Review notes don't mention any.", where, media.Text),
+			Fix:       "Remove the download feature, or attach the service's written authorization and describe it in the App Review notes.",
+		})
+		return nil
+	}
+	*findings = append(*findings, Finding{
+		Tier:      TierContent,
+		Severity:  SeverityWarn,
+		Guideline: media.Guideline,
+		Title:     "Streaming media app without licensing in App Review notes",
+		Detail:    fmt.Sprintf("%s mentions %q, but the App Review notes and attachments say nothing about the rights to the content. Reviewers ask streaming apps for licensing documentation, and 5.2.2 rejections are common when it's missing.", where, media.Text),
+		Fix:       codescan.MediaRightsFix,
+	})
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Apple trademarks that may not appear in app names or keywords. Trademarks
+// that are also common words ("Apple", "Mac") are left out.
+var appleTrademarks = []string{
+	"iPhone", "iPad", "iPod", "Apple Watch", "AirPods", "Apple TV", "Vision Pro",
+	"MacBook", "macOS", "iOS", "watchOS", "iCloud", "Siri", "FaceTime", "iMessage", "App Store",
+}
+
+// Competitor and popular-app brand names commonly rejected in keywords and
+// names. Brands that are also common words ("Uber", "Zoom") are left out.
+var defaultBrandTerms = []string{
+	"Instagram", "TikTok", "Facebook", "WhatsApp", "Snapchat", "YouTube", "Netflix",
+	"Spotify", "Google", "Twitter", "Telegram", "ChatGPT", "Pokemon", "Minecraft",
+	"Fortnite", "Candy Crush", "Tinder", "Duolingo",
+}
+
+var priceInNameRe = regexp.MustCompile(`(?i)(\bfree\b|[$€£¥]\s?\d|\b\d+\s?% off\b|\bsale\b|\bdiscount\b|\bcheap(est)?\b)`)
+
+// checkTrademarks flags Apple trademarks, pricing, and third-party brand names in
+// the app name, subtitle, keywords, and screenshot file names.
+func (r *Runner) checkTrademarks(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	brands := append(append([]string{}, defaultBrandTerms...), r.brandTerms...)
+
+	type field struct{ locale, name, value string }
+	var nameFields, keywordFields []field
+
+	if app, err := client.GetApp(ctx, appID); err == nil {
+		nameFields = append(nameFields, field{app.Attributes.PrimaryLocale, "app name", app.Attributes.Name})
+	}
+	if infos, err := client.GetAppInfos(ctx, appID); err == nil && len(infos) > 0 {
+		if locs, err := client.GetAppInfoLocalizations(ctx, infos[0].ID); err == nil {
+			for _, l := range locs {
+				nameFields = append(nameFields,
+					field{l.Attributes.Locale, "app name", l.Attributes.Name},
+					field{l.Attributes.Locale, "subtitle", l.Attributes.Subtitle})
+			}
+		}
+	}
+
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil {
+		return err
+	}
+	var localizations []asc.VersionLocalization
+	if len(versions) > 0 {
+		localizations, err = client.GetVersionLocalizations(ctx, versions[0].ID)
+		if err != nil {
+			return err
+		}
+		for _, loc := range localizations {
+			keywordFields = append(keywordFields, field{loc.Attributes.Locale, "keywords", loc.Attributes.Keywords})
+		}
+	}
+
+	seen := make(map[string]bool)
+	report := func(f Finding) {
+		if seen[f.Title] {
+			return
+		}
+		seen[f.Title] = true
+		*findings = append(*findings, f)
+	}
+
+	for _, f := range nameFields {
+		if f.value == "" {
+			continue
+		}
+		for _, tm := range appleTrademarks {
+			if namesTerm(f.value, tm) {
+				report(Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "2.3.7",
+					Title:     fmt.Sprintf("[%s] Apple trademark %q in %s", f.locale, tm, f.name),
+					Detail:    fmt.Sprintf("%q — Apple trademarks and product names may not be used in app names or subtitles, other than to say what the app works with (\"for iPhone\").", f.value),
+					Fix:       fmt.Sprintf("Remove %q from the %s. Mention device support in the description instead.", tm, f.name),
+				})
+				break
+			}
+		}
+		if m := priceInNameRe.FindString(f.value); m != "" {
+			report(Finding{
+				Tier:      TierContent,
+				Severity:  SeverityCritical,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] Pricing term %q in %s", f.locale, m, f.name),
+				Detail:    fmt.Sprintf("%q — app names and subtitles may not include prices or pricing terms.", f.value),
+				Fix:       fmt.Sprintf("Remove %q from the %s.", m, f.name),
+			})
+		}
+		for _, b := range brands {
+			if namesTerm(f.value, b) {
+				report(Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "2.3.7",
+					Title:     fmt.Sprintf("[%s] Third-party brand %q in %s", f.locale, b, f.name),
+					Detail:    fmt.Sprintf("%q — names and subtitles may not include other apps' or companies' trademarks, other than to say what the app works with (\"for %s\").", f.value, b),
+					Fix:       fmt.Sprintf("Remove %q from the %s unless you own the trademark.", b, f.name),
+				})
+			}
+		}
+	}
+
+	for _, f := range keywordFields {
+		var hits []string
+		for _, b := range brands {
+			if containsTerm(f.value, b) {
+				hits = append(hits, b)
+			}
+		}
+		for _, tm := range appleTrademarks {
+			if containsTerm(f.value, tm) {
+				hits = append(hits, tm)
+			}
+		}
+		if len(hits) > 0 {
+			report(Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] Trademarked terms in keywords: %s", f.locale, strings.Join(hits, ", ")),
+				Detail:    "Keywords may not include trademarked terms, popular app names, or competitor brands.",
+				Fix:       "Remove the trademarked terms from the keywords field.",
+			})
+		}
+	}
+
+	// Screenshot file names often reveal mockups made from other platforms or apps.
+	if len(localizations) > 0 {
+		sets, err := client.GetScreenshotSets(ctx, localizations[0].ID)
+		if err == nil {
+			for _, set := range sets {
+				shots, err := client.GetScreenshots(ctx, set.ID)
+				if err != nil {
+					continue
+				}
+				for _, ss := range shots {
+					name := ss.Attributes.FileName
+					for _, b := range append([]string{"Android", "Pixel", "Galaxy"}, brands...) {
+						if containsTerm(name, b) {
+							report(Finding{
+								Tier:      TierContent,
+								Severity:  SeverityWarn,
+								Guideline: "2.3.8",
+								Title:     fmt.Sprintf("Screenshot %q references %q", name, b),
+								Detail:    "The screenshot file name suggests it shows another platform's device or a third-party brand. Screenshots must show your app on Apple devices.",
+								Fix:       "Verify the screenshot and replace it if it shows non-Apple hardware or third-party branding.",
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// termPatterns caches the compiled pattern of each term containsTerm looks
+// for, as checks run for every locale of every app.
+var termPatterns sync.Map // term -> *regexp.Regexp
+
+func termPattern(term string) *regexp.Regexp {
+	if re, ok := termPatterns.Load(term); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(term) + `($|[^\p{L}\p{N}])`)
+	termPatterns.Store(term, re)
+	return re
+}
+
+// containsTerm reports whether term appears in s as a whole word, case-insensitively.
+func containsTerm(s, term string) bool {
+	return termPattern(term).MatchString(s)
+}
+
+// namesTerm is containsTerm for names and subtitles, where a trademark may
+// say what the app works with: "Widgets for iPhone" doesn't count.
+func namesTerm(s, term string) bool {
+	for _, loc := range termPattern(term).FindAllStringIndex(s, -1) {
+		before := strings.Fields(strings.ToLower(s[:loc[0]]))
+		if len(before) == 0 || strings.Trim(before[len(before)-1], "-–—|:·,(") != "for" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCommonMisspellings looks for common misspellings, repeated words and
+// spacing slips in user-facing copy.
+func checkCommonMisspellings(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil {
+		return err
+	}
+
+	for _, loc := range localizations {
+		locale := loc.Attributes.Locale
+		fields := []struct{ name, value string }{
+			{"description", loc.Attributes.Description},
+			{"what's new", loc.Attributes.WhatsNew},
+			{"promotional text", loc.Attributes.PromotionalText},
+		}
+
+		for _, field := range fields {
+			issues := spellcheck.Check(locale, field.value)
+			if len(issues) == 0 {
+				continue
+			}
+
+			var examples []string
+			for i, is := range issues {
+				if i == 5 {
+					examples = append(examples, fmt.Sprintf("and %d more", len(issues)-5))
+					break
+				}
+				if is.Suggestion != "" {
+					examples = append(examples, fmt.Sprintf("%q → %q", is.Word, is.Suggestion))
+				} else {
+					examples = append(examples, fmt.Sprintf("%s %s", is.Kind, is.Context))
+				}
+			}
+
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityInfo,
+				Guideline: "2.3",
+				Title:     fmt.Sprintf("[%s] %d likely typo(s) in %s", locale, len(issues), field.name),
+				Detail:    strings.Join(examples, "; "),
+				Fix:       fmt.Sprintf("Proofread the %s before submitting. Sloppy metadata draws extra scrutiny from reviewers.", field.name),
+			})
+		}
+	}
+
+	return nil
+}
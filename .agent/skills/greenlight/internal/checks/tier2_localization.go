@@ -0,0 +1,230 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/RevylAI/greenlight/internal/itunes"
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// localizedPricingTerms are "free" and its kin in the languages the
+// English pricing check can't read, by locale language.
+var localizedPricingTerms = map[string][]string{
+	"de": {"kostenlos", "gratis"},
+	"fr": {"gratuit", "gratuite"},
+	"es": {"gratis", "gratuito", "gratuita"},
+	"it": {"gratis", "gratuito", "gratuita"},
+	"pt": {"grátis", "gratuito", "gratuita"},
+	"nl": {"gratis"},
+	"sv": {"gratis"},
+	"da": {"gratis"},
+	"nb": {"gratis"},
+	"tr": {"ücretsiz", "bedava"},
+	"ru": {"бесплатно", "бесплатный"},
+	"pl": {"darmowy", "darmowa", "za darmo"},
+	"ja": {"無料"},
+	"ko": {"무료"},
+	"zh": {"免费", "免費"},
+}
+
+// marketTerms are terms a storefront's regulators or App Review reject,
+// by locale.
+var marketTerms = []struct {
+	locale string
+	terms  []string
+	detail string
+}{
+	{"zh-Hans", []string{"VPN", "翻墙", "翻牆", "彩票", "博彩", "赌博"}, "Apps on the China mainland storefront may not offer VPNs without a government license, or lottery and gambling services. Metadata that mentions them is rejected or the app is removed from the storefront."},
+	{"ko", []string{"도박", "카지노"}, "Gambling is illegal in South Korea outside licensed venues; gambling terms in Korean metadata get the app removed from the storefront."},
+}
+
+// nameSeparatorRe splits an app name into its brand and a descriptive part:
+// "Acme – Notes & Lists" is brand "Acme".
+var nameSeparatorRe = regexp.MustCompile(`\s+[-–—|:·]\s+|:\s+`)
+
+// maxNameSearches bounds the App Store searches for name collisions.
+const maxNameSearches = 8
+
+// checkLocalizedNames compares the app name, subtitle and keywords across
+// locales for branding that differs from the primary locale and terms a
+// market rejects, and searches each storefront for existing apps with the
+// same name, which invite copycat disputes under 4.1.
+func checkLocalizedNames(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	app, err := client.GetApp(ctx, appID)
+	if err != nil {
+		return err
+	}
+	type localized struct{ locale, name, subtitle, keywords string }
+	var locales []localized
+	index := map[string]int{}
+	if infos, err := client.GetAppInfos(ctx, appID); err == nil && len(infos) > 0 {
+		if locs, err := client.GetAppInfoLocalizations(ctx, infos[0].ID); err == nil {
+			for _, l := range locs {
+				index[l.Attributes.Locale] = len(locales)
+				locales = append(locales, localized{locale: l.Attributes.Locale, name: l.Attributes.Name, subtitle: l.Attributes.Subtitle})
+			}
+		}
+	}
+	if versions, err := client.GetAppStoreVersions(ctx, appID); err == nil && len(versions) > 0 {
+		if locs, err := client.GetVersionLocalizations(ctx, versions[0].ID); err == nil {
+			for _, l := range locs {
+				if i, ok := index[l.Attributes.Locale]; ok {
+					locales[i].keywords = l.Attributes.Keywords
+				}
+			}
+		}
+	}
+
+	primaryName := app.Attributes.Name
+	if i, ok := index[app.Attributes.PrimaryLocale]; ok && locales[i].name != "" {
+		primaryName = locales[i].name
+	}
+	primaryBrand := brandOf(primaryName)
+
+	for _, l := range locales {
+		if l.locale != app.Attributes.PrimaryLocale && l.name != "" && primaryBrand != "" &&
+			!strings.Contains(strings.ToLower(l.name), strings.ToLower(primaryBrand)) && latin(l.name) {
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityInfo,
+				Guideline: "2.3.7",
+				Title:     fmt.Sprintf("[%s] App name %q drops the brand %q", l.locale, l.name, primaryBrand),
+				Detail:    fmt.Sprintf("The %s name is %q. Names that change brand between storefronts look like different apps, and are harder to defend in a name dispute.", app.Attributes.PrimaryLocale, primaryName),
+				Fix:       fmt.Sprintf("Keep %q in every localized name and translate only the descriptive part.", primaryBrand),
+			})
+		}
+
+		lang, _, _ := strings.Cut(l.locale, "-")
+		for _, field := range []struct{ name, value string }{{"app name", l.name}, {"subtitle", l.subtitle}, {"keywords", l.keywords}} {
+			for _, term := range localizedPricingTerms[lang] {
+				if containsTerm(field.value, term) || unspaced(term) && strings.Contains(field.value, term) {
+					*findings = append(*findings, Finding{
+						Tier:      TierContent,
+						Severity:  SeverityCritical,
+						Guideline: "2.3.7",
+						Title:     fmt.Sprintf("[%s] Pricing term %q in %s", l.locale, term, field.name),
+						Detail:    fmt.Sprintf("%q — names, subtitles and keywords may not include prices or pricing terms in any language.", field.value),
+						Fix:       fmt.Sprintf("Remove %q from the %s.", term, field.name),
+					})
+					break
+				}
+			}
+			for _, m := range marketTerms {
+				if l.locale != m.locale && lang != m.locale {
+					continue
+				}
+				for _, term := range m.terms {
+					if strings.Contains(strings.ToLower(field.value), strings.ToLower(term)) {
+						*findings = append(*findings, Finding{
+							Tier:      TierContent,
+							Severity:  SeverityWarn,
+							Guideline: "5.0",
+							Title:     fmt.Sprintf("[%s] %q in %s is restricted in this market", l.locale, term, field.name),
+							Detail:    m.detail,
+							Fix:       fmt.Sprintf("Remove %q from the %s, or the territory from availability if the app depends on it.", term, field.name),
+						})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Existing apps with the same name, one search per storefront and name.
+	search := itunes.NewClient(&http.Client{Timeout: 15 * time.Second})
+	searched := map[string]bool{}
+	for _, l := range locales {
+		country := itunes.Country(l.locale)
+		key := country + "/" + normalizeName(l.name)
+		if l.name == "" || country == "" || searched[key] || len(searched) >= maxNameSearches {
+			continue
+		}
+		searched[key] = true
+		results, err := search.Search(ctx, itunes.Query{Term: l.name, Country: country, Limit: 25})
+		if err != nil {
+			return nil // the iTunes Search API is best effort
+		}
+		// The developer's own apps share its brand; results carry the seller
+		// when the app itself is already on the store.
+		var seller string
+		for _, other := range results {
+			if other.BundleID == app.Attributes.BundleID {
+				seller = other.Seller
+			}
+		}
+		brandReported := false
+		for _, other := range results {
+			if other.BundleID == app.Attributes.BundleID || seller != "" && other.Seller == seller {
+				continue
+			}
+			switch {
+			case normalizeName(other.Name) == normalizeName(l.name):
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "4.1",
+					Title:     fmt.Sprintf("[%s] Name matches %q by %s", l.locale, other.Name, other.Seller),
+					Detail:    fmt.Sprintf("An app with the same name is already on the %s storefront (%s). Apps whose names match an existing app are rejected as copycats under 4.1, or taken down when the other developer files a dispute.", strings.ToUpper(country), other.URL),
+					Fix:       "Choose a distinctive name, or confirm you hold the trademark and include the registration in App Review notes.",
+				})
+			case !brandReported && len([]rune(primaryBrand)) >= 4 && strings.EqualFold(brandOf(other.Name), primaryBrand):
+				brandReported = true
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityInfo,
+					Guideline: "4.1",
+					Title:     fmt.Sprintf("[%s] Brand %q is also used by %q (%s)", l.locale, primaryBrand, other.Name, other.Seller),
+					Detail:    fmt.Sprintf("%s on the %s storefront shares the brand. Similar names are a common cause of 4.1 and trademark disputes.", other.URL, strings.ToUpper(country)),
+					Fix:       "Make sure you hold the rights to the brand in the storefronts you sell in, or differentiate the name.",
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// brandOf returns the part of an app name before its first separator.
+func brandOf(name string) string {
+	return strings.TrimSpace(nameSeparatorRe.Split(name, 2)[0])
+}
+
+// normalizeName lowercases name and drops everything but letters and digits,
+// so "Acme: Notes" and "ACME Notes" compare equal.
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// latin reports whether s is written in the Latin script, where a missing
+// brand isn't explained by transliteration.
+func latin(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// unspaced reports whether s is in a script written without spaces between
+// words, where a term can't be matched on word boundaries.
+func unspaced(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// maxSupportPageBytes bounds how much of a support page is read when
+// looking for contact details.
+const maxSupportPageBytes = 1 << 20
+
+// Hosts of social networks whose profile pages don't count as a support page.
+var socialHosts = map[string]string{
+	"facebook.com":  "Facebook",
+	"fb.com":        "Facebook",
+	"instagram.com": "Instagram",
+	"twitter.com":   "Twitter",
+	"x.com":         "X",
+	"tiktok.com":    "TikTok",
+	"linkedin.com":  "LinkedIn",
+	"youtube.com":   "YouTube",
+	"t.me":          "Telegram",
+	"discord.gg":    "Discord",
+	"discord.com":   "Discord",
+	"threads.net":   "Threads",
+	"reddit.com":    "Reddit",
+	"linktr.ee":     "Linktree",
+}
+
+// Hosts of app store listings, which a marketing URL must not point at.
+var storeHosts = map[string]string{
+	"apps.apple.com":   "the App Store",
+	"itunes.apple.com": "the App Store",
+	"play.google.com":  "Google Play",
+}
+
+var contactRe = regexp.MustCompile(`(?i)(mailto:|tel:|<form\b|[\w.+-]+@[\w-]+\.[\w.-]+|\bcontact\b|\bkontakt|\bcontacto\b|\bcontactez|\bcontatt|\bsupport@|\bhelp ?desk\b|\bfeedback\b)`)
+
+// pageFetch is one URL requested with one locale's Accept-Language.
+type pageFetch struct {
+	url    string
+	locale string
+}
+
+// page is what fetching a URL returned.
+type page struct {
+	status   int
+	finalURL *url.URL
+	body     string
+	err      error
+}
+
+// checkSupportURLContent checks what the support and marketing URLs serve
+// in each locale: a support page that loads for some locales but not
+// others, support URLs that are only a social profile or offer no way to
+// get in touch, and marketing URLs that lead to a store listing.
+func checkSupportURLContent(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil || len(localizations) == 0 {
+		return err
+	}
+
+	var fetches []pageFetch
+	var support, marketing []metadataURL
+	queued := map[pageFetch]bool{}
+	add := func(f pageFetch) {
+		if !queued[f] {
+			queued[f] = true
+			fetches = append(fetches, f)
+		}
+	}
+	for _, loc := range localizations {
+		locale := loc.Attributes.Locale
+		if u := strings.TrimSpace(loc.Attributes.SupportURL); u != "" {
+			support = append(support, metadataURL{locale, "Support URL", u})
+			add(pageFetch{u, locale})
+		}
+		if u := strings.TrimSpace(loc.Attributes.MarketingURL); u != "" {
+			marketing = append(marketing, metadataURL{locale, "Marketing URL", u})
+			add(pageFetch{u, locale})
+		}
+	}
+
+	pages := fetchPages(ctx, fetches)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Locales that can't load a URL other locales can: the site is blocking
+	// by language or region, and reviewers in that storefront see an error.
+	loads := map[string][]string{}
+	fails := map[string][]string{}
+	for _, f := range fetches {
+		if p := pages[f]; p.err == nil && p.status < 400 {
+			loads[f.url] = append(loads[f.url], f.locale)
+		} else {
+			fails[f.url] = append(fails[f.url], f.locale)
+		}
+	}
+	for _, u := range sortedKeys(fails) {
+		if len(loads[u]) == 0 {
+			continue // unreachable everywhere: reported by the reachability check
+		}
+		locales := fails[u]
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "1.5",
+			Title:     fmt.Sprintf("%s doesn't load for %s", u, strings.Join(locales, ", ")),
+			Detail:    fmt.Sprintf("The page loads for %s but not when requested in %s (%s). Sites that block by language or region also block the reviewers in those storefronts.", strings.Join(loads[u], ", "), strings.Join(locales, ", "), describePage(pages[pageFetch{u, locales[0]}])),
+			Fix:       "Serve the page to every locale and region the app is offered in, and remove any geo-blocking in front of it.",
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, s := range support {
+		p := pages[pageFetch{s.url, s.locale}]
+		if name := socialNetwork(s.url, p); name != "" {
+			if key := "social " + s.url; !seen[key] {
+				seen[key] = true
+				*findings = append(*findings, Finding{
+					Tier:      TierContent,
+					Severity:  SeverityWarn,
+					Guideline: "1.5",
+					Title:     fmt.Sprintf("[%s] Support URL is a social media profile (%s): %s", s.locale, name, s.url),
+					Detail:    "The support URL must lead to a page where users can get help. A social media profile needs an account to message you and is regularly rejected.",
+					Fix:       "Point the support URL at a page on your own site with a support email address or contact form.",
+				})
+			}
+			continue
+		}
+		if p.err != nil || p.status >= 400 || contactRe.MatchString(p.body) {
+			continue
+		}
+		if key := "contact " + s.url; !seen[key] {
+			seen[key] = true
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "1.5",
+				Title:     fmt.Sprintf("[%s] Support URL has no contact details: %s", s.locale, s.url),
+				Detail:    "The page has no email address, contact form, or contact link. Guideline 1.5 requires an easy way to contact you with questions and support requests.",
+				Fix:       "Add a support email address or contact form to the page.",
+			})
+		}
+	}
+
+	for _, m := range marketing {
+		target := m.url
+		store := storeHost(m.url)
+		if p := pages[pageFetch{m.url, m.locale}]; store == "" && p.finalURL != nil {
+			target = p.finalURL.String()
+			store = storeHost(target)
+		}
+		if store == "" {
+			continue
+		}
+		if key := "store " + m.url; !seen[key] {
+			seen[key] = true
+			*findings = append(*findings, Finding{
+				Tier:      TierContent,
+				Severity:  SeverityWarn,
+				Guideline: "2.3",
+				Title:     fmt.Sprintf("[%s] Marketing URL leads to %s: %s", m.locale, store, m.url),
+				Detail:    fmt.Sprintf("It resolves to %s. The marketing URL should be your own page about the app, not a store listing.", target),
+				Fix:       "Point the marketing URL at your app's website, or leave it empty.",
+			})
+		}
+	}
+
+	return nil
+}
+
+// fetchPages fetches each URL once per locale, in parallel.
+func fetchPages(ctx context.Context, fetches []pageFetch) map[pageFetch]page {
+	httpClient := &http.Client{CheckRedirect: checkRedirect}
+	pages := make(map[pageFetch]page)
+	var mu sync.Mutex
+
+	queue := make(chan pageFetch)
+	var wg sync.WaitGroup
+	for range min(urlProbeWorkers, len(fetches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range queue {
+				p := fetchPage(ctx, httpClient, f)
+				mu.Lock()
+				pages[f] = p
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, f := range fetches {
+		select {
+		case queue <- f:
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return pages
+}
+
+// fetchPage GETs f.url with f.locale as the preferred language and reads
+// the start of the body.
+func fetchPage(ctx context.Context, httpClient *http.Client, f pageFetch) page {
+	parsed, err := url.Parse(f.url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return page{err: fmt.Errorf("not an http(s) URL")}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, urlProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url, nil)
+	if err != nil {
+		return page{err: err}
+	}
+	req.Header.Set("User-Agent", urlProbeUserAgent)
+	req.Header.Set("Accept-Language", f.locale+", "+strings.SplitN(f.locale, "-", 2)[0]+";q=0.9")
+
+	// Stop at a store listing: where the URL leads is all that matters then.
+	c := *httpClient
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if storeHost(req.URL.String()) != "" {
+			return http.ErrUseLastResponse
+		}
+		return httpClient.CheckRedirect(req, via)
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return page{err: errNoResponse}
+		}
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return page{err: uerr.Err}
+		}
+		return page{err: err}
+	}
+	defer resp.Body.Close()
+	finalURL := resp.Request.URL
+	if loc, err := resp.Location(); err == nil && storeHost(loc.String()) != "" {
+		return page{status: resp.StatusCode, finalURL: loc}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxSupportPageBytes))
+	return page{status: resp.StatusCode, finalURL: finalURL, body: string(body)}
+}
+
+func describePage(p page) string {
+	if p.err != nil {
+		return p.err.Error()
+	}
+	if p.status == http.StatusUnavailableForLegalReasons {
+		return "HTTP 451, blocked for legal reasons"
+	}
+	return fmt.Sprintf("HTTP %d %s", p.status, http.StatusText(p.status))
+}
+
+// socialNetwork names the social network u, or the page it redirected to,
+// belongs to.
+func socialNetwork(u string, p page) string {
+	hosts := []string{hostOf(u)}
+	if p.finalURL != nil {
+		hosts = append(hosts, p.finalURL.Hostname())
+	}
+	for _, h := range hosts {
+		if name, ok := socialHosts[strings.TrimPrefix(strings.TrimPrefix(h, "www."), "m.")]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// storeHost names the app store u links to, if any.
+func storeHost(u string) string {
+	return storeHosts[strings.TrimPrefix(hostOf(u), "www.")]
+}
+
+func hostOf(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
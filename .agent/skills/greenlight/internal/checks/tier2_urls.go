@@ -0,0 +1,200 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// URL probing limits. Every URL gets at most urlProbeTimeout per request,
+// and urlProbeWorkers are probed at once.
+const (
+	urlProbeTimeout = 10 * time.Second
+	urlProbeWorkers = 8
+	maxURLRedirects = 10
+)
+
+// urlProbeUserAgent is sent instead of Go's default, which some hosts block
+// although a reviewer's browser gets through.
+const urlProbeUserAgent = "Mozilla/5.0 (compatible; greenlight; +https://github.com/RevylAI/greenlight)"
+
+var (
+	errRedirectLoop     = errors.New("redirect loop")
+	errTooManyRedirects = fmt.Errorf("more than %d redirects", maxURLRedirects)
+	errNoResponse       = fmt.Errorf("no response within %s", urlProbeTimeout)
+)
+
+// metadataURL is one URL field of one localization.
+type metadataURL struct {
+	locale string
+	field  string
+	url    string
+}
+
+// checkURLReachability verifies that support/marketing URLs are reachable.
+// Localizations usually share their URLs, so each distinct URL is probed
+// once, and the probes run in parallel.
+func checkURLReachability(ctx context.Context, client *asc.Client, appID string, findings *[]Finding) error {
+	versions, err := client.GetAppStoreVersions(ctx, appID)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+
+	localizations, err := client.GetVersionLocalizations(ctx, versions[0].ID)
+	if err != nil || len(localizations) == 0 {
+		return err
+	}
+
+	var fields []metadataURL
+	var urls []string
+	seen := map[string]bool{}
+	for _, loc := range localizations {
+		for _, f := range []metadataURL{
+			{loc.Attributes.Locale, "Support URL", loc.Attributes.SupportURL},
+			{loc.Attributes.Locale, "Marketing URL", loc.Attributes.MarketingURL},
+		} {
+			if f.url = strings.TrimSpace(f.url); f.url == "" {
+				continue
+			}
+			fields = append(fields, f)
+			if !seen[f.url] {
+				seen[f.url] = true
+				urls = append(urls, f.url)
+			}
+		}
+	}
+
+	problems := probeURLs(ctx, urls)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, f := range fields {
+		problem := problems[f.url]
+		if problem == nil {
+			continue
+		}
+		*findings = append(*findings, Finding{
+			Tier:      TierContent,
+			Severity:  SeverityWarn,
+			Guideline: "2.3",
+			Title:     fmt.Sprintf("[%s] %s is unreachable: %s", f.locale, f.field, f.url),
+			Detail:    fmt.Sprintf("Apple verifies that URLs in your metadata are accessible during review. Probing it failed: %v.", problem),
+			Fix:       "Ensure the URL is live and returns a 200 status code.",
+		})
+	}
+
+	return nil
+}
+
+// probeURLs probes urls concurrently and returns the problem found with
+// each unreachable one.
+func probeURLs(ctx context.Context, urls []string) map[string]error {
+	httpClient := &http.Client{CheckRedirect: checkRedirect}
+	problems := make(map[string]error)
+	var mu sync.Mutex
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range min(urlProbeWorkers, len(urls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				if err := probeURL(ctx, httpClient, u); err != nil {
+					mu.Lock()
+					problems[u] = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, u := range urls {
+		select {
+		case queue <- u:
+		case <-ctx.Done():
+		}
+	}
+	close(queue)
+	wg.Wait()
+	return problems
+}
+
+// probeURL requests u with HEAD and, when that fails, with GET: some
+// servers reject or mishandle HEAD although the page loads fine. Timeouts
+// and redirect problems are not retried, since GET would fail the same way.
+func probeURL(ctx context.Context, httpClient *http.Client, u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("not an http(s) URL")
+	}
+
+	status, err := fetchStatus(ctx, httpClient, http.MethodHead, u)
+	if err == nil && status < 400 {
+		return nil
+	}
+	if err != nil && (ctx.Err() != nil || errors.Is(err, errNoResponse) ||
+		errors.Is(err, errRedirectLoop) || errors.Is(err, errTooManyRedirects)) {
+		return err
+	}
+
+	status, err = fetchStatus(ctx, httpClient, http.MethodGet, u)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return fmt.Errorf("HTTP %d %s", status, http.StatusText(status))
+	}
+	return nil
+}
+
+// fetchStatus sends one request and returns the final status code after
+// redirects.
+func fetchStatus(ctx context.Context, httpClient *http.Client, method, u string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, urlProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", urlProbeUserAgent)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if isTimeout(err) {
+			return 0, errNoResponse
+		}
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			return 0, uerr.Err
+		}
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// checkRedirect stops a redirect chain that revisits a URL or runs longer
+// than maxURLRedirects.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	for _, prev := range via {
+		if prev.URL.String() == req.URL.String() {
+			return fmt.Errorf("%w back to %s", errRedirectLoop, req.URL)
+		}
+	}
+	if len(via) >= maxURLRedirects {
+		return errTooManyRedirects
+	}
+	return nil
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
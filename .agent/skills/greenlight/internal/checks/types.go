@@ -1,27 +1,20 @@
 package checks
 
+import (
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
 // Severity indicates how likely a finding is to cause rejection.
-type Severity int
+type Severity = severity.Level
 
 const (
-	SeverityInfo Severity = iota // Best practice recommendation
-	SeverityWarn                 // High risk of rejection
-	SeverityBlock                // Will almost certainly be rejected
+	SeverityInfo     = severity.Info     // Best practice recommendation
+	SeverityWarn     = severity.Warn     // High risk of rejection
+	SeverityCritical = severity.Critical // Will almost certainly be rejected
 )
 
-func (s Severity) String() string {
-	switch s {
-	case SeverityInfo:
-		return "INFO"
-	case SeverityWarn:
-		return "WARN"
-	case SeverityBlock:
-		return "BLOCK"
-	default:
-		return "UNKNOWN"
-	}
-}
-
 // Tier represents the check tier level.
 type Tier int
 
@@ -40,6 +33,51 @@ type Finding struct {
 	Title     string   `json:"title"`
 	Detail    string   `json:"detail"`
 	Fix       string   `json:"fix,omitempty"`
+	// GuidelineTitle and GuidelineURL resolve Guideline for reports.
+	GuidelineTitle string `json:"guideline_title,omitempty"`
+	GuidelineURL   string `json:"guideline_url,omitempty"`
+	// RuleID names the kind of finding; Fingerprint identifies this
+	// occurrence across runs.
+	RuleID      string `json:"rule_id,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Check is the ID of the check that reported the finding.
+	Check string `json:"check,omitempty"`
+	// Risk is the estimated likelihood, from 0 to 100, that the finding
+	// gets the app rejected (see internal/risk).
+	Risk int `json:"risk,omitempty"`
+	// Effort and Owner suggest the size of the fix and the team it belongs
+	// to (see internal/triage).
+	Effort string `json:"effort,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+}
+
+// ScanFinding converts f to scan.Finding, with "asc" as its source.
+func (f Finding) ScanFinding() scan.Finding {
+	return scan.Finding{
+		Source:         "asc",
+		RuleID:         f.RuleID,
+		Severity:       f.Severity,
+		Guideline:      f.Guideline,
+		GuidelineTitle: f.GuidelineTitle,
+		GuidelineURL:   f.GuidelineURL,
+		Title:          f.Title,
+		Detail:         f.Detail,
+		Fix:            f.Fix,
+		Fingerprint:    f.Fingerprint,
+		Check:          f.Check,
+		Risk:           f.Risk,
+		Effort:         f.Effort,
+		Owner:          f.Owner,
+	}
+}
+
+// ScanFindings converts findings to scan.Finding.
+func ScanFindings(findings []Finding) []scan.Finding {
+	out := make([]scan.Finding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, f.ScanFinding())
+	}
+	return out
 }
 
 // Results holds the complete scan output.
@@ -57,14 +95,20 @@ type Summary struct {
 	Warns  int `json:"warns"`
 	Infos  int `json:"infos"`
 	Passed bool `json:"passed"` // true if zero BLOCKs
+	// RiskScore is the estimated likelihood, from 0 to 100, that the app is
+	// rejected for any of the findings; Grade rates it from A to F.
+	RiskScore int    `json:"risk_score"`
+	Grade     string `json:"grade"`
 }
 
 func (r *Results) ComputeSummary() {
 	r.Summary = Summary{}
+	var scores []int
 	for _, f := range r.Findings {
 		r.Summary.Total++
+		scores = append(scores, risk.Score(f.Severity, f.Guideline))
 		switch f.Severity {
-		case SeverityBlock:
+		case SeverityCritical:
 			r.Summary.Blocks++
 		case SeverityWarn:
 			r.Summary.Warns++
@@ -73,4 +117,6 @@ func (r *Results) ComputeSummary() {
 		}
 	}
 	r.Summary.Passed = r.Summary.Blocks == 0
+	r.Summary.RiskScore = risk.Overall(scores)
+	r.Summary.Grade = risk.Grade(r.Summary.RiskScore)
 }
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"golang.org/x/term"
+)
+
+// resolveAppID turns an --app-id value into an App Store Connect ID. Numeric
+// IDs are used as given; a bundle ID or app name is looked up with FindApp,
+// asking which app was meant when several match, and the answer is cached
+// in the config so later runs skip the lookup.
+func resolveAppID(ctx context.Context, client *asc.Client, query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || isAppID(query) {
+		return query, nil
+	}
+	if id := cachedAppID(query); id != "" {
+		return id, nil
+	}
+
+	apps, err := client.FindApp(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up app %q: %w", query, err)
+	}
+	var app asc.App
+	switch len(apps) {
+	case 0:
+		return "", fmt.Errorf("no app matches %q (use the numeric ID, bundle ID or name shown in App Store Connect)", query)
+	case 1:
+		app = apps[0]
+	default:
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			var names []string
+			for _, a := range apps {
+				names = append(names, fmt.Sprintf("%s (%s, %s)", a.Attributes.Name, a.Attributes.BundleID, a.ID))
+			}
+			return "", fmt.Errorf("%q matches %d apps; pass one of their IDs: %s", query, len(apps), strings.Join(names, "; "))
+		}
+		if app, err = chooseApp(query, apps); err != nil {
+			return "", err
+		}
+	}
+
+	dim.Printf("  %s → %s (%s, %s)\n", query, app.ID, app.Attributes.Name, app.Attributes.BundleID)
+	cacheAppID(query, app.ID)
+	return app.ID, nil
+}
+
+// appIDOrCached resolves query from the config cache only, for commands that
+// don't talk to App Store Connect.
+func appIDOrCached(query string) string {
+	if id := cachedAppID(strings.TrimSpace(query)); id != "" {
+		return id
+	}
+	return query
+}
+
+func isAppID(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
+
+func chooseApp(query string, apps []asc.App) (asc.App, error) {
+	fmt.Printf("\n  %q matches %d apps:\n", query, len(apps))
+	for i, a := range apps {
+		fmt.Printf("    %d) %s  %s  (%s)\n", i+1, a.Attributes.Name, a.Attributes.BundleID, a.ID)
+	}
+	fmt.Print("  Which one? ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(apps) {
+		return asc.App{}, fmt.Errorf("no app chosen")
+	}
+	return apps[n-1], nil
+}
+
+func cachedAppID(query string) string {
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.AppIDs[query]
+}
+
+// cacheAppID remembers the ID query resolved to. Failing to save only costs
+// a lookup next time.
+func cacheAppID(query, id string) {
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	if cfg.AppIDs == nil {
+		cfg.AppIDs = map[string]string{}
+	}
+	cfg.AppIDs[query] = id
+	config.Save(cfg)
+}
@@ -8,8 +8,8 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/RevylAI/greenlight/internal/asc"
 	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/pkg/asc"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
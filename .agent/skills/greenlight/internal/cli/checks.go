@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/checks"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var checksFormat string
+
+var checksCmd = &cobra.Command{
+	Use:   "checks",
+	Short: "List the checks and scanners --only and --skip select from",
+}
+
+var checksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the App Store Connect checks by tier and the preflight scanners",
+	Long: `List every App Store Connect check 'greenlight scan' runs, by tier, and
+every scanner 'greenlight preflight' runs. Their IDs are what --only and
+--skip accept, along with rule IDs ('greenlight rules list') and guideline
+sections such as 5.1 or '5.1.*'.`,
+	Args: cobra.NoArgs,
+	RunE: runChecksList,
+}
+
+var tierNames = map[checks.Tier]string{
+	checks.TierMetadata: "Metadata & completeness",
+	checks.TierContent:  "Content analysis",
+	checks.TierBinary:   "Binary inspection",
+	checks.TierPattern:  "Historical pattern matching",
+}
+
+func init() {
+	checksListCmd.Flags().StringVar(&checksFormat, "format", "terminal", "output format: terminal, json")
+	checksCmd.AddCommand(checksListCmd)
+	rootCmd.AddCommand(checksCmd)
+}
+
+func runChecksList(cmd *cobra.Command, args []string) error {
+	catalog := checks.Catalog()
+	var scanners []string
+	for _, s := range scan.All() {
+		scanners = append(scanners, s.Name())
+	}
+
+	if strings.ToLower(checksFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Checks   []checks.CheckInfo `json:"checks"`
+			Scanners []string           `json:"scanners"`
+		}{catalog, scanners})
+	}
+
+	bold := color.New(color.Bold)
+	purple.Println("\n  greenlight checks")
+	tier := checks.Tier(0)
+	for _, c := range catalog {
+		if c.Tier != tier {
+			tier = c.Tier
+			fmt.Println()
+			bold.Printf("  scan — tier %d: %s\n", tier, tierNames[tier])
+		}
+		fmt.Printf("    %-30s", c.ID)
+		dim.Printf(" %s\n", c.Name)
+	}
+	fmt.Println()
+	bold.Println("  preflight scanners")
+	for _, name := range scanners {
+		fmt.Printf("    %s\n", name)
+	}
+
+	fmt.Println()
+	dim.Println("  Select with --only / --skip: check or scanner IDs, rule IDs, or guideline sections ('5.1.*').")
+	fmt.Println()
+	return nil
+}
+
+// printSelection adds the --only and --skip patterns to a command banner
+// written to w whose labels are width characters wide.
+func printSelection(w io.Writer, width int, sel scan.Selection) {
+	if len(sel.Only) > 0 {
+		fmt.Fprintf(w, "  %-*s%s\n", width, "Only:", strings.Join(sel.Only, ", "))
+	}
+	if len(sel.Skip) > 0 {
+		fmt.Fprintf(w, "  %-*s%s\n", width, "Skip:", strings.Join(sel.Skip, ", "))
+	}
+}
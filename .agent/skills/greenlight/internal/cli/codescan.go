@@ -1,22 +1,31 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"sort"
-	"strings"
 	"time"
 
-	"github.com/fatih/color"
-	"github.com/RevylAI/greenlight/internal/codescan"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/codescan/swiftsyntax"
+	"github.com/RevylAI/greenlight/pkg/scan"
 	"github.com/spf13/cobra"
 )
 
 var (
-	codescanPath   string
-	codescanFormat string
-	codescanOutput string
+	codescanPath    string
+	codescanFormat  string
+	codescanOutputs []string
+	codescanRedact  bool
+	codescanVerify  bool
+	codescanAST     string
+	codescanMaxMB   int
+	codescanMmap    bool
+	codescanGroupBy string
+	codescanInclude []string
+	codescanExclude []string
 )
 
 var codescanCmd = &cobra.Command{
@@ -28,27 +37,48 @@ App Store rejections. Works with Swift, Objective-C, React Native, and Expo.
 No App Store Connect account needed — runs entirely offline.
 
 Checks for:
-  • Private API usage (CRITICAL)
-  • Hardcoded secrets (CRITICAL)
+  • Private API usage, including obfuscated selectors (CRITICAL)
+  • Hardcoded secrets: provider keys and high-entropy strings (CRITICAL)
   • External payment for digital goods (CRITICAL)
+  • External purchase link-outs vs. StoreKit entitlement (CRITICAL)
   • Dynamic code execution (CRITICAL)
   • Missing Sign in with Apple when using social login
   • Missing Restore Purchases for IAP
+  • Subscription paywalls missing price, terms, or privacy links
   • Missing ATT for ad/tracking SDKs
+  • Tracking SDKs started before the ATT prompt, unguarded IDFA reads
   • Account creation without deletion option
+  • HealthKit/ResearchKit/CareKit purpose strings, iCloud storage, ad use
+  • Gambling, raffle, and loot-box mechanics
   • Placeholder content in strings
   • Platform references (Android, Google Play)
   • Hardcoded IPv4 addresses
   • Insecure HTTP URLs
   • Vague Info.plist purpose strings
-  • Expo config issues`,
+  • Encryption usage vs. ITSAppUsesNonExemptEncryption
+  • Expo config issues
+  • Shipped React Native bundles (main.jsbundle in build output)
+
+With --swift-ast, Swift files are parsed instead of matched line by line:
+matches inside comments are ignored, and ATT ordering follows call
+structure (SDK starts must be inside the requestTrackingAuthorization
+completion handler). Use "builtin", "sourcekitten" (needs sourcekitten on
+PATH), or "auto".`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runCodescan,
 }
 
 func init() {
-	codescanCmd.Flags().StringVar(&codescanFormat, "format", "terminal", "output format: terminal, json")
-	codescanCmd.Flags().StringVar(&codescanOutput, "output", "", "write report to file (stdout if omitted)")
+	codescanCmd.Flags().StringVar(&codescanFormat, "format", "terminal", formatFlagUsage)
+	codescanCmd.Flags().StringArrayVar(&codescanOutputs, "output", nil, outputFlagUsage)
+	codescanCmd.Flags().StringVar(&codescanGroupBy, "group-by", "severity", groupByFlagUsage)
+	codescanCmd.Flags().StringSliceVar(&codescanInclude, "include", nil, includeFlagUsage)
+	codescanCmd.Flags().StringSliceVar(&codescanExclude, "exclude", nil, excludeFlagUsage)
+	codescanCmd.Flags().BoolVar(&codescanRedact, "redact", false, "mask detected secrets in report output")
+	codescanCmd.Flags().BoolVar(&codescanVerify, "verify-secrets", false, "check detected keys against provider APIs (sends each key to its own provider)")
+	codescanCmd.Flags().StringVar(&codescanAST, "swift-ast", "off", "syntax-aware Swift analysis: off, auto, builtin, sourcekitten")
+	codescanCmd.Flags().IntVar(&codescanMaxMB, "max-file-size", codescan.DefaultMaxFileSize>>20, "skip source files larger than this many MB (0 for no limit)")
+	codescanCmd.Flags().BoolVar(&codescanMmap, "mmap", false, "read files through memory mappings (lower memory on large repos)")
 	rootCmd.AddCommand(codescanCmd)
 }
 
@@ -58,6 +88,14 @@ func runCodescan(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	sinks, err := parseOutputs(codescanOutputs, codescanFormat)
+	if err != nil {
+		return err
+	}
+	if err := checkGroupBy(codescanGroupBy); err != nil {
+		return err
+	}
+
 	// Verify path exists
 	info, err := os.Stat(path)
 	if err != nil {
@@ -67,208 +105,98 @@ func runCodescan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path must be a directory: %s", path)
 	}
 
-	// Banner
-	purple.Println("\n  greenlight codescan — find rejection risks in your code.")
-	fmt.Printf("  Scanning: %s\n", path)
-	fmt.Printf("  Format:   %s\n\n", codescanFormat)
-
-	// Run scan
-	start := time.Now()
-	scanner := codescan.NewScanner(path, verbose)
-	findings, err := scanner.Scan()
+	swiftBackend, err := swiftsyntax.NewBackend(codescanAST)
 	if err != nil {
-		return fmt.Errorf("scan failed: %w", err)
-	}
-	elapsed := time.Since(start)
-
-	// Sort: critical first, then warn, then info
-	sort.Slice(findings, func(i, j int) bool {
-		if findings[i].Severity != findings[j].Severity {
-			return findings[i].Severity > findings[j].Severity
-		}
-		return findings[i].File < findings[j].File
-	})
-
-	// Output
-	var output *os.File
-	if codescanOutput != "" {
-		output, err = os.Create(codescanOutput)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
-		}
-		defer output.Close()
-	} else {
-		output = os.Stdout
+		return err
 	}
-
-	switch strings.ToLower(codescanFormat) {
-	case "json":
-		return writeCodescanJSON(output, findings, elapsed)
-	default:
-		return writeCodescanTerminal(output, findings, elapsed)
+	paths, err := scanPaths(path, codescanInclude, codescanExclude)
+	if err != nil {
+		return err
 	}
-}
-
-func writeCodescanTerminal(w *os.File, findings []codescan.Finding, elapsed time.Duration) error {
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen, color.Bold)
 
-	if len(findings) == 0 {
-		green.Fprintln(w, "  No issues found!")
-		fmt.Fprintln(w)
-		printCodescanFooter(w, 0, 0, 0, elapsed)
-		return nil
+	// Banner
+	out := bannerWriter(sinks)
+	purple.Fprintln(out, "\n  "+i18n.T("greenlight codescan — find rejection risks in your code."))
+	fmt.Fprintf(out, "  Scanning: %s\n", path)
+	fmt.Fprintf(out, "  Format:   %s\n", describeOutputs(sinks))
+	if swiftBackend != nil {
+		fmt.Fprintf(out, "  Swift:    %s syntax analysis\n", swiftBackend.Name())
 	}
+	printPaths(out, 10, paths)
+	fmt.Fprintln(out)
 
-	// Group by severity
-	var criticals, warns, infos []codescan.Finding
-	for _, f := range findings {
-		switch f.Severity {
-		case codescan.SeverityCritical:
-			criticals = append(criticals, f)
-		case codescan.SeverityWarn:
-			warns = append(warns, f)
-		case codescan.SeverityInfo:
-			infos = append(infos, f)
-		}
+	overrides, err := loadRuleOverrides(path)
+	if err != nil {
+		return err
 	}
 
-	if len(criticals) > 0 {
-		red.Fprintln(w, "  CRITICAL — Will be rejected")
-		fmt.Fprintln(w)
-		for _, f := range criticals {
-			printCodescanFinding(w, f)
-		}
+	// Run scan
+	start := time.Now()
+	scanner := codescan.NewScanner(path)
+	scanner.SetSwiftBackend(swiftBackend)
+	scanner.SetMaxFileSize(int64(codescanMaxMB) << 20)
+	scanner.SetMmap(codescanMmap)
+	scanner.SetPaths(paths)
+	findings, err := scanner.Scan(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("scan failed: %w", err)
 	}
+	findings = codescan.ApplyOverrides(findings, overrides)
+	elapsed := time.Since(start)
 
-	if len(warns) > 0 {
-		yellow.Fprintln(w, "  WARNING — High rejection risk")
-		fmt.Fprintln(w)
-		for _, f := range warns {
-			printCodescanFinding(w, f)
-		}
+	if codescanVerify {
+		codescan.VerifySecrets(cmd.Context(), findings)
 	}
-
-	if len(infos) > 0 {
-		dim.Fprintln(w, "  INFO — Best practices")
-		fmt.Fprintln(w)
-		for _, f := range infos {
-			printCodescanFinding(w, f)
-		}
+	if codescanRedact {
+		codescan.RedactSecrets(findings)
 	}
 
-	printCodescanFooter(w, len(criticals), len(warns), len(infos), elapsed)
-	return nil
-}
+	recordCodescan(path, findings)
 
-func printCodescanFinding(w *os.File, f codescan.Finding) {
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	bold := color.New(color.Bold)
+	// Sort: critical first, then warn, then info
+	codescan.SortFindings(findings)
 
-	// Severity badge
-	switch f.Severity {
-	case codescan.SeverityCritical:
-		red.Fprintf(w, "  [CRITICAL] ")
-	case codescan.SeverityWarn:
-		yellow.Fprintf(w, "  [WARN]     ")
-	case codescan.SeverityInfo:
-		dim.Fprintf(w, "  [INFO]     ")
-	}
+	rep := codescanReport(findings, scanner.Stats(), elapsed)
+	rep.GroupBy = codescanGroupBy
+	rep.Root = path
+	return writeOutputs(sinks, rep.Write)
+}
 
-	// Guideline + title
-	if f.Guideline != "" {
-		bold.Fprintf(w, "§%s ", f.Guideline)
+// codescanReport is the scan's findings in the shared findings renderer.
+func codescanReport(findings []codescan.Finding, stats codescan.ScanStats, elapsed time.Duration) *report.Findings {
+	files := fmt.Sprintf("%d files scanned", stats.Files)
+	if n := stats.Skipped.Total(); n > 0 {
+		files += fmt.Sprintf(", %d skipped (%s)", n, stats.Skipped)
 	}
-	bold.Fprintln(w, f.Title)
-
-	// Location
-	if f.File != "" {
-		loc := f.File
-		if f.Line > 0 {
-			loc = fmt.Sprintf("%s:%d", f.File, f.Line)
-		}
-		dim.Fprintf(w, "             %s\n", loc)
+	unified := make([]scan.Finding, 0, len(findings))
+	for _, f := range findings {
+		unified = append(unified, f.ScanFinding())
 	}
-
-	// Code snippet
-	if f.Code != "" {
-		dim.Fprintf(w, "             > %s\n", truncate(f.Code, 80))
+	return &report.Findings{
+		Command:  "codescan",
+		Findings: unified,
+		Stats:    []string{files},
+		Elapsed:  elapsed,
+		JSON:     codescanJSON(findings, stats, elapsed),
 	}
-
-	// Detail
-	fmt.Fprintf(w, "             %s\n", f.Detail)
-
-	// Fix
-	if f.Fix != "" {
-		green.Fprintf(w, "             Fix: ")
-		fmt.Fprintln(w, f.Fix)
-	}
-
-	fmt.Fprintln(w)
 }
 
-func printCodescanFooter(w *os.File, criticals, warns, infos int, elapsed time.Duration) {
-	red := color.New(color.FgRed, color.Bold)
-	green := color.New(color.FgGreen, color.Bold)
-
-	total := criticals + warns + infos
-
-	fmt.Fprintln(w)
-	dim.Fprintln(w, "  ─────────────────────────────────────────────")
-	fmt.Fprintln(w)
-
-	if criticals == 0 {
-		green.Fprint(w, "  GREENLIT")
-		fmt.Fprint(w, " — no critical issues found")
-	} else {
-		red.Fprint(w, "  NOT READY")
-		fmt.Fprintf(w, " — %d critical issue(s) must be fixed", criticals)
-	}
-	fmt.Fprintln(w)
-
-	if total > 0 {
-		fmt.Fprintf(w, "  %d findings: ", total)
-		if criticals > 0 {
-			red.Fprintf(w, "%d critical  ", criticals)
-		}
-		if warns > 0 {
-			color.New(color.FgYellow).Fprintf(w, "%d warn  ", warns)
-		}
-		if infos > 0 {
-			dim.Fprintf(w, "%d info", infos)
-		}
-		fmt.Fprintln(w)
-	}
-
-	dim.Fprintf(w, "  completed in %s\n", elapsed.Round(time.Millisecond))
-
-	// Revyl attribution
-	fmt.Fprintln(w)
-	dim.Fprintln(w, "  ─────────────────────────────────────────────")
-	fmt.Fprintf(w, "  Built by ")
-	purple.Fprint(w, "Revyl")
-	fmt.Fprintln(w, " — the mobile reliability platform")
-	dim.Fprintln(w, "  Catch more than rejections. Catch bugs.")
-	fmt.Fprint(w, "  ")
-	color.New(color.Underline).Fprintln(w, "https://revyl.com")
-	fmt.Fprintln(w)
+func writeCodescanJSON(w io.Writer, findings []codescan.Finding, stats codescan.ScanStats, elapsed time.Duration) error {
+	return codescanReport(findings, stats, elapsed).WriteJSON(w)
 }
 
-func writeCodescanJSON(w *os.File, findings []codescan.Finding, elapsed time.Duration) error {
-	result := struct {
+// codescanJSON is the document 'codescan --format json' writes, which keeps
+// codescan's own fields (verification results) and summary.
+func codescanJSON(findings []codescan.Finding, stats codescan.ScanStats, elapsed time.Duration) any {
+	summary := codescan.ComputeSummary(findings, stats.Files)
+	summary.Skipped = stats.Skipped
+	return struct {
 		Findings []codescan.Finding `json:"findings"`
 		Summary  codescan.Summary   `json:"summary"`
 		Elapsed  string             `json:"elapsed"`
 	}{
 		Findings: findings,
-		Summary:  codescan.ComputeSummary(findings, 0),
+		Summary:  summary,
 		Elapsed:  elapsed.Round(time.Millisecond).String(),
 	}
-
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(result)
 }
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/history"
+	"github.com/RevylAI/greenlight/pkg/severity"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat string
+	diffOutput string
+	diffFailOn string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old-report.json> <new-report.json>",
+	Short: "Show findings added and resolved between two saved reports",
+	Long: `Compare two reports saved with --format json (preflight, codescan or
+scan): findings that are new in the second report, findings that were
+resolved, and how many are unchanged. Findings are matched by fingerprint,
+so code that only moved is not a change.
+
+With --fail-on, diff exits non-zero when the new report adds a finding at or
+above that severity — a gate for "did this change make compliance worse?".
+
+Usage:
+  greenlight diff main.json pr.json
+  greenlight diff main.json pr.json --format markdown --output diff.md
+  greenlight diff main.json pr.json --fail-on warn`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFormat, "format", "terminal", "output format: terminal, markdown, json")
+	diffCmd.Flags().StringVar(&diffOutput, "output", "", "write the diff to file (stdout if omitted)")
+	diffCmd.Flags().StringVar(&diffFailOn, "fail-on", "", "exit non-zero if a new finding is at or above this severity: info, warn, critical")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	var failOn *severity.Level
+	if diffFailOn != "" {
+		level, err := severity.Parse(diffFailOn)
+		if err != nil {
+			return fmt.Errorf("unknown --fail-on severity %q (use info, warn or critical)", diffFailOn)
+		}
+		failOn = &level
+	}
+
+	before, err := readFindingReport(args[0])
+	if err != nil {
+		return err
+	}
+	after, err := readFindingReport(args[1])
+	if err != nil {
+		return err
+	}
+	diff := history.Compare(before, after)
+
+	output := os.Stdout
+	if diffOutput != "" {
+		output, err = os.Create(diffOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer output.Close()
+	}
+
+	switch strings.ToLower(diffFormat) {
+	case "json":
+		enc := json.NewEncoder(output)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+			history.Diff
+		}{args[0], args[1], diff})
+	case "markdown", "md":
+		err = writeFindingDiffMarkdown(output, args[0], args[1], diff)
+	default:
+		purple.Println("\n  greenlight diff")
+		fmt.Printf("  %s → %s\n\n", args[0], args[1])
+		writeFindingDiff(diff)
+	}
+	if err != nil {
+		return err
+	}
+
+	if failOn != nil {
+		n := 0
+		for _, f := range diff.Added {
+			if f.Severity >= *failOn {
+				n++
+			}
+		}
+		if n > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d new finding(s) at or above %s", n, strings.ToLower(diffFailOn))
+		}
+	}
+	return nil
+}
+
+// readFindingReport loads the findings of a report saved with --format json
+// by preflight, codescan or scan. Severities are names; reports from older
+// versions of codescan and scan carry numeric levels, which decode too.
+func readFindingReport(path string) ([]history.Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report struct {
+		Findings []struct {
+			RuleID      string         `json:"rule_id"`
+			Fingerprint string         `json:"fingerprint"`
+			Severity    severity.Level `json:"severity"`
+			Title       string         `json:"title"`
+			File        string         `json:"file"`
+			Line        int            `json:"line"`
+			Code        string         `json:"code"`
+		} `json:"findings"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	if report.Findings == nil {
+		return nil, fmt.Errorf("%s is not a greenlight JSON report (no findings list)", path)
+	}
+
+	findings := make([]history.Finding, 0, len(report.Findings))
+	for _, f := range report.Findings {
+		// Reports written before fingerprints existed still line up.
+		fp := f.Fingerprint
+		if fp == "" {
+			fp = findingid.Fingerprint(f.RuleID, f.File, f.Code, f.Title)
+		}
+		findings = append(findings, history.Finding{
+			RuleID:      f.RuleID,
+			Fingerprint: fp,
+			Severity:    f.Severity,
+			Title:       f.Title,
+			File:        f.File,
+			Line:        f.Line,
+		})
+	}
+	return findings, nil
+}
+
+func writeFindingDiffMarkdown(w *os.File, from, to string, diff history.Diff) error {
+	var b strings.Builder
+	b.WriteString("# greenlight diff\n\n")
+	fmt.Fprintf(&b, "`%s` → `%s`\n\n", from, to)
+	fmt.Fprintf(&b, "**%d new, %d resolved, %d unchanged**\n", len(diff.Added), len(diff.Resolved), len(diff.Unchanged))
+
+	table := func(heading string, findings []history.Finding) {
+		if len(findings) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", heading, len(findings))
+		b.WriteString("| Severity | Finding | Location | ID |\n|---|---|---|---|\n")
+		for _, f := range findings {
+			loc := f.File
+			if f.Line > 0 {
+				loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+			}
+			if loc != "" {
+				loc = "`" + loc + "`"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | `%s` |\n", f.Severity, strings.ReplaceAll(f.Title, "|", `\|`), loc, findingid.Display(f.RuleID, f.Fingerprint))
+		}
+	}
+	table("New findings", diff.Added)
+	table("Resolved", diff.Resolved)
+
+	_, err := w.WriteString(b.String())
+	return err
+}
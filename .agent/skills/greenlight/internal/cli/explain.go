@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	explainReport string
+	explainFormat string
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <finding-id | rule-id | section>",
+	Short: "Print the full guideline behind a finding",
+	Long: `Explain why a finding matters: the full text of the App Store Review
+Guideline it cites, its common violations and a link to the section on
+developer.apple.com.
+
+The argument is a rule ID (as shown in reports and 'greenlight rules
+list') or a guideline section. With --report, the matching findings from a
+saved 'preflight --format json' report are shown too, and the argument can
+be a finding ID ("apple-pay@3f2a91c0", as printed under each finding) or
+fingerprint to explain one specific finding.
+
+Usage:
+  greenlight explain att-timing
+  greenlight explain 5.1.1
+  greenlight explain apple-pay@3f2a91c0 --report preflight.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplain,
+}
+
+// explanation is the JSON form of explain's output.
+type explanation struct {
+	Rule      *ruleEntry            `json:"rule,omitempty"`
+	Findings  []preflight.Finding   `json:"findings,omitempty"`
+	Guideline *guidelines.Guideline `json:"guideline,omitempty"`
+	URL       string                `json:"url,omitempty"`
+}
+
+func init() {
+	explainCmd.Flags().StringVar(&explainReport, "report", "", "saved 'preflight --format json' report to look the finding up in")
+	explainCmd.Flags().StringVar(&explainFormat, "format", "terminal", "output format: terminal, json")
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	db, err := guidelines.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load guidelines: %w", err)
+	}
+
+	var ex explanation
+	for _, e := range allRuleEntries() {
+		if e.ID == id {
+			e := e
+			ex.Rule = &e
+			break
+		}
+	}
+
+	if explainReport != "" {
+		result, err := readPreflightReport(explainReport)
+		if err != nil {
+			return err
+		}
+		for _, f := range result.Findings {
+			if findingid.Matches(id, f.RuleID, f.Fingerprint) {
+				ex.Findings = []preflight.Finding{f}
+				break
+			}
+			if f.RuleID == id || (ex.Rule == nil && f.Guideline == id) {
+				ex.Findings = append(ex.Findings, f)
+			}
+		}
+		if ex.Rule == nil && len(ex.Findings) > 0 {
+			for _, e := range allRuleEntries() {
+				if e.ID == ex.Findings[0].RuleID {
+					e := e
+					ex.Rule = &e
+					break
+				}
+			}
+		}
+		if len(ex.Findings) == 0 {
+			return fmt.Errorf("no finding for '%s' in %s", id, explainReport)
+		}
+	}
+
+	section := id
+	switch {
+	case ex.Rule != nil:
+		section = ex.Rule.Guideline
+	case len(ex.Findings) > 0:
+		section = ex.Findings[0].Guideline
+	}
+	if g := db.Lookup(section); g != nil && section != "" {
+		ex.Guideline = g
+		ex.URL = guidelines.URL(g.Section)
+	}
+	if ex.Rule == nil && ex.Guideline == nil {
+		return fmt.Errorf("'%s' is neither a rule ID nor a guideline section (see 'greenlight rules list')", id)
+	}
+
+	if strings.ToLower(explainFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ex)
+	}
+	writeExplanationTerminal(ex)
+	return nil
+}
+
+func writeExplanationTerminal(ex explanation) {
+	bold := color.New(color.Bold)
+	yellow := color.New(color.FgYellow)
+
+	fmt.Println()
+	if r := ex.Rule; r != nil {
+		fmt.Print("  ")
+		severityBadge(r.Severity)
+		bold.Printf(" %s", r.ID)
+		dim.Printf("  (%s)\n", r.Scanner)
+		bold.Printf("  %s\n", r.Title)
+		fmt.Printf("  %s\n\n", r.Description)
+	}
+
+	for _, f := range ex.Findings {
+		fmt.Print("  ")
+		severityBadge(f.Severity)
+		fmt.Printf(" %s\n", f.Title)
+		if f.File != "" {
+			loc := f.File
+			if f.Line > 0 {
+				loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+			}
+			dim.Printf("             %s\n", loc)
+		}
+		fmt.Printf("             %s\n", f.Detail)
+	}
+	if len(ex.Findings) > 0 {
+		fmt.Println()
+	}
+
+	g := ex.Guideline
+	if g == nil {
+		dim.Println("  This rule doesn't cite a guideline.")
+		fmt.Println()
+		return
+	}
+	purple.Printf("  Guideline %s", g.Section)
+	bold.Printf("  %s\n", g.Title)
+	color.New(color.Underline).Printf("  %s\n\n", ex.URL)
+	for _, line := range wrapText(g.Content, 76) {
+		fmt.Printf("  %s\n", line)
+	}
+
+	if len(g.CommonViolations) > 0 {
+		fmt.Println()
+		yellow.Println("  Common violations:")
+		for _, v := range g.CommonViolations {
+			fmt.Printf("    • %s\n", v)
+		}
+	}
+
+	for _, s := range g.Subsections {
+		fmt.Println()
+		bold.Printf("  %s  %s\n", s.Section, s.Title)
+		for _, line := range wrapText(s.Content, 76) {
+			dim.Printf("  %s\n", line)
+		}
+	}
+	fmt.Println()
+}
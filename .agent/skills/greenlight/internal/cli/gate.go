@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/policy"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// gateErrorCode is gate's exit code when the policy or report can't be read
+// or evaluated, so CI can tell a broken policy from a failing report.
+const gateErrorCode = 2
+
+var (
+	gatePolicy string
+	gateExpr   string
+	gateFormat string
+)
+
+var gateCmd = &cobra.Command{
+	Use:   "gate <report.json>",
+	Short: "Pass or fail a saved report against a policy",
+	Long: `Evaluate a policy over a report saved with --format json (preflight,
+codescan or scan) and exit non-zero when it fails. Policies are CEL
+expressions that are true when the report should fail:
+
+  // fail on any CRITICAL outside tests, or more than 5 privacy WARNs
+  findings.exists(f, f.severity == "CRITICAL" && !f.file.startsWith("Tests/")) ||
+  findings.filter(f, f.severity == "WARN" && f.guideline.startsWith("5.1")).size() > 5
+
+Variables:
+  findings  every finding: severity ("INFO", "WARN", "CRITICAL"), source,
+            rule_id, guideline, title, detail, fix, file, line, fingerprint
+            and any other field in the report
+  summary   total, critical, warns and infos
+  report    the whole JSON document
+
+Usage:
+  greenlight gate --policy policy.cel preflight.json
+  greenlight gate --expr 'summary.critical > 0 || summary.warns > 10' preflight.json
+
+Without --policy or --expr, the policy key of .greenlight.yml in the current
+directory is used, including one inherited through extends.
+
+Exit codes: 0 when the report meets the policy, 1 when it fails the policy,
+and 2 when the policy or the report can't be read or evaluated.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGate,
+}
+
+func init() {
+	gateCmd.Flags().StringVar(&gatePolicy, "policy", "", "file with the CEL policy expression")
+	gateCmd.Flags().StringVar(&gateExpr, "expr", "", "CEL policy expression (instead of --policy)")
+	gateCmd.Flags().StringVar(&gateFormat, "format", "terminal", "output format: terminal, json")
+	rootCmd.AddCommand(gateCmd)
+}
+
+func runGate(cmd *cobra.Command, args []string) error {
+	var (
+		pol          *policy.Policy
+		err          error
+		policySource = gatePolicy
+	)
+	switch {
+	case gatePolicy != "" && gateExpr != "":
+		return fmt.Errorf("use either --policy or --expr, not both")
+	case gatePolicy != "":
+		pol, err = policy.Load(gatePolicy)
+	case gateExpr != "":
+		pol, err = policy.Compile(gateExpr)
+	default:
+		cfg, cerr := config.LoadProject(".")
+		if cerr != nil {
+			cmd.SilenceUsage = true
+			return &exitError{gateErrorCode, cerr}
+		}
+		if cfg.Policy == "" {
+			return fmt.Errorf("a policy is required: --policy <file>, --expr <expression> or policy in .greenlight.yml")
+		}
+		policySource = cfg.Path
+		pol, err = policy.Compile(cfg.Policy)
+	}
+	cmd.SilenceUsage = true
+	if err != nil {
+		return &exitError{gateErrorCode, err}
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return &exitError{gateErrorCode, err}
+	}
+	vars, err := policy.Vars(data)
+	if err != nil {
+		return &exitError{gateErrorCode, fmt.Errorf("failed to parse report %s: %w", args[0], err)}
+	}
+	failed, err := pol.Fails(vars)
+	if err != nil {
+		return &exitError{gateErrorCode, fmt.Errorf("policy error: %w", err)}
+	}
+
+	summary := vars["summary"].(map[string]any)
+	switch strings.ToLower(gateFormat) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(struct {
+			Report  string         `json:"report"`
+			Policy  string         `json:"policy"`
+			Failed  bool           `json:"failed"`
+			Summary map[string]any `json:"summary"`
+		}{args[0], strings.TrimSpace(pol.String()), failed, summary}); err != nil {
+			return err
+		}
+	default:
+		purple.Println("\n  greenlight gate")
+		fmt.Printf("  Report: %s (%d findings: %d critical, %d warn, %d info)\n",
+			args[0], summary["total"], summary["critical"], summary["warns"], summary["infos"])
+		if policySource != "" {
+			fmt.Printf("  Policy: %s\n\n", policySource)
+		} else {
+			fmt.Printf("  Policy: %s\n\n", gateExpr)
+		}
+		if failed {
+			color.New(color.FgRed, color.Bold).Println("  FAILED — the report violates the policy")
+		} else {
+			color.New(color.FgGreen, color.Bold).Println("  PASSED — the report meets the policy")
+		}
+		fmt.Println()
+	}
+
+	if failed {
+		return fmt.Errorf("policy failed")
+	}
+	return nil
+}
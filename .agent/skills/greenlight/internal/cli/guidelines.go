@@ -1,8 +1,12 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/RevylAI/greenlight/internal/guidelines"
@@ -18,10 +22,24 @@ var guidelinesCmd = &cobra.Command{
 var guidelinesSearchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search guidelines by keyword",
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runGuidelinesSearch,
+	Long: `Search guidelines, best match first. Words are stemmed and misspellings
+are matched to close words; --semantic also matches related terms, so
+"delete account" finds the account deletion requirement in 5.1.1(v).
+
+Usage:
+  greenlight guidelines search "privacy"
+  greenlight guidelines search --semantic "delete account"
+  greenlight guidelines search "loot boxes" --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runGuidelinesSearch,
 }
 
+var (
+	guidelinesSearchSemantic bool
+	guidelinesSearchJSON     bool
+	guidelinesSearchLimit    int
+)
+
 var guidelinesShowCmd = &cobra.Command{
 	Use:   "show [section]",
 	Short: "Show a specific guideline section (e.g. '2.1', '5.1.1')",
@@ -35,10 +53,34 @@ var guidelinesListCmd = &cobra.Command{
 	RunE:  runGuidelinesList,
 }
 
+var guidelinesUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Fetch the latest guidelines from Apple",
+	Long: `Download the current App Store Review Guidelines from developer.apple.com
+and save them to ~/.greenlight/guidelines.json, stamped with the date Apple
+last updated them. All guidelines commands (and scan findings) use the
+updated copy from then on instead of the one built into greenlight.
+
+Recent Apple Developer news about guideline changes is saved alongside.
+
+Usage:
+  greenlight guidelines update
+  greenlight guidelines update --file guidelines.html   # parse a saved copy of the page`,
+	RunE: runGuidelinesUpdate,
+}
+
+var guidelinesUpdateFile string
+
 func init() {
+	guidelinesSearchCmd.Flags().BoolVar(&guidelinesSearchSemantic, "semantic", false, "also match related terms (e.g. delete → remove, deactivate)")
+	guidelinesSearchCmd.Flags().BoolVar(&guidelinesSearchJSON, "json", false, "output results as JSON")
+	guidelinesSearchCmd.Flags().IntVar(&guidelinesSearchLimit, "limit", 10, "max results (0 for all)")
+	guidelinesUpdateCmd.Flags().StringVar(&guidelinesUpdateFile, "file", "", "parse a saved copy of the guidelines page instead of downloading it")
+
 	guidelinesCmd.AddCommand(guidelinesSearchCmd)
 	guidelinesCmd.AddCommand(guidelinesShowCmd)
 	guidelinesCmd.AddCommand(guidelinesListCmd)
+	guidelinesCmd.AddCommand(guidelinesUpdateCmd)
 }
 
 func runGuidelinesSearch(cmd *cobra.Command, args []string) error {
@@ -48,20 +90,35 @@ func runGuidelinesSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load guidelines: %w", err)
 	}
 
-	results := db.Search(query)
+	results := db.Search(query, guidelines.SearchOptions{
+		Semantic: guidelinesSearchSemantic,
+		Limit:    guidelinesSearchLimit,
+	})
+
+	if guidelinesSearchJSON {
+		if results == nil {
+			results = []guidelines.Result{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
 
 	purple.Printf("\n  Guidelines matching '%s'\n\n", query)
 
 	if len(results) == 0 {
 		dim.Println("  No matching guidelines found.")
+		if !guidelinesSearchSemantic {
+			dim.Println("  Try --semantic to match related terms.")
+		}
 		return nil
 	}
 
-	for _, g := range results {
+	for _, r := range results {
 		bold := color.New(color.Bold)
-		bold.Printf("  %s  ", g.Section)
-		fmt.Println(g.Title)
-		dim.Printf("  %s\n\n", truncate(g.Content, 120))
+		bold.Printf("  %s  ", r.Section)
+		fmt.Println(r.Title)
+		dim.Printf("  %s\n\n", truncate(r.Content, 120))
 	}
 
 	return nil
@@ -110,6 +167,7 @@ func runGuidelinesList(cmd *cobra.Command, args []string) error {
 	}
 
 	purple.Println("\n  Apple App Store Review Guidelines")
+	dim.Printf("  Version: %s\n\n", db.VersionLabel())
 
 	for _, g := range db.TopLevel() {
 		bold := color.New(color.Bold)
@@ -124,6 +182,70 @@ func runGuidelinesList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runGuidelinesUpdate(cmd *cobra.Command, args []string) error {
+	path, err := guidelines.UpdatedPath()
+	if err != nil {
+		return err
+	}
+
+	var db *guidelines.DB
+	if guidelinesUpdateFile != "" {
+		page, err := os.ReadFile(guidelinesUpdateFile)
+		if err != nil {
+			return err
+		}
+		if db, err = guidelines.Parse(page); err != nil {
+			return err
+		}
+		db.Source = guidelinesUpdateFile
+	} else {
+		fmt.Printf("  Fetching %s...\n", guidelines.GuidelinesURL)
+		if db, err = guidelines.Fetch(&http.Client{Timeout: 30 * time.Second}); err != nil {
+			return err
+		}
+	}
+
+	previous := "built-in"
+	if old, err := guidelines.Load(); err == nil {
+		previous = old.VersionLabel()
+	}
+	if err := db.Save(path); err != nil {
+		return fmt.Errorf("failed to save guidelines: %w", err)
+	}
+	if snapshot, err := guidelines.VersionPath(db.Version); err == nil {
+		db.Save(snapshot)
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	green.Printf("\n  Guidelines updated to %s", db.Version)
+	dim.Printf(" (was %s)\n", previous)
+	fmt.Printf("  %d sections saved to %s\n", countGuidelines(db.Guidelines), path)
+	if previous != db.Version {
+		dim.Printf("  Run 'greenlight guidelines diff --from %s' to see what changed.\n", previous)
+	}
+
+	if len(db.ReleaseNotes) > 0 {
+		fmt.Println()
+		color.New(color.Bold).Println("  Recent guideline changes:")
+		for _, n := range db.ReleaseNotes {
+			fmt.Printf("    %s  %s\n", n.Date, n.Title)
+			if n.URL != "" {
+				dim.Printf("                %s\n", n.URL)
+			}
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func countGuidelines(list []guidelines.Guideline) int {
+	n := len(list)
+	for _, g := range list {
+		n += countGuidelines(g.Subsections)
+	}
+	return n
+}
+
 func truncate(s string, maxLen int) string {
 	s = strings.ReplaceAll(s, "\n", " ")
 	if len(s) <= maxLen {
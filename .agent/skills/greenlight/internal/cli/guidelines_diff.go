@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	guidelinesDiffFrom   string
+	guidelinesDiffTo     string
+	guidelinesDiffFormat string
+)
+
+var guidelinesDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show guideline sections that changed and the checks they affect",
+	Long: `Compare two versions of the App Store Review Guidelines: sections added,
+changed and removed, and which built-in greenlight rules cite a changed
+section — so you know when a policy shift may affect your app.
+
+By default the guidelines built into greenlight are compared with the
+latest copy fetched by 'greenlight guidelines update'. Versions are
+"built-in", "latest", a fetched version date, or a guidelines JSON file.
+
+Usage:
+  greenlight guidelines diff
+  greenlight guidelines diff --from 2025-06-09
+  greenlight guidelines diff --from built-in --to 2025-06-09 --format json`,
+	Args: cobra.NoArgs,
+	RunE: runGuidelinesDiff,
+}
+
+// guidelinesDiff is the JSON form of a diff.
+type guidelinesDiff struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Changes  []guidelines.Change `json:"changes"`
+	Affected []affectedRule      `json:"affected_rules"`
+}
+
+type affectedRule struct {
+	Scanner   string   `json:"scanner"`
+	ID        string   `json:"id"`
+	Guideline string   `json:"guideline"`
+	Sections  []string `json:"changed_sections"`
+}
+
+func init() {
+	guidelinesDiffCmd.Flags().StringVar(&guidelinesDiffFrom, "from", "built-in", "older version")
+	guidelinesDiffCmd.Flags().StringVar(&guidelinesDiffTo, "to", "latest", "newer version")
+	guidelinesDiffCmd.Flags().StringVar(&guidelinesDiffFormat, "format", "terminal", "output format: terminal, json")
+	guidelinesCmd.AddCommand(guidelinesDiffCmd)
+}
+
+func runGuidelinesDiff(cmd *cobra.Command, args []string) error {
+	from, err := guidelines.LoadVersion(guidelinesDiffFrom)
+	if err != nil {
+		return fmt.Errorf("failed to load guidelines %q: %w", guidelinesDiffFrom, err)
+	}
+	to, err := guidelines.LoadVersion(guidelinesDiffTo)
+	if err != nil {
+		return fmt.Errorf("failed to load guidelines %q: %w", guidelinesDiffTo, err)
+	}
+
+	diff := guidelinesDiff{
+		From:    from.VersionLabel(),
+		To:      to.VersionLabel(),
+		Changes: guidelines.Diff(from, to),
+		// Empty rather than null in JSON.
+		Affected: []affectedRule{},
+	}
+	if diff.Changes == nil {
+		diff.Changes = []guidelines.Change{}
+	}
+	for _, e := range allRuleEntries() {
+		var sections []string
+		for _, c := range diff.Changes {
+			if guidelines.Affects(c.Section, e.Guideline) {
+				sections = append(sections, c.Section)
+			}
+		}
+		if len(sections) > 0 {
+			diff.Affected = append(diff.Affected, affectedRule{
+				Scanner:   e.Scanner,
+				ID:        e.ID,
+				Guideline: e.Guideline,
+				Sections:  sections,
+			})
+		}
+	}
+
+	if strings.ToLower(guidelinesDiffFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+	writeGuidelinesDiffTerminal(diff)
+	return nil
+}
+
+func writeGuidelinesDiffTerminal(diff guidelinesDiff) {
+	bold := color.New(color.Bold)
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	red := color.New(color.FgRed)
+
+	purple.Println("\n  greenlight guidelines diff")
+	fmt.Printf("  %s → %s\n\n", diff.From, diff.To)
+
+	if len(diff.Changes) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("  No guideline changes.")
+		if diff.To == "built-in" {
+			dim.Println("  Run 'greenlight guidelines update' to fetch the latest guidelines.")
+		}
+		fmt.Println()
+		return
+	}
+
+	counts := map[string]int{}
+	for _, c := range diff.Changes {
+		counts[c.Kind]++
+		switch c.Kind {
+		case guidelines.Added:
+			green.Printf("  + %-10s", c.Section)
+		case guidelines.Changed:
+			yellow.Printf("  ~ %-10s", c.Section)
+		default:
+			red.Printf("  - %-10s", c.Section)
+		}
+		fmt.Print(c.Title)
+		if c.OldTitle != "" {
+			dim.Printf("  (was: %s)", c.OldTitle)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n  %d added, %d changed, %d removed\n", counts[guidelines.Added], counts[guidelines.Changed], counts[guidelines.Removed])
+
+	fmt.Println()
+	if len(diff.Affected) == 0 {
+		dim.Println("  No built-in rules cite a changed section.")
+		fmt.Println()
+		return
+	}
+	width := 0
+	for _, a := range diff.Affected {
+		width = max(width, len(a.ID))
+	}
+	bold.Printf("  Rules citing changed sections (%d)\n", len(diff.Affected))
+	for _, a := range diff.Affected {
+		fmt.Printf("    %-*s", width, a.ID)
+		dim.Printf(" §%-8s %s", a.Guideline, a.Scanner)
+		fmt.Printf("  ← %s\n", strings.Join(a.Sections, ", "))
+	}
+	fmt.Println()
+	dim.Println("  Review these rules' findings against the new guideline text.")
+	fmt.Println()
+}
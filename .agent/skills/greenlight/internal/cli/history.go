@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/checks"
+	"github.com/RevylAI/greenlight/internal/history"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/ipa"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/RevylAI/greenlight/pkg/severity"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	noHistory      bool
+	historyAppID   string
+	historyAll     bool
+	historyCommand string
+	historyLimit   int
+	historyFormat  string
+	compareProject string
+	compareFormat  string
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [path]",
+	Short: "Show pass/fail and finding trends of past runs",
+	Long: `Every preflight, codescan, ipa and scan run is recorded (disable with
+--no-history). Runs are kept in the project's .greenlight/history when the
+project has a .greenlight directory — commit it to share history with the
+team — and in ~/.greenlight/history otherwise.
+
+history lists the runs for a project (or, with --app-id, the App Store
+Connect scans of an app) with finding counts, the change from the previous
+run and a trend line.
+
+Usage:
+  greenlight history .
+  greenlight history --app-id 6758967212
+  greenlight history --all --limit 50`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <run-a> <run-b>",
+	Short: "Show findings added and resolved between two recorded runs",
+	Long: `Compare two runs from 'greenlight history': findings that appeared
+(regressions), findings that were resolved, and how many are unchanged.
+Findings are matched by fingerprint, so moved code is not a change.
+
+Runs are given by ID (or a unique prefix), "latest" or "previous".
+
+Usage:
+  greenlight compare previous latest
+  greenlight compare 20250601-0912 latest --project ./my-app`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCompare,
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "don't record this run in the scan history")
+
+	historyCmd.Flags().StringVar(&historyAppID, "app-id", "", "show App Store Connect scans of this app")
+	historyCmd.Flags().BoolVar(&historyAll, "all", false, "show runs of every project and app")
+	historyCmd.Flags().StringVar(&historyCommand, "command", "", "only runs of this command: preflight, codescan, ipa, scan")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 20, "max runs to show")
+	historyCmd.Flags().StringVar(&historyFormat, "format", "terminal", "output format: terminal, json")
+	compareCmd.Flags().StringVar(&compareProject, "project", ".", "project whose history the runs are in")
+	compareCmd.Flags().StringVar(&compareFormat, "format", "terminal", "output format: terminal, json")
+
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(compareCmd)
+}
+
+// recordRun saves a finished run to the history. Failures are logged and
+// otherwise ignored; history must never break a scan.
+func recordRun(command, target, projectPath string, passed bool, findings []history.Finding) {
+	recordTelemetry(command, passed, findings)
+	if noHistory {
+		return
+	}
+	dir, err := history.Dir(projectPath)
+	if err == nil {
+		err = history.Record(dir, &history.Run{
+			Command:  command,
+			Target:   historyTarget(target),
+			Passed:   passed,
+			Findings: findings,
+		})
+	}
+	if err != nil {
+		slog.Info("failed to record history", "command", command, "error", err)
+	}
+}
+
+func recordPreflight(path string, result *preflight.Result) {
+	var recorded []history.Finding
+	for _, f := range result.Findings {
+		recorded = append(recorded, history.Finding{RuleID: f.RuleID, Fingerprint: f.Fingerprint, Severity: f.Severity, Title: f.Title, File: f.File, Line: f.Line})
+	}
+	recordRun("preflight", path, path, result.Summary.Passed, recorded)
+}
+
+func recordCodescan(path string, findings []codescan.Finding) {
+	var recorded []history.Finding
+	passed := true
+	for _, f := range findings {
+		recorded = append(recorded, history.Finding{RuleID: f.RuleID, Fingerprint: f.Fingerprint, Severity: f.Severity, Title: f.Title, File: f.File, Line: f.Line})
+		passed = passed && f.Severity != codescan.SeverityCritical
+	}
+	recordRun("codescan", path, path, passed, recorded)
+}
+
+func recordIPA(ipaPath string, result *ipa.InspectResult) {
+	var recorded []history.Finding
+	passed := true
+	for _, f := range result.Findings {
+		recorded = append(recorded, history.Finding{RuleID: f.RuleID, Fingerprint: f.Fingerprint, Severity: f.Severity, Title: f.Title})
+		passed = passed && f.Severity != severity.Critical
+	}
+	recordRun("ipa", ipaPath, ".", passed, recorded)
+}
+
+func recordScan(appID, project string, results *checks.Results) {
+	var recorded []history.Finding
+	for _, f := range results.Findings {
+		recorded = append(recorded, history.Finding{RuleID: f.RuleID, Fingerprint: f.Fingerprint, Severity: f.Severity, Title: f.Title})
+	}
+	recordRun("scan", appID, project, results.Summary.Passed, recorded)
+}
+
+// historyTarget makes paths absolute so runs from different working
+// directories line up; app IDs are kept as they are.
+func historyTarget(target string) string {
+	if _, err := os.Stat(target); err == nil {
+		if abs, err := filepath.Abs(target); err == nil {
+			return abs
+		}
+	}
+	return target
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	dir, err := history.Dir(path)
+	if err != nil {
+		return err
+	}
+	runs, err := history.List(dir)
+	if err != nil {
+		return err
+	}
+
+	target := historyTarget(path)
+	if historyAppID != "" {
+		target = appIDOrCached(historyAppID)
+	}
+	var shown []history.Run
+	for _, r := range runs {
+		if historyCommand != "" && r.Command != historyCommand {
+			continue
+		}
+		if historyAll || r.Target == target || (historyAppID == "" && strings.HasPrefix(r.Target, target+string(filepath.Separator))) {
+			shown = append(shown, r)
+		}
+	}
+	if historyLimit > 0 && len(shown) > historyLimit {
+		shown = shown[len(shown)-historyLimit:]
+	}
+
+	if strings.ToLower(historyFormat) == "json" {
+		if shown == nil {
+			shown = []history.Run{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(shown)
+	}
+
+	red := color.New(color.FgRed, color.Bold)
+	green := color.New(color.FgGreen, color.Bold)
+	bold := color.New(color.Bold)
+
+	purple.Println("\n  greenlight history")
+	if historyAll {
+		fmt.Printf("  All runs in %s\n\n", dir)
+	} else {
+		fmt.Printf("  %s\n\n", target)
+	}
+	if len(shown) == 0 {
+		dim.Println("  No recorded runs yet — run 'greenlight preflight' first.")
+		fmt.Println()
+		return nil
+	}
+
+	bold.Printf("  %-28s %-10s %-10s %5s %5s %5s  %s\n", "RUN", "COMMAND", "RESULT", "CRIT", "WARN", "INFO", "CHANGE")
+	last := map[string]*history.Run{} // previous run per command+target
+	for i := range shown {
+		r := &shown[i]
+		fmt.Printf("  %-28s %-10s ", r.ID, r.Command)
+		if r.Passed {
+			green.Printf("%-10s", "GREENLIT")
+		} else {
+			red.Printf("%-10s", "NOT READY")
+		}
+		fmt.Printf(" %5d %5d %5d  ", r.Critical, r.Warns, r.Infos)
+		key := r.Command + "\x00" + r.Target
+		if prev := last[key]; prev != nil {
+			d := history.Compare(prev.Findings, r.Findings)
+			switch {
+			case len(d.Added) > 0 && len(d.Resolved) > 0:
+				color.New(color.FgYellow).Printf("+%d / -%d", len(d.Added), len(d.Resolved))
+			case len(d.Added) > 0:
+				red.Printf("+%d new", len(d.Added))
+			case len(d.Resolved) > 0:
+				green.Printf("-%d fixed", len(d.Resolved))
+			default:
+				dim.Print("no change")
+			}
+		}
+		if historyAll {
+			dim.Printf("  %s", r.Target)
+		}
+		fmt.Println()
+		last[key] = r
+	}
+
+	// Trend of the runs like the latest one (same command and target).
+	latest := shown[len(shown)-1]
+	var totals []int
+	for _, r := range shown {
+		if r.Command == latest.Command && r.Target == latest.Target {
+			totals = append(totals, r.Total())
+		}
+	}
+	if len(totals) > 1 {
+		fmt.Println()
+		fmt.Printf("  %s trend: %s  (%d → %d findings)\n", latest.Command, sparkline(totals), totals[0], totals[len(totals)-1])
+	}
+	fmt.Println()
+	dim.Println("  Run 'greenlight compare previous latest' to see what changed.")
+	fmt.Println()
+	return nil
+}
+
+// sparkline renders values as a row of block characters.
+func sparkline(values []int) string {
+	blocks := []rune("▁▂▃▄▅▆▇█")
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = (v - lo) * (len(blocks) - 1) / (hi - lo)
+		}
+		b.WriteRune(blocks[i])
+	}
+	return b.String()
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	dir, err := history.Dir(compareProject)
+	if err != nil {
+		return err
+	}
+	runs, err := history.List(dir)
+	if err != nil {
+		return err
+	}
+	a, err := history.Find(runs, args[0])
+	if err != nil {
+		return err
+	}
+	b, err := history.Find(runs, args[1])
+	if err != nil {
+		return err
+	}
+	diff := history.Compare(a.Findings, b.Findings)
+
+	if strings.ToLower(compareFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+			history.Diff
+		}{a.ID, b.ID, diff})
+	}
+
+	purple.Println("\n  greenlight compare")
+	fmt.Printf("  %s → %s\n", a.ID, b.ID)
+	if a.Target != b.Target {
+		color.New(color.FgYellow).Printf("  Note: the runs are of different targets (%s, %s)\n", a.Target, b.Target)
+	}
+	fmt.Println()
+	writeFindingDiff(diff)
+	return nil
+}
+
+// writeFindingDiff prints added and resolved findings and a summary line.
+func writeFindingDiff(diff history.Diff) {
+	red := color.New(color.FgRed, color.Bold)
+	green := color.New(color.FgGreen, color.Bold)
+
+	if len(diff.Added) > 0 {
+		red.Printf("  New findings (%d)\n", len(diff.Added))
+		for _, f := range diff.Added {
+			printDiffFinding("+", f)
+		}
+		fmt.Println()
+	}
+	if len(diff.Resolved) > 0 {
+		green.Printf("  Resolved (%d)\n", len(diff.Resolved))
+		for _, f := range diff.Resolved {
+			printDiffFinding("-", f)
+		}
+		fmt.Println()
+	}
+
+	switch {
+	case len(diff.Added) == 0 && len(diff.Resolved) == 0:
+		dim.Printf("  No changes — %d finding(s) in both runs.\n", len(diff.Unchanged))
+	default:
+		fmt.Printf("  %d new, %d resolved, %d unchanged\n", len(diff.Added), len(diff.Resolved), len(diff.Unchanged))
+	}
+	fmt.Println()
+}
+
+func printDiffFinding(sign string, f history.Finding) {
+	fmt.Printf("    %s ", sign)
+	severityBadge(f.Severity)
+	fmt.Printf(" %s\n", f.Title)
+	loc := f.RuleID
+	if f.File != "" {
+		loc = f.File
+		if f.Line > 0 {
+			loc += fmt.Sprintf(":%d", f.Line)
+		}
+		loc += "  " + f.RuleID
+	}
+	dim.Printf("                 %s\n", loc)
+}
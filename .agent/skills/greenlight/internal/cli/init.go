@@ -0,0 +1,337 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	initYes    bool
+	initHook   bool
+	initCI     bool
+	initNoScan bool
+	initForce  bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [path]",
+	Short: "Set up greenlight in a project",
+	Long: `Detect the project type (Expo, React Native, native iOS or Flutter),
+write a .greenlight.yml with scanner defaults for it, optionally install a
+git pre-commit hook and a GitHub Actions workflow, and run a first
+preflight.
+
+Without --yes, init asks before installing the hook and the workflow.
+
+Usage:
+  greenlight init
+  greenlight init ./my-app --hook --ci
+  greenlight init --yes --no-scan`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "don't ask; install only what --hook and --ci request")
+	initCmd.Flags().BoolVar(&initHook, "hook", false, "install a git pre-commit hook that blocks commits with CRITICAL findings")
+	initCmd.Flags().BoolVar(&initCI, "ci", false, "write a GitHub Actions workflow that runs preflight on pull requests")
+	initCmd.Flags().BoolVar(&initNoScan, "no-scan", false, "don't run preflight after setting up")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite an existing .greenlight.yml")
+	rootCmd.AddCommand(initCmd)
+}
+
+// projectType is what init detected a project to be.
+type projectType struct {
+	Name string // "expo", "react-native", "ios", "flutter"
+	Desc string
+	// Xcode is false for projects without a native iOS project yet
+	// (managed Expo before prebuild).
+	Xcode bool
+}
+
+// detectProjectType guesses the kind of app in root. ok is false when root
+// looks like none of them.
+func detectProjectType(root string) (projectType, bool) {
+	exists := func(rel string) bool {
+		_, err := os.Stat(filepath.Join(root, rel))
+		return err == nil
+	}
+	xcode := hasXcodeProject(root)
+
+	if data, err := os.ReadFile(filepath.Join(root, "pubspec.yaml")); err == nil && strings.Contains(string(data), "flutter:") {
+		return projectType{"flutter", "Flutter", xcode}, true
+	}
+
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "package.json")); err == nil && json.Unmarshal(data, &pkg) == nil {
+		dep := func(name string) bool {
+			_, a := pkg.Dependencies[name]
+			_, b := pkg.DevDependencies[name]
+			return a || b
+		}
+		switch {
+		case dep("expo") && exists("ios"):
+			return projectType{"expo", "Expo (prebuild, with ios/)", xcode}, true
+		case dep("expo"):
+			return projectType{"expo", "Expo (managed)", false}, true
+		case dep("react-native"):
+			return projectType{"react-native", "React Native", xcode}, true
+		}
+	}
+	if exists("app.json") && exists("node_modules/expo") {
+		return projectType{"expo", "Expo (managed)", xcode}, true
+	}
+	if xcode || exists("Package.swift") {
+		return projectType{"ios", "Native iOS", xcode}, true
+	}
+	return projectType{}, false
+}
+
+// hasXcodeProject reports whether root or a directory one level below it
+// holds an .xcodeproj.
+func hasXcodeProject(root string) bool {
+	for _, pattern := range []string{"*.xcodeproj", "*/*.xcodeproj"} {
+		if matches, _ := filepath.Glob(filepath.Join(root, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// projectConfigTemplate is the .greenlight.yml init writes for pt.
+func projectConfigTemplate(pt projectType) string {
+	var b strings.Builder
+	b.WriteString("# greenlight project config — https://github.com/RevylAI/greenlight\n")
+	if pt.Desc != "" {
+		fmt.Fprintf(&b, "# Project type: %s\n", pt.Desc)
+	}
+	b.WriteString("\n# Scanners preflight runs. Set one to false to skip it.\nscanners:\n")
+	b.WriteString("  codescan: true\n  metadata: true\n  privacy: true\n")
+	switch {
+	case pt.Xcode:
+		b.WriteString("  xcode: true\n")
+	case pt.Name == "expo":
+		b.WriteString("  xcode: false  # no ios/ directory until `npx expo prebuild`\n")
+	default:
+		b.WriteString("  xcode: false  # no .xcodeproj found\n")
+	}
+	b.WriteString("\n# Per-rule settings: off, info, warn or critical.\n")
+	b.WriteString("# Rule IDs are listed by 'greenlight rules list'.\nrules:\n")
+	switch pt.Name {
+	case "flutter":
+		b.WriteString("  # Dart code is not scanned; native code under ios/ is.\n")
+	}
+	b.WriteString("  # hardcoded-secrets: critical\n  # missing-att: warn\n")
+	return b.String()
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if info, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("path must be a directory: %s", path)
+	}
+
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+	interactive := !initYes && term.IsTerminal(int(os.Stdin.Fd()))
+	reader := bufio.NewReader(os.Stdin)
+
+	purple.Println("\n  greenlight init")
+	fmt.Printf("  Project: %s\n", path)
+
+	pt, ok := detectProjectType(path)
+	if ok {
+		fmt.Printf("  Type:    %s\n\n", pt.Desc)
+	} else {
+		yellow.Println("  Type:    unknown (no Expo, React Native, Flutter or Xcode project found)")
+		fmt.Println()
+	}
+
+	// .greenlight.yml
+	cfgPath := filepath.Join(path, config.ProjectFileNames[0])
+	existing, err := config.LoadProject(path)
+	if err != nil && !initForce {
+		return err
+	}
+	switch {
+	case existing != nil && existing.Path != "" && !initForce:
+		dim.Printf("  Kept existing %s (use --force to replace it)\n", filepath.Base(existing.Path))
+	default:
+		if err := os.WriteFile(cfgPath, []byte(projectConfigTemplate(pt)), 0644); err != nil {
+			return err
+		}
+		green.Print("  ✓ ")
+		fmt.Printf("Wrote %s\n", cfgPath)
+	}
+
+	// Git hook and CI workflow live at the repository root.
+	top, rel := gitRoot(path)
+
+	if initHook || (interactive && confirm(reader, "Install a git pre-commit hook that blocks commits with CRITICAL findings?")) {
+		if top == "" {
+			yellow.Println("  ! Not a git repository — skipped the pre-commit hook")
+		} else if hook, err := installPreCommitHook(top, rel); err != nil {
+			yellow.Printf("  ! Pre-commit hook not installed: %v\n", err)
+		} else {
+			green.Print("  ✓ ")
+			fmt.Printf("Installed %s\n", hook)
+		}
+	}
+
+	if initCI || (interactive && confirm(reader, "Add a GitHub Actions workflow that runs preflight on pull requests?")) {
+		root := top
+		if root == "" {
+			root, rel = path, "."
+		}
+		if wf, err := writeCIWorkflow(root, rel); err != nil {
+			yellow.Printf("  ! Workflow not written: %v\n", err)
+		} else {
+			green.Print("  ✓ ")
+			fmt.Printf("Wrote %s\n", wf)
+		}
+	}
+	fmt.Println()
+
+	if initNoScan {
+		fmt.Printf("  Run 'greenlight preflight %s' to scan the project.\n\n", path)
+		return nil
+	}
+
+	fmt.Println("  Running a first preflight...")
+	fmt.Println()
+	start := time.Now()
+	result, err := preflight.Run(cmd.Context(), path, "", preflight.BuildSelection{})
+	if err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+	result.Elapsed = time.Since(start)
+	recordPreflight(path, result)
+	return writePreflightTerminal(os.Stdout, result)
+}
+
+// confirm asks a yes/no question; anything but y or yes is no.
+func confirm(reader *bufio.Reader, question string) bool {
+	fmt.Printf("  %s [y/N] ", question)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// gitRoot returns the top level of the git repository containing path and
+// path relative to it (with forward slashes), or "" when path is not in a
+// repository.
+func gitRoot(path string) (top, rel string) {
+	out, err := exec.Command("git", "-C", path, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", ""
+	}
+	top = strings.TrimSpace(string(out))
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", ""
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err = filepath.Rel(top, abs)
+	if err != nil {
+		return "", ""
+	}
+	return top, filepath.ToSlash(rel)
+}
+
+// hookMarker identifies hooks written by init, which init may replace.
+const hookMarker = "# greenlight pre-commit hook"
+
+// installPreCommitHook writes a pre-commit hook to the repository at top
+// that preflights the project at rel and fails on CRITICAL findings. An
+// existing hook not written by greenlight is left alone.
+func installPreCommitHook(top, rel string) (string, error) {
+	out, err := exec.Command("git", "-C", top, "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(top, dir)
+	}
+	hook := filepath.Join(dir, "pre-commit")
+	if data, err := os.ReadFile(hook); err == nil && !strings.Contains(string(data), hookMarker) {
+		return "", fmt.Errorf("%s already exists; add 'greenlight preflight' to it by hand", hook)
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+%s (installed by 'greenlight init')
+# Blocks the commit when preflight finds CRITICAL issues.
+# Skip once with: git commit --no-verify
+cd "$(git rev-parse --show-toplevel)/%s" || exit 1
+report=$(mktemp)
+trap 'rm -f "$report"' EXIT
+greenlight preflight . --no-history --format json --output "$report" >/dev/null || exit 1
+greenlight gate --expr 'summary.critical > 0' "$report"
+`, hookMarker, rel)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return hook, os.WriteFile(hook, []byte(script), 0755)
+}
+
+// writeCIWorkflow writes .github/workflows/greenlight.yml under root for
+// the project at rel. An existing workflow is left alone.
+func writeCIWorkflow(root, rel string) (string, error) {
+	path := filepath.Join(root, ".github", "workflows", "greenlight.yml")
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("%s already exists", path)
+	}
+	workflow := fmt.Sprintf(`name: greenlight
+
+on:
+  pull_request:
+  push:
+    branches: [main]
+
+jobs:
+  preflight:
+    runs-on: ubuntu-latest
+    defaults:
+      run:
+        working-directory: %s
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+      - run: go install github.com/RevylAI/greenlight/cmd/greenlight@latest
+      - run: greenlight preflight . --format json --output greenlight-report.json
+      - run: greenlight gate --expr 'summary.critical > 0' greenlight-report.json
+      - uses: actions/upload-artifact@v4
+        if: always()
+        with:
+          name: greenlight-report
+          path: %s
+`, rel, filepath.ToSlash(filepath.Join(rel, "greenlight-report.json")))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, []byte(workflow), 0644)
+}
@@ -1,19 +1,26 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
-	"github.com/fatih/color"
-	"github.com/RevylAI/greenlight/internal/ipa"
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/internal/triage"
+	"github.com/RevylAI/greenlight/pkg/ipa"
+	"github.com/RevylAI/greenlight/pkg/scan"
 	"github.com/spf13/cobra"
 )
 
 var ipaFormat string
 
 var ipaCmd = &cobra.Command{
-	Use:   "ipa <path-to-ipa>",
+	Use:   "ipa <path-to-ipa|xcarchive>",
 	Short: "Inspect an IPA binary for App Store compliance issues",
 	Long: `Inspect an IPA file for common issues that cause App Store rejection.
 
@@ -26,6 +33,11 @@ Checks:
   • App size vs cellular download limit
   • Embedded framework privacy manifests
   • Purpose string quality (empty, vague)
+  • React Native bundle (main.jsbundle): dev builds, endpoints, secrets
+
+Given an .xcarchive instead, checks crash-symbolication readiness: dSYMs
+for the app and every embedded framework, matching binary UUIDs, and
+leftover bitcode.
 
 No App Store Connect account needed — works entirely offline.`,
 	Args: cobra.ExactArgs(1),
@@ -33,7 +45,7 @@ No App Store Connect account needed — works entirely offline.`,
 }
 
 func init() {
-	ipaCmd.Flags().StringVar(&ipaFormat, "format", "terminal", "output format: terminal, json")
+	ipaCmd.Flags().StringVar(&ipaFormat, "format", "terminal", formatFlagUsage)
 	rootCmd.AddCommand(ipaCmd)
 }
 
@@ -44,147 +56,71 @@ func runIPA(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("IPA file not found: %s", ipaPath)
 	}
 
-	purple.Println("\n  greenlight ipa — inspect your binary before submission.")
-	fmt.Printf("  IPA: %s\n\n", ipaPath)
+	terminal := report.IsTerminal(ipaFormat)
+	if terminal {
+		purple.Println("\n  " + i18n.T("greenlight ipa — inspect your binary before submission."))
+		fmt.Printf("  IPA: %s\n\n", ipaPath)
+	}
 
 	start := time.Now()
-	result, err := ipa.Inspect(ipaPath)
+	result, err := inspectIPA(cmd.Context(), ipaPath)
 	if err != nil {
 		return fmt.Errorf("inspection failed: %w", err)
 	}
 	elapsed := time.Since(start)
+	recordIPA(ipaPath, result)
 
-	if result.AppName != "" {
-		fmt.Printf("  App:  %s\n", result.AppName)
-	}
 	sizeMB := float64(result.Size) / (1024 * 1024)
-	fmt.Printf("  Size: %.1fMB\n\n", sizeMB)
-
-	if len(result.Findings) == 0 {
-		color.New(color.FgGreen, color.Bold).Fprintln(os.Stdout, "  No issues found!")
-		fmt.Println()
-		printIPAFooter(0, 0, 0, elapsed)
-		return nil
-	}
-
-	// Group findings
-	var criticals, warns, infos []ipa.Finding
-	for _, f := range result.Findings {
-		switch f.Severity {
-		case "CRITICAL":
-			criticals = append(criticals, f)
-		case "WARN":
-			warns = append(warns, f)
-		case "INFO":
-			infos = append(infos, f)
+	if terminal {
+		if result.AppName != "" {
+			fmt.Printf("  App:  %s\n", result.AppName)
 		}
-	}
-
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	bold := color.New(color.Bold)
-
-	if len(criticals) > 0 {
-		red.Println("  CRITICAL — Will be rejected")
-		fmt.Println()
-		for _, f := range criticals {
-			red.Fprint(os.Stdout, "  [CRITICAL] ")
-			if f.Guideline != "" {
-				bold.Fprintf(os.Stdout, "§%s ", f.Guideline)
+		fmt.Printf("  Size: %.1fMB\n", sizeMB)
+		for i, item := range result.Payload {
+			if i == 5 {
+				dim.Printf("        … %d more\n", len(result.Payload)-i)
+				break
 			}
-			bold.Fprintln(os.Stdout, f.Title)
-			fmt.Printf("             %s\n", f.Detail)
-			if f.Fix != "" {
-				green.Fprint(os.Stdout, "             Fix: ")
-				fmt.Println(f.Fix)
-			}
-			fmt.Println()
+			dim.Printf("        %-32s %8.1fMB  %s\n", item.Name, float64(item.Bytes)/(1024*1024), item.Kind)
 		}
-	}
-
-	if len(warns) > 0 {
-		yellow.Println("  WARNING — High rejection risk")
 		fmt.Println()
-		for _, f := range warns {
-			yellow.Fprint(os.Stdout, "  [WARN]     ")
-			if f.Guideline != "" {
-				bold.Fprintf(os.Stdout, "§%s ", f.Guideline)
-			}
-			bold.Fprintln(os.Stdout, f.Title)
-			fmt.Printf("             %s\n", f.Detail)
-			if f.Fix != "" {
-				green.Fprint(os.Stdout, "             Fix: ")
-				fmt.Println(f.Fix)
-			}
-			fmt.Println()
-		}
 	}
 
-	if len(infos) > 0 {
-		dim.Println("  INFO — Best practices")
-		fmt.Println()
-		for _, f := range infos {
-			dim.Fprint(os.Stdout, "  [INFO]     ")
-			if f.Guideline != "" {
-				bold.Fprintf(os.Stdout, "§%s ", f.Guideline)
-			}
-			bold.Fprintln(os.Stdout, f.Title)
-			fmt.Printf("             %s\n", f.Detail)
-			if f.Fix != "" {
-				green.Fprint(os.Stdout, "             Fix: ")
-				fmt.Println(f.Fix)
-			}
-			fmt.Println()
-		}
+	header := []report.Field{{Label: "IPA", Value: ipaPath}}
+	if result.AppName != "" {
+		header = append(header, report.Field{Label: "App", Value: result.AppName})
 	}
+	header = append(header, report.Field{Label: "Size", Value: fmt.Sprintf("%.1fMB", sizeMB)})
 
-	printIPAFooter(len(criticals), len(warns), len(infos), elapsed)
-	return nil
+	unified := make([]scan.Finding, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		unified = append(unified, f.ScanFinding())
+	}
+	rep := &report.Findings{
+		Command:  "ipa",
+		Header:   header,
+		Findings: unified,
+		Elapsed:  elapsed,
+		Scope:    "in binary",
+		JSON:     result,
+	}
+	return rep.Write(os.Stdout, ipaFormat)
 }
 
-func printIPAFooter(criticals, warns, infos int, elapsed time.Duration) {
-	red := color.New(color.FgRed, color.Bold)
-	green := color.New(color.FgGreen, color.Bold)
-	total := criticals + warns + infos
-
-	dim.Fprintln(os.Stdout, "  ─────────────────────────────────────────────")
-	fmt.Println()
-
-	if criticals == 0 {
-		green.Fprint(os.Stdout, "  GREENLIT")
-		fmt.Fprint(os.Stdout, " — no critical issues in binary")
-	} else {
-		red.Fprint(os.Stdout, "  NOT READY")
-		fmt.Fprintf(os.Stdout, " — %d critical issue(s) must be fixed", criticals)
+// inspectIPA inspects an IPA and resolves each finding's guideline and ID.
+func inspectIPA(ctx context.Context, ipaPath string) (*ipa.InspectResult, error) {
+	result, err := ipa.Inspect(ctx, ipaPath)
+	if err != nil {
+		return nil, err
 	}
-	fmt.Println()
-
-	if total > 0 {
-		fmt.Fprintf(os.Stdout, "  %d findings: ", total)
-		if criticals > 0 {
-			red.Fprintf(os.Stdout, "%d critical  ", criticals)
-		}
-		if warns > 0 {
-			color.New(color.FgYellow).Fprintf(os.Stdout, "%d warn  ", warns)
-		}
-		if infos > 0 {
-			dim.Fprintf(os.Stdout, "%d info", infos)
-		}
-		fmt.Println()
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		f.GuidelineTitle, f.GuidelineURL = guidelines.Reference(f.Guideline)
+		f.RuleID = findingid.RuleID("ipa", f.RuleID, f.Title)
+		f.Fingerprint = findingid.Fingerprint(f.RuleID, "", "", f.Title)
+		f.Risk = risk.Score(f.Severity, f.Guideline)
+		hint := triage.For("ipa", f.RuleID, "", f.Guideline)
+		f.Effort, f.Owner = string(hint.Effort), string(hint.Owner)
 	}
-
-	dim.Fprintf(os.Stdout, "  completed in %s\n", elapsed.Round(time.Millisecond))
-
-	fmt.Println()
-	dim.Fprintln(os.Stdout, "  ─────────────────────────────────────────────")
-	fmt.Fprint(os.Stdout, "  Built by ")
-	purple.Fprint(os.Stdout, "Revyl")
-	fmt.Fprintln(os.Stdout, " — the mobile reliability platform")
-	dim.Fprintln(os.Stdout, "  Catch more than rejections. Catch bugs.")
-	fmt.Fprint(os.Stdout, "  ")
-	color.New(color.Underline).Fprintln(os.Stdout, "https://revyl.com")
-	fmt.Println()
-
-	return
+	return result, nil
 }
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/RevylAI/greenlight/internal/lsp"
+	"github.com/RevylAI/greenlight/pkg/codescan/swiftsyntax"
+	"github.com/spf13/cobra"
+)
+
+var lspAST string
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server that shows rejection risks as you type",
+	Long: `Serve codescan and privacy findings as Language Server Protocol
+diagnostics over stdin and stdout, for VS Code and any other LSP client.
+
+The workspace is scanned when the editor connects and again on every save.
+Open files are re-checked on each change against the per-file rules
+(secrets, private APIs, placeholder text, ...); rules that correlate several
+files, and the privacy scan, refresh on save. Rule settings in
+.greenlight.yml apply as in codescan.
+
+Configure your editor to start "greenlight lsp" for Swift, Objective-C,
+JavaScript, TypeScript and plist files.`,
+	Args: cobra.NoArgs,
+	RunE: runLSP,
+}
+
+func init() {
+	lspCmd.Flags().StringVar(&lspAST, "swift-ast", "off", "syntax-aware Swift analysis: off, auto, builtin, sourcekitten")
+	rootCmd.AddCommand(lspCmd)
+}
+
+func runLSP(cmd *cobra.Command, args []string) error {
+	swiftBackend, err := swiftsyntax.NewBackend(lspAST)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	srv := lsp.NewServer(os.Stdin, os.Stdout, lsp.Options{Version: appVersion, Swift: swiftBackend})
+	return srv.Run(cmd.Context())
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/internal/termout"
+)
+
+// outputSink is one --output destination: a report format written to a
+// file, or to stdout when path is "-".
+type outputSink struct {
+	format string
+	path   string
+}
+
+func (s outputSink) String() string {
+	if s.path == "-" {
+		return s.format
+	}
+	return s.format + " → " + s.path
+}
+
+// formatFlagUsage documents --format on commands that write findings reports.
+var formatFlagUsage = "output format: " + strings.Join(report.Formats, ", ")
+
+// groupByFlagUsage documents --group-by on commands with file findings.
+const groupByFlagUsage = "group markdown and HTML findings by: severity, owner (the CODEOWNERS owners of each file)"
+
+// checkGroupBy validates --group-by.
+func checkGroupBy(groupBy string) error {
+	switch groupBy {
+	case "severity", "owner":
+		return nil
+	}
+	return fmt.Errorf("invalid --group-by %q (use severity or owner)", groupBy)
+}
+
+// outputFlagUsage documents the repeatable --output flag.
+const outputFlagUsage = "write the report to a file, or format=file for several formats in one run, e.g. json=report.json, junit=report.xml, terminal=- (repeatable; stdout if omitted)"
+
+// parseOutputs turns --output values into sinks. A value is either
+// "format=path" or a bare path written in format (--format); "-" is stdout.
+// Without any --output the report goes to stdout in format.
+func parseOutputs(specs []string, format string) ([]outputSink, error) {
+	if len(specs) == 0 {
+		return []outputSink{{format: format, path: "-"}}, nil
+	}
+	var sinks []outputSink
+	seen := map[string]bool{}
+	for _, spec := range specs {
+		sink := outputSink{format: format, path: spec}
+		if f, path, ok := strings.Cut(spec, "="); ok && isReportFormat(f) {
+			sink = outputSink{format: strings.ToLower(f), path: path}
+		}
+		if sink.path == "" {
+			return nil, fmt.Errorf("--output %q: missing file (use - for stdout)", spec)
+		}
+		if seen[sink.path] {
+			if sink.path == "-" {
+				return nil, fmt.Errorf("--output: only one report can be written to stdout")
+			}
+			return nil, fmt.Errorf("--output: %s is written more than once", sink.path)
+		}
+		seen[sink.path] = true
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// isReportFormat reports whether f names a report format.
+func isReportFormat(f string) bool {
+	f = strings.ToLower(f)
+	return f == "md" || slices.Contains(report.Formats, f)
+}
+
+// bannerWriter is where a command writes its banner and progress: stdout,
+// unless stdout gets a machine-readable report (JSON, JUnit, ...), which
+// has to stay parseable, in which case stderr.
+func bannerWriter(sinks []outputSink) io.Writer {
+	for _, s := range sinks {
+		if s.path == "-" && !report.IsTerminal(s.format) {
+			return os.Stderr
+		}
+	}
+	return os.Stdout
+}
+
+// describeOutputs is the banner's summary of where the report goes.
+func describeOutputs(sinks []outputSink) string {
+	parts := make([]string, len(sinks))
+	for i, s := range sinks {
+		parts[i] = s.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// writeOutputs writes the report to every sink in turn; write renders it in
+// one format. Colors follow each sink (none in files unless forced). Every
+// sink is attempted; the first error is returned.
+func writeOutputs(sinks []outputSink, write func(w io.Writer, format string) error) error {
+	defer termout.Configure(os.Stdout)
+
+	var first error
+	for _, s := range sinks {
+		if err := writeOutput(s, write); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func writeOutput(s outputSink, write func(w io.Writer, format string) error) error {
+	if s.path == "-" {
+		termout.Configure(os.Stdout)
+		return write(os.Stdout, s.format)
+	}
+	f, err := os.Create(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	termout.Configure(f)
+	if err := write(f, s.format); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", s.path, err)
+	}
+	return f.Close()
+}
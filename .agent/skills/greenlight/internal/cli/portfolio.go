@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// runPortfolioScan scans every app named in --app-id, or every app on the
+// account with --all-apps, --parallel at a time, and writes one report
+// covering them all.
+func runPortfolioScan(ctx context.Context, cfg *config.Config, client *asc.Client, sinks []outputSink) error {
+	apps, err := portfolioApps(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(apps) == 0 {
+		return fmt.Errorf("no apps to scan")
+	}
+	parallel := scanParallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	out := bannerWriter(sinks)
+	purple.Fprintln(out, "\n  "+i18n.T("greenlight — know before you submit."))
+	fmt.Fprintf(out, "  Apps:     %d\n", len(apps))
+	fmt.Fprintf(out, "  Tier:     1-%d\n", scanTier)
+	fmt.Fprintf(out, "  Format:   %s\n", describeOutputs(sinks))
+	printSelection(out, 10, scanSelection)
+	fmt.Fprintln(out)
+
+	start := time.Now()
+	results := make([]report.AppResult, len(apps))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, app := range apps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = scanPortfolioApp(ctx, cfg, client, app)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Err == nil {
+			recordScan(r.AppID, "", r.Results)
+		}
+	}
+
+	rep := report.NewPortfolio(results, time.Since(start))
+	if err := writeScanReport(rep, sinks); err != nil {
+		return err
+	}
+	if _, _, errored := rep.Summary(); errored > 0 {
+		return fmt.Errorf("%d of %d app scans failed", errored, len(results))
+	}
+	return nil
+}
+
+// portfolioApps lists the apps to scan: the account's apps with --all-apps,
+// else each comma-separated --app-id value resolved to an app ID.
+func portfolioApps(ctx context.Context, client *asc.Client) ([]asc.App, error) {
+	if scanAllApps {
+		apps, err := client.ListApps(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list apps: %w", err)
+		}
+		return apps, nil
+	}
+
+	var apps []asc.App
+	seen := map[string]bool{}
+	for _, query := range strings.Split(scanAppID, ",") {
+		if strings.TrimSpace(query) == "" {
+			continue
+		}
+		id, err := resolveAppID(ctx, client, query)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			apps = append(apps, asc.App{ID: id})
+		}
+	}
+	return apps, nil
+}
+
+// scanPortfolioApp runs the checks for one app of a portfolio.
+func scanPortfolioApp(ctx context.Context, cfg *config.Config, client *asc.Client, app asc.App) report.AppResult {
+	start := time.Now()
+	if app.Attributes.Name == "" {
+		// Apps given by ID; a missing app is reported by the checks.
+		if found, err := client.GetApp(ctx, app.ID); err == nil {
+			app = *found
+		}
+	}
+	result := report.AppResult{AppID: app.ID, AppName: app.Attributes.Name, BundleID: app.Attributes.BundleID}
+	if result.AppName == "" {
+		result.AppName = app.ID
+	}
+
+	results, err := newScanRunner(cfg, client).Run(ctx, app.ID, "", scanTier)
+	if err != nil {
+		result.Err = err
+	} else {
+		results.AppName = result.AppName
+		result.Results = results
+	}
+	result.Elapsed = time.Since(start)
+	return result
+}
@@ -1,22 +1,33 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"sort"
 	"strings"
 	"time"
 
-	"github.com/fatih/color"
-	"github.com/RevylAI/greenlight/internal/preflight"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/RevylAI/greenlight/pkg/scan"
 	"github.com/spf13/cobra"
 )
 
 var (
-	preflightIPA    string
-	preflightFormat string
-	preflightOutput string
+	preflightIPA        string
+	preflightFormat     string
+	preflightOutputs    []string
+	preflightRedact     bool
+	preflightScheme     string
+	preflightConfig     string
+	preflightOnly       []string
+	preflightSkip       []string
+	preflightGroupBy    string
+	preflightInclude    []string
+	preflightExclude    []string
+	preflightAdvisories bool
+	preflightProbe      bool
 )
 
 var preflightCmd = &cobra.Command{
@@ -29,20 +40,48 @@ Combines:
   • Code scan     — private APIs, hardcoded secrets, missing ATT, etc.
   • Privacy scan  — Required Reason APIs, PrivacyInfo.xcprivacy, tracking SDKs
   • Metadata scan — app.json / Info.plist completeness, icons, version, bundle ID
+  • Xcode project — Release build settings, signing, debug frameworks
+  • Dependencies  — end-of-life OpenSSL; with --advisories, versions with
+                    known vulnerabilities (OSV: GitHub, npm and NVD advisories)
+  • Endpoints     — with --probe-endpoints, the API base URLs in env files
+                    and code: DNS, public reachability, TLS, server errors
   • IPA inspect   — binary analysis (if --ipa is provided)
 
 Usage:
   greenlight preflight .
   greenlight preflight ./my-app --ipa build.ipa
-  greenlight preflight /path/to/project --format json`,
+  greenlight preflight /path/to/project --format json
+  greenlight preflight . --format html --output report.html
+  greenlight preflight . --scheme MyApp --configuration Release
+
+Build settings referenced from Info.plist ($(PRODUCT_BUNDLE_IDENTIFIER),
+$(PRODUCT_NAME), ...) are resolved from the pbxproj and xcconfig files of
+the selected scheme and configuration. Without --scheme/--configuration,
+every Release-like configuration of each app target is checked.
+
+--only and --skip take scanner names, rule IDs or guideline sections,
+comma-separated or repeated (see 'greenlight checks list' and
+'greenlight rules list'):
+  greenlight preflight . --only codescan,privacy
+  greenlight preflight . --skip hardcoded-secrets --only '5.1.*'`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPreflight,
 }
 
 func init() {
-	preflightCmd.Flags().StringVar(&preflightIPA, "ipa", "", "path to .ipa file for binary inspection")
-	preflightCmd.Flags().StringVar(&preflightFormat, "format", "terminal", "output format: terminal, json")
-	preflightCmd.Flags().StringVar(&preflightOutput, "output", "", "write report to file (stdout if omitted)")
+	preflightCmd.Flags().StringVar(&preflightIPA, "ipa", "", "path to .ipa file (or .xcarchive) for binary inspection")
+	preflightCmd.Flags().StringVar(&preflightFormat, "format", "terminal", formatFlagUsage)
+	preflightCmd.Flags().StringArrayVar(&preflightOutputs, "output", nil, outputFlagUsage)
+	preflightCmd.Flags().StringVar(&preflightGroupBy, "group-by", "severity", groupByFlagUsage)
+	preflightCmd.Flags().BoolVar(&preflightRedact, "redact", false, "mask detected secrets in report output")
+	preflightCmd.Flags().StringVar(&preflightScheme, "scheme", "", "Xcode scheme whose archive targets and configuration are checked")
+	preflightCmd.Flags().StringVar(&preflightConfig, "configuration", "", "Xcode build configuration to check (default: the scheme's archive configuration, or all Release-like ones)")
+	preflightCmd.Flags().StringSliceVar(&preflightOnly, "only", nil, "run only these scanners, rules or guideline sections, e.g. codescan or '5.1.*' (repeatable)")
+	preflightCmd.Flags().StringSliceVar(&preflightSkip, "skip", nil, "skip these scanners, rules or guideline sections (repeatable)")
+	preflightCmd.Flags().StringSliceVar(&preflightInclude, "include", nil, includeFlagUsage)
+	preflightCmd.Flags().StringSliceVar(&preflightExclude, "exclude", nil, excludeFlagUsage)
+	preflightCmd.Flags().BoolVar(&preflightAdvisories, "advisories", false, "look up dependency versions in the OSV vulnerability database (sends pod, package and npm names and versions to osv.dev)")
+	preflightCmd.Flags().BoolVar(&preflightProbe, "probe-endpoints", false, "request the API base URLs found in env files and code to check the backend is reachable from the public internet")
 	rootCmd.AddCommand(preflightCmd)
 }
 
@@ -52,6 +91,14 @@ func runPreflight(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	sinks, err := parseOutputs(preflightOutputs, preflightFormat)
+	if err != nil {
+		return err
+	}
+	if err := checkGroupBy(preflightGroupBy); err != nil {
+		return err
+	}
+
 	// Verify project path exists
 	info, err := os.Stat(path)
 	if err != nil {
@@ -68,244 +115,140 @@ func runPreflight(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	selection, err := scan.NewSelection(preflightOnly, preflightSkip)
+	if err != nil {
+		return err
+	}
+	paths, err := scan.NewPaths(preflightInclude, preflightExclude)
+	if err != nil {
+		return err
+	}
+	effective, err := scanPaths(path, preflightInclude, preflightExclude)
+	if err != nil {
+		return err
+	}
+
 	// Banner
-	purple.Println("\n  greenlight preflight — every check, one command, zero uploads.")
-	fmt.Printf("  Project: %s\n", path)
+	out := bannerWriter(sinks)
+	purple.Fprintln(out, "\n  "+i18n.T("greenlight preflight — every check, one command, zero uploads."))
+	fmt.Fprintf(out, "  Project: %s\n", path)
 	if preflightIPA != "" {
-		fmt.Printf("  IPA:     %s\n", preflightIPA)
+		fmt.Fprintf(out, "  IPA:     %s\n", preflightIPA)
 	}
+	if preflightScheme != "" || preflightConfig != "" {
+		build := preflightScheme
+		if preflightConfig != "" {
+			build = strings.TrimPrefix(build+" ("+preflightConfig+")", " ")
+		}
+		fmt.Fprintf(out, "  Build:   %s\n", build)
+	}
+	printSelection(out, 9, selection)
+	printPaths(out, 9, effective)
 
-	scanners := []string{"metadata", "codescan", "privacy"}
-	if preflightIPA != "" {
-		scanners = append(scanners, "ipa")
+	if _, err := loadRuleOverrides(path); err != nil {
+		return err
+	}
+	target := scan.Target{
+		ProjectPath: path,
+		IPAPath:     preflightIPA,
+		Build: preflight.BuildSelection{
+			Scheme:        preflightScheme,
+			Configuration: preflightConfig,
+		},
+		Selection:      selection,
+		Paths:          paths,
+		Advisories:     preflightAdvisories,
+		ProbeEndpoints: preflightProbe,
+	}
+	enabled, err := preflight.Scanners(target)
+	if err != nil {
+		return err
+	}
+	var scanners []string
+	for _, s := range enabled {
+		scanners = append(scanners, s.Name())
 	}
-	fmt.Printf("  Checks:  %s\n\n", strings.Join(scanners, " + "))
+	fmt.Fprintf(out, "  Checks:  %s\n\n", strings.Join(scanners, " + "))
 
 	// Run all checks
 	start := time.Now()
-	result, err := preflight.Run(path, preflightIPA, verbose)
+	result, err := preflight.RunTarget(cmd.Context(), target)
 	if err != nil {
 		return fmt.Errorf("preflight failed: %w", err)
 	}
 	result.Elapsed = time.Since(start)
-
-	// Output
-	var output *os.File
-	if preflightOutput != "" {
-		output, err = os.Create(preflightOutput)
-		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
-		}
-		defer output.Close()
-	} else {
-		output = os.Stdout
+	if preflightRedact {
+		result.RedactSecrets()
 	}
 
-	switch strings.ToLower(preflightFormat) {
-	case "json":
-		return writePreflightJSON(output, result)
-	default:
-		return writePreflightTerminal(output, result)
-	}
+	recordPreflight(path, result)
+
+	rep := preflightReport(result)
+	rep.GroupBy = preflightGroupBy
+	return writeOutputs(sinks, rep.Write)
 }
 
-func writePreflightTerminal(w *os.File, result *preflight.Result) error {
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen, color.Bold)
+// preflightReport is result in the shared findings renderer, with the
+// project's context and a breakdown by scanner.
+func preflightReport(result *preflight.Result) *report.Findings {
+	preflight.SortFindings(result.Findings)
 
-	// Show context
+	var fields []report.Field
 	if result.AppName != "" {
-		fmt.Fprintf(w, "  App:     %s\n", result.AppName)
+		fields = append(fields, report.Field{Label: "App", Value: result.AppName})
 	}
 	if result.BundleID != "" {
-		fmt.Fprintf(w, "  Bundle:  %s\n", result.BundleID)
+		fields = append(fields, report.Field{Label: "Bundle", Value: result.BundleID})
 	}
 	if result.HasPrivacyInfo {
-		color.New(color.FgGreen).Fprint(w, "  ✓ ")
-		fmt.Fprintln(w, "PrivacyInfo.xcprivacy found")
+		fields = append(fields, report.Field{Label: "Privacy", Value: "PrivacyInfo.xcprivacy found"})
 	}
 	if len(result.DetectedAPIs) > 0 {
-		fmt.Fprintf(w, "  APIs:    %s\n", strings.Join(result.DetectedAPIs, ", "))
+		fields = append(fields, report.Field{Label: "APIs", Value: strings.Join(result.DetectedAPIs, ", ")})
 	}
 	if len(result.TrackingSDKs) > 0 {
-		yellow.Fprint(w, "  Tracking: ")
-		fmt.Fprintln(w, strings.Join(result.TrackingSDKs, ", "))
-	}
-	fmt.Fprintln(w)
-
-	if len(result.Findings) == 0 {
-		green.Fprintln(w, "  No issues found!")
-		fmt.Fprintln(w)
-		printPreflightFooter(w, result)
-		return nil
-	}
-
-	// Sort: critical first, then warn, then info
-	sort.Slice(result.Findings, func(i, j int) bool {
-		sevRank := map[string]int{"CRITICAL": 3, "WARN": 2, "INFO": 1}
-		ri, rj := sevRank[result.Findings[i].Severity], sevRank[result.Findings[j].Severity]
-		if ri != rj {
-			return ri > rj
-		}
-		return result.Findings[i].Source < result.Findings[j].Source
-	})
-
-	// Group by severity
-	var criticals, warns, infos []preflight.Finding
-	for _, f := range result.Findings {
-		switch f.Severity {
-		case "CRITICAL":
-			criticals = append(criticals, f)
-		case "WARN":
-			warns = append(warns, f)
-		case "INFO":
-			infos = append(infos, f)
-		}
-	}
-
-	if len(criticals) > 0 {
-		red.Fprintln(w, "  CRITICAL — Will be rejected")
-		fmt.Fprintln(w)
-		for _, f := range criticals {
-			printPreflightFinding(w, f)
-		}
-	}
-
-	if len(warns) > 0 {
-		yellow.Fprintln(w, "  WARNING — High rejection risk")
-		fmt.Fprintln(w)
-		for _, f := range warns {
-			printPreflightFinding(w, f)
-		}
+		fields = append(fields, report.Field{Label: "Tracking", Value: strings.Join(result.TrackingSDKs, ", ")})
 	}
 
-	if len(infos) > 0 {
-		dim.Fprintln(w, "  INFO — Best practices")
-		fmt.Fprintln(w)
-		for _, f := range infos {
-			printPreflightFinding(w, f)
-		}
-	}
-
-	printPreflightFooter(w, result)
-	return nil
-}
-
-func printPreflightFinding(w *os.File, f preflight.Finding) {
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	greenC := color.New(color.FgGreen)
-	bold := color.New(color.Bold)
-
-	// Severity badge + source tag
-	switch f.Severity {
-	case "CRITICAL":
-		red.Fprintf(w, "  [CRITICAL] ")
-	case "WARN":
-		yellow.Fprintf(w, "  [WARN]     ")
-	case "INFO":
-		dim.Fprintf(w, "  [INFO]     ")
-	}
-
-	// Source tag
-	dim.Fprintf(w, "[%s] ", f.Source)
-
-	// Guideline + title
-	if f.Guideline != "" {
-		bold.Fprintf(w, "§%s ", f.Guideline)
-	}
-	bold.Fprintln(w, f.Title)
-
-	// Location
-	if f.File != "" {
-		loc := f.File
-		if f.Line > 0 {
-			loc = fmt.Sprintf("%s:%d", f.File, f.Line)
-		}
-		dim.Fprintf(w, "             %s\n", loc)
-	}
-
-	// Code snippet
-	if f.Code != "" {
-		dim.Fprintf(w, "             > %s\n", truncate(f.Code, 80))
-	}
-
-	// Detail
-	fmt.Fprintf(w, "             %s\n", f.Detail)
-
-	// Fix
-	if f.Fix != "" {
-		greenC.Fprintf(w, "             Fix: ")
-		fmt.Fprintln(w, f.Fix)
-	}
-
-	fmt.Fprintln(w)
-}
-
-func printPreflightFooter(w *os.File, result *preflight.Result) {
-	red := color.New(color.FgRed, color.Bold)
-	green := color.New(color.FgGreen, color.Bold)
-
-	s := result.Summary
-
-	dim.Fprintln(w, "  ─────────────────────────────────────────────")
-	fmt.Fprintln(w)
-
-	if s.Passed {
-		green.Fprint(w, "  GREENLIT")
-		fmt.Fprint(w, " — no critical issues found")
-	} else {
-		red.Fprint(w, "  NOT READY")
-		fmt.Fprintf(w, " — %d critical issue(s) must be fixed", s.Critical)
-	}
-	fmt.Fprintln(w)
-
-	if s.Total > 0 {
-		fmt.Fprintf(w, "  %d findings: ", s.Total)
-		if s.Critical > 0 {
-			red.Fprintf(w, "%d critical  ", s.Critical)
-		}
-		if s.Warns > 0 {
-			color.New(color.FgYellow).Fprintf(w, "%d warn  ", s.Warns)
-		}
-		if s.Infos > 0 {
-			dim.Fprintf(w, "%d info", s.Infos)
-		}
-		fmt.Fprintln(w)
-	}
-
-	// Breakdown by scanner
+	var stats []string
 	sources := make(map[string]int)
 	for _, f := range result.Findings {
 		sources[f.Source]++
 	}
 	if len(sources) > 0 {
 		var parts []string
-		for _, src := range []string{"metadata", "codescan", "privacy", "ipa"} {
+		for _, src := range []string{"metadata", "codescan", "privacy", "vulns", "endpoints", "xcode", "ipa"} {
 			if n, ok := sources[src]; ok {
 				parts = append(parts, fmt.Sprintf("%s: %d", src, n))
 			}
 		}
-		dim.Fprintf(w, "  by scanner: %s\n", strings.Join(parts, "  "))
+		stats = append(stats, "by scanner: "+strings.Join(parts, "  "))
 	}
 
-	dim.Fprintf(w, "  completed in %s\n", result.Elapsed.Round(time.Millisecond))
+	return &report.Findings{
+		Command:    "preflight",
+		Header:     []report.Field{{Label: "Project", Value: result.ProjectPath}},
+		Root:       result.ProjectPath,
+		Context:    fields,
+		Findings:   result.Findings,
+		Stats:      stats,
+		Elapsed:    result.Elapsed,
+		ShowSource: true,
+		JSON:       preflightJSON(result),
+	}
+}
+
+func writePreflightTerminal(w io.Writer, result *preflight.Result) error {
+	return preflightReport(result).WriteTerminal(w)
+}
 
-	// Revyl attribution
-	fmt.Fprintln(w)
-	dim.Fprintln(w, "  ─────────────────────────────────────────────")
-	fmt.Fprintf(w, "  Built by ")
-	purple.Fprint(w, "Revyl")
-	fmt.Fprintln(w, " — the mobile reliability platform")
-	dim.Fprintln(w, "  Catch more than rejections. Catch bugs.")
-	fmt.Fprint(w, "  ")
-	color.New(color.Underline).Fprintln(w, "https://revyl.com")
-	fmt.Fprintln(w)
+func writePreflightJSON(w io.Writer, result *preflight.Result) error {
+	return preflightReport(result).WriteJSON(w)
 }
 
-func writePreflightJSON(w *os.File, result *preflight.Result) error {
-	output := struct {
+// preflightJSON is the document 'preflight --format json' writes.
+func preflightJSON(result *preflight.Result) any {
+	return struct {
 		ProjectPath    string              `json:"project_path"`
 		IPAPath        string              `json:"ipa_path,omitempty"`
 		AppName        string              `json:"app_name,omitempty"`
@@ -328,8 +271,4 @@ func writePreflightJSON(w *os.File, result *preflight.Result) error {
 		Summary:        result.Summary,
 		Elapsed:        result.Elapsed.Round(time.Millisecond).String(),
 	}
-
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "  ")
-	return enc.Encode(output)
 }
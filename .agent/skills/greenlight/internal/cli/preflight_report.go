@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/preflight"
+)
+
+// readPreflightReport loads a report written by 'preflight --format json'.
+func readPreflightReport(path string) (*preflight.Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Elapsed is written as a string ("1.2s").
+	var report struct {
+		*preflight.Result
+		Elapsed string `json:"elapsed"`
+	}
+	report.Result = &preflight.Result{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	report.Result.Elapsed, _ = time.ParseDuration(report.Elapsed)
+	return report.Result, nil
+}
@@ -1,18 +1,32 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/RevylAI/greenlight/internal/privacy"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/pkg/privacy"
+	"github.com/RevylAI/greenlight/pkg/scan"
 	"github.com/spf13/cobra"
 )
 
+var (
+	privacyAggregate bool
+	privacyFormat    string
+	privacyGenOutput string
+	privacyGenDryRun bool
+	privacyMaxMB     int
+	privacyInclude   []string
+	privacyExclude   []string
+)
+
 var privacyCmd = &cobra.Command{
-	Use:   "privacy [path]",
+	Use:   "privacy [path | app.ipa]",
 	Short: "Validate privacy manifest and Required Reason API compliance",
 	Long: `Deep privacy compliance scan for your project.
 
@@ -25,14 +39,43 @@ Checks:
     - Active keyboards (activeInputModes)
     - User Defaults (NSUserDefaults, AsyncStorage)
   • Tracking SDKs detected vs ATT implementation
+  • Tracking endpoints in code vs NSPrivacyTrackingDomains
   • NSPrivacyTracking, NSPrivacyAccessedAPITypes declarations
 
+With --aggregate, merges the app's manifest with every framework's manifest
+(from an .ipa, or Pods/SPM/node_modules checkouts) into the consolidated
+report Apple computes server-side: tracking, tracking domains, Required
+Reason APIs with reasons, and collected data types.
+
 No App Store Connect account needed — runs entirely offline.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runPrivacy,
 }
 
+var privacyGenerateCmd = &cobra.Command{
+	Use:   "generate [path]",
+	Short: "Scaffold or update PrivacyInfo.xcprivacy from detected API usage",
+	Long: `Generate a PrivacyInfo.xcprivacy from what the privacy scanner detects:
+Required Reason APIs used in code and tracking SDK domains contacted.
+
+An existing manifest is updated in place. Manual entries (reasons, collected
+data types, extra domains) are preserved; only missing APIs and domains are
+added. New API entries get the most common approved reason — review each one
+before submitting.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPrivacyGenerate,
+}
+
 func init() {
+	privacyGenerateCmd.Flags().StringVar(&privacyGenOutput, "output", "", "manifest path to write (existing manifest or ./PrivacyInfo.xcprivacy if omitted)")
+	privacyGenerateCmd.Flags().BoolVar(&privacyGenDryRun, "dry-run", false, "print the manifest instead of writing it")
+	privacyCmd.AddCommand(privacyGenerateCmd)
+
+	privacyCmd.Flags().BoolVar(&privacyAggregate, "aggregate", false, "merge all privacy manifests (app + frameworks) into one report")
+	privacyCmd.Flags().IntVar(&privacyMaxMB, "max-file-size", privacy.DefaultMaxFileSize>>20, "skip source files larger than this many MB (0 for no limit)")
+	privacyCmd.Flags().StringSliceVar(&privacyInclude, "include", nil, includeFlagUsage)
+	privacyCmd.Flags().StringSliceVar(&privacyExclude, "exclude", nil, excludeFlagUsage)
+	privacyCmd.Flags().StringVar(&privacyFormat, "format", "terminal", formatFlagUsage+" (terminal or json with --aggregate)")
 	rootCmd.AddCommand(privacyCmd)
 }
 
@@ -42,6 +85,10 @@ func runPrivacy(cmd *cobra.Command, args []string) error {
 		path = args[0]
 	}
 
+	if privacyAggregate {
+		return runPrivacyAggregate(path)
+	}
+
 	info, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("cannot access path: %w", err)
@@ -50,160 +97,228 @@ func runPrivacy(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("path must be a directory: %s", path)
 	}
 
-	purple.Println("\n  greenlight privacy — validate your privacy compliance.")
-	fmt.Printf("  Scanning: %s\n\n", path)
+	paths, err := scanPaths(path, privacyInclude, privacyExclude)
+	if err != nil {
+		return err
+	}
+	if report.IsTerminal(privacyFormat) {
+		purple.Println("\n  " + i18n.T("greenlight privacy — validate your privacy compliance."))
+		fmt.Printf("  Scanning: %s\n", path)
+		printPaths(os.Stdout, 10, paths)
+		fmt.Println()
+	}
+
+	overrides, err := loadRuleOverrides(path)
+	if err != nil {
+		return err
+	}
 
 	start := time.Now()
-	result, err := privacy.Scan(path)
+	maxSize := int64(privacyMaxMB) << 20
+	if maxSize == 0 {
+		maxSize = -1 // no limit
+	}
+	result, err := privacy.ScanWithOptions(cmd.Context(), path, privacy.Options{MaxFileSize: maxSize, Paths: paths})
 	if err != nil {
 		return fmt.Errorf("privacy scan failed: %w", err)
 	}
+	result.Findings = privacy.ApplyOverrides(result.Findings, overrides)
 	elapsed := time.Since(start)
 
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	green := color.New(color.FgGreen)
-	bold := color.New(color.Bold)
-
-	// Status summary
-	if result.HasPrivacyInfo {
-		green.Fprint(os.Stdout, "  ✓ ")
-		fmt.Println("PrivacyInfo.xcprivacy found")
-	} else {
-		red.Fprint(os.Stdout, "  ✗ ")
-		fmt.Println("PrivacyInfo.xcprivacy NOT found")
+	manifest := "found"
+	if !result.HasPrivacyInfo {
+		manifest = "NOT found"
 	}
-
+	fields := []report.Field{{Label: "PrivacyInfo.xcprivacy", Value: manifest}}
 	if len(result.DetectedAPIs) > 0 {
-		fmt.Printf("  Required Reason APIs detected: %s\n", strings.Join(result.DetectedAPIs, ", "))
+		fields = append(fields, report.Field{Label: "Required Reason APIs detected", Value: strings.Join(result.DetectedAPIs, ", ")})
 	}
-
 	if len(result.DeclaredAPIs) > 0 {
-		fmt.Printf("  APIs declared in manifest:     %s\n", strings.Join(result.DeclaredAPIs, ", "))
+		fields = append(fields, report.Field{Label: "APIs declared in manifest", Value: strings.Join(result.DeclaredAPIs, ", ")})
 	}
-
 	if len(result.TrackingSDKs) > 0 {
-		yellow.Fprint(os.Stdout, "  Tracking SDKs found: ")
-		fmt.Println(strings.Join(result.TrackingSDKs, ", "))
+		fields = append(fields, report.Field{Label: "Tracking SDKs found", Value: strings.Join(result.TrackingSDKs, ", ")})
 	}
 
-	fmt.Println()
+	files := fmt.Sprintf("%d files scanned", result.FilesScanned)
+	if n := result.Skipped.Total(); n > 0 {
+		files += fmt.Sprintf(", %d skipped (%s)", n, result.Skipped)
+	}
+	unified := make([]scan.Finding, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		unified = append(unified, f.ScanFinding())
+	}
+	rep := &report.Findings{
+		Command:  "privacy",
+		Header:   []report.Field{{Label: "Project", Value: path}},
+		Context:  fields,
+		Findings: unified,
+		Stats:    []string{files},
+		Elapsed:  elapsed,
+		Scope:    "by the privacy scan",
+		JSON:     result,
+	}
+	return rep.Write(os.Stdout, privacyFormat)
+}
+
+func runPrivacyAggregate(path string) error {
+	agg, err := privacy.Aggregate(path)
+	if err != nil {
+		return err
+	}
+
+	if !report.IsTerminal(privacyFormat) {
+		if strings.ToLower(privacyFormat) != "json" {
+			return fmt.Errorf("--aggregate supports --format terminal or json")
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(agg)
+	}
+
+	bold := color.New(color.Bold)
+	yellow := color.New(color.FgYellow)
+	green := color.New(color.FgGreen)
+
+	purple.Println("\n  greenlight privacy — aggregated privacy report.")
+	fmt.Printf("  Source: %s\n\n", path)
 
-	if len(result.Findings) == 0 {
-		green.Fprintln(os.Stdout, "  No privacy issues found!")
+	if len(agg.Manifests) == 0 {
+		yellow.Println("  No PrivacyInfo.xcprivacy files found.")
 		fmt.Println()
-		printPrivacyFooter(0, 0, 0, elapsed)
 		return nil
 	}
 
-	// Group findings
-	var criticals, warns, infos []privacy.Finding
-	for _, f := range result.Findings {
-		switch f.Severity {
-		case "CRITICAL":
-			criticals = append(criticals, f)
-		case "WARN":
-			warns = append(warns, f)
-		case "INFO":
-			infos = append(infos, f)
+	bold.Printf("  Manifests (%d)\n", len(agg.Manifests))
+	for _, m := range agg.Manifests {
+		if m.Error != "" {
+			yellow.Fprintf(os.Stdout, "    ! %s", m.Source)
+			dim.Fprintf(os.Stdout, " — %s\n", m.Error)
+			continue
 		}
+		fmt.Printf("    • %s", m.Source)
+		dim.Fprintf(os.Stdout, " — %d API(s), %d data type(s)\n", len(m.AccessedAPIs), len(m.CollectedData))
 	}
+	fmt.Println()
 
-	if len(criticals) > 0 {
-		red.Println("  CRITICAL — Will be rejected")
-		fmt.Println()
-		for _, f := range criticals {
-			red.Fprint(os.Stdout, "  [CRITICAL] ")
-			if f.Guideline != "" {
-				bold.Fprintf(os.Stdout, "§%s ", f.Guideline)
-			}
-			bold.Fprintln(os.Stdout, f.Title)
-			fmt.Printf("             %s\n", f.Detail)
-			if f.Fix != "" {
-				green.Fprint(os.Stdout, "             Fix: ")
-				fmt.Println(f.Fix)
-			}
-			fmt.Println()
+	bold.Print("  Tracking: ")
+	if agg.Tracking {
+		yellow.Fprint(os.Stdout, "yes")
+		dim.Fprintf(os.Stdout, " (%s)\n", strings.Join(agg.TrackingSources, ", "))
+	} else {
+		green.Fprintln(os.Stdout, "no")
+	}
+	if len(agg.TrackingDomains) > 0 {
+		bold.Println("  Tracking domains")
+		for _, d := range agg.TrackingDomains {
+			fmt.Printf("    • %s", d.Value)
+			dim.Fprintf(os.Stdout, " ← %s\n", strings.Join(d.Sources, ", "))
 		}
 	}
+	fmt.Println()
 
-	if len(warns) > 0 {
-		yellow.Println("  WARNING — High rejection risk")
-		fmt.Println()
-		for _, f := range warns {
-			yellow.Fprint(os.Stdout, "  [WARN]     ")
-			if f.Guideline != "" {
-				bold.Fprintf(os.Stdout, "§%s ", f.Guideline)
+	if len(agg.AccessedAPIs) > 0 {
+		bold.Println("  Required Reason APIs")
+		for _, a := range agg.AccessedAPIs {
+			fmt.Printf("    • %s", strings.TrimPrefix(a.Type, "NSPrivacyAccessedAPICategory"))
+			if len(a.Reasons) > 0 {
+				fmt.Printf(" [%s]", strings.Join(a.Reasons, ", "))
 			}
-			bold.Fprintln(os.Stdout, f.Title)
-			fmt.Printf("             %s\n", f.Detail)
-			if f.Fix != "" {
-				green.Fprint(os.Stdout, "             Fix: ")
-				fmt.Println(f.Fix)
-			}
-			fmt.Println()
+			dim.Fprintf(os.Stdout, " ← %s\n", strings.Join(a.Sources, ", "))
 		}
+		fmt.Println()
 	}
 
-	if len(infos) > 0 {
-		dim.Println("  INFO — Best practices")
-		fmt.Println()
-		for _, f := range infos {
-			dim.Fprint(os.Stdout, "  [INFO]     ")
-			bold.Fprintln(os.Stdout, f.Title)
-			fmt.Printf("             %s\n", f.Detail)
-			if f.Fix != "" {
-				green.Fprint(os.Stdout, "             Fix: ")
-				fmt.Println(f.Fix)
+	if len(agg.CollectedData) > 0 {
+		bold.Println("  Collected data types")
+		for _, c := range agg.CollectedData {
+			var flags []string
+			if c.Linked {
+				flags = append(flags, "linked")
 			}
-			fmt.Println()
+			if c.Tracking {
+				flags = append(flags, "tracking")
+			}
+			fmt.Printf("    • %s", strings.TrimPrefix(c.Type, "NSPrivacyCollectedDataType"))
+			if len(flags) > 0 {
+				yellow.Fprintf(os.Stdout, " (%s)", strings.Join(flags, ", "))
+			}
+			if len(c.Purposes) > 0 {
+				var purposes []string
+				for _, p := range c.Purposes {
+					purposes = append(purposes, strings.TrimPrefix(p, "NSPrivacyCollectedDataTypePurpose"))
+				}
+				fmt.Printf(" — %s", strings.Join(purposes, ", "))
+			}
+			dim.Fprintf(os.Stdout, " ← %s\n", strings.Join(c.Sources, ", "))
 		}
+		fmt.Println()
 	}
 
-	printPrivacyFooter(len(criticals), len(warns), len(infos), elapsed)
 	return nil
 }
 
-func printPrivacyFooter(criticals, warns, infos int, elapsed time.Duration) {
-	red := color.New(color.FgRed, color.Bold)
-	green := color.New(color.FgGreen, color.Bold)
-	total := criticals + warns + infos
+func runPrivacyGenerate(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
 
-	dim.Fprintln(os.Stdout, "  ─────────────────────────────────────────────")
-	fmt.Println()
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("path must be a directory: %s", path)
+	}
 
-	if criticals == 0 {
-		green.Fprint(os.Stdout, "  GREENLIT")
-		fmt.Fprint(os.Stdout, " — privacy compliance looks good")
-	} else {
-		red.Fprint(os.Stdout, "  NOT READY")
-		fmt.Fprintf(os.Stdout, " — %d critical privacy issue(s)", criticals)
+	res, err := privacy.Generate(cmd.Context(), path, privacyGenOutput)
+	if err != nil {
+		return fmt.Errorf("generate failed: %w", err)
 	}
-	fmt.Println()
 
-	if total > 0 {
-		fmt.Fprintf(os.Stdout, "  %d findings: ", total)
-		if criticals > 0 {
-			red.Fprintf(os.Stdout, "%d critical  ", criticals)
-		}
-		if warns > 0 {
-			color.New(color.FgYellow).Fprintf(os.Stdout, "%d warn  ", warns)
-		}
-		if infos > 0 {
-			dim.Fprintf(os.Stdout, "%d info", infos)
-		}
-		fmt.Println()
+	if privacyGenDryRun {
+		os.Stdout.Write(res.Content)
+		return nil
 	}
 
-	dim.Fprintf(os.Stdout, "  completed in %s\n", elapsed.Round(time.Millisecond))
+	if err := os.WriteFile(res.Path, res.Content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", res.Path, err)
+	}
 
+	green := color.New(color.FgGreen)
+	yellow := color.New(color.FgYellow)
+
+	purple.Println("\n  greenlight privacy generate")
+	if res.Existing {
+		fmt.Printf("  Updated: %s\n\n", res.Path)
+	} else {
+		fmt.Printf("  Created: %s\n\n", res.Path)
+	}
+
+	if len(res.AddedAPIs) == 0 && len(res.AddedDomains) == 0 {
+		green.Println("  Manifest already declares everything detected.")
+		fmt.Println()
+		return nil
+	}
+	for _, api := range res.AddedAPIs {
+		green.Fprint(os.Stdout, "  + ")
+		fmt.Println(api)
+	}
+	for _, d := range res.AddedDomains {
+		green.Fprint(os.Stdout, "  + ")
+		fmt.Printf("tracking domain %s\n", d)
+	}
+	if len(res.AddedAPIs) > 0 {
+		fmt.Println()
+		yellow.Println("  Review the reason codes for each added API — a default was filled in.")
+	}
+	if res.TrackingOff {
+		yellow.Println("  NSPrivacyTracking is false but tracking domains are declared — confirm whether the app tracks.")
+	}
+	if !res.Existing {
+		dim.Println("  Add the new file to your app target in Xcode.")
+	}
 	fmt.Println()
-	dim.Fprintln(os.Stdout, "  ─────────────────────────────────────────────")
-	fmt.Fprint(os.Stdout, "  Built by ")
-	purple.Fprint(os.Stdout, "Revyl")
-	fmt.Fprintln(os.Stdout, " — the mobile reliability platform")
-	dim.Fprintln(os.Stdout, "  Catch more than rejections. Catch bugs.")
-	fmt.Fprint(os.Stdout, "  ")
-	color.New(color.Underline).Fprintln(os.Stdout, "https://revyl.com")
-	fmt.Println()
+	return nil
 }
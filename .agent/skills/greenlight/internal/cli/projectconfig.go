@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/fatih/color"
+)
+
+// loadRuleOverrides reads .greenlight.yml from the project root, warns about
+// unknown rule IDs, and prints the effective rule configuration in verbose mode.
+// Both go to stderr, so they never mix with a report on stdout.
+func loadRuleOverrides(projectPath string) (map[string]config.RuleOverride, error) {
+	cfg, err := config.LoadProject(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := cfg.RuleOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	for _, e := range allRuleEntries() {
+		known[e.ID] = true
+	}
+	yellow := color.New(color.FgYellow)
+	for _, w := range cfg.Warnings {
+		yellow.Fprintf(os.Stderr, "  warning: %s\n", w)
+	}
+	for _, sub := range sourcefile.MissingSubmodules(projectPath) {
+		yellow.Fprintf(os.Stderr, "  warning: git submodule %s is not checked out and won't be scanned (run 'git submodule update --init')\n", sub)
+	}
+	for _, id := range cfg.UnknownRules(known) {
+		yellow.Fprintf(os.Stderr, "  warning: %s: unknown rule '%s' (see 'greenlight rules list')\n", cfg.Path, id)
+	}
+	names := make([]string, 0, len(cfg.Scanners))
+	for name := range cfg.Scanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := scan.Lookup(name); !ok {
+			yellow.Fprintf(os.Stderr, "  warning: %s: unknown scanner '%s'\n", cfg.Path, name)
+		}
+	}
+
+	if verbose {
+		if cfg.Path == "" {
+			dim.Fprintln(os.Stderr, "  Rule config: defaults (no .greenlight.yml)")
+		} else {
+			dim.Fprintf(os.Stderr, "  Rule config: %s\n", cfg.Path)
+			if cfg.Extends != "" {
+				dim.Fprintf(os.Stderr, "    extends %s\n", cfg.Extends)
+			}
+			for _, id := range cfg.SortedRuleIDs() {
+				o := overrides[id]
+				setting := o.Severity.String()
+				if o.Disabled {
+					setting = "off"
+				}
+				dim.Fprintf(os.Stderr, "    %-28s %s\n", id, setting)
+			}
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+
+	return overrides, nil
+}
+
+// Usage of the --include and --exclude flags.
+const (
+	includeFlagUsage = "scan only files matching these globs, e.g. 'ios/**' (repeatable; replaces paths.include in .greenlight.yml)"
+	excludeFlagUsage = "skip files matching these globs, e.g. 'examples/**' (repeatable; added to paths.exclude in .greenlight.yml)"
+)
+
+// scanPaths is the project's configured paths with --include and --exclude
+// layered over them.
+func scanPaths(projectPath string, include, exclude []string) (scan.Paths, error) {
+	flags, err := scan.NewPaths(include, exclude)
+	if err != nil {
+		return scan.Paths{}, err
+	}
+	cfg, err := config.LoadProject(projectPath)
+	if err != nil {
+		return scan.Paths{}, err
+	}
+	configured, err := scan.NewPaths(cfg.Paths.Include, cfg.Paths.Exclude)
+	if err != nil {
+		return scan.Paths{}, err
+	}
+	configured.FirstParty = cfg.Paths.FirstParty
+	return configured.Merge(flags), nil
+}
+
+// printPaths prints the include and exclude patterns in a banner written to
+// w whose labels are width wide.
+func printPaths(w io.Writer, width int, paths scan.Paths) {
+	if len(paths.Include) > 0 {
+		fmt.Fprintf(w, "  %-*s%s\n", width, "Include:", strings.Join(paths.Include, ", "))
+	}
+	if len(paths.Exclude) > 0 {
+		fmt.Fprintf(w, "  %-*s%s\n", width, "Exclude:", strings.Join(paths.Exclude, ", "))
+	}
+}
@@ -1,8 +1,19 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/termout"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -10,6 +21,11 @@ import (
 var (
 	appVersion string
 	verbose    bool
+	timeout    time.Duration
+	logLevel   string
+	logJSON    bool
+	noBanner   bool
+	reportLang string
 )
 
 var purple = color.New(color.FgHiMagenta)
@@ -29,18 +45,140 @@ Get started:
   greenlight scan --app-id ID     Check App Store Connect metadata (needs API key)
   greenlight guidelines search    Browse Apple's review guidelines`,
 		purple.Sprint("greenlight — know before you submit.")),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupLogging(cmd); err != nil {
+			return err
+		}
+		termout.Configure(os.Stdout)
+		termout.ShowBanner = !noBanner
+		if err := setupLanguage(cmd); err != nil {
+			return err
+		}
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+		startUpdateNotice(cmd)
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		printUpdateNotice()
+		flushTelemetry()
+	},
 }
 
+// setupLogging installs the default slog logger on stderr. -v lowers the
+// level to debug unless --log-level is given.
+func setupLogging(cmd *cobra.Command) error {
+	var level slog.Level
+	switch {
+	case cmd.Flags().Changed("log-level"):
+		if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+			return fmt.Errorf("invalid --log-level %q (use debug, info, warn or error)", logLevel)
+		}
+	case verbose:
+		level = slog.LevelDebug
+	default:
+		level = slog.LevelWarn
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, opts)
+	if logJSON {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// setupLanguage selects the language of human-readable reports: --lang,
+// else the config's "lang".
+func setupLanguage(cmd *cobra.Command) error {
+	lang := reportLang
+	if !cmd.Flags().Changed("lang") {
+		if cfg, err := config.Load(); err == nil && cfg.Lang != "" {
+			lang = cfg.Lang
+		}
+	}
+	if err := i18n.SetLanguage(lang); err != nil {
+		return fmt.Errorf("--lang: %w", err)
+	}
+	return nil
+}
+
+// cancelTimeout releases the --timeout context once the command returns.
+var cancelTimeout context.CancelFunc = func() {}
+
 func SetVersion(v string) {
 	appVersion = v
 }
 
+// Execute runs the CLI. Ctrl-C (or SIGTERM) cancels the command's context,
+// which stops in-flight file walks and API requests; a second Ctrl-C exits
+// immediately.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	defer func() { cancelTimeout() }()
+	reportCancellation(rootCmd)
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// exitError is an error with its own process exit code, for commands whose
+// callers need to tell kinds of failure apart.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode is the process exit code for err, an error Execute returned: 1
+// unless the command chose another.
+func ExitCode(err error) int {
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return 1
+}
+
+// reportCancellation replaces the error of a command stopped by Ctrl-C or
+// --timeout with a plain message, without the usage text.
+func reportCancellation(cmd *cobra.Command) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			err := run(cmd, args)
+			switch {
+			case err == nil:
+				return nil
+			case errors.Is(cmd.Context().Err(), context.DeadlineExceeded):
+				cmd.SilenceUsage = true
+				return fmt.Errorf("timed out after %s (--timeout)", timeout)
+			case errors.Is(cmd.Context().Err(), context.Canceled):
+				cmd.SilenceUsage = true
+				return errors.New("interrupted")
+			}
+			return err
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		reportCancellation(sub)
+	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "log level on stderr: debug, info, warn, error")
+	rootCmd.PersistentFlags().BoolVar(&logJSON, "log-json", false, "write logs as JSON lines (for CI log ingestion)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "cancel the command after this long, e.g. 5m (0 = no limit)")
+	rootCmd.PersistentFlags().BoolVar(&noBanner, "no-banner", false, "leave the attribution footer out of terminal reports (for CI logs)")
+	rootCmd.PersistentFlags().StringVar(&reportLang, "lang", "en", "language of terminal, Markdown, HTML and PDF reports: "+strings.Join(i18n.Languages, ", "))
 
 	rootCmd.AddCommand(scanCmd)
 	rootCmd.AddCommand(authCmd)
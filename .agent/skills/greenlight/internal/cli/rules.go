@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/internal/triage"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/privacy"
+	"github.com/RevylAI/greenlight/pkg/severity"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var rulesFormat string
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List and explain the built-in codescan and privacy rules",
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every codescan and privacy rule with severity and guideline",
+	Args:  cobra.NoArgs,
+	RunE:  runRulesList,
+}
+
+var rulesExplainCmd = &cobra.Command{
+	Use:   "explain [rule-id]",
+	Short: "Explain a rule: what triggers it, the guideline, and how to suppress it",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRulesExplain,
+}
+
+// ruleEntry is the common shape of codescan rules and privacy checks.
+type ruleEntry struct {
+	Scanner     string         `json:"scanner"`
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Severity    severity.Level `json:"severity"`
+	Guideline   string         `json:"guideline,omitempty"`
+	Languages   []string       `json:"languages,omitempty"`
+	Description string         `json:"description"`
+	Fix         string         `json:"fix,omitempty"`
+	Examples    []string       `json:"examples,omitempty"`
+	Suppress    string         `json:"suppress"`
+	// Effort and Owner suggest the size of a fix and who owns it.
+	Effort triage.Effort `json:"effort"`
+	Owner  triage.Owner  `json:"owner"`
+}
+
+func init() {
+	rulesListCmd.Flags().StringVar(&rulesFormat, "format", "terminal", "output format: terminal, json")
+	rulesExplainCmd.Flags().StringVar(&rulesFormat, "format", "terminal", "output format: terminal, json")
+	rulesCmd.AddCommand(rulesListCmd)
+	rulesCmd.AddCommand(rulesExplainCmd)
+	rootCmd.AddCommand(rulesCmd)
+}
+
+func allRuleEntries() []ruleEntry {
+	var entries []ruleEntry
+	for _, r := range codescan.Catalog() {
+		entries = append(entries, ruleEntry{
+			Scanner:     "codescan",
+			ID:          r.ID,
+			Title:       r.Title,
+			Severity:    r.Severity,
+			Guideline:   r.Guideline,
+			Languages:   r.Languages,
+			Description: r.Description,
+			Fix:         r.Fix,
+			Examples:    r.Examples,
+			Suppress:    codescan.SuppressionHelp(r.ID),
+		})
+	}
+	for _, c := range privacy.Catalog() {
+		entries = append(entries, ruleEntry{
+			Scanner:     "privacy",
+			ID:          c.ID,
+			Title:       c.Title,
+			Severity:    c.Severity,
+			Guideline:   c.Guideline,
+			Description: c.Description,
+			Fix:         c.Fix,
+			Examples:    c.Examples,
+			Suppress:    "Privacy checks compare code with PrivacyInfo.xcprivacy; resolve them by updating the manifest.",
+		})
+	}
+	for i, e := range entries {
+		hint := triage.For(e.Scanner, e.ID, "", e.Guideline)
+		entries[i].Effort, entries[i].Owner = hint.Effort, hint.Owner
+	}
+	return entries
+}
+
+func runRulesList(cmd *cobra.Command, args []string) error {
+	entries := allRuleEntries()
+
+	if strings.ToLower(rulesFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	bold := color.New(color.Bold)
+	purple.Println("\n  greenlight rules")
+
+	scanner := ""
+	for _, e := range entries {
+		if e.Scanner != scanner {
+			scanner = e.Scanner
+			fmt.Println()
+			bold.Printf("  %s\n", scanner)
+		}
+		fmt.Print("    ")
+		severityBadge(e.Severity)
+		fmt.Printf(" %-28s", e.ID)
+		if e.Guideline != "" {
+			dim.Printf(" §%-6s", e.Guideline)
+		} else {
+			dim.Printf("  %-6s", "")
+		}
+		fmt.Printf(" %s\n", e.Title)
+	}
+
+	fmt.Println()
+	dim.Println("  Run 'greenlight rules explain <id>' for details.")
+	fmt.Println()
+	return nil
+}
+
+func runRulesExplain(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	var entry *ruleEntry
+	for _, e := range allRuleEntries() {
+		if e.ID == id {
+			e := e
+			entry = &e
+			break
+		}
+	}
+	if entry == nil {
+		return fmt.Errorf("unknown rule '%s' (see 'greenlight rules list')", id)
+	}
+
+	if strings.ToLower(rulesFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entry)
+	}
+
+	bold := color.New(color.Bold)
+	green := color.New(color.FgGreen)
+
+	fmt.Println()
+	fmt.Print("  ")
+	severityBadge(entry.Severity)
+	bold.Printf(" %s", entry.ID)
+	dim.Printf("  (%s)\n", entry.Scanner)
+	bold.Printf("  %s\n\n", entry.Title)
+	fmt.Printf("  %s\n", entry.Description)
+
+	if entry.Guideline != "" {
+		fmt.Println()
+		bold.Print("  Guideline: ")
+		fmt.Print("§" + entry.Guideline)
+		if db, err := guidelines.Load(); err == nil {
+			if g, ok := db.Get(entry.Guideline); ok {
+				dim.Printf(" — %s", g.Title)
+			}
+		}
+		fmt.Println()
+	}
+	if len(entry.Languages) > 0 {
+		bold.Print("  Files:     ")
+		fmt.Println(strings.Join(entry.Languages, ", "))
+	}
+	bold.Print("  Effort:    ")
+	fmt.Printf("%s fix, owned by %s\n", entry.Effort, entry.Owner)
+
+	if len(entry.Examples) > 0 {
+		fmt.Println()
+		bold.Println("  Example triggers:")
+		for _, ex := range entry.Examples {
+			dim.Printf("    > %s\n", ex)
+		}
+	}
+
+	if entry.Fix != "" {
+		fmt.Println()
+		green.Print("  Fix: ")
+		fmt.Println(entry.Fix)
+	}
+
+	fmt.Println()
+	bold.Print("  Suppress: ")
+	fmt.Println(entry.Suppress)
+	fmt.Println()
+	return nil
+}
+
+func severityBadge(sev severity.Level) {
+	switch sev {
+	case severity.Critical:
+		color.New(color.FgRed, color.Bold).Print("[CRITICAL]")
+	case severity.Warn:
+		color.New(color.FgYellow).Print("[WARN]    ")
+	default:
+		dim.Print("[INFO]    ")
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sbom"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sbomFormat string
+	sbomOutput string
+)
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [path]",
+	Short: "Generate a software bill of materials (CycloneDX or SPDX)",
+	Long: `Write a software bill of materials for the app: every CocoaPod in
+Podfile.lock, every Swift package in Package.resolved, and the npm packages a
+React Native app bundles (package-lock.json, or package.json when there is
+no lock file; devDependencies are left out).
+
+Each component has its package URL and, when its sources are installed
+(Pods/, node_modules, Swift package checkouts), its license.
+
+Formats: cyclonedx (CycloneDX 1.5 JSON) and spdx (SPDX 2.3 JSON).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSBOM,
+}
+
+func init() {
+	sbomCmd.Flags().StringVar(&sbomFormat, "format", "cyclonedx", "SBOM format: "+strings.Join(sbom.Formats, ", "))
+	sbomCmd.Flags().StringVarP(&sbomOutput, "output", "o", "", "file to write the SBOM to (stdout if omitted)")
+	rootCmd.AddCommand(sbomCmd)
+}
+
+func runSBOM(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	format := strings.ToLower(sbomFormat)
+	if !slices.Contains(sbom.Formats, format) {
+		return fmt.Errorf("invalid --format %q (use %s)", sbomFormat, strings.Join(sbom.Formats, " or "))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("path must be a directory: %s", path)
+	}
+	cmd.SilenceUsage = true
+
+	bom, err := sbom.Collect(path)
+	if err != nil {
+		return fmt.Errorf("reading dependencies: %w", err)
+	}
+
+	if sbomOutput == "" || sbomOutput == "-" {
+		return sbom.Write(os.Stdout, bom, format, appVersion)
+	}
+	f, err := os.Create(sbomOutput)
+	if err != nil {
+		return err
+	}
+	if err := sbom.Write(f, bom, format, appVersion); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "  Wrote %s SBOM with %d components to %s\n", format, len(bom.Components), sbomOutput)
+	return nil
+}
@@ -1,24 +1,42 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"io"
 	"strings"
 	"time"
 
-	"github.com/RevylAI/greenlight/internal/asc"
 	"github.com/RevylAI/greenlight/internal/checks"
 	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/i18n"
 	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/ipa"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/RevylAI/greenlight/pkg/scan"
 	"github.com/spf13/cobra"
 )
 
 var (
-	scanAppID    string
-	scanBuildNum string
-	scanFormat   string
-	scanOutput   string
-	scanTier     int
+	scanAppID       string
+	scanBuildNum    string
+	scanFormat      string
+	scanOutputs     []string
+	scanTier        int
+	scanBrands      []string
+	scanStaleDays   int
+	scanCheckTime   time.Duration
+	scanProject     string
+	scanIPA         string
+	scanInclude     []string
+	scanExclude     []string
+	scanAllApps     bool
+	scanParallel    int
+	scanOnly        []string
+	scanSkip        []string
+	scanCompetitors bool
+	scanSelection   scan.Selection
 )
 
 var scanCmd = &cobra.Command{
@@ -32,66 +50,162 @@ Checks are organized into tiers:
   --tier 3   Binary inspection (requires IPA path)
   --tier 4   Historical pattern matching (community data)
 
-By default, runs all tiers.`,
+By default, runs all tiers. --only and --skip take check IDs (see
+'greenlight checks list') or guideline sections, comma-separated or
+repeated: --skip url-reachability --only '5.1.*'.
+
+Pass several apps to --app-id (comma-separated), or --all-apps for every
+app on the account, to scan a portfolio: the apps are checked concurrently
+and reported together with a pass/fail line per app.
+
+Usage:
+  greenlight scan --app-id com.example.app
+  greenlight scan --app-id 6449123456,com.example.other,"My App"
+  greenlight scan --all-apps --format json --output portfolio.json`,
 	RunE: runScan,
 }
 
 func init() {
-	scanCmd.Flags().StringVar(&scanAppID, "app-id", "", "App Store Connect app ID (required)")
+	scanCmd.Flags().StringVar(&scanAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name; comma-separated for several apps")
 	scanCmd.Flags().StringVar(&scanBuildNum, "build", "", "build number to check (latest if omitted)")
-	scanCmd.Flags().StringVar(&scanFormat, "format", "terminal", "output format: terminal, json, junit")
-	scanCmd.Flags().StringVar(&scanOutput, "output", "", "write report to file (stdout if omitted)")
+	scanCmd.Flags().StringVar(&scanFormat, "format", "terminal", formatFlagUsage)
+	scanCmd.Flags().StringArrayVar(&scanOutputs, "output", nil, outputFlagUsage)
 	scanCmd.Flags().IntVar(&scanTier, "tier", 4, "max check tier to run (1-4)")
-	scanCmd.MarkFlagRequired("app-id")
+	scanCmd.Flags().StringSliceVar(&scanBrands, "brand-term", nil, "extra brand/competitor term to flag in metadata (repeatable)")
+	scanCmd.Flags().DurationVar(&scanCheckTime, "check-timeout", 0, "stop any single check that runs longer than this (default 1m30s)")
+	scanCmd.Flags().IntVar(&scanStaleDays, "stale-build-days", 0, "flag the latest build when it is older than this many days (default 30)")
+	scanCmd.Flags().StringVar(&scanProject, "project", "", "local project to cross-check version, build number and capabilities against App Store Connect")
+	scanCmd.Flags().StringSliceVar(&scanInclude, "include", nil, "with --project, read only project files matching these globs, e.g. 'ios/**' (repeatable; replaces paths.include in .greenlight.yml)")
+	scanCmd.Flags().StringSliceVar(&scanExclude, "exclude", nil, "with --project, skip project files matching these globs, e.g. 'examples/**' (repeatable; added to paths.exclude in .greenlight.yml)")
+	scanCmd.Flags().StringVar(&scanIPA, "ipa", "", "IPA to cross-check version and build number against App Store Connect")
+	scanCmd.Flags().BoolVar(&scanAllApps, "all-apps", false, "scan every app on the account")
+	scanCmd.Flags().IntVar(&scanParallel, "parallel", 4, "apps to scan at once with --all-apps or several --app-id values")
+	scanCmd.Flags().StringSliceVar(&scanOnly, "only", nil, "run only these checks or guideline sections, e.g. url-reachability or '5.1.*' (repeatable)")
+	scanCmd.Flags().StringSliceVar(&scanSkip, "skip", nil, "skip these checks or guideline sections (repeatable)")
+	scanCmd.Flags().BoolVar(&scanCompetitors, "competitors", false, "search the app's App Store category for top competitors and flag their names and keyword stuffing in the metadata (sends the name, subtitle and keywords to the iTunes Search API)")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
+	portfolio := scanAllApps || strings.Contains(scanAppID, ",")
+	switch {
+	case scanAllApps && scanAppID != "":
+		return fmt.Errorf("use either --app-id or --all-apps, not both")
+	case !scanAllApps && strings.TrimSpace(scanAppID) == "":
+		return fmt.Errorf("an app is required: --app-id <id, bundle ID or name> or --all-apps")
+	case portfolio && (scanProject != "" || scanIPA != "" || scanBuildNum != ""):
+		return fmt.Errorf("--project, --ipa and --build describe a single app; they can't be used when scanning several apps")
+	case scanProject == "" && (len(scanInclude) > 0 || len(scanExclude) > 0):
+		return fmt.Errorf("--include and --exclude narrow the --project files; pass --project too")
+	}
+	var err error
+	if scanSelection, err = scan.NewSelection(scanOnly, scanSkip); err != nil {
+		return err
+	}
+	sinks, err := parseOutputs(scanOutputs, scanFormat)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("not authenticated — run 'greenlight auth setup' first: %w", err)
 	}
 
-	// Banner
-	purple.Println("\n  greenlight — know before you submit.")
-	fmt.Printf("  App ID:   %s\n", scanAppID)
-	fmt.Printf("  Tier:     1-%d\n", scanTier)
-	fmt.Printf("  Format:   %s\n\n", scanFormat)
-
 	// Init API client
 	client, err := asc.NewClient(cfg.KeyID, cfg.IssuerID, cfg.PrivateKeyPath)
 	if err != nil {
 		return fmt.Errorf("failed to create API client: %w", err)
 	}
+	if portfolio {
+		return runPortfolioScan(cmd.Context(), cfg, client, sinks)
+	}
+	if scanAppID, err = resolveAppID(cmd.Context(), client, scanAppID); err != nil {
+		return err
+	}
+
+	// Banner
+	out := bannerWriter(sinks)
+	purple.Fprintln(out, "\n  "+i18n.T("greenlight — know before you submit."))
+	fmt.Fprintf(out, "  App ID:   %s\n", scanAppID)
+	fmt.Fprintf(out, "  Tier:     1-%d\n", scanTier)
+	fmt.Fprintf(out, "  Format:   %s\n", describeOutputs(sinks))
+	printSelection(out, 10, scanSelection)
+
+	source, version, build, err := scanLocalVersion(cmd.Context())
+	if err != nil {
+		return err
+	}
+	if source != "" {
+		fmt.Fprintf(out, "  Local:    %s (%s) from %s\n", version, build, source)
+	}
+	fmt.Fprintln(out)
 
 	// Run checks
 	start := time.Now()
-	runner := checks.NewRunner(client, verbose)
+	runner := newScanRunner(cfg, client)
+	if source != "" {
+		runner.SetLocalVersion(source, version, build)
+	}
+	if scanProject != "" {
+		paths, err := scanPaths(scanProject, scanInclude, scanExclude)
+		if err != nil {
+			return err
+		}
+		runner.SetProjectPath(scanProject)
+		runner.SetPaths(paths)
+	}
 	results, err := runner.Run(cmd.Context(), scanAppID, scanBuildNum, scanTier)
 	if err != nil {
 		return fmt.Errorf("scan failed: %w", err)
 	}
 	elapsed := time.Since(start)
 
+	recordScan(scanAppID, scanProject, results)
+
 	// Generate report
-	rep := report.New(results, elapsed)
+	return writeScanReport(report.New(results, elapsed), sinks)
+}
+
+// scanReport is a single-app report or a portfolio.
+type scanReport interface {
+	Write(w io.Writer, format string) error
+}
 
-	var output *os.File
-	if scanOutput != "" {
-		output, err = os.Create(scanOutput)
+// writeScanReport writes rep to every --output sink.
+func writeScanReport(rep scanReport, sinks []outputSink) error {
+	return writeOutputs(sinks, rep.Write)
+}
+
+// newScanRunner is a check runner with the brand terms and stale-build age
+// from the config and flags.
+func newScanRunner(cfg *config.Config, client *asc.Client) *checks.Runner {
+	runner := checks.NewRunner(client)
+	runner.AddBrandTerms(cfg.BrandTerms...)
+	runner.AddBrandTerms(scanBrands...)
+	runner.SetStaleBuildDays(cfg.StaleBuildDays)
+	runner.SetStaleBuildDays(scanStaleDays)
+	runner.SetCheckTimeout(scanCheckTime)
+	runner.SetCompetitorSearch(scanCompetitors)
+	runner.SetSelection(scanSelection)
+	return runner
+}
+
+// scanLocalVersion reads the version and build number from --ipa, or from
+// --project when no IPA is given.
+func scanLocalVersion(ctx context.Context) (source, version, build string, err error) {
+	switch {
+	case scanIPA != "":
+		result, err := ipa.Inspect(ctx, scanIPA)
 		if err != nil {
-			return fmt.Errorf("failed to create output file: %w", err)
+			return "", "", "", fmt.Errorf("failed to read IPA: %w", err)
 		}
-		defer output.Close()
-	} else {
-		output = os.Stdout
-	}
-
-	switch strings.ToLower(scanFormat) {
-	case "json":
-		return rep.WriteJSON(output)
-	case "junit":
-		return rep.WriteJUnit(output)
-	default:
-		return rep.WriteTerminal(output)
+		return "IPA", result.Version, result.BuildNumber, nil
+	case scanProject != "":
+		meta, err := preflight.ReadAppMeta(scanProject)
+		if err != nil {
+			return "", "", "", err
+		}
+		return "Project", meta.Version, meta.BuildNumber, nil
 	}
+	return "", "", "", nil
 }
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/checks"
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/score"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scoreAppID  string
+	scoreIPA    string
+	scoreFormat string
+	scoreOutput string
+	scoreMin    int
+)
+
+var scoreCmd = &cobra.Command{
+	Use:   "score [path]",
+	Short: "Print the app's readiness for App Review as one percentage",
+	Long: `Run preflight on the project, and scan the app in App Store Connect when
+greenlight is authenticated, then reduce every finding to one readiness
+percentage with a breakdown by section of the App Review Guidelines
+(Safety, Performance, Business, Design, Legal).
+
+Readiness is 100 minus the rejection risk shown in reports: findings are
+weighted by severity and by how often Apple rejects apps under the
+guideline they cite.
+
+The app is scanned by --app-id, or by the bundle ID preflight finds in the
+project. Without credentials only preflight runs.
+
+Formats: terminal, json, and prometheus (text exposition format, for the
+node exporter's textfile collector or a Pushgateway).
+
+Usage:
+  greenlight score .
+  greenlight score ./my-app --app-id com.example.app --format json
+  greenlight score . --format prometheus --output /var/lib/node_exporter/greenlight.prom
+  greenlight score . --min 80     # exit non-zero below 80%`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScore,
+}
+
+func init() {
+	scoreCmd.Flags().StringVar(&scoreAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name to scan (default: the project's bundle ID)")
+	scoreCmd.Flags().StringVar(&scoreIPA, "ipa", "", "path to .ipa file (or .xcarchive) for binary inspection")
+	scoreCmd.Flags().StringVar(&scoreFormat, "format", "terminal", "output format: "+strings.Join(score.Formats, ", "))
+	scoreCmd.Flags().StringVarP(&scoreOutput, "output", "o", "", "file to write the score to (stdout if omitted)")
+	scoreCmd.Flags().IntVar(&scoreMin, "min", 0, "exit non-zero when readiness is below this percentage")
+	rootCmd.AddCommand(scoreCmd)
+}
+
+func runScore(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	format := strings.ToLower(scoreFormat)
+	if !slices.Contains(score.Formats, format) {
+		return fmt.Errorf("invalid --format %q (use %s)", scoreFormat, strings.Join(score.Formats, ", "))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("path must be a directory: %s", path)
+	}
+	if scoreIPA != "" {
+		if _, err := os.Stat(scoreIPA); os.IsNotExist(err) {
+			return fmt.Errorf("IPA file not found: %s", scoreIPA)
+		}
+	}
+	if _, err := loadRuleOverrides(path); err != nil {
+		return err
+	}
+	paths, err := scanPaths(path, nil, nil)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	// Progress goes to stderr, so stdout is only the score.
+	fmt.Fprintf(os.Stderr, "  Running preflight on %s\n", path)
+	result, err := preflight.RunTarget(cmd.Context(), scan.Target{ProjectPath: path, IPAPath: scoreIPA})
+	if err != nil {
+		return fmt.Errorf("preflight failed: %w", err)
+	}
+	findings := result.Findings
+	sources := []string{"preflight"}
+	app := result.BundleID
+	if app == "" {
+		abs, _ := filepath.Abs(path)
+		app = filepath.Base(abs)
+	}
+
+	cfg, cfgErr := config.Load()
+	appID := scoreAppID
+	if appID == "" {
+		appID = result.BundleID
+	}
+	switch {
+	case cfgErr != nil && scoreAppID != "":
+		return cfgErr
+	case cfgErr != nil:
+		fmt.Fprintln(os.Stderr, "  Not authenticated: scoring preflight only")
+	case appID == "":
+		fmt.Fprintln(os.Stderr, "  No bundle ID found in the project: scoring preflight only (pass --app-id to scan)")
+	default:
+		client, err := asc.NewClient(cfg.KeyID, cfg.IssuerID, cfg.PrivateKeyPath)
+		if err != nil {
+			return fmt.Errorf("failed to create API client: %w", err)
+		}
+		resolved, err := resolveAppID(cmd.Context(), client, appID)
+		if err != nil {
+			if scoreAppID != "" {
+				return err
+			}
+			// The project's app may not be in App Store Connect yet.
+			fmt.Fprintf(os.Stderr, "  %v: scoring preflight only\n", err)
+			break
+		}
+		fmt.Fprintf(os.Stderr, "  Scanning %s in App Store Connect\n", appID)
+		runner := checks.NewRunner(client)
+		runner.AddBrandTerms(cfg.BrandTerms...)
+		runner.SetStaleBuildDays(cfg.StaleBuildDays)
+		runner.SetProjectPath(path)
+		runner.SetPaths(paths)
+		results, err := runner.Run(cmd.Context(), resolved, "", 4)
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		findings = append(findings, checks.ScanFindings(results.Findings)...)
+		sources = append(sources, "scan")
+		if scoreAppID != "" {
+			app = scoreAppID
+		}
+	}
+
+	s := score.New(app, findings, sources...)
+	if scoreOutput == "" || scoreOutput == "-" {
+		err = score.Write(os.Stdout, s, format)
+	} else {
+		err = writeScoreFile(scoreOutput, s, format)
+	}
+	if err != nil {
+		return err
+	}
+	if s.Readiness < scoreMin {
+		return fmt.Errorf("readiness %d%% is below --min %d%%", s.Readiness, scoreMin)
+	}
+	return nil
+}
+
+// writeScoreFile writes s to path, replacing it in one rename so a metrics
+// collector reading it never sees a partial file.
+func writeScoreFile(path string, s score.Score, format string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := score.Write(f, s, format); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "  Wrote readiness %d%% to %s\n", s.Readiness, path)
+	return nil
+}
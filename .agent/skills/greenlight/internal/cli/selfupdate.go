@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/selfupdate"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	selfUpdateCheck  bool
+	selfUpdateForce  bool
+	selfUpdateNotice string
+)
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update greenlight to the latest release",
+	Long: `Download the latest greenlight release for this platform, verify its
+signed checksum and replace the running binary. Newer releases carry
+updated guideline data and rules.
+
+Greenlight also prints a short notice when a newer release is out (checked
+at most once a day in the background). Turn it off with --notice off or
+GREENLIGHT_NO_UPDATE_NOTICE=1.
+
+Usage:
+  greenlight self-update
+  greenlight self-update --check
+  greenlight self-update --notice off`,
+	Args: cobra.NoArgs,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "only report whether a newer release is available")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "install the latest release even if it is not newer (or this is a development build)")
+	selfUpdateCmd.Flags().StringVar(&selfUpdateNotice, "notice", "", "turn the new-version notice on or off")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	if selfUpdateNotice != "" {
+		return setUpdateNotice(selfUpdateNotice)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	release, err := selfupdate.Latest(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+	if _, err := selfupdate.Refresh(cmd.Context(), client); err != nil {
+		dim.Printf("  (could not cache the release check: %v)\n", err)
+	}
+
+	purple.Println("\n  greenlight self-update")
+	fmt.Printf("  Installed: %s\n", appVersion)
+	fmt.Printf("  Latest:    %s\n\n", release.Version)
+
+	_, isRelease := selfupdate.ReleaseVersion(appVersion)
+	newer := selfupdate.Newer(appVersion, release.Version)
+	switch {
+	case !isRelease && !selfUpdateForce:
+		fmt.Println("  This is a development build; use --force to replace it with the latest release.")
+		fmt.Println()
+		return nil
+	case !newer && !selfUpdateForce:
+		color.New(color.FgGreen).Println("  greenlight is up to date.")
+		fmt.Println()
+		return nil
+	case selfUpdateCheck:
+		fmt.Printf("  Run 'greenlight self-update' to install %s.\n", release.Version)
+		if release.URL != "" {
+			fmt.Printf("  Release notes: %s\n", release.URL)
+		}
+		fmt.Println()
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot locate the greenlight binary: %w", err)
+	}
+	fmt.Printf("  Downloading %s...\n", selfupdate.AssetName())
+	binary, err := release.Download(cmd.Context(), client)
+	if err != nil {
+		return err
+	}
+	if err := selfupdate.Replace(exe, binary); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	color.New(color.FgGreen).Printf("  ✓ Updated %s to %s (signature and checksum verified)\n\n", exe, release.Version)
+	return nil
+}
+
+// setUpdateNotice saves the new-version notice setting to the config.
+func setUpdateNotice(value string) error {
+	var on bool
+	switch value {
+	case "on", "true":
+		on = true
+	case "off", "false":
+	default:
+		return fmt.Errorf("--notice must be on or off")
+	}
+	cfg, err := config.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg, err = &config.Config{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	cfg.UpdateNotice = &on
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+	fmt.Printf("  New-version notice turned %s.\n", value)
+	return nil
+}
+
+// updateNotice receives the release check for the new-version notice, or
+// is nil when the notice is off.
+var updateNotice chan selfupdate.LastCheck
+
+// startUpdateNotice starts the release check behind the new-version notice.
+// It never blocks the command: a fresh cached result is used as is, and a
+// stale one is refreshed in the background.
+func startUpdateNotice(cmd *cobra.Command) {
+	if cmd == selfUpdateCmd || cmd == versionCmd || os.Getenv("GREENLIGHT_NO_UPDATE_NOTICE") != "" {
+		return
+	}
+	if _, ok := selfupdate.ReleaseVersion(appVersion); !ok || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return
+	}
+	if cfg, err := config.Load(); err == nil && cfg.UpdateNotice != nil && !*cfg.UpdateNotice {
+		return
+	}
+	ch := make(chan selfupdate.LastCheck, 1)
+	updateNotice = ch
+	last := selfupdate.LoadLastCheck()
+	if !last.Stale() {
+		ch <- last
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if c, err := selfupdate.Refresh(ctx, http.DefaultClient); err == nil {
+			last = c
+		}
+		ch <- last
+	}()
+}
+
+// printUpdateNotice prints the new-version notice to stderr if the release
+// check has finished and found a newer release.
+func printUpdateNotice() {
+	if updateNotice == nil {
+		return
+	}
+	var last selfupdate.LastCheck
+	select {
+	case last = <-updateNotice:
+	case <-time.After(250 * time.Millisecond):
+		return
+	}
+	if selfupdate.Newer(appVersion, last.Latest) {
+		yellow := color.New(color.FgYellow)
+		yellow.Fprintf(os.Stderr, "\n  A new version of greenlight is available: %s → %s\n", appVersion, last.Latest)
+		dim.Fprintln(os.Stderr, "  Run 'greenlight self-update' to install it ('greenlight self-update --notice off' to hide this).")
+	}
+}
@@ -0,0 +1,585 @@
+package cli
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/checks"
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/history"
+	"github.com/RevylAI/greenlight/internal/report"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/ipa"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr      string
+	serveToken     string
+	serveRoot      string
+	serveMaxUpload int64
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run greenlight as a local REST API with a dashboard of recent runs",
+	Long: `Serve greenlight's scans over HTTP so internal tools can call them
+without shelling out to the CLI. Responses are the same JSON the commands
+write with --format json, and every run is recorded in the scan history.
+
+Endpoints:
+  POST /api/preflight   {"path": "...", "ipa": "...", "scheme": "...", "configuration": "...", "redact": false}
+  POST /api/codescan    {"path": "...", "redact": false}
+  POST /api/ipa         multipart upload (field "ipa"), or {"path": "..."}
+  POST /api/scan        {"app_id": "...", "build": "...", "tier": 4, "project": "..."}
+  GET  /api/runs        recent runs (?limit=50)
+  GET  /api/runs/{id}   one run with its findings
+  GET  /api/health
+  GET  /                dashboard of recent runs
+
+Scans run one at a time. The server listens on localhost by default; set
+--token (or GREENLIGHT_SERVE_TOKEN) to require "Authorization: Bearer
+<token>" before exposing it further, and --root to confine project paths.
+Browsers sign in to the dashboard with the token as the password. Detected
+secrets are masked unless a request sets "redact": false.
+
+POST bodies must be application/json (or multipart for IPA uploads).
+Requests from other web origins are rejected, and without a token so are
+requests for any host but localhost, so web pages can't start scans.
+
+Usage:
+  greenlight serve
+  greenlight serve --addr :7700 --token s3cret --root /srv/checkouts`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:7700", "address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "bearer token required on every request (default $GREENLIGHT_SERVE_TOKEN)")
+	serveCmd.Flags().StringVar(&serveRoot, "root", "", "only allow project and IPA paths under this directory; relative paths resolve against it")
+	serveCmd.Flags().Int64Var(&serveMaxUpload, "max-upload", 4096, "max IPA upload size in MB")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// server holds the state shared by the HTTP handlers.
+type server struct {
+	token string
+	root  string
+
+	scanMu sync.Mutex // scans run one at a time
+
+	dirsMu sync.Mutex
+	dirs   map[string]bool // history dirs runs were recorded in
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	s := &server{token: serveToken, dirs: map[string]bool{}}
+	if s.token == "" {
+		s.token = os.Getenv("GREENLIGHT_SERVE_TOKEN")
+	}
+	if serveRoot != "" {
+		root, err := filepath.Abs(serveRoot)
+		if err != nil {
+			return err
+		}
+		s.root = root
+	}
+	s.addHistoryDir(s.root)
+
+	// Ctrl-C cancels in-flight scans and then stops the server.
+	ctx := cmd.Context()
+	srv := &http.Server{
+		Addr:              serveAddr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	purple.Println("\n  greenlight serve — scans over HTTP.")
+	fmt.Printf("  Listening: http://%s\n", serveAddr)
+	if s.root != "" {
+		fmt.Printf("  Root:      %s\n", s.root)
+	}
+	if s.token == "" {
+		dim.Println("  No --token set: anyone who can reach this address can run scans.")
+	}
+	fmt.Println()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/health", func(w http.ResponseWriter, r *http.Request) {
+		writeServeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": appVersion})
+	})
+	mux.HandleFunc("POST /api/preflight", s.handlePreflight)
+	mux.HandleFunc("POST /api/codescan", s.handleCodescan)
+	mux.HandleFunc("POST /api/ipa", s.handleIPA)
+	mux.HandleFunc("POST /api/scan", s.handleScan)
+	mux.HandleFunc("GET /api/runs", s.handleRuns)
+	mux.HandleFunc("GET /api/runs/{id}", s.handleRun)
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	return s.authorize(mux)
+}
+
+// authorize rejects requests from other web origins and, without a token,
+// for hosts other than localhost, which a DNS-rebinding page would send.
+// With a token, it checks the bearer token, or the Basic password a
+// browser sends for the dashboard.
+func (s *server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+				writeServeError(w, http.StatusForbidden, errors.New("cross-origin requests are not allowed"))
+				return
+			}
+		}
+		if s.token == "" {
+			if !isLoopbackHost(r.Host) {
+				writeServeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed without --token", r.Host))
+				return
+			}
+		} else if !s.validToken(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="greenlight"`)
+			writeServeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validToken reports whether r carries the token, as a bearer token or a
+// Basic password.
+func (s *server) validToken(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		_, got, ok = r.BasicAuth()
+	}
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// isLoopbackHost reports whether a Host header names this machine:
+// localhost or a loopback address, with or without a port.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveRequest is the JSON body of the scan endpoints.
+type serveRequest struct {
+	Path          string `json:"path"`
+	IPA           string `json:"ipa"`
+	Scheme        string `json:"scheme"`
+	Configuration string `json:"configuration"`
+	// Redact masks detected secrets; true unless the request sets false.
+	Redact  *bool  `json:"redact"`
+	AppID   string `json:"app_id"`
+	Build   string `json:"build"`
+	Tier    int    `json:"tier"`
+	Project string `json:"project"`
+}
+
+// redact reports whether secrets are masked in the response.
+func (req serveRequest) redact() bool {
+	return req.Redact == nil || *req.Redact
+}
+
+func decodeServeRequest(r *http.Request) (serveRequest, error) {
+	var req serveRequest
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		return req, errors.New("Content-Type must be application/json")
+	}
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil && err != io.EOF {
+		return req, fmt.Errorf("invalid request body: %w", err)
+	}
+	return req, nil
+}
+
+// resolvePath makes a request path absolute and keeps it under --root.
+func (s *server) resolvePath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("path is required")
+	}
+	if s.root != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(s.root, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if s.root != "" {
+		if rel, err := filepath.Rel(s.root, abs); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s is outside the server root", path)
+		}
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "", fmt.Errorf("cannot access %s", path)
+	}
+	return abs, nil
+}
+
+func (s *server) handlePreflight(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeServeRequest(r)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	path, err := s.resolvePath(req.Path)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ipaPath := ""
+	if req.IPA != "" {
+		if ipaPath, err = s.resolvePath(req.IPA); err != nil {
+			writeServeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if _, err := loadRuleOverrides(path); err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	start := time.Now()
+	result, err := preflight.Run(r.Context(), path, ipaPath, preflight.BuildSelection{
+		Scheme:        req.Scheme,
+		Configuration: req.Configuration,
+	})
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Errorf("preflight failed: %w", err))
+		return
+	}
+	result.Elapsed = time.Since(start)
+	if req.redact() {
+		result.RedactSecrets()
+	}
+	recordPreflight(path, result)
+	s.addHistoryDir(path)
+
+	w.Header().Set("Content-Type", "application/json")
+	writePreflightJSON(w, result)
+}
+
+func (s *server) handleCodescan(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeServeRequest(r)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	path, err := s.resolvePath(req.Path)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	overrides, err := loadRuleOverrides(path)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	start := time.Now()
+	paths, err := scanPaths(path, nil, nil)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	scanner := codescan.NewScanner(path)
+	scanner.SetPaths(paths)
+	findings, err := scanner.Scan(r.Context())
+	if err != nil {
+		writeServeError(w, http.StatusInternalServerError, fmt.Errorf("scan failed: %w", err))
+		return
+	}
+	findings = codescan.ApplyOverrides(findings, overrides)
+	if req.redact() {
+		codescan.RedactSecrets(findings)
+	}
+	recordCodescan(path, findings)
+	s.addHistoryDir(path)
+
+	w.Header().Set("Content-Type", "application/json")
+	writeCodescanJSON(w, findings, scanner.Stats(), time.Since(start))
+}
+
+func (s *server) handleIPA(w http.ResponseWriter, r *http.Request) {
+	var ipaPath, label string
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, serveMaxUpload<<20)
+		file, header, err := r.FormFile("ipa")
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("expected an upload in form field \"ipa\": %w", err))
+			return
+		}
+		defer file.Close()
+		tmp, err := os.CreateTemp("", "greenlight-*.ipa")
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		defer os.Remove(tmp.Name())
+		_, err = io.Copy(tmp, file)
+		tmp.Close()
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("upload failed: %w", err))
+			return
+		}
+		ipaPath, label = tmp.Name(), header.Filename
+	} else {
+		req, err := decodeServeRequest(r)
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if ipaPath, err = s.resolvePath(req.Path); err != nil {
+			writeServeError(w, http.StatusBadRequest, err)
+			return
+		}
+		label = ipaPath
+	}
+
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	start := time.Now()
+	result, err := inspectIPA(r.Context(), ipaPath)
+	if err != nil {
+		writeServeError(w, http.StatusUnprocessableEntity, fmt.Errorf("inspection failed: %w", err))
+		return
+	}
+	result.IPAPath = label
+	recordIPA(label, result)
+
+	writeServeJSON(w, http.StatusOK, struct {
+		IPA     string `json:"ipa"`
+		Elapsed string `json:"elapsed"`
+		*ipa.InspectResult
+	}{label, time.Since(start).Round(time.Millisecond).String(), result})
+}
+
+func (s *server) handleScan(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeServeRequest(r)
+	if err != nil {
+		writeServeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.AppID == "" {
+		writeServeError(w, http.StatusBadRequest, errors.New("app_id is required"))
+		return
+	}
+	if req.Project != "" {
+		if req.Project, err = s.resolvePath(req.Project); err != nil {
+			writeServeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if req.Tier == 0 {
+		req.Tier = 4
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		writeServeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	client, err := asc.NewClient(cfg.KeyID, cfg.IssuerID, cfg.PrivateKeyPath)
+	if err != nil {
+		writeServeError(w, http.StatusServiceUnavailable, fmt.Errorf("failed to create API client: %w", err))
+		return
+	}
+
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	start := time.Now()
+	runner := checks.NewRunner(client)
+	runner.AddBrandTerms(cfg.BrandTerms...)
+	runner.SetStaleBuildDays(cfg.StaleBuildDays)
+	if req.Project != "" {
+		runner.SetProjectPath(req.Project)
+		if paths, err := scanPaths(req.Project, nil, nil); err == nil {
+			runner.SetPaths(paths)
+		}
+	}
+	results, err := runner.Run(r.Context(), req.AppID, req.Build, req.Tier)
+	if err != nil {
+		writeServeError(w, http.StatusBadGateway, fmt.Errorf("scan failed: %w", err))
+		return
+	}
+	recordScan(req.AppID, req.Project, results)
+	s.addHistoryDir(req.Project)
+
+	w.Header().Set("Content-Type", "application/json")
+	report.New(results, time.Since(start)).WriteJSON(w)
+}
+
+func (s *server) addHistoryDir(project string) {
+	if project == "" {
+		project = "."
+	}
+	dir, err := history.Dir(project)
+	if err != nil {
+		return
+	}
+	s.dirsMu.Lock()
+	s.dirs[dir] = true
+	s.dirsMu.Unlock()
+}
+
+// recentRuns returns the runs of every history dir the server has recorded
+// in, newest first.
+func (s *server) recentRuns(limit int) []history.Run {
+	s.dirsMu.Lock()
+	dirs := make([]string, 0, len(s.dirs))
+	for dir := range s.dirs {
+		dirs = append(dirs, dir)
+	}
+	s.dirsMu.Unlock()
+
+	var runs []history.Run
+	for _, dir := range dirs {
+		list, err := history.List(dir)
+		if err != nil {
+			continue
+		}
+		runs = append(runs, list...)
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.After(runs[j].Time) })
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs
+}
+
+func (s *server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return
+		}
+		limit = n
+	}
+	runs := s.recentRuns(limit)
+	for i := range runs {
+		runs[i].Findings = nil
+	}
+	if runs == nil {
+		runs = []history.Run{}
+	}
+	writeServeJSON(w, http.StatusOK, runs)
+}
+
+func (s *server) handleRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, run := range s.recentRuns(0) {
+		if run.ID == id {
+			writeServeJSON(w, http.StatusOK, run)
+			return
+		}
+	}
+	writeServeError(w, http.StatusNotFound, fmt.Errorf("no run %s", id))
+}
+
+var dashboardTemplate = template.Must(template.New("dashboard").Funcs(template.FuncMap{
+	"when": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04:05") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="30">
+<title>greenlight</title>
+<style>
+body { font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 1100px; margin: 2rem auto; padding: 0 1rem; color: #1d1d1f; }
+h1 { color: #a020c0; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .4rem .6rem; border-bottom: 1px solid #d0d7de; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+.pass { color: #1a7f37; font-weight: 600; } .fail { color: #cf222e; font-weight: 600; }
+.meta { color: #57606a; font-size: .9rem; }
+code { font-family: ui-monospace, Menlo, monospace; font-size: .85rem; }
+</style>
+</head>
+<body>
+<h1>greenlight</h1>
+<p class="meta">Recent runs · version {{.Version}} · refreshes every 30s</p>
+{{if .Runs}}
+<table>
+<tr><th>Time</th><th>Command</th><th>Target</th><th>Result</th><th>Critical</th><th>Warn</th><th>Info</th><th></th></tr>
+{{range .Runs}}
+<tr>
+<td>{{when .Time}}</td>
+<td>{{.Command}}</td>
+<td><code>{{.Target}}</code></td>
+<td>{{if .Passed}}<span class="pass">GREENLIT</span>{{else}}<span class="fail">NOT READY</span>{{end}}</td>
+<td class="n">{{.Critical}}</td><td class="n">{{.Warns}}</td><td class="n">{{.Infos}}</td>
+<td><a href="/api/runs/{{.ID}}">findings</a></td>
+</tr>
+{{end}}
+</table>
+{{else}}
+<p>No runs yet. <code>POST /api/preflight {"path": "..."}</code> to start one.</p>
+{{end}}
+</body>
+</html>
+`))
+
+func (s *server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	dashboardTemplate.Execute(w, struct {
+		Version string
+		Runs    []history.Run
+	}{appVersion, s.recentRuns(100)})
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	writeServeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/adnetworks"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/spf13/cobra"
+)
+
+var skadnetworkFormat string
+
+var skadnetworkCmd = &cobra.Command{
+	Use:   "skadnetwork [path]",
+	Short: "Generate SKAdNetworkItems for the ad networks the app bundles",
+	Long: `Detect the ad SDKs the app bundles (AdMob, AppLovin, ironSource, Unity
+Ads, Meta Audience Network and other mediated networks, from Podfile.lock,
+Package.resolved and package.json) and print an SKAdNetworkItems array with
+their SKAdNetwork identifiers, keeping the identifiers the app already
+declares. SKAdNetwork and AdAttributionKit both read it.
+
+Formats: plist (an Info.plist fragment) and json (for expo.ios.infoPlist).
+
+The generated array has each network's own identifiers. Networks also
+publish lists of the demand partners they buy from; the links are printed
+to stderr so you can complete the array.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSKAdNetwork,
+}
+
+func init() {
+	skadnetworkCmd.Flags().StringVar(&skadnetworkFormat, "format", "plist", "output format: "+strings.Join(adnetworks.Formats, ", "))
+	rootCmd.AddCommand(skadnetworkCmd)
+}
+
+func runSKAdNetwork(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	format := strings.ToLower(skadnetworkFormat)
+	if !slices.Contains(adnetworks.Formats, format) {
+		return fmt.Errorf("invalid --format %q (use %s)", skadnetworkFormat, strings.Join(adnetworks.Formats, " or "))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("cannot access path: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("path must be a directory: %s", path)
+	}
+	cmd.SilenceUsage = true
+
+	networks := adnetworks.Detect(path)
+	if len(networks) == 0 {
+		fmt.Fprintln(os.Stderr, "  No ad SDKs found in Podfile.lock, Package.resolved or package.json.")
+		return nil
+	}
+	if err := adnetworks.WriteItems(os.Stdout, networks, preflight.SKAdNetworkIDs(path), format); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "\n  Complete the list with each network's partner identifiers:")
+	for _, n := range networks {
+		fmt.Fprintf(os.Stderr, "    %-26s %s (%s)\n", n.Name, n.ListURL, n.File)
+	}
+	return nil
+}
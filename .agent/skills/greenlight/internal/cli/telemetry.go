@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/checks"
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/history"
+	"github.com/RevylAI/greenlight/internal/telemetry"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	telemetryOutcomes   bool
+	telemetryResult     string
+	telemetryGuidelines []string
+	telemetryRun        string
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Opt-in anonymous rule statistics and review outcomes",
+	Long: `Greenlight can share two kinds of anonymized data, each off until you
+turn it on:
+
+  rule statistics  which built-in rules each scan hit, and how often
+  outcomes         App Review results you report with 'telemetry outcome'
+
+Both feed the community data behind the Tier 4 historical pattern checks.
+Events carry only built-in rule IDs, counts, guideline numbers, the
+greenlight version and platform, the day and a random install ID — never
+app names, bundle IDs, app IDs, file paths, code or finding text.
+
+DO_NOT_TRACK=1 or GREENLIGHT_TELEMETRY=off turns everything off for a run.
+
+Usage:
+  greenlight telemetry status
+  greenlight telemetry enable               # rule statistics
+  greenlight telemetry enable --outcomes    # and review outcomes
+  greenlight telemetry disable
+  greenlight telemetry outcome --result rejected --guideline 2.1 --guideline 5.1.1`,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show what telemetry is on and what is queued",
+	Args:  cobra.NoArgs,
+	RunE:  runTelemetryStatus,
+}
+
+var telemetryEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Share anonymized rule statistics (and, with --outcomes, review outcomes)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTelemetry(true)
+	},
+}
+
+var telemetryDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop sharing telemetry (with --outcomes, only review outcomes)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setTelemetry(false)
+	},
+}
+
+var telemetryOutcomeCmd = &cobra.Command{
+	Use:   "outcome [path]",
+	Short: "Contribute the App Review result for the last scan",
+	Long: `Report whether App Review approved or rejected a submission, with the
+guidelines cited in a rejection. The built-in rules hit by the latest
+recorded run for the project at path (or --run) are sent with it, so
+greenlight can learn which findings predict rejections.
+
+Requires 'greenlight telemetry enable --outcomes'.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTelemetryOutcome,
+}
+
+func init() {
+	telemetryEnableCmd.Flags().BoolVar(&telemetryOutcomes, "outcomes", false, "also allow sending review outcomes")
+	telemetryDisableCmd.Flags().BoolVar(&telemetryOutcomes, "outcomes", false, "only stop sending review outcomes")
+	telemetryOutcomeCmd.Flags().StringVar(&telemetryResult, "result", "", "review result: approved or rejected (required)")
+	telemetryOutcomeCmd.Flags().StringSliceVar(&telemetryGuidelines, "guideline", nil, "guideline cited in the rejection, e.g. 2.1 (repeatable)")
+	telemetryOutcomeCmd.Flags().StringVar(&telemetryRun, "run", "latest", "recorded run whose findings to send (see 'greenlight history')")
+	telemetryOutcomeCmd.MarkFlagRequired("result")
+	telemetryCmd.AddCommand(telemetryStatusCmd, telemetryEnableCmd, telemetryDisableCmd, telemetryOutcomeCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+func runTelemetryStatus(cmd *cobra.Command, args []string) error {
+	s := telemetry.Settings()
+	onOff := func(on bool) string {
+		if on {
+			return color.New(color.FgGreen).Sprint("on")
+		}
+		return "off"
+	}
+
+	purple.Println("\n  greenlight telemetry")
+	fmt.Printf("  Rule statistics: %s\n", onOff(s.RuleStats))
+	fmt.Printf("  Review outcomes: %s\n", onOff(s.Outcomes))
+	if reason := telemetry.OffByEnv(); reason != "" {
+		color.New(color.FgYellow).Printf("  Off for this shell: %s\n", reason)
+	}
+	if s.InstallID != "" {
+		fmt.Printf("  Install ID:      %s\n", s.InstallID)
+	}
+	fmt.Printf("  Endpoint:        %s\n", telemetry.Endpoint())
+	events, err := telemetry.Queued()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Queued events:   %d\n\n", len(events))
+	return nil
+}
+
+// setTelemetry turns telemetry on or off in the config. Turning it off also
+// drops anything still queued.
+func setTelemetry(on bool) error {
+	cfg, err := config.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg, err = &config.Config{}, nil
+	}
+	if err != nil {
+		return err
+	}
+	if cfg.Telemetry == nil {
+		cfg.Telemetry = &config.TelemetrySettings{}
+	}
+	t := cfg.Telemetry
+	switch {
+	case on:
+		t.RuleStats = true
+		t.Outcomes = t.Outcomes || telemetryOutcomes
+		if t.InstallID == "" {
+			t.InstallID = telemetry.NewInstallID()
+		}
+	case telemetryOutcomes:
+		t.Outcomes = false
+	default:
+		t.RuleStats, t.Outcomes = false, false
+	}
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+	if !t.RuleStats && !t.Outcomes {
+		if err := telemetry.Clear(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case on && t.Outcomes:
+		fmt.Println("  Telemetry on: anonymized rule statistics and the review outcomes you report. Thank you!")
+	case on:
+		fmt.Println("  Telemetry on: anonymized rule statistics. Thank you!")
+		dim.Println("  Add --outcomes to also allow 'greenlight telemetry outcome'.")
+	case t.RuleStats:
+		fmt.Println("  Review outcome sharing off; rule statistics are still on.")
+	default:
+		fmt.Println("  Telemetry off. Nothing more will be sent.")
+	}
+	return nil
+}
+
+func runTelemetryOutcome(cmd *cobra.Command, args []string) error {
+	result := strings.ToLower(strings.TrimSpace(telemetryResult))
+	if result != "approved" && result != "rejected" {
+		return fmt.Errorf("--result must be approved or rejected")
+	}
+	if !telemetry.OutcomesEnabled() {
+		if reason := telemetry.OffByEnv(); reason != "" {
+			return fmt.Errorf("telemetry is off: %s", reason)
+		}
+		return fmt.Errorf("review outcome sharing is off; turn it on with 'greenlight telemetry enable --outcomes'")
+	}
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	dir, err := history.Dir(path)
+	if err != nil {
+		return err
+	}
+	runs, err := history.List(dir)
+	if err != nil {
+		return err
+	}
+	run, err := history.Find(runs, telemetryRun)
+	if err != nil {
+		return fmt.Errorf("%w; run a scan first so its findings can go with the outcome", err)
+	}
+	var ruleIDs []string
+	for _, f := range run.Findings {
+		ruleIDs = append(ruleIDs, f.RuleID)
+	}
+	var guidelines []string
+	for _, g := range telemetryGuidelines {
+		if g = strings.TrimPrefix(strings.TrimSpace(g), "§"); g != "" {
+			guidelines = append(guidelines, g)
+		}
+	}
+
+	event := telemetry.Outcome(appVersion, result, guidelines, ruleIDs, builtinRule)
+	if err := telemetry.Enqueue(event); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
+	if _, err := telemetry.Flush(ctx, http.DefaultClient); err != nil {
+		dim.Printf("  Could not send now (%v); it will be sent after a later command.\n", err)
+		return nil
+	}
+	fmt.Printf("  Sent: %s, %d guideline(s), %d rule(s) from run %s. Thank you!\n", result, len(guidelines), len(event.RuleIDs), run.ID)
+	return nil
+}
+
+// builtinRule reports whether id is in the catalog of rule IDs that ship
+// with greenlight, the only rule IDs telemetry sends. IDs derived from
+// finding titles are never in it: titles carry keywords, competitor names
+// and locales.
+func builtinRule(id string) bool {
+	return catalogRuleIDs()[id]
+}
+
+var catalogRuleIDs = sync.OnceValue(func() map[string]bool {
+	ids := map[string]bool{checks.CheckFailedRuleID: true}
+	for _, e := range allRuleEntries() {
+		ids[e.ID] = true
+	}
+	for _, c := range checks.Catalog() {
+		ids[c.RuleID] = true
+	}
+	for _, id := range preflight.RuleIDs() {
+		ids[id] = true
+	}
+	return ids
+})
+
+// recordTelemetry queues a rule_hits event for a finished run when rule
+// statistics are on.
+func recordTelemetry(command string, passed bool, findings []history.Finding) {
+	if !telemetry.RuleStatsEnabled() {
+		return
+	}
+	ruleIDs := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ruleIDs = append(ruleIDs, f.RuleID)
+	}
+	if err := telemetry.Enqueue(telemetry.RuleHits(command, appVersion, passed, ruleIDs, builtinRule)); err != nil {
+		slog.Debug("failed to queue telemetry", "error", err)
+	}
+}
+
+// flushTelemetry sends queued events at most once per FlushInterval, giving
+// up quickly so commands aren't held up by a slow network.
+func flushTelemetry() {
+	if !telemetry.RuleStatsEnabled() && !telemetry.OutcomesEnabled() {
+		return
+	}
+	if !telemetry.FlushDue() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if n, err := telemetry.Flush(ctx, http.DefaultClient); err != nil {
+		slog.Debug("failed to send telemetry", "error", err)
+	} else {
+		slog.Debug("sent telemetry", "events", n)
+	}
+}
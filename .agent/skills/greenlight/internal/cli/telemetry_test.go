@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/RevylAI/greenlight/internal/telemetry"
+)
+
+func TestBuiltinRule(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"ugc-moderation", true},
+		{"asc/metadata-completeness", true},
+		{"asc/check-failed", true},
+		{"metadata/no-app-icon", true},
+		// Title-derived IDs carry the locale and the app's own metadata.
+		{"asc/en-us-description-is-empty", false},
+		{"asc/en-us-subtitle-stacks-popular-fitness-workout", false},
+		{"xcode/marketing-version-missing-for-target-release", false},
+		{"my-team/custom-rule", false},
+	}
+	for _, tt := range tests {
+		if got := builtinRule(tt.id); got != tt.want {
+			t.Errorf("builtinRule(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestRuleHitsDropsTitleDerivedIDs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	e := telemetry.RuleHits("preflight", "dev", false, []string{
+		"asc/metadata-completeness",
+		"asc/en-us-subtitle-stacks-popular-fitness-workout",
+	}, builtinRule)
+	if len(e.Rules) != 1 || e.Rules["asc/metadata-completeness"] != 1 {
+		t.Errorf("rules = %v, want only asc/metadata-completeness", e.Rules)
+	}
+}
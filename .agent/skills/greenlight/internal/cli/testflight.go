@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tfAppID      string
+	tfBuild      string
+	tfGroups     []string
+	tfEncryption string
+	tfSkipReview bool
+)
+
+var testflightCmd = &cobra.Command{
+	Use:   "testflight",
+	Short: "Manage TestFlight distribution through App Store Connect",
+}
+
+var testflightDistributeCmd = &cobra.Command{
+	Use:   "distribute",
+	Short: "Distribute a processed build to TestFlight beta groups",
+	Long: `Distribute a processed build to one or more TestFlight beta groups.
+
+Steps:
+  1. Finds the build (latest upload if --build is omitted) and checks it has
+     finished processing
+  2. Answers export compliance if the build has no answer yet (--encryption)
+  3. Adds the build to each --group
+  4. Submits the build for beta app review when an external group is
+     included (skip with --skip-review)
+
+Usage:
+  greenlight testflight distribute --app-id 6758967212 --build 42 --group "External"
+  greenlight testflight distribute --app-id 6758967212 --group QA --group Beta --encryption exempt`,
+	RunE: runTestflightDistribute,
+}
+
+func init() {
+	testflightDistributeCmd.Flags().StringVar(&tfAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name (required)")
+	testflightDistributeCmd.Flags().StringVar(&tfBuild, "build", "", "build number to distribute (latest if omitted)")
+	testflightDistributeCmd.Flags().StringSliceVar(&tfGroups, "group", nil, "beta group name (repeatable, required)")
+	testflightDistributeCmd.Flags().StringVar(&tfEncryption, "encryption", "", "export compliance answer if the build has none: exempt, non-exempt")
+	testflightDistributeCmd.Flags().BoolVar(&tfSkipReview, "skip-review", false, "don't submit for beta app review")
+	testflightDistributeCmd.MarkFlagRequired("app-id")
+	testflightDistributeCmd.MarkFlagRequired("group")
+
+	testflightCmd.AddCommand(testflightDistributeCmd)
+	rootCmd.AddCommand(testflightCmd)
+}
+
+// newASCClient creates an API client from the stored credentials.
+func newASCClient() (*asc.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("not authenticated — run 'greenlight auth setup' first: %w", err)
+	}
+	client, err := asc.NewClient(cfg.KeyID, cfg.IssuerID, cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	return client, nil
+}
+
+// resolveBetaGroups looks up beta groups by name, case-insensitively.
+func resolveBetaGroups(ctx context.Context, client *asc.Client, appID string, names []string) ([]asc.BetaGroup, error) {
+	groups, err := client.GetBetaGroups(ctx, appID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list beta groups: %w", err)
+	}
+	var matched []asc.BetaGroup
+	for _, name := range names {
+		var match *asc.BetaGroup
+		for i := range groups {
+			if strings.EqualFold(groups[i].Attributes.Name, name) {
+				match = &groups[i]
+				break
+			}
+		}
+		if match == nil {
+			var available []string
+			for _, g := range groups {
+				available = append(available, g.Attributes.Name)
+			}
+			return nil, fmt.Errorf("beta group %q not found (available: %s)", name, strings.Join(available, ", "))
+		}
+		matched = append(matched, *match)
+	}
+	return matched, nil
+}
+
+func runTestflightDistribute(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	var usesEncryption *bool
+	switch strings.ToLower(tfEncryption) {
+	case "":
+	case "exempt", "none", "no":
+		v := false
+		usesEncryption = &v
+	case "non-exempt", "yes":
+		v := true
+		usesEncryption = &v
+	default:
+		return fmt.Errorf("invalid --encryption %q (use exempt or non-exempt)", tfEncryption)
+	}
+
+	client, err := newASCClient()
+	if err != nil {
+		return err
+	}
+	if tfAppID, err = resolveAppID(ctx, client, tfAppID); err != nil {
+		return err
+	}
+
+	purple.Println("\n  greenlight testflight distribute")
+	fmt.Printf("  App ID:  %s\n", tfAppID)
+
+	build, err := client.GetBuild(ctx, tfAppID, tfBuild)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Build:   %s (uploaded %s)\n", build.Attributes.Version, build.Attributes.UploadedDate)
+	fmt.Printf("  Groups:  %s\n\n", strings.Join(tfGroups, ", "))
+
+	if state := build.Attributes.ProcessingState; state != "VALID" {
+		return fmt.Errorf("build %s is %s — wait until processing finishes (VALID) before distributing", build.Attributes.Version, state)
+	}
+
+	groups, err := resolveBetaGroups(ctx, client, tfAppID, tfGroups)
+	if err != nil {
+		return err
+	}
+	var groupIDs []string
+	external := false
+	for _, g := range groups {
+		groupIDs = append(groupIDs, g.ID)
+		if !g.Attributes.IsInternalGroup {
+			external = true
+		}
+	}
+
+	green := color.New(color.FgGreen)
+
+	// Export compliance
+	switch {
+	case build.Attributes.UsesNonExemptEncryption != nil:
+		dim.Printf("  Export compliance already answered (non-exempt encryption: %t)\n", *build.Attributes.UsesNonExemptEncryption)
+	case usesEncryption == nil:
+		return fmt.Errorf("build %s has no export compliance answer — pass --encryption exempt or --encryption non-exempt, or set ITSAppUsesNonExemptEncryption in Info.plist", build.Attributes.Version)
+	default:
+		if err := client.SetBuildEncryption(ctx, build.ID, *usesEncryption); err != nil {
+			return fmt.Errorf("failed to set export compliance: %w", err)
+		}
+		green.Print("  ✓ ")
+		fmt.Printf("Export compliance set (non-exempt encryption: %t)\n", *usesEncryption)
+	}
+
+	// Group assignment
+	if err := client.AddBuildToBetaGroups(ctx, build.ID, groupIDs); err != nil {
+		return fmt.Errorf("failed to add build to groups: %w", err)
+	}
+	green.Print("  ✓ ")
+	fmt.Printf("Added to %s\n", strings.Join(tfGroups, ", "))
+
+	// Beta app review (external testers only)
+	switch {
+	case !external:
+		dim.Println("  Internal groups only — no beta app review needed")
+	case tfSkipReview:
+		dim.Println("  Skipped beta app review (--skip-review); external testers get the build once it is approved")
+	default:
+		existing, err := client.GetBetaAppReviewSubmission(ctx, build.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check beta review status: %w", err)
+		}
+		if existing != nil {
+			dim.Printf("  Already submitted for beta review (%s)\n", existing.Attributes.BetaReviewState)
+			break
+		}
+		sub, err := client.SubmitForBetaReview(ctx, build.ID)
+		if err != nil {
+			return fmt.Errorf("failed to submit for beta review: %w", err)
+		}
+		green.Print("  ✓ ")
+		fmt.Printf("Submitted for beta app review (%s)\n", sub.Attributes.BetaReviewState)
+	}
+
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tfFeedbackLimit     int
+	tfFeedbackCrashLogs bool
+	tfFeedbackFormat    string
+)
+
+var testflightFeedbackCmd = &cobra.Command{
+	Use:   "feedback",
+	Short: "Summarize tester crash reports and screenshot feedback per build",
+	Long: `Summarize TestFlight feedback: crashes testers reported and screenshot
+feedback, grouped by build. Crashes that testers hit are the same crashes
+App Review will hit — fix them before submitting.
+
+With --crash-logs, each crash report is downloaded and crashes are grouped
+by exception type and top frames of the crashed thread.
+
+Usage:
+  greenlight testflight feedback --app-id 6758967212
+  greenlight testflight feedback --app-id 6758967212 --build 42 --crash-logs
+  greenlight testflight feedback --app-id 6758967212 --format json`,
+	RunE: runTestflightFeedback,
+}
+
+func init() {
+	testflightFeedbackCmd.Flags().StringVar(&tfAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name (required)")
+	testflightFeedbackCmd.Flags().StringVar(&tfBuild, "build", "", "only feedback for this build number")
+	testflightFeedbackCmd.Flags().IntVar(&tfFeedbackLimit, "limit", 50, "max submissions of each kind to fetch (1-200)")
+	testflightFeedbackCmd.Flags().BoolVar(&tfFeedbackCrashLogs, "crash-logs", false, "download crash reports and group crashes by signature")
+	testflightFeedbackCmd.Flags().StringVar(&tfFeedbackFormat, "format", "terminal", "output format: terminal, json")
+	testflightFeedbackCmd.MarkFlagRequired("app-id")
+
+	testflightCmd.AddCommand(testflightFeedbackCmd)
+}
+
+// buildFeedback is the feedback for one build.
+type buildFeedback struct {
+	Build       string          `json:"build"`
+	Crashes     []feedbackEntry `json:"crashes"`
+	Screenshots []feedbackEntry `json:"screenshots"`
+	// CrashGroups is set with --crash-logs.
+	CrashGroups []crashGroup `json:"crash_groups,omitempty"`
+}
+
+type feedbackEntry struct {
+	Date        string   `json:"date"`
+	Comment     string   `json:"comment,omitempty"`
+	Tester      string   `json:"tester,omitempty"`
+	Device      string   `json:"device"`
+	OSVersion   string   `json:"os_version"`
+	Screenshots []string `json:"screenshots,omitempty"`
+}
+
+type crashGroup struct {
+	Exception string   `json:"exception"`
+	Frames    []string `json:"frames,omitempty"`
+	Count     int      `json:"count"`
+	Devices   []string `json:"devices"`
+}
+
+func runTestflightFeedback(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if tfFeedbackLimit < 1 || tfFeedbackLimit > 200 {
+		return fmt.Errorf("--limit must be between 1 and 200")
+	}
+
+	client, err := newASCClient()
+	if err != nil {
+		return err
+	}
+	if tfAppID, err = resolveAppID(cmd.Context(), client, tfAppID); err != nil {
+		return err
+	}
+
+	buildID := ""
+	if tfBuild != "" {
+		b, err := client.GetBuild(ctx, tfAppID, tfBuild)
+		if err != nil {
+			return err
+		}
+		buildID = b.ID
+	}
+
+	crashes, crashBuilds, err := client.GetBetaCrashFeedback(ctx, tfAppID, buildID, tfFeedbackLimit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch crash feedback: %w", err)
+	}
+	shots, shotBuilds, err := client.GetBetaScreenshotFeedback(ctx, tfAppID, buildID, tfFeedbackLimit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch screenshot feedback: %w", err)
+	}
+
+	versions := map[string]string{}
+	for _, b := range append(crashBuilds, shotBuilds...) {
+		versions[b.ID] = b.Attributes.Version
+	}
+	byBuild := map[string]*buildFeedback{}
+	feedbackFor := func(id string) *buildFeedback {
+		v := versions[id]
+		if v == "" {
+			v = "unknown"
+		}
+		if byBuild[v] == nil {
+			byBuild[v] = &buildFeedback{Build: v}
+		}
+		return byBuild[v]
+	}
+	entry := func(a asc.BetaFeedbackAttributes) feedbackEntry {
+		e := feedbackEntry{
+			Date:      a.CreatedDate,
+			Comment:   a.Comment,
+			Tester:    a.Email,
+			Device:    a.DeviceModel,
+			OSVersion: a.OSVersion,
+		}
+		for _, s := range a.Screenshots {
+			e.Screenshots = append(e.Screenshots, s.URL)
+		}
+		return e
+	}
+
+	groups := map[string]map[string]*crashGroup{} // build -> signature -> group
+	for _, c := range crashes {
+		fb := feedbackFor(c.Relationships.BuildID())
+		fb.Crashes = append(fb.Crashes, entry(c.Attributes))
+
+		if !tfFeedbackCrashLogs {
+			continue
+		}
+		exception, frames := "unknown (no crash log)", []string(nil)
+		if log, err := client.GetBetaCrashLog(ctx, c.ID); err == nil {
+			if e, f := log.CrashSignature(3); e != "" || len(f) > 0 {
+				exception, frames = e, f
+			}
+		}
+		sig := exception + "\n" + strings.Join(frames, "\n")
+		if groups[fb.Build] == nil {
+			groups[fb.Build] = map[string]*crashGroup{}
+		}
+		g := groups[fb.Build][sig]
+		if g == nil {
+			g = &crashGroup{Exception: exception, Frames: frames}
+			groups[fb.Build][sig] = g
+		}
+		g.Count++
+		g.Devices = appendUnique(g.Devices, c.Attributes.DeviceModel+" / iOS "+c.Attributes.OSVersion)
+	}
+	for _, s := range shots {
+		fb := feedbackFor(s.Relationships.BuildID())
+		fb.Screenshots = append(fb.Screenshots, entry(s.Attributes))
+	}
+
+	var summary []*buildFeedback
+	for build, fb := range byBuild {
+		for _, g := range groups[build] {
+			fb.CrashGroups = append(fb.CrashGroups, *g)
+		}
+		sort.Slice(fb.CrashGroups, func(i, j int) bool { return fb.CrashGroups[i].Count > fb.CrashGroups[j].Count })
+		summary = append(summary, fb)
+	}
+	sort.Slice(summary, func(i, j int) bool { return compareBuildNumbers(summary[i].Build, summary[j].Build) > 0 })
+
+	if strings.ToLower(tfFeedbackFormat) == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	writeFeedbackTerminal(summary)
+	return nil
+}
+
+func writeFeedbackTerminal(summary []*buildFeedback) {
+	red := color.New(color.FgRed, color.Bold)
+	yellow := color.New(color.FgYellow)
+	bold := color.New(color.Bold)
+
+	purple.Println("\n  greenlight testflight feedback")
+	fmt.Printf("  App ID:  %s\n\n", tfAppID)
+
+	if len(summary) == 0 {
+		color.New(color.FgGreen, color.Bold).Println("  No tester feedback yet.")
+		fmt.Println()
+		return
+	}
+
+	for _, fb := range summary {
+		bold.Printf("  Build %s", fb.Build)
+		fmt.Printf(" — %d crash(es), %d screenshot report(s)\n\n", len(fb.Crashes), len(fb.Screenshots))
+
+		for _, g := range fb.CrashGroups {
+			red.Printf("  [CRASH ×%d] ", g.Count)
+			bold.Println(g.Exception)
+			for _, f := range g.Frames {
+				dim.Printf("             %s\n", f)
+			}
+			dim.Printf("             %s\n", strings.Join(g.Devices, ", "))
+		}
+		if len(fb.CrashGroups) == 0 {
+			for _, c := range fb.Crashes {
+				red.Print("  [CRASH]    ")
+				fmt.Printf("%s  %s / iOS %s\n", c.Date, c.Device, c.OSVersion)
+				if c.Comment != "" {
+					fmt.Printf("             “%s”\n", c.Comment)
+				}
+			}
+		}
+		for _, s := range fb.Screenshots {
+			yellow.Print("  [FEEDBACK] ")
+			fmt.Printf("%s  %s / iOS %s\n", s.Date, s.Device, s.OSVersion)
+			if s.Comment != "" {
+				fmt.Printf("             “%s”\n", s.Comment)
+			}
+			for _, u := range s.Screenshots {
+				dim.Printf("             %s\n", u)
+			}
+		}
+		fmt.Println()
+	}
+
+	dim.Println("  Screenshot URLs expire; download what you need.")
+	fmt.Println()
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// compareBuildNumbers compares dotted build numbers numerically.
+func compareBuildNumbers(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			fmt.Sscanf(pa[i], "%d", &x)
+		}
+		if i < len(pb) {
+			fmt.Sscanf(pb[i], "%d", &y)
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,477 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tfTesterEmails    []string
+	tfTesterFirstName string
+	tfTesterLastName  string
+	tfTesterCSV       string
+	tfTesterFormat    string
+	tfLinkLimit       int
+	tfLinkDisable     bool
+)
+
+var testflightTestersCmd = &cobra.Command{
+	Use:   "testers",
+	Short: "Add, remove, and list TestFlight beta testers",
+}
+
+var testflightTestersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the app's beta testers",
+	Long: `List the app's beta testers, or only one group's with --group.
+
+Usage:
+  greenlight testflight testers list --app-id 6758967212
+  greenlight testflight testers list --app-id 6758967212 --group QA --format json`,
+	RunE: runTestersList,
+}
+
+var testflightTestersAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Invite testers and assign them to beta groups",
+	Long: `Invite testers and assign them to one or more beta groups. New testers
+receive a TestFlight invitation; existing testers are added to the groups.
+
+--csv imports testers from a file with email, first name and last name
+columns. A header row (e.g. "Email,First Name,Last Name") sets the column
+order; without one, the column containing an @ is the email and the others
+are first and last name — the App Store Connect import template works as-is.
+
+Usage:
+  greenlight testflight testers add --app-id 6758967212 --group QA --email jane@example.com --first-name Jane
+  greenlight testflight testers add --app-id 6758967212 --group Beta --csv testers.csv`,
+	RunE: runTestersAdd,
+}
+
+var testflightTestersRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove testers from beta groups or from the app",
+	Long: `Remove testers from the given beta groups, or revoke their access to the
+app entirely when no --group is given.
+
+Usage:
+  greenlight testflight testers remove --app-id 6758967212 --email jane@example.com
+  greenlight testflight testers remove --app-id 6758967212 --group QA --csv former.csv`,
+	RunE: runTestersRemove,
+}
+
+var testflightPublicLinkCmd = &cobra.Command{
+	Use:   "public-link",
+	Short: "Create a public TestFlight link for an external beta group",
+	Long: `Enable a public TestFlight link for an external beta group and print it.
+Anyone with the link can join the group, up to --limit testers.
+
+Usage:
+  greenlight testflight public-link --app-id 6758967212 --group Beta
+  greenlight testflight public-link --app-id 6758967212 --group Beta --limit 500
+  greenlight testflight public-link --app-id 6758967212 --group Beta --disable`,
+	RunE: runPublicLink,
+}
+
+func init() {
+	testflightTestersListCmd.Flags().StringVar(&tfAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name (required)")
+	testflightTestersListCmd.Flags().StringSliceVar(&tfGroups, "group", nil, "only testers in this beta group")
+	testflightTestersListCmd.Flags().StringVar(&tfTesterFormat, "format", "terminal", "output format: terminal, json")
+	testflightTestersListCmd.MarkFlagRequired("app-id")
+
+	testflightTestersAddCmd.Flags().StringVar(&tfAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name (required)")
+	testflightTestersAddCmd.Flags().StringSliceVar(&tfGroups, "group", nil, "beta group name (repeatable, required)")
+	testflightTestersAddCmd.Flags().StringSliceVar(&tfTesterEmails, "email", nil, "tester email (repeatable)")
+	testflightTestersAddCmd.Flags().StringVar(&tfTesterFirstName, "first-name", "", "first name for a single --email")
+	testflightTestersAddCmd.Flags().StringVar(&tfTesterLastName, "last-name", "", "last name for a single --email")
+	testflightTestersAddCmd.Flags().StringVar(&tfTesterCSV, "csv", "", "import testers from a CSV file")
+	testflightTestersAddCmd.MarkFlagRequired("app-id")
+	testflightTestersAddCmd.MarkFlagRequired("group")
+
+	testflightTestersRemoveCmd.Flags().StringVar(&tfAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name (required)")
+	testflightTestersRemoveCmd.Flags().StringSliceVar(&tfGroups, "group", nil, "remove only from this beta group (repeatable)")
+	testflightTestersRemoveCmd.Flags().StringSliceVar(&tfTesterEmails, "email", nil, "tester email (repeatable)")
+	testflightTestersRemoveCmd.Flags().StringVar(&tfTesterCSV, "csv", "", "read tester emails from a CSV file")
+	testflightTestersRemoveCmd.MarkFlagRequired("app-id")
+
+	testflightPublicLinkCmd.Flags().StringVar(&tfAppID, "app-id", "", "App Store Connect app ID, bundle ID or app name (required)")
+	testflightPublicLinkCmd.Flags().StringSliceVar(&tfGroups, "group", nil, "external beta group name (required)")
+	testflightPublicLinkCmd.Flags().IntVar(&tfLinkLimit, "limit", 0, "max testers who can join through the link (1-10000, no limit if omitted)")
+	testflightPublicLinkCmd.Flags().BoolVar(&tfLinkDisable, "disable", false, "turn the public link off")
+	testflightPublicLinkCmd.MarkFlagRequired("app-id")
+	testflightPublicLinkCmd.MarkFlagRequired("group")
+
+	testflightTestersCmd.AddCommand(testflightTestersListCmd, testflightTestersAddCmd, testflightTestersRemoveCmd)
+	testflightCmd.AddCommand(testflightTestersCmd, testflightPublicLinkCmd)
+}
+
+// testerEntry is a tester to add or remove.
+type testerEntry struct {
+	Email     string
+	FirstName string
+	LastName  string
+}
+
+// collectTesters merges --email flags and the --csv file.
+func collectTesters() ([]testerEntry, error) {
+	var entries []testerEntry
+	for _, email := range tfTesterEmails {
+		entries = append(entries, testerEntry{Email: strings.TrimSpace(email)})
+	}
+	if len(entries) == 1 {
+		entries[0].FirstName = tfTesterFirstName
+		entries[0].LastName = tfTesterLastName
+	}
+	if tfTesterCSV != "" {
+		f, err := os.Open(tfTesterCSV)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		imported, err := readTestersCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tfTesterCSV, err)
+		}
+		entries = append(entries, imported...)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no testers given — pass --email or --csv")
+	}
+	return entries, nil
+}
+
+// readTestersCSV parses email, first name and last name columns. A header
+// row sets the column order; otherwise the column containing an @ is the
+// email and the remaining columns are first and last name.
+func readTestersCSV(r io.Reader) ([]testerEntry, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	emailCol, firstCol, lastCol := -1, -1, -1
+	if len(records) > 0 {
+		for i, cell := range records[0] {
+			switch strings.ToLower(strings.ReplaceAll(strings.TrimSpace(cell), " ", "")) {
+			case "email", "emailaddress":
+				emailCol = i
+			case "firstname", "first", "givenname":
+				firstCol = i
+			case "lastname", "last", "surname", "familyname":
+				lastCol = i
+			}
+		}
+		if emailCol >= 0 {
+			records = records[1:]
+		}
+	}
+
+	cell := func(rec []string, i int) string {
+		if i < 0 || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var entries []testerEntry
+	for n, rec := range records {
+		if emailCol >= 0 {
+			if email := cell(rec, emailCol); email != "" {
+				entries = append(entries, testerEntry{Email: email, FirstName: cell(rec, firstCol), LastName: cell(rec, lastCol)})
+			}
+			continue
+		}
+		var e testerEntry
+		var names []string
+		for i := range rec {
+			v := cell(rec, i)
+			if strings.Contains(v, "@") && e.Email == "" {
+				e.Email = v
+			} else {
+				names = append(names, v)
+			}
+		}
+		if e.Email == "" {
+			if strings.TrimSpace(strings.Join(rec, "")) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: no email address", n+1)
+		}
+		if len(names) > 0 {
+			e.FirstName = names[0]
+		}
+		if len(names) > 1 {
+			e.LastName = names[1]
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func runTestersList(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if len(tfGroups) > 1 {
+		return fmt.Errorf("list takes a single --group")
+	}
+	client, err := newASCClient()
+	if err != nil {
+		return err
+	}
+	if tfAppID, err = resolveAppID(cmd.Context(), client, tfAppID); err != nil {
+		return err
+	}
+
+	groupID := ""
+	if len(tfGroups) == 1 {
+		groups, err := resolveBetaGroups(ctx, client, tfAppID, tfGroups)
+		if err != nil {
+			return err
+		}
+		groupID = groups[0].ID
+	}
+
+	testers, err := client.ListBetaTesters(ctx, tfAppID, groupID)
+	if err != nil {
+		return fmt.Errorf("failed to list testers: %w", err)
+	}
+
+	if tfTesterFormat == "json" {
+		type jsonTester struct {
+			Email      string `json:"email"`
+			FirstName  string `json:"first_name,omitempty"`
+			LastName   string `json:"last_name,omitempty"`
+			InviteType string `json:"invite_type"`
+			State      string `json:"state"`
+		}
+		out := []jsonTester{}
+		for _, t := range testers {
+			out = append(out, jsonTester{
+				Email:      t.Attributes.Email,
+				FirstName:  t.Attributes.FirstName,
+				LastName:   t.Attributes.LastName,
+				InviteType: t.Attributes.InviteType,
+				State:      t.Attributes.State,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	purple.Println("\n  greenlight testflight testers")
+	fmt.Printf("  App ID:  %s\n", tfAppID)
+	if groupID != "" {
+		fmt.Printf("  Group:   %s\n", tfGroups[0])
+	}
+	fmt.Printf("  Testers: %d\n\n", len(testers))
+
+	for _, t := range testers {
+		name := strings.TrimSpace(t.Attributes.FirstName + " " + t.Attributes.LastName)
+		email := t.Attributes.Email
+		if email == "" {
+			email = "(anonymous)"
+		}
+		fmt.Printf("  %-36s %-24s ", email, name)
+		dim.Printf("%-12s %s\n", t.Attributes.State, t.Attributes.InviteType)
+	}
+	if len(testers) > 0 {
+		fmt.Println()
+	}
+	return nil
+}
+
+func runTestersAdd(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	entries, err := collectTesters()
+	if err != nil {
+		return err
+	}
+	client, err := newASCClient()
+	if err != nil {
+		return err
+	}
+	if tfAppID, err = resolveAppID(cmd.Context(), client, tfAppID); err != nil {
+		return err
+	}
+	groups, err := resolveBetaGroups(ctx, client, tfAppID, tfGroups)
+	if err != nil {
+		return err
+	}
+	var groupIDs []string
+	for _, g := range groups {
+		groupIDs = append(groupIDs, g.ID)
+	}
+
+	purple.Println("\n  greenlight testflight testers add")
+	fmt.Printf("  App ID:  %s\n", tfAppID)
+	fmt.Printf("  Groups:  %s\n", strings.Join(tfGroups, ", "))
+	fmt.Printf("  Testers: %d\n\n", len(entries))
+
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+	failed := 0
+	for _, e := range entries {
+		err := addTester(ctx, client, e, groupIDs)
+		if err != nil {
+			failed++
+			red.Print("  ✗ ")
+			fmt.Printf("%s: %v\n", e.Email, err)
+			continue
+		}
+		green.Print("  ✓ ")
+		fmt.Println(e.Email)
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d testers could not be added", failed, len(entries))
+	}
+	return nil
+}
+
+// addTester invites a new tester into the groups, or adds an existing one.
+func addTester(ctx context.Context, client *asc.Client, e testerEntry, groupIDs []string) error {
+	existing, err := client.FindBetaTester(ctx, tfAppID, e.Email)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, err := client.CreateBetaTester(ctx, e.Email, e.FirstName, e.LastName, groupIDs)
+		return err
+	}
+	for _, id := range groupIDs {
+		if err := client.AddBetaTestersToGroup(ctx, id, []string{existing.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runTestersRemove(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	entries, err := collectTesters()
+	if err != nil {
+		return err
+	}
+	client, err := newASCClient()
+	if err != nil {
+		return err
+	}
+	if tfAppID, err = resolveAppID(cmd.Context(), client, tfAppID); err != nil {
+		return err
+	}
+	var groups []asc.BetaGroup
+	if len(tfGroups) > 0 {
+		if groups, err = resolveBetaGroups(ctx, client, tfAppID, tfGroups); err != nil {
+			return err
+		}
+	}
+
+	purple.Println("\n  greenlight testflight testers remove")
+	fmt.Printf("  App ID:  %s\n", tfAppID)
+	if len(groups) > 0 {
+		fmt.Printf("  Groups:  %s\n\n", strings.Join(tfGroups, ", "))
+	} else {
+		fmt.Printf("  Groups:  all (access to the app is revoked)\n\n")
+	}
+
+	var ids, emails []string
+	for _, e := range entries {
+		t, err := client.FindBetaTester(ctx, tfAppID, e.Email)
+		if err != nil {
+			return fmt.Errorf("failed to look up %s: %w", e.Email, err)
+		}
+		if t == nil {
+			dim.Printf("  – %s is not a tester of this app\n", e.Email)
+			continue
+		}
+		ids = append(ids, t.ID)
+		emails = append(emails, e.Email)
+	}
+	if len(ids) == 0 {
+		fmt.Println()
+		return nil
+	}
+
+	if len(groups) == 0 {
+		if err := client.RemoveBetaTestersFromApp(ctx, tfAppID, ids); err != nil {
+			return fmt.Errorf("failed to remove testers: %w", err)
+		}
+	}
+	for _, g := range groups {
+		if err := client.RemoveBetaTestersFromGroup(ctx, g.ID, ids); err != nil {
+			return fmt.Errorf("failed to remove testers from %s: %w", g.Attributes.Name, err)
+		}
+	}
+
+	green := color.New(color.FgGreen)
+	for _, email := range emails {
+		green.Print("  ✓ ")
+		fmt.Println(email)
+	}
+	fmt.Println()
+	return nil
+}
+
+func runPublicLink(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if len(tfGroups) != 1 {
+		return fmt.Errorf("public-link takes a single --group")
+	}
+	if tfLinkLimit < 0 || tfLinkLimit > 10000 {
+		return fmt.Errorf("--limit must be between 1 and 10000")
+	}
+	client, err := newASCClient()
+	if err != nil {
+		return err
+	}
+	if tfAppID, err = resolveAppID(cmd.Context(), client, tfAppID); err != nil {
+		return err
+	}
+	groups, err := resolveBetaGroups(ctx, client, tfAppID, tfGroups)
+	if err != nil {
+		return err
+	}
+	group := groups[0]
+	if group.Attributes.IsInternalGroup {
+		return fmt.Errorf("%s is an internal group — public links are only available for external groups", group.Attributes.Name)
+	}
+
+	updated, err := client.SetBetaGroupPublicLink(ctx, group.ID, !tfLinkDisable, tfLinkLimit)
+	if err != nil {
+		return fmt.Errorf("failed to update public link: %w", err)
+	}
+
+	purple.Println("\n  greenlight testflight public-link")
+	fmt.Printf("  Group:   %s\n\n", group.Attributes.Name)
+	green := color.New(color.FgGreen)
+	if tfLinkDisable {
+		green.Print("  ✓ ")
+		fmt.Println("Public link disabled")
+		fmt.Println()
+		return nil
+	}
+	green.Print("  ✓ ")
+	fmt.Println("Public link enabled")
+	if updated.Attributes.PublicLink != "" {
+		fmt.Print("  ")
+		color.New(color.Underline).Println(updated.Attributes.PublicLink)
+	}
+	if updated.Attributes.PublicLinkLimit > 0 {
+		dim.Printf("  Limited to %d testers\n", updated.Attributes.PublicLinkLimit)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,609 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/pkg/preflight"
+	"github.com/RevylAI/greenlight/pkg/severity"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	tuiIPA    string
+	tuiReport string
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui [path]",
+	Short: "Browse preflight findings and their guidelines interactively",
+	Long: `Run preflight and browse the findings in an interactive list instead of
+scrolling through terminal output. Filter by severity, read the full
+guideline behind each finding, and open the offending file at the line in
+$VISUAL / $EDITOR.
+
+Keys:
+  ↑/↓ j/k        move            enter       finding details + guideline
+  PgUp/PgDn      page            o           open file in editor
+  g/G            first/last      esc/←       back to the list
+  f              cycle filter: all → CRITICAL → WARN → INFO
+  q              quit
+
+Usage:
+  greenlight tui .
+  greenlight tui ./my-app --ipa build.ipa
+  greenlight tui --report preflight.json    # browse a saved JSON report`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTUI,
+}
+
+func init() {
+	tuiCmd.Flags().StringVar(&tuiIPA, "ipa", "", "path to .ipa file (or .xcarchive) for binary inspection")
+	tuiCmd.Flags().StringVar(&tuiReport, "report", "", "browse a saved 'preflight --format json' report instead of scanning")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("tui needs an interactive terminal; use 'greenlight preflight' in scripts and CI")
+	}
+
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	var result *preflight.Result
+	if tuiReport != "" {
+		var err error
+		if result, err = readPreflightReport(tuiReport); err != nil {
+			return err
+		}
+		if len(args) == 0 && result.ProjectPath != "" {
+			path = result.ProjectPath
+		}
+	} else {
+		if _, err := loadRuleOverrides(path); err != nil {
+			return err
+		}
+		fmt.Printf("  Scanning %s...\n", path)
+		var err error
+		result, err = preflight.Run(cmd.Context(), path, tuiIPA, preflight.BuildSelection{})
+		if err != nil {
+			return fmt.Errorf("preflight failed: %w", err)
+		}
+	}
+
+	db, err := guidelines.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load guidelines: %w", err)
+	}
+
+	findings := result.Findings
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+
+	ui := &tui{
+		projectPath: path,
+		findings:    findings,
+		guidelines:  db,
+		keys:        newKeyReader(os.Stdin),
+	}
+	ui.applyFilter()
+	return ui.run()
+}
+
+// escTimeout is how long after an ESC byte the rest of an escape sequence
+// may take to arrive before the ESC is read as the esc key. Sequences can
+// arrive split over ssh, so waiting on what is already buffered isn't
+// enough.
+const escTimeout = 50 * time.Millisecond
+
+// tuiFilters are the severity filters cycled with "f"; "" shows everything.
+var tuiFilters = []string{"", "CRITICAL", "WARN", "INFO"}
+
+// tui draws with ANSI escape sequences and golang.org/x/term rather than a
+// TUI framework such as bubbletea: one list and one detail screen don't
+// justify the dependency tree in a CLI that is also imported as a library.
+type tui struct {
+	projectPath string
+	findings    []preflight.Finding
+	guidelines  *guidelines.DB
+	keys        *keyReader
+
+	filter  int   // index into tuiFilters
+	visible []int // indexes into findings matching the filter
+	cursor  int   // index into visible
+	offset  int   // first visible row in the list
+
+	detail       bool
+	detailOffset int
+	status       string
+
+	width, height int
+}
+
+func (t *tui) run() error {
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return err
+	}
+	// Alternate screen, hidden cursor.
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		term.Restore(int(os.Stdin.Fd()), state)
+	}()
+
+	for {
+		t.width, t.height, err = term.GetSize(int(os.Stdout.Fd()))
+		if err != nil || t.width < 20 || t.height < 5 {
+			t.width, t.height = 80, 24
+		}
+		t.draw()
+
+		key, err := t.readKey()
+		if err != nil {
+			return nil
+		}
+		t.status = ""
+		if key == "q" || key == "ctrl-c" {
+			return nil
+		}
+		if key == "o" {
+			t.openInEditor(state)
+			continue
+		}
+		if t.detail {
+			t.detailKey(key)
+		} else {
+			t.listKey(key)
+		}
+	}
+}
+
+// readKey reads one keypress, decoding the arrow and paging escape
+// sequences.
+func (t *tui) readKey() (string, error) {
+	b, _, err := t.keys.next(0)
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 3:
+		return "ctrl-c", nil
+	case '\r', '\n':
+		return "enter", nil
+	case 127, 8:
+		return "backspace", nil
+	case 0x1b:
+		seq := []byte{}
+		for {
+			c, ok, err := t.keys.next(escTimeout)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				break
+			}
+			if len(seq) == 0 && c != '[' && c != 'O' {
+				// ESC then another key, typed quickly.
+				t.keys.unread(c)
+				break
+			}
+			seq = append(seq, c)
+			if (c >= 'A' && c <= 'Z' && len(seq) > 1) || c == '~' {
+				break
+			}
+		}
+		switch string(seq) {
+		case "[A", "OA":
+			return "up", nil
+		case "[B", "OB":
+			return "down", nil
+		case "[C", "OC":
+			return "right", nil
+		case "[D", "OD":
+			return "left", nil
+		case "[5~":
+			return "pgup", nil
+		case "[6~":
+			return "pgdn", nil
+		case "[H", "[1~":
+			return "home", nil
+		case "[F", "[4~":
+			return "end", nil
+		}
+		return "esc", nil
+	}
+	return string(b), nil
+}
+
+func (t *tui) applyFilter() {
+	t.visible = t.visible[:0]
+	for i, f := range t.findings {
+		if sev := tuiFilters[t.filter]; sev == "" || f.Severity.String() == sev {
+			t.visible = append(t.visible, i)
+		}
+	}
+	t.cursor, t.offset = 0, 0
+}
+
+func (t *tui) listRows() int {
+	return t.height - 4 // header (2) + footer (2)
+}
+
+func (t *tui) listKey(key string) {
+	page := max(t.listRows()-1, 1)
+	switch key {
+	case "up", "k":
+		t.cursor--
+	case "down", "j":
+		t.cursor++
+	case "pgup":
+		t.cursor -= page
+	case "pgdn", " ":
+		t.cursor += page
+	case "home", "g":
+		t.cursor = 0
+	case "end", "G":
+		t.cursor = len(t.visible) - 1
+	case "f":
+		t.filter = (t.filter + 1) % len(tuiFilters)
+		t.applyFilter()
+	case "enter", "right", "l":
+		if len(t.visible) > 0 {
+			t.detail, t.detailOffset = true, 0
+		}
+	}
+	t.cursor = max(0, min(t.cursor, len(t.visible)-1))
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if rows := t.listRows(); t.cursor >= t.offset+rows {
+		t.offset = t.cursor - rows + 1
+	}
+}
+
+func (t *tui) detailKey(key string) {
+	switch key {
+	case "esc", "left", "h", "backspace":
+		t.detail = false
+	case "up", "k":
+		t.detailOffset--
+	case "down", "j":
+		t.detailOffset++
+	case "pgup":
+		t.detailOffset -= t.height - 3
+	case "pgdn", " ":
+		t.detailOffset += t.height - 3
+	case "home", "g":
+		t.detailOffset = 0
+	}
+	t.detailOffset = max(t.detailOffset, 0)
+}
+
+func (t *tui) current() *preflight.Finding {
+	if len(t.visible) == 0 {
+		return nil
+	}
+	return &t.findings[t.visible[t.cursor]]
+}
+
+func (t *tui) draw() {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	if t.detail {
+		t.drawDetail(&b)
+	} else {
+		t.drawList(&b)
+	}
+	fmt.Print(b.String())
+}
+
+func (t *tui) drawList(b *strings.Builder) {
+	bold := color.New(color.Bold)
+	filter := tuiFilters[t.filter]
+	if filter == "" {
+		filter = "all"
+	}
+	t.line(b, purple.Sprint(" greenlight")+fmt.Sprintf("  %s — %d finding(s), showing %s (%d)", t.projectPath, len(t.findings), filter, len(t.visible)))
+	t.line(b, "")
+
+	rows := t.listRows()
+	if len(t.visible) == 0 {
+		t.line(b, color.New(color.FgGreen, color.Bold).Sprint("  No findings."))
+		rows--
+	}
+	for r := 0; r < rows; r++ {
+		i := t.offset + r
+		if i >= len(t.visible) {
+			t.line(b, "")
+			continue
+		}
+		f := t.findings[t.visible[i]]
+		loc := ""
+		if f.File != "" {
+			loc = f.File
+			if f.Line > 0 {
+				loc += ":" + strconv.Itoa(f.Line)
+			}
+		}
+		text := fmt.Sprintf(" %-8s %-9s %s", f.Severity, f.Source, f.Title)
+		if f.Guideline != "" {
+			text += "  §" + f.Guideline
+		}
+		avail := t.width - utf8.RuneCountInString(text) - 3
+		if loc != "" && avail > 10 {
+			text += "  " + truncateLeft(loc, avail)
+		}
+		text = truncateRunes(text, t.width-1)
+		if i == t.cursor {
+			t.line(b, "\x1b[7m"+padRunes(text, t.width-1)+"\x1b[0m")
+		} else {
+			t.line(b, severityColor(f.Severity).Sprint(text))
+		}
+	}
+
+	t.footer(b, bold.Sprint(" ↑↓")+" move  "+bold.Sprint("enter")+" details  "+bold.Sprint("o")+" open  "+bold.Sprint("f")+" filter  "+bold.Sprint("q")+" quit")
+}
+
+func (t *tui) drawDetail(b *strings.Builder) {
+	f := t.current()
+	bold := color.New(color.Bold)
+	var lines []string
+	add := func(s string) { lines = append(lines, wrapText(s, t.width-4)...) }
+
+	lines = append(lines, severityColor(f.Severity).Sprint(f.Severity)+"  "+bold.Sprint(f.Title))
+	meta := f.Source
+	if f.RuleID != "" {
+		meta += " · " + findingid.Display(f.RuleID, f.Fingerprint)
+	}
+	if f.File != "" {
+		meta += " · " + f.File
+		if f.Line > 0 {
+			meta += ":" + strconv.Itoa(f.Line)
+		}
+	}
+	lines = append(lines, dim.Sprint(meta), "")
+	add(f.Detail)
+	if f.Code != "" {
+		lines = append(lines, "", dim.Sprint("  "+strings.TrimSpace(f.Code)))
+	}
+	if f.Fix != "" {
+		lines = append(lines, "", color.New(color.FgGreen).Sprint("Fix"))
+		add(f.Fix)
+	}
+
+	if f.Guideline != "" {
+		lines = append(lines, "")
+		if g := t.guidelines.Lookup(f.Guideline); g != nil {
+			lines = append(lines, purple.Sprint("Guideline "+g.Section)+"  "+bold.Sprint(g.Title))
+			add(g.Content)
+			if len(g.CommonViolations) > 0 {
+				lines = append(lines, "", color.New(color.FgYellow).Sprint("Common violations"))
+				for _, v := range g.CommonViolations {
+					add("• " + v)
+				}
+			}
+			for _, s := range g.Subsections {
+				lines = append(lines, "")
+				lines = append(lines, bold.Sprint(s.Section+"  "+s.Title))
+				add(s.Content)
+			}
+		} else {
+			lines = append(lines, purple.Sprint("Guideline "+f.Guideline))
+		}
+	}
+
+	rows := t.height - 2
+	t.detailOffset = min(t.detailOffset, max(len(lines)-rows, 0))
+	for r := 0; r < rows; r++ {
+		if i := t.detailOffset + r; i < len(lines) {
+			t.line(b, "  "+lines[i])
+		} else {
+			t.line(b, "")
+		}
+	}
+	t.footer(b, bold.Sprint(" ↑↓")+" scroll  "+bold.Sprint("esc")+" back  "+bold.Sprint("o")+" open  "+bold.Sprint("q")+" quit")
+}
+
+func (t *tui) line(b *strings.Builder, s string) {
+	b.WriteString(s)
+	b.WriteString("\x1b[K\r\n")
+}
+
+func (t *tui) footer(b *strings.Builder, keys string) {
+	if t.status != "" {
+		keys = color.New(color.FgYellow).Sprint(" " + t.status)
+	}
+	b.WriteString("\x1b[K\r\n" + keys + "\x1b[K")
+}
+
+// keyReader reads keypresses from a terminal in raw mode, with a timeout.
+// A goroutine makes one read at a time, only when asked, so nothing is
+// read from the terminal while the UI is suspended for an editor.
+type keyReader struct {
+	req     chan struct{}
+	res     chan keyRead
+	pending bool   // a read was asked for and hasn't returned
+	buf     []byte // bytes read and not yet consumed
+}
+
+type keyRead struct {
+	data []byte
+	err  error
+}
+
+func newKeyReader(r io.Reader) *keyReader {
+	k := &keyReader{req: make(chan struct{}), res: make(chan keyRead)}
+	go func() {
+		buf := make([]byte, 64)
+		for range k.req {
+			n, err := r.Read(buf)
+			k.res <- keyRead{append([]byte(nil), buf[:n]...), err}
+		}
+	}()
+	return k
+}
+
+// next returns the next byte. With a timeout, ok is false when no byte
+// arrives in time; the read stays pending for the next call.
+func (k *keyReader) next(timeout time.Duration) (b byte, ok bool, err error) {
+	for len(k.buf) == 0 {
+		if !k.pending {
+			k.req <- struct{}{}
+			k.pending = true
+		}
+		var r keyRead
+		if timeout > 0 {
+			select {
+			case r = <-k.res:
+			case <-time.After(timeout):
+				return 0, false, nil
+			}
+		} else {
+			r = <-k.res
+		}
+		k.pending = false
+		if len(r.data) == 0 && r.err != nil {
+			return 0, false, r.err
+		}
+		k.buf = r.data
+	}
+	b, k.buf = k.buf[0], k.buf[1:]
+	return b, true, nil
+}
+
+// unread puts b back to be returned by the next call to next.
+func (k *keyReader) unread(b byte) {
+	k.buf = append([]byte{b}, k.buf...)
+}
+
+// openInEditor suspends the UI and opens the current finding's file at its
+// line.
+func (t *tui) openInEditor(state *term.State) {
+	f := t.current()
+	if f == nil || f.File == "" {
+		t.status = "This finding has no file."
+		return
+	}
+	file := f.File
+	if !filepath.IsAbs(file) {
+		if _, err := os.Stat(file); err != nil {
+			file = filepath.Join(t.projectPath, file)
+		}
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.status = "File not found: " + file
+		return
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], editorArgs(parts[0], file, f.Line)...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	term.Restore(int(os.Stdin.Fd()), state)
+	err := cmd.Run()
+	term.MakeRaw(int(os.Stdin.Fd()))
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	if err != nil {
+		t.status = fmt.Sprintf("%s: %v", parts[0], err)
+	}
+}
+
+// editorArgs builds the arguments that open file at line for common editors.
+func editorArgs(editor, file string, line int) []string {
+	if line <= 0 {
+		return []string{file}
+	}
+	switch filepath.Base(editor) {
+	case "code", "code-insiders", "cursor", "codium", "windsurf":
+		return []string{"--goto", fmt.Sprintf("%s:%d", file, line)}
+	case "subl", "zed":
+		return []string{fmt.Sprintf("%s:%d", file, line)}
+	case "mate", "xed":
+		return []string{"--line", strconv.Itoa(line), file}
+	default:
+		// vi, vim, nvim, nano, emacs, micro, hx...
+		return []string{"+" + strconv.Itoa(line), file}
+	}
+}
+
+func severityColor(sev severity.Level) *color.Color {
+	switch sev {
+	case severity.Critical:
+		return color.New(color.FgRed, color.Bold)
+	case severity.Warn:
+		return color.New(color.FgYellow)
+	default:
+		return dim
+	}
+}
+
+// wrapText wraps s at width on spaces.
+func wrapText(s string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func truncateRunes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func truncateLeft(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return "…" + string(r[len(r)-n+1:])
+}
+
+func padRunes(s string, n int) string {
+	if pad := n - utf8.RuneCountInString(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+// Package codeowners reads a repository's CODEOWNERS file (GitHub, GitLab
+// and Bitbucket syntax) and finds the owners of a path, so findings can be
+// routed to the team that owns the offending file.
+package codeowners
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/pathglob"
+)
+
+// locations are where CODEOWNERS files are looked for in a repository, in
+// the order GitHub uses them.
+var locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Path is the CODEOWNERS file.
+	Path  string
+	rules []rule
+	// prefix is the scanned project's path within the repository, which
+	// finding paths are relative to.
+	prefix string
+}
+
+type rule struct {
+	pattern string
+	owners  []string
+}
+
+// Load finds the CODEOWNERS file of the repository containing projectPath,
+// looking in the project and each parent up to the repository root (the
+// directory with .git). It returns nil, nil when there is none.
+func Load(projectPath string) (*File, error) {
+	abs, err := filepath.Abs(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		for _, loc := range locations {
+			p := filepath.Join(dir, filepath.FromSlash(loc))
+			f, err := os.Open(p)
+			if err != nil {
+				continue
+			}
+			owners := Parse(f)
+			f.Close()
+			owners.Path = p
+			if rel, err := filepath.Rel(dir, abs); err == nil && rel != "." {
+				owners.prefix = filepath.ToSlash(rel)
+			}
+			return owners, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil || filepath.Dir(dir) == dir {
+			return nil, nil
+		}
+	}
+}
+
+// Parse reads CODEOWNERS rules: a pattern followed by owners per line,
+// "#" comments and GitLab "[Section]" headers ignored.
+func Parse(r io.Reader) *File {
+	f := &File{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		f.rules = append(f.rules, rule{pattern: fields[0], owners: fields[1:]})
+	}
+	return f
+}
+
+// Owners returns the owners of file, a path relative to the scanned
+// project: those of the last matching rule, as in GitHub. A rule without
+// owners leaves the file unowned.
+func (f *File) Owners(file string) []string {
+	if f == nil || file == "" {
+		return nil
+	}
+	p := filepath.ToSlash(filepath.Clean(file))
+	if f.prefix != "" {
+		p = path.Join(f.prefix, p)
+	}
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if pathglob.Match(f.rules[i].pattern, p) {
+			return f.rules[i].owners
+		}
+	}
+	return nil
+}
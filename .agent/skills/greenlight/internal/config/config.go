@@ -26,6 +26,42 @@ type Config struct {
 
 	// Session auth (Apple ID)
 	Session *SessionConfig `json:"session,omitempty"`
+
+	// BrandTerms are extra competitor/brand names the trademark check flags in metadata.
+	BrandTerms []string `json:"brand_terms,omitempty"`
+
+	// StaleBuildDays is the age at which the latest processed build is flagged as stale.
+	StaleBuildDays int `json:"stale_build_days,omitempty"`
+
+	// AppIDs caches the App Store Connect ID that a bundle ID or app name
+	// given to --app-id resolved to.
+	AppIDs map[string]string `json:"app_ids,omitempty"`
+
+	// Lang is the language reports are written in when --lang isn't given,
+	// e.g. "ja" (see i18n.Languages).
+	Lang string `json:"lang,omitempty"`
+
+	// UpdateNotice turns the "new version available" notice off when false.
+	UpdateNotice *bool `json:"update_notice,omitempty"`
+
+	// Telemetry holds the opt-in telemetry settings; nil means never asked
+	// and nothing is sent.
+	Telemetry *TelemetrySettings `json:"telemetry,omitempty"`
+
+	// PolicyTokenHosts are hosts GREENLIGHT_POLICY_TOKEN may be sent to when
+	// fetching a remote extends config, besides GREENLIGHT_POLICY_TOKEN_HOSTS.
+	PolicyTokenHosts []string `json:"policy_token_hosts,omitempty"`
+}
+
+// TelemetrySettings are the two separate telemetry opt-ins.
+type TelemetrySettings struct {
+	// RuleStats sends anonymized counts of the rules each scan hits.
+	RuleStats bool `json:"rule_stats"`
+	// Outcomes allows 'greenlight telemetry outcome' to send review results.
+	Outcomes bool `json:"outcomes"`
+	// InstallID is a random ID that groups events from one install; it is
+	// not derived from anything about the user or machine.
+	InstallID string `json:"install_id,omitempty"`
 }
 
 type SessionConfig struct {
@@ -0,0 +1,324 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A project config can extend a shared one, usually an organization policy
+// served over HTTPS:
+//
+//	extends: https://git.example.com/greenlight/policy.yml
+//
+// The shared file has the same format. Its rules, scanners and policy are
+// the defaults; the project's own settings replace them unless the shared
+// file lists them under locked:
+//
+//	locked:
+//	  - rules.hardcoded-secrets  # one rule
+//	  - scanners                 # every scanner switch
+//	  - policy                   # the gate policy
+//	  - "*"                      # everything
+//
+// Remote files are cached under ~/.greenlight/policies and the cached copy is
+// used when the server can't be reached.
+
+// PolicyTokenEnv names the environment variable whose value is sent as a
+// bearer token when fetching a remote extends URL. It is only sent to hosts
+// the user lists in PolicyTokenHostsEnv or in policy_token_hosts in
+// ~/.greenlight/config.json, never to one a repository names in extends.
+const PolicyTokenEnv = "GREENLIGHT_POLICY_TOKEN"
+
+// PolicyTokenHostsEnv names the environment variable with the hosts,
+// comma-separated, that the policy token may be sent to.
+const PolicyTokenHostsEnv = "GREENLIGHT_POLICY_TOKEN_HOSTS"
+
+// maxExtendsDepth bounds a chain of configs extending each other.
+const maxExtendsDepth = 5
+
+// fetched holds remote configs already fetched by this process, keyed by URL.
+var fetched sync.Map
+
+// policyTransport carries remote config requests; nil uses the default.
+var policyTransport http.RoundTripper
+
+type fetchResult struct {
+	data    []byte
+	warning string
+	err     error
+}
+
+// resolveExtends merges the config c extends, and whatever that extends in
+// turn, underneath c. dir is the directory relative extends paths start from.
+func (c *ProjectConfig) resolveExtends(dir string) error {
+	seen := map[string]bool{}
+	if abs, err := filepath.Abs(c.Path); err == nil {
+		seen[abs] = true
+	}
+	ref, base := c.Extends, dir
+	var chain []*ProjectConfig
+	for ref != "" {
+		if len(chain) == maxExtendsDepth {
+			return fmt.Errorf("extends: more than %d levels", maxExtendsDepth)
+		}
+		key, data, warning, err := readExtends(ref, base)
+		if err != nil {
+			return fmt.Errorf("extends %s: %w", ref, err)
+		}
+		if seen[key] {
+			return fmt.Errorf("extends %s: cycle", ref)
+		}
+		seen[key] = true
+		if warning != "" {
+			c.Warnings = append(c.Warnings, warning)
+		}
+
+		parent := &ProjectConfig{Path: key}
+		if err := yaml.Unmarshal(data, parent); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if _, err := parent.RuleOverrides(); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		chain = append(chain, parent)
+		ref, base = parent.Extends, extendsBase(key)
+	}
+
+	// Merge from the root of the chain down to c.
+	merged := &ProjectConfig{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		merged = chain[i].over(merged)
+	}
+	if len(chain) > 0 {
+		resolved := c.over(merged)
+		resolved.Extends = c.Extends
+		*c = *resolved
+	}
+	return nil
+}
+
+// over returns c's settings layered over base: c replaces base's rules,
+// scanners and policy except where base locks them, and adds its own locks.
+// c's path includes replace base's; its excludes and first-party packages
+// add to them.
+func (c *ProjectConfig) over(base *ProjectConfig) *ProjectConfig {
+	out := &ProjectConfig{
+		Path:     c.Path,
+		Rules:    map[string]string{},
+		Scanners: map[string]bool{},
+		Policy:   base.Policy,
+		Paths:    base.Paths,
+	}
+	if len(c.Paths.Include) > 0 {
+		out.Paths.Include = c.Paths.Include
+	}
+	out.Paths.Exclude = append(append([]string(nil), base.Paths.Exclude...), c.Paths.Exclude...)
+	out.Paths.FirstParty = append(append([]string(nil), base.Paths.FirstParty...), c.Paths.FirstParty...)
+	for id, v := range base.Rules {
+		out.Rules[id] = v
+	}
+	for name, on := range base.Scanners {
+		out.Scanners[name] = on
+	}
+	locked := base.lockedSet()
+	ignored := func(key string) {
+		out.Warnings = append(out.Warnings, fmt.Sprintf("%s: %s is locked by %s; the local setting is ignored", c.Path, key, base.Path))
+	}
+
+	for _, id := range c.SortedRuleIDs() {
+		v := c.Rules[id]
+		if locked["*"] || locked["rules"] || locked["rules."+id] {
+			if base.Rules[id] != v {
+				ignored("rules." + id)
+			}
+			continue
+		}
+		out.Rules[id] = v
+	}
+	names := make([]string, 0, len(c.Scanners))
+	for name := range c.Scanners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		on := c.Scanners[name]
+		if locked["*"] || locked["scanners"] || locked["scanners."+name] {
+			if base.ScannerEnabled(name) != on {
+				ignored("scanners." + name)
+			}
+			continue
+		}
+		out.Scanners[name] = on
+	}
+	if c.Policy != "" {
+		if locked["*"] || locked["policy"] {
+			if strings.TrimSpace(c.Policy) != strings.TrimSpace(base.Policy) {
+				ignored("policy")
+			}
+		} else {
+			out.Policy = c.Policy
+		}
+	}
+
+	out.Locked = append(append(out.Locked, base.Locked...), c.Locked...)
+	out.Warnings = append(append(out.Warnings, base.Warnings...), c.Warnings...)
+	return out
+}
+
+func (c *ProjectConfig) lockedSet() map[string]bool {
+	set := make(map[string]bool, len(c.Locked))
+	for _, key := range c.Locked {
+		set[strings.TrimSpace(key)] = true
+	}
+	return set
+}
+
+// readExtends loads the config ref names: an https:// URL, or a file path
+// relative to base (a directory or, for configs fetched remotely, a URL).
+// key identifies the config for cycle checks and messages.
+func readExtends(ref, base string) (key string, data []byte, warning string, err error) {
+	if strings.HasPrefix(ref, "http://") {
+		return "", nil, "", fmt.Errorf("only https:// URLs can be extended")
+	}
+	if baseURL, err := url.Parse(base); err == nil && baseURL.Scheme == "https" {
+		u, err := baseURL.Parse(ref)
+		if err != nil {
+			return "", nil, "", err
+		}
+		ref = u.String()
+	}
+	if strings.HasPrefix(ref, "https://") {
+		r, _ := fetched.LoadOrStore(ref, sync.OnceValue(func() fetchResult {
+			data, warning, err := fetchExtends(ref)
+			return fetchResult{data, warning, err}
+		}))
+		res := r.(func() fetchResult)()
+		return ref, res.data, res.warning, res.err
+	}
+
+	path := ref
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	data, err = os.ReadFile(path)
+	return path, data, "", err
+}
+
+// extendsBase is where relative extends in the config at key start from.
+func extendsBase(key string) string {
+	if strings.HasPrefix(key, "https://") {
+		return key
+	}
+	return filepath.Dir(key)
+}
+
+// fetchExtends downloads a remote config, caching it; when the download
+// fails the cached copy is used with a warning.
+func fetchExtends(ref string) ([]byte, string, error) {
+	cache := ""
+	if dir, err := ConfigDir(); err == nil {
+		sum := sha256.Sum256([]byte(ref))
+		cache = filepath.Join(dir, "policies", hex.EncodeToString(sum[:8])+".yml")
+	}
+
+	data, err := download(ref)
+	if err == nil {
+		if cache != "" && os.MkdirAll(filepath.Dir(cache), 0700) == nil {
+			os.WriteFile(cache, data, 0600)
+		}
+		return data, "", nil
+	}
+	if cache != "" {
+		if cached, cerr := os.ReadFile(cache); cerr == nil {
+			return cached, fmt.Sprintf("could not fetch %s (%v); using the cached copy", ref, err), nil
+		}
+	}
+	return nil, "", err
+}
+
+// download fetches ref, with the policy token when ref, or a redirect from
+// it, is on a host the token may be sent to.
+func download(ref string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return nil, err
+	}
+	token := os.Getenv(PolicyTokenEnv)
+	hosts := policyTokenHosts()
+	if token != "" && tokenAllowed(req.URL, hosts) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{
+		Transport: policyTransport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if token != "" && tokenAllowed(req.URL, hosts) {
+				req.Header.Set("Authorization", "Bearer "+token)
+			} else {
+				req.Header.Del("Authorization")
+			}
+			return nil
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		if token != "" && req.Header.Get("Authorization") == "" && resp.StatusCode < 500 {
+			return nil, fmt.Errorf("%s (%s is set but not sent to %s; add the host to %s)", resp.Status, PolicyTokenEnv, req.URL.Host, PolicyTokenHostsEnv)
+		}
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// policyTokenHosts returns the hosts the user allows the policy token to be
+// sent to: PolicyTokenHostsEnv and policy_token_hosts in the user config.
+func policyTokenHosts() []string {
+	hosts := strings.Split(os.Getenv(PolicyTokenHostsEnv), ",")
+	if cfg, err := Load(); err == nil {
+		hosts = append(hosts, cfg.PolicyTokenHosts...)
+	}
+	return hosts
+}
+
+// tokenAllowed reports whether the policy token may be sent to u: it is an
+// https URL on one of hosts, given with or without a port.
+func tokenAllowed(u *url.URL, hosts []string) bool {
+	if u.Scheme != "https" {
+		return false
+	}
+	for _, h := range hosts {
+		if h = strings.TrimSpace(h); h != "" && (strings.EqualFold(h, u.Host) || strings.EqualFold(h, u.Hostname())) {
+			return true
+		}
+	}
+	return false
+}
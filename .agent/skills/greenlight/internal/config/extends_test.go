@@ -0,0 +1,50 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPolicyTokenOnlyToListedHosts(t *testing.T) {
+	tests := []struct {
+		name   string
+		listed bool
+		want   string
+	}{
+		{"unlisted first host", false, ""},
+		{"listed host", true, "Bearer s3cret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				w.Write([]byte("rules:\n  hardcoded-secrets: error\n"))
+			}))
+			defer srv.Close()
+			policyTransport = srv.Client().Transport
+			defer func() { policyTransport = nil }()
+
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv(PolicyTokenEnv, "s3cret")
+			t.Setenv(PolicyTokenHostsEnv, "")
+			if tt.listed {
+				u, _ := url.Parse(srv.URL)
+				t.Setenv(PolicyTokenHostsEnv, u.Host)
+			}
+
+			c := &ProjectConfig{Path: ".greenlight.yml", Extends: srv.URL + "/policy.yml"}
+			if err := c.resolveExtends(t.TempDir()); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+			if c.Rules["hardcoded-secrets"] != "error" {
+				t.Errorf("rules = %v, want the extended policy's", c.Rules)
+			}
+		})
+	}
+}
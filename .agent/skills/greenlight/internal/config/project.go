@@ -0,0 +1,191 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/pathglob"
+	"github.com/RevylAI/greenlight/internal/policy"
+	"github.com/RevylAI/greenlight/pkg/severity"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileNames are the per-project config files, checked in order.
+var ProjectFileNames = []string{".greenlight.yml", ".greenlight.yaml"}
+
+// ProjectConfig is the per-project .greenlight.yml checked into the repo.
+type ProjectConfig struct {
+	// Path is the file the config was loaded from ("" if none was found).
+	Path string `yaml:"-"`
+
+	// Rules maps a codescan or privacy rule ID to "off" or a severity
+	// ("info", "warn", "critical").
+	Rules map[string]string `yaml:"rules"`
+
+	// Scanners turns preflight scanners ("codescan", "privacy", "metadata",
+	// "xcode", "ipa", ...) on or off. Scanners not listed run.
+	Scanners map[string]bool `yaml:"scanners"`
+
+	// Paths narrows the files codescan and the privacy scan read, as
+	// --include and --exclude do.
+	Paths PathsConfig `yaml:"paths"`
+
+	// Extends is a shared config (an https:// URL or a file path) whose
+	// settings apply unless this file overrides them (see resolveExtends).
+	Extends string `yaml:"extends"`
+
+	// Policy is the CEL expression 'greenlight gate' uses when given no
+	// --policy or --expr; true means the report fails.
+	Policy string `yaml:"policy"`
+
+	// Locked lists settings that configs extending this one can't change:
+	// "rules.<id>", "rules", "scanners.<name>", "scanners", "policy" or "*".
+	Locked []string `yaml:"locked"`
+
+	// Warnings are problems found while resolving Extends that did not stop
+	// the config from loading, such as ignored local overrides.
+	Warnings []string `yaml:"-"`
+}
+
+// PathsConfig is the paths section: gitignore-style globs relative to the
+// project ("ios/**", "examples"), and npm packages in node_modules that are
+// the team's own code ("@acme/ui").
+type PathsConfig struct {
+	Include    []string `yaml:"include"`
+	Exclude    []string `yaml:"exclude"`
+	FirstParty []string `yaml:"first_party"`
+}
+
+// ScannerEnabled reports whether the scanner called name should run.
+func (c *ProjectConfig) ScannerEnabled(name string) bool {
+	on, ok := c.Scanners[name]
+	return !ok || on
+}
+
+// RuleOverride is the parsed form of one rules entry.
+type RuleOverride struct {
+	Disabled bool
+	Severity severity.Level // ignored when Disabled
+}
+
+// LoadProject reads .greenlight.yml from the project root. A missing file is
+// not an error and yields an empty config.
+func LoadProject(root string) (*ProjectConfig, error) {
+	for _, name := range ProjectFileNames {
+		path := filepath.Join(root, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		cfg := &ProjectConfig{Path: path}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if err := cfg.resolveExtends(root); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if _, err := cfg.RuleOverrides(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if err := cfg.Paths.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if cfg.Policy != "" {
+			if _, err := policy.Compile(cfg.Policy); err != nil {
+				return nil, fmt.Errorf("invalid %s: policy: %w", name, err)
+			}
+		}
+		return cfg, nil
+	}
+	return &ProjectConfig{}, nil
+}
+
+func (p PathsConfig) validate() error {
+	for _, list := range []struct {
+		key      string
+		patterns []string
+	}{{"paths.include", p.Include}, {"paths.exclude", p.Exclude}} {
+		for _, pattern := range list.patterns {
+			if !pathglob.Valid(pattern) {
+				return fmt.Errorf("%s: malformed pattern %q", list.key, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// RuleOverrides parses the rules section, keyed by rule ID.
+func (c *ProjectConfig) RuleOverrides() (map[string]RuleOverride, error) {
+	overrides := make(map[string]RuleOverride, len(c.Rules))
+	for id, value := range c.Rules {
+		o, err := parseRuleOverride(value)
+		if err != nil {
+			return nil, fmt.Errorf("rules.%s: %w", id, err)
+		}
+		overrides[id] = o
+	}
+	return overrides, nil
+}
+
+// UnknownRules returns the configured rule IDs that known doesn't contain,
+// in a stable order. Overrides for them would otherwise be silently ignored.
+func (c *ProjectConfig) UnknownRules(known map[string]bool) []string {
+	var unknown []string
+	for _, id := range c.SortedRuleIDs() {
+		if !known[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	return unknown
+}
+
+// ApplyOverrides drops findings from rules disabled in overrides and
+// re-grades the rest according to any per-rule severity override. rule
+// returns a finding's rule ID and its severity field.
+func ApplyOverrides[F any](findings []F, overrides map[string]RuleOverride, rule func(*F) (string, *severity.Level)) []F {
+	if len(overrides) == 0 {
+		return findings
+	}
+	kept := findings[:0]
+	for _, f := range findings {
+		id, level := rule(&f)
+		o, ok := overrides[id]
+		if ok && o.Disabled {
+			continue
+		}
+		if ok {
+			*level = o.Severity
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
+
+// SortedRuleIDs returns the configured rule IDs in a stable order for display.
+func (c *ProjectConfig) SortedRuleIDs() []string {
+	ids := make([]string, 0, len(c.Rules))
+	for id := range c.Rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func parseRuleOverride(value string) (RuleOverride, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "off", "false", "disable", "disabled", "no":
+		return RuleOverride{Disabled: true}, nil
+	}
+	level, err := severity.Parse(value)
+	if err != nil {
+		return RuleOverride{}, fmt.Errorf("unknown setting %q (use off, info, warn or critical)", value)
+	}
+	return RuleOverride{Severity: level}, nil
+}
@@ -0,0 +1,236 @@
+// Package deps reads the third-party dependencies a project pins:
+// CocoaPods from Podfile.lock, Swift packages from Package.resolved and npm
+// packages from package.json or package-lock.json, and finds their
+// installed sources and licenses.
+package deps
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Dep is one pinned dependency.
+type Dep struct {
+	// Name is the pod name, Swift package identity (lowercase) or npm
+	// package name.
+	Name    string
+	Version string
+	// URL is the repository of a Swift package.
+	URL string
+	// Line is the line of the pin in its lock file or manifest, or 0.
+	Line int
+}
+
+// podLine matches a top-level entry of Podfile.lock's PODS section:
+// "  - Alamofire (5.8.1)" or "  - Firebase/Core (10.20.0):".
+var podLine = regexp.MustCompile(`^  - "?([^ /"(]+)(?:/[^ "(]+)?"? \(([^)]+)\)`)
+
+// Pods reads the pods pinned in a Podfile.lock. Subspecs are reported once,
+// under their pod's name.
+func Pods(lock string) ([]Dep, error) {
+	f, err := os.Open(lock)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var deps []Dep
+	seen := map[string]bool{}
+	inPods := false
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if !strings.HasPrefix(line, " ") {
+			inPods = line == "PODS:"
+			continue
+		}
+		m := podLine.FindStringSubmatch(line)
+		if !inPods || m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		deps = append(deps, Dep{Name: m[1], Version: strings.TrimPrefix(m[2], "= "), Line: n})
+	}
+	return deps, sc.Err()
+}
+
+// packageResolved is Package.resolved: version 1 nests the pins under
+// "object" and names packages by URL, later versions by identity.
+type packageResolved struct {
+	Pins   []packagePin `json:"pins"`
+	Object struct {
+		Pins []packagePin `json:"pins"`
+	} `json:"object"`
+}
+
+type packagePin struct {
+	Identity      string `json:"identity"`
+	Location      string `json:"location"`
+	RepositoryURL string `json:"repositoryURL"`
+	State         struct {
+		Version string `json:"version"`
+	} `json:"state"`
+}
+
+// Packages reads the Swift packages pinned in a Package.resolved.
+func Packages(file string) ([]Dep, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var pr packageResolved
+	if err := json.Unmarshal(data, &pr); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	var deps []Dep
+	for _, pin := range append(pr.Pins, pr.Object.Pins...) {
+		url := pin.Location
+		if url == "" {
+			url = pin.RepositoryURL
+		}
+		d := Dep{Name: strings.ToLower(pin.Identity), Version: pin.State.Version, URL: url}
+		if d.Name == "" {
+			d.Name = strings.ToLower(strings.TrimSuffix(path.Base(url), ".git"))
+		}
+		for i, line := range lines {
+			if url != "" && strings.Contains(line, url) {
+				d.Line = i + 1
+				break
+			}
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// NPM reads the runtime dependencies of a package.json, leaving out
+// devDependencies, which don't ship in the app. Version is the declared
+// range.
+func NPM(manifest string) ([]Dep, error) {
+	data, err := os.ReadFile(manifest)
+	if err != nil {
+		return nil, err
+	}
+	var pkg struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(data), "\n")
+	var deps []Dep
+	for name, version := range pkg.Dependencies {
+		d := Dep{Name: name, Version: version}
+		key := `"` + name + `"`
+		for i, line := range lines {
+			if strings.Contains(line, key) {
+				d.Line = i + 1
+				break
+			}
+		}
+		deps = append(deps, d)
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps, nil
+}
+
+// NPMLock reads the packages a package-lock.json (lockfileVersion 2 or 3)
+// installs for the app, direct and transitive, leaving out those only
+// devDependencies need. Version is the installed version.
+func NPMLock(lock string) ([]Dep, error) {
+	data, err := os.ReadFile(lock)
+	if err != nil {
+		return nil, err
+	}
+	var pl struct {
+		Packages map[string]struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			Dev     bool   `json:"dev"`
+			Link    bool   `json:"link"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(data, &pl); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var deps []Dep
+	for key, p := range pl.Packages {
+		i := strings.LastIndex(key, "node_modules/")
+		if i < 0 || p.Dev || p.Link {
+			continue // the root package, a workspace or a dev-only package
+		}
+		name := p.Name
+		if name == "" {
+			name = key[i+len("node_modules/"):]
+		}
+		if seen[name+"@"+p.Version] {
+			continue
+		}
+		seen[name+"@"+p.Version] = true
+		deps = append(deps, Dep{Name: name, Version: p.Version})
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Name != deps[j].Name {
+			return deps[i].Name < deps[j].Name
+		}
+		return deps[i].Version < deps[j].Version
+	})
+	return deps, nil
+}
+
+// Source returns the installed sources of d, pinned in the lock file or
+// manifest at file, or "" if they aren't installed: Pods/<name> next to a
+// Podfile.lock, node_modules/<name> next to a package.json or
+// package-lock.json, and for a Package.resolved the package's directory in
+// any of checkouts (SwiftPM and Xcode checkouts directories).
+func Source(file string, d Dep, checkouts []string) string {
+	dir := filepath.Dir(file)
+	switch filepath.Base(file) {
+	case "Podfile.lock":
+		return existingDir(filepath.Join(dir, "Pods", d.Name))
+	case "package.json", "package-lock.json":
+		return existingDir(filepath.Join(dir, "node_modules", filepath.FromSlash(d.Name)))
+	case "Package.resolved":
+		for _, c := range checkouts {
+			entries, _ := os.ReadDir(c)
+			for _, e := range entries {
+				if e.IsDir() && strings.EqualFold(e.Name(), d.Name) {
+					return filepath.Join(c, e.Name())
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// IsCheckouts reports whether dir holds Swift package checkouts: the
+// checkouts directory of Xcode's SourcePackages or SwiftPM's .build.
+func IsCheckouts(dir string) bool {
+	parent := filepath.Base(filepath.Dir(dir))
+	return filepath.Base(dir) == "checkouts" && (parent == "SourcePackages" || parent == ".build")
+}
+
+// BuildCheckouts lists the checkouts directories Xcode keeps in a build
+// directory, DerivedData/<App>/SourcePackages/checkouts.
+func BuildCheckouts(buildDir string) []string {
+	var found []string
+	for _, pattern := range []string{"SourcePackages/checkouts", "*/SourcePackages/checkouts"} {
+		matches, _ := filepath.Glob(filepath.Join(buildDir, pattern))
+		found = append(found, matches...)
+	}
+	return found
+}
+
+func existingDir(p string) string {
+	if info, err := os.Stat(p); err == nil && info.IsDir() {
+		return p
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package deps
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// License reads the license of the dependency installed at dir: the
+// "license" field of an npm package, or else the text of its LICENSE or
+// COPYING file. Licenses told apart by their text are named by their SPDX
+// family ("BSD", "GPL") where the text doesn't settle the version. It
+// returns "" when the license isn't recognized.
+func License(dir string) string {
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			License  json.RawMessage `json:"license"`
+			Licenses []struct {
+				Type string `json:"type"`
+			} `json:"licenses"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			var id string
+			var obj struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(pkg.License, &id) != nil && json.Unmarshal(pkg.License, &obj) == nil {
+				id = obj.Type
+			}
+			if id == "" && len(pkg.Licenses) > 0 {
+				id = pkg.Licenses[0].Type
+			}
+			if id != "" && !strings.HasPrefix(strings.ToUpper(id), "SEE LICEN") {
+				return id
+			}
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, e := range entries {
+		name := strings.ToUpper(e.Name())
+		if e.IsDir() || !(strings.HasPrefix(name, "LICENSE") || strings.HasPrefix(name, "LICENCE") || strings.HasPrefix(name, "COPYING")) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		if id := licenseFromText(string(data)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// licenseTexts identify a license by a phrase of its text, most specific
+// first.
+var licenseTexts = []struct{ phrase, id string }{
+	{"GNU AFFERO GENERAL PUBLIC LICENSE", "AGPL-3.0"},
+	{"GNU LESSER GENERAL PUBLIC LICENSE", "LGPL"},
+	{"GNU LIBRARY GENERAL PUBLIC LICENSE", "LGPL"},
+	{"GNU GENERAL PUBLIC LICENSE", "GPL"},
+	{"Apache License", "Apache-2.0"},
+	{"Mozilla Public License", "MPL-2.0"},
+	{"This is free and unencumbered software", "Unlicense"},
+	{"Permission to use, copy, modify, and/or distribute this software for any purpose with or without fee", "ISC"},
+	{"Permission is hereby granted, free of charge", "MIT"},
+	{"Redistribution and use in source and binary forms", "BSD"},
+	{"This software is provided 'as-is', without any express or implied", "Zlib"},
+}
+
+func licenseFromText(text string) string {
+	for _, l := range licenseTexts {
+		if strings.Contains(text, l.phrase) {
+			return l.id
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+// Package findingid derives stable identifiers for findings, so baselines,
+// suppressions and trend tracking can follow a finding across runs even
+// when the code around it moves.
+package findingid
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	quotedRe   = regexp.MustCompile("\"[^\"]*\"|'[^']*'|“[^”]*”|`[^`]*`")
+	volatileRe = regexp.MustCompile(`\S*[0-9./:@]\S*`)
+	nonSlugRe  = regexp.MustCompile(`[^a-z]+`)
+)
+
+// RuleID returns ruleID when the scanner assigned one. Otherwise it derives
+// one from the source scanner and the title, dropping the parts of the title
+// that vary between findings of the same kind (quoted names, numbers,
+// versions, paths, identifiers): "metadata/bundle-id-uses-reserved-prefix".
+func RuleID(source, ruleID, title string) string {
+	if ruleID != "" {
+		return ruleID
+	}
+	slug := strings.ToLower(quotedRe.ReplaceAllString(title, " "))
+	slug = volatileRe.ReplaceAllString(slug, " ")
+	slug = strings.Trim(nonSlugRe.ReplaceAllString(slug, "-"), "-")
+	if len(slug) > 48 {
+		slug = slug[:48]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	if slug == "" {
+		slug = "finding"
+	}
+	return source + "/" + slug
+}
+
+// Fingerprint identifies one occurrence of a rule: the rule, the file and
+// the offending line with whitespace normalized, so it survives line
+// number shifts and re-indentation. Findings without a code line are
+// identified by their title instead.
+func Fingerprint(ruleID, file, code, title string) string {
+	content := strings.Join(strings.Fields(code), " ")
+	if content == "" {
+		content = strings.Join(strings.Fields(title), " ")
+	}
+	sum := sha256.Sum256([]byte(ruleID + "\x00" + filepath.ToSlash(file) + "\x00" + content))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Display is the compact finding ID shown in terminal output and accepted
+// by 'greenlight explain': "apple-pay@3f2a91c0".
+func Display(ruleID, fingerprint string) string {
+	if len(fingerprint) > 8 {
+		fingerprint = fingerprint[:8]
+	}
+	return ruleID + "@" + fingerprint
+}
+
+// Matches reports whether id — a Display ID, a full fingerprint or a
+// fingerprint prefix of at least 6 characters — refers to the finding.
+func Matches(id, ruleID, fingerprint string) bool {
+	if fingerprint == "" {
+		return false
+	}
+	if rule, fp, ok := strings.Cut(id, "@"); ok {
+		return rule == ruleID && len(fp) >= 6 && strings.HasPrefix(fingerprint, fp)
+	}
+	return len(id) >= 6 && strings.HasPrefix(fingerprint, id)
+}
@@ -0,0 +1,51 @@
+// Package findingorder puts findings in one fixed order — severity, source,
+// file, line, title — so the same project always produces the same report,
+// whatever the map iteration or goroutine scheduling, and report diffs in
+// git or CI show only real changes.
+package findingorder
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Key holds the fields a finding is ordered by.
+type Key struct {
+	Severity severity.Level // higher sorts first
+	Source   string
+	File     string
+	Line     int
+	Title    string
+	// Detail breaks ties between otherwise identical findings.
+	Detail string
+}
+
+// Compare orders a before b when it is more severe, then by source, file,
+// line, title and detail.
+func Compare(a, b Key) int {
+	if c := cmp.Compare(b.Severity, a.Severity); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Source, b.Source); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.File, b.File); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Line, b.Line); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.Title, b.Title); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Detail, b.Detail)
+}
+
+// Sort orders findings by the Key that key derives from each one.
+func Sort[T any](findings []T, key func(T) Key) {
+	slices.SortStableFunc(findings, func(a, b T) int {
+		return Compare(key(a), key(b))
+	})
+}
@@ -0,0 +1,131 @@
+package guidelines
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/config"
+)
+
+// Change kinds reported by Diff.
+const (
+	Added   = "added"
+	Changed = "changed"
+	Removed = "removed"
+)
+
+// Change is one guideline section that differs between two versions.
+type Change struct {
+	Section  string `json:"section"`
+	Kind     string `json:"kind"`
+	Title    string `json:"title"`
+	OldTitle string `json:"old_title,omitempty"`
+}
+
+// VersionPath is where the snapshot of a fetched guidelines version is kept,
+// so later updates can be diffed against it.
+func VersionPath(version string) (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "guidelines", version+".json"), nil
+}
+
+// Versions lists the fetched guideline versions kept on disk, oldest first.
+func Versions() []string {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "guidelines", "*.json"))
+	var versions []string
+	for _, m := range matches {
+		versions = append(versions, strings.TrimSuffix(filepath.Base(m), ".json"))
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// LoadVersion resolves a version reference: "built-in" (or "") for the copy
+// embedded in the binary, "latest" for the copy Load would use, a fetched
+// version such as "2025-06-09", or the path of a guidelines JSON file.
+func LoadVersion(ref string) (*DB, error) {
+	switch ref {
+	case "", "built-in":
+		return LoadEmbedded()
+	case "latest":
+		return Load()
+	}
+	path := ref
+	if _, err := os.Stat(path); err != nil {
+		if path, err = VersionPath(ref); err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := parseDB(data)
+	if err != nil {
+		return nil, err
+	}
+	db.Path = path
+	return db, nil
+}
+
+// Diff lists the sections added, changed (title or text) and removed going
+// from old to new, in section order.
+func Diff(old, new *DB) []Change {
+	var changes []Change
+	for section, g := range new.index {
+		prev, ok := old.index[section]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Section: section, Kind: Added, Title: g.Title})
+		case normalize(prev.Title) != normalize(g.Title) || normalize(prev.Content) != normalize(g.Content):
+			c := Change{Section: section, Kind: Changed, Title: g.Title}
+			if normalize(prev.Title) != normalize(g.Title) {
+				c.OldTitle = prev.Title
+			}
+			changes = append(changes, c)
+		}
+	}
+	for section, g := range old.index {
+		if _, ok := new.index[section]; !ok {
+			changes = append(changes, Change{Section: section, Kind: Removed, Title: g.Title})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return compareSections(changes[i].Section, changes[j].Section) < 0 })
+	return changes
+}
+
+// Affects reports whether a check citing guideline is affected by a change
+// to section: the same section, a subsection of it, or (below the five
+// top-level sections) the section it belongs to.
+func Affects(section, guideline string) bool {
+	if guideline == "" {
+		return false
+	}
+	if section == guideline || isWithin(section, guideline) {
+		return true
+	}
+	return parentSection(section) != "" && isWithin(guideline, section)
+}
+
+// isWithin reports whether section is nested under parent.
+func isWithin(section, parent string) bool {
+	for s := parentSection(section); s != ""; s = parentSection(s) {
+		if s == parent {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
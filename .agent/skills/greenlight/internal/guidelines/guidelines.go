@@ -3,7 +3,7 @@ package guidelines
 import (
 	_ "embed"
 	"encoding/json"
-	"strings"
+	"os"
 )
 
 //go:embed data/guidelines.json
@@ -20,20 +20,63 @@ type Guideline struct {
 
 // DB holds the full set of guidelines for querying.
 type DB struct {
-	Guidelines []Guideline `json:"guidelines"`
-	index      map[string]*Guideline
+	// Version is the "last updated" date of Apple's guidelines page
+	// (YYYY-MM-DD); empty for the copy embedded in the binary.
+	Version      string        `json:"version,omitempty"`
+	FetchedAt    string        `json:"fetched_at,omitempty"`
+	Source       string        `json:"source,omitempty"`
+	ReleaseNotes []ReleaseNote `json:"release_notes,omitempty"`
+	Guidelines   []Guideline   `json:"guidelines"`
+
+	// Path is the file the guidelines were loaded from ("" if embedded).
+	Path   string `json:"-"`
+	index  map[string]*Guideline
+	search *searchIndex
+}
+
+// ReleaseNote is an Apple Developer news item announcing a guidelines change.
+type ReleaseNote struct {
+	Date  string `json:"date"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
 }
 
-// Load parses the embedded guidelines JSON.
+// Load returns the guidelines fetched by `greenlight guidelines update` if
+// present, otherwise the copy embedded in the binary.
 func Load() (*DB, error) {
+	if path, err := UpdatedPath(); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			if db, err := parseDB(data); err == nil && len(db.Guidelines) > 0 {
+				db.Path = path
+				return db, nil
+			}
+		}
+	}
+	return LoadEmbedded()
+}
+
+// LoadEmbedded parses the guidelines JSON embedded in the binary.
+func LoadEmbedded() (*DB, error) {
+	return parseDB(guidelinesJSON)
+}
+
+func parseDB(data []byte) (*DB, error) {
 	var db DB
-	if err := json.Unmarshal(guidelinesJSON, &db); err != nil {
+	if err := json.Unmarshal(data, &db); err != nil {
 		return nil, err
 	}
 	db.buildIndex()
 	return &db, nil
 }
 
+// VersionLabel describes the loaded copy for display.
+func (db *DB) VersionLabel() string {
+	if db.Version == "" {
+		return "built-in"
+	}
+	return db.Version
+}
+
 func (db *DB) buildIndex() {
 	db.index = make(map[string]*Guideline)
 	var walk func(gs []Guideline)
@@ -56,24 +99,3 @@ func (db *DB) Get(section string) (*Guideline, bool) {
 func (db *DB) TopLevel() []Guideline {
 	return db.Guidelines
 }
-
-// Search finds guidelines matching a keyword query.
-func (db *DB) Search(query string) []Guideline {
-	query = strings.ToLower(query)
-	var results []Guideline
-
-	var walk func(gs []Guideline)
-	walk = func(gs []Guideline) {
-		for _, g := range gs {
-			if strings.Contains(strings.ToLower(g.Title), query) ||
-				strings.Contains(strings.ToLower(g.Content), query) ||
-				strings.Contains(strings.ToLower(g.Section), query) {
-				results = append(results, g)
-			}
-			walk(g.Subsections)
-		}
-	}
-	walk(db.Guidelines)
-
-	return results
-}
@@ -0,0 +1,63 @@
+package guidelines
+
+import (
+	"strings"
+	"sync"
+)
+
+// topLevelAnchors are the anchors Apple uses for the five top-level
+// sections; numbered sections are anchored by their number.
+var topLevelAnchors = map[string]string{
+	"1": "safety",
+	"2": "performance",
+	"3": "business",
+	"4": "design",
+	"5": "legal",
+}
+
+// URL returns the link to a section on Apple's guidelines page. Lettered
+// items such as 5.1.1(v) link to their numbered section.
+func URL(section string) string {
+	if section == "" {
+		return ""
+	}
+	if i := strings.Index(section, "("); i > 0 {
+		section = section[:i]
+	}
+	if anchor, ok := topLevelAnchors[section]; ok {
+		return GuidelinesURL + "#" + anchor
+	}
+	return GuidelinesURL + "#" + section
+}
+
+// Lookup returns the guideline for section, or its closest parent when the
+// database doesn't have that subsection.
+func (db *DB) Lookup(section string) *Guideline {
+	for section != "" {
+		if g, ok := db.Get(section); ok {
+			return g
+		}
+		section = parentSection(section)
+	}
+	return nil
+}
+
+var (
+	defaultDB   *DB
+	defaultOnce sync.Once
+)
+
+// Reference resolves the title and link of the guideline a finding cites.
+// The title is empty when the section isn't known.
+func Reference(section string) (title, url string) {
+	if section == "" {
+		return "", ""
+	}
+	defaultOnce.Do(func() { defaultDB, _ = Load() })
+	if defaultDB != nil {
+		if g := defaultDB.Lookup(section); g != nil {
+			title = g.Title
+		}
+	}
+	return title, URL(section)
+}
@@ -0,0 +1,277 @@
+package guidelines
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SearchOptions tunes Search.
+type SearchOptions struct {
+	// Semantic expands the query with related terms ("delete" also finds
+	// "remove", "deactivate") before ranking.
+	Semantic bool
+	// Limit caps the number of results (0 for all).
+	Limit int
+}
+
+// Result is a guideline matching a search, best first.
+type Result struct {
+	*Guideline
+	Score float64 `json:"score"`
+	// Matched lists the indexed terms that matched the query.
+	Matched []string `json:"matched,omitempty"`
+}
+
+// Search ranks guidelines against a free-text query. Words are stemmed, so
+// "deleting" finds "deletion"; words not in the guidelines are matched to
+// close spellings, so typos still find results; and an exact section number
+// or phrase ranks its guideline first.
+func (db *DB) Search(query string, opts SearchOptions) []Result {
+	if db.search == nil {
+		db.search = newSearchIndex(db)
+	}
+	idx := db.search
+
+	weights := map[string]float64{}
+	addTerm := func(term string, w float64) {
+		if w > weights[term] {
+			weights[term] = w
+		}
+	}
+	for _, word := range tokenize(query) {
+		term := stem(word)
+		if _, ok := idx.idf[term]; ok {
+			addTerm(term, 1)
+		} else {
+			for _, t := range idx.closeTerms(term) {
+				addTerm(t, 0.8)
+			}
+		}
+		if opts.Semantic {
+			for _, t := range related[term] {
+				if _, ok := idx.idf[t]; ok {
+					addTerm(t, 0.5)
+				}
+			}
+		}
+	}
+
+	phrase := strings.ToLower(strings.TrimSpace(query))
+	var results []Result
+	for _, doc := range idx.docs {
+		var score float64
+		var matched []string
+		for term, w := range weights {
+			if v := doc.vector[term]; v > 0 {
+				score += w * v
+				matched = append(matched, term)
+			}
+		}
+		g := doc.guideline
+		if strings.EqualFold(g.Section, phrase) {
+			score += 10
+		} else if len(phrase) > 3 && (strings.Contains(strings.ToLower(g.Title), phrase) || strings.Contains(strings.ToLower(g.Content), phrase)) {
+			score += 1
+		}
+		if score == 0 {
+			continue
+		}
+		sort.Strings(matched)
+		results = append(results, Result{Guideline: g, Score: math.Round(score*1000) / 1000, Matched: matched})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return compareSections(results[i].Section, results[j].Section) < 0
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results
+}
+
+// searchIndex holds a normalized TF-IDF vector per guideline.
+type searchIndex struct {
+	docs []searchDoc
+	idf  map[string]float64
+}
+
+type searchDoc struct {
+	guideline *Guideline
+	vector    map[string]float64
+}
+
+func newSearchIndex(db *DB) *searchIndex {
+	idx := &searchIndex{idf: map[string]float64{}}
+	df := map[string]int{}
+	var walk func(gs []Guideline)
+	walk = func(gs []Guideline) {
+		for i := range gs {
+			g := &gs[i]
+			tf := map[string]float64{}
+			// Titles say what a guideline is about; weigh them higher.
+			for _, w := range tokenize(g.Title) {
+				tf[stem(w)] += 3
+			}
+			for _, w := range tokenize(g.Content + " " + strings.Join(g.CommonViolations, " ")) {
+				tf[stem(w)]++
+			}
+			for t := range tf {
+				df[t]++
+			}
+			idx.docs = append(idx.docs, searchDoc{guideline: g, vector: tf})
+			walk(g.Subsections)
+		}
+	}
+	walk(db.Guidelines)
+
+	n := float64(len(idx.docs))
+	for t, count := range df {
+		idx.idf[t] = math.Log(1 + n/float64(count))
+	}
+	for _, doc := range idx.docs {
+		var norm float64
+		for t, tf := range doc.vector {
+			doc.vector[t] = (1 + math.Log(tf)) * idx.idf[t]
+			norm += doc.vector[t] * doc.vector[t]
+		}
+		norm = math.Sqrt(norm)
+		for t := range doc.vector {
+			doc.vector[t] /= norm
+		}
+	}
+	return idx
+}
+
+// closeTerms finds indexed terms within a small edit distance of term, or
+// that extend it ("priv" → "privaci"), for misspelled or partial words.
+func (idx *searchIndex) closeTerms(term string) []string {
+	maxEdits := 0
+	switch {
+	case len(term) >= 8:
+		maxEdits = 2
+	case len(term) >= 4:
+		maxEdits = 1
+	}
+	var terms []string
+	for t := range idx.idf {
+		if len(term) >= 4 && strings.HasPrefix(t, term) {
+			terms = append(terms, t)
+		} else if maxEdits > 0 && abs(len(t)-len(term)) <= maxEdits && editDistance(t, term) <= maxEdits {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "be": true,
+	"by": true, "for": true, "from": true, "how": true, "i": true, "if": true,
+	"in": true, "is": true, "it": true, "its": true, "my": true, "not": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "their": true,
+	"this": true, "to": true, "with": true, "you": true, "your": true,
+}
+
+// tokenize splits text into lowercase words, dropping stop words.
+func tokenize(text string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !stopWords[w] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+var suffixes = []struct{ suffix, replacement string }{
+	{"ies", "y"}, {"ied", "y"}, {"ments", ""}, {"ment", ""}, {"ings", ""},
+	{"ing", ""}, {"ions", ""}, {"ion", ""}, {"ers", ""}, {"er", ""},
+	{"ed", ""}, {"es", ""}, {"ly", ""}, {"s", ""}, {"e", ""},
+}
+
+// stem strips common English suffixes so inflections of a word index the
+// same: "delete", "deleting", "deletion" → "delet".
+func stem(word string) string {
+	for _, s := range suffixes {
+		if strings.HasSuffix(word, s.suffix) && len(word)-len(s.suffix) >= 3 {
+			word = word[:len(word)-len(s.suffix)] + s.replacement
+			break
+		}
+	}
+	// "submitted" → "submitt" → "submit"
+	if n := len(word); n > 3 && word[n-1] == word[n-2] && !strings.ContainsRune("aeiouls", rune(word[n-1])) {
+		word = word[:n-1]
+	}
+	return word
+}
+
+// concepts groups words used interchangeably when talking about review
+// issues; semantic search expands a query word to its group.
+var concepts = [][]string{
+	{"delete", "remove", "erase", "deactivate", "close", "deletion"},
+	{"account", "login", "signin", "sign", "register", "registration", "profile", "user"},
+	{"purchase", "buy", "payment", "pay", "iap", "subscription", "subscribe", "unlock", "price", "money"},
+	{"kid", "child", "children", "minor", "parental", "age"},
+	{"track", "tracking", "advertising", "ad", "ads", "idfa", "att", "advertiser"},
+	{"crash", "bug", "broken", "freeze", "hang", "incomplete"},
+	{"privacy", "personal", "data", "collect", "consent"},
+	{"location", "gps", "geolocation"},
+	{"health", "medical", "fitness", "healthkit", "diagnosis"},
+	{"gamble", "gambling", "casino", "lottery", "betting", "bet"},
+	{"copy", "clone", "spam", "duplicate", "imitation"},
+	{"loot", "random", "gacha"},
+	{"crypto", "cryptocurrency", "bitcoin", "nft", "mining", "wallet"},
+	{"web", "webview", "website", "wrapper", "html"},
+	{"review", "rating", "ratings"},
+	{"screenshot", "preview", "metadata", "description", "keyword"},
+	{"trademark", "copyright", "intellectual", "property", "brand"},
+	{"hidden", "undocumented", "secret", "dormant", "switch"},
+	{"update", "download", "executable", "code"},
+}
+
+// related maps each stemmed concept word to the others in its group.
+var related = func() map[string][]string {
+	m := map[string][]string{}
+	for _, group := range concepts {
+		for _, a := range group {
+			for _, b := range group {
+				if sa, sb := stem(a), stem(b); sa != sb {
+					m[sa] = append(m[sa], sb)
+				}
+			}
+		}
+	}
+	return m
+}()
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
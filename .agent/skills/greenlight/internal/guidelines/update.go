@@ -0,0 +1,329 @@
+package guidelines
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/config"
+)
+
+const (
+	// GuidelinesURL is Apple's App Store Review Guidelines page.
+	GuidelinesURL = "https://developer.apple.com/app-store/review/guidelines/"
+	newsFeedURL   = "https://developer.apple.com/news/rss/news.rss"
+
+	// minSections guards against saving a partial parse after a page
+	// redesign; the guidelines have well over a hundred numbered sections.
+	minSections = 40
+)
+
+var (
+	// Numbered guideline anchors: id="5.1.1", id="5.1.1(v)", id="4.2.3".
+	sectionAnchorRe = regexp.MustCompile(`<(?:li|div|p|section|h[1-6]|span|a)\b[^>]*\bid="(\d+(?:\.\d+)*)(?:\(?([ivx]+)\)?)?"[^>]*>`)
+	// Top-level headings: "1. Safety", "5 Legal".
+	topLevelHeadingRe = regexp.MustCompile(`<h[1-6][^>]*>\s*(?:<[^>]+>\s*)*([1-5])\.?\s+([A-Z][^<]{2,40})<`)
+	boldRe            = regexp.MustCompile(`(?s)<(strong|b)\b[^>]*>(.*?)</(strong|b)>`)
+	tagRe             = regexp.MustCompile(`<[^>]+>`)
+	spaceRe           = regexp.MustCompile(`\s+`)
+	leadingSectionRe  = regexp.MustCompile(`^(\d+(\.\d+)*)?\s*(\([ivx]+\))?\s*[.:]?\s*`)
+	lastUpdatedRe     = regexp.MustCompile(`(?i)last\s+updated\s*:?\s*(?:<[^>]+>\s*)*([A-Z][a-z]+\s+\d{1,2},\s+\d{4})`)
+
+	rssItemRe       = regexp.MustCompile(`(?s)<item>(.*?)</item>`)
+	rssTitleRe      = regexp.MustCompile(`(?s)<title>(?:<!\[CDATA\[)?(.*?)(?:\]\]>)?</title>`)
+	rssLinkRe       = regexp.MustCompile(`(?s)<link>(.*?)</link>`)
+	rssDateRe       = regexp.MustCompile(`(?s)<pubDate>(.*?)</pubDate>`)
+	rssDescRe       = regexp.MustCompile(`(?s)<description>(.*?)</description>`)
+	guidelineNewsRe = regexp.MustCompile(`(?i)review guidelines`)
+)
+
+// UpdatedPath is where `greenlight guidelines update` stores the fetched
+// guidelines (~/.greenlight/guidelines.json).
+func UpdatedPath() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "guidelines.json"), nil
+}
+
+// Fetch downloads and parses Apple's current guidelines, merged with the
+// curated common violations of the embedded copy, plus recent news items
+// announcing guideline changes. Release notes are best-effort.
+func Fetch(client *http.Client) (*DB, error) {
+	page, err := download(client, GuidelinesURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download guidelines: %w", err)
+	}
+	db, err := Parse(page)
+	if err != nil {
+		return nil, err
+	}
+	db.Source = GuidelinesURL
+	if feed, err := download(client, newsFeedURL); err == nil {
+		db.ReleaseNotes = parseReleaseNotes(feed)
+	}
+	return db, nil
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 20<<20))
+}
+
+// Parse extracts the numbered guidelines from the HTML of Apple's guidelines
+// page and merges in the embedded copy's common violations and titles.
+func Parse(page []byte) (*DB, error) {
+	doc := string(page)
+
+	type parsed struct {
+		section string
+		title   string
+		content string
+	}
+	var sections []parsed
+	seen := map[string]bool{}
+	anchors := sectionAnchorRe.FindAllStringSubmatchIndex(doc, -1)
+	for i, a := range anchors {
+		section := doc[a[2]:a[3]]
+		if a[4] >= 0 {
+			section += "(" + doc[a[4]:a[5]] + ")"
+		}
+		if seen[section] {
+			continue
+		}
+		end := len(doc)
+		if i+1 < len(anchors) {
+			end = anchors[i+1][0]
+		}
+		title, content := sectionText(doc[a[1]:end])
+		if title == "" && content == "" {
+			continue
+		}
+		seen[section] = true
+		sections = append(sections, parsed{section, title, content})
+	}
+	if len(sections) < minSections {
+		return nil, fmt.Errorf("found only %d guideline sections — the page layout may have changed; keeping the current copy", len(sections))
+	}
+
+	embedded, _ := LoadEmbedded()
+	nodes := map[string]*Guideline{}
+	var order []string
+	add := func(section, title, content string) *Guideline {
+		g := &Guideline{Section: section, Title: title, Content: content}
+		if embedded != nil {
+			if old, ok := embedded.Get(section); ok {
+				g.CommonViolations = old.CommonViolations
+				if g.Title == "" {
+					g.Title = old.Title
+				}
+				if g.Content == "" {
+					g.Content = old.Content
+				}
+			}
+		}
+		nodes[section] = g
+		order = append(order, section)
+		return g
+	}
+
+	for _, m := range topLevelHeadingRe.FindAllStringSubmatch(doc, -1) {
+		if nodes[m[1]] == nil {
+			add(m[1], strings.TrimSpace(html.UnescapeString(m[2])), "")
+		}
+	}
+	for _, p := range sections {
+		if g, ok := nodes[p.section]; ok {
+			if p.content != "" {
+				g.Content = p.content
+			}
+			continue
+		}
+		add(p.section, p.title, p.content)
+	}
+	// Every section needs its ancestors to hang from.
+	for _, section := range append([]string(nil), order...) {
+		for parent := parentSection(section); parent != ""; parent = parentSection(parent) {
+			if nodes[parent] == nil {
+				add(parent, "", "")
+			}
+		}
+	}
+
+	// Assemble the tree in document order, children before being attached
+	// so values copied into parents are complete.
+	sort.SliceStable(order, func(i, j int) bool { return depth(order[i]) > depth(order[j]) })
+	children := map[string][]string{}
+	var roots []string
+	for _, section := range order {
+		if parent := parentSection(section); parent != "" {
+			children[parent] = append(children[parent], section)
+		} else {
+			roots = append(roots, section)
+		}
+	}
+	var build func(section string) Guideline
+	build = func(section string) Guideline {
+		g := *nodes[section]
+		kids := children[section]
+		sort.SliceStable(kids, func(i, j int) bool { return compareSections(kids[i], kids[j]) < 0 })
+		for _, k := range kids {
+			g.Subsections = append(g.Subsections, build(k))
+		}
+		return g
+	}
+	sort.SliceStable(roots, func(i, j int) bool { return compareSections(roots[i], roots[j]) < 0 })
+
+	db := &DB{FetchedAt: time.Now().UTC().Format(time.RFC3339)}
+	for _, r := range roots {
+		db.Guidelines = append(db.Guidelines, build(r))
+	}
+	db.Version = time.Now().UTC().Format("2006-01-02")
+	if m := lastUpdatedRe.FindStringSubmatch(doc); m != nil {
+		if t, err := time.Parse("January 2, 2006", spaceRe.ReplaceAllString(m[1], " ")); err == nil {
+			db.Version = t.Format("2006-01-02")
+		}
+	}
+	db.buildIndex()
+	return db, nil
+}
+
+// sectionText splits the HTML between two anchors into a title (the
+// leading bold text) and plain-text content.
+func sectionText(fragment string) (title, content string) {
+	if m := boldRe.FindStringSubmatchIndex(fragment); m != nil && strings.TrimSpace(tagRe.ReplaceAllString(fragment[:m[0]], "")) == "" {
+		title = cleanText(fragment[m[4]:m[5]])
+		fragment = fragment[m[1]:]
+	}
+	title = strings.TrimSpace(leadingSectionRe.ReplaceAllString(title, ""))
+	content = strings.TrimSpace(leadingSectionRe.ReplaceAllString(cleanText(fragment), ""))
+	content = strings.TrimLeft(content, ":.— ")
+	if title == "" && content != "" {
+		// Untitled items (e.g. 5.1.1(v)) start with their first sentence.
+		title = content
+		if i := strings.IndexAny(title, ".:"); i > 0 {
+			title = title[:i]
+		}
+		if len(title) > 80 {
+			title = title[:77] + "..."
+		}
+	}
+	return strings.TrimRight(title, ":. "), content
+}
+
+func cleanText(s string) string {
+	s = tagRe.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(spaceRe.ReplaceAllString(s, " "))
+}
+
+// parentSection returns "5.1.1" for "5.1.1(v)", "5.1" for "5.1.1" and ""
+// for top-level sections.
+func parentSection(section string) string {
+	if i := strings.Index(section, "("); i > 0 {
+		return section[:i]
+	}
+	if i := strings.LastIndex(section, "."); i > 0 {
+		return section[:i]
+	}
+	return ""
+}
+
+func depth(section string) int {
+	return strings.Count(section, ".") + strings.Count(section, "(")
+}
+
+// compareSections orders sections numerically: 1.2 < 1.10, 5.1.1 < 5.1.1(i).
+func compareSections(a, b string) int {
+	pa := strings.FieldsFunc(a, func(r rune) bool { return r == '.' || r == '(' || r == ')' })
+	pb := strings.FieldsFunc(b, func(r rune) bool { return r == '.' || r == '(' || r == ')' })
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if pa[i] == pb[i] {
+			continue
+		}
+		var x, y int
+		_, errX := fmt.Sscan(pa[i], &x)
+		_, errY := fmt.Sscan(pb[i], &y)
+		if errX == nil && errY == nil {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+		if romanValue(pa[i]) < romanValue(pb[i]) {
+			return -1
+		}
+		return 1
+	}
+	return len(pa) - len(pb)
+}
+
+func romanValue(s string) int {
+	values := map[byte]int{'i': 1, 'v': 5, 'x': 10}
+	total := 0
+	for i := 0; i < len(s); i++ {
+		v := values[s[i]]
+		if i+1 < len(s) && values[s[i+1]] > v {
+			total -= v
+		} else {
+			total += v
+		}
+	}
+	return total
+}
+
+// parseReleaseNotes picks the guideline announcements from the Apple
+// Developer news feed, newest first.
+func parseReleaseNotes(feed []byte) []ReleaseNote {
+	var notes []ReleaseNote
+	for _, item := range rssItemRe.FindAllStringSubmatch(string(feed), -1) {
+		title := firstMatch(rssTitleRe, item[1])
+		if !guidelineNewsRe.MatchString(title) && !guidelineNewsRe.MatchString(firstMatch(rssDescRe, item[1])) {
+			continue
+		}
+		note := ReleaseNote{Title: cleanText(title), URL: strings.TrimSpace(firstMatch(rssLinkRe, item[1]))}
+		if t, err := time.Parse(time.RFC1123Z, strings.TrimSpace(firstMatch(rssDateRe, item[1]))); err == nil {
+			note.Date = t.Format("2006-01-02")
+		}
+		notes = append(notes, note)
+		if len(notes) == 10 {
+			break
+		}
+	}
+	return notes
+}
+
+func firstMatch(re *regexp.Regexp, s string) string {
+	if m := re.FindStringSubmatch(s); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// Save writes the guidelines as JSON, creating the directory if needed.
+func (db *DB) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,206 @@
+// Package history records the outcome of every scan so trends and
+// regressions can be reported across runs.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/findingorder"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Run is one recorded scan.
+type Run struct {
+	ID      string    `json:"id"`
+	Command string    `json:"command"` // "preflight", "codescan", "ipa", "scan"
+	Target  string    `json:"target"`  // absolute project or IPA path, or app ID
+	Time    time.Time `json:"time"`
+	Passed  bool      `json:"passed"`
+	// Critical counts CRITICAL (local scanners) and BLOCK (scan) findings.
+	Critical int       `json:"critical"`
+	Warns    int       `json:"warns"`
+	Infos    int       `json:"infos"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Finding is the part of a finding needed to follow it across runs.
+type Finding struct {
+	RuleID      string         `json:"rule_id"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    severity.Level `json:"severity"`
+	Title       string         `json:"title"`
+	File        string         `json:"file,omitempty"`
+	Line        int            `json:"line,omitempty"`
+}
+
+// Total is the number of findings in the run.
+func (r *Run) Total() int {
+	return r.Critical + r.Warns + r.Infos
+}
+
+// Dir returns where runs for a project are kept: the project's own
+// .greenlight/history when the project has a .greenlight directory,
+// otherwise ~/.greenlight/history.
+func Dir(projectPath string) (string, error) {
+	if projectPath != "" {
+		local := filepath.Join(projectPath, ".greenlight")
+		if info, err := os.Stat(local); err == nil && info.IsDir() {
+			return filepath.Join(local, "history"), nil
+		}
+	}
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+// Record counts the run's findings by severity, assigns it an ID and
+// writes it to dir.
+func Record(dir string, run *Run) error {
+	if run.Time.IsZero() {
+		run.Time = time.Now()
+	}
+	run.Critical, run.Warns, run.Infos = 0, 0, 0
+	for _, f := range run.Findings {
+		switch f.Severity {
+		case severity.Critical:
+			run.Critical++
+		case severity.Warn:
+			run.Warns++
+		default:
+			run.Infos++
+		}
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	// Runs within the same second get a numeric suffix.
+	base := run.Time.UTC().Format("20060102-150405") + "-" + run.Command
+	run.ID = base
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(dir, run.ID+".json")); os.IsNotExist(err) {
+			break
+		}
+		run.ID = fmt.Sprintf("%s-%d", base, n)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, run.ID+".json"), data, 0600)
+}
+
+// List returns the runs recorded in dir, oldest first.
+func List(dir string) ([]Run, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	for _, m := range matches {
+		data, err := os.ReadFile(m)
+		if err != nil {
+			continue
+		}
+		var run Run
+		if json.Unmarshal(data, &run) == nil && run.ID != "" {
+			runs = append(runs, run)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Time.Before(runs[j].Time) })
+	return runs, nil
+}
+
+// Find resolves a run reference against runs (oldest first): a run ID or a
+// unique prefix of one, "latest", or "previous" (the run before the latest
+// one of the same command and target).
+func Find(runs []Run, ref string) (*Run, error) {
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no recorded runs")
+	}
+	switch ref {
+	case "latest", "last":
+		return &runs[len(runs)-1], nil
+	case "previous", "prev":
+		latest := runs[len(runs)-1]
+		for i := len(runs) - 2; i >= 0; i-- {
+			if runs[i].Command == latest.Command && runs[i].Target == latest.Target {
+				return &runs[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no run before %s of the same command and target", latest.ID)
+	}
+	var found *Run
+	for i := range runs {
+		if runs[i].ID == ref {
+			return &runs[i], nil
+		}
+		if strings.HasPrefix(runs[i].ID, ref) {
+			if found != nil {
+				return nil, fmt.Errorf("run %q is ambiguous", ref)
+			}
+			found = &runs[i]
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no run %q (see 'greenlight history')", ref)
+	}
+	return found, nil
+}
+
+// Diff is the difference between the findings of two runs.
+type Diff struct {
+	Added     []Finding `json:"added"`
+	Resolved  []Finding `json:"resolved"`
+	Unchanged []Finding `json:"unchanged"`
+}
+
+// Compare matches findings by fingerprint: findings only in new are added,
+// findings only in old are resolved.
+func Compare(old, new []Finding) Diff {
+	key := func(f Finding) string {
+		if f.Fingerprint != "" {
+			return f.Fingerprint
+		}
+		return f.RuleID + "\x00" + f.File + "\x00" + f.Title
+	}
+	before := map[string]bool{}
+	for _, f := range old {
+		before[key(f)] = true
+	}
+	after := map[string]bool{}
+	d := Diff{Added: []Finding{}, Resolved: []Finding{}, Unchanged: []Finding{}}
+	for _, f := range new {
+		after[key(f)] = true
+		if before[key(f)] {
+			d.Unchanged = append(d.Unchanged, f)
+		} else {
+			d.Added = append(d.Added, f)
+		}
+	}
+	for _, f := range old {
+		if !after[key(f)] {
+			d.Resolved = append(d.Resolved, f)
+		}
+	}
+	for _, list := range [][]Finding{d.Added, d.Resolved, d.Unchanged} {
+		findingorder.Sort(list, func(f Finding) findingorder.Key {
+			return findingorder.Key{
+				Severity: f.Severity,
+				File:     f.File,
+				Line:     f.Line,
+				Title:    f.Title,
+				Detail:   f.RuleID,
+			}
+		})
+	}
+	return d
+}
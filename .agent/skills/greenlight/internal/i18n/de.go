@@ -0,0 +1,94 @@
+package i18n
+
+var de = map[string]string{
+	// Banners
+	"greenlight — know before you submit.":                           "greenlight — wissen, bevor Sie einreichen.",
+	"greenlight preflight — every check, one command, zero uploads.": "greenlight preflight — alle Prüfungen, ein Befehl, kein Upload.",
+	"greenlight codescan — find rejection risks in your code.":       "greenlight codescan — Ablehnungsrisiken im Code finden.",
+	"greenlight privacy — validate your privacy compliance.":         "greenlight privacy — Datenschutzanforderungen prüfen.",
+	"greenlight ipa — inspect your binary before submission.":        "greenlight ipa — das Binary vor dem Einreichen prüfen.",
+
+	// Report sections and summary
+	"CRITICAL — Will be rejected":                  "KRITISCH — wird abgelehnt",
+	"WARNING — High rejection risk":                "WARNUNG — hohes Ablehnungsrisiko",
+	"INFO — Best practices":                        "INFO — Best Practices",
+	"Critical — will be rejected":                  "Kritisch — wird abgelehnt",
+	"Warnings — high rejection risk":               "Warnungen — hohes Ablehnungsrisiko",
+	"Info — best practices":                        "Info — Best Practices",
+	"No issues found!":                             "Keine Probleme gefunden!",
+	"%d critical issue(s) must be fixed":           "%s kritische(s) Problem(e) müssen behoben werden",
+	"no critical issues found":                     "keine kritischen Probleme gefunden",
+	"no critical issues found in binary":           "keine kritischen Probleme im Binary gefunden",
+	"no critical issues found by the privacy scan": "der Datenschutz-Scan hat keine kritischen Probleme gefunden",
+	"%d findings":                                  "%s Befunde",
+	"%d critical":                                  "%s kritisch",
+	"%d warn":                                      "%s Warnung",
+	"%d info":                                      "%s Info",
+	"completed in %s":                              "abgeschlossen in %s",
+	"Completed in %s":                              "Abgeschlossen in %s",
+	"Fix:":                                         "Lösung:",
+	"Generated by greenlight in %s.":               "Von greenlight in %s erstellt.",
+	"Generated: %s":                                "Erstellt: %s",
+	"App Store compliance report — %s":             "App-Store-Konformitätsbericht — %s",
+	"%d files scanned":                             "%s Dateien gescannt",
+	"%d files scanned, %d skipped (%s)":            "%s Dateien gescannt, %s übersprungen (%s)",
+	"by scanner: %s":                               "nach Scanner: %s",
+	"Rejection risk: %d/100 (grade %s)":            "Ablehnungsrisiko: %s/100 (Note %s)",
+	"risk %d":                                      "Risiko %s",
+	"Unowned":                                      "Ohne Owner",
+
+	// Report context
+	"Project":                       "Projekt",
+	"App":                           "App",
+	"App ID":                        "App-ID",
+	"Bundle":                        "Bundle",
+	"IPA":                           "IPA",
+	"Size":                          "Größe",
+	"Privacy":                       "Datenschutz",
+	"APIs":                          "APIs",
+	"Tracking":                      "Tracking",
+	"PrivacyInfo.xcprivacy found":   "PrivacyInfo.xcprivacy vorhanden",
+	"found":                         "vorhanden",
+	"NOT found":                     "NICHT vorhanden",
+	"Required Reason APIs detected": "Erkannte Required Reason APIs",
+	"APIs declared in manifest":     "Im Manifest deklarierte APIs",
+	"Tracking SDKs found":           "Gefundene Tracking-SDKs",
+
+	// Findings
+	"No PrivacyInfo.xcprivacy found in project":                                                                                          "Keine PrivacyInfo.xcprivacy im Projekt gefunden",
+	"Privacy manifests are required since May 2024. Missing it triggers ITMS-91061.":                                                     "Datenschutzmanifeste sind seit Mai 2024 Pflicht. Fehlt es, wird ITMS-91061 ausgelöst.",
+	"Create a PrivacyInfo.xcprivacy file in your project. See: developer.apple.com/documentation/bundleresources/privacy-manifest-files": "Legen Sie eine PrivacyInfo.xcprivacy-Datei im Projekt an. Siehe: developer.apple.com/documentation/bundleresources/privacy-manifest-files",
+	"Hardcoded secret detected: %s":                                                                                                      "Fest codiertes Geheimnis gefunden: %s",
+	"%s found in source. Credentials in the app binary can be extracted by anyone who downloads it.":                                     "%s im Quellcode gefunden. Zugangsdaten im App-Binary kann jeder auslesen, der die App lädt.",
+	"Revoke and rotate this credential, then move it to your backend. Apps should never ship server-side keys.":                          "Widerrufen und erneuern Sie diese Zugangsdaten und verlagern Sie sie ins Backend. Apps dürfen keine serverseitigen Schlüssel enthalten.",
+	"Required Reason API used but not declared: %s":                                                                                      "Required Reason API verwendet, aber nicht deklariert: %s",
+	"%s usage detected in code but not in PrivacyInfo.xcprivacy. Found in %s":                                                            "Verwendung von %s im Code erkannt, aber nicht in PrivacyInfo.xcprivacy deklariert. Gefunden in %s",
+	"Add %s to NSPrivacyAccessedAPITypes in your PrivacyInfo.xcprivacy with the appropriate reason.":                                     "Tragen Sie %s mit dem passenden Grund unter NSPrivacyAccessedAPITypes in PrivacyInfo.xcprivacy ein.",
+	"Required Reason API used without privacy manifest: %s":                                                                              "Required Reason API ohne Datenschutzmanifest verwendet: %s",
+	"%s detected in code. Found in %s":                                                                                                   "%s im Code erkannt. Gefunden in %s",
+	"Create PrivacyInfo.xcprivacy and declare %s with the appropriate reason.":                                                           "Legen Sie PrivacyInfo.xcprivacy an und deklarieren Sie %s mit dem passenden Grund.",
+	"Tracking SDKs detected without ATT implementation":                                                                                  "Tracking-SDKs ohne ATT-Implementierung erkannt",
+	"Found: %s. App Tracking Transparency prompt is required before any tracking.":                                                       "Gefunden: %s. Vor jedem Tracking ist die App-Tracking-Transparency-Abfrage erforderlich.",
+	"Import AppTrackingTransparency and call requestTrackingAuthorization() before initializing any tracking SDK.":                       "Importieren Sie AppTrackingTransparency und rufen Sie requestTrackingAuthorization() auf, bevor ein Tracking-SDK initialisiert wird.",
+	"Placeholder app name detected":                                                                                                      "Platzhalter-App-Name erkannt",
+	"The app name looks like a placeholder.":                                                                                             "Der App-Name sieht nach einem Platzhalter aus.",
+	"Set a proper app name before submitting.":                                                                                           "Legen Sie vor dem Einreichen einen richtigen App-Namen fest.",
+	"Private API usage detected":                                                                                                         "Verwendung privater APIs erkannt",
+	"External payment for potentially digital goods":                                                                                     "Externe Zahlung für möglicherweise digitale Güter",
+	"Cryptocurrency mining detected":                                                                                                     "Kryptowährungs-Mining erkannt",
+	"Dynamic code execution detected":                                                                                                    "Dynamische Codeausführung erkannt",
+	"Ad/tracking SDK without ATT implementation":                                                                                         "Werbe-/Tracking-SDK ohne ATT-Implementierung",
+	"Social login without Sign in with Apple":                                                                                            "Social Login ohne „Mit Apple anmelden“",
+	"In-app purchases without restore functionality":                                                                                     "In-App-Käufe ohne Wiederherstellen-Funktion",
+	"Account creation without account deletion":                                                                                          "Kontoerstellung ohne Kontolöschung",
+	"Reference to competing platform":                                                                                                    "Verweis auf konkurrierende Plattform",
+	"Placeholder content in user-facing strings":                                                                                         "Platzhalterinhalt in sichtbaren Texten",
+	"Debug logging in production code":                                                                                                   "Debug-Logging im Produktionscode",
+	"Hardcoded IPv4 address":                                                                                                             "Fest codierte IPv4-Adresse",
+	"Insecure HTTP URL":                                                                                                                  "Unsicherer HTTP-URL",
+	"WebView-only app pattern detected":                                                                                                  "Reines WebView-App-Muster erkannt",
+	"Vague permission purpose string":                                                                                                    "Vager Zweckhinweis für Berechtigung",
+	"Info.plist missing required privacy keys":                                                                                           "Info.plist fehlen erforderliche Datenschutzschlüssel",
+	"Privacy manifest declares tracking but no tracking SDKs detected":                                                                   "Datenschutzmanifest deklariert Tracking, aber keine Tracking-SDKs erkannt",
+	"Hardcoded secret in the JS bundle: %s":                                                                                              "Fest codiertes Geheimnis im JS-Bundle: %s",
+}
@@ -0,0 +1,94 @@
+package i18n
+
+var es = map[string]string{
+	// Banners
+	"greenlight — know before you submit.":                           "greenlight — sepa antes de enviar.",
+	"greenlight preflight — every check, one command, zero uploads.": "greenlight preflight — todas las comprobaciones, un comando, sin subir nada.",
+	"greenlight codescan — find rejection risks in your code.":       "greenlight codescan — encuentre riesgos de rechazo en su código.",
+	"greenlight privacy — validate your privacy compliance.":         "greenlight privacy — valide el cumplimiento de privacidad.",
+	"greenlight ipa — inspect your binary before submission.":        "greenlight ipa — inspeccione el binario antes de enviarlo.",
+
+	// Report sections and summary
+	"CRITICAL — Will be rejected":                  "CRÍTICO — será rechazada",
+	"WARNING — High rejection risk":                "ADVERTENCIA — alto riesgo de rechazo",
+	"INFO — Best practices":                        "INFO — buenas prácticas",
+	"Critical — will be rejected":                  "Crítico — será rechazada",
+	"Warnings — high rejection risk":               "Advertencias — alto riesgo de rechazo",
+	"Info — best practices":                        "Info — buenas prácticas",
+	"No issues found!":                             "¡No se encontraron problemas!",
+	"%d critical issue(s) must be fixed":           "hay que corregir %s problema(s) crítico(s)",
+	"no critical issues found":                     "no se encontraron problemas críticos",
+	"no critical issues found in binary":           "no se encontraron problemas críticos en el binario",
+	"no critical issues found by the privacy scan": "el análisis de privacidad no encontró problemas críticos",
+	"%d findings":                                  "%s hallazgos",
+	"%d critical":                                  "%s críticos",
+	"%d warn":                                      "%s advertencias",
+	"%d info":                                      "%s info",
+	"completed in %s":                              "completado en %s",
+	"Completed in %s":                              "Completado en %s",
+	"Fix:":                                         "Solución:",
+	"Generated by greenlight in %s.":               "Generado por greenlight en %s.",
+	"Generated: %s":                                "Generado: %s",
+	"App Store compliance report — %s":             "Informe de cumplimiento de App Store — %s",
+	"%d files scanned":                             "%s archivos analizados",
+	"%d files scanned, %d skipped (%s)":            "%s archivos analizados, %s omitidos (%s)",
+	"by scanner: %s":                               "por analizador: %s",
+	"Rejection risk: %d/100 (grade %s)":            "Riesgo de rechazo: %s/100 (nota %s)",
+	"risk %d":                                      "riesgo %s",
+	"Unowned":                                      "Sin propietario",
+
+	// Report context
+	"Project":                       "Proyecto",
+	"App":                           "App",
+	"App ID":                        "ID de la app",
+	"Bundle":                        "Bundle",
+	"IPA":                           "IPA",
+	"Size":                          "Tamaño",
+	"Privacy":                       "Privacidad",
+	"APIs":                          "API",
+	"Tracking":                      "Rastreo",
+	"PrivacyInfo.xcprivacy found":   "PrivacyInfo.xcprivacy encontrado",
+	"found":                         "encontrado",
+	"NOT found":                     "NO encontrado",
+	"Required Reason APIs detected": "API de Required Reason detectadas",
+	"APIs declared in manifest":     "API declaradas en el manifiesto",
+	"Tracking SDKs found":           "SDK de rastreo encontrados",
+
+	// Findings
+	"No PrivacyInfo.xcprivacy found in project":                                                                                          "No se encontró PrivacyInfo.xcprivacy en el proyecto",
+	"Privacy manifests are required since May 2024. Missing it triggers ITMS-91061.":                                                     "Los manifiestos de privacidad son obligatorios desde mayo de 2024. Si falta, se produce ITMS-91061.",
+	"Create a PrivacyInfo.xcprivacy file in your project. See: developer.apple.com/documentation/bundleresources/privacy-manifest-files": "Cree un archivo PrivacyInfo.xcprivacy en su proyecto. Consulte: developer.apple.com/documentation/bundleresources/privacy-manifest-files",
+	"Hardcoded secret detected: %s":                                                                                                      "Secreto escrito en el código: %s",
+	"%s found in source. Credentials in the app binary can be extracted by anyone who downloads it.":                                     "Se encontró %s en el código fuente. Cualquiera que descargue la app puede extraer las credenciales del binario.",
+	"Revoke and rotate this credential, then move it to your backend. Apps should never ship server-side keys.":                          "Revoque y renueve esta credencial y muévala a su backend. Las apps nunca deben incluir claves de servidor.",
+	"Required Reason API used but not declared: %s":                                                                                      "API de Required Reason usada pero no declarada: %s",
+	"%s usage detected in code but not in PrivacyInfo.xcprivacy. Found in %s":                                                            "Se detectó el uso de %s en el código, pero no está en PrivacyInfo.xcprivacy. Encontrado en %s",
+	"Add %s to NSPrivacyAccessedAPITypes in your PrivacyInfo.xcprivacy with the appropriate reason.":                                     "Añada %s a NSPrivacyAccessedAPITypes en PrivacyInfo.xcprivacy con el motivo adecuado.",
+	"Required Reason API used without privacy manifest: %s":                                                                              "API de Required Reason usada sin manifiesto de privacidad: %s",
+	"%s detected in code. Found in %s":                                                                                                   "Se detectó %s en el código. Encontrado en %s",
+	"Create PrivacyInfo.xcprivacy and declare %s with the appropriate reason.":                                                           "Cree PrivacyInfo.xcprivacy y declare %s con el motivo adecuado.",
+	"Tracking SDKs detected without ATT implementation":                                                                                  "SDK de rastreo detectados sin implementar ATT",
+	"Found: %s. App Tracking Transparency prompt is required before any tracking.":                                                       "Encontrado: %s. Se requiere la solicitud de App Tracking Transparency antes de cualquier rastreo.",
+	"Import AppTrackingTransparency and call requestTrackingAuthorization() before initializing any tracking SDK.":                       "Importe AppTrackingTransparency y llame a requestTrackingAuthorization() antes de inicializar cualquier SDK de rastreo.",
+	"Placeholder app name detected":                                                                                                      "Nombre de app provisional detectado",
+	"The app name looks like a placeholder.":                                                                                             "El nombre de la app parece provisional.",
+	"Set a proper app name before submitting.":                                                                                           "Establezca un nombre de app definitivo antes de enviarla.",
+	"Private API usage detected":                                                                                                         "Uso de API privada detectado",
+	"External payment for potentially digital goods":                                                                                     "Pago externo para posibles bienes digitales",
+	"Cryptocurrency mining detected":                                                                                                     "Minería de criptomonedas detectada",
+	"Dynamic code execution detected":                                                                                                    "Ejecución dinámica de código detectada",
+	"Ad/tracking SDK without ATT implementation":                                                                                         "SDK de anuncios/rastreo sin implementar ATT",
+	"Social login without Sign in with Apple":                                                                                            "Inicio de sesión social sin Iniciar sesión con Apple",
+	"In-app purchases without restore functionality":                                                                                     "Compras dentro de la app sin función de restaurar",
+	"Account creation without account deletion":                                                                                          "Creación de cuenta sin eliminación de cuenta",
+	"Reference to competing platform":                                                                                                    "Referencia a una plataforma competidora",
+	"Placeholder content in user-facing strings":                                                                                         "Contenido provisional en textos visibles",
+	"Debug logging in production code":                                                                                                   "Registro de depuración en código de producción",
+	"Hardcoded IPv4 address":                                                                                                             "Dirección IPv4 escrita en el código",
+	"Insecure HTTP URL":                                                                                                                  "URL HTTP no segura",
+	"WebView-only app pattern detected":                                                                                                  "Patrón de app solo WebView detectado",
+	"Vague permission purpose string":                                                                                                    "Texto de propósito del permiso poco claro",
+	"Info.plist missing required privacy keys":                                                                                           "Faltan claves de privacidad obligatorias en Info.plist",
+	"Privacy manifest declares tracking but no tracking SDKs detected":                                                                   "El manifiesto de privacidad declara rastreo, pero no se detectaron SDK de rastreo",
+	"Hardcoded secret in the JS bundle: %s":                                                                                              "Secreto escrito en el bundle JS: %s",
+}
@@ -0,0 +1,137 @@
+// Package i18n translates the text of human-readable reports: the terminal
+// UI, section headings and verdicts, and finding titles, details and fixes.
+//
+// Catalogs map English text to its translation. A key may contain %s and %d
+// placeholders; it then also translates text built from it, so "Hardcoded
+// secret detected: Stripe live secret key" is found under "Hardcoded secret
+// detected: %s". Translations refer to the placeholders' values with %s, or
+// %[n]s to reorder them. Text without a translation stays in English.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Languages are the report languages, English first.
+var Languages = []string{"en", "ja", "zh-Hans", "de", "es"}
+
+var catalogs = map[string]map[string]string{
+	"ja":      ja,
+	"zh-Hans": zhHans,
+	"de":      de,
+	"es":      es,
+}
+
+// pattern translates text built from a catalog key with placeholders.
+type pattern struct {
+	re          *regexp.Regexp
+	translation string
+}
+
+var (
+	lang     = "en"
+	messages map[string]string
+	patterns []pattern
+)
+
+// Normalize resolves a language tag ("ja-JP", "zh_CN", "DE") to one of
+// Languages.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	lower := strings.ToLower(tag)
+	switch {
+	case lower == "":
+		return "", false
+	case lower == "zh" || strings.HasPrefix(lower, "zh-hans") || lower == "zh-cn" || lower == "zh-sg":
+		return "zh-Hans", true
+	}
+	base, _, _ := strings.Cut(lower, "-")
+	for _, l := range Languages {
+		if l == base {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// SetLanguage selects the language reports are written in.
+func SetLanguage(tag string) error {
+	l, ok := Normalize(tag)
+	if !ok {
+		return fmt.Errorf("unsupported language %q (use %s)", tag, strings.Join(Languages, ", "))
+	}
+	lang, messages, patterns = l, catalogs[l], nil
+	keys := make([]string, 0, len(messages))
+	for key := range messages {
+		if strings.Contains(key, "%s") || strings.Contains(key, "%d") {
+			keys = append(keys, key)
+		}
+	}
+	// Longer keys first, so the most specific pattern wins.
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		expr := regexp.QuoteMeta(key)
+		expr = strings.ReplaceAll(expr, "%s", "(.+?)")
+		expr = strings.ReplaceAll(expr, "%d", `(\d+)`)
+		patterns = append(patterns, pattern{regexp.MustCompile("(?s)^" + expr + "$"), messages[key]})
+	}
+	return nil
+}
+
+// Language is the selected language.
+func Language() string {
+	return lang
+}
+
+// Active reports whether reports are translated, i.e. the language isn't
+// English.
+func Active() bool {
+	return lang != "en"
+}
+
+// Latin reports whether the language is written in the Latin script, which
+// the PDF report's fonts are limited to.
+func Latin() bool {
+	return lang != "ja" && lang != "zh-Hans"
+}
+
+// T translates s: its catalog entry, or the pattern it was built from.
+func T(s string) string {
+	if messages == nil || s == "" {
+		return s
+	}
+	if t, ok := messages[s]; ok {
+		return t
+	}
+	for _, p := range patterns {
+		m := p.re.FindStringSubmatch(s)
+		if m == nil {
+			continue
+		}
+		args := make([]any, len(m)-1)
+		for i, v := range m[1:] {
+			args[i] = T(v)
+		}
+		return fmt.Sprintf(p.translation, args...)
+	}
+	return s
+}
+
+var verbRe = regexp.MustCompile(`%(\[\d+\])?s`)
+
+// Sprintf formats with the translation of format.
+func Sprintf(format string, args ...any) string {
+	if t, ok := messages[format]; ok {
+		// Translations use %s for every placeholder; the values may be numbers.
+		format = verbRe.ReplaceAllString(t, "%${1}v")
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,94 @@
+package i18n
+
+var ja = map[string]string{
+	// Banners
+	"greenlight — know before you submit.":                           "greenlight — 提出前に把握する。",
+	"greenlight preflight — every check, one command, zero uploads.": "greenlight preflight — すべてのチェックを 1 コマンドで、アップロード不要。",
+	"greenlight codescan — find rejection risks in your code.":       "greenlight codescan — コード内のリジェクトリスクを検出。",
+	"greenlight privacy — validate your privacy compliance.":         "greenlight privacy — プライバシー要件への準拠を検証。",
+	"greenlight ipa — inspect your binary before submission.":        "greenlight ipa — 提出前にバイナリを検査。",
+
+	// Report sections and summary
+	"CRITICAL — Will be rejected":                  "重大 — リジェクトされます",
+	"WARNING — High rejection risk":                "警告 — リジェクトの可能性が高い",
+	"INFO — Best practices":                        "情報 — ベストプラクティス",
+	"Critical — will be rejected":                  "重大 — リジェクトされます",
+	"Warnings — high rejection risk":               "警告 — リジェクトの可能性が高い",
+	"Info — best practices":                        "情報 — ベストプラクティス",
+	"No issues found!":                             "問題は見つかりませんでした。",
+	"%d critical issue(s) must be fixed":           "%s 件の重大な問題を修正する必要があります",
+	"no critical issues found":                     "重大な問題は見つかりませんでした",
+	"no critical issues found in binary":           "バイナリに重大な問題は見つかりませんでした",
+	"no critical issues found by the privacy scan": "プライバシースキャンで重大な問題は見つかりませんでした",
+	"%d findings":                                  "指摘 %s 件",
+	"%d critical":                                  "重大 %s",
+	"%d warn":                                      "警告 %s",
+	"%d info":                                      "情報 %s",
+	"completed in %s":                              "所要時間 %s",
+	"Completed in %s":                              "所要時間 %s",
+	"Fix:":                                         "修正:",
+	"Generated by greenlight in %s.":               "greenlight により %s で生成。",
+	"Generated: %s":                                "生成日時: %s",
+	"App Store compliance report — %s":             "App Store 準拠レポート — %s",
+	"%d files scanned":                             "%s 個のファイルをスキャン",
+	"%d files scanned, %d skipped (%s)":            "%s 個のファイルをスキャン、%s 個をスキップ (%s)",
+	"by scanner: %s":                               "スキャナー別: %s",
+	"Rejection risk: %d/100 (grade %s)":            "リジェクトリスク: %s/100 (評価 %s)",
+	"risk %d":                                      "リスク %s",
+	"Unowned":                                      "所有者なし",
+
+	// Report context
+	"Project":                       "プロジェクト",
+	"App":                           "アプリ",
+	"App ID":                        "アプリ ID",
+	"Bundle":                        "バンドル",
+	"IPA":                           "IPA",
+	"Size":                          "サイズ",
+	"Privacy":                       "プライバシー",
+	"APIs":                          "API",
+	"Tracking":                      "トラッキング",
+	"PrivacyInfo.xcprivacy found":   "PrivacyInfo.xcprivacy あり",
+	"found":                         "あり",
+	"NOT found":                     "なし",
+	"Required Reason APIs detected": "検出された Required Reason API",
+	"APIs declared in manifest":     "マニフェストで宣言された API",
+	"Tracking SDKs found":           "検出されたトラッキング SDK",
+
+	// Findings
+	"No PrivacyInfo.xcprivacy found in project":                                                                                          "プロジェクトに PrivacyInfo.xcprivacy がありません",
+	"Privacy manifests are required since May 2024. Missing it triggers ITMS-91061.":                                                     "2024 年 5 月以降、プライバシーマニフェストは必須です。ない場合は ITMS-91061 が発生します。",
+	"Create a PrivacyInfo.xcprivacy file in your project. See: developer.apple.com/documentation/bundleresources/privacy-manifest-files": "プロジェクトに PrivacyInfo.xcprivacy ファイルを作成してください。参照: developer.apple.com/documentation/bundleresources/privacy-manifest-files",
+	"Hardcoded secret detected: %s":                                                                                                      "ハードコードされたシークレットを検出: %s",
+	"%s found in source. Credentials in the app binary can be extracted by anyone who downloads it.":                                     "ソースコードに %s があります。アプリバイナリ内の認証情報は、ダウンロードした誰もが抽出できます。",
+	"Revoke and rotate this credential, then move it to your backend. Apps should never ship server-side keys.":                          "この認証情報を失効・再発行し、バックエンドへ移してください。サーバー側のキーをアプリに含めてはいけません。",
+	"Required Reason API used but not declared: %s":                                                                                      "Required Reason API が使用されていますが宣言されていません: %s",
+	"%s usage detected in code but not in PrivacyInfo.xcprivacy. Found in %s":                                                            "コードで %s の使用を検出しましたが、PrivacyInfo.xcprivacy にありません。検出箇所: %s",
+	"Add %s to NSPrivacyAccessedAPITypes in your PrivacyInfo.xcprivacy with the appropriate reason.":                                     "PrivacyInfo.xcprivacy の NSPrivacyAccessedAPITypes に、適切な理由とともに %s を追加してください。",
+	"Required Reason API used without privacy manifest: %s":                                                                              "プライバシーマニフェストなしで Required Reason API を使用: %s",
+	"%s detected in code. Found in %s":                                                                                                   "コードで %s を検出。検出箇所: %s",
+	"Create PrivacyInfo.xcprivacy and declare %s with the appropriate reason.":                                                           "PrivacyInfo.xcprivacy を作成し、適切な理由とともに %s を宣言してください。",
+	"Tracking SDKs detected without ATT implementation":                                                                                  "ATT を実装せずにトラッキング SDK を使用しています",
+	"Found: %s. App Tracking Transparency prompt is required before any tracking.":                                                       "検出: %s。トラッキングの前に App Tracking Transparency の許可を求める必要があります。",
+	"Import AppTrackingTransparency and call requestTrackingAuthorization() before initializing any tracking SDK.":                       "AppTrackingTransparency をインポートし、トラッキング SDK を初期化する前に requestTrackingAuthorization() を呼び出してください。",
+	"Placeholder app name detected":                                                                                                      "仮のアプリ名を検出",
+	"The app name looks like a placeholder.":                                                                                             "アプリ名が仮の名前のようです。",
+	"Set a proper app name before submitting.":                                                                                           "提出前に正式なアプリ名を設定してください。",
+	"Private API usage detected":                                                                                                         "非公開 API の使用を検出",
+	"External payment for potentially digital goods":                                                                                     "デジタル商品に外部決済を使用している可能性",
+	"Cryptocurrency mining detected":                                                                                                     "暗号通貨マイニングを検出",
+	"Dynamic code execution detected":                                                                                                    "動的なコード実行を検出",
+	"Ad/tracking SDK without ATT implementation":                                                                                         "ATT を実装していない広告/トラッキング SDK",
+	"Social login without Sign in with Apple":                                                                                            "Sign in with Apple なしのソーシャルログイン",
+	"In-app purchases without restore functionality":                                                                                     "復元機能のないアプリ内課金",
+	"Account creation without account deletion":                                                                                          "アカウント削除のないアカウント作成",
+	"Reference to competing platform":                                                                                                    "競合プラットフォームへの言及",
+	"Placeholder content in user-facing strings":                                                                                         "ユーザー向け文字列に仮のコンテンツ",
+	"Debug logging in production code":                                                                                                   "本番コードにデバッグログ",
+	"Hardcoded IPv4 address":                                                                                                             "ハードコードされた IPv4 アドレス",
+	"Insecure HTTP URL":                                                                                                                  "安全でない HTTP URL",
+	"WebView-only app pattern detected":                                                                                                  "WebView だけのアプリ構成を検出",
+	"Vague permission purpose string":                                                                                                    "あいまいな権限の利用目的文字列",
+	"Info.plist missing required privacy keys":                                                                                           "Info.plist に必要なプライバシーキーがありません",
+	"Privacy manifest declares tracking but no tracking SDKs detected":                                                                   "プライバシーマニフェストはトラッキングを宣言していますが、トラッキング SDK は検出されません",
+	"Hardcoded secret in the JS bundle: %s":                                                                                              "JS バンドル内のハードコードされたシークレット: %s",
+}
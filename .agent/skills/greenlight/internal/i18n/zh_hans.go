@@ -0,0 +1,94 @@
+package i18n
+
+var zhHans = map[string]string{
+	// Banners
+	"greenlight — know before you submit.":                           "greenlight — 提交之前，心中有数。",
+	"greenlight preflight — every check, one command, zero uploads.": "greenlight preflight — 一条命令完成全部检查，无需上传。",
+	"greenlight codescan — find rejection risks in your code.":       "greenlight codescan — 找出代码中的被拒风险。",
+	"greenlight privacy — validate your privacy compliance.":         "greenlight privacy — 检查隐私合规性。",
+	"greenlight ipa — inspect your binary before submission.":        "greenlight ipa — 提交前检查二进制文件。",
+
+	// Report sections and summary
+	"CRITICAL — Will be rejected":                  "严重 — 将被拒绝",
+	"WARNING — High rejection risk":                "警告 — 被拒风险高",
+	"INFO — Best practices":                        "信息 — 最佳实践",
+	"Critical — will be rejected":                  "严重 — 将被拒绝",
+	"Warnings — high rejection risk":               "警告 — 被拒风险高",
+	"Info — best practices":                        "信息 — 最佳实践",
+	"No issues found!":                             "未发现问题！",
+	"%d critical issue(s) must be fixed":           "必须修复 %s 个严重问题",
+	"no critical issues found":                     "未发现严重问题",
+	"no critical issues found in binary":           "二进制文件中未发现严重问题",
+	"no critical issues found by the privacy scan": "隐私扫描未发现严重问题",
+	"%d findings":                                  "%s 个问题",
+	"%d critical":                                  "%s 个严重",
+	"%d warn":                                      "%s 个警告",
+	"%d info":                                      "%s 个信息",
+	"completed in %s":                              "用时 %s",
+	"Completed in %s":                              "用时 %s",
+	"Fix:":                                         "修复:",
+	"Generated by greenlight in %s.":               "由 greenlight 生成，用时 %s。",
+	"Generated: %s":                                "生成时间：%s",
+	"App Store compliance report — %s":             "App Store 合规报告 — %s",
+	"%d files scanned":                             "已扫描 %s 个文件",
+	"%d files scanned, %d skipped (%s)":            "已扫描 %s 个文件，跳过 %s 个（%s）",
+	"by scanner: %s":                               "按扫描器：%s",
+	"Rejection risk: %d/100 (grade %s)":            "被拒风险：%s/100（等级 %s）",
+	"risk %d":                                      "风险 %s",
+	"Unowned":                                      "无所有者",
+
+	// Report context
+	"Project":                       "项目",
+	"App":                           "应用",
+	"App ID":                        "应用 ID",
+	"Bundle":                        "Bundle ID",
+	"IPA":                           "IPA",
+	"Size":                          "大小",
+	"Privacy":                       "隐私",
+	"APIs":                          "API",
+	"Tracking":                      "跟踪",
+	"PrivacyInfo.xcprivacy found":   "已找到 PrivacyInfo.xcprivacy",
+	"found":                         "已找到",
+	"NOT found":                     "未找到",
+	"Required Reason APIs detected": "检测到的 Required Reason API",
+	"APIs declared in manifest":     "清单中声明的 API",
+	"Tracking SDKs found":           "检测到的跟踪 SDK",
+
+	// Findings
+	"No PrivacyInfo.xcprivacy found in project":                                                                                          "项目中未找到 PrivacyInfo.xcprivacy",
+	"Privacy manifests are required since May 2024. Missing it triggers ITMS-91061.":                                                     "自 2024 年 5 月起必须提供隐私清单，缺少时会触发 ITMS-91061。",
+	"Create a PrivacyInfo.xcprivacy file in your project. See: developer.apple.com/documentation/bundleresources/privacy-manifest-files": "在项目中创建 PrivacyInfo.xcprivacy 文件。参见：developer.apple.com/documentation/bundleresources/privacy-manifest-files",
+	"Hardcoded secret detected: %s":                                                                                                      "检测到硬编码密钥：%s",
+	"%s found in source. Credentials in the app binary can be extracted by anyone who downloads it.":                                     "源代码中发现 %s。应用二进制文件中的凭据可被任何下载者提取。",
+	"Revoke and rotate this credential, then move it to your backend. Apps should never ship server-side keys.":                          "吊销并轮换此凭据，然后将其移到后端。应用绝不应包含服务器端密钥。",
+	"Required Reason API used but not declared: %s":                                                                                      "使用了 Required Reason API 但未声明：%s",
+	"%s usage detected in code but not in PrivacyInfo.xcprivacy. Found in %s":                                                            "代码中检测到 %s 的使用，但 PrivacyInfo.xcprivacy 中未声明。位置：%s",
+	"Add %s to NSPrivacyAccessedAPITypes in your PrivacyInfo.xcprivacy with the appropriate reason.":                                     "在 PrivacyInfo.xcprivacy 的 NSPrivacyAccessedAPITypes 中添加 %s 并注明适当的理由。",
+	"Required Reason API used without privacy manifest: %s":                                                                              "在没有隐私清单的情况下使用了 Required Reason API：%s",
+	"%s detected in code. Found in %s":                                                                                                   "代码中检测到 %s。位置：%s",
+	"Create PrivacyInfo.xcprivacy and declare %s with the appropriate reason.":                                                           "创建 PrivacyInfo.xcprivacy 并以适当的理由声明 %s。",
+	"Tracking SDKs detected without ATT implementation":                                                                                  "检测到跟踪 SDK 但未实现 ATT",
+	"Found: %s. App Tracking Transparency prompt is required before any tracking.":                                                       "发现：%s。进行任何跟踪之前都必须显示 App Tracking Transparency 授权请求。",
+	"Import AppTrackingTransparency and call requestTrackingAuthorization() before initializing any tracking SDK.":                       "导入 AppTrackingTransparency，并在初始化任何跟踪 SDK 之前调用 requestTrackingAuthorization()。",
+	"Placeholder app name detected":                                                                                                      "检测到占位应用名称",
+	"The app name looks like a placeholder.":                                                                                             "应用名称看起来是占位名称。",
+	"Set a proper app name before submitting.":                                                                                           "提交前请设置正式的应用名称。",
+	"Private API usage detected":                                                                                                         "检测到私有 API 的使用",
+	"External payment for potentially digital goods":                                                                                     "可能对数字商品使用了外部支付",
+	"Cryptocurrency mining detected":                                                                                                     "检测到加密货币挖矿",
+	"Dynamic code execution detected":                                                                                                    "检测到动态代码执行",
+	"Ad/tracking SDK without ATT implementation":                                                                                         "广告/跟踪 SDK 未实现 ATT",
+	"Social login without Sign in with Apple":                                                                                            "提供社交登录但未提供“通过 Apple 登录”",
+	"In-app purchases without restore functionality":                                                                                     "App 内购买缺少恢复功能",
+	"Account creation without account deletion":                                                                                          "可创建账户但无法删除账户",
+	"Reference to competing platform":                                                                                                    "提及竞争平台",
+	"Placeholder content in user-facing strings":                                                                                         "面向用户的字符串中有占位内容",
+	"Debug logging in production code":                                                                                                   "生产代码中有调试日志",
+	"Hardcoded IPv4 address":                                                                                                             "硬编码的 IPv4 地址",
+	"Insecure HTTP URL":                                                                                                                  "不安全的 HTTP URL",
+	"WebView-only app pattern detected":                                                                                                  "检测到仅 WebView 的应用模式",
+	"Vague permission purpose string":                                                                                                    "权限用途说明过于含糊",
+	"Info.plist missing required privacy keys":                                                                                           "Info.plist 缺少必需的隐私键",
+	"Privacy manifest declares tracking but no tracking SDKs detected":                                                                   "隐私清单声明了跟踪，但未检测到跟踪 SDK",
+	"Hardcoded secret in the JS bundle: %s":                                                                                              "JS 包中的硬编码密钥：%s",
+}
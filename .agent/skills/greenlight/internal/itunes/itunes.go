@@ -0,0 +1,207 @@
+// Package itunes searches the App Store through Apple's public iTunes Search
+// API, which needs no credentials.
+package itunes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultURL is the iTunes Search API; GREENLIGHT_ITUNES_URL overrides it,
+// e.g. for a mirror inside a network without internet access.
+const DefaultURL = "https://itunes.apple.com"
+
+// URLEnv names the environment variable that overrides DefaultURL.
+const URLEnv = "GREENLIGHT_ITUNES_URL"
+
+const (
+	// MaxLimit is the most results the API returns for one search.
+	MaxLimit = 200
+	// maxResponse bounds one API response.
+	maxResponse = 8 << 20
+)
+
+// App is an App Store app in search results.
+type App struct {
+	TrackID           int64   `json:"trackId"`
+	Name              string  `json:"trackName"`
+	BundleID          string  `json:"bundleId"`
+	Seller            string  `json:"sellerName"`
+	Developer         string  `json:"artistName"`
+	URL               string  `json:"trackViewUrl"`
+	PrimaryGenre      string  `json:"primaryGenreName"`
+	PrimaryGenreID    int     `json:"primaryGenreId"`
+	Description       string  `json:"description"`
+	UserRatingCount   int     `json:"userRatingCount"`
+	AverageUserRating float64 `json:"averageUserRating"`
+}
+
+// Query is a search of the App Store in one country.
+type Query struct {
+	Term string
+	// Country is the two-letter storefront code; "us" when empty.
+	Country string
+	// GenreID limits results to an App Store category, e.g. 6013 for
+	// Health & Fitness.
+	GenreID int
+	// Limit is the number of results, at most MaxLimit; the API returns 50
+	// when it is 0.
+	Limit int
+}
+
+// Client queries the iTunes Search API.
+type Client struct {
+	HTTP *http.Client
+	URL  string
+}
+
+// NewClient returns a client of the API at GREENLIGHT_ITUNES_URL, or
+// DefaultURL.
+func NewClient(client *http.Client) *Client {
+	base := os.Getenv(URLEnv)
+	if base == "" {
+		base = DefaultURL
+	}
+	return &Client{HTTP: client, URL: strings.TrimSuffix(base, "/")}
+}
+
+// Search returns the iPhone and iPad apps matching q, ranked as the App
+// Store ranks them.
+func (c *Client) Search(ctx context.Context, q Query) ([]App, error) {
+	v := url.Values{}
+	v.Set("term", q.Term)
+	v.Set("entity", "software")
+	v.Set("media", "software")
+	country := q.Country
+	if country == "" {
+		country = "us"
+	}
+	v.Set("country", strings.ToLower(country))
+	if q.GenreID != 0 {
+		v.Set("genreId", strconv.Itoa(q.GenreID))
+	}
+	if q.Limit > 0 {
+		v.Set("limit", strconv.Itoa(min(q.Limit, MaxLimit)))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+"/search?"+v.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "greenlight")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("itunes: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("itunes: search %q: %s", q.Term, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse+1))
+	if err != nil {
+		return nil, fmt.Errorf("itunes: %w", err)
+	}
+	if len(data) > maxResponse {
+		return nil, fmt.Errorf("itunes: search %q: response larger than %d bytes", q.Term, maxResponse)
+	}
+	var out struct {
+		Results []App `json:"results"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("itunes: search %q: %w", q.Term, err)
+	}
+	return out.Results, nil
+}
+
+// Country returns the storefront country for an App Store Connect locale:
+// "de" for de-DE, "jp" for ja, "cn" for zh-Hans. It is "" when the locale
+// names no country and has no usual one.
+func Country(locale string) string {
+	lang, region, _ := strings.Cut(locale, "-")
+	if len(region) == 2 {
+		return strings.ToLower(region)
+	}
+	switch strings.ToLower(lang) {
+	case "ja":
+		return "jp"
+	case "ko":
+		return "kr"
+	case "zh":
+		if region == "Hant" {
+			return "tw"
+		}
+		return "cn"
+	case "he":
+		return "il"
+	case "el":
+		return "gr"
+	case "cs":
+		return "cz"
+	case "da":
+		return "dk"
+	case "sv":
+		return "se"
+	case "uk":
+		return "ua"
+	case "vi":
+		return "vn"
+	case "hi":
+		return "in"
+	case "ms":
+		return "my"
+	case "ca":
+		return "es"
+	case "nb", "no":
+		return "no"
+	case "fi", "fr", "de", "it", "nl", "pl", "pt", "ro", "ru", "sk", "tr", "hu", "hr", "id", "th", "es":
+		return strings.ToLower(lang)
+	}
+	return ""
+}
+
+// genres maps App Store Connect category IDs to App Store genre IDs.
+var genres = map[string]int{
+	"BUSINESS":                 6000,
+	"WEATHER":                  6001,
+	"UTILITIES":                6002,
+	"TRAVEL":                   6003,
+	"SPORTS":                   6004,
+	"SOCIAL_NETWORKING":        6005,
+	"REFERENCE":                6006,
+	"PRODUCTIVITY":             6007,
+	"PHOTO_AND_VIDEO":          6008,
+	"NEWS":                     6009,
+	"NAVIGATION":               6010,
+	"MUSIC":                    6011,
+	"LIFESTYLE":                6012,
+	"HEALTH_AND_FITNESS":       6013,
+	"GAMES":                    6014,
+	"FINANCE":                  6015,
+	"ENTERTAINMENT":            6016,
+	"EDUCATION":                6017,
+	"BOOKS":                    6018,
+	"MEDICAL":                  6020,
+	"MAGAZINES_AND_NEWSPAPERS": 6021,
+	"FOOD_AND_DRINK":           6023,
+	"SHOPPING":                 6024,
+	"STICKERS":                 6025,
+	"DEVELOPER_TOOLS":          6026,
+	"GRAPHICS_AND_DESIGN":      6027,
+}
+
+// GenreID returns the App Store genre ID for an App Store Connect category
+// such as "HEALTH_AND_FITNESS", or 0 if it is unknown. Game subcategories
+// ("GAMES_PUZZLE") map to Games.
+func GenreID(category string) int {
+	if strings.HasPrefix(category, "GAMES_") {
+		category = "GAMES"
+	}
+	return genres[category]
+}
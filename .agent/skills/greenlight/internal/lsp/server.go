@@ -0,0 +1,453 @@
+// Package lsp serves greenlight's codescan and privacy findings as Language
+// Server Protocol diagnostics, so editors show rejection risks as code is
+// written.
+//
+// The server scans the workspace when the client initializes and again on
+// every save. Open documents are re-checked on each change against the
+// per-file codescan rules, which need no other file; project-wide rules and
+// the privacy scan, which correlate files, refresh on save.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/codescan/swiftsyntax"
+	"github.com/RevylAI/greenlight/pkg/privacy"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Options configures a Server.
+type Options struct {
+	// Version is reported to the client as the server version.
+	Version string
+	// Swift enables syntax-aware Swift analysis, as codescan --swift-ast.
+	Swift swiftsyntax.Backend
+}
+
+// Server is a language server speaking over one stream pair, usually the
+// editor's pipes to greenlight's stdin and stdout.
+type Server struct {
+	opts Options
+	in   *bufio.Reader
+	out  io.Writer
+
+	outMu sync.Mutex
+	// scanMu serializes workspace scans.
+	scanMu sync.Mutex
+	// cancelScan stops the latest workspace scan; guarded by mu.
+	cancelScan context.CancelFunc
+
+	mu        sync.Mutex
+	root      string
+	scanner   *codescan.Scanner
+	overrides map[string]config.RuleOverride
+	paths     scan.Paths
+	// codescan and privacy are the last workspace scan's findings by file.
+	codescan map[string][]scan.Finding
+	privacy  map[string][]scan.Finding
+	// open holds the text of open documents by file.
+	open map[string]string
+	// published are the files with diagnostics on the client.
+	published map[string]bool
+	shutdown  bool
+}
+
+// NewServer returns a server reading requests from in and writing
+// responses and notifications to out.
+func NewServer(in io.Reader, out io.Writer, opts Options) *Server {
+	return &Server{
+		opts:      opts,
+		in:        bufio.NewReader(in),
+		out:       out,
+		open:      make(map[string]string),
+		published: make(map[string]bool),
+	}
+}
+
+// errExit stops Run when the client sends exit.
+var errExit = errors.New("exit")
+
+// Run serves requests until the client exits or in is closed. It returns
+// nil after an orderly shutdown and exit.
+func (s *Server) Run(ctx context.Context) error {
+	for {
+		m, err := readMessage(s.in)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.handle(ctx, m); err == errExit {
+			if !s.shutdown {
+				return errors.New("lsp: exit before shutdown")
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches one message. Requests (with an ID) get a response;
+// unknown notifications are ignored, as the protocol requires.
+func (s *Server) handle(ctx context.Context, m *message) error {
+	var (
+		result any // null unless set
+		rerr   *responseError
+	)
+	switch m.Method {
+	case "initialize":
+		var p initializeParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			rerr = &responseError{codeInvalidParams, err.Error()}
+			break
+		}
+		s.initialize(p)
+		result = map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    1, // full text on every change
+					"save":      map[string]bool{"includeText": false},
+				},
+			},
+			"serverInfo": map[string]string{"name": "greenlight", "version": s.opts.Version},
+		}
+	case "initialized":
+		s.rescan(ctx)
+	case "textDocument/didOpen", "textDocument/didChange":
+		var p textDocumentParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			return nil
+		}
+		text := p.TextDocument.Text
+		if n := len(p.ContentChanges); n > 0 {
+			text = p.ContentChanges[n-1].Text
+		}
+		if rel, ok := s.relPath(p.TextDocument.URI); ok {
+			s.mu.Lock()
+			s.open[rel] = text
+			s.mu.Unlock()
+			s.publish(rel)
+		}
+	case "textDocument/didSave":
+		s.rescan(ctx)
+	case "textDocument/didClose":
+		var p textDocumentParams
+		if err := json.Unmarshal(m.Params, &p); err != nil {
+			return nil
+		}
+		if rel, ok := s.relPath(p.TextDocument.URI); ok {
+			s.mu.Lock()
+			delete(s.open, rel)
+			s.mu.Unlock()
+			s.publish(rel)
+		}
+	case "shutdown":
+		s.shutdown = true
+		s.mu.Lock()
+		if s.cancelScan != nil {
+			s.cancelScan()
+		}
+		s.mu.Unlock()
+	case "exit":
+		return errExit
+	default:
+		if m.ID != nil {
+			rerr = &responseError{codeMethodNotFound, "method not supported: " + m.Method}
+		}
+	}
+	if m.ID == nil {
+		return nil
+	}
+	resp := &message{ID: m.ID, Error: rerr}
+	if rerr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = data
+	}
+	return s.send(resp)
+}
+
+// initialize sets the workspace root and loads its .greenlight.yml.
+func (s *Server) initialize(p initializeParams) {
+	root := p.RootPath
+	if len(p.WorkspaceFolders) > 0 {
+		p.RootURI = p.WorkspaceFolders[0].URI
+	}
+	if path, ok := uriPath(p.RootURI); ok {
+		root = path
+	}
+	if root == "" {
+		root = "."
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	scanner := codescan.NewScanner(root)
+	scanner.SetSwiftBackend(s.opts.Swift)
+
+	overrides, paths, warnings, err := loadConfig(root)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	for _, w := range warnings {
+		s.notify("window/showMessage", showMessageParams{messageWarning, "greenlight: " + w})
+	}
+	scanner.SetPaths(paths)
+	s.mu.Lock()
+	s.root, s.scanner, s.overrides, s.paths = root, scanner, overrides, paths
+	s.mu.Unlock()
+}
+
+// loadConfig reads the rules and paths sections of the project's
+// .greenlight.yml, with warnings about settings that won't take effect.
+func loadConfig(root string) (map[string]config.RuleOverride, scan.Paths, []string, error) {
+	cfg, err := config.LoadProject(root)
+	if err != nil {
+		return nil, scan.Paths{}, nil, err
+	}
+	overrides, err := cfg.RuleOverrides()
+	if err != nil {
+		return nil, scan.Paths{}, nil, err
+	}
+	known := make(map[string]bool)
+	for _, r := range codescan.Catalog() {
+		known[r.ID] = true
+	}
+	for _, c := range privacy.Catalog() {
+		known[c.ID] = true
+	}
+	warnings := cfg.Warnings
+	for _, id := range cfg.UnknownRules(known) {
+		warnings = append(warnings, fmt.Sprintf("%s: unknown rule '%s'; its setting is ignored", cfg.Path, id))
+	}
+	paths, err := scan.NewPaths(cfg.Paths.Include, cfg.Paths.Exclude)
+	paths.FirstParty = cfg.Paths.FirstParty
+	return overrides, paths, warnings, err
+}
+
+// rescan cancels the workspace scan in progress, if any, and starts a new
+// one, so saving several times in a row runs one full scan rather than
+// queueing one per save.
+func (s *Server) rescan(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	if s.cancelScan != nil {
+		s.cancelScan()
+	}
+	s.cancelScan = cancel
+	s.mu.Unlock()
+	go s.scanWorkspace(ctx)
+}
+
+// scanWorkspace runs codescan and the privacy scan over the workspace and
+// republishes every file's diagnostics. A scan whose ctx is cancelled,
+// because a newer one started, publishes nothing.
+func (s *Server) scanWorkspace(ctx context.Context) {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	s.mu.Lock()
+	root, scanner, overrides, paths := s.root, s.scanner, s.overrides, s.paths
+	s.mu.Unlock()
+	if scanner == nil {
+		return
+	}
+
+	found, err := scanner.Scan(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		s.notify("window/showMessage", showMessageParams{messageError, "greenlight: codescan failed: " + err.Error()})
+		return
+	}
+	byFile := make(map[string][]scan.Finding)
+	for _, f := range codescan.ApplyOverrides(found, overrides) {
+		byFile[f.File] = append(byFile[f.File], f.ScanFinding())
+	}
+	privacyByFile := make(map[string][]scan.Finding)
+	if result, err := privacy.ScanWithOptions(ctx, root, privacy.Options{Paths: paths}); err != nil {
+		slog.DebugContext(ctx, "lsp privacy scan failed", "err", err)
+	} else {
+		for _, f := range privacy.ApplyOverrides(result.Findings, overrides) {
+			if f.File != "" {
+				privacyByFile[f.File] = append(privacyByFile[f.File], f.ScanFinding())
+			}
+		}
+	}
+
+	s.mu.Lock()
+	if ctx.Err() != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.codescan, s.privacy = byFile, privacyByFile
+	files := make(map[string]bool)
+	for _, m := range []map[string][]scan.Finding{byFile, privacyByFile} {
+		for file := range m {
+			files[file] = true
+		}
+	}
+	for file := range s.open {
+		files[file] = true
+	}
+	for file := range s.published {
+		files[file] = true // to clear what was fixed
+	}
+	s.mu.Unlock()
+
+	sorted := make([]string, 0, len(files))
+	for file := range files {
+		sorted = append(sorted, file)
+	}
+	sort.Strings(sorted)
+	for _, file := range sorted {
+		s.publish(file)
+	}
+}
+
+// publish sends the diagnostics of file: an open document's are checked
+// from its text, a closed file's come from the last workspace scan.
+func (s *Server) publish(file string) {
+	s.mu.Lock()
+	root, scanner, overrides := s.root, s.scanner, s.overrides
+	text, isOpen := s.open[file]
+	findings := append([]scan.Finding(nil), s.privacy[file]...)
+	if !isOpen || scanner == nil {
+		findings = append(findings, s.codescan[file]...)
+	}
+	s.mu.Unlock()
+
+	if isOpen && scanner != nil {
+		for _, f := range codescan.ApplyOverrides(scanner.ScanFile(file, []byte(text)), overrides) {
+			findings = append(findings, f.ScanFinding())
+		}
+	} else if len(findings) > 0 {
+		data, _ := os.ReadFile(filepath.Join(root, file))
+		text = string(data)
+	}
+
+	lines := strings.Split(text, "\n")
+	diags := make([]diagnostic, 0, len(findings))
+	for _, f := range findings {
+		diags = append(diags, toDiagnostic(f, lines))
+	}
+
+	s.mu.Lock()
+	if len(diags) == 0 && !s.published[file] {
+		s.mu.Unlock()
+		return
+	}
+	s.published[file] = len(diags) > 0
+	s.mu.Unlock()
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(root, file))}).String()
+	s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diags})
+}
+
+// lspSeverity maps levels to diagnostic severities.
+var lspSeverity = map[severity.Level]int{
+	severity.Critical: severityError,
+	severity.Warn:     severityWarning,
+	severity.Info:     severityInformation,
+}
+
+// toDiagnostic is f on its line of lines, or on the first line for
+// findings about the whole file.
+func toDiagnostic(f scan.Finding, lines []string) diagnostic {
+	line := max(f.Line, 1) - 1
+	end := 0
+	if line < len(lines) {
+		end = len(utf16.Encode([]rune(strings.TrimSuffix(lines[line], "\r"))))
+	}
+	msg := f.Title
+	if f.Guideline != "" {
+		msg = "§" + f.Guideline + " " + msg
+	}
+	if f.Detail != "" {
+		msg += "\n" + f.Detail
+	}
+	if f.Fix != "" {
+		msg += "\nFix: " + f.Fix
+	}
+	d := diagnostic{
+		Range:    textRange{position{line, 0}, position{line, end}},
+		Severity: lspSeverity[f.Severity],
+		Code:     f.RuleID,
+		Source:   "greenlight",
+		Message:  msg,
+	}
+	if f.GuidelineURL != "" {
+		d.CodeDescription = &codeDescription{Href: f.GuidelineURL}
+	}
+	return d
+}
+
+// relPath is the workspace-relative path of a file: URI, if it is in the
+// workspace.
+func (s *Server) relPath(uri string) (string, bool) {
+	path, ok := uriPath(uri)
+	s.mu.Lock()
+	root := s.root
+	s.mu.Unlock()
+	if !ok || root == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// uriPath is the local path of a file: URI.
+func uriPath(uri string) (string, bool) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || u.Path == "" {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// notify sends a notification to the client.
+func (s *Server) notify(method string, params any) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	if err := s.send(&message{Method: method, Params: data}); err != nil {
+		slog.Debug("lsp notification not sent", "method", method, "err", err)
+	}
+}
+
+// send writes m to the client.
+func (s *Server) send(m *message) error {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	if err := writeMessage(s.out, m); err != nil {
+		return fmt.Errorf("lsp: %w", err)
+	}
+	return nil
+}
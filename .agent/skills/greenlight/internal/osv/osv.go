@@ -0,0 +1,215 @@
+// Package osv looks up package versions in the OSV vulnerability database
+// (osv.dev), which aggregates the GitHub, npm and NVD advisories.
+package osv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultURL is the OSV API; GREENLIGHT_OSV_URL overrides it, e.g. for a
+// mirror inside a network without internet access.
+const DefaultURL = "https://api.osv.dev"
+
+// URLEnv names the environment variable that overrides DefaultURL.
+const URLEnv = "GREENLIGHT_OSV_URL"
+
+// OSV ecosystems.
+const (
+	NPM = "npm"
+	// SwiftURL names Swift packages by repository: "github.com/owner/repo".
+	SwiftURL = "SwiftURL"
+)
+
+const (
+	// maxBatch is the most queries the API takes in one batch.
+	maxBatch = 1000
+	// maxResponse bounds one API response.
+	maxResponse = 8 << 20
+)
+
+// Package is a package version to look up.
+type Package struct {
+	Ecosystem string
+	Name      string
+	Version   string
+}
+
+// Vuln is one advisory.
+type Vuln struct {
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Aliases  []string `json:"aliases"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Events []struct {
+				Introduced string `json:"introduced,omitempty"`
+				Fixed      string `json:"fixed,omitempty"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		// Severity is GitHub's rating: LOW, MODERATE, HIGH or CRITICAL.
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// Fixed lists the versions of pkg that fix v.
+func (v *Vuln) Fixed(pkg Package) []string {
+	var fixed []string
+	for _, a := range v.Affected {
+		if a.Package.Ecosystem != pkg.Ecosystem || !strings.EqualFold(a.Package.Name, pkg.Name) {
+			continue
+		}
+		for _, r := range a.Ranges {
+			for _, e := range r.Events {
+				if e.Fixed != "" {
+					fixed = append(fixed, e.Fixed)
+				}
+			}
+		}
+	}
+	return fixed
+}
+
+// Client queries an OSV API.
+type Client struct {
+	HTTP *http.Client
+	URL  string
+}
+
+// NewClient returns a client of the API at GREENLIGHT_OSV_URL, or
+// DefaultURL.
+func NewClient(client *http.Client) *Client {
+	base := os.Getenv(URLEnv)
+	if base == "" {
+		base = DefaultURL
+	}
+	return &Client{HTTP: client, URL: strings.TrimSuffix(base, "/")}
+}
+
+// Query returns the advisories affecting each of pkgs, in order.
+func (c *Client) Query(ctx context.Context, pkgs []Package) ([][]*Vuln, error) {
+	type query struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Version string `json:"version"`
+	}
+	type result struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+	var results []result
+	for start := 0; start < len(pkgs); start += maxBatch {
+		var batch struct {
+			Queries []query `json:"queries"`
+		}
+		for _, p := range pkgs[start:min(start+maxBatch, len(pkgs))] {
+			var q query
+			q.Package.Ecosystem, q.Package.Name, q.Version = p.Ecosystem, p.Name, p.Version
+			batch.Queries = append(batch.Queries, q)
+		}
+		var resp struct {
+			Results []result `json:"results"`
+		}
+		if err := c.do(ctx, http.MethodPost, "/v1/querybatch", batch, &resp); err != nil {
+			return nil, err
+		}
+		if len(resp.Results) != len(batch.Queries) {
+			return nil, fmt.Errorf("osv: %d results for %d queries", len(resp.Results), len(batch.Queries))
+		}
+		results = append(results, resp.Results...)
+	}
+
+	// The batch only returns IDs; fetch each advisory once.
+	vulns := map[string]*Vuln{}
+	for _, r := range results {
+		for _, v := range r.Vulns {
+			vulns[v.ID] = nil
+		}
+	}
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		sem      = make(chan struct{}, 8)
+	)
+	for id := range vulns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var v Vuln
+			err := c.do(ctx, http.MethodGet, "/v1/vulns/"+url.PathEscape(id), nil, &v)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			vulns[id] = &v
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	out := make([][]*Vuln, len(pkgs))
+	for i, r := range results {
+		for _, v := range r.Vulns {
+			out[i] = append(out[i], vulns[v.ID])
+		}
+	}
+	return out, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "greenlight")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("osv: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("osv: %s %s: %s", method, path, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse+1))
+	if err != nil {
+		return fmt.Errorf("osv: %w", err)
+	}
+	if len(data) > maxResponse {
+		return fmt.Errorf("osv: %s %s: response larger than %d bytes", method, path, maxResponse)
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,89 @@
+// Package pathglob matches slash-separated paths against gitignore-style
+// patterns, as used by CODEOWNERS files and the --include and --exclude
+// scan filters.
+package pathglob
+
+import (
+	"path"
+	"strings"
+)
+
+// Anchored reports whether pattern is tied to the root: it starts with "/"
+// or has a "/" before its end. Other patterns match at any depth.
+func Anchored(pattern string) bool {
+	return strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+}
+
+// Match reports whether the pattern matches p or one of its parent
+// directories, so a directory pattern covers everything under it. "*" and
+// "?" match within a path segment and "**" spans any number of segments.
+func Match(pattern, p string) bool {
+	anchored := Anchored(pattern)
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" || pattern == "*" || pattern == "**" {
+		return true
+	}
+	segs := strings.Split(p, "/")
+	pat := strings.Split(pattern, "/")
+	if anchored {
+		return matchSegs(pat, segs)
+	}
+	// Unanchored patterns match at any depth.
+	for i := range segs {
+		if matchSegs(pat, segs[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reaches reports whether the pattern could match dir or something under
+// it, judging by its leading segments. Unanchored patterns reach every
+// directory.
+func Reaches(pattern, dir string) bool {
+	if !Anchored(pattern) {
+		return true
+	}
+	pat := strings.Split(strings.Trim(pattern, "/"), "/")
+	for i, seg := range strings.Split(dir, "/") {
+		if i >= len(pat) || pat[i] == "**" {
+			return true
+		}
+		if ok, _ := path.Match(pat[i], seg); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid reports whether pattern is well formed.
+func Valid(pattern string) bool {
+	for _, seg := range strings.Split(pattern, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSegs matches pattern segments against a prefix of path segments.
+func matchSegs(pat, segs []string) bool {
+	if len(pat) == 0 {
+		return true
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegs(pat[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pat[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegs(pat[1:], segs[1:])
+}
@@ -0,0 +1,559 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// scope binds comprehension variables on top of the policy's variables.
+type scope struct {
+	name   string
+	val    any
+	parent *scope
+	vars   map[string]any
+}
+
+func (s *scope) lookup(name string) (any, bool) {
+	for ; s != nil; s = s.parent {
+		if s.vars != nil {
+			v, ok := s.vars[name]
+			return v, ok
+		}
+		if s.name == name {
+			return s.val, true
+		}
+	}
+	return nil, false
+}
+
+func (s *scope) bind(name string, val any) *scope {
+	return &scope{name: name, val: val, parent: s}
+}
+
+type evaluator struct {
+	regexps map[string]*regexp.Regexp
+}
+
+func (e *evaluator) eval(n node, s *scope) (any, error) {
+	switch n := n.(type) {
+	case literal:
+		return n.val, nil
+	case ident:
+		v, ok := s.lookup(n.name)
+		if !ok {
+			return nil, fmt.Errorf("undeclared reference to '%s'", n.name)
+		}
+		return v, nil
+	case listExpr:
+		list := make([]any, 0, len(n.elems))
+		for _, el := range n.elems {
+			v, err := e.eval(el, s)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case member:
+		x, err := e.eval(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := x.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot select field '%s' from %s", n.name, typeName(x))
+		}
+		v, ok := m[n.name]
+		if !ok {
+			return nil, fmt.Errorf("no such key: %s", n.name)
+		}
+		return v, nil
+	case index:
+		x, err := e.eval(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		i, err := e.eval(n.i, s)
+		if err != nil {
+			return nil, err
+		}
+		return indexValue(x, i)
+	case unary:
+		x, err := e.eval(n.x, s)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "!":
+			b, ok := x.(bool)
+			if !ok {
+				return nil, fmt.Errorf("no such overload: !%s", typeName(x))
+			}
+			return !b, nil
+		default:
+			switch v := x.(type) {
+			case int64:
+				return -v, nil
+			case float64:
+				return -v, nil
+			}
+			return nil, fmt.Errorf("no such overload: -%s", typeName(x))
+		}
+	case binary:
+		return e.binary(n, s)
+	case cond:
+		c, err := e.eval(n.c, s)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := c.(bool)
+		if !ok {
+			return nil, fmt.Errorf("condition of ?: is %s, not bool", typeName(c))
+		}
+		if b {
+			return e.eval(n.t, s)
+		}
+		return e.eval(n.f, s)
+	case comprehension:
+		return e.comprehension(n, s)
+	case call:
+		return e.call(n, s)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", n)
+}
+
+func (e *evaluator) binary(n binary, s *scope) (any, error) {
+	// || and && short-circuit, and like CEL an error on one side is ignored
+	// when the other side decides the result.
+	if n.op == "||" || n.op == "&&" {
+		decisive := n.op == "||"
+		l, lerr := e.evalBool(n.l, s)
+		if lerr == nil && l == decisive {
+			return decisive, nil
+		}
+		r, rerr := e.evalBool(n.r, s)
+		if rerr == nil && r == decisive {
+			return decisive, nil
+		}
+		if lerr != nil {
+			return nil, lerr
+		}
+		if rerr != nil {
+			return nil, rerr
+		}
+		return !decisive, nil
+	}
+
+	l, err := e.eval(n.l, s)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.eval(n.r, s)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, fmt.Errorf("no such overload: %s %s %s", typeName(l), n.op, typeName(r))
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	case "in":
+		switch r := r.(type) {
+		case []any:
+			for _, el := range r {
+				if equal(l, el) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]any:
+			k, ok := l.(string)
+			if !ok {
+				return false, nil
+			}
+			_, found := r[k]
+			return found, nil
+		}
+		return nil, fmt.Errorf("no such overload: %s in %s", typeName(l), typeName(r))
+	case "+":
+		switch lv := l.(type) {
+		case string:
+			if rv, ok := r.(string); ok {
+				return lv + rv, nil
+			}
+		case []any:
+			if rv, ok := r.([]any); ok {
+				return append(append([]any{}, lv...), rv...), nil
+			}
+		}
+	}
+	return arithmetic(n.op, l, r)
+}
+
+func (e *evaluator) evalBool(n node, s *scope) (bool, error) {
+	v, err := e.eval(n, s)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected bool, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+func (e *evaluator) comprehension(n comprehension, s *scope) (any, error) {
+	x, err := e.eval(n.recv, s)
+	if err != nil {
+		return nil, err
+	}
+	var elems []any
+	switch x := x.(type) {
+	case []any:
+		elems = x
+	case map[string]any:
+		for _, k := range sortedKeys(x) {
+			elems = append(elems, k)
+		}
+	default:
+		return nil, fmt.Errorf("cannot apply %s to %s", n.name, typeName(x))
+	}
+
+	var (
+		matched int
+		out     []any
+	)
+	for _, el := range elems {
+		inner := s.bind(n.v, el)
+		if n.name == "map" {
+			v, err := e.eval(n.body, inner)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+			continue
+		}
+		ok, err := e.evalBool(n.body, inner)
+		if err != nil {
+			return nil, err
+		}
+		switch n.name {
+		case "exists":
+			if ok {
+				return true, nil
+			}
+		case "all":
+			if !ok {
+				return false, nil
+			}
+		case "filter":
+			if ok {
+				out = append(out, el)
+			}
+		default: // exists_one
+			if ok {
+				matched++
+			}
+		}
+	}
+	switch n.name {
+	case "exists":
+		return false, nil
+	case "all":
+		return true, nil
+	case "exists_one":
+		return matched == 1, nil
+	}
+	if out == nil {
+		out = []any{}
+	}
+	return out, nil
+}
+
+func (e *evaluator) call(n call, s *scope) (any, error) {
+	// has(x.f) tests for a field without failing when it is missing.
+	if n.recv == nil && n.name == "has" {
+		var m member
+		ok := len(n.args) == 1
+		if ok {
+			m, ok = n.args[0].(member)
+		}
+		if !ok {
+			return nil, fmt.Errorf("has() takes a field selection, e.g. has(f.file)")
+		}
+		x, err := e.eval(m.x, s)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := x.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot apply has() to %s", typeName(x))
+		}
+		_, found := obj[m.name]
+		return found, nil
+	}
+
+	var args []any
+	if n.recv != nil {
+		recv, err := e.eval(n.recv, s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, recv)
+	}
+	for _, a := range n.args {
+		v, err := e.eval(a, s)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	signature := func() error {
+		types := make([]string, len(args))
+		for i, a := range args {
+			types[i] = typeName(a)
+		}
+		return fmt.Errorf("no such overload: %s(%s)", n.name, strings.Join(types, ", "))
+	}
+
+	switch n.name {
+	case "size":
+		if len(args) != 1 {
+			return nil, signature()
+		}
+		switch v := args[0].(type) {
+		case string:
+			return int64(utf8.RuneCountInString(v)), nil
+		case []any:
+			return int64(len(v)), nil
+		case map[string]any:
+			return int64(len(v)), nil
+		}
+	case "startsWith", "endsWith", "contains", "matches":
+		if len(args) != 2 {
+			return nil, signature()
+		}
+		str, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, signature()
+		}
+		switch n.name {
+		case "startsWith":
+			return strings.HasPrefix(str, sub), nil
+		case "endsWith":
+			return strings.HasSuffix(str, sub), nil
+		case "contains":
+			return strings.Contains(str, sub), nil
+		}
+		re, ok := e.regexps[sub]
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(sub); err != nil {
+				return nil, fmt.Errorf("matches: %w", err)
+			}
+			e.regexps[sub] = re
+		}
+		return re.MatchString(str), nil
+	case "lowerAscii", "upperAscii":
+		if len(args) != 1 {
+			return nil, signature()
+		}
+		if str, ok := args[0].(string); ok {
+			if n.name == "lowerAscii" {
+				return strings.ToLower(str), nil
+			}
+			return strings.ToUpper(str), nil
+		}
+	case "string":
+		if len(args) == 1 {
+			if str, ok := args[0].(string); ok {
+				return str, nil
+			}
+			return fmt.Sprint(args[0]), nil
+		}
+	case "int":
+		if len(args) == 1 {
+			switch v := args[0].(type) {
+			case int64:
+				return v, nil
+			case float64:
+				return int64(v), nil
+			case string:
+				i, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("int(%q): not an integer", v)
+				}
+				return i, nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown function '%s'", n.name)
+	}
+	return nil, signature()
+}
+
+func indexValue(x, i any) (any, error) {
+	switch x := x.(type) {
+	case []any:
+		n, ok := i.(int64)
+		if !ok {
+			return nil, fmt.Errorf("list index must be int, got %s", typeName(i))
+		}
+		if n < 0 || n >= int64(len(x)) {
+			return nil, fmt.Errorf("index %d out of range (size %d)", n, len(x))
+		}
+		return x[n], nil
+	case map[string]any:
+		k, ok := i.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be string, got %s", typeName(i))
+		}
+		v, found := x[k]
+		if !found {
+			return nil, fmt.Errorf("no such key: %s", k)
+		}
+		return v, nil
+	}
+	return nil, fmt.Errorf("cannot index %s", typeName(x))
+}
+
+func equal(a, b any) bool {
+	if af, bf, ok := numbers(a, b); ok {
+		return af == bf
+	}
+	switch a := a.(type) {
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, found := b[k]; !found || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func compare(a, b any) (int, error) {
+	if af, bf, ok := numbers(a, b); ok {
+		switch {
+		case af < bf:
+			return -1, nil
+		case af > bf:
+			return 1, nil
+		}
+		return 0, nil
+	}
+	as, ok1 := a.(string)
+	bs, ok2 := b.(string)
+	if ok1 && ok2 {
+		return strings.Compare(as, bs), nil
+	}
+	return 0, fmt.Errorf("not comparable")
+}
+
+func arithmetic(op string, l, r any) (any, error) {
+	li, lint := l.(int64)
+	ri, rint := r.(int64)
+	if lint && rint {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	if lf, rf, ok := numbers(l, r); ok && op != "%" {
+		switch op {
+		case "+":
+			return lf + rf, nil
+		case "-":
+			return lf - rf, nil
+		case "*":
+			return lf * rf, nil
+		case "/":
+			return lf / rf, nil
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s %s %s", typeName(l), op, typeName(r))
+}
+
+// numbers converts a and b to float64 if both are numeric.
+func numbers(a, b any) (float64, float64, bool) {
+	af, ok1 := number(a)
+	bf, ok2 := number(b)
+	return af, bf, ok1 && ok2
+}
+
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,407 @@
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression tree.
+type (
+	node any
+
+	literal struct{ val any }
+	ident   struct{ name string }
+	member  struct {
+		x    node
+		name string
+	}
+	index struct{ x, i node }
+	call  struct {
+		recv node // nil for global functions
+		name string
+		args []node
+	}
+	// comprehension is a macro that binds v to each element of recv:
+	// exists, all, exists_one, filter and map.
+	comprehension struct {
+		recv node
+		name string
+		v    string
+		body node
+	}
+	unary struct {
+		op string
+		x  node
+	}
+	binary struct {
+		op   string
+		l, r node
+	}
+	cond     struct{ c, t, f node }
+	listExpr struct{ elems []node }
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokInt
+	tokFloat
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	val  any
+	pos  int
+}
+
+// lex splits src into tokens, dropping whitespace and // comments.
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.') {
+				i++
+			}
+			text := src[start:i]
+			if strings.Contains(text, ".") {
+				f, err := strconv.ParseFloat(text, 64)
+				if err != nil {
+					return nil, posError(src, start, "bad number %q", text)
+				}
+				toks = append(toks, token{tokFloat, text, f, start})
+			} else {
+				n, err := strconv.ParseInt(text, 10, 64)
+				if err != nil {
+					return nil, posError(src, start, "bad number %q", text)
+				}
+				toks = append(toks, token{tokInt, text, n, start})
+			}
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			var b strings.Builder
+			for {
+				if i >= len(src) || src[i] == '\n' {
+					return nil, posError(src, start, "unterminated string")
+				}
+				if src[i] == c {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(src[i])
+					}
+					i++
+					continue
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{tokString, src[start:i], b.String(), start})
+		case c == '_' || unicode.IsLetter(rune(c)):
+			start := i
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, src[start:i], nil, start})
+		default:
+			op := ""
+			for _, o := range []string{"||", "&&", "==", "!=", "<=", ">="} {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				if !strings.ContainsRune("!<>+-*/%?:.,()[]", rune(c)) {
+					return nil, posError(src, i, "unexpected character %q", c)
+				}
+				op = string(c)
+			}
+			toks = append(toks, token{tokOp, op, nil, i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// posError reports a syntax error at byte offset pos as line:column.
+func posError(src string, pos int, format string, args ...any) error {
+	line := 1 + strings.Count(src[:pos], "\n")
+	col := pos - strings.LastIndex(src[:pos], "\n")
+	return fmt.Errorf("%d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+type parser struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func parse(src string) (node, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	if p.peek().kind == tokEOF {
+		return nil, fmt.Errorf("policy is empty")
+	}
+	n, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, posError(src, t.pos, "unexpected %q", t.text)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of ops.
+func (p *parser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp && !(t.kind == tokIdent && t.text == "in") {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return posError(p.src, t.pos, "expected %q, got end of policy", op)
+		}
+		return posError(p.src, t.pos, "expected %q, got %q", op, t.text)
+	}
+	return nil
+}
+
+// expr parses a conditional: or ? expr : expr.
+func (p *parser) expr() (node, error) {
+	c, err := p.binaryLevel(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return c, nil
+	}
+	t, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	f, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return cond{c, t, f}, nil
+}
+
+// precedence lists binary operators from loosest to tightest binding.
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">=", "in"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binaryLevel(level int) (node, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+	l, err := p.binaryLevel(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(precedence[level]...)
+		if !ok {
+			return l, nil
+		}
+		r, err := p.binaryLevel(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op, l, r}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if op, ok := p.accept("!", "-"); ok {
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op, x}, nil
+	}
+	return p.postfix()
+}
+
+// macros are the methods whose first argument names a variable.
+var macros = map[string]bool{"exists": true, "all": true, "exists_one": true, "filter": true, "map": true}
+
+func (p *parser) postfix() (node, error) {
+	x, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.peek().kind == tokOp && p.peek().text == ".":
+			p.next()
+			name := p.next()
+			if name.kind != tokIdent {
+				return nil, posError(p.src, name.pos, "expected a field or method name after '.'")
+			}
+			if _, ok := p.accept("("); !ok {
+				x = member{x, name.text}
+				continue
+			}
+			args, err := p.args()
+			if err != nil {
+				return nil, err
+			}
+			if macros[name.text] {
+				v, ok := args[0].(ident)
+				if len(args) != 2 || !ok {
+					return nil, posError(p.src, name.pos, "%s takes a variable name and an expression, e.g. %s(f, f.severity == \"CRITICAL\")", name.text, name.text)
+				}
+				x = comprehension{x, name.text, v.name, args[1]}
+				continue
+			}
+			x = call{x, name.text, args}
+		case p.peek().kind == tokOp && p.peek().text == "[":
+			p.next()
+			i, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			x = index{x, i}
+		default:
+			return x, nil
+		}
+	}
+}
+
+// args parses a call's arguments after the opening parenthesis.
+func (p *parser) args() ([]node, error) {
+	var args []node
+	if _, ok := p.accept(")"); ok {
+		return args, nil
+	}
+	for {
+		a, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+		if _, ok := p.accept(")"); ok {
+			return args, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokInt, tokFloat, tokString:
+		return literal{t.val}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			args, err := p.args()
+			if err != nil {
+				return nil, err
+			}
+			return call{nil, t.text, args}, nil
+		}
+		return ident{t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			var elems []node
+			if _, ok := p.accept("]"); ok {
+				return listExpr{elems}, nil
+			}
+			for {
+				e, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				elems = append(elems, e)
+				if _, ok := p.accept("]"); ok {
+					return listExpr{elems}, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case tokEOF:
+		return nil, posError(p.src, t.pos, "unexpected end of policy")
+	}
+	return nil, posError(p.src, t.pos, "unexpected %q", t.text)
+}
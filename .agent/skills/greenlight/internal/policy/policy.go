@@ -0,0 +1,177 @@
+// Package policy evaluates gate policies: expressions in a subset of CEL
+// (the Common Expression Language) over the findings of a saved report.
+//
+// A policy is true when the report should fail the gate:
+//
+//	// fail on any CRITICAL outside tests, or more than 5 privacy WARNs
+//	findings.exists(f, f.severity == "CRITICAL" && !f.file.startsWith("Tests/")) ||
+//	findings.filter(f, f.severity == "WARN" && f.guideline.startsWith("5.1")).size() > 5
+//
+// Supported: int, double, string, bool, null and list literals; the ! - * / %
+// + - comparison, in, && || and ?: operators; field access and indexing; the
+// exists, all, exists_one, filter and map macros; has(), size(), int(),
+// string(), and the string methods startsWith, endsWith, contains, matches,
+// lowerAscii and upperAscii.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Policy is a compiled gate policy.
+type Policy struct {
+	src  string
+	root node
+}
+
+// Compile parses a policy expression.
+func Compile(src string) (*Policy, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	return &Policy{src: src, root: root}, nil
+}
+
+// Load reads and compiles the policy in path.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Compile(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// String returns the policy source.
+func (p *Policy) String() string { return p.src }
+
+// Eval evaluates the policy with vars as its top-level variables. Values are
+// those encoding/json decodes into, with whole numbers as int64 (see Vars).
+func (p *Policy) Eval(vars map[string]any) (any, error) {
+	e := &evaluator{regexps: map[string]*regexp.Regexp{}}
+	return e.eval(p.root, &scope{vars: vars})
+}
+
+// Fails evaluates the policy and reports whether the gate fails. The policy
+// must evaluate to a bool.
+func (p *Policy) Fails(vars map[string]any) (bool, error) {
+	v, err := p.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("policy must evaluate to a bool, got %s", typeName(v))
+	}
+	return b, nil
+}
+
+// findingFields are present on every finding, so policies can test them
+// without has() whichever command wrote the report.
+var findingFields = map[string]any{
+	"source":      "",
+	"rule_id":     "",
+	"severity":    "",
+	"guideline":   "",
+	"title":       "",
+	"detail":      "",
+	"fix":         "",
+	"file":        "",
+	"line":        int64(0),
+	"fingerprint": "",
+}
+
+// Vars decodes a report saved with --format json into policy variables:
+// findings (the report's findings, with severities as names), summary
+// (total, critical, warns and infos counted from findings) and report (the
+// whole document).
+func Vars(data []byte) (map[string]any, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc = normalize(doc)
+	report, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("not a greenlight JSON report")
+	}
+	list, ok := report["findings"].([]any)
+	if !ok {
+		if report["findings"] != nil {
+			return nil, fmt.Errorf("not a greenlight JSON report (findings is not a list)")
+		}
+		if _, found := report["findings"]; !found {
+			return nil, fmt.Errorf("not a greenlight JSON report (no findings list)")
+		}
+		list = []any{}
+	}
+
+	counts := map[severity.Level]int64{}
+	for _, item := range list {
+		f, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("not a greenlight JSON report (finding is not an object)")
+		}
+		for k, v := range findingFields {
+			if _, found := f[k]; !found {
+				f[k] = v
+			}
+		}
+		var level severity.Level
+		raw, _ := json.Marshal(f["severity"])
+		if err := json.Unmarshal(raw, &level); err == nil {
+			f["severity"] = level.String()
+			counts[level]++
+		}
+	}
+
+	return map[string]any{
+		"findings": list,
+		"summary": map[string]any{
+			"total":    int64(len(list)),
+			"critical": counts[severity.Critical],
+			"warns":    counts[severity.Warn],
+			"infos":    counts[severity.Info],
+		},
+		"report": report,
+	}, nil
+}
+
+// normalize turns whole float64 numbers from encoding/json into int64, so
+// line numbers and counts compare as ints.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
+	case []any:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = normalize(v[k])
+		}
+	}
+	return v
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,206 @@
+package policy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const report = `{
+  "findings": [
+    {"severity": 2, "source": "codescan", "rule_id": "hardcoded-secret", "guideline": "2.5.1", "file": "App/Keys.swift", "line": 12},
+    {"severity": 2, "source": "codescan", "rule_id": "hardcoded-secret", "guideline": "2.5.1", "file": "Tests/KeysTests.swift", "line": 3},
+    {"severity": 1, "source": "privacy", "guideline": "5.1.1", "title": "Missing purpose string"},
+    {"severity": 1, "source": "privacy", "guideline": "5.1.2", "title": "Tracking without ATT"},
+    {"severity": 0, "source": "metadata", "title": "No support URL", "extra": {"tags": ["a", "b"]}}
+  ],
+  "summary": {"critical": 99}
+}`
+
+func reportVars(t *testing.T) map[string]any {
+	t.Helper()
+	vars, err := Vars([]byte(report))
+	if err != nil {
+		t.Fatalf("Vars: %v", err)
+	}
+	return vars
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string // substring of the error
+	}{
+		{"", "empty"},
+		{"1 +", "unexpected end"},
+		{"(1 + 2", ")"},
+		{"[1, 2", "end of policy"},
+		{`"unterminated`, "unterminated"},
+		{"1 2", "unexpected"},
+		{"a ? b", ":"},
+		{"findings.exists(1, true)", "variable name"},
+		{"1 # 2", "unexpected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Compile(tt.src)
+			if err == nil {
+				t.Fatalf("Compile(%q) succeeded, want error", tt.src)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Compile(%q) error %q, want it to mention %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		// Literals and operators.
+		{"1 + 2 * 3", int64(7)},
+		{"(1 + 2) * 3", int64(9)},
+		{"7 / 2", int64(3)},
+		{"7 % 4", int64(3)},
+		{"7.0 / 2", 3.5},
+		{"-3 + 1", int64(-2)},
+		{`"a" + "b"`, "ab"},
+		{"1 < 2 && 2 <= 2 && 3 > 2 && 3 >= 3", true},
+		{"1 == 1.0", true},
+		{`"a" != "b"`, true},
+		{"!true || false", false},
+		{"true ? 1 : 2", int64(1)},
+		{"false ? 1 : 2", int64(2)},
+		{"null == null", true},
+		{"2 in [1, 2, 3]", true},
+		{`"x" in ["a"]`, false},
+		{"[1, 2][1]", int64(2)},
+		// Short-circuiting skips the erroring side.
+		{"false && 1 / 0 == 0", false},
+		{"true || 1 / 0 == 0", true},
+		// Variables and functions.
+		{"summary.total", int64(5)},
+		{"summary.critical", int64(2)},
+		{"summary.warns", int64(2)},
+		{"summary.infos", int64(1)},
+		{"report.summary.critical", int64(99)},
+		{"findings[0].file", "App/Keys.swift"},
+		{"findings[0].severity", "CRITICAL"},
+		{"findings[4].line", int64(0)},
+		{"size(findings)", int64(5)},
+		{"findings.size()", int64(5)},
+		{`size("héllo")`, int64(5)},
+		{`int("42") + 1`, int64(43)},
+		{"string(12)", "12"},
+		{"has(findings[4].extra)", true},
+		{"has(findings[0].extra)", false},
+		{"findings[4].extra.tags[1]", "b"},
+		// Strings.
+		{`"Tests/A.swift".startsWith("Tests/")`, true},
+		{`"a.swift".endsWith(".swift")`, true},
+		{`"hello".contains("ell")`, true},
+		{`"5.1.2".matches("^5\\.1\\.")`, true},
+		{`"AbC".lowerAscii()`, "abc"},
+		{`"AbC".upperAscii()`, "ABC"},
+		// Macros.
+		{`findings.exists(f, f.severity == "CRITICAL" && !f.file.startsWith("Tests/"))`, true},
+		{`findings.all(f, f.severity != "INFO")`, false},
+		{`findings.exists_one(f, f.source == "metadata")`, true},
+		{`findings.exists_one(f, f.source == "privacy")`, false},
+		{`findings.filter(f, f.severity == "WARN" && f.guideline.startsWith("5.1")).size()`, int64(2)},
+		{`findings.filter(f, f.source == "codescan").map(f, f.line)`, []any{int64(12), int64(3)}},
+		{`[1, 2, 3].map(x, x * 2)`, []any{int64(2), int64(4), int64(6)}},
+		{`[].exists(x, true)`, false},
+		{`[].all(x, false)`, true},
+	}
+	vars := reportVars(t)
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			got, err := p.Eval(vars)
+			if err != nil {
+				t.Fatalf("Eval: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval(%q) = %#v, want %#v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []string{
+		"nope",
+		"1 / 0",
+		"1 % 0",
+		`1 + "a"`,
+		`"a" < 1`,
+		"findings[10]",
+		"summary.missing",
+		"!1",
+		"1 && true",
+		"size(1)",
+		`"a".nope()`,
+		`"a".matches("(")`,
+		`int("x")`,
+		"findings.exists(f, f.line)",
+	}
+	vars := reportVars(t)
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			p, err := Compile(src)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			if v, err := p.Eval(vars); err == nil {
+				t.Errorf("Eval(%q) = %#v, want error", src, v)
+			}
+		})
+	}
+}
+
+func TestFails(t *testing.T) {
+	tests := []struct {
+		src     string
+		fails   bool
+		wantErr bool
+	}{
+		{"summary.critical > 0", true, false},
+		{"summary.critical > 5", false, false},
+		{`findings.exists(f, f.severity == "CRITICAL" && !f.file.startsWith("Tests/"))`, true, false},
+		{`findings.filter(f, f.severity == "WARN").size() > 5`, false, false},
+		{"summary.total", false, true}, // not a bool
+	}
+	vars := reportVars(t)
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			p, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			fails, err := p.Fails(vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Fails(%q) error = %v, want error %v", tt.src, err, tt.wantErr)
+			}
+			if fails != tt.fails {
+				t.Errorf("Fails(%q) = %v, want %v", tt.src, fails, tt.fails)
+			}
+		})
+	}
+}
+
+func TestVarsRejectsOtherJSON(t *testing.T) {
+	for _, data := range []string{`[]`, `{}`, `{"findings": 1}`, `{"findings": [1]}`, `not json`} {
+		if _, err := Vars([]byte(data)); err == nil {
+			t.Errorf("Vars(%s) succeeded, want error", data)
+		}
+	}
+	if vars, err := Vars([]byte(`{"findings": null}`)); err != nil || vars["summary"].(map[string]any)["total"] != int64(0) {
+		t.Errorf(`Vars({"findings": null}) = %v, %v; want an empty report`, vars, err)
+	}
+}
@@ -0,0 +1,162 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Checkstyle XML, as read by Jenkins Warnings NG, SonarQube and most CI
+// servers' code-inspection views.
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// checkstyleSeverity maps levels to checkstyle's error, warning and info.
+var checkstyleSeverity = map[severity.Level]string{
+	severity.Critical: "error",
+	severity.Warn:     "warning",
+	severity.Info:     "info",
+}
+
+// WriteCheckstyle writes one <file> per file with findings. Findings that
+// aren't about a file (metadata, the binary) are listed under the report's
+// header, e.g. the project path or app ID.
+func (r *Findings) WriteCheckstyle(w io.Writer) error {
+	byFile := map[string][]checkstyleError{}
+	for _, f := range r.Findings {
+		byFile[r.fileOf(f)] = append(byFile[r.fileOf(f)], checkstyleError{
+			Line:     f.Line,
+			Severity: checkstyleSeverity[f.Severity],
+			Message:  findingMessage(f),
+			Source:   "greenlight." + ruleOf(f),
+		})
+	}
+	names := make([]string, 0, len(byFile))
+	for name := range byFile {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := checkstyleReport{Version: "4.3"}
+	for _, name := range names {
+		out.Files = append(out.Files, checkstyleFile{Name: name, Errors: byFile[name]})
+	}
+	fmt.Fprint(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// TeamCity service messages: each rule becomes an inspection type and each
+// finding an inspection, shown on the build's Inspections tab.
+
+// teamcitySeverity maps levels to TeamCity's inspection severities.
+var teamcitySeverity = map[severity.Level]string{
+	severity.Critical: "ERROR",
+	severity.Warn:     "WARNING",
+	severity.Info:     "INFO",
+}
+
+// WriteTeamCity writes the report as TeamCity service messages.
+func (r *Findings) WriteTeamCity(w io.Writer) error {
+	types := map[string]bool{}
+	for _, f := range r.Findings {
+		rule := ruleOf(f)
+		if !types[rule] {
+			types[rule] = true
+			category := "App Store Review Guidelines"
+			if f.Guideline != "" {
+				category = GuidelineRef(f.Guideline, f.GuidelineTitle)
+			}
+			fmt.Fprintf(w, "##teamcity[inspectionType id='%s' name='%s' category='%s' description='%s']\n",
+				teamcityEscape(rule), teamcityEscape(rule), teamcityEscape(category), teamcityEscape(f.Title))
+		}
+		fmt.Fprintf(w, "##teamcity[inspection typeId='%s' message='%s' file='%s'", teamcityEscape(rule), teamcityEscape(findingMessage(f)), teamcityEscape(r.fileOf(f)))
+		if f.Line > 0 {
+			fmt.Fprintf(w, " line='%d'", f.Line)
+		}
+		fmt.Fprintf(w, " SEVERITY='%s']\n", teamcitySeverity[f.Severity])
+	}
+
+	s := r.Summary()
+	fmt.Fprintf(w, "##teamcity[buildStatisticValue key='greenlight.critical' value='%d']\n", s.Critical)
+	fmt.Fprintf(w, "##teamcity[buildStatisticValue key='greenlight.warn' value='%d']\n", s.Warns)
+	fmt.Fprintf(w, "##teamcity[buildStatisticValue key='greenlight.info' value='%d']\n", s.Infos)
+	fmt.Fprintf(w, "##teamcity[buildStatisticValue key='greenlight.risk' value='%d']\n", s.RiskScore)
+	if !s.Passed {
+		fmt.Fprintf(w, "##teamcity[buildProblem description='%s' identity='greenlight.%s']\n", teamcityEscape("greenlight "+r.Command+": "+r.verdict(s)), teamcityEscape(r.Command))
+	}
+	return nil
+}
+
+// teamcityEscape escapes a service message attribute value.
+var teamcityEscape = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"\n", "|n",
+	"\r", "|r",
+	"[", "|[",
+	"]", "|]",
+).Replace
+
+// fileOf is the file a finding is reported against.
+func (r *Findings) fileOf(f scan.Finding) string {
+	if f.File != "" {
+		return f.File
+	}
+	if len(r.Header) > 0 {
+		return r.Header[0].Value
+	}
+	return r.Command
+}
+
+// ruleOf names the kind of finding, for tools that group findings by rule.
+func ruleOf(f scan.Finding) string {
+	if f.RuleID != "" {
+		return f.RuleID
+	}
+	if f.Source != "" {
+		return f.Source
+	}
+	return "finding"
+}
+
+// findingMessage is a finding as one message: title, detail and fix.
+func findingMessage(f scan.Finding) string {
+	msg := f.Title
+	if f.Guideline != "" {
+		msg = "§" + f.Guideline + " " + msg
+	}
+	if f.Detail != "" {
+		msg += ": " + f.Detail
+	}
+	if f.Fix != "" {
+		msg += " Fix: " + f.Fix
+	}
+	return msg
+}
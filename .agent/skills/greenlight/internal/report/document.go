@@ -0,0 +1,197 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+// GuidelineRef formats "§5.1.1 Data Collection and Storage".
+func GuidelineRef(section, title string) string {
+	if title == "" {
+		return "§" + section
+	}
+	return "§" + section + " " + title
+}
+
+// documentContext is the report's context led by its header.
+func (r *Findings) documentContext() []Field {
+	return append(append([]Field(nil), r.Header...), r.Context...)
+}
+
+// docSection is a headed group of findings in a Markdown or HTML report.
+type docSection struct {
+	Heading  string
+	Findings []scan.Finding
+}
+
+// documentSections groups the findings by severity, or by CODEOWNERS owner
+// when GroupBy is "owner": one section per set of owners, in name order,
+// with unowned findings last.
+func (r *Findings) documentSections() []docSection {
+	var secs []docSection
+	if r.GroupBy != "owner" {
+		for _, sec := range sections {
+			secs = append(secs, docSection{i18n.T(sec.Heading), r.bySeverity(sec.Severity)})
+		}
+		return secs
+	}
+	byOwner := map[string][]scan.Finding{}
+	var unowned []scan.Finding
+	for _, f := range r.Findings {
+		if len(f.Owners) == 0 {
+			unowned = append(unowned, f)
+			continue
+		}
+		key := strings.Join(f.Owners, " ")
+		byOwner[key] = append(byOwner[key], f)
+	}
+	owners := make([]string, 0, len(byOwner))
+	for o := range byOwner {
+		owners = append(owners, o)
+	}
+	sort.Strings(owners)
+	for _, o := range owners {
+		secs = append(secs, docSection{o, byOwner[o]})
+	}
+	return append(secs, docSection{i18n.T("Unowned"), unowned})
+}
+
+// WriteMarkdown writes the report as a Markdown document, e.g. for a pull
+// request comment.
+func (r *Findings) WriteMarkdown(w io.Writer) error {
+	r = r.localized()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# greenlight %s\n\n", r.Command)
+	for _, c := range r.documentContext() {
+		fmt.Fprintf(&b, "- **%s:** %s\n", c.Label, c.Value)
+	}
+	s := r.Summary()
+	status := "✅ **GREENLIT** — " + i18n.T(r.verdict(s))
+	if !s.Passed {
+		status = "❌ **NOT READY** — " + i18n.T(r.verdict(s))
+	}
+	fmt.Fprintf(&b, "\n%s\n\n%s  \n%s\n", status, counts(s), riskLine(s))
+
+	for _, sec := range r.documentSections() {
+		if len(sec.Findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", sec.Heading)
+		for _, f := range sec.Findings {
+			fmt.Fprintf(&b, "\n### %s\n\n", f.Title)
+			var meta []string
+			if r.GroupBy == "owner" {
+				meta = append(meta, "**"+f.Severity.String()+"**")
+			}
+			if f.Source != "" {
+				meta = append(meta, "`"+f.Source+"`")
+			}
+			if f.Guideline != "" {
+				ref := GuidelineRef(f.Guideline, f.GuidelineTitle)
+				if f.GuidelineURL != "" {
+					ref = fmt.Sprintf("[%s](%s)", ref, f.GuidelineURL)
+				}
+				meta = append(meta, ref)
+			}
+			if loc := location(f); loc != "" {
+				meta = append(meta, "`"+loc+"`")
+			}
+			if f.Fingerprint != "" {
+				meta = append(meta, "id `"+findingid.Display(f.RuleID, f.Fingerprint)+"`")
+			}
+			meta = append(meta, i18n.Sprintf("risk %d", riskOf(f)))
+			if len(f.Owners) > 0 && r.GroupBy != "owner" {
+				meta = append(meta, strings.Join(f.Owners, " "))
+			}
+			if len(meta) > 0 {
+				b.WriteString(strings.Join(meta, " · ") + "\n\n")
+			}
+			if f.Code != "" {
+				fmt.Fprintf(&b, "```\n%s\n```\n\n", strings.TrimSpace(f.Code))
+			}
+			b.WriteString(f.Detail + "\n")
+			if f.Fix != "" {
+				fmt.Fprintf(&b, "\n**%s** %s\n", i18n.T("Fix:"), f.Fix)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\n---\n_%s_\n", i18n.Sprintf("Generated by greenlight in %s.", r.Elapsed.Round(time.Millisecond)))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"ref":   GuidelineRef,
+	"id":    findingid.Display,
+	"lower": strings.ToLower,
+	"t":     i18n.T,
+	"tf":    i18n.Sprintf,
+	"risk":  riskOf,
+	"join":  strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>greenlight {{.Command}}{{with .Header}} — {{(index . 0).Value}}{{end}}</title>
+<style>
+body { font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; max-width: 920px; margin: 2rem auto; padding: 0 1rem; color: #1d1d1f; }
+h1 { color: #a020c0; }
+.status { font-size: 1.2rem; font-weight: 600; }
+.pass { color: #1a7f37; } .fail { color: #cf222e; }
+.finding { border-left: 4px solid #d0d7de; padding: .25rem 1rem; margin: 1rem 0; }
+.critical { border-color: #cf222e; } .warn { border-color: #bf8700; } .info { border-color: #8c959f; }
+.meta { color: #57606a; font-size: .9rem; }
+pre { background: #f6f8fa; padding: .5rem; overflow-x: auto; }
+code { font-family: ui-monospace, Menlo, monospace; }
+</style>
+</head>
+<body>
+<h1>greenlight {{.Command}}</h1>
+{{if .Context}}<p class="meta">{{range $i, $c := .Context}}{{if $i}} · {{end}}{{$c.Label}}: <code>{{$c.Value}}</code>{{end}}</p>{{end}}
+{{with .Summary}}
+<p class="status {{if .Passed}}pass">GREENLIT{{else}}fail">NOT READY{{end}} — {{$.Verdict}}</p>
+<p>{{$.Counts}}<br>{{$.Risk}}</p>
+{{end}}
+{{range .Sections}}{{if .Findings}}
+<h2>{{.Heading}}</h2>
+{{range .Findings}}
+<div class="finding {{lower .Severity.String}}">
+<h3>{{.Title}}</h3>
+<p class="meta">{{if $.ByOwner}}<strong>{{.Severity}}</strong> · {{end}}{{if .Source}}<code>{{.Source}}</code>{{end}}{{if .Guideline}} · {{if .GuidelineURL}}<a href="{{.GuidelineURL}}">{{ref .Guideline .GuidelineTitle}}</a>{{else}}{{ref .Guideline .GuidelineTitle}}{{end}}{{end}}{{if .File}} · <code>{{.File}}{{if .Line}}:{{.Line}}{{end}}</code>{{end}}{{if .Fingerprint}} · id <code>{{id .RuleID .Fingerprint}}</code>{{end}} · {{tf "risk %d" (risk .)}}{{if and .Owners (not $.ByOwner)}} · {{join .Owners " "}}{{end}}</p>
+{{if .Code}}<pre><code>{{.Code}}</code></pre>{{end}}
+<p>{{.Detail}}</p>
+{{if .Fix}}<p><strong>{{t "Fix:"}}</strong> {{.Fix}}</p>{{end}}
+</div>
+{{end}}{{end}}{{end}}
+<p class="meta">{{tf "Generated by greenlight in %s." .Elapsed}}</p>
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a standalone HTML page.
+func (r *Findings) WriteHTML(w io.Writer) error {
+	r = r.localized()
+	s := r.Summary()
+	return htmlTemplate.Execute(w, struct {
+		Lang     string
+		Command  string
+		Header   []Field
+		Context  []Field
+		Summary  FindingsSummary
+		Verdict  string
+		Counts   string
+		Risk     string
+		Sections []docSection
+		ByOwner  bool
+		Elapsed  time.Duration
+	}{i18n.Language(), r.Command, r.Header, r.documentContext(), s, i18n.T(r.verdict(s)), counts(s), riskLine(s), r.documentSections(), r.GroupBy == "owner", r.Elapsed.Round(time.Millisecond)})
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// fastlane precheck's report: one Passed/Failed line per rule, a table of
+// the problems found and a closing verdict, so lanes and Danger steps that
+// read precheck's output can run greenlight in its place.
+
+// precheckRule is a precheck rule and the greenlight checks and rules that
+// cover it.
+type precheckRule struct {
+	Key    string // precheck's rule key, e.g. "unreachable_urls"
+	Name   string
+	Checks []string // App Store Connect check IDs
+	Rules  []string // codescan rule IDs
+}
+
+var precheckRules = []precheckRule{
+	{"placeholder_text", "No placeholder text", []string{"placeholder-content"}, []string{"placeholder-content"}},
+	{"other_platforms", "No mentioning competitors", []string{"platform-references"}, []string{"platform-reference"}},
+	{"future_functionality", "No future functionality promises", []string{"review-notes"}, nil},
+	{"unreachable_urls", "No broken urls", []string{"url-reachability", "support-url-content"}, nil},
+}
+
+// otherRule collects the findings no precheck rule covers.
+var otherRule = precheckRule{Key: "app_store_review_guidelines", Name: "App Store Review Guidelines"}
+
+// precheckRuleOf is the precheck rule f falls under.
+func precheckRuleOf(f scan.Finding) precheckRule {
+	id := strings.TrimPrefix(f.RuleID, f.Source+"/")
+	for _, rule := range precheckRules {
+		for _, c := range rule.Checks {
+			if f.Check == c {
+				return rule
+			}
+		}
+		for _, r := range rule.Rules {
+			if id == r {
+				return rule
+			}
+		}
+	}
+	return otherRule
+}
+
+// WriteFastlane writes the report in fastlane precheck's layout. CRITICAL
+// findings are precheck errors and WARN findings warnings; INFO findings
+// are left out, as precheck has no such level.
+func (r *Findings) WriteFastlane(w io.Writer) error {
+	failed := map[string][]scan.Finding{}
+	for _, f := range r.Findings {
+		if f.Severity >= severity.Warn {
+			key := precheckRuleOf(f).Key
+			failed[key] = append(failed[key], f)
+		}
+	}
+
+	var problems [][2]string
+	blocking := false
+	for _, rule := range append(precheckRules, otherRule) {
+		findings := failed[rule.Key]
+		if len(findings) == 0 {
+			fmt.Fprintf(w, "✅  Passed: %s\n", rule.Name)
+			continue
+		}
+		level := "⚠️  Warning"
+		for _, f := range findings {
+			if f.Severity == severity.Critical {
+				level = "😵  Failed"
+				blocking = true
+			}
+			field := location(f)
+			if field == "" {
+				field = ruleOf(f)
+			}
+			problems = append(problems, [2]string{field, f.Title})
+		}
+		fmt.Fprintf(w, "%s: %s (%s)-> %d potential problem(s)\n", level, rule.Name, rule.Key, len(findings))
+	}
+
+	if len(problems) > 0 {
+		fmt.Fprintln(w)
+		writeTable(w, "Potential problems", [2]string{"Field", "Failure reason"}, problems)
+	}
+	fmt.Fprintln(w)
+	switch {
+	case blocking:
+		fmt.Fprintln(w, "greenlight found one or more potential problems that must be addressed before submitting to review")
+	case len(problems) > 0:
+		fmt.Fprintln(w, "greenlight found one or more potential metadata problems, but this won't prevent the lane from completing")
+	default:
+		fmt.Fprintln(w, "greenlight found no potential problems")
+	}
+	return nil
+}
+
+// writeTable draws a two-column table with a title, as fastlane prints them.
+func writeTable(w io.Writer, title string, headings [2]string, rows [][2]string) {
+	widths := [2]int{utf8.RuneCountInString(headings[0]), utf8.RuneCountInString(headings[1])}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	inner := widths[0] + widths[1] + 3
+	if n := utf8.RuneCountInString(title); n > inner {
+		widths[1] += n - inner
+		inner = n
+	}
+	rule := "+" + strings.Repeat("-", widths[0]+2) + "+" + strings.Repeat("-", widths[1]+2) + "+"
+	pad := func(s string, n int) string { return s + strings.Repeat(" ", n-utf8.RuneCountInString(s)) }
+
+	left := (inner - utf8.RuneCountInString(title)) / 2
+	fmt.Fprintln(w, "+"+strings.Repeat("-", inner+2)+"+")
+	fmt.Fprintf(w, "| %s |\n", pad(strings.Repeat(" ", left)+title, inner))
+	fmt.Fprintln(w, rule)
+	fmt.Fprintf(w, "| %s | %s |\n", pad(headings[0], widths[0]), pad(headings[1], widths[1]))
+	fmt.Fprintln(w, rule)
+	for _, row := range rows {
+		fmt.Fprintf(w, "| %s | %s |\n", pad(row[0], widths[0]), pad(row[1], widths[1]))
+	}
+	fmt.Fprintln(w, rule)
+}
@@ -0,0 +1,440 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/internal/termout"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Formats are the output formats every findings report can be written in.
+var Formats = []string{"terminal", "json", "junit", "markdown", "html", "pdf", "checkstyle", "teamcity", "fastlane", "xcode"}
+
+// JSONSchemaVersion is the version of the JSON report contract, written as
+// "schema_version" at the top of every JSON report. Fields are only added
+// within a version; renaming or removing one bumps it.
+const JSONSchemaVersion = 1
+
+// Findings is a report over the findings of one command, whichever
+// scanners produced them. Commands fill it in and pick a format; the
+// rendering of each format lives here.
+type Findings struct {
+	// Command names the report: "greenlight <Command>".
+	Command string
+	// Header describes what was scanned (a project path, IPA or app ID).
+	// Markdown and HTML reports lead with it; in the terminal the command's
+	// banner has already shown it.
+	Header []Field
+	// Context describes what was scanned (project, app, bundle ID),
+	// shown above the findings.
+	Context []Field
+	// Root is the directory finding files are relative to, for formats
+	// that need absolute paths (xcode).
+	Root string
+	// Findings in report order (see scan.SortFindings).
+	Findings []scan.Finding
+	// Stats are extra summary lines, e.g. "120 files scanned".
+	Stats   []string
+	Elapsed time.Duration
+	// ShowSource tags each finding with the scanner that reported it.
+	ShowSource bool
+	// GroupBy "owner" groups Markdown and HTML findings by their CODEOWNERS
+	// owners instead of by severity.
+	GroupBy string
+	// Scope ends the verdict of a clean report: "no critical issues found
+	// in the binary".
+	Scope string
+	// JSON is the document WriteJSON encodes, for commands whose JSON
+	// output has its own schema. Without it, findings and summary are
+	// written.
+	JSON any
+}
+
+// Field is a labelled value in a report's context.
+type Field struct {
+	Label string
+	Value string
+}
+
+// FindingsSummary counts a report's findings by severity.
+type FindingsSummary struct {
+	Total    int  `json:"total"`
+	Critical int  `json:"critical"`
+	Warns    int  `json:"warns"`
+	Infos    int  `json:"infos"`
+	Passed   bool `json:"passed"` // true if zero CRITICALs
+	// RiskScore is the estimated likelihood, from 0 to 100, that the app is
+	// rejected for any of the findings; Grade rates it from A to F.
+	RiskScore int    `json:"risk_score"`
+	Grade     string `json:"grade"`
+}
+
+// Summary counts the report's findings.
+func (r *Findings) Summary() FindingsSummary {
+	var s FindingsSummary
+	var scores []int
+	for _, f := range r.Findings {
+		s.Total++
+		scores = append(scores, riskOf(f))
+		switch f.Severity {
+		case severity.Critical:
+			s.Critical++
+		case severity.Warn:
+			s.Warns++
+		case severity.Info:
+			s.Infos++
+		}
+	}
+	s.Passed = s.Critical == 0
+	s.RiskScore = risk.Overall(scores)
+	s.Grade = risk.Grade(s.RiskScore)
+	return s
+}
+
+// riskOf is the finding's risk score, estimated from its severity and
+// guideline when the scanner didn't set one (or it comes from an older
+// report).
+func riskOf(f scan.Finding) int {
+	if f.Risk > 0 {
+		return f.Risk
+	}
+	return risk.Score(f.Severity, f.Guideline)
+}
+
+// riskLine is the summary line with the app's risk score and grade.
+func riskLine(s FindingsSummary) string {
+	return i18n.Sprintf("Rejection risk: %d/100 (grade %s)", s.RiskScore, s.Grade)
+}
+
+// Write writes the report in format, one of Formats ("md" is short for
+// markdown). Unknown formats fall back to terminal output.
+func (r *Findings) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		return r.WriteJSON(w)
+	case "junit":
+		return r.WriteJUnit(w)
+	case "markdown", "md":
+		return r.WriteMarkdown(w)
+	case "html":
+		return r.WriteHTML(w)
+	case "pdf":
+		return r.WritePDF(w)
+	case "checkstyle":
+		return r.WriteCheckstyle(w)
+	case "teamcity":
+		return r.WriteTeamCity(w)
+	case "fastlane":
+		return r.WriteFastlane(w)
+	case "xcode":
+		return r.WriteXcode(w)
+	default:
+		return r.WriteTerminal(w)
+	}
+}
+
+// IsTerminal reports whether format is written as terminal output, which
+// commands precede with their banner.
+func IsTerminal(format string) bool {
+	switch strings.ToLower(format) {
+	case "json", "junit", "markdown", "md", "html", "pdf", "checkstyle", "teamcity", "fastlane", "xcode":
+		return false
+	}
+	return true
+}
+
+// sections are the severity groups of every format, most severe first.
+var sections = []struct {
+	Severity severity.Level
+	Terminal string
+	Heading  string
+}{
+	{severity.Critical, "CRITICAL — Will be rejected", "Critical — will be rejected"},
+	{severity.Warn, "WARNING — High rejection risk", "Warnings — high rejection risk"},
+	{severity.Info, "INFO — Best practices", "Info — best practices"},
+}
+
+// bySeverity returns the report's findings of level l.
+func (r *Findings) bySeverity(l severity.Level) []scan.Finding {
+	var out []scan.Finding
+	for _, f := range r.Findings {
+		if f.Severity == l {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// verdict is the text after GREENLIT or NOT READY.
+func (r *Findings) verdict(s FindingsSummary) string {
+	if !s.Passed {
+		return fmt.Sprintf("%d critical issue(s) must be fixed", s.Critical)
+	}
+	if r.Scope != "" {
+		return "no critical issues found " + r.Scope
+	}
+	return "no critical issues found"
+}
+
+// counts is the summary line of documents: "3 findings: 1 critical, 2 warn,
+// 0 info".
+func counts(s FindingsSummary) string {
+	return i18n.Sprintf("%d findings", s.Total) + ": " + strings.Join([]string{
+		i18n.Sprintf("%d critical", s.Critical),
+		i18n.Sprintf("%d warn", s.Warns),
+		i18n.Sprintf("%d info", s.Infos),
+	}, ", ")
+}
+
+// localized returns a copy of the report for human readers, with finding
+// text, context and stats in the selected language (see i18n). Machine
+// formats are always written in English.
+func (r *Findings) localized() *Findings {
+	if !i18n.Active() {
+		return r
+	}
+	l := *r
+	l.Header = localizedFields(r.Header)
+	l.Context = localizedFields(r.Context)
+	l.Stats = make([]string, len(r.Stats))
+	for i, stat := range r.Stats {
+		l.Stats[i] = i18n.T(stat)
+	}
+	l.Findings = make([]scan.Finding, len(r.Findings))
+	for i, f := range r.Findings {
+		f.Title, f.Detail, f.Fix = i18n.T(f.Title), i18n.T(f.Detail), i18n.T(f.Fix)
+		l.Findings[i] = f
+	}
+	return &l
+}
+
+func localizedFields(fields []Field) []Field {
+	out := make([]Field, len(fields))
+	for i, c := range fields {
+		out[i] = Field{i18n.T(c.Label), i18n.T(c.Value)}
+	}
+	return out
+}
+
+// severityColor is the color findings of level l are shown in.
+func severityColor(l severity.Level) *color.Color {
+	switch l {
+	case severity.Critical:
+		return red
+	case severity.Warn:
+		return yellow
+	}
+	return dim
+}
+
+// WriteTerminal writes the report for reading in a terminal: context,
+// findings grouped by severity, the verdict and summary counts.
+func (r *Findings) WriteTerminal(w io.Writer) error {
+	r = r.localized()
+	width := 0
+	for _, c := range r.Context {
+		width = max(width, termout.DisplayWidth(c.Label)+1)
+	}
+	for _, c := range r.Context {
+		fmt.Fprintf(w, "  %s%s %s\n", c.Label+":", strings.Repeat(" ", width-termout.DisplayWidth(c.Label)-1), c.Value)
+	}
+	if len(r.Context) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	if len(r.Findings) == 0 {
+		green.Fprintln(w, "  "+i18n.T("No issues found!"))
+		fmt.Fprintln(w)
+	}
+	for _, sec := range sections {
+		findings := r.bySeverity(sec.Severity)
+		if len(findings) == 0 {
+			continue
+		}
+		severityColor(sec.Severity).Fprintln(w, "  "+i18n.T(sec.Terminal))
+		fmt.Fprintln(w)
+		for _, f := range findings {
+			r.printFinding(w, f)
+		}
+	}
+
+	s := r.Summary()
+	dim.Fprintln(w, "  ─────────────────────────────────────────────")
+	fmt.Fprintln(w)
+	if s.Passed {
+		green.Fprint(w, "  GREENLIT")
+	} else {
+		red.Fprint(w, "  NOT READY")
+	}
+	fmt.Fprintf(w, " — %s\n", i18n.T(r.verdict(s)))
+
+	if s.Total > 0 {
+		fmt.Fprint(w, "  "+i18n.Sprintf("%d findings", s.Total)+": ")
+		if s.Critical > 0 {
+			red.Fprint(w, i18n.Sprintf("%d critical", s.Critical)+"  ")
+		}
+		if s.Warns > 0 {
+			yellow.Fprint(w, i18n.Sprintf("%d warn", s.Warns)+"  ")
+		}
+		if s.Infos > 0 {
+			dim.Fprint(w, i18n.Sprintf("%d info", s.Infos))
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "  %s\n", riskLine(s))
+	}
+	for _, line := range r.Stats {
+		dim.Fprintf(w, "  %s\n", line)
+	}
+	dim.Fprintf(w, "  %s\n", i18n.Sprintf("completed in %s", r.Elapsed.Round(time.Millisecond)))
+
+	termout.Attribution(w)
+	return nil
+}
+
+// badges are the severity tags in front of each finding, padded to one width.
+var badges = map[severity.Level]string{
+	severity.Critical: "  [CRITICAL] ",
+	severity.Warn:     "  [WARN]     ",
+	severity.Info:     "  [INFO]     ",
+}
+
+// findingIndent is the column finding details start at, under the title.
+const findingIndent = 13
+
+func (r *Findings) printFinding(w io.Writer, f scan.Finding) {
+	pad := strings.Repeat(" ", findingIndent)
+	severityColor(f.Severity).Fprint(w, badges[f.Severity])
+	if r.ShowSource && f.Source != "" {
+		dim.Fprintf(w, "[%s] ", f.Source)
+	}
+	if f.Guideline != "" {
+		bold.Fprintf(w, "§%s ", f.Guideline)
+	}
+	bold.Fprint(w, f.Title)
+	dim.Fprintf(w, "  %s\n", i18n.Sprintf("risk %d", riskOf(f)))
+
+	if loc := location(f); loc != "" {
+		dim.Fprintf(w, "%s%s\n", pad, loc)
+	}
+	if f.Code != "" {
+		dim.Fprintf(w, "%s> %s\n", pad, truncate(f.Code, 80))
+	}
+
+	width := termout.Width(w)
+	fmt.Fprintf(w, "%s%s\n", pad, termout.Wrap(f.Detail, width, findingIndent))
+	if f.Fix != "" {
+		label := i18n.T("Fix:")
+		green.Fprintf(w, "%s%s ", pad, label)
+		fmt.Fprintln(w, termout.Wrap(f.Fix, width, findingIndent+termout.DisplayWidth(label)+1))
+	}
+	if f.Fingerprint != "" {
+		dim.Fprintf(w, "%sid: %s\n", pad, findingid.Display(f.RuleID, f.Fingerprint))
+	}
+	fmt.Fprintln(w)
+}
+
+// location formats "file:line", or just the file.
+func location(f scan.Finding) string {
+	if f.File == "" || f.Line <= 0 {
+		return f.File
+	}
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// truncate shortens s to one line of at most maxLen characters.
+func truncate(s string, maxLen int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
+
+// WriteJSON writes the command's JSON document, or the findings with a
+// summary and elapsed time, led by the schema version.
+func (r *Findings) WriteJSON(w io.Writer) error {
+	doc := r.JSON
+	if doc == nil {
+		findings := r.Findings
+		if findings == nil {
+			findings = []scan.Finding{}
+		}
+		doc = struct {
+			Findings []scan.Finding  `json:"findings"`
+			Summary  FindingsSummary `json:"summary"`
+			Elapsed  string          `json:"elapsed"`
+		}{findings, r.Summary(), r.Elapsed.Round(time.Millisecond).String()}
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if rest, ok := bytes.CutPrefix(data, []byte("{\n")); ok {
+		data = append([]byte(fmt.Sprintf("{\n  \"schema_version\": %d,\n", JSONSchemaVersion)), rest...)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteJUnit writes one test case per finding; critical findings fail.
+func (r *Findings) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:  "greenlight." + r.Command,
+		Tests: len(r.Findings),
+		Time:  fmt.Sprintf("%.3f", r.Elapsed.Seconds()),
+	}
+	suite.Cases, suite.Failures = junitCases(r.Findings)
+
+	fmt.Fprint(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}})
+}
+
+// junitCases turns each finding into a test case, classed by source and
+// guideline; critical findings fail.
+func junitCases(findings []scan.Finding) (cases []junitTestCase, failures int) {
+	for _, f := range findings {
+		tc := junitTestCase{
+			Name:      f.Title,
+			ClassName: strings.TrimSuffix(fmt.Sprintf("greenlight.%s.%s", f.Source, f.Guideline), "."),
+		}
+		if f.RuleID != "" {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "rule_id", Value: f.RuleID})
+		}
+		if f.Fingerprint != "" {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "fingerprint", Value: f.Fingerprint})
+		}
+		tc.Properties = append(tc.Properties, junitProperty{Name: "risk", Value: strconv.Itoa(riskOf(f))})
+		if loc := location(f); loc != "" {
+			tc.Properties = append(tc.Properties, junitProperty{Name: "file", Value: loc})
+		}
+
+		if f.Severity == severity.Critical {
+			failures++
+			text := f.Detail
+			if f.Fix != "" {
+				text += "\n\nFix: " + f.Fix
+			}
+			tc.Failure = &junitFailure{
+				Message: f.Title,
+				Type:    f.Severity.String(),
+				Text:    text,
+			}
+		}
+		cases = append(cases, tc)
+	}
+	return cases, failures
+}
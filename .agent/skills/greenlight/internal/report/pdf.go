@@ -0,0 +1,333 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/i18n"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// The PDF report is generated directly: A4 pages set in the standard
+// Helvetica and Courier fonts, which every PDF reader has, so no font is
+// embedded and no HTML renderer is needed.
+
+const (
+	pdfPageWidth  = 595.0 // A4 in points
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+	pdfBottom     = 70.0 // footer space
+)
+
+type pdfFont string
+
+const (
+	pdfRegular pdfFont = "F1" // Helvetica
+	pdfBold    pdfFont = "F2" // Helvetica-Bold
+	pdfMono    pdfFont = "F3" // Courier
+)
+
+type pdfColor [3]float64
+
+var (
+	pdfBlack  = pdfColor{0.11, 0.11, 0.12}
+	pdfGray   = pdfColor{0.34, 0.38, 0.42}
+	pdfPurple = pdfColor{0.63, 0.13, 0.75}
+	pdfRed    = pdfColor{0.81, 0.13, 0.18}
+	pdfAmber  = pdfColor{0.75, 0.53, 0}
+	pdfGreen  = pdfColor{0.10, 0.50, 0.22}
+	pdfRule   = pdfColor{0.82, 0.84, 0.87}
+)
+
+var pdfSeverityColor = map[severity.Level]pdfColor{
+	severity.Critical: pdfRed,
+	severity.Warn:     pdfAmber,
+	severity.Info:     pdfGray,
+}
+
+// pdfDoc lays text out top to bottom, starting a new page when one fills.
+type pdfDoc struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func (d *pdfDoc) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+func (d *pdfDoc) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pdfPageHeight - pdfMargin
+}
+
+// need starts a new page unless height points fit on this one.
+func (d *pdfDoc) need(height float64) {
+	if len(d.pages) == 0 || d.y-height < pdfBottom {
+		d.newPage()
+	}
+}
+
+// text writes one line at x on the baseline y.
+func (d *pdfDoc) text(font pdfFont, size float64, c pdfColor, x, y float64, s string) {
+	fmt.Fprintf(d.page(), "BT %.2f %.2f %.2f rg /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		c[0], c[1], c[2], font, size, x, y, pdfString(s))
+}
+
+// line writes s at x on the next line, wrapped to the page width.
+func (d *pdfDoc) line(font pdfFont, size float64, c pdfColor, x float64, s string) {
+	leading := size * 1.35
+	for _, l := range pdfWrap(s, font, size, pdfPageWidth-pdfMargin-x) {
+		d.need(leading)
+		d.y -= leading
+		d.text(font, size, c, x, d.y, l)
+	}
+}
+
+// rule draws a horizontal line across the text column.
+func (d *pdfDoc) rule(c pdfColor) {
+	d.need(12)
+	d.y -= 8
+	fmt.Fprintf(d.page(), "%.2f %.2f %.2f RG 0.75 w %.2f %.2f m %.2f %.2f l S\n",
+		c[0], c[1], c[2], pdfMargin, d.y, pdfPageWidth-pdfMargin, d.y)
+	d.y -= 4
+}
+
+// bar draws a colored bar left of the text, from top down to the current
+// line. Findings that run onto another page go without.
+func (d *pdfDoc) bar(c pdfColor, top float64) {
+	fmt.Fprintf(d.page(), "%.2f %.2f %.2f rg %.2f %.2f 3 %.2f re f\n",
+		c[0], c[1], c[2], pdfMargin, d.y-3, top-d.y)
+}
+
+func (d *pdfDoc) space(points float64) {
+	d.y -= points
+}
+
+// WritePDF writes the report as a compliance summary in PDF, for sharing
+// with clients and compliance reviewers ahead of a release.
+func (r *Findings) WritePDF(w io.Writer) error {
+	// The standard PDF fonts only cover Western European text, so reports
+	// in languages written in other scripts stay in English.
+	t, tf := i18n.T, i18n.Sprintf
+	if i18n.Latin() {
+		r = r.localized()
+	} else {
+		t, tf = func(s string) string { return s }, fmt.Sprintf
+	}
+
+	d := &pdfDoc{}
+	d.newPage()
+	s := r.Summary()
+
+	d.line(pdfBold, 22, pdfPurple, pdfMargin, "greenlight")
+	d.line(pdfRegular, 13, pdfBlack, pdfMargin, tf("App Store compliance report — %s", r.Command))
+	d.space(6)
+	for _, c := range r.documentContext() {
+		d.line(pdfRegular, 10, pdfGray, pdfMargin, c.Label+": "+c.Value)
+	}
+	d.line(pdfRegular, 10, pdfGray, pdfMargin, tf("Generated: %s", time.Now().Format("2 January 2006 15:04 MST")))
+	d.rule(pdfRule)
+
+	d.space(6)
+	if s.Passed {
+		d.line(pdfBold, 16, pdfGreen, pdfMargin, "GREENLIT — "+t(r.verdict(s)))
+	} else {
+		d.line(pdfBold, 16, pdfRed, pdfMargin, "NOT READY — "+t(r.verdict(s)))
+	}
+	d.line(pdfRegular, 11, pdfBlack, pdfMargin, tf("%d findings", s.Total)+": "+strings.Join([]string{tf("%d critical", s.Critical), tf("%d warn", s.Warns), tf("%d info", s.Infos)}, ", "))
+	d.line(pdfRegular, 11, pdfBlack, pdfMargin, tf("Rejection risk: %d/100 (grade %s)", s.RiskScore, s.Grade))
+	for _, stat := range r.Stats {
+		d.line(pdfRegular, 10, pdfGray, pdfMargin, stat)
+	}
+	d.line(pdfRegular, 10, pdfGray, pdfMargin, tf("Completed in %s", r.Elapsed.Round(time.Millisecond)))
+
+	for _, sec := range sections {
+		findings := r.bySeverity(sec.Severity)
+		if len(findings) == 0 {
+			continue
+		}
+		d.space(10)
+		d.need(60)
+		d.line(pdfBold, 14, pdfSeverityColor[sec.Severity], pdfMargin, t(sec.Heading))
+		d.rule(pdfRule)
+		for _, f := range findings {
+			d.space(6)
+			d.need(50)
+			top, page := d.y, len(d.pages)
+			x := pdfMargin + 10
+			d.line(pdfBold, 11, pdfBlack, x, f.Title)
+			var meta []string
+			if f.Source != "" {
+				meta = append(meta, f.Source)
+			}
+			if f.Guideline != "" {
+				meta = append(meta, GuidelineRef(f.Guideline, f.GuidelineTitle))
+			}
+			if loc := location(f); loc != "" {
+				meta = append(meta, loc)
+			}
+			if f.Fingerprint != "" {
+				meta = append(meta, "id "+findingid.Display(f.RuleID, f.Fingerprint))
+			}
+			meta = append(meta, tf("risk %d", riskOf(f)))
+			if len(meta) > 0 {
+				d.line(pdfRegular, 9, pdfGray, x, strings.Join(meta, " · "))
+			}
+			if f.Code != "" {
+				d.line(pdfMono, 8.5, pdfGray, x, truncate(strings.TrimSpace(f.Code), 120))
+			}
+			d.line(pdfRegular, 10, pdfBlack, x, f.Detail)
+			if f.Fix != "" {
+				d.line(pdfRegular, 10, pdfGreen, x, t("Fix:")+" "+f.Fix)
+			}
+			if len(d.pages) == page {
+				d.bar(pdfSeverityColor[f.Severity], top)
+			}
+		}
+	}
+
+	for i, p := range d.pages {
+		fmt.Fprintf(p, "%.2f %.2f %.2f RG 0.5 w %.2f %.2f m %.2f %.2f l S\n",
+			pdfRule[0], pdfRule[1], pdfRule[2], pdfMargin, pdfBottom-20, pdfPageWidth-pdfMargin, pdfBottom-20)
+		footer := &pdfDoc{pages: []*bytes.Buffer{p}}
+		footer.text(pdfRegular, 8, pdfGray, pdfMargin, pdfBottom-34, "Built by Revyl — the mobile reliability platform · https://revyl.com")
+		footer.text(pdfRegular, 8, pdfGray, pdfPageWidth-pdfMargin-50, pdfBottom-34, fmt.Sprintf("Page %d of %d", i+1, len(d.pages)))
+	}
+	return writePDF(w, d.pages)
+}
+
+// writePDF writes the PDF file: catalog, page tree, fonts, then each page
+// and its compressed content stream.
+func writePDF(w io.Writer, pages []*bytes.Buffer) error {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	for i, p := range pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R /F3 5 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*i))
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(p.Bytes())
+		zw.Close()
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// pdfString encodes s in WinAnsiEncoding as a PDF string literal's content.
+// Characters the standard fonts can't show become '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch ch := winAnsi(c); ch {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// winAnsiExtra are the WinAnsiEncoding characters outside Latin-1.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+func winAnsi(c rune) byte {
+	switch {
+	case c == '\t':
+		return ' '
+	case c >= 0x20 && c < 0x7F, c >= 0xA0 && c <= 0xFF:
+		return byte(c)
+	}
+	if b, ok := winAnsiExtra[c]; ok {
+		return b
+	}
+	return '?'
+}
+
+// helveticaWidths are Helvetica's advance widths for ' ' through '~', in
+// thousandths of the font size.
+var helveticaWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// textWidth estimates the width of s in points. Bold is taken as a little
+// wider than regular; Courier is monospaced.
+func textWidth(s string, font pdfFont, size float64) float64 {
+	total := 0
+	for _, c := range s {
+		switch {
+		case font == pdfMono:
+			total += 600
+		case c >= ' ' && c <= '~':
+			total += helveticaWidths[c-' ']
+		default:
+			total += 556
+		}
+	}
+	w := float64(total) * size / 1000
+	if font == pdfBold {
+		w *= 1.07
+	}
+	return w
+}
+
+// pdfWrap breaks s into lines no wider than width points.
+func pdfWrap(s string, font pdfFont, size, width float64) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			switch {
+			case line == "":
+				line = word
+			case textWidth(line+" "+word, font, size) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,208 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/checks"
+)
+
+// AppResult is one app's outcome in a portfolio scan: its results, or the
+// error that stopped its scan.
+type AppResult struct {
+	AppID    string
+	AppName  string
+	BundleID string
+	Results  *checks.Results
+	Err      error
+	Elapsed  time.Duration
+}
+
+// Passed reports whether the app's scan ran and found no blocking issues.
+func (a AppResult) Passed() bool {
+	return a.Err == nil && a.Results != nil && a.Results.Summary.Passed
+}
+
+// Portfolio is a consolidated report over several apps.
+type Portfolio struct {
+	apps    []AppResult
+	elapsed time.Duration
+}
+
+func NewPortfolio(apps []AppResult, elapsed time.Duration) *Portfolio {
+	return &Portfolio{apps: apps, elapsed: elapsed}
+}
+
+// Summary counts apps that passed, failed (blocking findings) or errored.
+func (p *Portfolio) Summary() (passed, failed, errored int) {
+	for _, a := range p.apps {
+		switch {
+		case a.Err != nil:
+			errored++
+		case a.Passed():
+			passed++
+		default:
+			failed++
+		}
+	}
+	return passed, failed, errored
+}
+
+// Write writes the portfolio in format. Markdown, HTML, PDF, checkstyle,
+// TeamCity, fastlane and Xcode reports cover a single app; portfolios are written as terminal, JSON or JUnit output.
+func (p *Portfolio) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		return p.WriteJSON(w)
+	case "junit":
+		return p.WriteJUnit(w)
+	case "markdown", "md", "html", "pdf", "checkstyle", "teamcity", "fastlane", "xcode":
+		return fmt.Errorf("--format %s isn't supported for portfolio scans; use terminal, json or junit", format)
+	default:
+		return p.WriteTerminal(w)
+	}
+}
+
+func (p *Portfolio) WriteTerminal(w io.Writer) error {
+	for _, a := range p.apps {
+		bold.Fprintf(w, "  %s", a.AppName)
+		if a.BundleID != "" {
+			dim.Fprintf(w, "  %s", a.BundleID)
+		}
+		dim.Fprintf(w, "  (%s)\n\n", a.AppID)
+		if a.Err != nil {
+			red.Fprint(w, "  ERROR")
+			fmt.Fprintf(w, " — %v\n\n", a.Err)
+			continue
+		}
+		var rep Findings
+		for _, f := range a.Results.Findings {
+			if f.Severity == checks.SeverityCritical || f.Severity == checks.SeverityWarn {
+				rep.printFinding(w, f.ScanFinding())
+			}
+		}
+		if s := a.Results.Summary; s.Blocks == 0 && s.Warns == 0 {
+			dim.Fprintln(w, "  no blocking issues or warnings")
+			fmt.Fprintln(w)
+		}
+		if a.Results.Summary.Infos > 0 {
+			dim.Fprintf(w, "  %d info finding(s) not shown; scan the app on its own for details\n\n", a.Results.Summary.Infos)
+		}
+	}
+
+	fmt.Fprintln(w)
+	dim.Fprintln(w, "  ─────────────────────────────────────────────")
+	fmt.Fprintln(w)
+
+	for _, a := range p.apps {
+		switch {
+		case a.Err != nil:
+			red.Fprint(w, "  ERROR      ")
+		case a.Passed():
+			green.Fprint(w, "  GREENLIT   ")
+		default:
+			red.Fprint(w, "  NOT READY  ")
+		}
+		fmt.Fprintf(w, "%-32s", a.AppName)
+		if a.Results != nil {
+			s := a.Results.Summary
+			fmt.Fprintf(w, " %d critical, %d warn, %d info", s.Blocks, s.Warns, s.Infos)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+
+	passed, failed, errored := p.Summary()
+	fmt.Fprintf(w, "  %d apps: ", len(p.apps))
+	green.Fprintf(w, "%d greenlit  ", passed)
+	if failed > 0 {
+		red.Fprintf(w, "%d not ready  ", failed)
+	}
+	if errored > 0 {
+		red.Fprintf(w, "%d errored", errored)
+	}
+	fmt.Fprintln(w)
+	dim.Fprintf(w, "  completed in %s\n\n", p.elapsed.Round(time.Millisecond))
+	return nil
+}
+
+type portfolioJSON struct {
+	Apps    []portfolioApp   `json:"apps"`
+	Summary portfolioSummary `json:"summary"`
+}
+
+type portfolioApp struct {
+	AppID    string           `json:"app_id"`
+	AppName  string           `json:"app_name"`
+	BundleID string           `json:"bundle_id,omitempty"`
+	Passed   bool             `json:"passed"`
+	Error    string           `json:"error,omitempty"`
+	Findings []checks.Finding `json:"findings"`
+	Summary  *checks.Summary  `json:"summary,omitempty"`
+}
+
+type portfolioSummary struct {
+	Apps      int  `json:"apps"`
+	Passed    int  `json:"passed"`
+	Failed    int  `json:"failed"`
+	Errored   int  `json:"errored"`
+	AllPassed bool `json:"all_passed"`
+}
+
+// WriteJSON writes one entry per app plus a portfolio summary.
+func (p *Portfolio) WriteJSON(w io.Writer) error {
+	out := portfolioJSON{Apps: []portfolioApp{}}
+	for _, a := range p.apps {
+		entry := portfolioApp{AppID: a.AppID, AppName: a.AppName, BundleID: a.BundleID, Passed: a.Passed(), Findings: []checks.Finding{}}
+		if a.Err != nil {
+			entry.Error = a.Err.Error()
+		}
+		if a.Results != nil && a.Results.Findings != nil {
+			entry.Findings = a.Results.Findings
+		}
+		if a.Results != nil {
+			entry.Summary = &a.Results.Summary
+		}
+		out.Apps = append(out.Apps, entry)
+	}
+	passed, failed, errored := p.Summary()
+	out.Summary = portfolioSummary{Apps: len(p.apps), Passed: passed, Failed: failed, Errored: errored, AllPassed: failed == 0 && errored == 0}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteJUnit writes one test suite per app. An app whose scan failed gets a
+// single failing test case carrying the error.
+func (p *Portfolio) WriteJUnit(w io.Writer) error {
+	var suites junitTestSuites
+	for _, a := range p.apps {
+		suite := junitTestSuite{
+			Name: "greenlight." + a.AppID,
+			Time: fmt.Sprintf("%.3f", a.Elapsed.Seconds()),
+		}
+		if a.Err != nil {
+			suite.Tests, suite.Failures = 1, 1
+			suite.Cases = []junitTestCase{{
+				Name:      "scan",
+				ClassName: "greenlight." + a.AppID,
+				Failure:   &junitFailure{Message: "scan failed", Type: "ERROR", Text: a.Err.Error()},
+			}}
+			suites.Suites = append(suites.Suites, suite)
+			continue
+		}
+		suite.Cases, suite.Failures = junitCases(checks.ScanFindings(a.Results.Findings))
+		suite.Tests = len(suite.Cases)
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	fmt.Fprint(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(suites)
+}
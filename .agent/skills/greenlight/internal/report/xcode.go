@@ -0,0 +1,43 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// xcodeSeverity maps levels to the diagnostic kinds Xcode's build log
+// parser recognizes.
+var xcodeSeverity = map[severity.Level]string{
+	severity.Critical: "error",
+	severity.Warn:     "warning",
+	severity.Info:     "note",
+}
+
+// WriteXcode writes one "path:line: error: message" diagnostic per finding,
+// as Xcode parses from Run Script build phase output, so findings appear in
+// the issue navigator and inline in the editor. Files are made absolute
+// against Root, as Xcode needs; findings that aren't about a file are
+// reported without a location.
+func (r *Findings) WriteXcode(w io.Writer) error {
+	for _, f := range r.Findings {
+		msg := strings.Join(strings.Fields(findingMessage(f)), " ") + " [greenlight:" + ruleOf(f) + "]"
+		kind := xcodeSeverity[f.Severity]
+		if f.File == "" {
+			fmt.Fprintf(w, "%s: %s\n", kind, msg)
+			continue
+		}
+		file := f.File
+		if !filepath.IsAbs(file) && r.Root != "" {
+			if root, err := filepath.Abs(r.Root); err == nil {
+				file = filepath.Join(root, file)
+			}
+		}
+		line := max(f.Line, 1)
+		fmt.Fprintf(w, "%s:%d: %s: %s\n", file, line, kind, msg)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Package risk estimates how likely findings are to get an app rejected,
+// as a score from 0 to 100 per finding and for the app as a whole, so teams
+// can order fixes within a severity and track an app's readiness over time.
+//
+// A finding's score starts from its severity and is weighted by how often
+// Apple rejects apps under the guideline it cites: Apple's App Store
+// Transparency Reports put Performance (section 2, led by App Completeness)
+// far ahead of Legal (5), Design (4), Business (3) and Safety (1), and a few
+// guidelines account for most rejections within their section.
+package risk
+
+import (
+	"math"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// base is the score of a finding of each severity under a guideline with
+// an average rejection rate.
+var base = map[severity.Level]float64{
+	severity.Critical: 80,
+	severity.Warn:     40,
+	severity.Info:     8,
+}
+
+// sectionWeight scales scores by how often Apple rejects apps under each
+// top-level section of the guidelines.
+var sectionWeight = map[string]float64{
+	"1": 0.9,  // Safety
+	"2": 1.15, // Performance
+	"3": 1.0,  // Business
+	"4": 1.0,  // Design
+	"5": 1.05, // Legal
+}
+
+// frequent are the guidelines most rejections are cited under, weighted on
+// top of their section.
+var frequent = map[string]float64{
+	"2.1":      1.1, // App Completeness: crashes, placeholders, broken links
+	"2.3":      1.1, // Accurate Metadata
+	"2.3.3":    1.05,
+	"3.1.1":    1.1, // In-App Purchase
+	"4.0":      1.05,
+	"4.2":      1.05, // Minimum Functionality
+	"4.3":      1.1,  // Spam
+	"4.8":      1.05, // Login Services
+	"5.1.1":    1.1,  // Data Collection and Storage
+	"5.1.1(v)": 1.1,  // Account deletion
+	"5.1.2":    1.05,
+}
+
+// Score is the estimated likelihood, from 0 to 100, that a finding of
+// severity sev citing guideline gets the app rejected.
+func Score(sev severity.Level, guideline string) int {
+	s := base[sev]
+	if s == 0 {
+		return 0
+	}
+	guideline = strings.TrimSpace(guideline)
+	top, _, _ := strings.Cut(guideline, ".")
+	if w, ok := sectionWeight[top]; ok {
+		s *= w
+	}
+	// The most specific frequent guideline the finding falls under.
+	for g := guideline; g != ""; g = parent(g) {
+		if w, ok := frequent[g]; ok {
+			s *= w
+			break
+		}
+	}
+	return int(math.Round(math.Min(s, 99)))
+}
+
+// parent is the guideline a section belongs to: "5.1.1(v)" → "5.1.1" →
+// "5.1" → "5" → "".
+func parent(section string) string {
+	if i := strings.LastIndexAny(section, ".("); i > 0 {
+		return section[:i]
+	}
+	return ""
+}
+
+// Overall combines finding scores into the likelihood that the app is
+// rejected for at least one of them, treating findings as independent.
+func Overall(scores []int) int {
+	pass := 1.0
+	for _, s := range scores {
+		pass *= 1 - float64(min(max(s, 0), 100))/100
+	}
+	return int(math.Round((1 - pass) * 100))
+}
+
+// Grade is the letter grade of an overall score: A (little risk) to F
+// (rejection all but certain).
+func Grade(score int) string {
+	switch {
+	case score < 10:
+		return "A"
+	case score < 30:
+		return "B"
+	case score < 60:
+		return "C"
+	case score < 85:
+		return "D"
+	}
+	return "F"
+}
@@ -0,0 +1,231 @@
+// Package sbom builds a software bill of materials for an iOS app from the
+// dependencies its lock files pin (CocoaPods, Swift packages and the npm
+// packages a React Native app bundles) and writes it as CycloneDX or SPDX
+// JSON.
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/deps"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// Formats are the SBOM formats Write accepts.
+var Formats = []string{"cyclonedx", "spdx"}
+
+// Ecosystems of a Component.
+const (
+	CocoaPods = "cocoapods"
+	Swift     = "swift"
+	NPM       = "npm"
+)
+
+// BOM is the app and the third-party components it ships.
+type BOM struct {
+	// App names the app the components belong to.
+	App        string
+	Components []Component
+}
+
+// Component is one third-party dependency.
+type Component struct {
+	Name      string
+	Version   string
+	Ecosystem string
+	// License is the SPDX identifier or expression read from the installed
+	// sources, or "" when they aren't installed or the license isn't
+	// recognized.
+	License string
+	// Repository is the source repository of a Swift package.
+	Repository string
+	// File is the lock file or manifest the component was read from,
+	// relative to the project, and Line the line of its pin there, or 0.
+	File string
+	Line int
+}
+
+// PURL is the component's package URL (github.com/package-url/purl-spec).
+func (c Component) PURL() string {
+	version := ""
+	if c.Version != "" {
+		version = "@" + url.PathEscape(c.Version)
+	}
+	switch c.Ecosystem {
+	case NPM:
+		name := c.Name
+		if scope, pkg, ok := strings.Cut(name, "/"); ok {
+			// The scope's "@" is percent-encoded: pkg:npm/%40babel/core.
+			name = "%40" + url.PathEscape(strings.TrimPrefix(scope, "@")) + "/" + url.PathEscape(pkg)
+		}
+		return "pkg:npm/" + name + version
+	case Swift:
+		if u, err := url.Parse(c.Repository); err == nil && u.Host != "" {
+			path := strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+			return "pkg:swift/" + u.Host + "/" + path + version
+		}
+		return "pkg:swift/" + url.PathEscape(c.Name) + version
+	}
+	return "pkg:cocoapods/" + url.PathEscape(c.Name) + version
+}
+
+// skipDirs are never searched for lock files.
+var skipDirs = map[string]bool{
+	"node_modules": true, ".git": true, "Pods": true,
+	"build": true, "dist": true, ".expo": true,
+	"DerivedData": true, "vendor": true,
+}
+
+// Collect reads the components pinned under root: every Podfile.lock and
+// Package.resolved, and for npm the package-lock.json, or the package.json
+// when there is no lock file. Licenses come from installed sources (Pods/,
+// node_modules, Swift package checkouts) where present.
+func Collect(root string) (*BOM, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	bom := &BOM{App: filepath.Base(abs)}
+
+	var manifests, checkouts []string
+	err = sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			switch {
+			case deps.IsCheckouts(path):
+				checkouts = append(checkouts, path)
+				return filepath.SkipDir
+			case info.Name() == "build" || info.Name() == "DerivedData":
+				checkouts = append(checkouts, deps.BuildCheckouts(path)...)
+				return filepath.SkipDir
+			case skipDirs[info.Name()]:
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch info.Name() {
+		case "Podfile.lock", "Package.resolved", "package-lock.json":
+			manifests = append(manifests, path)
+		case "package.json":
+			if _, err := os.Stat(filepath.Join(filepath.Dir(path), "package-lock.json")); err != nil {
+				manifests = append(manifests, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, manifest := range manifests {
+		var pins []deps.Dep
+		var ecosystem string
+		switch filepath.Base(manifest) {
+		case "Podfile.lock":
+			pins, err = deps.Pods(manifest)
+			ecosystem = CocoaPods
+		case "Package.resolved":
+			pins, err = deps.Packages(manifest)
+			ecosystem = Swift
+		case "package-lock.json":
+			pins, err = deps.NPMLock(manifest)
+			ecosystem = NPM
+		default:
+			pins, err = deps.NPM(manifest)
+			ecosystem = NPM
+		}
+		rel, _ := filepath.Rel(root, manifest)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+		}
+		for _, pin := range pins {
+			c := Component{
+				Name:       pin.Name,
+				Version:    pin.Version,
+				Ecosystem:  ecosystem,
+				Repository: pin.URL,
+				File:       filepath.ToSlash(rel),
+				Line:       pin.Line,
+			}
+			if dir := deps.Source(manifest, pin, checkouts); dir != "" {
+				c.License = deps.License(dir)
+				if filepath.Base(manifest) == "package.json" {
+					c.Version = installedVersion(dir, c.Version)
+				}
+			}
+			if seen[c.PURL()] {
+				continue // the same pin in several lock files
+			}
+			seen[c.PURL()] = true
+			bom.Components = append(bom.Components, c)
+		}
+	}
+	sort.SliceStable(bom.Components, func(i, j int) bool {
+		a, b := bom.Components[i], bom.Components[j]
+		if a.Ecosystem != b.Ecosystem {
+			return a.Ecosystem < b.Ecosystem
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return bom, nil
+}
+
+// installedVersion is the version of the npm package installed at dir, or
+// declared (the range from package.json) when it can't be read.
+func installedVersion(dir, declared string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return declared
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || pkg.Version == "" {
+		return declared
+	}
+	return pkg.Version
+}
+
+// spdxToken matches a license identifier of an SPDX expression.
+var spdxToken = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// spdxFamilies are the license names deps.License gives when the text
+// doesn't settle the version, which aren't SPDX identifiers.
+var spdxFamilies = map[string]bool{"BSD": true, "GPL": true, "LGPL": true}
+
+// validSPDX reports whether license is an SPDX identifier or expression.
+func validSPDX(license string) bool {
+	if license == "" {
+		return false
+	}
+	for _, tok := range strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(license)) {
+		switch tok {
+		case "AND", "OR", "WITH":
+			continue
+		}
+		if !spdxToken.MatchString(tok) || spdxFamilies[tok] {
+			return false
+		}
+	}
+	return true
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,228 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Write writes bom in format (cyclonedx or spdx) as JSON. version is
+// greenlight's, recorded as the generating tool.
+func Write(w io.Writer, bom *BOM, format, version string) error {
+	var doc any
+	switch format {
+	case "cyclonedx":
+		doc = cycloneDX(bom, version, time.Now().UTC())
+	case "spdx":
+		doc = spdx(bom, version, time.Now().UTC())
+	default:
+		return fmt.Errorf("unknown SBOM format %q (use cyclonedx or spdx)", format)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// CycloneDX 1.5 JSON (cyclonedx.org/docs/1.5/json).
+
+type cdxBOM struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+	Dependencies []cdxDependsOn `json:"dependencies,omitempty"`
+}
+
+type cdxMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cdxComponent `json:"components"`
+	} `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Licenses   []cdxLicense  `json:"licenses,omitempty"`
+	ExtRefs    []cdxExtRef   `json:"externalReferences,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxLicense struct {
+	License    *cdxLicenseID `json:"license,omitempty"`
+	Expression string        `json:"expression,omitempty"`
+}
+
+type cdxLicenseID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type cdxExtRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependsOn struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func cycloneDX(bom *BOM, version string, now time.Time) cdxBOM {
+	doc := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Components:   []cdxComponent{},
+	}
+	doc.Metadata.Timestamp = now.Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []cdxComponent{{Type: "application", Name: "greenlight", Version: version}}
+	doc.Metadata.Component = cdxComponent{Type: "application", BOMRef: "app", Name: bom.App}
+
+	app := cdxDependsOn{Ref: "app", DependsOn: []string{}}
+	for _, c := range bom.Components {
+		comp := cdxComponent{
+			Type:    "library",
+			BOMRef:  c.PURL(),
+			Name:    c.Name,
+			Version: c.Version,
+			PURL:    c.PURL(),
+			Properties: []cdxProperty{
+				{Name: "greenlight:ecosystem", Value: c.Ecosystem},
+				{Name: "greenlight:source", Value: c.File},
+			},
+		}
+		switch {
+		case c.License == "":
+		case !validSPDX(c.License):
+			comp.Licenses = []cdxLicense{{License: &cdxLicenseID{Name: c.License}}}
+		case isExpression(c.License):
+			comp.Licenses = []cdxLicense{{Expression: c.License}}
+		default:
+			comp.Licenses = []cdxLicense{{License: &cdxLicenseID{ID: c.License}}}
+		}
+		if c.Repository != "" {
+			comp.ExtRefs = []cdxExtRef{{Type: "vcs", URL: c.Repository}}
+		}
+		doc.Components = append(doc.Components, comp)
+		app.DependsOn = append(app.DependsOn, comp.BOMRef)
+	}
+	doc.Dependencies = []cdxDependsOn{app}
+	return doc
+}
+
+// isExpression reports whether license combines several identifiers.
+func isExpression(license string) bool {
+	return strings.ContainsAny(license, " (")
+}
+
+// SPDX 2.3 JSON (spdx.github.io/spdx-spec/v2.3).
+
+type spdxDoc struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string       `json:"name"`
+	SPDXID           string       `json:"SPDXID"`
+	VersionInfo      string       `json:"versionInfo,omitempty"`
+	DownloadLocation string       `json:"downloadLocation"`
+	FilesAnalyzed    bool         `json:"filesAnalyzed"`
+	LicenseConcluded string       `json:"licenseConcluded"`
+	LicenseDeclared  string       `json:"licenseDeclared"`
+	CopyrightText    string       `json:"copyrightText"`
+	PrimaryPurpose   string       `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs     []spdxExtRef `json:"externalRefs,omitempty"`
+	Comment          string       `json:"comment,omitempty"`
+}
+
+type spdxExtRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+const noAssertion = "NOASSERTION"
+
+func spdx(bom *BOM, version string, now time.Time) spdxDoc {
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              bom.App,
+		DocumentNamespace: "https://spdx.org/spdxdocs/" + url.PathEscape(bom.App) + "-" + newUUID(),
+		CreationInfo: spdxCreationInfo{
+			Created:  now.Format(time.RFC3339),
+			Creators: []string{"Tool: greenlight-" + version},
+		},
+	}
+	doc.Packages = append(doc.Packages, spdxPackage{
+		Name:             bom.App,
+		SPDXID:           "SPDXRef-App",
+		DownloadLocation: noAssertion,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  noAssertion,
+		CopyrightText:    noAssertion,
+		PrimaryPurpose:   "APPLICATION",
+	})
+	doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-App"})
+
+	for i, c := range bom.Components {
+		id := "SPDXRef-Package-" + strconv.Itoa(i+1)
+		pkg := spdxPackage{
+			Name:             c.Name,
+			SPDXID:           id,
+			VersionInfo:      c.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+			PrimaryPurpose:   "LIBRARY",
+			ExternalRefs:     []spdxExtRef{{Category: "PACKAGE-MANAGER", Type: "purl", Locator: c.PURL()}},
+			Comment:          c.Ecosystem + " dependency from " + c.File,
+		}
+		if strings.HasPrefix(c.Repository, "https://") {
+			pkg.DownloadLocation = "git+" + c.Repository
+		}
+		if validSPDX(c.License) {
+			pkg.LicenseDeclared = c.License
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-App", "DEPENDS_ON", id})
+	}
+	return doc
+}
@@ -0,0 +1,191 @@
+// Package score reduces an app's findings to one readiness percentage, with
+// a breakdown by section of the App Review Guidelines, for release
+// dashboards and launch checklists.
+//
+// Readiness is the complement of the rejection risk in internal/risk: an app
+// whose findings give it a 30% chance of rejection is 70% ready.
+package score
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Formats are the output formats Write accepts.
+var Formats = []string{"terminal", "json", "prometheus"}
+
+// sections are the categories of the breakdown: the top-level sections of
+// the guidelines, then findings that cite none.
+var sections = []struct{ id, name string }{
+	{"1", "Safety"},
+	{"2", "Performance"},
+	{"3", "Business"},
+	{"4", "Design"},
+	{"5", "Legal"},
+	{"other", "Other"},
+}
+
+// Category is the readiness of one section of the guidelines.
+type Category struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Readiness int    `json:"readiness"`
+	Critical  int    `json:"critical"`
+	Warns     int    `json:"warns"`
+	Infos     int    `json:"infos"`
+}
+
+// Score is an app's readiness for App Review.
+type Score struct {
+	// App names the app: its bundle ID, or the project directory when it
+	// has none.
+	App string `json:"app"`
+	// Readiness is the estimated likelihood, from 0 to 100, that the app
+	// passes App Review; Grade rates its rejection risk from A to F.
+	Readiness int    `json:"readiness"`
+	Grade     string `json:"grade"`
+	Passed    bool   `json:"passed"` // true if zero CRITICALs
+	Critical  int    `json:"critical"`
+	Warns     int    `json:"warns"`
+	Infos     int    `json:"infos"`
+	// Sources are the runs the findings came from: "preflight", "scan".
+	Sources    []string   `json:"sources"`
+	Categories []Category `json:"categories"`
+}
+
+// New scores findings for app. Every category is listed, so dashboards
+// see the same series whether or not it has findings.
+func New(app string, findings []scan.Finding, sources ...string) Score {
+	s := Score{App: app, Sources: sources}
+	if s.Sources == nil {
+		s.Sources = []string{}
+	}
+	all := make([]int, 0, len(findings))
+	bySection := make(map[string][]int)
+	counts := make(map[string]*Category)
+	for _, sec := range sections {
+		counts[sec.id] = &Category{ID: sec.id, Name: sec.name}
+	}
+	for _, f := range findings {
+		r := f.Risk
+		if r <= 0 {
+			r = risk.Score(f.Severity, f.Guideline)
+		}
+		sec := section(f.Guideline)
+		all = append(all, r)
+		bySection[sec] = append(bySection[sec], r)
+		c := counts[sec]
+		switch f.Severity {
+		case severity.Critical:
+			s.Critical++
+			c.Critical++
+		case severity.Warn:
+			s.Warns++
+			c.Warns++
+		case severity.Info:
+			s.Infos++
+			c.Infos++
+		}
+	}
+	overall := risk.Overall(all)
+	s.Readiness = 100 - overall
+	s.Grade = risk.Grade(overall)
+	s.Passed = s.Critical == 0
+	for _, sec := range sections {
+		c := counts[sec.id]
+		c.Readiness = 100 - risk.Overall(bySection[sec.id])
+		s.Categories = append(s.Categories, *c)
+	}
+	return s
+}
+
+// section is the top-level section of the guidelines a guideline belongs
+// to: "5" for "5.1.1(v)", "other" when there is none.
+func section(guideline string) string {
+	top, _, _ := strings.Cut(strings.TrimSpace(guideline), ".")
+	for _, sec := range sections {
+		if top == sec.id {
+			return top
+		}
+	}
+	return "other"
+}
+
+// Write writes s in format, one of Formats.
+func Write(w io.Writer, s Score, format string) error {
+	switch format {
+	case "terminal":
+		return writeTerminal(w, s)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(s)
+	case "prometheus":
+		return writePrometheus(w, s)
+	}
+	return fmt.Errorf("unknown score format %q (use %s)", format, strings.Join(Formats, ", "))
+}
+
+func writeTerminal(w io.Writer, s Score) error {
+	fmt.Fprintf(w, "\n  Readiness: %d%% (grade %s) — %s\n", s.Readiness, s.Grade, strings.Join(s.Sources, " + "))
+	fmt.Fprintf(w, "  %d critical, %d warn, %d info\n\n", s.Critical, s.Warns, s.Infos)
+	for _, c := range s.Categories {
+		line := fmt.Sprintf("  %-12s %3d%%", c.Name, c.Readiness)
+		if n := c.Critical + c.Warns + c.Infos; n > 0 {
+			line += fmt.Sprintf("   %d critical, %d warn, %d info", c.Critical, c.Warns, c.Infos)
+		}
+		fmt.Fprintln(w, line)
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// writePrometheus writes s in the Prometheus text exposition format, for
+// the node exporter's textfile collector or a Pushgateway.
+func writePrometheus(w io.Writer, s Score) error {
+	app := `app="` + escapeLabel(s.App) + `"`
+	var b strings.Builder
+	metric := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+
+	metric("greenlight_readiness_percent", "Estimated likelihood, in percent, that the app passes App Review.")
+	fmt.Fprintf(&b, "greenlight_readiness_percent{%s} %d\n", app, s.Readiness)
+
+	metric("greenlight_category_readiness_percent", "Readiness by section of the App Review Guidelines.")
+	for _, c := range s.Categories {
+		fmt.Fprintf(&b, "greenlight_category_readiness_percent{%s,category=%q} %d\n", app, strings.ToLower(c.Name), c.Readiness)
+	}
+
+	metric("greenlight_findings", "Findings by section of the App Review Guidelines and severity.")
+	for _, c := range s.Categories {
+		for _, n := range []struct {
+			severity string
+			count    int
+		}{{"critical", c.Critical}, {"warn", c.Warns}, {"info", c.Infos}} {
+			fmt.Fprintf(&b, "greenlight_findings{%s,category=%q,severity=%q} %d\n", app, strings.ToLower(c.Name), n.severity, n.count)
+		}
+	}
+
+	passed := 0
+	if s.Passed {
+		passed = 1
+	}
+	metric("greenlight_passed", "1 if the app has no critical findings, else 0.")
+	fmt.Fprintf(&b, "greenlight_passed{%s} %d\n", app, passed)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
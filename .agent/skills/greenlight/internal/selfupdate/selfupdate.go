@@ -0,0 +1,313 @@
+// Package selfupdate finds newer greenlight releases on the GitHub release
+// feed and replaces the running binary with a verified download.
+//
+// Every release carries checksums.txt (SHA-256 of each binary, in sha256sum
+// format) and checksums.txt.sig, an Ed25519 signature of checksums.txt made
+// with the release key. The public half is compiled in through publicKey; a
+// binary is only installed when the signature and its checksum both match.
+package selfupdate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/internal/config"
+)
+
+// ReleasesURL is the feed of the latest greenlight release.
+const ReleasesURL = "https://api.github.com/repos/RevylAI/greenlight/releases/latest"
+
+const (
+	checksumsAsset = "checksums.txt"
+	signatureAsset = "checksums.txt.sig"
+
+	// maxBinarySize bounds a downloaded binary.
+	maxBinarySize = 256 << 20
+)
+
+// publicKey is the base64-encoded (PKIX DER) Ed25519 key that signs
+// checksums.txt. Release builds set it with
+// -ldflags "-X github.com/RevylAI/greenlight/internal/selfupdate.publicKey=...".
+var publicKey string
+
+// ErrUnsigned is returned when this build has no release key to verify
+// signatures with.
+var ErrUnsigned = errors.New("this build has no release signing key; reinstall greenlight from a release")
+
+// Release is one published greenlight version.
+type Release struct {
+	Version string // without the leading "v"
+	URL     string // release notes page
+	// Assets maps an asset file name to its download URL.
+	Assets map[string]string
+}
+
+// Latest fetches the newest release from the feed.
+func Latest(ctx context.Context, client *http.Client) (*Release, error) {
+	data, err := get(ctx, client, ReleasesURL, 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	var feed struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("invalid release feed: %w", err)
+	}
+	if feed.TagName == "" {
+		return nil, fmt.Errorf("invalid release feed: no tag")
+	}
+	r := &Release{
+		Version: strings.TrimPrefix(feed.TagName, "v"),
+		URL:     feed.HTMLURL,
+		Assets:  map[string]string{},
+	}
+	for _, a := range feed.Assets {
+		r.Assets[a.Name] = a.URL
+	}
+	return r, nil
+}
+
+// ReleaseVersion returns v without its leading "v" if it names a release
+// ("1.4.0", "v1.4.0"). Development builds ("dev", "v1.4.0-3-gabc123",
+// "v1.4.0-dirty") are not releases.
+func ReleaseVersion(v string) (string, bool) {
+	v = strings.TrimPrefix(v, "v")
+	if appversion.Validate(v) != "" {
+		return "", false
+	}
+	return v, true
+}
+
+// Newer reports whether latest is a newer release than current. It is
+// false for development builds.
+func Newer(current, latest string) bool {
+	cur, ok := ReleaseVersion(current)
+	if !ok {
+		return false
+	}
+	lat, ok := ReleaseVersion(latest)
+	return ok && appversion.Compare(lat, cur) > 0
+}
+
+// AssetName is the release asset for this platform, as built by
+// 'make release': greenlight-<os>-<arch>.
+func AssetName() string {
+	name := "greenlight-" + runtime.GOOS + "-" + runtime.GOARCH
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Download fetches the binary for this platform and verifies it against
+// the release's signed checksums.
+func (r *Release) Download(ctx context.Context, client *http.Client) ([]byte, error) {
+	name := AssetName()
+	for _, asset := range []string{name, checksumsAsset, signatureAsset} {
+		if r.Assets[asset] == "" {
+			return nil, fmt.Errorf("release %s has no %s", r.Version, asset)
+		}
+	}
+	checksums, err := get(ctx, client, r.Assets[checksumsAsset], 1<<20)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsAsset, err)
+	}
+	sig, err := get(ctx, client, r.Assets[signatureAsset], 4<<10)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", signatureAsset, err)
+	}
+	if err := VerifySignature(checksums, sig); err != nil {
+		return nil, err
+	}
+	binary, err := get(ctx, client, r.Assets[name], maxBinarySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := VerifyChecksum(binary, checksums, name); err != nil {
+		return nil, err
+	}
+	return binary, nil
+}
+
+// VerifySignature checks sig (raw or base64) over checksums with the
+// compiled-in release key.
+func VerifySignature(checksums, sig []byte) error {
+	if publicKey == "" {
+		return ErrUnsigned
+	}
+	der, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil {
+		return fmt.Errorf("invalid release key: %w", err)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("invalid release key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return fmt.Errorf("invalid release key: not Ed25519")
+	}
+	if len(sig) != ed25519.SignatureSize {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig))); err == nil {
+			sig = decoded
+		}
+	}
+	if !ed25519.Verify(key, checksums, sig) {
+		return fmt.Errorf("%s signature does not match the release key; not installing", checksumsAsset)
+	}
+	return nil
+}
+
+// VerifyChecksum checks binary against the line for name in checksums.
+func VerifyChecksum(binary, checksums []byte, name string) error {
+	sum := sha256.Sum256(binary)
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+				return fmt.Errorf("%s checksum mismatch; not installing", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%s lists no checksum for %s", checksumsAsset, name)
+}
+
+// Replace atomically swaps the executable at exe for binary: the new file is
+// written next to it and renamed over it, so an interrupted update leaves
+// the old binary in place.
+func Replace(exe string, binary []byte) error {
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".greenlight-update-*")
+	if err != nil {
+		return fmt.Errorf("cannot write next to %s: %w", exe, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+	// Windows can't replace a running executable, but it can rename it.
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), exe)
+}
+
+func get(ctx context.Context, client *http.Client, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "greenlight-self-update")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("GET %s: response larger than %d bytes", url, limit)
+	}
+	return data, nil
+}
+
+// CheckInterval is how often the new-version notice refreshes the latest
+// release.
+const CheckInterval = 24 * time.Hour
+
+// LastCheck is the cached result of the last release check
+// (~/.greenlight/update-check.json).
+type LastCheck struct {
+	Checked time.Time `json:"checked"`
+	Latest  string    `json:"latest"`
+	URL     string    `json:"url,omitempty"`
+}
+
+func lastCheckPath() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "update-check.json"), nil
+}
+
+// LoadLastCheck returns the cached release check; a missing or unreadable
+// cache yields a zero LastCheck.
+func LoadLastCheck() LastCheck {
+	var c LastCheck
+	path, err := lastCheckPath()
+	if err != nil {
+		return c
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &c)
+	}
+	return c
+}
+
+// Stale reports whether the cache is older than CheckInterval.
+func (c LastCheck) Stale() bool {
+	return time.Since(c.Checked) > CheckInterval
+}
+
+// Refresh fetches the latest release and caches the result.
+func Refresh(ctx context.Context, client *http.Client) (LastCheck, error) {
+	r, err := Latest(ctx, client)
+	if err != nil {
+		return LastCheck{}, err
+	}
+	c := LastCheck{Checked: time.Now(), Latest: r.Version, URL: r.URL}
+	path, err := lastCheckPath()
+	if err != nil {
+		return c, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return c, err
+	}
+	data, _ := json.MarshalIndent(c, "", "  ")
+	return c, os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,128 @@
+// Package sourcefile reads project source files for the codescan and privacy
+// walkers and decides which files aren't worth scanning: binary blobs,
+// minified JavaScript and files over a size limit, which otherwise stall a
+// scan on vendored build output.
+package sourcefile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultMaxSize is the size above which source files are skipped.
+const DefaultMaxSize = 16 << 20
+
+// Reasons a file is skipped.
+const (
+	TooLarge = "too-large"
+	Binary   = "binary"
+	Minified = "minified"
+)
+
+// ErrBinary is returned by ReadLines for files that look binary.
+var ErrBinary = errors.New("binary file")
+
+// sniffLen is how much of a file is checked for NUL bytes, as git does.
+const sniffLen = 8000
+
+// IsBinary reports whether data, the start of a file, contains a NUL byte.
+func IsBinary(data []byte) bool {
+	if len(data) > sniffLen {
+		data = data[:sniffLen]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// ReadLines reads a text file line by line, returning ErrBinary when it
+// looks binary. Lines longer than 1 MB fail with bufio.ErrTooLong.
+func ReadLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReaderSize(f, sniffLen)
+	if head, _ := r.Peek(sniffLen); IsBinary(head) {
+		return nil, ErrBinary
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Minified thresholds: a line this long in a file whose lines average at
+// least minifiedAvgLine characters is generated, not written.
+const (
+	minifiedLongLine = 1000
+	minifiedAvgLine  = 200
+)
+
+// IsMinified reports whether a JavaScript or TypeScript file is minified:
+// named *.min.js, or made of a few very long lines.
+func IsMinified(path string, lines []string) bool {
+	if lower := strings.ToLower(path); strings.HasSuffix(lower, ".min.js") || strings.HasSuffix(lower, ".min.mjs") {
+		return true
+	}
+	if len(lines) == 0 {
+		return false
+	}
+	total, longest := 0, 0
+	for _, l := range lines {
+		total += len(l)
+		longest = max(longest, len(l))
+	}
+	return longest >= minifiedLongLine && total/len(lines) >= minifiedAvgLine
+}
+
+// IsEnvFile reports whether name is a dotenv file holding real settings:
+// .env, .env.production, production.env. Templates such as .env.example
+// are not.
+func IsEnvFile(name string) bool {
+	lower := strings.ToLower(name)
+	if lower != ".env" && !strings.HasPrefix(lower, ".env.") && !strings.HasSuffix(lower, ".env") {
+		return false
+	}
+	for _, t := range []string{"example", "sample", "template", "dist"} {
+		if strings.Contains(lower, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Counts tallies skipped files by reason.
+type Counts map[string]int
+
+// Total is the number of skipped files.
+func (c Counts) Total() int {
+	n := 0
+	for _, v := range c {
+		n += v
+	}
+	return n
+}
+
+// String lists the counts, e.g. "3 binary, 1 minified".
+func (c Counts) String() string {
+	reasons := make([]string, 0, len(c))
+	for reason := range c {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%d %s", c[reason], reason)
+	}
+	return strings.Join(parts, ", ")
+}
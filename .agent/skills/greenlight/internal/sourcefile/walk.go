@@ -0,0 +1,118 @@
+package sourcefile
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Walk is filepath.Walk for project scans, which also follows symbolic
+// links out of the project: pnpm and yarn workspaces link packages into
+// node_modules, and monorepos link app code in from sibling directories.
+// Links are reported at their own path, so a linked package's files appear
+// under the link. Links that resolve inside the project are not followed,
+// as the walk reaches their targets directly; each directory outside it is
+// walked once, which also stops link cycles.
+func Walk(root string, fn filepath.WalkFunc) error {
+	realRoot, err := realPath(root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	w := &walker{root: realRoot, fn: fn, visited: map[string]bool{}}
+	err = filepath.Walk(root, w.visit)
+	if w.stopped {
+		return nil
+	}
+	return err
+}
+
+type walker struct {
+	root    string
+	fn      filepath.WalkFunc
+	visited map[string]bool
+	// stopped is set when fn returns filepath.SkipAll from inside a linked
+	// directory, which only ends that directory's walk.
+	stopped bool
+}
+
+func (w *walker) visit(path string, info os.FileInfo, err error) error {
+	if w.stopped {
+		return filepath.SkipAll
+	}
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return w.call(path, info, err)
+	}
+	real, err := realPath(path)
+	if err != nil {
+		return nil // dangling link
+	}
+	if within(w.root, real) {
+		return nil
+	}
+	target, err := os.Stat(real)
+	if err != nil {
+		return nil
+	}
+	if !target.IsDir() {
+		return w.call(path, target, nil)
+	}
+	if w.visited[real] {
+		return nil
+	}
+	w.visited[real] = true
+	err = filepath.Walk(real, func(p string, info os.FileInfo, err error) error {
+		return w.visit(filepath.Join(path, strings.TrimPrefix(p, real)), info, err)
+	})
+	if w.stopped {
+		return filepath.SkipAll
+	}
+	return err
+}
+
+func (w *walker) call(path string, info os.FileInfo, err error) error {
+	err = w.fn(path, info, err)
+	if err == filepath.SkipAll {
+		w.stopped = true
+	}
+	return err
+}
+
+// realPath is the absolute path of path with every link resolved.
+func realPath(path string) (string, error) {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(real)
+}
+
+// within reports whether path is dir or inside it.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// MissingSubmodules lists the git submodules of the repository at root
+// (from .gitmodules) that aren't checked out, whose code a scan can't see.
+func MissingSubmodules(root string) []string {
+	f, err := os.Open(filepath.Join(root, ".gitmodules"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var missing []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(sc.Text()), "=")
+		if !ok || strings.TrimSpace(key) != "path" {
+			continue
+		}
+		path := strings.TrimSpace(value)
+		entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(path)))
+		if err != nil || len(entries) == 0 {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
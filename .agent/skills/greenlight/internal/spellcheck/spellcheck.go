@@ -0,0 +1,160 @@
+// Package spellcheck finds common misspellings, repeated words and spacing
+// slips in App Store copy. Misspellings come from short bundled lists of
+// frequent typos per language, not a dictionary: a word that isn't on a
+// list passes, however it is spelled.
+package spellcheck
+
+import (
+	"embed"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed data/*.txt
+var dictFS embed.FS
+
+// Issue is a likely typo or grammar slip in a piece of text.
+type Issue struct {
+	Word       string `json:"word"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Kind       string `json:"kind"` // "spelling", "repeated-word", "spacing"
+	Context    string `json:"context"`
+}
+
+var (
+	loadOnce sync.Once
+	dicts    map[string]map[string]string // language -> common misspelling -> correction
+
+	missingSpaceRe  = regexp.MustCompile(`\p{Ll}[.,;!?]\p{Lu}\p{Ll}`)
+	doubleSpaceRe   = regexp.MustCompile(`\S  +\S`)
+	spaceBeforePunc = regexp.MustCompile(`\p{L} +[,.;!?](\s|$)`)
+)
+
+// Check returns common misspellings, repeated words and spacing slips in
+// text for an App Store locale such as "en-US" or "de-DE". Locales without a
+// bundled misspelling list still get the language-independent checks.
+func Check(locale, text string) []Issue {
+	load()
+	var issues []Issue
+
+	if dict, ok := dicts[language(locale)]; ok {
+		for _, w := range words(text) {
+			if fix, ok := dict[strings.ToLower(w)]; ok {
+				issues = append(issues, Issue{Word: w, Suggestion: fix, Kind: "spelling", Context: excerpt(text, w)})
+			}
+		}
+	}
+
+	fields := strings.Fields(text)
+	for i := 1; i < len(fields); i++ {
+		prev, cur := fields[i-1], fields[i]
+		// A trailing punctuation mark ends the phrase ("Done. Done.").
+		last, _ := utf8.DecodeLastRuneInString(prev)
+		if strings.IndexFunc(prev, unicode.IsLetter) < 0 || !unicode.IsLetter(last) {
+			continue
+		}
+		word := strings.TrimRightFunc(cur, func(r rune) bool { return !unicode.IsLetter(r) })
+		if strings.EqualFold(prev, word) && !allowedRepeat(word) {
+			pair := prev + " " + word
+			issues = append(issues, Issue{Word: pair, Suggestion: prev, Kind: "repeated-word", Context: excerpt(text, pair)})
+		}
+	}
+
+	for _, m := range missingSpaceRe.FindAllString(text, -1) {
+		// Skip domains and abbreviations like "example.Com" or "e.G".
+		if strings.Contains(excerpt(text, m), "://") {
+			continue
+		}
+		issues = append(issues, Issue{Word: m, Kind: "spacing", Context: excerpt(text, m)})
+	}
+	for _, m := range doubleSpaceRe.FindAllString(text, -1) {
+		issues = append(issues, Issue{Word: m, Kind: "spacing", Context: excerpt(text, m)})
+	}
+	for _, m := range spaceBeforePunc.FindAllString(text, -1) {
+		if language(locale) == "fr" {
+			break // French typography puts a space before ! ? ;
+		}
+		issues = append(issues, Issue{Word: strings.TrimSpace(m), Kind: "spacing", Context: excerpt(text, m)})
+	}
+
+	return issues
+}
+
+func load() {
+	loadOnce.Do(func() {
+		dicts = make(map[string]map[string]string)
+		entries, err := dictFS.ReadDir("data")
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			data, err := dictFS.ReadFile("data/" + e.Name())
+			if err != nil {
+				continue
+			}
+			lang := strings.TrimSuffix(e.Name(), ".txt")
+			dict := make(map[string]string)
+			for _, line := range strings.Split(string(data), "\n") {
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+				parts := strings.SplitN(line, "\t", 2)
+				if len(parts) == 2 {
+					dict[strings.ToLower(parts[0])] = parts[1]
+				}
+			}
+			dicts[lang] = dict
+		}
+	})
+}
+
+// language maps an App Store locale ("en-US", "zh-Hans") to a dictionary name.
+func language(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	return lang
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+}
+
+// allowedRepeat lists words that are legitimately doubled ("that that", "had had").
+func allowedRepeat(w string) bool {
+	switch strings.ToLower(w) {
+	case "that", "had", "is", "bye", "very", "so", "no", "go", "ha", "la", "pom", "chop", "tut", "knock":
+		return true
+	}
+	return false
+}
+
+// excerpt returns a short snippet of text around the first occurrence of needle.
+func excerpt(text, needle string) string {
+	i := strings.Index(text, needle)
+	if i < 0 {
+		return ""
+	}
+	start, end := i-25, i+len(needle)+25
+	if start < 0 {
+		start = 0
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	// Keep slice boundaries on rune starts.
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	return fmt.Sprintf("…%s…", snippet)
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
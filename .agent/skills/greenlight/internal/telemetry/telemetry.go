@@ -0,0 +1,317 @@
+// Package telemetry sends opt-in, anonymized usage data: how often each
+// built-in rule fires, and (under a separate opt-in) App Review outcomes
+// users choose to contribute. Both feed the community data behind the
+// Tier 4 historical pattern checks.
+//
+// Nothing is sent unless the user turned it on with 'greenlight telemetry
+// enable'. Events never carry app names, bundle IDs, app IDs, file paths,
+// code or finding text — only built-in rule IDs, counts, guideline numbers,
+// the greenlight version and platform, the UTC day and a random install ID.
+// Events are queued in ~/.greenlight/telemetry and sent in batches.
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/config"
+)
+
+// DefaultEndpoint receives event batches; EndpointEnv overrides it.
+const DefaultEndpoint = "https://telemetry.revyl.com/greenlight/v1/events"
+
+const (
+	// EndpointEnv names the variable that overrides DefaultEndpoint.
+	EndpointEnv = "GREENLIGHT_TELEMETRY_URL"
+	// OffEnv turns telemetry off for a run when set to 0, off or false,
+	// whatever the config says; DO_NOT_TRACK=1 does the same.
+	OffEnv = "GREENLIGHT_TELEMETRY"
+)
+
+// FlushInterval is how often queued events are sent after a command.
+const FlushInterval = time.Hour
+
+// maxQueued bounds the queue; the oldest events are dropped beyond it.
+const maxQueued = 500
+
+// Event is one telemetry record.
+type Event struct {
+	Kind      string `json:"kind"` // "rule_hits" or "outcome"
+	InstallID string `json:"install_id"`
+	Version   string `json:"version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Day       string `json:"day"` // UTC, YYYY-MM-DD
+
+	// rule_hits
+	Command string         `json:"command,omitempty"`
+	Passed  *bool          `json:"passed,omitempty"`
+	Rules   map[string]int `json:"rules,omitempty"`
+
+	// outcome
+	Outcome    string   `json:"outcome,omitempty"` // "approved" or "rejected"
+	Guidelines []string `json:"guidelines,omitempty"`
+	RuleIDs    []string `json:"rule_ids,omitempty"`
+}
+
+// Settings returns the telemetry settings from the config; without a
+// config everything is off.
+func Settings() config.TelemetrySettings {
+	cfg, err := config.Load()
+	if err != nil || cfg.Telemetry == nil {
+		return config.TelemetrySettings{}
+	}
+	return *cfg.Telemetry
+}
+
+// OffByEnv returns why the environment turns telemetry off, or "".
+func OffByEnv() string {
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return "DO_NOT_TRACK is set"
+	}
+	switch strings.ToLower(os.Getenv(OffEnv)) {
+	case "0", "off", "false", "no":
+		return OffEnv + " is off"
+	}
+	return ""
+}
+
+// RuleStatsEnabled reports whether rule-hit statistics are collected.
+func RuleStatsEnabled() bool {
+	return OffByEnv() == "" && Settings().RuleStats
+}
+
+// OutcomesEnabled reports whether review outcomes may be sent.
+func OutcomesEnabled() bool {
+	return OffByEnv() == "" && Settings().Outcomes
+}
+
+// NewInstallID returns a random install ID.
+func NewInstallID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Endpoint is where batches are sent.
+func Endpoint() string {
+	if u := os.Getenv(EndpointEnv); u != "" {
+		return u
+	}
+	return DefaultEndpoint
+}
+
+// RuleHits builds a rule_hits event for one run. Only rule IDs for which
+// builtin returns true are counted, so custom rule names never leave the
+// machine.
+func RuleHits(command, version string, passed bool, ruleIDs []string, builtin func(id string) bool) Event {
+	e := newEvent("rule_hits", version)
+	e.Command = command
+	e.Passed = &passed
+	e.Rules = map[string]int{}
+	for _, id := range ruleIDs {
+		if builtin(id) {
+			e.Rules[id]++
+		}
+	}
+	return e
+}
+
+// Outcome builds an outcome event: the review result, the guidelines
+// cited, and the built-in rules the last scan hit.
+func Outcome(version, outcome string, guidelines, ruleIDs []string, builtin func(id string) bool) Event {
+	e := newEvent("outcome", version)
+	e.Outcome = outcome
+	e.Guidelines = guidelines
+	seen := map[string]bool{}
+	for _, id := range ruleIDs {
+		if builtin(id) && !seen[id] {
+			seen[id] = true
+			e.RuleIDs = append(e.RuleIDs, id)
+		}
+	}
+	sort.Strings(e.RuleIDs)
+	return e
+}
+
+func newEvent(kind, version string) Event {
+	return Event{
+		Kind:      kind,
+		InstallID: Settings().InstallID,
+		Version:   version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Day:       time.Now().UTC().Format("2006-01-02"),
+	}
+}
+
+func dir() (string, error) {
+	d, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, "telemetry"), nil
+}
+
+func queuePath() (string, error) {
+	d, err := dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, "queue.jsonl"), nil
+}
+
+// Enqueue adds e to the queue for the next flush.
+func Enqueue(e Event) error {
+	events, err := Queued()
+	if err != nil {
+		return err
+	}
+	events = append(events, e)
+	if len(events) > maxQueued {
+		events = events[len(events)-maxQueued:]
+	}
+	return writeQueue(events)
+}
+
+// Queued returns the events waiting to be sent.
+func Queued() ([]Event, error) {
+	path, err := queuePath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var events []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Event
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	return events, sc.Err()
+}
+
+func writeQueue(events []Event) error {
+	path, err := queuePath()
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, e := range events {
+		data, _ := json.Marshal(e)
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// Clear drops every queued event.
+func Clear() error {
+	return writeQueue(nil)
+}
+
+// FlushDue reports whether queued events should be sent now: there are
+// some, and the last attempt was more than FlushInterval ago.
+func FlushDue() bool {
+	events, err := Queued()
+	if err != nil || len(events) == 0 {
+		return false
+	}
+	d, err := dir()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(d, "last-flush"))
+	return err != nil || time.Since(info.ModTime()) > FlushInterval
+}
+
+// Flush sends the queued events in one batch and removes them from the
+// queue. It returns how many were sent.
+func Flush(ctx context.Context, client *http.Client) (int, error) {
+	events, err := Queued()
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+	if d, err := dir(); err == nil {
+		os.WriteFile(filepath.Join(d, "last-flush"), nil, 0600)
+	}
+
+	body, err := json.Marshal(struct {
+		Events []Event `json:"events"`
+	}{events})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, Endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "greenlight-telemetry")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+
+	// Keep anything queued while the batch was in flight. Enqueue may have
+	// dropped the oldest events meanwhile, so the sent ones are removed by
+	// content rather than by count; identical events are interchangeable.
+	current, err := Queued()
+	if err != nil {
+		return len(events), err
+	}
+	sent := make(map[string]int, len(events))
+	for _, e := range events {
+		sent[eventKey(e)]++
+	}
+	var remaining []Event
+	for _, e := range current {
+		if k := eventKey(e); sent[k] > 0 {
+			sent[k]--
+			continue
+		}
+		remaining = append(remaining, e)
+	}
+	return len(events), writeQueue(remaining)
+}
+
+// eventKey identifies an event by its encoding, as it is stored in the queue.
+func eventKey(e Event) string {
+	data, _ := json.Marshal(e)
+	return string(data)
+}
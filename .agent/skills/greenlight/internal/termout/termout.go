@@ -0,0 +1,160 @@
+// Package termout adapts terminal reports to where they are written: colors
+// only on terminals (following the NO_COLOR and CLICOLOR conventions),
+// text wrapped to the terminal's width, and an attribution footer that can
+// be turned off for CI logs.
+package termout
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+// minWidth is the narrowest width text is wrapped to; below it, wrapping
+// would leave a word or two per line.
+const minWidth = 40
+
+// ShowBanner controls whether Attribution writes anything (--no-banner).
+var ShowBanner = true
+
+// ColorEnabled reports whether output to f should be colored:
+//
+//   - never when NO_COLOR is set to any non-empty value, or TERM is "dumb";
+//   - always when CLICOLOR_FORCE is set to anything but "0";
+//   - never when CLICOLOR is "0";
+//   - otherwise only when f is a terminal, so piped and redirected output
+//     carries no escape codes.
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return false
+	}
+	return f != nil && term.IsTerminal(int(f.Fd()))
+}
+
+// Configure turns colored output on or off for writing to f. Commands call
+// it again when a report goes to a file instead of stdout.
+func Configure(f *os.File) {
+	color.NoColor = !ColorEnabled(f)
+}
+
+// Width returns the column count to wrap output to w at: the terminal's
+// width, or $COLUMNS when w isn't a terminal. It returns 0 (don't wrap)
+// when neither is known.
+func Width(w io.Writer) int {
+	width := 0
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		width, _, _ = term.GetSize(int(f.Fd()))
+	}
+	if width <= 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	if width <= 0 {
+		return 0
+	}
+	return max(width, minWidth)
+}
+
+// Wrap breaks text into lines of at most width columns, for printing at
+// column indent: continuation lines are indented by that many spaces. Words
+// longer than a line (URLs, paths) are kept whole; text in scripts written
+// without spaces (Japanese, Chinese) breaks between any two characters. A
+// width of 0 leaves text as it is.
+func Wrap(text string, width, indent int) string {
+	avail := width - indent
+	if width <= 0 || avail <= 0 || DisplayWidth(text) <= avail {
+		return text
+	}
+	pad := strings.Repeat(" ", indent)
+	var b strings.Builder
+	for i, para := range strings.Split(text, "\n") {
+		if i > 0 {
+			b.WriteString("\n" + pad)
+		}
+		n := 0
+		for _, word := range strings.Fields(para) {
+			wl := DisplayWidth(word)
+			switch {
+			case n == 0:
+			case n+1+wl > avail && wl == utf8.RuneCountInString(word):
+				b.WriteString("\n" + pad)
+				n = 0
+			default:
+				b.WriteByte(' ')
+				n++
+			}
+			if wl == utf8.RuneCountInString(word) {
+				b.WriteString(word)
+				n += wl
+				continue
+			}
+			for _, r := range word {
+				rw := runeWidth(r)
+				if n > 0 && n+rw > avail {
+					b.WriteString("\n" + pad)
+					n = 0
+				}
+				b.WriteRune(r)
+				n += rw
+			}
+		}
+	}
+	return b.String()
+}
+
+// DisplayWidth is the number of columns s takes in a terminal: East Asian
+// wide characters take two.
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F, // CJK, kana, Yi
+		r >= 0xAC00 && r <= 0xD7A3,                // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF,                // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F,                // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60,                // fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
+
+var (
+	purple = color.New(color.FgHiMagenta)
+	dim    = color.New(color.Faint)
+)
+
+// Attribution writes the "Built by Revyl" footer that closes terminal
+// reports, unless ShowBanner is off.
+func Attribution(w io.Writer) {
+	if !ShowBanner {
+		return
+	}
+	io.WriteString(w, "\n")
+	dim.Fprintln(w, "  ─────────────────────────────────────────────")
+	io.WriteString(w, "  Built by ")
+	purple.Fprint(w, "Revyl")
+	io.WriteString(w, " — the mobile reliability platform\n")
+	dim.Fprintln(w, "  Catch more than rejections. Catch bugs.")
+	io.WriteString(w, "  ")
+	color.New(color.Underline).Fprintln(w, "https://revyl.com")
+	io.WriteString(w, "\n")
+}
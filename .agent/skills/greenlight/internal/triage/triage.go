@@ -0,0 +1,135 @@
+// Package triage suggests how much work a finding takes to fix and which
+// team should own it, so large organizations can route findings from the
+// JSON report to the right queue without reading each one.
+package triage
+
+import "strings"
+
+// Effort is the estimated size of a fix.
+type Effort string
+
+const (
+	Quick  Effort = "quick"  // a config, string or metadata change
+	Medium Effort = "medium" // a contained code change
+	Large  Effort = "large"  // a new feature, redesign or architecture change
+)
+
+// Owner is the kind of team a finding belongs to.
+type Owner string
+
+const (
+	Code     Owner = "code"     // app engineers
+	Metadata Owner = "metadata" // whoever maintains the App Store listing
+	Design   Owner = "design"   // product and visual design
+	Legal    Owner = "legal"    // legal, compliance and business affairs
+)
+
+// Hint is the suggested effort and owner of a finding.
+type Hint struct {
+	Effort Effort `json:"effort"`
+	Owner  Owner  `json:"owner"`
+}
+
+// rules are the hints for codescan rules and privacy checks, by rule ID.
+var rules = map[string]Hint{
+	"private-api":              {Large, Code},
+	"hardcoded-secrets":        {Medium, Code},
+	"external-payment-digital": {Large, Code},
+	"external-purchase-link":   {Medium, Legal},
+	"crypto-mining":            {Medium, Code},
+	"crypto-features":          {Medium, Legal},
+	"dynamic-code-exec":        {Large, Code},
+	"ota-updates":              {Large, Code},
+	"missing-att":              {Medium, Code},
+	"att-timing":               {Quick, Code},
+	"social-login-no-apple":    {Medium, Code},
+	"iap-no-restore":           {Quick, Code},
+	"subscription-paywall":     {Quick, Design},
+	"health-data":              {Medium, Code},
+	"medical-disclaimer":       {Quick, Legal},
+	"media-rights":             {Medium, Legal},
+	"capability-mismatch":      {Quick, Code},
+	"apple-pay":                {Medium, Code},
+	"account-no-delete":        {Large, Code},
+	"gambling-mechanics":       {Medium, Legal},
+	"platform-reference":       {Quick, Code},
+	"placeholder-content":      {Quick, Code},
+	"console-log":              {Quick, Code},
+	"hardcoded-ipv4":           {Quick, Code},
+	"ipv4-reachability":        {Medium, Code},
+	"ipv4-socket-api":          {Large, Code},
+	"http-not-https":           {Quick, Code},
+	"webview-only":             {Large, Design},
+	"ugc-moderation":           {Medium, Design},
+	"launch-blocking":          {Medium, Code},
+	"vague-purpose-string":     {Quick, Code},
+	"export-compliance":        {Quick, Legal},
+	"missing-privacy-keys":     {Quick, Code},
+	"expo-config-check":        {Quick, Code},
+	"js-bundle":                {Medium, Code},
+
+	"privacy-manifest-missing":     {Quick, Code},
+	"tracking-without-att":         {Medium, Code},
+	"tracking-domain-undeclared":   {Quick, Code},
+	"tracking-declared-unused":     {Quick, Code},
+	"sdk-privacy-manifest-missing": {Quick, Code},
+	"sdk-signature-missing":        {Quick, Code},
+}
+
+// checks are the hints for App Store Connect checks, by check ID.
+var checks = map[string]Hint{
+	"screenshots-uploaded":        {Medium, Design},
+	"screenshot-dimensions":       {Medium, Design},
+	"app-previews":                {Medium, Design},
+	"build-processed":             {Quick, Code},
+	"build-freshness":             {Quick, Code},
+	"version-consistency":         {Quick, Code},
+	"app-id-capabilities":         {Quick, Code},
+	"age-rating-declared":         {Quick, Legal},
+	"encryption-compliance":       {Quick, Legal},
+	"territory-availability":      {Medium, Legal},
+	"regional-regulations":        {Medium, Legal},
+	"gambling-vs-age-rating":      {Quick, Legal},
+	"medical-disclaimer":          {Quick, Metadata},
+	"media-licensing":             {Medium, Legal},
+	"trademark-and-branding":      {Medium, Legal},
+	"localized-name-conflicts":    {Quick, Metadata},
+	"competitor-metadata":         {Quick, Metadata},
+	"subscription-disclosures":    {Quick, Metadata},
+	"testflight-external-testing": {Quick, Metadata},
+}
+
+// sources are the hints for findings no table names, by scanner.
+var sources = map[string]Hint{
+	"asc":       {Quick, Metadata},
+	"metadata":  {Quick, Metadata},
+	"xcode":     {Quick, Code},
+	"privacy":   {Quick, Code},
+	"ipa":       {Medium, Code},
+	"codescan":  {Medium, Code},
+	"vulns":     {Quick, Code},
+	"endpoints": {Medium, Code},
+}
+
+// For suggests the effort and owner of a finding: by its App Store Connect
+// check or rule ID, else by the scanner (source) that reported it.
+// Findings under the intellectual property, gaming, VPN and developer
+// identity guidelines (5.2–5.6) go to legal whatever reported them.
+func For(source, ruleID, check, guideline string) Hint {
+	h, ok := checks[check]
+	if !ok {
+		h, ok = rules[strings.TrimPrefix(ruleID, source+"/")]
+	}
+	if !ok {
+		h, ok = sources[source]
+	}
+	if !ok {
+		h = Hint{Medium, Code}
+	}
+	for _, s := range []string{"5.2", "5.3", "5.4", "5.5", "5.6"} {
+		if guideline == s || strings.HasPrefix(guideline, s+".") {
+			h.Owner = Legal
+		}
+	}
+	return h
+}
@@ -0,0 +1,204 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Finding is an issue in an Xcode project's build settings.
+type Finding struct {
+	Severity  severity.Level `json:"severity"`
+	Guideline string         `json:"guideline,omitempty"`
+	Title     string         `json:"title"`
+	Detail    string         `json:"detail"`
+	Fix       string         `json:"fix,omitempty"`
+	File      string         `json:"file,omitempty"`
+}
+
+var (
+	// Configurations that produce store builds.
+	releaseConfigPattern = regexp.MustCompile(`(?i)(release|prod|appstore|app store|distribution)`)
+
+	// Debug-only tooling that must not ship.
+	debugFrameworkPattern = regexp.MustCompile(`(?i)^(FLEX|Reveal|RevealServer|LookinServer|Lookin|FlipperKit|Flipper|FBRetainCycleDetector|InjectionIII|DoraemonKit|netfox|Wormholy|CocoaDebug|Atlantis|Pulse(UI)?|XCTest|XCTAutomationSupport|libXCTestSwiftSupport)(\.framework|\.xcframework|\.dylib|\.a)?$`)
+)
+
+// ReleaseConfigs returns the configuration names that look like store builds.
+func (p *Project) ReleaseConfigs() []string {
+	var names []string
+	for _, c := range p.Configs {
+		if releaseConfigPattern.MatchString(c.Name) && !strings.Contains(strings.ToLower(c.Name), "debug") {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// Check flags release-configuration problems in app targets: testability,
+// DEBUG conditions, missing or malformed version numbers, dSYM generation, development
+// code signing, embedded debug frameworks and bundled .env files. sel narrows the check to a scheme and/or
+// configuration; root is used to make file paths relative.
+func Check(p *Project, root string, sel Selection) ([]Finding, error) {
+	builds, err := p.Builds(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	rel, err := filepath.Rel(root, filepath.Join(p.Path, "project.pbxproj"))
+	if err != nil {
+		rel = filepath.Join(p.Path, "project.pbxproj")
+	}
+
+	add := func(sev severity.Level, guideline, title, detail, fix string) {
+		findings = append(findings, Finding{
+			Severity:  sev,
+			Guideline: guideline,
+			Title:     title,
+			Detail:    detail,
+			Fix:       fix,
+			File:      rel,
+		})
+	}
+
+	checkedFrameworks := map[string]bool{}
+	for _, b := range builds {
+		t, c, name := b.Target, b.Config, b.Config.Name
+
+		if !checkedFrameworks[t.Name] {
+			checkedFrameworks[t.Name] = true
+			for _, fw := range t.Frameworks {
+				if debugFrameworkPattern.MatchString(fw) {
+					add(severity.Critical, "2.5.1",
+						fmt.Sprintf("Debug framework %s linked into target '%s'", fw, t.Name),
+						fw+" is linked or embedded for every configuration, so it ships in the App Store build. Debugging and inspection tools use private APIs and expose internals.",
+						"Link it only in Debug (e.g. a Debug-only pod or an excluded source file setting), or remove it.")
+				}
+			}
+			for _, res := range t.Resources {
+				if sourcefile.IsEnvFile(res) {
+					add(severity.Warn, "1.6",
+						fmt.Sprintf("Environment file %s bundled into target '%s'", res, t.Name),
+						res+" is in the Copy Bundle Resources phase, so it ships inside the app where anyone can unzip it and read the API keys, secrets and internal endpoints it holds.",
+						"Remove it from Copy Bundle Resources (uncheck its target membership), read the values at build time instead (xcconfig, react-native-config), and rotate any secrets it contained.")
+				}
+			}
+		}
+
+		where := fmt.Sprintf("target '%s' (%s)", t.Name, name)
+
+		if v, _ := c.Setting("ENABLE_TESTABILITY"); v == "YES" {
+			add(severity.Warn, "2.1",
+				"ENABLE_TESTABILITY enabled for "+where,
+				"Testability exports internal symbols and disables optimizations that strip them, which is meant for Debug builds only.",
+				"Set ENABLE_TESTABILITY = NO for "+name+".")
+		}
+
+		if hasDebugCondition(c) {
+			add(severity.Warn, "2.1",
+				"DEBUG compilation condition set for "+where,
+				"DEBUG is defined in GCC_PREPROCESSOR_DEFINITIONS or SWIFT_ACTIVE_COMPILATION_CONDITIONS, so `#if DEBUG` code — debug menus, test endpoints, verbose logging — compiles into the store build.",
+				"Remove DEBUG from the "+name+" configuration's preprocessor definitions and compilation conditions.")
+		}
+
+		if _, ok := c.Setting("MARKETING_VERSION"); !ok && needsMarketingVersion(c) {
+			add(severity.Warn, "2.1",
+				"MARKETING_VERSION missing for "+where,
+				"The Info.plist takes its version from $(MARKETING_VERSION), but the setting is not defined, so CFBundleShortVersionString ends up empty and the upload is rejected.",
+				"Set the version in the target's General tab (MARKETING_VERSION).")
+		}
+
+		for _, key := range []string{"MARKETING_VERSION", "CURRENT_PROJECT_VERSION"} {
+			v, _ := c.Resolve(key)
+			if v == "" || strings.Contains(v, "$") {
+				continue
+			}
+			if problem := appversion.Validate(v); problem != "" {
+				add(severity.Critical, "2.1",
+					fmt.Sprintf("%s \"%s\" will be rejected at upload (%s)", key, v, where),
+					fmt.Sprintf("App Store Connect only accepts up to three period-separated integers (e.g. 1.4.2) as the version and build number; %s.", problem),
+					"Set "+key+" to a value like 1.4.2 for "+name+".")
+			}
+		}
+
+		if format, ok := c.Setting("DEBUG_INFORMATION_FORMAT"); ok && format != "dwarf-with-dsym" {
+			add(severity.Warn, "2.1",
+				"No dSYM generated for "+where,
+				"DEBUG_INFORMATION_FORMAT is \""+format+"\", so the archive has no dSYM and crash logs from App Review cannot be symbolicated — which makes 2.1 crash rejections hard to diagnose and answer.",
+				"Set DEBUG_INFORMATION_FORMAT = dwarf-with-dsym for "+name+".")
+		}
+
+		style, _ := c.Resolve("CODE_SIGN_STYLE")
+		identity, _ := c.Resolve("CODE_SIGN_IDENTITY[sdk=iphoneos*]")
+		if identity == "" {
+			identity, _ = c.Resolve("CODE_SIGN_IDENTITY")
+		}
+		profile, _ := c.Resolve("PROVISIONING_PROFILE_SPECIFIER")
+		switch {
+		case style == "Manual" && profile == "":
+			add(severity.Warn, "2.1",
+				"Manual signing without a provisioning profile for "+where,
+				"CODE_SIGN_STYLE is Manual but PROVISIONING_PROFILE_SPECIFIER is empty, so archiving fails or picks the wrong profile.",
+				"Use Automatic signing, or set an App Store provisioning profile for "+name+".")
+		case style == "Manual" && isDevelopmentIdentity(identity):
+			add(severity.Warn, "2.1",
+				"Development signing identity for "+where,
+				"Manual signing uses \""+identity+"\", a development certificate. App Store builds must be signed with a distribution certificate.",
+				"Set CODE_SIGN_IDENTITY to \"Apple Distribution\" (or use Automatic signing).")
+		}
+	}
+	return findings, nil
+}
+
+func hasDebugCondition(c Configuration) bool {
+	for _, d := range c.SettingList("GCC_PREPROCESSOR_DEFINITIONS") {
+		if d == "DEBUG" || strings.HasPrefix(d, "DEBUG=") && d != "DEBUG=0" {
+			return true
+		}
+	}
+	for _, d := range c.SettingList("SWIFT_ACTIVE_COMPILATION_CONDITIONS") {
+		if d == "DEBUG" {
+			return true
+		}
+	}
+	return false
+}
+
+func isDevelopmentIdentity(identity string) bool {
+	return strings.HasPrefix(identity, "iPhone Developer") || strings.HasPrefix(identity, "Apple Development")
+}
+
+// needsMarketingVersion reports whether the version comes from the
+// MARKETING_VERSION setting: generated Info.plists always do, and custom
+// ones do when they reference it (or cannot be read).
+func needsMarketingVersion(c Configuration) bool {
+	if v, _ := c.Setting("GENERATE_INFOPLIST_FILE"); v == "YES" {
+		return true
+	}
+	data, err := os.ReadFile(c.InfoPlistPath())
+	if err != nil {
+		return true
+	}
+	return strings.Contains(string(data), "MARKETING_VERSION")
+}
+
+// InfoPlistPath returns the absolute path of the configuration's
+// INFOPLIST_FILE ("" if none is set).
+func (c Configuration) InfoPlistPath() string {
+	plist, _ := c.Resolve("INFOPLIST_FILE")
+	if plist == "" {
+		return ""
+	}
+	if !filepath.IsAbs(plist) {
+		srcRoot, _ := c.Setting("SRCROOT")
+		plist = filepath.Join(srcRoot, plist)
+	}
+	return plist
+}
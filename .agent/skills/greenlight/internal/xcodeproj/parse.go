@@ -0,0 +1,208 @@
+// Package xcodeproj reads Xcode project files (project.pbxproj) and checks
+// the build settings that ship to the App Store.
+package xcodeproj
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parsePlist parses an old-style (OpenStep) ASCII property list, the format
+// of project.pbxproj. Dictionaries become map[string]interface{}, arrays
+// []interface{}, and every scalar a string.
+func parsePlist(src string) (interface{}, error) {
+	p := &plistParser{src: src}
+	p.skip()
+	if strings.HasPrefix(p.src[p.pos:], "// !$*UTF8*$!") {
+		p.pos += len("// !$*UTF8*$!")
+	}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type plistParser struct {
+	src string
+	pos int
+}
+
+func (p *plistParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("pbxproj line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skip advances past whitespace and comments.
+func (p *plistParser) skip() {
+	for p.pos < len(p.src) {
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end + 4
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+				return
+			}
+			p.pos += end
+		case strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *plistParser) expect(c byte) error {
+	p.skip()
+	if p.pos >= len(p.src) || p.src[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *plistParser) value() (interface{}, error) {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of file")
+	}
+	switch p.src[p.pos] {
+	case '{':
+		return p.dict()
+	case '(':
+		return p.array()
+	case '"':
+		return p.quoted()
+	case '<':
+		end := strings.IndexByte(p.src[p.pos:], '>')
+		if end < 0 {
+			return nil, p.errorf("unterminated data")
+		}
+		s := p.src[p.pos : p.pos+end+1]
+		p.pos += end + 1
+		return s, nil
+	}
+	return p.unquoted()
+}
+
+func (p *plistParser) dict() (map[string]interface{}, error) {
+	p.pos++ // {
+	d := make(map[string]interface{})
+	for {
+		p.skip()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated dictionary")
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			return d, nil
+		}
+		k, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, p.errorf("dictionary key is not a string")
+		}
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(';'); err != nil {
+			return nil, err
+		}
+		d[key] = v
+	}
+}
+
+func (p *plistParser) array() ([]interface{}, error) {
+	p.pos++ // (
+	var a []interface{}
+	for {
+		p.skip()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.src[p.pos] == ')' {
+			p.pos++
+			return a, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+		p.skip()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *plistParser) quoted() (string, error) {
+	p.pos++ // opening quote
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'U':
+				if p.pos+4 < len(p.src) {
+					if r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						p.pos += 4
+						break
+					}
+				}
+				b.WriteByte(e)
+			default:
+				b.WriteByte(e)
+			}
+			p.pos++
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *plistParser) unquoted() (string, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isUnquotedChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("unexpected %q", p.src[p.pos])
+	}
+	return p.src[start:p.pos], nil
+}
+
+func isUnquotedChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		strings.IndexByte("_$/:.-+", c) >= 0
+}
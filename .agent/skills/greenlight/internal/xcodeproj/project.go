@@ -0,0 +1,369 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// Project is the part of an Xcode project greenlight checks.
+type Project struct {
+	// Path is the .xcodeproj directory.
+	Path    string
+	Configs []Configuration // project-level configurations
+	Targets []Target
+}
+
+// Target is a native target with its build configurations.
+type Target struct {
+	Name        string
+	ProductType string // e.g. com.apple.product-type.application
+	Configs     []Configuration
+	// Frameworks are the framework and library names linked or embedded by
+	// the target's build phases.
+	Frameworks []string
+	// Resources are the file names copied by the target's Copy Bundle
+	// Resources phase.
+	Resources []string
+}
+
+// Configuration is one build configuration (Debug, Release, ...). Settings
+// values are strings or []string.
+type Configuration struct {
+	Name     string
+	Settings map[string]interface{}
+	// BaseConfig is the xcconfig file the configuration is based on, if any.
+	BaseConfig string
+}
+
+// IsApp reports whether the target builds an app or app extension.
+func (t Target) IsApp() bool {
+	return strings.HasPrefix(t.ProductType, "com.apple.product-type.application") ||
+		strings.HasPrefix(t.ProductType, "com.apple.product-type.app-extension")
+}
+
+// Config returns the target's configuration with the given name.
+func (t Target) Config(name string) (Configuration, bool) {
+	for _, c := range t.Configs {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Configuration{}, false
+}
+
+// Setting returns a setting as a single string (lists are space-joined).
+func (c Configuration) Setting(key string) (string, bool) {
+	v, ok := c.Settings[key]
+	if !ok {
+		return "", false
+	}
+	if list, ok := v.([]interface{}); ok {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " "), true
+	}
+	s, _ := v.(string)
+	return s, true
+}
+
+// SettingList returns a setting split into its items.
+func (c Configuration) SettingList(key string) []string {
+	s, _ := c.Setting(key)
+	return strings.Fields(s)
+}
+
+// Effective returns the target's settings for a configuration name, layered
+// the way Xcode does: project xcconfig, project settings, target xcconfig,
+// target settings. $(inherited) picks up the value from the layer below, and
+// TARGET_NAME, PRODUCT_NAME, CONFIGURATION, SRCROOT and friends are defined.
+func (p *Project) Effective(t Target, name string) Configuration {
+	merged := Configuration{Name: name, Settings: map[string]interface{}{
+		"TARGET_NAME":   t.Name,
+		"PRODUCT_NAME":  "$(TARGET_NAME)",
+		"PROJECT_NAME":  strings.TrimSuffix(filepath.Base(p.Path), ".xcodeproj"),
+		"CONFIGURATION": name,
+		"SRCROOT":       filepath.Dir(p.Path),
+		"PROJECT_DIR":   filepath.Dir(p.Path),
+	}}
+
+	var layers []Configuration
+	for _, c := range p.Configs {
+		if c.Name == name {
+			layers = append(layers, c)
+		}
+	}
+	if c, ok := t.Config(name); ok {
+		layers = append(layers, c)
+	}
+	for _, c := range layers {
+		if c.BaseConfig != "" {
+			if xc, err := loadXCConfig(c.BaseConfig, 0); err == nil {
+				merged.overlay(xc)
+			}
+		}
+		merged.overlay(c.Settings)
+	}
+	merged.Settings["EXECUTABLE_NAME"] = "$(PRODUCT_NAME)"
+	merged.Settings["PRODUCT_MODULE_NAME"] = "$(PRODUCT_NAME:c99extidentifier)"
+	return merged
+}
+
+// overlay applies a layer of settings, resolving $(inherited) against the
+// current value.
+func (c *Configuration) overlay(settings map[string]interface{}) {
+	for k, v := range settings {
+		if s, ok := v.(string); ok && strings.Contains(s, "$(inherited)") {
+			prev, _ := c.Setting(k)
+			v = strings.TrimSpace(strings.ReplaceAll(s, "$(inherited)", prev))
+		} else if list, ok := v.([]interface{}); ok {
+			var out []interface{}
+			for _, item := range list {
+				if item == "$(inherited)" {
+					for _, prev := range c.SettingList(k) {
+						out = append(out, prev)
+					}
+					continue
+				}
+				out = append(out, item)
+			}
+			v = out
+		}
+		c.Settings[k] = v
+	}
+}
+
+// Load parses the project.pbxproj inside an .xcodeproj directory (or the
+// pbxproj file itself).
+func Load(path string) (*Project, error) {
+	pbxPath := path
+	if filepath.Base(path) != "project.pbxproj" {
+		pbxPath = filepath.Join(path, "project.pbxproj")
+	}
+	data, err := os.ReadFile(pbxPath)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parsePlist(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	top, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: not a project file", pbxPath)
+	}
+	objects, _ := top["objects"].(map[string]interface{})
+	rootID, _ := top["rootObject"].(string)
+	rootObj := object(objects, rootID)
+	if rootObj == nil {
+		return nil, fmt.Errorf("%s: missing root object", pbxPath)
+	}
+
+	proj := &Project{Path: filepath.Dir(pbxPath)}
+	proj.Configs = proj.configList(objects, str(rootObj, "buildConfigurationList"))
+	for _, id := range strs(rootObj, "targets") {
+		t := object(objects, id)
+		if t == nil || str(t, "isa") != "PBXNativeTarget" {
+			continue
+		}
+		proj.Targets = append(proj.Targets, Target{
+			Name:        str(t, "name"),
+			ProductType: str(t, "productType"),
+			Configs:     proj.configList(objects, str(t, "buildConfigurationList")),
+			Frameworks:  phaseFrameworks(objects, strs(t, "buildPhases")),
+			Resources:   phaseFiles(objects, strs(t, "buildPhases"), "PBXResourcesBuildPhase"),
+		})
+	}
+	return proj, nil
+}
+
+// Find returns the .xcodeproj directories under root, skipping Pods and
+// dependency folders.
+func Find(root string) []string {
+	var found []string
+	sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() {
+			return nil
+		}
+		switch info.Name() {
+		case "Pods", "node_modules", ".git", "build", "DerivedData", "Carthage", ".build":
+			return filepath.SkipDir
+		}
+		if strings.HasSuffix(info.Name(), ".xcodeproj") {
+			if _, err := os.Stat(filepath.Join(path, "project.pbxproj")); err == nil {
+				found = append(found, path)
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	sort.Strings(found)
+	return found
+}
+
+func (p *Project) configList(objects map[string]interface{}, id string) []Configuration {
+	list := object(objects, id)
+	if list == nil {
+		return nil
+	}
+	var configs []Configuration
+	for _, cid := range strs(list, "buildConfigurations") {
+		c := object(objects, cid)
+		if c == nil {
+			continue
+		}
+		settings, _ := c["buildSettings"].(map[string]interface{})
+		configs = append(configs, Configuration{
+			Name:       str(c, "name"),
+			Settings:   settings,
+			BaseConfig: p.filePath(object(objects, str(c, "baseConfigurationReference"))),
+		})
+	}
+	return configs
+}
+
+// phaseFrameworks lists frameworks from the link and copy-files (embed)
+// build phases.
+func phaseFrameworks(objects map[string]interface{}, phaseIDs []string) []string {
+	return phaseFiles(objects, phaseIDs, "PBXFrameworksBuildPhase", "PBXCopyFilesBuildPhase")
+}
+
+// phaseFiles lists the names of the files in the build phases of the given
+// kinds (isa), each once.
+func phaseFiles(objects map[string]interface{}, phaseIDs []string, kinds ...string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, pid := range phaseIDs {
+		phase := object(objects, pid)
+		if phase == nil || !slices.Contains(kinds, str(phase, "isa")) {
+			continue
+		}
+		for _, bid := range strs(phase, "files") {
+			ref := object(objects, str(object(objects, bid), "fileRef"))
+			if ref == nil {
+				continue
+			}
+			name := str(ref, "name")
+			if name == "" {
+				name = filepath.Base(str(ref, "path"))
+			}
+			if name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// filePath locates a PBXFileReference on disk. Group-relative paths are
+// matched by suffix under the project's directory rather than by walking the
+// group tree.
+func (p *Project) filePath(ref map[string]interface{}) string {
+	path := str(ref, "path")
+	if path == "" {
+		return ""
+	}
+	srcRoot := filepath.Dir(p.Path)
+	switch {
+	case filepath.IsAbs(path):
+		return path
+	case str(ref, "sourceTree") == "SOURCE_ROOT":
+		return filepath.Join(srcRoot, path)
+	}
+	if _, err := os.Stat(filepath.Join(srcRoot, path)); err == nil {
+		return filepath.Join(srcRoot, path)
+	}
+	var found string
+	suffix := string(filepath.Separator) + filepath.FromSlash(path)
+	sourcefile.Walk(srcRoot, func(walked string, info os.FileInfo, err error) error {
+		if err != nil || found != "" {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			switch info.Name() {
+			case "Pods", "node_modules", ".git", "build", "DerivedData":
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(walked, suffix) {
+			found = walked
+		}
+		return nil
+	})
+	return found
+}
+
+func object(objects map[string]interface{}, id string) map[string]interface{} {
+	if objects == nil || id == "" {
+		return nil
+	}
+	o, _ := objects[id].(map[string]interface{})
+	return o
+}
+
+func str(o map[string]interface{}, key string) string {
+	if o == nil {
+		return ""
+	}
+	s, _ := o[key].(string)
+	return s
+}
+
+func strs(o map[string]interface{}, key string) []string {
+	if o == nil {
+		return nil
+	}
+	list, _ := o[key].([]interface{})
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// DeviceFamilies reports which iOS devices the project's apps run on, from
+// TARGETED_DEVICE_FAMILY ("1" iPhone, "2" iPad) of their Release-like
+// builds. ok is false when the project has no iOS app target. A target
+// without the setting builds for iPhone, as Xcode does.
+func (p *Project) DeviceFamilies() (iPhone, iPad, ok bool) {
+	builds, err := p.Builds(Selection{})
+	if err != nil {
+		return false, false, false
+	}
+	for _, b := range builds {
+		if b.Target.ProductType != "com.apple.product-type.application" {
+			continue
+		}
+		if sdk, _ := b.Config.Resolve("SDKROOT"); sdk != "" && sdk != "iphoneos" {
+			continue
+		}
+		ok = true
+		families, _ := b.Config.Resolve("TARGETED_DEVICE_FAMILY")
+		if strings.TrimSpace(families) == "" {
+			families = "1"
+		}
+		for _, f := range strings.Split(families, ",") {
+			switch strings.TrimSpace(f) {
+			case "1":
+				iPhone = true
+			case "2":
+				iPad = true
+			}
+		}
+	}
+	return iPhone, iPad, ok
+}
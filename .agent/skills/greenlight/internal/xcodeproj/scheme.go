@@ -0,0 +1,140 @@
+package xcodeproj
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Selection is the scheme and configuration the user asked to check. Empty
+// fields mean "infer": the scheme's archive configuration, or Release-like
+// configurations of every app target.
+type Selection struct {
+	Scheme        string
+	Configuration string
+}
+
+// Scheme is the part of an .xcscheme greenlight needs.
+type Scheme struct {
+	Name string
+	// ArchiveConfiguration is the configuration Product > Archive builds.
+	ArchiveConfiguration string
+	// Targets are the blueprint names built for archiving.
+	Targets []string
+}
+
+type xcscheme struct {
+	BuildAction struct {
+		Entries []struct {
+			BuildForArchiving string `xml:"buildForArchiving,attr"`
+			Reference         struct {
+				BlueprintName string `xml:"BlueprintName,attr"`
+			} `xml:"BuildableReference"`
+		} `xml:"BuildActionEntries>BuildActionEntry"`
+	} `xml:"BuildAction"`
+	ArchiveAction struct {
+		BuildConfiguration string `xml:"buildConfiguration,attr"`
+	} `xml:"ArchiveAction"`
+}
+
+// SchemeNames lists the shared and user schemes in the project.
+func (p *Project) SchemeNames() []string {
+	var names []string
+	for _, path := range p.schemePaths() {
+		names = append(names, strings.TrimSuffix(filepath.Base(path), ".xcscheme"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *Project) schemePaths() []string {
+	shared, _ := filepath.Glob(filepath.Join(p.Path, "xcshareddata", "xcschemes", "*.xcscheme"))
+	user, _ := filepath.Glob(filepath.Join(p.Path, "xcuserdata", "*.xcuserdatad", "xcschemes", "*.xcscheme"))
+	return append(shared, user...)
+}
+
+// LoadScheme reads the named scheme from the project.
+func (p *Project) LoadScheme(name string) (*Scheme, error) {
+	for _, path := range p.schemePaths() {
+		if strings.TrimSuffix(filepath.Base(path), ".xcscheme") != name {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var x xcscheme
+		if err := xml.Unmarshal(data, &x); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		s := &Scheme{Name: name, ArchiveConfiguration: x.ArchiveAction.BuildConfiguration}
+		if s.ArchiveConfiguration == "" {
+			s.ArchiveConfiguration = "Release"
+		}
+		for _, e := range x.BuildAction.Entries {
+			if e.BuildForArchiving != "NO" && e.Reference.BlueprintName != "" {
+				s.Targets = append(s.Targets, e.Reference.BlueprintName)
+			}
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("scheme '%s' not found in %s (available: %s)", name, filepath.Base(p.Path), strings.Join(p.SchemeNames(), ", "))
+}
+
+// Build is a resolved target and configuration to check.
+type Build struct {
+	Target Target
+	Config Configuration // effective settings
+}
+
+// Builds returns the app target/configuration pairs a selection covers.
+func (p *Project) Builds(sel Selection) ([]Build, error) {
+	targets := map[string]bool{}
+	configs := []string{sel.Configuration}
+
+	if sel.Scheme != "" {
+		scheme, err := p.LoadScheme(sel.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range scheme.Targets {
+			targets[t] = true
+		}
+		if sel.Configuration == "" {
+			configs = []string{scheme.ArchiveConfiguration}
+		}
+	} else if sel.Configuration == "" {
+		configs = p.ReleaseConfigs()
+	}
+
+	if sel.Configuration != "" && !p.hasConfig(sel.Configuration) {
+		var names []string
+		for _, c := range p.Configs {
+			names = append(names, c.Name)
+		}
+		return nil, fmt.Errorf("configuration '%s' not found in %s (available: %s)", sel.Configuration, filepath.Base(p.Path), strings.Join(names, ", "))
+	}
+
+	var builds []Build
+	for _, t := range p.Targets {
+		if !t.IsApp() || (len(targets) > 0 && !targets[t.Name]) {
+			continue
+		}
+		for _, name := range configs {
+			builds = append(builds, Build{Target: t, Config: p.Effective(t, name)})
+		}
+	}
+	return builds, nil
+}
+
+func (p *Project) hasConfig(name string) bool {
+	for _, c := range p.Configs {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
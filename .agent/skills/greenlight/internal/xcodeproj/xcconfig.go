@@ -0,0 +1,138 @@
+package xcodeproj
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	xcconfigInclude = regexp.MustCompile(`^#include\??\s+"([^"]+)"`)
+	xcconfigSetting = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*(?:\[[^\]]*\])*)\s*=\s*(.*?)\s*;?\s*$`)
+	settingRef      = regexp.MustCompile(`\$[({]([A-Za-z_][A-Za-z0-9_]*)(?::([A-Za-z0-9_,=]+))?[)}]`)
+)
+
+// loadXCConfig reads an .xcconfig file and its #includes into a settings map.
+// Later assignments win; $(inherited) refers to the value defined so far.
+func loadXCConfig(path string, depth int) (map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	settings := map[string]interface{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 && !strings.Contains(line[:i], "://") {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		if m := xcconfigInclude.FindStringSubmatch(line); m != nil {
+			if depth >= 8 {
+				continue
+			}
+			inc := m[1]
+			if !filepath.IsAbs(inc) {
+				inc = filepath.Join(filepath.Dir(path), inc)
+			}
+			if included, err := loadXCConfig(inc, depth+1); err == nil {
+				for k, v := range included {
+					settings[k] = v
+				}
+			}
+			continue
+		}
+
+		m := xcconfigSetting.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := m[2]
+		if prev, ok := settings[m[1]].(string); ok {
+			value = strings.ReplaceAll(value, "$(inherited)", prev)
+		}
+		settings[m[1]] = value
+	}
+	return settings, scanner.Err()
+}
+
+// Expand substitutes $(VAR) and ${VAR} references, including the
+// :rfc1034identifier, :c99extidentifier, :lower and :upper modifiers. It
+// returns the names of settings that were referenced but not defined.
+func (c Configuration) Expand(s string) (string, []string) {
+	var missing []string
+	return strings.TrimSpace(c.expand(s, 0, &missing)), missing
+}
+
+func (c Configuration) expand(s string, depth int, missing *[]string) string {
+	if depth >= 10 {
+		return s
+	}
+	return settingRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := settingRef.FindStringSubmatch(ref)
+		name, modifiers := m[1], m[2]
+		v, ok := c.Setting(name)
+		if !ok {
+			// Platform-specific variants such as KEY[sdk=iphoneos*].
+			for key := range c.Settings {
+				if strings.HasPrefix(key, name+"[") && strings.Contains(key, "iphoneos") {
+					v, ok = c.Setting(key)
+					break
+				}
+			}
+		}
+		if !ok {
+			*missing = append(*missing, name)
+			return ""
+		}
+		// Modifiers apply to the fully expanded value.
+		v = c.expand(v, depth+1, missing)
+		for _, mod := range strings.Split(modifiers, ",") {
+			v = applyModifier(v, mod)
+		}
+		return v
+	})
+}
+
+// Resolve returns a setting with every reference expanded.
+func (c Configuration) Resolve(key string) (string, []string) {
+	v, ok := c.Setting(key)
+	if !ok {
+		return "", []string{key}
+	}
+	return c.Expand(v)
+}
+
+func applyModifier(v, mod string) string {
+	switch mod {
+	case "lower":
+		return strings.ToLower(v)
+	case "upper":
+		return strings.ToUpper(v)
+	case "rfc1034identifier":
+		return replaceInvalid(v, func(r rune) bool {
+			return r == '-' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+		}, '-')
+	case "c99extidentifier":
+		return replaceInvalid(v, func(r rune) bool {
+			return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+		}, '_')
+	}
+	return v
+}
+
+func replaceInvalid(s string, valid func(rune) bool, with rune) string {
+	return strings.Map(func(r rune) rune {
+		if valid(r) {
+			return r
+		}
+		return with
+	}, s)
+}
@@ -0,0 +1,431 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// App represents an App Store Connect app.
+type App struct {
+	ID         string        `json:"id"`
+	Attributes AppAttributes `json:"attributes"`
+}
+
+type AppAttributes struct {
+	Name             string `json:"name"`
+	BundleID         string `json:"bundleId"`
+	SKU              string `json:"sku"`
+	PrimaryLocale    string `json:"primaryLocale"`
+	ContentRightsDeclaration string `json:"contentRightsDeclaration"`
+}
+
+// AppInfo contains app information details.
+type AppInfo struct {
+	ID         string            `json:"id"`
+	Attributes AppInfoAttributes `json:"attributes"`
+}
+
+type AppInfoAttributes struct {
+	AppStoreState    string `json:"appStoreState"`
+	AppStoreAgeRating string `json:"appStoreAgeRating"`
+	BrazilAgeRating  string `json:"brazilAgeRating"`
+	KidsAgeBand      string `json:"kidsAgeBand"`
+}
+
+// AppInfoLocalization contains localized app-level info (name, subtitle).
+type AppInfoLocalization struct {
+	ID         string                        `json:"id"`
+	Attributes AppInfoLocalizationAttributes `json:"attributes"`
+}
+
+type AppInfoLocalizationAttributes struct {
+	Locale           string `json:"locale"`
+	Name             string `json:"name"`
+	Subtitle         string `json:"subtitle"`
+	PrivacyPolicyURL string `json:"privacyPolicyUrl"`
+}
+
+// AppStoreVersion represents a version of an app.
+type AppStoreVersion struct {
+	ID         string                    `json:"id"`
+	Attributes AppStoreVersionAttributes `json:"attributes"`
+}
+
+type AppStoreVersionAttributes struct {
+	VersionString string `json:"versionString"`
+	AppStoreState string `json:"appStoreState"`
+	Platform      string `json:"platform"`
+	ReleaseType   string `json:"releaseType"`
+	CreatedDate   string `json:"createdDate"`
+}
+
+// VersionLocalization contains localized version info.
+type VersionLocalization struct {
+	ID         string                        `json:"id"`
+	Attributes VersionLocalizationAttributes `json:"attributes"`
+}
+
+type VersionLocalizationAttributes struct {
+	Locale       string `json:"locale"`
+	Description  string `json:"description"`
+	Keywords     string `json:"keywords"`
+	WhatsNew     string `json:"whatsNew"`
+	SupportURL   string `json:"supportUrl"`
+	MarketingURL string `json:"marketingUrl"`
+	PromotionalText string `json:"promotionalText"`
+}
+
+// Build represents a build uploaded to App Store Connect.
+type Build struct {
+	ID         string          `json:"id"`
+	Attributes BuildAttributes `json:"attributes"`
+}
+
+type BuildAttributes struct {
+	Version              string `json:"version"`
+	UploadedDate         string `json:"uploadedDate"`
+	ProcessingState      string `json:"processingState"`
+	MinOsVersion         string `json:"minOsVersion"`
+	UsesNonExemptEncryption *bool `json:"usesNonExemptEncryption"`
+	ExpirationDate       string `json:"expirationDate"`
+	Expired              bool   `json:"expired"`
+}
+
+// ScreenshotSet represents a set of screenshots for a device type.
+type ScreenshotSet struct {
+	ID         string                  `json:"id"`
+	Attributes ScreenshotSetAttributes `json:"attributes"`
+}
+
+type ScreenshotSetAttributes struct {
+	ScreenshotDisplayType string `json:"screenshotDisplayType"`
+}
+
+// Generic API response wrappers.
+type DataResponse[T any] struct {
+	Data T `json:"data"`
+}
+
+type ListResponse[T any] struct {
+	Data []T `json:"data"`
+}
+
+// FindApp looks up apps by App Store Connect ID, bundle ID or name. A bundle
+// ID must match exactly; a name matches exactly (ignoring case) or, when no
+// app has that exact name, as a substring. Several matches are returned
+// for the caller to choose from.
+func (c *Client) FindApp(ctx context.Context, query string) ([]App, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("empty app query")
+	}
+	if isNumeric(query) {
+		app, err := c.GetApp(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return []App{*app}, nil
+	}
+
+	if strings.Contains(query, ".") && !strings.ContainsAny(query, " \t") {
+		var resp ListResponse[App]
+		if err := c.get(ctx, "/apps?filter[bundleId]="+url.QueryEscape(query), &resp); err != nil {
+			return nil, err
+		}
+		var apps []App
+		for _, app := range resp.Data {
+			if app.Attributes.BundleID == query {
+				apps = append(apps, app)
+			}
+		}
+		if len(apps) > 0 {
+			return apps, nil
+		}
+	}
+
+	all, err := c.ListApps(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var exact, partial []App
+	lower := strings.ToLower(query)
+	for _, app := range all {
+		name := strings.ToLower(app.Attributes.Name)
+		switch {
+		case name == lower:
+			exact = append(exact, app)
+		case strings.Contains(name, lower):
+			partial = append(partial, app)
+		}
+	}
+	if len(exact) > 0 {
+		return exact, nil
+	}
+	return partial, nil
+}
+
+// ListApps returns the apps on the account (up to 200), by name.
+func (c *Client) ListApps(ctx context.Context) ([]App, error) {
+	var resp ListResponse[App]
+	if err := c.get(ctx, "/apps?limit=200&sort=name&fields[apps]=name,bundleId,sku,primaryLocale", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// GetApp fetches an app by its App Store Connect ID.
+func (c *Client) GetApp(ctx context.Context, appID string) (*App, error) {
+	var resp DataResponse[App]
+	if err := c.get(ctx, fmt.Sprintf("/apps/%s", appID), &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+// GetAppInfos fetches app info (age rating, state, etc).
+func (c *Client) GetAppInfos(ctx context.Context, appID string) ([]AppInfo, error) {
+	var resp ListResponse[AppInfo]
+	if err := c.get(ctx, fmt.Sprintf("/apps/%s/appInfos", appID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetAppInfoLocalizations fetches localized name/subtitle info for an app info record.
+func (c *Client) GetAppInfoLocalizations(ctx context.Context, appInfoID string) ([]AppInfoLocalization, error) {
+	var resp ListResponse[AppInfoLocalization]
+	if err := c.get(ctx, fmt.Sprintf("/appInfos/%s/appInfoLocalizations", appInfoID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// AppCategory is an App Store category, identified by name: "HEALTH_AND_FITNESS".
+type AppCategory struct {
+	ID string `json:"id"`
+}
+
+// GetAppInfoPrimaryCategory fetches the primary category of an app info
+// record, or nil if none is set.
+func (c *Client) GetAppInfoPrimaryCategory(ctx context.Context, appInfoID string) (*AppCategory, error) {
+	var resp DataResponse[AppCategory]
+	if err := c.get(ctx, fmt.Sprintf("/appInfos/%s/primaryCategory", appInfoID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.ID == "" {
+		return nil, nil
+	}
+	return &resp.Data, nil
+}
+
+// GetAppStoreVersions fetches all versions for an app.
+func (c *Client) GetAppStoreVersions(ctx context.Context, appID string) ([]AppStoreVersion, error) {
+	var resp ListResponse[AppStoreVersion]
+	path := fmt.Sprintf("/apps/%s/appStoreVersions?filter[appStoreState]=READY_FOR_SALE,PREPARE_FOR_SUBMISSION,WAITING_FOR_REVIEW,IN_REVIEW,DEVELOPER_REJECTED", appID)
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetVersionLocalizations fetches localized metadata for a version.
+func (c *Client) GetVersionLocalizations(ctx context.Context, versionID string) ([]VersionLocalization, error) {
+	var resp ListResponse[VersionLocalization]
+	if err := c.get(ctx, fmt.Sprintf("/appStoreVersions/%s/appStoreVersionLocalizations", versionID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetBuilds fetches builds for an app, optionally filtered.
+func (c *Client) GetBuilds(ctx context.Context, appID string) ([]Build, error) {
+	var resp ListResponse[Build]
+	path := fmt.Sprintf("/builds?filter[app]=%s&sort=-uploadedDate&limit=5", appID)
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// TrainBuild is an uploaded build with the version (CFBundleShortVersionString)
+// it was uploaded for. Build numbers must increase within a version.
+type TrainBuild struct {
+	Build
+	AppVersion string
+}
+
+// GetBuildTrains fetches the most recent uploads, newest first, with the
+// version each belongs to.
+func (c *Client) GetBuildTrains(ctx context.Context, appID string, limit int) ([]TrainBuild, error) {
+	var resp struct {
+		Data []struct {
+			Build
+			Relationships struct {
+				PreReleaseVersion struct {
+					Data *resourceRef `json:"data"`
+				} `json:"preReleaseVersion"`
+			} `json:"relationships"`
+		} `json:"data"`
+		Included []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Version string `json:"version"`
+			} `json:"attributes"`
+		} `json:"included"`
+	}
+	path := fmt.Sprintf("/builds?filter[app]=%s&sort=-uploadedDate&limit=%d&include=preReleaseVersion", appID, limit)
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]string)
+	for _, v := range resp.Included {
+		versions[v.ID] = v.Attributes.Version
+	}
+	var builds []TrainBuild
+	for _, b := range resp.Data {
+		tb := TrainBuild{Build: b.Build}
+		if ref := b.Relationships.PreReleaseVersion.Data; ref != nil {
+			tb.AppVersion = versions[ref.ID]
+		}
+		builds = append(builds, tb)
+	}
+	return builds, nil
+}
+
+// GetVersionBuild fetches the build attached to an App Store version, or nil
+// if none is attached yet.
+func (c *Client) GetVersionBuild(ctx context.Context, versionID string) (*Build, error) {
+	var resp DataResponse[Build]
+	if err := c.get(ctx, fmt.Sprintf("/appStoreVersions/%s/build", versionID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.ID == "" {
+		return nil, nil
+	}
+	return &resp.Data, nil
+}
+
+// GetScreenshotSets fetches screenshot sets for a version localization.
+func (c *Client) GetScreenshotSets(ctx context.Context, localizationID string) ([]ScreenshotSet, error) {
+	var resp ListResponse[ScreenshotSet]
+	if err := c.get(ctx, fmt.Sprintf("/appStoreVersionLocalizations/%s/appScreenshotSets", localizationID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// Screenshot represents an individual screenshot file.
+type Screenshot struct {
+	ID         string               `json:"id"`
+	Attributes ScreenshotAttributes `json:"attributes"`
+}
+
+type ScreenshotAttributes struct {
+	FileSize      int    `json:"fileSize"`
+	FileName      string `json:"fileName"`
+	ImageAsset    *ImageAsset `json:"imageAsset"`
+	AssetToken    string `json:"assetToken"`
+	// SourceFileChecksum is the MD5 of the uploaded file.
+	SourceFileChecksum string `json:"sourceFileChecksum"`
+	UploadOperations interface{} `json:"uploadOperations"`
+}
+
+type ImageAsset struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// GetScreenshots fetches individual screenshots for a screenshot set.
+func (c *Client) GetScreenshots(ctx context.Context, screenshotSetID string) ([]Screenshot, error) {
+	var resp ListResponse[Screenshot]
+	if err := c.get(ctx, fmt.Sprintf("/appScreenshotSets/%s/appScreenshots", screenshotSetID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// BetaGroup represents a TestFlight group.
+type BetaGroup struct {
+	ID         string              `json:"id"`
+	Attributes BetaGroupAttributes `json:"attributes"`
+}
+
+type BetaGroupAttributes struct {
+	Name                      string `json:"name"`
+	IsInternalGroup           bool   `json:"isInternalGroup"`
+	PublicLinkEnabled         *bool  `json:"publicLinkEnabled"`
+	PublicLinkLimitEnabled    *bool  `json:"publicLinkLimitEnabled"`
+	HasAccessToAllBuilds      *bool  `json:"hasAccessToAllBuilds"`
+	PublicLink                string `json:"publicLink"`
+	PublicLinkLimit           int    `json:"publicLinkLimit"`
+}
+
+// GetBetaGroups fetches TestFlight beta groups for an app.
+func (c *Client) GetBetaGroups(ctx context.Context, appID string) ([]BetaGroup, error) {
+	var resp ListResponse[BetaGroup]
+	if err := c.get(ctx, fmt.Sprintf("/apps/%s/betaGroups", appID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// AppPrice represents an app's price schedule.
+type AppPrice struct {
+	ID         string             `json:"id"`
+	Attributes AppPriceAttributes `json:"attributes"`
+}
+
+type AppPriceAttributes struct {
+	Manual      bool   `json:"manual"`
+	StartDate   string `json:"startDate"`
+}
+
+// Territory represents an App Store territory.
+type Territory struct {
+	ID         string              `json:"id"`
+	Attributes TerritoryAttributes `json:"attributes"`
+}
+
+type TerritoryAttributes struct {
+	Currency string `json:"currency"`
+}
+
+// GetAppAvailability checks territory availability for an app.
+func (c *Client) GetAppAvailability(ctx context.Context, appID string) ([]Territory, error) {
+	var resp ListResponse[Territory]
+	if err := c.get(ctx, fmt.Sprintf("/apps/%s/availableTerritories?limit=200", appID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// AppPricePoint represents a price tier.
+type AppPricePoint struct {
+	ID         string                   `json:"id"`
+	Attributes AppPricePointAttributes  `json:"attributes"`
+}
+
+type AppPricePointAttributes struct {
+	CustomerPrice string `json:"customerPrice"`
+	Proceeds      string `json:"proceeds"`
+}
+
+// GetAppPriceSchedule fetches the app's price schedule.
+func (c *Client) GetAppPriceSchedule(ctx context.Context, appID string) ([]AppPrice, error) {
+	var resp ListResponse[AppPrice]
+	if err := c.get(ctx, fmt.Sprintf("/apps/%s/appPriceSchedule/manualPrices", appID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,262 @@
+// Package asctest is a fake App Store Connect API for tests: an
+// httptest server that serves the apps, versions, localizations, builds and
+// assets it is given, checks request JWTs like Apple does, and fails
+// requests on demand.
+//
+//	srv := asctest.NewServer()
+//	defer srv.Close()
+//	srv.AddApp(srv.SampleApp("6449000001", "Acme", "com.acme.app"))
+//	srv.Inject(asctest.Fault{Path: "/apps/*/appInfos", Status: 503, Times: 1})
+//	client, err := srv.Client()
+//
+// It covers the read endpoints greenlight's checks use; other routes
+// answer 404 in App Store Connect's error format.
+package asctest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Key ID and issuer ID of the API key the fake accepts.
+const (
+	KeyID    = "ASCTEST123"
+	IssuerID = "00000000-0000-0000-0000-000000000000"
+)
+
+// Server is a fake App Store Connect API. The API root is URL + "/v1".
+type Server struct {
+	*httptest.Server
+
+	key    *ecdsa.PrivateKey
+	keyDir string
+
+	mu        sync.Mutex
+	apps      []*App
+	bundleIDs []*BundleID
+	faults    []*Fault
+	requests  []Request
+}
+
+// Fault makes matching requests fail.
+type Fault struct {
+	Method string // "" matches any method
+	// Path is a path.Match pattern for the path below /v1, e.g. "/apps/*".
+	Path   string
+	Status int
+	// Times is how many matching requests fail; 0 fails all of them.
+	Times int
+	// RetryAfter, if set, is sent as the Retry-After header.
+	RetryAfter time.Duration
+}
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	Path   string // below /v1
+	Query  string
+	Status int
+}
+
+// NewServer starts a fake App Store Connect API. Close it when done.
+func NewServer() *Server {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(fmt.Sprintf("asctest: generating key: %v", err))
+	}
+	s := &Server{key: key}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Close shuts the server down and removes the key written by Client.
+func (s *Server) Close() {
+	s.Server.Close()
+	if s.keyDir != "" {
+		os.RemoveAll(s.keyDir)
+	}
+}
+
+// Client returns an asc.Client for the fake, authenticated with its key and
+// retrying quickly.
+func (s *Server) Client() (*asc.Client, error) {
+	path, err := s.KeyFile()
+	if err != nil {
+		return nil, err
+	}
+	client, err := asc.NewClient(KeyID, IssuerID, path)
+	if err != nil {
+		return nil, err
+	}
+	client.SetBaseURL(s.URL + "/v1")
+	client.SetHTTPClient(s.Server.Client())
+	client.SetRetry(3, 10*time.Millisecond)
+	return client, nil
+}
+
+// KeyFile writes the fake's API key as a .p8 file and returns its path.
+func (s *Server) KeyFile() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keyDir == "" {
+		dir, err := os.MkdirTemp("", "asctest-")
+		if err != nil {
+			return "", err
+		}
+		s.keyDir = dir
+	}
+	path := filepath.Join(s.keyDir, "AuthKey_"+KeyID+".p8")
+	der, err := x509.MarshalPKCS8PrivateKey(s.key)
+	if err != nil {
+		return "", err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return path, os.WriteFile(path, data, 0600)
+}
+
+// Inject adds a fault. Faults are checked in the order they were added.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Count returns how many requests matched the path.Match pattern.
+func (s *Server) Count(pattern string) int {
+	n := 0
+	for _, r := range s.Requests() {
+		if ok, _ := path.Match(pattern, r.Path); ok {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	p, ok := strings.CutPrefix(r.URL.Path, "/v1")
+	if !ok {
+		// Anything outside the API is a web page, for the metadata URLs
+		// that checks fetch.
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintln(w, `<!doctype html><title>asctest</title><a href="mailto:support@asctest.invalid">Contact us</a>`)
+		return
+	}
+
+	status := s.handle(w, r, p)
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: p, Query: r.URL.RawQuery, Status: status})
+	s.mu.Unlock()
+}
+
+// handle answers one API request and returns the status it sent.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, p string) int {
+	if err := s.authorize(r); err != nil {
+		return writeError(w, http.StatusUnauthorized, "NOT_AUTHORIZED", err.Error())
+	}
+	if f := s.fault(r.Method, p); f != nil {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((f.RetryAfter+time.Second-1)/time.Second)))
+		}
+		return writeError(w, f.Status, "INJECTED_FAULT", fmt.Sprintf("asctest: injected %d", f.Status))
+	}
+	if r.Method != http.MethodGet {
+		return writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "asctest serves GET requests only")
+	}
+
+	s.mu.Lock()
+	data, found := s.route(p, r.URL.Query())
+	s.mu.Unlock()
+	if !found {
+		return writeError(w, http.StatusNotFound, "NOT_FOUND", "The specified resource does not exist")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+	return http.StatusOK
+}
+
+// authorize checks the bearer token like App Store Connect: an ES256 JWT
+// signed with the fake's key, with its key ID, issuer and audience.
+func (s *Server) authorize(r *http.Request) error {
+	raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return fmt.Errorf("missing bearer token")
+	}
+	token, err := jwt.Parse(raw, func(t *jwt.Token) (any, error) {
+		if t.Header["kid"] != KeyID {
+			return nil, fmt.Errorf("unknown key ID %v", t.Header["kid"])
+		}
+		return &s.key.PublicKey, nil
+	},
+		jwt.WithValidMethods([]string{"ES256"}),
+		jwt.WithIssuer(IssuerID),
+		jwt.WithAudience("appstoreconnect-v1"),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return fmt.Errorf("invalid token")
+	}
+	return nil
+}
+
+func (s *Server) fault(method, p string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, method) {
+			continue
+		}
+		if ok, _ := path.Match(f.Path, p); !ok {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// writeError answers with an App Store Connect error document.
+func writeError(w http.ResponseWriter, status int, code, detail string) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]string{{
+			"status": strconv.Itoa(status),
+			"code":   code,
+			"title":  http.StatusText(status),
+			"detail": detail,
+		}},
+	})
+	return status
+}
@@ -0,0 +1,516 @@
+package asctest
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+)
+
+// App is an app the fake serves, with the resources under it.
+type App struct {
+	asc.App
+	Infos       []AppInfo
+	Versions    []Version
+	Builds      []asc.TrainBuild // newest first
+	BetaGroups  []asc.BetaGroup
+	Territories []asc.Territory
+	Prices      []asc.AppPrice
+}
+
+// AppInfo is an app info record and its localizations.
+type AppInfo struct {
+	asc.AppInfo
+	Localizations []asc.AppInfoLocalization
+	// PrimaryCategory is the category ID, e.g. "PRODUCTIVITY", if one is set.
+	PrimaryCategory string
+}
+
+// Version is an App Store version.
+type Version struct {
+	asc.AppStoreVersion
+	Localizations []VersionLocalization
+	// BuildID is the build attached to the version, if any.
+	BuildID string
+	// ReviewDetail is the App Review information, if any has been entered.
+	ReviewDetail *ReviewDetail
+}
+
+// ReviewDetail is a version's App Review information and its attachments.
+type ReviewDetail struct {
+	asc.ReviewDetail
+	Attachments []asc.ReviewAttachment
+}
+
+// VersionLocalization is a version's metadata in one locale, with its
+// screenshots and previews.
+type VersionLocalization struct {
+	asc.VersionLocalization
+	ScreenshotSets []ScreenshotSet
+	PreviewSets    []PreviewSet
+}
+
+// ScreenshotSet is the screenshots for one display type.
+type ScreenshotSet struct {
+	asc.ScreenshotSet
+	Screenshots []asc.Screenshot
+}
+
+// PreviewSet is the preview videos for one device class.
+type PreviewSet struct {
+	asc.AppPreviewSet
+	Previews []asc.AppPreview
+}
+
+// BundleID is an App ID in the Developer portal and its capabilities.
+type BundleID struct {
+	asc.BundleID
+	Capabilities []asc.BundleIDCapability
+}
+
+// AddApp adds app, giving IDs to the resources under it that have none, and
+// returns the stored copy. Change it through Update.
+func (s *Server) AddApp(app App) *App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	id := func(kind string, v *string) {
+		n++
+		if *v == "" {
+			*v = fmt.Sprintf("%s-%s-%d", kind, app.ID, n)
+		}
+	}
+	if app.ID == "" {
+		app.ID = strconv.Itoa(6449000000 + len(s.apps) + 1)
+	}
+	for i := range app.Infos {
+		info := &app.Infos[i]
+		id("appinfo", &info.ID)
+		for j := range info.Localizations {
+			id("appinfoloc", &info.Localizations[j].ID)
+		}
+	}
+	for i := range app.Builds {
+		id("build", &app.Builds[i].ID)
+	}
+	for i := range app.Versions {
+		v := &app.Versions[i]
+		id("version", &v.ID)
+		for j := range v.Localizations {
+			loc := &v.Localizations[j]
+			id("versionloc", &loc.ID)
+			for k := range loc.ScreenshotSets {
+				set := &loc.ScreenshotSets[k]
+				id("screenshotset", &set.ID)
+				for m := range set.Screenshots {
+					id("screenshot", &set.Screenshots[m].ID)
+				}
+			}
+			for k := range loc.PreviewSets {
+				set := &loc.PreviewSets[k]
+				id("previewset", &set.ID)
+				for m := range set.Previews {
+					id("preview", &set.Previews[m].ID)
+				}
+			}
+		}
+		if rd := v.ReviewDetail; rd != nil {
+			id("reviewdetail", &rd.ID)
+			for j := range rd.Attachments {
+				id("reviewattachment", &rd.Attachments[j].ID)
+			}
+		}
+	}
+	for i := range app.BetaGroups {
+		id("betagroup", &app.BetaGroups[i].ID)
+	}
+	stored := &app
+	s.apps = append(s.apps, stored)
+	return stored
+}
+
+// AddBundleID adds an App ID to the Developer portal.
+func (s *Server) AddBundleID(b BundleID) *BundleID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b.ID == "" {
+		b.ID = fmt.Sprintf("bundleid-%d", len(s.bundleIDs)+1)
+	}
+	stored := &b
+	s.bundleIDs = append(s.bundleIDs, stored)
+	return stored
+}
+
+// Update runs fn with the server's data locked, for changing apps added
+// earlier while requests may be in flight.
+func (s *Server) Update(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn()
+}
+
+// SampleApp returns an app ready for review: name and subtitle, a version
+// being prepared with complete en-US metadata, screenshots at the required
+// sizes, App Review notes and a processed build attached, age rating,
+// availability and a price. Its URLs point at the fake server, so they are reachable.
+func (s *Server) SampleApp(id, name, bundleID string) App {
+	notExempt := false
+	uploaded := time.Now().Add(-24 * time.Hour).UTC()
+	build := asc.TrainBuild{
+		Build: asc.Build{ID: "build-" + id, Attributes: asc.BuildAttributes{
+			Version:                 "12",
+			UploadedDate:            uploaded.Format(time.RFC3339),
+			ProcessingState:         "VALID",
+			MinOsVersion:            "16.0",
+			UsesNonExemptEncryption: &notExempt,
+			ExpirationDate:          uploaded.Add(90 * 24 * time.Hour).Format(time.RFC3339),
+		}},
+		AppVersion: "1.2.0",
+	}
+	screenshots := func(displayType string, width, height int) ScreenshotSet {
+		set := ScreenshotSet{ScreenshotSet: asc.ScreenshotSet{Attributes: asc.ScreenshotSetAttributes{ScreenshotDisplayType: displayType}}}
+		for i := 1; i <= 3; i++ {
+			file := fmt.Sprintf("%s-%d.png", strings.ToLower(displayType), i)
+			set.Screenshots = append(set.Screenshots, asc.Screenshot{Attributes: asc.ScreenshotAttributes{
+				FileName:           file,
+				FileSize:           512_000,
+				ImageAsset:         &asc.ImageAsset{Width: width, Height: height},
+				SourceFileChecksum: fmt.Sprintf("%x", md5.Sum([]byte(id+"/"+file))),
+			}})
+		}
+		return set
+	}
+
+	app := App{
+		App: asc.App{ID: id, Attributes: asc.AppAttributes{
+			Name:          name,
+			BundleID:      bundleID,
+			SKU:           strings.ToUpper(strings.ReplaceAll(bundleID, ".", "_")),
+			PrimaryLocale: "en-US",
+		}},
+		Infos: []AppInfo{{
+			AppInfo: asc.AppInfo{Attributes: asc.AppInfoAttributes{
+				AppStoreState:     "PREPARE_FOR_SUBMISSION",
+				AppStoreAgeRating: "FOUR_PLUS",
+			}},
+			Localizations: []asc.AppInfoLocalization{{Attributes: asc.AppInfoLocalizationAttributes{
+				Locale:           "en-US",
+				Name:             name,
+				Subtitle:         "Plan your day",
+				PrivacyPolicyURL: s.URL + "/privacy",
+			}}},
+			PrimaryCategory: "PRODUCTIVITY",
+		}},
+		Versions: []Version{{
+			AppStoreVersion: asc.AppStoreVersion{Attributes: asc.AppStoreVersionAttributes{
+				VersionString: "1.2.0",
+				AppStoreState: "PREPARE_FOR_SUBMISSION",
+				Platform:      "IOS",
+				ReleaseType:   "MANUAL",
+				CreatedDate:   uploaded.Format(time.RFC3339),
+			}},
+			BuildID: build.ID,
+			ReviewDetail: &ReviewDetail{ReviewDetail: asc.ReviewDetail{Attributes: asc.ReviewDetailAttributes{
+				ContactFirstName: "Sam",
+				ContactLastName:  "Reviewer",
+				ContactPhone:     "+1 555 0100",
+				ContactEmail:     "review@" + strings.TrimPrefix(bundleID, "com.") + ".example",
+				Notes:            "Sign in is optional. Tap the + button to add a task.",
+			}}},
+			Localizations: []VersionLocalization{{
+				VersionLocalization: asc.VersionLocalization{Attributes: asc.VersionLocalizationAttributes{
+					Locale:          "en-US",
+					Description:     name + " keeps your tasks, notes and reminders in one place. Plan the week ahead, check off what you finish and see what is coming up next.",
+					Keywords:        "tasks,notes,reminders,calendar,agenda,organizer,checklist,schedule,habits",
+					WhatsNew:        "Faster sync and a cleaner week view.",
+					SupportURL:      s.URL + "/support",
+					MarketingURL:    s.URL + "/",
+					PromotionalText: "Plan your week in minutes.",
+				}},
+				ScreenshotSets: []ScreenshotSet{
+					screenshots("APP_IPHONE_67", 1290, 2796),
+					screenshots("APP_IPHONE_55", 1242, 2208),
+					screenshots("APP_IPAD_PRO_3GEN_129", 2048, 2732),
+				},
+			}},
+		}},
+		Builds:     []asc.TrainBuild{build},
+		BetaGroups: []asc.BetaGroup{{Attributes: asc.BetaGroupAttributes{Name: "Public Beta"}}},
+		Prices:     []asc.AppPrice{{ID: "price-" + id, Attributes: asc.AppPriceAttributes{Manual: true}}},
+	}
+	for territory, currency := range map[string]string{"USA": "USD", "CAN": "CAD", "GBR": "GBP", "DEU": "EUR", "AUS": "AUD", "JPN": "JPY"} {
+		app.Territories = append(app.Territories, asc.Territory{ID: territory, Attributes: asc.TerritoryAttributes{Currency: currency}})
+	}
+	slices.SortFunc(app.Territories, func(a, b asc.Territory) int { return strings.Compare(a.ID, b.ID) })
+	return app
+}
+
+type data struct {
+	Data any `json:"data"`
+}
+
+// route finds the resource at p. Callers hold s.mu.
+func (s *Server) route(p string, q url.Values) (any, bool) {
+	seg := strings.Split(strings.Trim(p, "/"), "/")
+	match := func(pattern string) bool {
+		ok, _ := path.Match(pattern, p)
+		return ok
+	}
+
+	switch {
+	case p == "/apps":
+		var apps []asc.App
+		for _, a := range s.apps {
+			if b := q.Get("filter[bundleId]"); b != "" && a.Attributes.BundleID != b {
+				continue
+			}
+			apps = append(apps, a.App)
+		}
+		return data{orEmpty(apps)}, true
+	case match("/apps/*"):
+		if a := s.app(seg[1]); a != nil {
+			return data{a.App}, true
+		}
+	case match("/apps/*/appInfos"):
+		if a := s.app(seg[1]); a != nil {
+			var infos []asc.AppInfo
+			for _, i := range a.Infos {
+				infos = append(infos, i.AppInfo)
+			}
+			return data{orEmpty(infos)}, true
+		}
+	case match("/appInfos/*/appInfoLocalizations"):
+		for _, a := range s.apps {
+			for _, i := range a.Infos {
+				if i.ID == seg[1] {
+					return data{orEmpty(i.Localizations)}, true
+				}
+			}
+		}
+	case match("/appInfos/*/primaryCategory"):
+		for _, a := range s.apps {
+			for _, i := range a.Infos {
+				if i.ID == seg[1] {
+					if i.PrimaryCategory == "" {
+						return data{nil}, true
+					}
+					return data{asc.AppCategory{ID: i.PrimaryCategory}}, true
+				}
+			}
+		}
+	case match("/apps/*/appStoreVersions"):
+		if a := s.app(seg[1]); a != nil {
+			states := q.Get("filter[appStoreState]")
+			var versions []asc.AppStoreVersion
+			for _, v := range a.Versions {
+				if states == "" || slices.Contains(strings.Split(states, ","), v.Attributes.AppStoreState) {
+					versions = append(versions, v.AppStoreVersion)
+				}
+			}
+			return data{orEmpty(versions)}, true
+		}
+	case match("/appStoreVersions/*/appStoreVersionLocalizations"):
+		if v := s.version(seg[1]); v != nil {
+			var locs []asc.VersionLocalization
+			for _, l := range v.Localizations {
+				locs = append(locs, l.VersionLocalization)
+			}
+			return data{orEmpty(locs)}, true
+		}
+	case match("/appStoreVersions/*/build"):
+		if v := s.version(seg[1]); v != nil {
+			for _, a := range s.apps {
+				for _, b := range a.Builds {
+					if b.ID == v.BuildID {
+						return data{b.Build}, true
+					}
+				}
+			}
+			return data{nil}, true
+		}
+	case match("/appStoreVersions/*/appStoreReviewDetail"):
+		if v := s.version(seg[1]); v != nil {
+			if v.ReviewDetail == nil {
+				return data{nil}, true
+			}
+			return data{v.ReviewDetail.ReviewDetail}, true
+		}
+	case match("/appStoreReviewDetails/*/appStoreReviewAttachments"):
+		for _, a := range s.apps {
+			for _, v := range a.Versions {
+				if rd := v.ReviewDetail; rd != nil && rd.ID == seg[1] {
+					return data{orEmpty(rd.Attachments)}, true
+				}
+			}
+		}
+	case p == "/builds":
+		return s.builds(q), true
+	case match("/appStoreVersionLocalizations/*/appScreenshotSets"):
+		if l := s.localization(seg[1]); l != nil {
+			var sets []asc.ScreenshotSet
+			for _, set := range l.ScreenshotSets {
+				sets = append(sets, set.ScreenshotSet)
+			}
+			return data{orEmpty(sets)}, true
+		}
+	case match("/appScreenshotSets/*/appScreenshots"):
+		for _, l := range s.localizations() {
+			for _, set := range l.ScreenshotSets {
+				if set.ID == seg[1] {
+					return data{orEmpty(set.Screenshots)}, true
+				}
+			}
+		}
+	case match("/appStoreVersionLocalizations/*/appPreviewSets"):
+		if l := s.localization(seg[1]); l != nil {
+			var sets []asc.AppPreviewSet
+			for _, set := range l.PreviewSets {
+				sets = append(sets, set.AppPreviewSet)
+			}
+			return data{orEmpty(sets)}, true
+		}
+	case match("/appPreviewSets/*/appPreviews"):
+		for _, l := range s.localizations() {
+			for _, set := range l.PreviewSets {
+				if set.ID == seg[1] {
+					return data{orEmpty(set.Previews)}, true
+				}
+			}
+		}
+	case match("/apps/*/betaGroups"):
+		if a := s.app(seg[1]); a != nil {
+			return data{orEmpty(a.BetaGroups)}, true
+		}
+	case match("/apps/*/availableTerritories"):
+		if a := s.app(seg[1]); a != nil {
+			return data{orEmpty(a.Territories)}, true
+		}
+	case match("/apps/*/appPriceSchedule/manualPrices"):
+		if a := s.app(seg[1]); a != nil {
+			return data{orEmpty(a.Prices)}, true
+		}
+	case p == "/bundleIds":
+		var ids []asc.BundleID
+		for _, b := range s.bundleIDs {
+			// Like App Store Connect, the filter also matches prefixes.
+			if strings.HasPrefix(b.Attributes.Identifier, q.Get("filter[identifier]")) {
+				ids = append(ids, b.BundleID)
+			}
+		}
+		return data{orEmpty(ids)}, true
+	case match("/bundleIds/*/bundleIdCapabilities"):
+		for _, b := range s.bundleIDs {
+			if b.ID == seg[1] {
+				return data{orEmpty(b.Capabilities)}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// builds answers /builds?filter[app]=...&limit=...&include=preReleaseVersion.
+func (s *Server) builds(q url.Values) any {
+	type ref struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+	type build struct {
+		asc.Build
+		Relationships struct {
+			PreReleaseVersion struct {
+				Data *ref `json:"data"`
+			} `json:"preReleaseVersion"`
+		} `json:"relationships"`
+	}
+	type version struct {
+		Type       string `json:"type"`
+		ID         string `json:"id"`
+		Attributes struct {
+			Version string `json:"version"`
+		} `json:"attributes"`
+	}
+	resp := struct {
+		Data     []build   `json:"data"`
+		Included []version `json:"included,omitempty"`
+	}{Data: []build{}}
+
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	a := s.app(q.Get("filter[app]"))
+	if a == nil {
+		return resp
+	}
+	included := map[string]bool{}
+	for _, b := range a.Builds {
+		if limit > 0 && len(resp.Data) == limit {
+			break
+		}
+		out := build{Build: b.Build}
+		if b.AppVersion != "" {
+			vid := "prerelease-" + a.ID + "-" + b.AppVersion
+			out.Relationships.PreReleaseVersion.Data = &ref{"preReleaseVersions", vid}
+			if q.Get("include") == "preReleaseVersion" && !included[vid] {
+				included[vid] = true
+				v := version{Type: "preReleaseVersions", ID: vid}
+				v.Attributes.Version = b.AppVersion
+				resp.Included = append(resp.Included, v)
+			}
+		}
+		resp.Data = append(resp.Data, out)
+	}
+	return resp
+}
+
+func (s *Server) app(id string) *App {
+	for _, a := range s.apps {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Server) version(id string) *Version {
+	for _, a := range s.apps {
+		for i := range a.Versions {
+			if a.Versions[i].ID == id {
+				return &a.Versions[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) localizations() []*VersionLocalization {
+	var locs []*VersionLocalization
+	for _, a := range s.apps {
+		for i := range a.Versions {
+			for j := range a.Versions[i].Localizations {
+				locs = append(locs, &a.Versions[i].Localizations[j])
+			}
+		}
+	}
+	return locs
+}
+
+func (s *Server) localization(id string) *VersionLocalization {
+	for _, l := range s.localizations() {
+		if l.ID == id {
+			return l
+		}
+	}
+	return nil
+}
+
+// orEmpty keeps empty lists from encoding as null.
+func orEmpty[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+	return list
+}
@@ -0,0 +1,49 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// BundleID is an App ID registered in the Developer portal.
+type BundleID struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		Identifier string `json:"identifier"`
+		Name       string `json:"name"`
+		Platform   string `json:"platform"`
+	} `json:"attributes"`
+}
+
+// BundleIDCapability is a capability enabled on an App ID.
+type BundleIDCapability struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		CapabilityType string `json:"capabilityType"` // GAME_CENTER, ICLOUD, APPLE_ID_AUTH, APPLE_PAY, ...
+	} `json:"attributes"`
+}
+
+// FindBundleID looks up the App ID with exactly this identifier, or nil.
+func (c *Client) FindBundleID(ctx context.Context, identifier string) (*BundleID, error) {
+	var resp ListResponse[BundleID]
+	if err := c.get(ctx, "/bundleIds?filter[identifier]="+url.QueryEscape(identifier), &resp); err != nil {
+		return nil, err
+	}
+	// The filter matches prefixes too (com.acme.app.widget).
+	for i := range resp.Data {
+		if resp.Data[i].Attributes.Identifier == identifier {
+			return &resp.Data[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetBundleIDCapabilities fetches the capabilities enabled on an App ID.
+func (c *Client) GetBundleIDCapabilities(ctx context.Context, bundleID string) ([]BundleIDCapability, error) {
+	var resp ListResponse[BundleIDCapability]
+	if err := c.get(ctx, fmt.Sprintf("/bundleIds/%s/bundleIdCapabilities", bundleID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
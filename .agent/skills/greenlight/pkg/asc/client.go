@@ -0,0 +1,209 @@
+// Package asc is a client for the App Store Connect API, authenticated
+// with an API key (or an Apple ID session): apps, versions, builds,
+// TestFlight, screenshots and previews.
+//
+//	client, err := asc.NewClient(keyID, issuerID, "AuthKey_ABC123.p8")
+package asc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the App Store Connect API.
+const DefaultBaseURL = "https://api.appstoreconnect.apple.com/v1"
+
+const (
+	defaultMaxAttempts = 3
+	defaultRetryWait   = 500 * time.Millisecond
+	// maxRetryAfter caps how long a Retry-After header can make a request wait.
+	maxRetryAfter = 30 * time.Second
+)
+
+type Client struct {
+	keyID      string
+	issuerID   string
+	keyPath    string
+	baseURL    string
+	httpClient *http.Client
+
+	// Requests that fail with 429 or a 5xx status are retried up to
+	// maxAttempts in total, waiting retryWait, then twice as long, and so on
+	// (or as long as Retry-After says). Only GETs are retried after a 5xx,
+	// since the server may have acted on other methods.
+	maxAttempts int
+	retryWait   time.Duration
+
+	// mu guards the token, so one client can serve concurrent requests.
+	mu       sync.Mutex
+	token    string
+	tokenExp time.Time
+}
+
+func NewClient(keyID, issuerID, privateKeyPath string) (*Client, error) {
+	c := &Client{
+		keyID:    keyID,
+		issuerID: issuerID,
+		keyPath:  privateKeyPath,
+		baseURL:  DefaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		maxAttempts: defaultMaxAttempts,
+		retryWait:   defaultRetryWait,
+	}
+
+	// Validate credentials by generating a token
+	if err := c.refreshToken(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// SetBaseURL points the client at another API root, such as a fake server
+// from package asctest.
+func (c *Client) SetBaseURL(url string) {
+	c.baseURL = strings.TrimSuffix(url, "/")
+}
+
+// SetHTTPClient replaces the HTTP client requests are sent with.
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.httpClient = hc
+}
+
+// SetRetry sets how many times a request is attempted in total (1 turns
+// retries off) and the wait before the first retry.
+func (c *Client) SetRetry(maxAttempts int, wait time.Duration) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	c.maxAttempts, c.retryWait = maxAttempts, wait
+}
+
+func (c *Client) refreshToken() error {
+	token, err := generateToken(c.keyID, c.issuerID, c.keyPath)
+	if err != nil {
+		return err
+	}
+	c.token = token
+	c.tokenExp = time.Now().Add(15 * time.Minute) // refresh before 20min expiry
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, path string, result interface{}) error {
+	return c.do(ctx, "GET", path, nil, result)
+}
+
+func (c *Client) post(ctx context.Context, path string, body, result interface{}) error {
+	return c.do(ctx, "POST", path, body, result)
+}
+
+func (c *Client) patch(ctx context.Context, path string, body, result interface{}) error {
+	return c.do(ctx, "PATCH", path, body, result)
+}
+
+func (c *Client) delete(ctx context.Context, path string, body interface{}) error {
+	return c.do(ctx, "DELETE", path, body, nil)
+}
+
+// do sends a request with an optional JSON body and decodes the response
+// into result (if non-nil and the response has a body).
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	wait := c.retryWait
+	for attempt := 1; ; attempt++ {
+		status, retryAfter, err := c.send(ctx, method, path, data, result)
+		retryable := status == http.StatusTooManyRequests || (status >= 500 && method == http.MethodGet)
+		if err == nil || !retryable || attempt >= c.maxAttempts {
+			return err
+		}
+		if retryAfter > 0 {
+			wait = min(retryAfter, maxRetryAfter)
+		}
+		slog.DebugContext(ctx, "retrying asc request", "method", method, "path", path, "status", status, "attempt", attempt, "wait", wait)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// send makes one attempt at a request. It returns the response status (0 if
+// there was no response) and the Retry-After delay the server asked for.
+func (c *Client) send(ctx context.Context, method, path string, data []byte, result interface{}) (int, time.Duration, error) {
+	c.mu.Lock()
+	if time.Now().After(c.tokenExp) {
+		slog.DebugContext(ctx, "refreshing asc token", "key_id", c.keyID)
+		if err := c.refreshToken(); err != nil {
+			c.mu.Unlock()
+			return 0, 0, err
+		}
+	}
+	token := c.token
+	c.mu.Unlock()
+
+	var reqBody io.Reader
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
+
+	url := c.baseURL + path
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		slog.DebugContext(ctx, "asc request failed", "method", method, "path", path, "duration", time.Since(start), "error", err)
+		return 0, 0, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	slog.DebugContext(ctx, "asc request", "method", method, "path", path, "status", resp.StatusCode,
+		"bytes", len(respBody), "duration", time.Since(start))
+	if err != nil {
+		return resp.StatusCode, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var retryAfter time.Duration
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			retryAfter = time.Duration(secs) * time.Second
+		}
+		return resp.StatusCode, retryAfter, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return resp.StatusCode, 0, fmt.Errorf("failed to parse response: %w", err)
+		}
+	}
+
+	return resp.StatusCode, 0, nil
+}
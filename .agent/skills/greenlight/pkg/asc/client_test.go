@@ -0,0 +1,123 @@
+package asc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/asc/asctest"
+)
+
+const appID = "6449000001"
+
+func newServer(t *testing.T) (*asctest.Server, *asc.Client) {
+	t.Helper()
+	srv := asctest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.AddApp(srv.SampleApp(appID, "Acme", "com.acme.app"))
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	return srv, client
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    asctest.Fault
+		wantErr  string // "" for success
+		requests int
+	}{
+		{"no fault", asctest.Fault{}, "", 1},
+		{"5xx then success", asctest.Fault{Path: "/apps/*", Status: 503, Times: 2}, "", 3},
+		{"429 then success", asctest.Fault{Path: "/apps/*", Status: 429, Times: 1}, "", 2},
+		{"5xx every attempt", asctest.Fault{Path: "/apps/*", Status: 500}, "API error 500", 3},
+		{"4xx is not retried", asctest.Fault{Path: "/apps/*", Status: 403, Times: 1}, "API error 403", 1},
+		{"404 is not retried", asctest.Fault{Path: "/apps/*", Status: 404}, "API error 404", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newServer(t)
+			if tt.fault.Status != 0 {
+				srv.Inject(tt.fault)
+			}
+			app, err := client.GetApp(context.Background(), appID)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("GetApp: %v", err)
+			case tt.wantErr == "" && app.Attributes.BundleID != "com.acme.app":
+				t.Errorf("GetApp bundle ID = %q, want com.acme.app", app.Attributes.BundleID)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("GetApp error = %v, want %q", err, tt.wantErr)
+			}
+			if n := srv.Count("/apps/*"); n != tt.requests {
+				t.Errorf("requests = %d, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+func TestClientDoesNotRetryWrites(t *testing.T) {
+	srv, client := newServer(t)
+	srv.Inject(asctest.Fault{Method: "POST", Path: "/betaTesters", Status: 503})
+	if _, err := client.CreateBetaTester(context.Background(), "a@example.com", "A", "B", nil); err == nil {
+		t.Fatal("CreateBetaTester succeeded, want error")
+	}
+	if n := srv.Count("/betaTesters"); n != 1 {
+		t.Errorf("requests = %d, want 1: a POST that failed with a 5xx may have been applied", n)
+	}
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	srv, client := newServer(t)
+	srv.Inject(asctest.Fault{Path: "/apps/*", Status: 429, Times: 1, RetryAfter: time.Second})
+	start := time.Now()
+	if _, err := client.GetApp(context.Background(), appID); err != nil {
+		t.Fatalf("GetApp: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s Retry-After", elapsed)
+	}
+}
+
+func TestClientStopsRetryingWhenCanceled(t *testing.T) {
+	srv, client := newServer(t)
+	client.SetRetry(5, time.Minute)
+	srv.Inject(asctest.Fault{Path: "/apps/*", Status: 503})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetApp(ctx, appID); err == nil {
+		t.Fatal("GetApp succeeded, want error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetApp returned after %s, want it to stop when the context is canceled", elapsed)
+	}
+	if n := srv.Count("/apps/*"); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+func TestClientAuthentication(t *testing.T) {
+	srv, _ := newServer(t)
+	keyFile, err := srv.KeyFile()
+	if err != nil {
+		t.Fatalf("KeyFile: %v", err)
+	}
+	client, err := asc.NewClient("WRONGKEY", asctest.IssuerID, keyFile)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	client.SetBaseURL(srv.URL + "/v1")
+	client.SetRetry(3, 10*time.Millisecond)
+	_, err = client.GetApp(context.Background(), appID)
+	if err == nil || !strings.Contains(err.Error(), "API error 401") {
+		t.Fatalf("GetApp error = %v, want 401", err)
+	}
+	if n := srv.Count("/apps/*"); n != 1 {
+		t.Errorf("requests = %d, want 1: authentication failures aren't retried", n)
+	}
+}
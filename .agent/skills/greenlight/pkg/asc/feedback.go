@@ -0,0 +1,150 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// BetaFeedbackCrashSubmission is a crash a TestFlight tester reported.
+type BetaFeedbackCrashSubmission struct {
+	ID            string                    `json:"id"`
+	Attributes    BetaFeedbackAttributes    `json:"attributes"`
+	Relationships betaFeedbackRelationships `json:"relationships"`
+}
+
+// BetaFeedbackScreenshotSubmission is screenshot feedback from a tester.
+type BetaFeedbackScreenshotSubmission struct {
+	ID            string                    `json:"id"`
+	Attributes    BetaFeedbackAttributes    `json:"attributes"`
+	Relationships betaFeedbackRelationships `json:"relationships"`
+}
+
+// BetaFeedbackAttributes are the fields shared by crash and screenshot
+// feedback.
+type BetaFeedbackAttributes struct {
+	CreatedDate             string                   `json:"createdDate"`
+	Comment                 string                   `json:"comment"`
+	Email                   string                   `json:"email"`
+	DeviceModel             string                   `json:"deviceModel"`
+	OSVersion               string                   `json:"osVersion"`
+	Locale                  string                   `json:"locale"`
+	ConnectionType          string                   `json:"connectionType"`
+	AppUptimeInMilliseconds int64                    `json:"appUptimeInMilliseconds"`
+	BatteryPercentage       int                      `json:"batteryPercentage"`
+	Screenshots             []BetaFeedbackScreenshot `json:"screenshots,omitempty"`
+}
+
+// BetaFeedbackScreenshot is one image attached to screenshot feedback. URLs
+// expire after a short time.
+type BetaFeedbackScreenshot struct {
+	URL            string `json:"url"`
+	Width          int    `json:"width"`
+	Height         int    `json:"height"`
+	ExpirationDate string `json:"expirationDate"`
+}
+
+type betaFeedbackRelationships struct {
+	Build struct {
+		Data *resourceRef `json:"data"`
+	} `json:"build"`
+}
+
+// BuildID returns the ID of the build the feedback was sent from.
+func (r betaFeedbackRelationships) BuildID() string {
+	if r.Build.Data == nil {
+		return ""
+	}
+	return r.Build.Data.ID
+}
+
+// BetaCrashLog is the symbolicated (when dSYMs were uploaded) crash report.
+type BetaCrashLog struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		LogText string `json:"logText"`
+	} `json:"attributes"`
+}
+
+// feedbackResponse is a feedback list with the builds it references.
+type feedbackResponse[T any] struct {
+	Data     []T     `json:"data"`
+	Included []Build `json:"included"`
+}
+
+func feedbackPath(kind, appID, buildID string, limit int) string {
+	path := fmt.Sprintf("/apps/%s/%s?include=build&sort=-createdDate&limit=%d", url.PathEscape(appID), kind, limit)
+	if buildID != "" {
+		path += "&filter[build]=" + url.QueryEscape(buildID)
+	}
+	return path
+}
+
+// GetBetaCrashFeedback lists tester-reported crashes, newest first, with the
+// builds they came from. buildID filters to one build.
+func (c *Client) GetBetaCrashFeedback(ctx context.Context, appID, buildID string, limit int) ([]BetaFeedbackCrashSubmission, []Build, error) {
+	var resp feedbackResponse[BetaFeedbackCrashSubmission]
+	if err := c.get(ctx, feedbackPath("betaFeedbackCrashSubmissions", appID, buildID, limit), &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Data, resp.Included, nil
+}
+
+// GetBetaScreenshotFeedback lists tester screenshot feedback, newest first,
+// with the builds it came from. buildID filters to one build.
+func (c *Client) GetBetaScreenshotFeedback(ctx context.Context, appID, buildID string, limit int) ([]BetaFeedbackScreenshotSubmission, []Build, error) {
+	var resp feedbackResponse[BetaFeedbackScreenshotSubmission]
+	if err := c.get(ctx, feedbackPath("betaFeedbackScreenshotSubmissions", appID, buildID, limit), &resp); err != nil {
+		return nil, nil, err
+	}
+	return resp.Data, resp.Included, nil
+}
+
+// GetBetaCrashLog fetches the crash report for a crash submission.
+func (c *Client) GetBetaCrashLog(ctx context.Context, submissionID string) (*BetaCrashLog, error) {
+	var resp DataResponse[BetaCrashLog]
+	if err := c.get(ctx, "/betaFeedbackCrashSubmissions/"+submissionID+"/crashLog", &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+var (
+	crashExceptionRe = regexp.MustCompile(`(?m)^Exception Type:\s*(.+)$`)
+	crashThreadRe    = regexp.MustCompile(`(?m)^Thread \d+ Crashed:.*$`)
+	crashFrameRe     = regexp.MustCompile(`^\d+\s+(\S+)\s+0x[0-9a-fA-F]+\s+(.+)$`)
+)
+
+// CrashSignature summarizes a crash report as its exception type and the
+// top frames of the crashed thread, so reports of the same crash group
+// together.
+func (l *BetaCrashLog) CrashSignature(frames int) (exception string, top []string) {
+	text := l.Attributes.LogText
+	if m := crashExceptionRe.FindStringSubmatch(text); m != nil {
+		exception = strings.TrimSpace(m[1])
+	}
+	loc := crashThreadRe.FindStringIndex(text)
+	if loc == nil {
+		return exception, nil
+	}
+	for _, line := range strings.Split(text[loc[1]:], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if len(top) > 0 {
+				break
+			}
+			continue
+		}
+		m := crashFrameRe.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		top = append(top, m[1]+"  "+m[2])
+		if len(top) == frames {
+			break
+		}
+	}
+	return exception, top
+}
@@ -0,0 +1,70 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+)
+
+// AppPreviewSet groups app preview videos for one device class.
+type AppPreviewSet struct {
+	ID         string                  `json:"id"`
+	Attributes AppPreviewSetAttributes `json:"attributes"`
+}
+
+type AppPreviewSetAttributes struct {
+	PreviewType string `json:"previewType"` // IPHONE_67, IPAD_PRO_3GEN_129, ...
+}
+
+// AppPreview is an uploaded app preview video.
+type AppPreview struct {
+	ID         string               `json:"id"`
+	Attributes AppPreviewAttributes `json:"attributes"`
+}
+
+type AppPreviewAttributes struct {
+	FileSize             int                 `json:"fileSize"`
+	FileName             string              `json:"fileName"`
+	MimeType             string              `json:"mimeType"`
+	VideoURL             string              `json:"videoUrl"`
+	PreviewFrameTimeCode string              `json:"previewFrameTimeCode"`
+	PreviewImage         *ImageAsset         `json:"previewImage"`
+	AssetDeliveryState   *AssetDeliveryState `json:"assetDeliveryState"`
+	VideoDeliveryState   *AssetDeliveryState `json:"videoDeliveryState"`
+}
+
+// AssetDeliveryState is the upload/processing state of an asset:
+// AWAITING_UPLOAD, UPLOAD_COMPLETE, PROCESSING, COMPLETE or FAILED.
+type AssetDeliveryState struct {
+	State  string `json:"state"`
+	Errors []struct {
+		Code        string `json:"code"`
+		Description string `json:"description"`
+	} `json:"errors"`
+}
+
+// DeliveryState returns the preview's processing state, preferring the
+// video state over the upload state.
+func (p AppPreview) DeliveryState() *AssetDeliveryState {
+	if p.Attributes.VideoDeliveryState != nil {
+		return p.Attributes.VideoDeliveryState
+	}
+	return p.Attributes.AssetDeliveryState
+}
+
+// GetPreviewSets fetches app preview sets for a version localization.
+func (c *Client) GetPreviewSets(ctx context.Context, localizationID string) ([]AppPreviewSet, error) {
+	var resp ListResponse[AppPreviewSet]
+	if err := c.get(ctx, fmt.Sprintf("/appStoreVersionLocalizations/%s/appPreviewSets", localizationID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// GetPreviews fetches the preview videos in a preview set.
+func (c *Client) GetPreviews(ctx context.Context, previewSetID string) ([]AppPreview, error) {
+	var resp ListResponse[AppPreview]
+	if err := c.get(ctx, fmt.Sprintf("/appPreviewSets/%s/appPreviews", previewSetID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
@@ -0,0 +1,58 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReviewDetail is the App Review information for a version: contact,
+// demo account and notes for the reviewer.
+type ReviewDetail struct {
+	ID         string                 `json:"id"`
+	Attributes ReviewDetailAttributes `json:"attributes"`
+}
+
+type ReviewDetailAttributes struct {
+	ContactFirstName    string `json:"contactFirstName"`
+	ContactLastName     string `json:"contactLastName"`
+	ContactPhone        string `json:"contactPhone"`
+	ContactEmail        string `json:"contactEmail"`
+	DemoAccountName     string `json:"demoAccountName"`
+	DemoAccountPassword string `json:"demoAccountPassword"`
+	DemoAccountRequired bool   `json:"demoAccountRequired"`
+	Notes               string `json:"notes"`
+}
+
+// ReviewAttachment is a file attached to the App Review information.
+type ReviewAttachment struct {
+	ID         string                     `json:"id"`
+	Attributes ReviewAttachmentAttributes `json:"attributes"`
+}
+
+type ReviewAttachmentAttributes struct {
+	FileSize           int                 `json:"fileSize"`
+	FileName           string              `json:"fileName"`
+	AssetDeliveryState *AssetDeliveryState `json:"assetDeliveryState"`
+}
+
+// GetReviewDetail fetches the App Review information for a version, or nil
+// if none has been entered yet.
+func (c *Client) GetReviewDetail(ctx context.Context, versionID string) (*ReviewDetail, error) {
+	var resp DataResponse[ReviewDetail]
+	if err := c.get(ctx, fmt.Sprintf("/appStoreVersions/%s/appStoreReviewDetail", versionID), &resp); err != nil {
+		return nil, err
+	}
+	if resp.Data.ID == "" {
+		return nil, nil
+	}
+	return &resp.Data, nil
+}
+
+// GetReviewAttachments fetches the files attached to App Review information.
+func (c *Client) GetReviewAttachments(ctx context.Context, reviewDetailID string) ([]ReviewAttachment, error) {
+	var resp ListResponse[ReviewAttachment]
+	if err := c.get(ctx, fmt.Sprintf("/appStoreReviewDetails/%s/appStoreReviewAttachments", reviewDetailID), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
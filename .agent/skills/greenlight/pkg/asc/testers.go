@@ -0,0 +1,137 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BetaTester is a TestFlight tester.
+type BetaTester struct {
+	ID         string               `json:"id"`
+	Attributes BetaTesterAttributes `json:"attributes"`
+}
+
+type BetaTesterAttributes struct {
+	FirstName  string `json:"firstName"`
+	LastName   string `json:"lastName"`
+	Email      string `json:"email"`
+	InviteType string `json:"inviteType"` // EMAIL, PUBLIC_LINK
+	State      string `json:"state"`      // NOT_INVITED, INVITED, ACCEPTED, INSTALLED, REVOKED
+}
+
+type pagedResponse[T any] struct {
+	Data  []T `json:"data"`
+	Links struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+// ListBetaTesters lists the app's testers, or only a group's when groupID
+// is set.
+func (c *Client) ListBetaTesters(ctx context.Context, appID, groupID string) ([]BetaTester, error) {
+	path := "/betaTesters?limit=200&filter[apps]=" + url.QueryEscape(appID)
+	if groupID != "" {
+		path = "/betaGroups/" + groupID + "/betaTesters?limit=200"
+	}
+	var testers []BetaTester
+	for path != "" {
+		var resp pagedResponse[BetaTester]
+		if err := c.get(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+		testers = append(testers, resp.Data...)
+		path = strings.TrimPrefix(resp.Links.Next, c.baseURL)
+	}
+	return testers, nil
+}
+
+// FindBetaTester returns the app's tester with the given email, or nil.
+func (c *Client) FindBetaTester(ctx context.Context, appID, email string) (*BetaTester, error) {
+	path := fmt.Sprintf("/betaTesters?filter[apps]=%s&filter[email]=%s&limit=1", url.QueryEscape(appID), url.QueryEscape(email))
+	var resp ListResponse[BetaTester]
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, nil
+	}
+	return &resp.Data[0], nil
+}
+
+// CreateBetaTester creates a tester in the given groups, which sends the
+// TestFlight invitation.
+func (c *Client) CreateBetaTester(ctx context.Context, email, firstName, lastName string, groupIDs []string) (*BetaTester, error) {
+	var groups []resourceRef
+	for _, id := range groupIDs {
+		groups = append(groups, resourceRef{Type: "betaGroups", ID: id})
+	}
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "betaTesters",
+			"attributes": map[string]interface{}{
+				"email":     email,
+				"firstName": firstName,
+				"lastName":  lastName,
+			},
+			"relationships": map[string]interface{}{
+				"betaGroups": map[string]interface{}{"data": groups},
+			},
+		},
+	}
+	var resp DataResponse[BetaTester]
+	if err := c.post(ctx, "/betaTesters", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+func testerRefs(testerIDs []string) map[string]interface{} {
+	var refs []resourceRef
+	for _, id := range testerIDs {
+		refs = append(refs, resourceRef{Type: "betaTesters", ID: id})
+	}
+	return map[string]interface{}{"data": refs}
+}
+
+// AddBetaTestersToGroup adds existing testers to a group.
+func (c *Client) AddBetaTestersToGroup(ctx context.Context, groupID string, testerIDs []string) error {
+	return c.post(ctx, "/betaGroups/"+groupID+"/relationships/betaTesters", testerRefs(testerIDs), nil)
+}
+
+// RemoveBetaTestersFromGroup removes testers from a group; they keep access
+// through other groups.
+func (c *Client) RemoveBetaTestersFromGroup(ctx context.Context, groupID string, testerIDs []string) error {
+	return c.delete(ctx, "/betaGroups/"+groupID+"/relationships/betaTesters", testerRefs(testerIDs))
+}
+
+// RemoveBetaTestersFromApp removes testers from every group of the app and
+// revokes their access.
+func (c *Client) RemoveBetaTestersFromApp(ctx context.Context, appID string, testerIDs []string) error {
+	return c.delete(ctx, "/apps/"+appID+"/relationships/betaTesters", testerRefs(testerIDs))
+}
+
+// SetBetaGroupPublicLink enables or disables a group's public TestFlight
+// link. limit caps the number of testers who can join (0 for no limit).
+func (c *Client) SetBetaGroupPublicLink(ctx context.Context, groupID string, enabled bool, limit int) (*BetaGroup, error) {
+	attrs := map[string]interface{}{
+		"publicLinkEnabled":      enabled,
+		"publicLinkLimitEnabled": limit > 0,
+	}
+	if limit > 0 {
+		attrs["publicLinkLimit"] = limit
+	}
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type":       "betaGroups",
+			"id":         groupID,
+			"attributes": attrs,
+		},
+	}
+	var resp DataResponse[BetaGroup]
+	if err := c.patch(ctx, "/betaGroups/"+groupID, body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
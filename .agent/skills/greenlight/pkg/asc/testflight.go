@@ -0,0 +1,97 @@
+package asc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// resourceRef identifies a resource in relationship bodies.
+type resourceRef struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// BetaAppReviewSubmission is a build's TestFlight (beta) review submission.
+type BetaAppReviewSubmission struct {
+	ID         string                            `json:"id"`
+	Attributes BetaAppReviewSubmissionAttributes `json:"attributes"`
+}
+
+type BetaAppReviewSubmissionAttributes struct {
+	BetaReviewState string `json:"betaReviewState"` // WAITING_FOR_REVIEW, IN_REVIEW, REJECTED, APPROVED
+	SubmittedDate   string `json:"submittedDate"`
+}
+
+// GetBuild fetches the build with the given build number (CFBundleVersion),
+// or the most recent upload if number is empty.
+func (c *Client) GetBuild(ctx context.Context, appID, number string) (*Build, error) {
+	path := fmt.Sprintf("/builds?filter[app]=%s&sort=-uploadedDate&limit=1", url.QueryEscape(appID))
+	if number != "" {
+		path += "&filter[version]=" + url.QueryEscape(number)
+	}
+	var resp ListResponse[Build]
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		if number == "" {
+			return nil, fmt.Errorf("no builds uploaded for app %s", appID)
+		}
+		return nil, fmt.Errorf("build %s not found for app %s", number, appID)
+	}
+	return &resp.Data[0], nil
+}
+
+// SetBuildEncryption answers the export compliance question for a build.
+func (c *Client) SetBuildEncryption(ctx context.Context, buildID string, usesNonExemptEncryption bool) error {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "builds",
+			"id":   buildID,
+			"attributes": map[string]interface{}{
+				"usesNonExemptEncryption": usesNonExemptEncryption,
+			},
+		},
+	}
+	return c.patch(ctx, "/builds/"+buildID, body, nil)
+}
+
+// AddBuildToBetaGroups makes a build available to the given beta groups.
+func (c *Client) AddBuildToBetaGroups(ctx context.Context, buildID string, groupIDs []string) error {
+	var refs []resourceRef
+	for _, id := range groupIDs {
+		refs = append(refs, resourceRef{Type: "betaGroups", ID: id})
+	}
+	body := map[string]interface{}{"data": refs}
+	return c.post(ctx, "/builds/"+buildID+"/relationships/betaGroups", body, nil)
+}
+
+// GetBetaAppReviewSubmission returns the build's beta review submission, or
+// nil if it has not been submitted.
+func (c *Client) GetBetaAppReviewSubmission(ctx context.Context, buildID string) (*BetaAppReviewSubmission, error) {
+	var resp DataResponse[*BetaAppReviewSubmission]
+	if err := c.get(ctx, "/builds/"+buildID+"/betaAppReviewSubmission", &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// SubmitForBetaReview submits a build for TestFlight external testing review.
+func (c *Client) SubmitForBetaReview(ctx context.Context, buildID string) (*BetaAppReviewSubmission, error) {
+	body := map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "betaAppReviewSubmissions",
+			"relationships": map[string]interface{}{
+				"build": map[string]interface{}{
+					"data": resourceRef{Type: "builds", ID: buildID},
+				},
+			},
+		},
+	}
+	var resp DataResponse[BetaAppReviewSubmission]
+	if err := c.post(ctx, "/betaAppReviewSubmissions", body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
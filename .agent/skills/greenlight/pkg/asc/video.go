@@ -0,0 +1,186 @@
+package asc
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// VideoInfo is the duration and frame size of a video.
+type VideoInfo struct {
+	Duration time.Duration
+	Width    int
+	Height   int
+}
+
+// maxMoovSize bounds the metadata box read from a preview video.
+const maxMoovSize = 16 << 20
+
+// ProbeVideo reads the duration and frame size of an MP4/MOV video at url.
+// Only the metadata box is downloaded, using HTTP range requests, so large
+// previews are not fetched in full.
+func (c *Client) ProbeVideo(ctx context.Context, url string) (*VideoInfo, error) {
+	var off int64
+	for i := 0; i < 64; i++ {
+		hdr, err := c.fetchRange(ctx, url, off, 16)
+		if err != nil {
+			return nil, err
+		}
+		if len(hdr) < 8 {
+			break
+		}
+		size := int64(binary.BigEndian.Uint32(hdr[0:4]))
+		typ := string(hdr[4:8])
+		hdrLen := int64(8)
+		switch size {
+		case 0: // box extends to end of file
+			if typ != "moov" {
+				return nil, fmt.Errorf("no moov box in video")
+			}
+			size = maxMoovSize
+		case 1:
+			if len(hdr) < 16 {
+				return nil, fmt.Errorf("truncated box header")
+			}
+			size = int64(binary.BigEndian.Uint64(hdr[8:16]))
+			hdrLen = 16
+		}
+		if size < hdrLen {
+			return nil, fmt.Errorf("invalid %q box size %d", typ, size)
+		}
+		if typ == "moov" {
+			if size > maxMoovSize {
+				return nil, fmt.Errorf("moov box too large (%d bytes)", size)
+			}
+			body, err := c.fetchRange(ctx, url, off+hdrLen, size-hdrLen)
+			if err != nil {
+				return nil, err
+			}
+			return parseMoov(body)
+		}
+		off += size
+	}
+	return nil, fmt.Errorf("no moov box in video")
+}
+
+// fetchRange downloads n bytes at off. Servers that ignore the Range header
+// are read only up to n bytes.
+func (c *Client) fetchRange(ctx context.Context, url string, off, n int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", off, off+n-1))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		if _, err := io.CopyN(io.Discard, resp.Body, off); err != nil {
+			return nil, err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("video download failed (%d)", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, n))
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// parseMoov reads the movie header duration and the first track with a
+// frame size.
+func parseMoov(moov []byte) (*VideoInfo, error) {
+	info := &VideoInfo{}
+	found := false
+	walkBoxes(moov, func(typ string, body []byte) {
+		switch typ {
+		case "mvhd":
+			if d, ok := mvhdDuration(body); ok {
+				info.Duration = d
+				found = true
+			}
+		case "trak":
+			if info.Width != 0 {
+				return
+			}
+			walkBoxes(body, func(typ string, body []byte) {
+				if typ == "tkhd" {
+					info.Width, info.Height = tkhdSize(body)
+				}
+			})
+		}
+	})
+	if !found {
+		return nil, fmt.Errorf("no movie header in video")
+	}
+	return info, nil
+}
+
+func walkBoxes(data []byte, fn func(typ string, body []byte)) {
+	for len(data) >= 8 {
+		size := int(binary.BigEndian.Uint32(data[0:4]))
+		typ := string(data[4:8])
+		hdrLen := 8
+		if size == 1 && len(data) >= 16 {
+			size = int(binary.BigEndian.Uint64(data[8:16]))
+			hdrLen = 16
+		} else if size == 0 {
+			size = len(data)
+		}
+		if size < hdrLen || size > len(data) {
+			return
+		}
+		fn(typ, data[hdrLen:size])
+		data = data[size:]
+	}
+}
+
+func mvhdDuration(b []byte) (time.Duration, bool) {
+	if len(b) < 1 {
+		return 0, false
+	}
+	var timescale, duration uint64
+	if b[0] == 1 {
+		if len(b) < 32 {
+			return 0, false
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[20:24]))
+		duration = binary.BigEndian.Uint64(b[24:32])
+	} else {
+		if len(b) < 20 {
+			return 0, false
+		}
+		timescale = uint64(binary.BigEndian.Uint32(b[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(b[16:20]))
+	}
+	if timescale == 0 {
+		return 0, false
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second)), true
+}
+
+// tkhdSize returns a track's width and height (16.16 fixed point in the
+// header); audio tracks are 0x0.
+func tkhdSize(b []byte) (int, int) {
+	off := 76 // version 0: 4 flags + 20 times/ids + 8 reserved + 8 layer/group/volume + 36 matrix
+	if len(b) > 0 && b[0] == 1 {
+		off = 88
+	}
+	if len(b) < off+8 {
+		return 0, 0
+	}
+	w := binary.BigEndian.Uint32(b[off : off+4])
+	h := binary.BigEndian.Uint32(b[off+4 : off+8])
+	return int(w >> 16), int(h >> 16)
+}
@@ -0,0 +1,186 @@
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+var (
+	// Native payment request construction (Swift/ObjC) and the JS bridges.
+	applePayRequestPattern = regexp.MustCompile(`(\bPKPaymentRequest\s*\(|\[\s*PKPaymentRequest\s+new\s*\]|\[\s*\[\s*PKPaymentRequest\s+alloc\s*\]|presentApplePay\s*\(|confirmPlatformPayPayment\s*\()`)
+	applePayUsagePattern   = regexp.MustCompile(`(\bPKPaymentRequest\b|\bPKPaymentAuthorizationController\b|\bPKPaymentAuthorizationViewController\b|\bPKPaymentButton\b|PayWithApplePayButton|presentApplePay|confirmPlatformPayPayment|\bApplePaySession\b)`)
+
+	// Required PKPaymentRequest properties (Swift/ObjC and the common JS
+	// bridge option names).
+	applePayNetworksPattern     = regexp.MustCompile(`(supportedNetworks|paymentNetworks)`)
+	applePayCapabilitiesPattern = regexp.MustCompile(`(merchantCapabilities|merchantCapability|capability3DS|threeDSecure|supports3DS)`)
+	applePayCountryPattern      = regexp.MustCompile(`(countryCode|merchantCountryCode)`)
+
+	// Apple Pay on the web inside the app (WKWebView) needs the merchant
+	// domain verified with Apple.
+	applePayJSPattern = regexp.MustCompile(`\bApplePaySession\b`)
+
+	// Summary item labels and button copy for digital goods.
+	applePaySummaryLabelPattern = regexp.MustCompile(`(PKPaymentSummaryItem\s*\(\s*label\s*:\s*"([^"]+)"|summaryItemWithLabel\s*:\s*@"([^"]+)"|label\s*:\s*['"]([^'"]+)['"]\s*,\s*amount)`)
+	digitalGoodsPattern         = regexp.MustCompile(`(?i)\b(subscription|premium|pro (plan|upgrade|version)|upgrade to pro|unlock|ad[- ]?free|remove ads|coins?|gems|credits|tokens|lives|in-game|level pack|e-?book|digital (download|content))\b`)
+)
+
+const merchantDomainAssociation = "apple-developer-merchantid-domain-association"
+
+// ApplePayRule checks Apple Pay integrations: payment requests without
+// networks, merchant capabilities or country, Apple Pay JS without a merchant
+// domain verification file, and Apple Pay charges for digital content, which
+// must use In-App Purchase.
+type ApplePayRule struct {
+	id string
+}
+
+func (r *ApplePayRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript":
+		return true
+	}
+	return false
+}
+
+func (r *ApplePayRule) Check(fc FileContext) []Finding { return nil }
+
+// applePayEvidence is what one file contributes to ApplePayRule: its own
+// findings, and its first Apple Pay JS use with the project root to look for
+// the merchant domain file in.
+type applePayEvidence struct {
+	findings []Finding
+	jsHit    *Finding
+	root     string
+}
+
+func (r *ApplePayRule) Collect(fc FileContext) any {
+	var findings []Finding
+	var jsHit *Finding
+	var request *Finding
+	var networks, capabilities, country bool
+	for i := range fc.Lines {
+		line := fc.codeLine(i)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		if request == nil && applePayRequestPattern.MatchString(line) {
+			request = &Finding{File: fc.RelPath, Line: i + 1, Code: trimmed}
+		}
+		networks = networks || applePayNetworksPattern.MatchString(line)
+		capabilities = capabilities || applePayCapabilitiesPattern.MatchString(line)
+		country = country || applePayCountryPattern.MatchString(line)
+		if jsHit == nil && applePayJSPattern.MatchString(line) {
+			jsHit = &Finding{File: fc.RelPath, Line: i + 1, Code: trimmed}
+		}
+
+		if m := applePaySummaryLabelPattern.FindStringSubmatch(line); m != nil {
+			label := m[2] + m[3] + m[4]
+			if digitalGoodsPattern.MatchString(label) && fileUsesApplePay(fc) {
+				findings = append(findings, Finding{
+					Severity:  SeverityCritical,
+					Guideline: "3.1.1",
+					Title:     "Apple Pay used to sell digital content",
+					Detail:    "The payment summary item \"" + label + "\" looks like digital content (subscriptions, premium features, virtual currency). Apple Pay is for physical goods and services; digital content unlocked in the app must use In-App Purchase.",
+					Fix:       "Sell the digital item through StoreKit In-App Purchase and keep Apple Pay for physical goods and real-world services.",
+					File:      fc.RelPath,
+					Line:      i + 1,
+					Code:      trimmed,
+				})
+			}
+		}
+	}
+
+	if request != nil {
+		// The JS bridges (Stripe, react-native-payments) require the
+		// country but fill in networks and capabilities themselves.
+		native := fc.Language == "swift" || fc.Language == "objc"
+		var missing []string
+		if native && !networks {
+			missing = append(missing, "supportedNetworks")
+		}
+		if native && !capabilities {
+			missing = append(missing, "merchantCapabilities")
+		}
+		if !country {
+			missing = append(missing, "countryCode")
+		}
+		if len(missing) > 0 {
+			f := *request
+			f.Severity = SeverityWarn
+			f.Guideline = "2.1"
+			f.Title = "Apple Pay payment request missing " + strings.Join(missing, ", ")
+			f.Detail = "A payment request without supported networks, a 3-D Secure merchant capability or a merchant country fails to present or is declined — reviewers testing checkout see the Apple Pay sheet error out."
+			f.Fix = "Set supportedNetworks (e.g. [.visa, .masterCard, .amex]), merchantCapabilities = .threeDSecure, countryCode and currencyCode on the request."
+			findings = append(findings, f)
+		}
+	}
+
+	if findings == nil && jsHit == nil {
+		return nil
+	}
+	return &applePayEvidence{findings: findings, jsHit: jsHit, root: strings.TrimSuffix(fc.Path, fc.RelPath)}
+}
+
+func (r *ApplePayRule) CheckProject(evidence []any) []Finding {
+	var findings []Finding
+	var jsHit *Finding
+	root := ""
+	for _, ev := range evidence {
+		ev := ev.(*applePayEvidence)
+		findings = append(findings, ev.findings...)
+		if jsHit == nil && ev.jsHit != nil {
+			jsHit, root = ev.jsHit, ev.root
+		}
+	}
+
+	if jsHit != nil && root != "" && !hasMerchantDomainAssociation(root) {
+		f := *jsHit
+		f.Severity = SeverityWarn
+		f.Guideline = "2.1"
+		f.Title = "Apple Pay on the web without a merchant domain verification file"
+		f.Detail = "ApplePaySession only works on domains registered and verified for your merchant ID, which requires serving /.well-known/" + merchantDomainAssociation + " from each domain. No such file was found in the project."
+		f.Fix = "Download the domain association file from the merchant ID in the Developer portal (or your payment provider), serve it at /.well-known/" + merchantDomainAssociation + ", and verify the domain."
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+func fileUsesApplePay(fc FileContext) bool {
+	for _, line := range fc.Lines {
+		if applePayUsagePattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasMerchantDomainAssociation reports whether the project ships an Apple Pay
+// merchant domain verification file (usually served by the web frontend).
+func hasMerchantDomainAssociation(root string) bool {
+	found := false
+	sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if found {
+			return filepath.SkipAll
+		}
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skippedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(info.Name(), merchantDomainAssociation) {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
@@ -0,0 +1,229 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/codescan/swiftsyntax"
+)
+
+var (
+	// Ad, attribution, and tracking SDK start-up calls.
+	trackingInitPattern = regexp.MustCompile(`(AppsFlyerLib\.shared\(\)\.start|appsFlyer\.initSdk|Adjust\.appDidLaunch|Adjust\.initSdk|Adjust\.create|ApplicationDelegate\.shared\.application\(|Settings\.initializeSDK|FBSDKApplicationDelegate|GADMobileAds\.sharedInstance\(\)\.start|mobileAds\(\)\.initialize|ALSdk\.shared|AppLovinSdk\.initialize|IronSource\.initWithAppKey|UnityAds\.initialize|Branch\.getInstance\(\)\.initSession|branch\.subscribe|Mixpanel\.initialize|mixpanel\.init|Amplitude\.instance\(\)\.initialize|amplitude\.init|Singular\.start|Kochava)`)
+
+	// The ATT prompt itself.
+	attRequestPattern = regexp.MustCompile(`(requestTrackingAuthorization|requestTrackingPermissionsAsync|requestTrackingPermission\(|requestTrackingAuthorizationStatus)`)
+
+	// SDK settings that defer tracking until the user answers the prompt.
+	attDeferralPattern = regexp.MustCompile(`(waitForATTUserAuthorization|timeToWaitForATTUserAuthorization|isAdvertiserTrackingEnabled|setAdvertiserTrackingEnabled|AdvertiserTrackingEnabled|setAttributionDelay|delayed_start|delayStart)`)
+
+	// IDFA reads and the authorization checks that should guard them.
+	idfaReadPattern     = regexp.MustCompile(`(advertisingIdentifier|ASIdentifierManager|getAdvertisingId\(|getAdvertisingIdAsync|IDFA\.getIDFA|getIDFA\()`)
+	attStatusGuardRegex = regexp.MustCompile(`(trackingAuthorizationStatus|\.authorized\b|ATTrackingManager\.AuthorizationStatus|status\s*===?\s*['"](granted|authorized)['"]|granted\b|getTrackingPermissionsAsync|getTrackingStatus)`)
+
+	// Files on the app launch path.
+	launchPathPattern = regexp.MustCompile(`(didFinishLaunchingWithOptions|@main\b|@UIApplicationMain|AppRegistry\.registerComponent|registerRootComponent)`)
+)
+
+// ATTTimingRule goes beyond detecting ATT: it checks that tracking SDKs start
+// after the ATT prompt on the launch path, and that IDFA reads are guarded by
+// the authorization status — the ordering Apple actually rejects on.
+type ATTTimingRule struct {
+	id string
+}
+
+func (r *ATTTimingRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript":
+		return true
+	}
+	return false
+}
+
+func (r *ATTTimingRule) Check(fc FileContext) []Finding { return nil }
+
+// attEvidence is what one file contributes to ATTTimingRule: whether it
+// requests ATT, its own findings, and findings that only hold when the
+// project requests ATT somewhere.
+type attEvidence struct {
+	hasATT   bool
+	findings []Finding
+	ifATT    []Finding
+}
+
+func (r *ATTTimingRule) Collect(fc FileContext) any {
+	ev := r.checkFile(fc)
+	for _, line := range fc.Lines {
+		if attRequestPattern.MatchString(line) {
+			ev.hasATT = true
+			break
+		}
+	}
+	if !ev.hasATT && ev.findings == nil && ev.ifATT == nil {
+		return nil
+	}
+	return ev
+}
+
+func (r *ATTTimingRule) CheckProject(evidence []any) []Finding {
+	hasATT := false
+	for _, ev := range evidence {
+		hasATT = hasATT || ev.(*attEvidence).hasATT
+	}
+
+	var findings []Finding
+	for _, ev := range evidence {
+		ev := ev.(*attEvidence)
+		findings = append(findings, ev.findings...)
+		if hasATT {
+			findings = append(findings, ev.ifATT...)
+		}
+	}
+	return findings
+}
+
+func (r *ATTTimingRule) checkFile(fc FileContext) *attEvidence {
+	var (
+		ev       = &attEvidence{}
+		initLine int
+		initCode string
+		attLine  int
+		deferred bool
+		isLaunch = isLaunchFile(fc)
+		hasGuard bool
+		idfaLine int
+		idfaCode string
+	)
+
+	for lineNum := range fc.Lines {
+		line := fc.codeLine(lineNum)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		if initLine == 0 && trackingInitPattern.MatchString(line) {
+			initLine, initCode = lineNum+1, trimmed
+		}
+		if attLine == 0 && attRequestPattern.MatchString(line) {
+			attLine = lineNum + 1
+		}
+		if attDeferralPattern.MatchString(line) {
+			deferred = true
+		}
+		if attStatusGuardRegex.MatchString(line) {
+			hasGuard = true
+		}
+		if idfaLine == 0 && idfaReadPattern.MatchString(line) {
+			idfaLine, idfaCode = lineNum+1, trimmed
+		}
+	}
+
+	// With syntax, ordering follows the call structure: the prompt is
+	// asynchronous, so only SDK starts inside its completion handler (or after
+	// an awaited request) wait for the user's answer.
+	if fc.Syntax != nil && initLine > 0 && attLine > 0 && !deferred {
+		if call, ok := attUnorderedInit(fc.Syntax); ok {
+			if call.Line > attLine {
+				ev.findings = append(ev.findings, Finding{
+					Severity:  SeverityWarn,
+					Guideline: "5.1.2",
+					Title:     "Tracking SDK initialized outside the ATT completion handler",
+					Detail:    "requestTrackingAuthorization returns immediately and reports the user's choice later, so an SDK started after the call but outside its completion handler still runs before the user has answered.",
+					Fix:       "Move the SDK start into the requestTrackingAuthorization completion handler, or await the async variant first.",
+					File:      fc.RelPath,
+					Line:      call.Line,
+					Code:      strings.TrimSpace(fc.Lines[call.Line-1]),
+				})
+				initLine = 0
+			} else {
+				initLine, initCode = call.Line, strings.TrimSpace(fc.Lines[call.Line-1])
+			}
+		} else {
+			initLine = 0
+		}
+	}
+
+	// Tracking SDK started before the prompt in the same file, or at launch
+	// while the prompt lives elsewhere (and therefore runs later).
+	if initLine > 0 && !deferred {
+		switch {
+		case attLine > 0 && initLine < attLine:
+			ev.ifATT = append(ev.ifATT, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "5.1.2",
+				Title:     "Tracking SDK initialized before ATT prompt",
+				Detail:    "The SDK starts before requestTrackingAuthorization is called, so it can collect identifiers before the user has answered. Reviewers reject apps that track before consent.",
+				Fix:       "Start the SDK inside the requestTrackingAuthorization completion handler, or enable the SDK's wait-for-ATT option.",
+				File:      fc.RelPath,
+				Line:      initLine,
+				Code:      initCode,
+			})
+		case attLine == 0 && isLaunch:
+			ev.ifATT = append(ev.ifATT, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "5.1.2",
+				Title:     "Tracking SDK initialized at launch, ATT requested later",
+				Detail:    "This launch-path file starts a tracking SDK, but the ATT prompt is requested in a different file — typically after launch. The SDK may track before consent.",
+				Fix:       "Request ATT before starting the SDK, or configure the SDK to wait for the ATT result (e.g. waitForATTUserAuthorization).",
+				File:      fc.RelPath,
+				Line:      initLine,
+				Code:      initCode,
+			})
+		}
+	}
+
+	if idfaLine > 0 && !hasGuard {
+		ev.findings = append(ev.findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "5.1.2",
+			Title:     "IDFA read without checking ATT authorization status",
+			Detail:    "The advertising identifier is read without checking trackingAuthorizationStatus. When the user denies tracking the IDFA is all zeros, and reading it before authorization is treated as tracking without consent.",
+			Fix:       "Read the IDFA only when ATTrackingManager.trackingAuthorizationStatus == .authorized.",
+			File:      fc.RelPath,
+			Line:      idfaLine,
+			Code:      idfaCode,
+		})
+	}
+
+	return ev
+}
+
+// attUnorderedInit returns the first tracking SDK start that is not ordered
+// after the ATT prompt: neither inside a requestTrackingAuthorization call's
+// arguments or trailing closure, nor after an awaited request.
+func attUnorderedInit(syn *swiftsyntax.File) (swiftsyntax.Call, bool) {
+	requests := syn.CallsMatching("requestTrackingAuthorization")
+	for _, c := range syn.Calls {
+		if !trackingInitPattern.MatchString(c.Callee + "(") {
+			continue
+		}
+		ordered := false
+		for _, req := range requests {
+			if req.Contains(c.Start) || (req.Awaited && req.End <= c.Start) {
+				ordered = true
+				break
+			}
+		}
+		if !ordered {
+			return c, true
+		}
+	}
+	return swiftsyntax.Call{}, false
+}
+
+func isLaunchFile(fc FileContext) bool {
+	base := strings.ToLower(fc.RelPath)
+	if i := strings.LastIndexAny(base, `/\`); i >= 0 {
+		base = base[i+1:]
+	}
+	switch base {
+	case "appdelegate.swift", "appdelegate.m", "app.tsx", "app.js", "app.jsx", "app.ts", "index.js", "index.ts", "_layout.tsx", "_layout.js":
+		return true
+	}
+	for _, line := range fc.Lines {
+		if launchPathPattern.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
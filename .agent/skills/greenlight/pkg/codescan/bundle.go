@@ -0,0 +1,193 @@
+package codescan
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hermesMagic starts every Hermes bytecode bundle. Strings in the bytecode
+// string table are still plain text, so the same patterns apply, but line
+// numbers are meaningless.
+var hermesMagic = []byte{0xc6, 0x1f, 0xbc, 0x03, 0xc1, 0x03, 0x19, 0x1f}
+
+// bundleCheck is one pattern searched for in a shipped JavaScript bundle.
+type bundleCheck struct {
+	severity  Severity
+	guideline string
+	title     string
+	detail    string
+	fix       string
+	pattern   *regexp.Regexp
+	skip      *regexp.Regexp // matches that are benign
+}
+
+var bundleChecks = []bundleCheck{
+	{
+		severity:  SeverityCritical,
+		guideline: "2.1",
+		title:     "Development build of the JS bundle shipped",
+		detail:    "The bundle was built with __DEV__ enabled. Development bundles are slow, show red-box errors and the developer menu, and reviewers reject them as incomplete.",
+		fix:       "Build the bundle with --dev false (the Release configuration does this by default).",
+		pattern:   regexp.MustCompile(`__DEV__\s*=\s*(?:true|!0)\b`),
+	},
+	{
+		severity:  SeverityWarn,
+		guideline: "2.1",
+		title:     "Developer tooling left in the JS bundle",
+		detail:    "The shipped bundle includes developer-menu or debugging tooling. If it is reachable in release builds (e.g. via shake gesture) reviewers treat it as unfinished or hidden functionality.",
+		fix:       "Guard developer tooling with __DEV__ or remove it from release builds.",
+		pattern:   regexp.MustCompile(`(DevSettings\.addMenuItem|NativeModules\.DevMenu|RCTDevMenu|react-native-flipper|Reactotron\w*\.configure|react-native-debugger)`),
+	},
+	{
+		severity:  SeverityWarn,
+		guideline: "2.1",
+		title:     "Development server URL in the JS bundle",
+		detail:    "The shipped bundle contains a localhost or private-network endpoint. Requests to it fail on reviewers' devices, which usually shows up as a broken feature or crash.",
+		fix:       "Read endpoints from build-time config so release bundles only contain production URLs.",
+		pattern:   regexp.MustCompile(`https?://(?:localhost|127\.0\.0\.1|0\.0\.0\.0|10\.\d{1,3}\.\d{1,3}\.\d{1,3}|192\.168\.\d{1,3}\.\d{1,3}|[\w-]+\.local)(?::\d+)?[^\s"'` + "`" + `]*`),
+	},
+	{
+		severity:  SeverityInfo,
+		guideline: "2.1",
+		title:     "Staging or test endpoint in the JS bundle",
+		detail:    "The shipped bundle references what looks like a non-production backend. Make sure review builds talk to production.",
+		fix:       "Confirm the release configuration points at production services.",
+		pattern:   regexp.MustCompile(`https?://[\w.-]*\b(?:staging|stage|qa|uat|dev)\b[\w.-]*\.[a-z]{2,}[^\s"'` + "`" + `]*`),
+	},
+	{
+		severity:  SeverityInfo,
+		guideline: "1.6",
+		title:     "Insecure HTTP URL in the JS bundle",
+		detail:    "App Transport Security blocks plain HTTP by default, so this request will fail unless an ATS exception is declared.",
+		fix:       "Use HTTPS.",
+		pattern:   regexp.MustCompile(`http://[\w.-]+\.[a-z]{2,}[^\s"'` + "`" + `]*`),
+		skip:      regexp.MustCompile(`http://(www\.w3\.org|schemas\.|ns\.adobe\.com|purl\.org|www\.apple\.com/DTDs|xmlns\.|localhost|[\w-]+\.local)`),
+	},
+	{
+		severity:  SeverityCritical,
+		guideline: "2.5.1",
+		title:     "Private API reference in the JS bundle",
+		detail:    "A dependency in the shipped bundle references a private framework or class. Private API use is rejected even when it comes from a third-party package.",
+		fix:       "Find the package that injects it (search node_modules) and replace or patch it.",
+		pattern:   regexp.MustCompile(`(/System/Library/PrivateFrameworks/[\w./]+|\bLSApplicationWorkspace\b|\bUIKeyboardImpl\b|\bSBApplication\b|\bNativeModules\._\w+)`),
+	},
+}
+
+// JSBundleRule scans React Native bundles (main.jsbundle, index.ios.bundle)
+// found in build output, catching what npm dependencies inject into the
+// shipped code that source scanning never sees.
+type JSBundleRule struct {
+	id string
+}
+
+func (r *JSBundleRule) Applies(fc FileContext) bool {
+	return fc.Language == "jsbundle"
+}
+
+func (r *JSBundleRule) Check(fc FileContext) []Finding {
+	return ScanBundle(fc.RelPath, []byte(strings.Join(fc.Lines, "\n")))
+}
+
+// IsJSBundle reports whether a file name is a React Native release bundle.
+func IsJSBundle(name string) bool {
+	base := strings.ToLower(filepath.Base(name))
+	return strings.HasSuffix(base, ".jsbundle") || base == "index.ios.bundle" || base == "main.ios.bundle"
+}
+
+// ScanBundle checks the contents of a JS or Hermes bundle. Each distinct match
+// is reported once; Code holds a short excerpt around it.
+func ScanBundle(relPath string, data []byte) []Finding {
+	var (
+		findings []Finding
+		hermes   = bytes.HasPrefix(data, hermesMagic)
+		text     = string(data)
+		seen     = make(map[string]bool)
+	)
+
+	lineOf := func(offset int) int {
+		if hermes {
+			return 0
+		}
+		return strings.Count(text[:offset], "\n") + 1
+	}
+	note := ""
+	if hermes {
+		note = " (Hermes bytecode)"
+	}
+
+	for _, c := range bundleChecks {
+		for _, loc := range c.pattern.FindAllStringIndex(text, -1) {
+			match := text[loc[0]:loc[1]]
+			if c.skip != nil && c.skip.MatchString(match) {
+				continue
+			}
+			key := c.title + "\x00" + match
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			findings = append(findings, Finding{
+				Severity:  c.severity,
+				Guideline: c.guideline,
+				Title:     c.title,
+				Detail:    fmt.Sprintf("%s Found %q%s.", c.detail, match, note),
+				Fix:       c.fix,
+				File:      relPath,
+				Line:      lineOf(loc[0]),
+				Code:      bundleExcerpt(text, loc[0], loc[1]),
+			})
+		}
+	}
+
+	for _, p := range secretProviders {
+		for _, m := range p.pattern.FindAllStringSubmatchIndex(text, -1) {
+			secret := text[m[2]:m[3]]
+			if (p.valid != nil && !p.valid(secret)) || strings.Contains(strings.ToUpper(secret), "EXAMPLE") || seen[secret] {
+				continue
+			}
+			seen[secret] = true
+			findings = append(findings, Finding{
+				Severity:   p.severity,
+				Guideline:  "1.6",
+				Title:      "Hardcoded secret in the JS bundle: " + p.name,
+				Detail:     p.name + " found in the shipped bundle" + note + ". Anyone who downloads the app can extract it, including keys injected by dependencies or build-time env vars.",
+				Fix:        "Revoke and rotate this credential and keep server-side keys out of client env vars (EXPO_PUBLIC_*, react-native-config).",
+				File:       relPath,
+				Line:       lineOf(m[0]),
+				Code:       bundleExcerpt(text, m[0], m[1]),
+				Secret:     secret,
+				SecretType: p.name,
+			})
+		}
+	}
+
+	return findings
+}
+
+// bundleExcerpt returns the match with a little context, trimmed to
+// printable text so minified and bytecode bundles stay readable.
+func bundleExcerpt(text string, start, end int) string {
+	const context = 40
+	from, to := start-context, end+context
+	if from < 0 {
+		from = 0
+	}
+	if to > len(text) {
+		to = len(text)
+	}
+	if nl := strings.LastIndexByte(text[from:start], '\n'); nl >= 0 {
+		from += nl + 1
+	}
+	if nl := strings.IndexByte(text[end:to], '\n'); nl >= 0 {
+		to = end + nl
+	}
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == 0xfffd {
+			return ' '
+		}
+		return r
+	}, text[from:to]))
+}
@@ -0,0 +1,359 @@
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// Capability is an App ID capability backed by an entitlement, which
+// greenlight cross-checks against code usage and the Developer portal.
+type Capability struct {
+	Name        string
+	Entitlement string
+	// PortalType is the capability type of the App ID in the Developer portal.
+	PortalType string
+
+	// declared reports whether the entitlement values enable the capability.
+	declared func(values []string) bool
+	// usage matches code or dependencies that need the capability.
+	usage *regexp.Regexp
+	// idsEntitlement lists the identifiers (containers, merchant IDs) code
+	// may reference, matched by idUsage.
+	idsEntitlement string
+	idUsage        *regexp.Regexp
+	// missingDetail explains what breaks without the entitlement.
+	missingDetail string
+}
+
+// Capabilities are the capabilities checked for consistency.
+var Capabilities = []Capability{
+	{
+		Name:          "Game Center",
+		Entitlement:   "com.apple.developer.game-center",
+		PortalType:    "GAME_CENTER",
+		declared:      func(v []string) bool { return slices.Contains(v, "true") },
+		usage:         regexp.MustCompile(`(\bGKLocalPlayer\b|\bGKGameCenterViewController\b|\bGKLeaderboard\b|\bGKAchievement\b|\bGKAccessPoint\b|react-native-game-center|expo-game-center)`),
+		missingDetail: "GKLocalPlayer authentication fails without the Game Center entitlement, so leaderboards and achievements never load — reviewers see a broken feature.",
+	},
+	{
+		Name:        "CloudKit",
+		Entitlement: "com.apple.developer.icloud-services",
+		PortalType:  "ICLOUD",
+		declared: func(v []string) bool {
+			return slices.Contains(v, "CloudKit") || slices.Contains(v, "CloudKit-Anonymous")
+		},
+		usage:          regexp.MustCompile(`(\bCKContainer\b|\bCKDatabase\b|\bCKRecord\b|NSPersistentCloudKitContainer|cloudKitDatabase\s*:|react-native-cloud-store|expo-cloudkit)`),
+		idsEntitlement: "com.apple.developer.icloud-container-identifiers",
+		idUsage:        regexp.MustCompile(`["'](iCloud\.[A-Za-z0-9.\-]+)["']`),
+		missingDetail:  "CKContainer.default() traps at launch when the app has no CloudKit entitlement, and sync silently never starts for NSPersistentCloudKitContainer.",
+	},
+	{
+		Name:          "Sign in with Apple",
+		Entitlement:   "com.apple.developer.applesignin",
+		PortalType:    "APPLE_ID_AUTH",
+		declared:      func(v []string) bool { return slices.Contains(v, "Default") },
+		usage:         regexp.MustCompile(`(ASAuthorizationAppleIDProvider|SignInWithAppleButton|ASAuthorizationAppleIDButton|expo-apple-authentication|@invertase/react-native-apple-authentication|"usesAppleSignIn"\s*:\s*true)`),
+		missingDetail: "Without the entitlement, ASAuthorizationController fails with error 1000 as soon as the button is tapped — a common 2.1 rejection for login flows.",
+	},
+	{
+		Name:           "Apple Pay",
+		Entitlement:    "com.apple.developer.in-app-payments",
+		PortalType:     "APPLE_PAY",
+		declared:       func(v []string) bool { return true }, // merchant IDs checked separately
+		usage:          regexp.MustCompile(`(\bPKPaymentRequest\b|\bPKPaymentAuthorizationController\b|\bPKPaymentButton\b|PayWithApplePayButton|\bPKPaymentAuthorizationViewController\b|presentApplePay|confirmPlatformPayPayment|react-native-payments)`),
+		idsEntitlement: "com.apple.developer.in-app-payments",
+		idUsage:        regexp.MustCompile(`["'](merchant\.[A-Za-z0-9.\-]+)["']`),
+		missingDetail:  "PKPaymentAuthorizationController won't present without a merchant ID entitlement, so the Apple Pay button does nothing.",
+	},
+}
+
+var (
+	entitlementEntryRe = regexp.MustCompile(`(?s)<key>([^<]+)</key>\s*(<true\s*/>|<false\s*/>|<string>([^<]*)</string>|<array>(.*?)</array>|<array\s*/>)`)
+	plistStringRe      = regexp.MustCompile(`<string>([^<]*)</string>`)
+	// Expo config: ios.entitlements keys and ios.usesAppleSignIn.
+	expoEntitlementRe = regexp.MustCompile(`"(com\.apple\.developer\.[a-z\-.]+)"\s*:\s*(true|\[[^\]]*\]|"[^"]*")`)
+	expoAppleSignInRe = regexp.MustCompile(`"usesAppleSignIn"\s*:\s*true`)
+	jsonStringRe      = regexp.MustCompile(`"([^"]*)"`)
+)
+
+// parseEntitlements returns entitlement keys with their values: "true" for
+// booleans, and the strings of string and array values.
+func parseEntitlements(content string) map[string][]string {
+	ents := map[string][]string{}
+	for _, m := range entitlementEntryRe.FindAllStringSubmatch(content, -1) {
+		key := strings.TrimSpace(m[1])
+		switch {
+		case strings.HasPrefix(m[2], "<true"):
+			ents[key] = []string{"true"}
+		case strings.HasPrefix(m[2], "<false"):
+			ents[key] = nil
+		case strings.HasPrefix(m[2], "<string>"):
+			ents[key] = []string{strings.TrimSpace(m[3])}
+		default:
+			var values []string
+			for _, s := range plistStringRe.FindAllStringSubmatch(m[4], -1) {
+				values = append(values, strings.TrimSpace(s[1]))
+			}
+			ents[key] = values
+		}
+	}
+	return ents
+}
+
+// parseExpoEntitlements reads entitlements declared in an Expo config.
+func parseExpoEntitlements(content string) map[string][]string {
+	ents := map[string][]string{}
+	for _, m := range expoEntitlementRe.FindAllStringSubmatch(content, -1) {
+		if m[2] == "true" {
+			ents[m[1]] = []string{"true"}
+			continue
+		}
+		for _, s := range jsonStringRe.FindAllStringSubmatch(m[2], -1) {
+			ents[m[1]] = append(ents[m[1]], s[1])
+		}
+	}
+	if expoAppleSignInRe.MatchString(content) {
+		ents["com.apple.developer.applesignin"] = []string{"Default"}
+	}
+	return ents
+}
+
+func isExpoConfig(relPath string) bool {
+	switch strings.ToLower(filepath.Base(relPath)) {
+	case "app.json", "app.config.js", "app.config.ts":
+		return true
+	}
+	return false
+}
+
+// DeclaredCapability is a capability enabled by the project's entitlements.
+type DeclaredCapability struct {
+	Capability
+	File string
+	// Identifiers are the CloudKit containers or merchant IDs listed.
+	Identifiers []string
+}
+
+// DeclaredCapabilities reads the .entitlements files (and Expo config) under
+// projectPath and returns the checked capabilities they enable.
+func DeclaredCapabilities(projectPath string) []DeclaredCapability {
+	var files []FileContext
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skippedDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		if !strings.HasSuffix(path, ".entitlements") && !isExpoConfig(rel) {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, FileContext{Path: path, RelPath: rel, Lines: strings.Split(string(data), "\n")})
+		return nil
+	})
+	return declaredCapabilities(files)
+}
+
+func declaredCapabilities(files []FileContext) []DeclaredCapability {
+	var declared []DeclaredCapability
+	seen := map[string]int{}
+	for _, fc := range files {
+		var ents map[string][]string
+		switch {
+		case strings.HasSuffix(fc.RelPath, ".entitlements"):
+			ents = parseEntitlements(strings.Join(fc.Lines, "\n"))
+		case isExpoConfig(fc.RelPath):
+			ents = parseExpoEntitlements(strings.Join(fc.Lines, "\n"))
+		default:
+			continue
+		}
+		for _, c := range Capabilities {
+			values, ok := ents[c.Entitlement]
+			if !ok || !c.declared(values) {
+				continue
+			}
+			var ids []string
+			if c.idsEntitlement != "" {
+				ids = ents[c.idsEntitlement]
+			}
+			if i, ok := seen[c.Name]; ok {
+				declared[i].Identifiers = append(declared[i].Identifiers, ids...)
+				continue
+			}
+			seen[c.Name] = len(declared)
+			declared = append(declared, DeclaredCapability{Capability: c, File: fc.RelPath, Identifiers: ids})
+		}
+	}
+	return declared
+}
+
+// CapabilityRule cross-checks Game Center, CloudKit, Sign in with Apple and
+// Apple Pay entitlements against code usage: capabilities used without the
+// entitlement, entitlements nothing uses, and CloudKit containers or
+// merchant IDs referenced in code but missing from the entitlements.
+type CapabilityRule struct {
+	id string
+}
+
+func (r *CapabilityRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "json", "plist":
+		return true
+	}
+	return false
+}
+
+func (r *CapabilityRule) Check(fc FileContext) []Finding { return nil }
+
+// capabilityUsage is where code first uses a capability, and first
+// references each of its identifiers.
+type capabilityUsage struct {
+	first Finding
+	ids   map[string]Finding
+}
+
+// capabilityEvidence is what one file contributes to CapabilityRule: the
+// capabilities its entitlements declare and the ones its code uses.
+type capabilityEvidence struct {
+	declared []DeclaredCapability
+	used     map[string]*capabilityUsage
+}
+
+func (r *CapabilityRule) Collect(fc FileContext) any {
+	ev := &capabilityEvidence{declared: declaredCapabilities([]FileContext{fc}), used: map[string]*capabilityUsage{}}
+	if fc.Language != "plist" {
+		for i := range fc.Lines {
+			line := fc.codeLine(i)
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+				continue
+			}
+			for _, c := range Capabilities {
+				if fc.Language == "json" && isExpoConfig(fc.RelPath) && c.Name != "Sign in with Apple" {
+					continue
+				}
+				u := ev.used[c.Name]
+				if c.usage.MatchString(line) && u == nil {
+					u = &capabilityUsage{first: Finding{File: fc.RelPath, Line: i + 1, Code: trimmed}, ids: map[string]Finding{}}
+					ev.used[c.Name] = u
+				}
+				if c.idUsage == nil || fc.Language == "json" {
+					continue
+				}
+				for _, m := range c.idUsage.FindAllStringSubmatch(line, -1) {
+					if u == nil {
+						u = &capabilityUsage{first: Finding{File: fc.RelPath, Line: i + 1, Code: trimmed}, ids: map[string]Finding{}}
+						ev.used[c.Name] = u
+					}
+					if _, ok := u.ids[m[1]]; !ok {
+						u.ids[m[1]] = Finding{File: fc.RelPath, Line: i + 1, Code: trimmed}
+					}
+				}
+			}
+		}
+	}
+	if len(ev.declared) == 0 && len(ev.used) == 0 {
+		return nil
+	}
+	return ev
+}
+
+func (r *CapabilityRule) CheckProject(evidence []any) []Finding {
+	var findings []Finding
+	declared := map[string]DeclaredCapability{}
+	used := map[string]*capabilityUsage{}
+	for _, ev := range evidence {
+		ev := ev.(*capabilityEvidence)
+		for _, d := range ev.declared {
+			if prev, ok := declared[d.Name]; ok {
+				prev.Identifiers = append(prev.Identifiers, d.Identifiers...)
+				declared[d.Name] = prev
+				continue
+			}
+			declared[d.Name] = d
+		}
+		for name, fu := range ev.used {
+			u := used[name]
+			if u == nil {
+				used[name] = fu
+				continue
+			}
+			for id, f := range fu.ids {
+				if _, ok := u.ids[id]; !ok {
+					u.ids[id] = f
+				}
+			}
+		}
+	}
+
+	for _, c := range Capabilities {
+		d, isDeclared := declared[c.Name]
+		u := used[c.Name]
+		switch {
+		case u != nil && !isDeclared:
+			f := u.first
+			f.Severity = SeverityWarn
+			f.Guideline = "2.1"
+			f.Title = c.Name + " used without the " + c.Name + " entitlement"
+			f.Detail = c.missingDetail
+			f.Fix = "Enable " + c.Name + " under Signing & Capabilities (adds " + c.Entitlement + ") and on the App ID."
+			findings = append(findings, f)
+		case u == nil && isDeclared:
+			findings = append(findings, Finding{
+				Severity:  SeverityInfo,
+				Guideline: "2.1",
+				Title:     c.Name + " entitlement declared but never used",
+				Detail:    "The entitlements enable " + c.Name + " but no code or dependency uses it. Reviewers test declared capabilities (Game Center shows on the store listing), and unused ones raise questions.",
+				Fix:       "Remove the " + c.Name + " capability, or ship the feature that needs it.",
+				File:      d.File,
+			})
+		}
+
+		if u == nil || !isDeclared || c.idUsage == nil {
+			continue
+		}
+		if c.Name == "Apple Pay" && len(d.Identifiers) == 0 {
+			findings = append(findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "2.1",
+				Title:     "Apple Pay enabled without a merchant ID",
+				Detail:    "The in-app payments entitlement lists no merchant IDs, so payment requests fail.",
+				Fix:       "Select your merchant ID under Signing & Capabilities → Apple Pay.",
+				File:      d.File,
+			})
+			continue
+		}
+		var ids []string
+		for id := range u.ids {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if slices.Contains(d.Identifiers, id) {
+				continue
+			}
+			f := u.ids[id]
+			f.Severity = SeverityWarn
+			f.Guideline = "2.1"
+			f.Title = c.Name + " identifier " + id + " missing from entitlements"
+			f.Detail = "Code references " + id + ", but " + d.File + " only lists " + strings.Join(d.Identifiers, ", ") + ". Requests against it fail with a permission error in the store build."
+			f.Fix = "Add " + id + " to " + c.idsEntitlement + " (Signing & Capabilities) and to the App ID, or use a listed identifier."
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
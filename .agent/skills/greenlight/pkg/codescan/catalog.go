@@ -0,0 +1,425 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/config"
+)
+
+// RuleInfo describes a rule for `greenlight rules list/explain`.
+type RuleInfo struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Severity    Severity `json:"severity"`
+	Guideline   string   `json:"guideline"`
+	Languages   []string `json:"languages"`
+	Description string   `json:"description"`
+	Fix         string   `json:"fix,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	ProjectWide bool     `json:"project_wide,omitempty"`
+}
+
+// DescribedRule is implemented by every built-in rule.
+type DescribedRule interface {
+	Rule
+	Info() RuleInfo
+}
+
+// Catalog returns the description of every built-in rule in registration order.
+func Catalog() []RuleInfo {
+	var infos []RuleInfo
+	for _, r := range AllRules() {
+		if d, ok := r.(DescribedRule); ok {
+			infos = append(infos, d.Info())
+		}
+	}
+	return infos
+}
+
+// LookupRule returns the description of the rule with the given id.
+func LookupRule(id string) (RuleInfo, bool) {
+	for _, info := range Catalog() {
+		if info.ID == id {
+			return info, true
+		}
+	}
+	return RuleInfo{}, false
+}
+
+// ruleExamples are short snippets that trigger each rule.
+var ruleExamples = map[string][]string{
+	"private-api":              {`let sel = NSSelectorFromString("_setBackgroundStyle:")`, `NSSelectorFromString([NSString stringWithFormat:@"%@%@", @"_set", @"Style:"])`, `NSClassFromString(@"LSApplication" @"Workspace")`, `dlopen("/System/Library/PrivateFrameworks/...", RTLD_NOW)`},
+	"hardcoded-secrets":        {`let stripeKey = "sk_live_..."`, `const AWS_KEY = "AKIA..."`, `let clientSecret = "q8Zr3vLk9XpT2mWb7YcN"`},
+	"external-payment-digital": {`stripe.confirmPaymentIntent(...)  // unlocking premium content`},
+	"external-purchase-link":   {`ExternalPurchaseLink.open()  // without the StoreKit entitlement`},
+	"crypto-mining":            {`import CoinHive`, `startMining(threads: 4)`},
+	"crypto-features":          {`if ownsNFT { isPremium = true }`, `Button("Buy this NFT") { openURL(openSeaURL) }`, `import WalletConnectSwift`},
+	"dynamic-code-exec":        {`JSContext().evaluateScript(remoteCode)`, `eval(downloadedScript)`},
+	"ota-updates":              {`codePush.sync({ installMode: codePush.InstallMode.IMMEDIATE })`, `"updates": { "url": "http://updates.example.com/manifest" }`, `[JPEngine evaluateScript:patch]`},
+	"missing-att":              {`import FBSDKCoreKit  // and no requestTrackingAuthorization anywhere`},
+	"att-timing":               {`AppsFlyerLib.shared().start()  // before requestTrackingAuthorization`, `ASIdentifierManager.shared().advertisingIdentifier  // without a status check`},
+	"social-login-no-apple":    {`GIDSignIn.sharedInstance.signIn(...)  // and no ASAuthorizationAppleIDProvider`},
+	"iap-no-restore":           {`Product.purchase()  // and no restorePurchases / AppStore.sync`},
+	"subscription-paywall":     {`Button("Subscribe") { ... }  // no price, Terms of Use or privacy link`, `"Start your free trial"  // no trial length`},
+	"health-data":              {`let store = HKHealthStore()  // no NSHealthShareUsageDescription`, `CKContainer.default()  // in a file handling HKQuantitySample`},
+	"medical-disclaimer":       {`Text("Dosage calculator")`, `Text("Talk to a doctor in minutes")  // no "not for emergencies" text`},
+	"capability-mismatch":      {`GKLocalPlayer.local.authenticateHandler = ...  // no com.apple.developer.game-center`, `CKContainer(identifier: "iCloud.com.acme.old")  // not in icloud-container-identifiers`},
+	"apple-pay":                {`PKPaymentSummaryItem(label: "Premium subscription", amount: 9.99)`, `let request = PKPaymentRequest()  // no supportedNetworks or merchantCapabilities`, `new ApplePaySession(3, request)  // no .well-known/apple-developer-merchantid-domain-association`},
+	"account-no-delete":        {`Auth.auth().createUser(...)  // and no account deletion flow`},
+	"gambling-mechanics":       {`func openLootBox()`, `"Spin the wheel for a prize!"`},
+	"platform-reference":       {`"Also available on Google Play"`},
+	"placeholder-content":      {`Text("Lorem ipsum dolor sit amet")`, `"TODO: replace"`},
+	"console-log":              {`console.log("user", user)`},
+	"dev-endpoint":             {`let baseURL = "https://staging-api.myapp.com"  // outside #if DEBUG`, "const API = 'http://localhost:3000'"},
+	"debug-flag-forced":        {`static let isDebug = true`, `global.__DEV__ = true`},
+	"pii-logging":              {`print("Logged in: \(user.email) \(password)")`, "console.log(`token ${accessToken}`)"},
+	"hardcoded-ipv4":           {`let api = "http://192.168.1.20:8080"`},
+	"ipv4-reachability":        {`var zeroAddress = sockaddr_in()  // passed to SCNetworkReachabilityCreateWithAddress`, `struct sockaddr_in zeroAddress;`},
+	"ipv4-socket-api":          {`struct hostent *host = gethostbyname("api.example.com");`, `int fd = socket(AF_INET, SOCK_STREAM, 0);`},
+	"http-not-https":           {`URL(string: "http://api.example.com")`},
+	"webview-only":             {`webView.load(URLRequest(url: URL(string: "https://example.com")!))  // the whole app, no offline or native screens`, `server: { url: 'https://example.com' }  // capacitor.config.ts`},
+	"ugc-moderation":           {`ChatView(messages: messages)  // no report or block actions`, `func uploadPhoto(_ image: UIImage)  // no EULA before posting`},
+	"media-rights":             {`let playlist = try M3UParser.parse(url)  // IPTV channels`, `"ytdl-core": "^4.11.5"  // package.json`},
+	"launch-blocking":          {`let config = try Data(contentsOf: remoteConfigURL)  // in didFinishLaunchingWithOptions`, `semaphore.wait()  // waiting for a token before returning true`},
+	"vague-purpose-string":     {`<key>NSCameraUsageDescription</key><string>Camera access</string>`},
+	"export-compliance":        {`import CryptoSwift  // with ITSAppUsesNonExemptEncryption = false`},
+	"missing-privacy-keys":     {`<key>NSCameraUsageDescription</key><string></string>`},
+	"expo-config-check":        {`{ "expo": { "name": "My App" } }  // no ios.bundleIdentifier or icon`},
+	"js-bundle":                {`var __DEV__=true  // in ios/build/.../main.jsbundle`, `fetch("http://192.168.1.20:3000/api")  // injected by a dependency`},
+}
+
+// SuppressionHelp explains how to silence a rule.
+func SuppressionHelp(id string) string {
+	return "Add `greenlight:ignore " + id + "` in a comment on the flagged line or the line above it."
+}
+
+func (r *PatternRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       r.title,
+		Severity:    r.severity,
+		Guideline:   r.guideline,
+		Languages:   r.languages,
+		Description: r.detail,
+		Fix:         r.fix,
+		Examples:    ruleExamples[r.id],
+		ProjectWide: r.antiPatternsGlobal,
+	}
+}
+
+func (r *PrivateAPIRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Private API usage detected",
+		Severity:    SeverityCritical,
+		Guideline:   "2.5.1",
+		Languages:   []string{"swift", "objc"},
+		Description: "Private selectors, classes and symbols reached through @selector, Selector, NSSelectorFromString (including selectors handed to performSelector), NSClassFromString, sel_registerName and dlopen/dlsym. Names built with concatenation, stringWithFormat or joined arrays are resolved; names that cannot be resolved are WARN as obfuscation. dlopen of a public system library such as /usr/lib/libsqlite3.dylib is allowed; dlopen of anything other than a system library is reported as dynamic code loading (§2.5.2).",
+		Fix:         "Replace with public API equivalents.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+func (r *SecretsRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Hardcoded secret/API key detected",
+		Severity:    SeverityCritical,
+		Guideline:   "1.6",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "Provider key formats (AWS, Stripe, Firebase server keys, private keys, GitHub, Slack, OpenAI) are CRITICAL; other high-entropy strings assigned to secret-looking names are WARN.",
+		Fix:         "Revoke and rotate the credential, then move it to your backend.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+func (r *ExternalPurchaseRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "External purchase link-out vs. StoreKit entitlement",
+		Severity:    SeverityCritical,
+		Guideline:   "3.1.1",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "plist"},
+		Description: "Links to external purchases need the StoreKit External Purchase Link entitlement, allowed storefronts in SKExternalPurchaseLink, and the system disclosure sheet.",
+		Fix:         "Request the entitlement for the storefronts you target and open links through ExternalPurchaseLink.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *WebViewWrapperRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "App is a thin WebView wrapper",
+		Severity:    SeverityWarn,
+		Guideline:   "4.2",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "A web view or Capacitor server.url loading a remote site in an app with little code (under 600 lines) or no native features. The finding lists what is missing: native features, offline handling, native navigation. CRITICAL when all of them are.",
+		Fix:         "Add native screens and navigation, offline handling and device features beyond the website.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *UGCModerationRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "User-generated content without moderation safeguards",
+		Severity:    SeverityWarn,
+		Guideline:   "1.2",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "Chat, comments, posts or uploads without a report/flag mechanism, user blocking, an EULA or terms link, or a moderation contact. The finding is a checklist with the file and line behind each item. CRITICAL when reporting or blocking is missing from an app with a chat SDK or chat screen; comments, posts and uploads are recognized by name only and stay at WARN.",
+		Fix:         "Add report and block actions to user content, require agreement to terms before posting, and publish a contact for moderation.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *MediaRightsRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Third-party media streaming or downloads",
+		Severity:    SeverityCritical,
+		Guideline:   "5.2.2",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "json"},
+		Description: "Streaming of movies, TV, sports or music from third-party sources (IPTV, M3U playlists) needs licensing documented in App Review notes (WARN). Downloading or converting media from YouTube, SoundCloud or other services, and torrent clients, are CRITICAL (5.2.3).",
+		Fix:         "Document the rights to your content in App Review notes, and remove downloads from other services.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *CryptoRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Cryptocurrency and NFT features",
+		Severity:    SeverityCritical,
+		Guideline:   "3.1.5",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "json"},
+		Description: "Wallets (3.1.5(i), organization accounts only), exchanges and on-ramps (3.1.5(iii), licensed regions only), token sales and crypto futures (3.1.5(iv)), crypto rewards for tasks (3.1.5(v)), and NFTs: ownership unlocking features is CRITICAL, buy or mint links outside in-app purchase are WARN (3.1.1).",
+		Fix:         "Follow the constraint in each finding; unlock features with in-app purchase, not NFT ownership.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *ATTTimingRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Tracking before the ATT prompt",
+		Severity:    SeverityWarn,
+		Guideline:   "5.1.2",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "Tracking SDKs started before requestTrackingAuthorization on the launch path, and IDFA reads without an authorization status check.",
+		Fix:         "Start tracking SDKs in the ATT completion handler or enable their wait-for-ATT option.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *PaywallRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Subscription paywall disclosures",
+		Severity:    SeverityWarn,
+		Guideline:   "3.1.2",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "Subscription purchase screens must show the price, a Terms of Use (EULA) link and a privacy policy link; free-trial copy must state the trial length. The disclosures may live in any file of the project, since paywalls are often split across views.",
+		Fix:         "Show price and billing period next to the purchase button and link Terms of Use and Privacy Policy.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+func (r *HealthDataRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "HealthKit / ResearchKit / CareKit data handling",
+		Severity:    SeverityCritical,
+		Guideline:   "5.1.3",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "plist", "json"},
+		Description: "Health purpose strings and entitlement, no health values written to iCloud (a file that only uses both HealthKit and iCloud is a warning), and no health data near advertising or analytics SDKs.",
+		Fix:         "Add NSHealthShareUsageDescription / NSHealthUpdateUsageDescription and keep health data out of iCloud and ad SDKs.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *MedicalDisclaimerRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Medical claims and missing health disclaimers",
+		Severity:    SeverityCritical,
+		Guideline:   "1.4.1",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "Telehealth, medication, symptom and emergency features without a matching disclaimer (INFO, or WARN when the app says it diagnoses, treats or cures), and claims guideline 1.4 rejects: dosage calculators, diagnosis or cure promises, vitals measured with only the phone's sensors (CRITICAL). Terms inside dotted names such as SF Symbols (\"waveform.path.ecg\") and asset identifiers are not copy and are ignored.",
+		Fix:         "Add a disclaimer for each health feature and remove claims that aren't backed by regulatory clearance.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *CapabilityRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Capability and entitlement mismatch",
+		Severity:    SeverityWarn,
+		Guideline:   "2.1",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "json", "plist"},
+		Description: "Game Center, CloudKit, Sign in with Apple and Apple Pay used in code without the entitlement, declared in entitlements but never used, or CloudKit containers and merchant IDs referenced in code that the entitlements don't list.",
+		Fix:         "Keep Signing & Capabilities, the App ID and the code in sync: add missing capabilities and identifiers, remove unused ones.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *ApplePayRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Apple Pay configuration",
+		Severity:    SeverityWarn,
+		Guideline:   "3.1.1",
+		Languages:   []string{"swift", "objc", "typescript", "javascript"},
+		Description: "Apple Pay charges for digital content (subscriptions, premium unlocks, virtual currency), which must use In-App Purchase; payment requests without supported networks, merchant capabilities or country; Apple Pay JS without the merchant domain verification file.",
+		Fix:         "Use In-App Purchase for digital content, complete the PKPaymentRequest, and verify every Apple Pay web domain.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *OTAUpdateRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Over-the-air update configuration",
+		Severity:    SeverityWarn,
+		Guideline:   "2.5.2",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "json", "plist"},
+		Description: "CodePush, expo-updates, Capacitor live updates and similar OTA frameworks: forced restarts, non-HTTPS or unsigned self-hosted update servers, expo-updates without runtimeVersion, and native hot-patching (JSPatch, Rollout), which is never allowed.",
+		Fix:         "Limit OTA releases to JS/asset changes, apply them on next launch, and serve signed updates over HTTPS.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *LaunchBlockingRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Blocking call during app launch",
+		Severity:    SeverityWarn,
+		Guideline:   "2.1",
+		Languages:   []string{"swift", "objc"},
+		Description: "Synchronous network loads, semaphore/group waits and sleeps inside didFinishLaunchingWithOptions or a SwiftUI App initializer, which stall cold launch on slow review networks.",
+		Fix:         "Load asynchronously after the first screen is shown and start from cached or bundled data.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+func (r *ExportComplianceRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Encryption usage vs. ITSAppUsesNonExemptEncryption",
+		Severity:    SeverityWarn,
+		Guideline:   "5.0",
+		Languages:   []string{"swift", "objc", "typescript", "javascript", "plist", "json"},
+		Description: "Compares cryptography found in code (OS-provided vs. bundled libraries) with the export compliance declaration in Info.plist or app.json.",
+		Fix:         "Declare ITSAppUsesNonExemptEncryption accurately and add ITSEncryptionExportComplianceCode once approved.",
+		Examples:    ruleExamples[r.id],
+		ProjectWide: true,
+	}
+}
+
+func (r *PlistKeyRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       r.title,
+		Severity:    r.severity,
+		Guideline:   r.guideline,
+		Languages:   []string{"plist"},
+		Description: "Privacy purpose strings that are declared in Info.plist but left empty.",
+		Fix:         "Describe specifically why the app needs each permission.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+func (r *ExpoConfigRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Expo config issues",
+		Severity:    SeverityWarn,
+		Guideline:   "2.1",
+		Languages:   []string{"json"},
+		Description: "Missing ios.bundleIdentifier or icon, and placeholder app names in app.json / app.config.",
+		Fix:         "Fill in bundleIdentifier, icon and a real app name before building for the store.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+func (r *JSBundleRule) Info() RuleInfo {
+	return RuleInfo{
+		ID:          r.id,
+		Title:       "Rejection risks in the shipped JS bundle",
+		Severity:    SeverityCritical,
+		Guideline:   "2.1",
+		Languages:   []string{"jsbundle"},
+		Description: "Scans main.jsbundle / index.ios.bundle in build output or the IPA (plain JS or Hermes bytecode) for development builds, developer tooling, dev-server and staging endpoints, HTTP URLs, private API references and secrets — including what npm dependencies inject.",
+		Fix:         "Build release bundles with --dev false and trace injected code back to its package in node_modules.",
+		Examples:    ruleExamples[r.id],
+	}
+}
+
+var ignoreDirective = regexp.MustCompile(`greenlight:ignore(?:\s+([a-z0-9,\s-]+))?`)
+
+// ignoreDirectives returns fc's `greenlight:ignore` comments by 0-based
+// line, or nil if it has none.
+func ignoreDirectives(fc FileContext) map[int]string {
+	var directives map[int]string
+	for i, line := range fc.Lines {
+		if !strings.Contains(line, "greenlight:ignore") {
+			continue
+		}
+		if directives == nil {
+			directives = make(map[int]string)
+		}
+		directives[i] = line
+	}
+	return directives
+}
+
+// inlineSuppressed reports whether a `greenlight:ignore [ids]` comment on the
+// finding's line or the line above it silences ruleID. directives is the
+// file's comments, as returned by ignoreDirectives.
+func inlineSuppressed(directives map[int]string, line int, ruleID string) bool {
+	for _, n := range []int{line - 1, line - 2} {
+		m := ignoreDirective.FindStringSubmatch(directives[n])
+		if m == nil {
+			continue
+		}
+		ids := strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(ids) == 0 {
+			return true
+		}
+		for _, id := range ids {
+			if id == ruleID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RuleOverride turns a rule off or changes its severity, as parsed from the
+// rules section of .greenlight.yml.
+type RuleOverride = config.RuleOverride
+
+// ApplyOverrides drops findings from rules disabled in .greenlight.yml and
+// re-grades the rest according to any per-rule severity override.
+func ApplyOverrides(findings []Finding, overrides map[string]RuleOverride) []Finding {
+	if len(overrides) == 0 {
+		return findings
+	}
+	kept := config.ApplyOverrides(findings, overrides, func(f *Finding) (string, *Severity) {
+		return f.RuleID, &f.Severity
+	})
+	SortFindings(kept) // re-graded findings move with their severity
+	return kept
+}
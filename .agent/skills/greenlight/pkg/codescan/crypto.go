@@ -0,0 +1,166 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// cryptoSignals are the cryptocurrency and NFT features guidelines 3.1.1
+// and 3.1.5 constrain, each mapped to the sub-guideline that applies.
+// Mining (3.1.5(ii)) is the crypto-mining rule.
+var cryptoSignals = []struct {
+	guideline string
+	severity  Severity
+	pattern   *regexp.Regexp
+	// also must match the same line, when set, and within near bytes of
+	// pattern's match when near is set.
+	also   *regexp.Regexp
+	near   int
+	title  string
+	detail string
+	fix    string
+}{
+	{
+		guideline: "3.1.5(i)",
+		severity:  SeverityInfo,
+		pattern:   regexp.MustCompile(`(?i)(seed phrase|recovery phrase|mnemonic phrase|\bbip-?39\b|\bHDWallet\b|WalletConnect|@walletconnect/|web3swift|\bWalletCore\b|@solana/web3\.js|ethers\.Wallet|web3modal|@rainbow-me/rainbowkit|\bwagmi\b|Web3Auth|MetaMaskSDK|@metamask/sdk|CoinbaseWalletSDK)`),
+		title:     "Cryptocurrency wallet: submit from an organization account",
+		detail:    "Apps may facilitate virtual currency storage only when offered by developers enrolled as an organization. Wallets submitted from an individual account are rejected.",
+		fix:       "Submit from an Apple Developer Program membership enrolled as an organization, and describe how keys are stored in App Review notes.",
+	},
+	{
+		guideline: "3.1.5(iii)",
+		severity:  SeverityWarn,
+		pattern:   regexp.MustCompile(`(?i)(\b(buy|sell|trade|swap) (crypto|cryptocurrency|bitcoin|btc|eth|ethereum)\b|\bccxt\b|MoonPay|@ramp-network|\bTransak\b|\bWyre\b|Uniswap|\b1inch\b|api\.0x\.org|api\.binance\.com|api\.kraken\.com|api\.exchange\.coinbase\.com)`),
+		title:     "Cryptocurrency exchange: restrict to licensed regions",
+		detail:    "Apps may facilitate buying, selling or trading cryptocurrency on an approved exchange only in countries or regions where the app has the appropriate licensing and permissions, and only when offered by the exchange itself.",
+		fix:       "Limit territory availability in App Store Connect to regions where you or your exchange partner are licensed, and list the licenses in App Review notes.",
+	},
+	{
+		guideline: "3.1.5(iv)",
+		severity:  SeverityWarn,
+		pattern:   regexp.MustCompile(`(?i)(\binitial coin offering\b|(?-i:\bICOs?\b)|\btoken (pre-?)?sale\b|\bcrypto futures\b|\bperpetual (futures|swaps)\b)`),
+		title:     "Token sales or crypto futures: established institutions only",
+		detail:    "Initial coin offerings, cryptocurrency futures trading and other crypto-securities trading must come from established banks, securities firms, futures commission merchants or other approved financial institutions, and comply with the law.",
+		fix:       "Remove the feature, or submit from the licensed institution and include its registration in App Review notes.",
+	},
+	{
+		guideline: "3.1.5(v)",
+		severity:  SeverityWarn,
+		pattern:   regexp.MustCompile(`(?i)\b(earn|get|win|receive) (free )?(crypto|cryptocurrency|bitcoin|btc|eth|sats)\b[^"'\x60]*\b(for|by) (downloading|installing|sharing|posting|referring|inviting|watching|completing|playing)\b`),
+		title:     "Cryptocurrency offered as a reward for tasks",
+		detail:    "Apps may not offer cryptocurrency for completing tasks such as downloading other apps, encouraging other users to download, or posting to social networks.",
+		fix:       "Remove cryptocurrency rewards for downloads, referrals, sharing and similar tasks.",
+	},
+	{
+		guideline: "3.1.1",
+		severity:  SeverityCritical,
+		pattern:   regexp.MustCompile(`(?i:\bnfts?\b|ERC-?721|ERC-?1155|\btokenGat\w*)|\b(?i:nfts?)[A-Z_]|[a-z]NFTs?`),
+		also:      regexp.MustCompile(`(?i)(unlock\w*|is(Premium|Pro|Vip|Member)\b|premium|hasAccess|grantAccess|entitle\w*|\bgated?\b)`),
+		near:      40,
+		title:     "NFT ownership unlocks app features",
+		detail:    "Apps may let users view the NFTs they own, but owning an NFT may not unlock features or functionality within the app. Features must be unlocked with in-app purchase.",
+		fix:       "Unlock features with in-app purchase instead of NFT ownership; keep NFTs to display only.",
+	},
+	{
+		guideline: "3.1.1",
+		severity:  SeverityWarn,
+		pattern:   regexp.MustCompile(`(?i)(\b(buy|mint|purchase|bid on) (this |an? |the |your )?nfts?\b|opensea\.io/(assets|collection)|magiceden\.io|rarible\.com|blur\.io)`),
+		title:     "NFT purchases outside in-app purchase",
+		detail:    "Apps may sell NFTs and NFT-related services only with in-app purchase. Apps that let users browse NFT collections may not include buttons, links or other calls to action that lead to other purchasing mechanisms.",
+		fix:       "Sell NFTs with in-app purchase, or remove buy and mint buttons and marketplace links from the app.",
+	},
+}
+
+// CryptoRule detects cryptocurrency wallets, exchanges, token sales, crypto
+// rewards and NFT features, and reports each with the guideline 3.1.5 or
+// 3.1.1 constraint that applies to it.
+type CryptoRule struct {
+	id string
+}
+
+func (r *CryptoRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "json":
+		return !isToolingConfig(fc.RelPath)
+	}
+	return false
+}
+
+func (r *CryptoRule) Check(fc FileContext) []Finding { return nil }
+
+// Collect returns the file's first hit for each signal, indexed like
+// cryptoSignals.
+func (r *CryptoRule) Collect(fc FileContext) any {
+	var hits []*Finding
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		for i, s := range cryptoSignals {
+			if (hits != nil && hits[i] != nil) || !matchSignal(line, s.pattern, s.also, s.near) {
+				continue
+			}
+			if hits == nil {
+				hits = make([]*Finding, len(cryptoSignals))
+			}
+			hits[i] = &Finding{
+				RuleID:    r.id,
+				Severity:  s.severity,
+				Guideline: s.guideline,
+				Title:     s.title,
+				Detail:    s.detail,
+				Fix:       s.fix,
+				File:      fc.RelPath,
+				Line:      lineNum + 1,
+				Code:      trimmed,
+			}
+		}
+	}
+	if hits == nil {
+		return nil
+	}
+	return hits
+}
+
+func (r *CryptoRule) CheckProject(evidence []any) []Finding {
+	hits := make([]*Finding, len(cryptoSignals))
+	for _, ev := range evidence {
+		for i, f := range ev.([]*Finding) {
+			if hits[i] == nil {
+				hits[i] = f
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, f := range hits {
+		if f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings
+}
+
+// matchSignal reports whether line matches pattern and, when also is set,
+// also; with near set, the two matches must be at most near bytes apart,
+// so an unlock check is tied to the NFT it reads rather than to anything
+// else on a long line.
+func matchSignal(line string, pattern, also *regexp.Regexp, near int) bool {
+	if also == nil {
+		return pattern.MatchString(line)
+	}
+	if near == 0 {
+		return pattern.MatchString(line) && also.MatchString(line)
+	}
+	targets := also.FindAllStringIndex(line, -1)
+	for _, m := range pattern.FindAllStringIndex(line, -1) {
+		for _, t := range targets {
+			if t[0]-m[1] <= near && m[0]-t[1] <= near {
+				return true
+			}
+		}
+	}
+	return false
+}
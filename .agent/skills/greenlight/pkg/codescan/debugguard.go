@@ -0,0 +1,95 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Swift and Objective-C conditional compilation on the DEBUG flag.
+	debugIfPattern    = regexp.MustCompile(`^#\s*(if\s+.*\bDEBUG\b|ifdef\s+DEBUG\b|if\s+targetEnvironment\(simulator\))`)
+	notDebugIfPattern = regexp.MustCompile(`^#\s*(if\s+!\s*DEBUG\b|ifndef\s+DEBUG\b)`)
+	ifDirective       = regexp.MustCompile(`^#\s*if`)
+	elseDirective     = regexp.MustCompile(`^#\s*(else|elif|elseif)\b`)
+	endifDirective    = regexp.MustCompile(`^#\s*endif\b`)
+
+	// JavaScript blocks and expressions that only run in development.
+	jsDevBlockPattern = regexp.MustCompile(`\bif\s*\(\s*(__DEV__|process\.env\.NODE_ENV\s*[!=]==?\s*['"]development['"]|import\.meta\.env\.DEV)\s*\)\s*\{`)
+	jsDevExprPattern  = regexp.MustCompile(`(__DEV__|process\.env\.NODE_ENV\s*[!=]==?\s*['"]\w+['"]|import\.meta\.env\.DEV)\s*(\?|&&|\|\||$)`)
+	// Swift runtime debug checks.
+	swiftDebugCheckPattern = regexp.MustCompile(`\b_isDebugAssertConfiguration\s*\(\s*\)`)
+)
+
+// debugOnlyLines reports, for each line of fc, whether it only runs in
+// debug builds: inside #if DEBUG (or the #else of #if !DEBUG) in Swift and
+// Objective-C, inside if (__DEV__) { ... } blocks in JavaScript, or on a
+// line that branches on __DEV__ or NODE_ENV, such as a development/
+// production ternary.
+func debugOnlyLines(fc FileContext) []bool {
+	debug := make([]bool, len(fc.Lines))
+	switch fc.Language {
+	case "swift", "objc":
+		// One entry per open #if: whether the current branch is debug-only,
+		// and whether the condition was DEBUG (1), !DEBUG (-1) or other (0).
+		type branch struct {
+			debug bool
+			cond  int
+		}
+		var stack []branch
+		for i, line := range fc.Lines {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case notDebugIfPattern.MatchString(trimmed):
+				stack = append(stack, branch{cond: -1})
+			case debugIfPattern.MatchString(trimmed):
+				stack = append(stack, branch{debug: true, cond: 1})
+			case ifDirective.MatchString(trimmed):
+				stack = append(stack, branch{})
+			case elseDirective.MatchString(trimmed) && len(stack) > 0:
+				top := &stack[len(stack)-1]
+				switch {
+				case top.cond == -1 && strings.Contains(trimmed, "else") && !strings.Contains(trimmed, "elseif"):
+					top.debug = true
+				case strings.Contains(trimmed, "DEBUG") && !strings.Contains(trimmed, "!"):
+					top.debug = true
+				default:
+					top.debug = false
+				}
+			case endifDirective.MatchString(trimmed) && len(stack) > 0:
+				stack = stack[:len(stack)-1]
+			}
+			for _, b := range stack {
+				if b.debug {
+					debug[i] = true
+				}
+			}
+			if swiftDebugCheckPattern.MatchString(line) {
+				debug[i] = true
+			}
+		}
+	case "typescript", "javascript":
+		depth := 0 // brace depth inside a development-only block
+		prevDev := false
+		for i, line := range fc.Lines {
+			trimmed := strings.TrimSpace(line)
+			if depth > 0 {
+				debug[i] = true
+				depth += strings.Count(line, "{") - strings.Count(line, "}")
+				if depth < 0 {
+					depth = 0
+				}
+			} else if loc := jsDevBlockPattern.FindStringIndex(line); loc != nil {
+				debug[i] = true
+				depth = strings.Count(line[loc[0]:], "{") - strings.Count(line[loc[0]:], "}")
+			}
+			// cond ? dev : prod, split over lines after a trailing __DEV__.
+			if jsDevExprPattern.MatchString(line) || prevDev && strings.HasPrefix(trimmed, "?") {
+				debug[i] = true
+			}
+			if trimmed != "" {
+				prevDev = strings.HasSuffix(trimmed, "__DEV__")
+			}
+		}
+	}
+	return debug
+}
@@ -0,0 +1,181 @@
+package codescan
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Crypto usage grouped by whether the implementation ships with the OS.
+var (
+	osCryptoPattern      = regexp.MustCompile(`(import\s+CryptoKit\b|import\s+CommonCrypto\b|#import\s+<CommonCrypto/|#include\s+<CommonCrypto/|\bCCCrypt\(|\bCCCryptorCreate|\bSecKeyCreateEncryptedData|AES\.GCM\.seal|ChaChaPoly\.seal|expo-crypto|react-native-aes)`)
+	bundledCryptoPattern = regexp.MustCompile(`(#include\s+<openssl/|#import\s+<openssl/|import\s+OpenSSL\b|\bEVP_EncryptInit|\bEVP_CIPHER_CTX_new|import\s+CryptoSwift\b|import\s+Sodium\b|\bcrypto_secretbox|react-native-sodium|libsodium|tweetnacl|crypto-js|node-forge)`)
+
+	nonExemptKeyRe   = regexp.MustCompile(`ITSAppUsesNonExemptEncryption</key>\s*<(true|false)\s*/>`)
+	expoNonExemptRe  = regexp.MustCompile(`"(ITSAppUsesNonExemptEncryption|usesNonExemptEncryption)"\s*:\s*(true|false)`)
+	complianceCodeRe = regexp.MustCompile(`ITSEncryptionExportComplianceCode`)
+
+	// Info.plists of bundles other than the app: extensions, frameworks,
+	// resource bundles and test bundles.
+	nonAppPlistRe = regexp.MustCompile(`<key>NSExtension</key>|<key>CFBundlePackageType</key>\s*<string>(BNDL|FMWK)</string>`)
+)
+
+// ExportComplianceRule detects encryption in code and checks it against the
+// ITSAppUsesNonExemptEncryption declaration in Info.plist or app.json.
+type ExportComplianceRule struct {
+	id string
+}
+
+func (r *ExportComplianceRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "plist", "json":
+		return true
+	}
+	return false
+}
+
+func (r *ExportComplianceRule) Check(fc FileContext) []Finding { return nil }
+
+// exportEvidence is what one file contributes to ExportComplianceRule: an
+// app config's encryption declaration, or code's first crypto uses.
+type exportEvidence struct {
+	declared          string // "true", "false"
+	declaredIn        string
+	hasComplianceCode bool
+
+	osHit, bundledHit *Finding
+}
+
+func (r *ExportComplianceRule) Collect(fc FileContext) any {
+	if fc.Language == "plist" || fc.Language == "json" {
+		content := strings.Join(fc.Lines, "\n")
+		if !isAppConfig(fc.RelPath, content) {
+			return nil
+		}
+		ev := &exportEvidence{declaredIn: fc.RelPath, hasComplianceCode: complianceCodeRe.MatchString(content)}
+		if m := nonExemptKeyRe.FindStringSubmatch(content); m != nil {
+			ev.declared = m[1]
+		} else if m := expoNonExemptRe.FindStringSubmatch(content); m != nil {
+			ev.declared = m[2]
+		} else {
+			return nil
+		}
+		return ev
+	}
+
+	ev := &exportEvidence{}
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		if ev.bundledHit == nil && bundledCryptoPattern.MatchString(line) {
+			ev.bundledHit = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+		if ev.osHit == nil && osCryptoPattern.MatchString(line) {
+			ev.osHit = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+	}
+	if ev.osHit == nil && ev.bundledHit == nil {
+		return nil
+	}
+	return ev
+}
+
+func (r *ExportComplianceRule) CheckProject(evidence []any) []Finding {
+	var (
+		osHit, bundledHit *Finding
+		declared          string // "", "true", "false"
+		declaredIn        string
+		hasComplianceCode bool
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*exportEvidence)
+		if ev.declared != "" {
+			if shallower(ev.declaredIn, declaredIn) {
+				declared, declaredIn, hasComplianceCode = ev.declared, ev.declaredIn, ev.hasComplianceCode
+			}
+			continue
+		}
+		if bundledHit == nil {
+			bundledHit = ev.bundledHit
+		}
+		if osHit == nil {
+			osHit = ev.osHit
+		}
+	}
+
+	if osHit == nil && bundledHit == nil {
+		return nil
+	}
+	hit := bundledHit
+	if hit == nil {
+		hit = osHit
+	}
+
+	var findings []Finding
+	switch {
+	case declared == "":
+		findings = append(findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "5.0",
+			Title:     "Encryption used but ITSAppUsesNonExemptEncryption not declared",
+			Detail:    "The app uses cryptography beyond HTTPS. Without ITSAppUsesNonExemptEncryption in Info.plist every build waits on a manual export compliance question, and a wrong answer can block distribution.",
+			Fix:       "Add ITSAppUsesNonExemptEncryption to Info.plist (false only if all encryption is exempt — e.g. HTTPS, OS-provided authentication or data protection).",
+			File:      hit.File,
+			Line:      hit.Line,
+			Code:      hit.Code,
+		})
+	case declared == "false" && bundledHit != nil:
+		findings = append(findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "5.0",
+			Title:     "Bundled crypto library with ITSAppUsesNonExemptEncryption = false",
+			Detail:    "The app links a non-OS encryption library (OpenSSL, libsodium, CryptoSwift, etc.) but declares no non-exempt encryption in " + declaredIn + ". Proprietary or bundled encryption usually needs export documentation.",
+			Fix:       "Confirm the usage qualifies for an exemption. Otherwise set ITSAppUsesNonExemptEncryption to true and provide export compliance documentation in App Store Connect.",
+			File:      bundledHit.File,
+			Line:      bundledHit.Line,
+			Code:      bundledHit.Code,
+		})
+	case declared == "true" && !hasComplianceCode:
+		findings = append(findings, Finding{
+			Severity:  SeverityInfo,
+			Guideline: "5.0",
+			Title:     "Non-exempt encryption declared without ITSEncryptionExportComplianceCode",
+			Detail:    "ITSAppUsesNonExemptEncryption is true in " + declaredIn + ". Once App Store Connect approves your export compliance documentation, adding the compliance code skips the per-build question. Distribution in France also requires an encryption declaration.",
+			Fix:       "Upload export compliance documentation in App Store Connect, then add ITSEncryptionExportComplianceCode to Info.plist.",
+			File:      declaredIn,
+		})
+	}
+
+	return findings
+}
+
+// isAppConfig reports whether the file at relPath declares the app target's
+// own settings: an Expo app.json, or an Info.plist (or Name-Info.plist) that doesn't belong to a
+// dependency, test bundle, extension or framework. Their declarations don't
+// describe the app's binary.
+func isAppConfig(relPath, content string) bool {
+	for _, seg := range strings.Split(path.Dir(relPath), "/") {
+		lower := strings.ToLower(seg)
+		if seg == "Pods" || seg == "Carthage" || seg == "node_modules" || lower == "vendor" ||
+			strings.Contains(lower, "test") || strings.Contains(lower, "example") ||
+			strings.HasSuffix(lower, ".framework") || strings.HasSuffix(lower, ".bundle") || strings.HasSuffix(lower, ".appex") {
+			return false
+		}
+	}
+	switch name := path.Base(relPath); {
+	case name == "app.json":
+		return true
+	case name == "Info.plist", strings.HasSuffix(name, "-Info.plist") && name != "GoogleService-Info.plist":
+		return !nonAppPlistRe.MatchString(content)
+	}
+	return false
+}
+
+// shallower reports whether relPath is nearer the project root than other,
+// or other is unset, so the app's top-level config wins over nested ones.
+func shallower(relPath, other string) bool {
+	return other == "" || strings.Count(relPath, "/") < strings.Count(other, "/")
+}
@@ -0,0 +1,204 @@
+package codescan
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// StoreKit external purchase entitlements.
+const (
+	entitlementExternalPurchaseLink = "com.apple.developer.storekit.external-purchase-link"
+	entitlementExternalPurchase     = "com.apple.developer.storekit.external-purchase"
+)
+
+var (
+	// Links out to a web checkout for digital goods (subscriptions, upgrades, credits).
+	externalPurchaseURLPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)https://(checkout|buy)\.stripe\.com`),
+		regexp.MustCompile(`(?i)https://[^"'\s]*(paddle\.com|lemonsqueezy\.com|gumroad\.com|chargebee\.com)`),
+		regexp.MustCompile(`(?i)"https?://[^"]*/(checkout|subscribe|pricing|upgrade|billing|purchase|buy)[^"]*"`),
+		regexp.MustCompile(`(?i)'https?://[^']*/(checkout|subscribe|pricing|upgrade|billing|purchase|buy)[^']*'`),
+		regexp.MustCompile(`\bExternalPurchaseLink\b|\bExternalPurchaseCustomLink\b`),
+	}
+
+	// Apple's system disclosure sheet, shown before leaving the app.
+	externalPurchaseDisclosurePattern = regexp.MustCompile(`(ExternalPurchaseLink\.open|ExternalPurchase\.presentNoticeSheet|ExternalPurchaseCustomLink\.showNotice|ExternalLinkAccount\.open)`)
+
+	// SKExternalPurchaseLink maps storefront country codes to the link-out URL.
+	externalPurchaseLinkPlistRe = regexp.MustCompile(`(?s)<key>SKExternalPurchaseLink</key>\s*<dict>(.*?)</dict>`)
+	plistDictKeyRe              = regexp.MustCompile(`<key>([^<]+)</key>`)
+)
+
+// ExternalPurchaseRule correlates external payment links in code with the
+// StoreKit External Purchase Link entitlement, its Info.plist storefront
+// configuration, and the required system disclosure sheet.
+type ExternalPurchaseRule struct {
+	id string
+}
+
+func (r *ExternalPurchaseRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "plist":
+		return true
+	}
+	return false
+}
+
+func (r *ExternalPurchaseRule) Check(fc FileContext) []Finding { return nil }
+
+// externalPurchaseEvidence is what one file contributes to
+// ExternalPurchaseRule: a plist's entitlements and storefronts, or code's
+// first link-out and whether it shows the disclosure sheet.
+type externalPurchaseEvidence struct {
+	rel                  string
+	hasLinkEnt, hasEUEnt bool
+	plistLink            bool
+	storefronts          []string
+
+	linkHit       *Finding
+	hasDisclosure bool
+}
+
+func (r *ExternalPurchaseRule) Collect(fc FileContext) any {
+	ev := &externalPurchaseEvidence{rel: fc.RelPath}
+	if fc.Language == "plist" {
+		content := strings.Join(fc.Lines, "\n")
+		ev.hasLinkEnt = strings.Contains(content, entitlementExternalPurchaseLink)
+		ev.hasEUEnt = strings.Contains(content, "<key>"+entitlementExternalPurchase+"</key>")
+		if m := externalPurchaseLinkPlistRe.FindStringSubmatch(content); m != nil {
+			ev.plistLink = true
+			for _, k := range plistDictKeyRe.FindAllStringSubmatch(m[1], -1) {
+				ev.storefronts = append(ev.storefronts, strings.ToLower(k[1]))
+			}
+		}
+		if !ev.hasLinkEnt && !ev.hasEUEnt && !ev.plistLink {
+			return nil
+		}
+		return ev
+	}
+
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		if externalPurchaseDisclosurePattern.MatchString(line) {
+			ev.hasDisclosure = true
+		}
+		if ev.linkHit != nil {
+			continue
+		}
+		for _, p := range externalPurchaseURLPatterns {
+			if p.MatchString(line) {
+				ev.linkHit = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+				break
+			}
+		}
+	}
+	if ev.linkHit == nil && !ev.hasDisclosure {
+		return nil
+	}
+	return ev
+}
+
+func (r *ExternalPurchaseRule) CheckProject(evidence []any) []Finding {
+	var (
+		linkHit        *Finding
+		hasDisclosure  bool
+		hasLinkEnt     bool
+		hasEUEnt       bool
+		entitlementRel string
+		plistRel       string
+		storefronts    []string
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*externalPurchaseEvidence)
+		if ev.hasLinkEnt {
+			hasLinkEnt = true
+			entitlementRel = ev.rel
+		}
+		hasEUEnt = hasEUEnt || ev.hasEUEnt
+		if ev.plistLink {
+			plistRel = ev.rel
+			storefronts = append(storefronts, ev.storefronts...)
+		}
+		hasDisclosure = hasDisclosure || ev.hasDisclosure
+		if linkHit == nil {
+			linkHit = ev.linkHit
+		}
+	}
+
+	if linkHit == nil {
+		return nil
+	}
+
+	var findings []Finding
+	add := func(sev Severity, guideline, title, detail, fix, file string, line int, code string) {
+		findings = append(findings, Finding{
+			Severity:  sev,
+			Guideline: guideline,
+			Title:     title,
+			Detail:    detail,
+			Fix:       fix,
+			File:      file,
+			Line:      line,
+			Code:      code,
+		})
+	}
+
+	if !hasLinkEnt {
+		detail := "The app links to an external checkout but does not declare " + entitlementExternalPurchaseLink + ". Linking out to buy digital goods without the entitlement is rejected."
+		if hasEUEnt {
+			detail = "The app declares " + entitlementExternalPurchase + " (alternative in-app payment), which does not cover linking out to a website. " + entitlementExternalPurchaseLink + " is required for link-outs."
+		}
+		add(SeverityCritical, "3.1.1",
+			"External purchase link without StoreKit External Purchase Link entitlement",
+			detail,
+			"Use StoreKit/IAP for digital goods, or request the External Purchase Link entitlement and add it to your .entitlements file.",
+			linkHit.File, linkHit.Line, linkHit.Code)
+		return findings
+	}
+
+	if !hasDisclosure {
+		add(SeverityCritical, "3.1.1(a)",
+			"External purchase link opened without Apple's disclosure sheet",
+			"Apps with the External Purchase Link entitlement must present the system disclosure sheet before sending users to an external purchase page. Opening the URL directly is rejected.",
+			"Open the link with ExternalPurchaseLink.open() (or ExternalPurchaseCustomLink.showNotice()) instead of UIApplication.open / Linking.openURL.",
+			linkHit.File, linkHit.Line, linkHit.Code)
+	}
+
+	if plistRel == "" {
+		add(SeverityWarn, "3.1.1(a)",
+			"SKExternalPurchaseLink missing from Info.plist",
+			"The External Purchase Link entitlement is declared but no SKExternalPurchaseLink dictionary maps storefronts to your link-out URL.",
+			"Add SKExternalPurchaseLink to Info.plist with a storefront country code key (e.g. \"us\") and the HTTPS link-out URL.",
+			entitlementRel, 0, "")
+		return findings
+	}
+
+	var unsupported []string
+	for _, sf := range storefronts {
+		if sf != "us" {
+			unsupported = append(unsupported, sf)
+		}
+	}
+	sort.Strings(unsupported)
+
+	if len(unsupported) > 0 {
+		add(SeverityWarn, "3.1.1(a)",
+			"External purchase link configured outside the US storefront",
+			"SKExternalPurchaseLink lists storefronts ("+strings.Join(unsupported, ", ")+") where link-outs require a separate, region-specific entitlement (e.g. music streaming in the EU). Apple rejects link-outs in storefronts the app is not approved for.",
+			"Restrict SKExternalPurchaseLink to \"us\", or confirm the region-specific entitlement has been granted for each listed storefront.",
+			plistRel, 0, "")
+	} else if hasDisclosure {
+		add(SeverityInfo, "3.1.1(a)",
+			"External purchase link-out configured for the US storefront",
+			"Entitlement, SKExternalPurchaseLink, and the disclosure sheet are all present. US link-outs in this configuration are allowed.",
+			"",
+			plistRel, 0, "")
+	}
+
+	return findings
+}
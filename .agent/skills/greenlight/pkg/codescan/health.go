@@ -0,0 +1,317 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+)
+
+var (
+	healthFrameworkPattern = regexp.MustCompile(`(import\s+(HealthKit|ResearchKit|CareKit\w*)\b|@import\s+HealthKit|#import\s+<HealthKit/|HKHealthStore|ORKTaskViewController|OCKStore|react-native-health|@kingstinct/react-native-healthkit|rn-apple-healthkit|expo-health)`)
+	healthWritePattern     = regexp.MustCompile(`(healthStore\.save\(|HKHealthStore\(\)\.save\(|\.save\(\s*\w*[Ss]ample|saveSample|saveQuantitySample|initHealthKit\([^)]*write)`)
+	healthShareSetPattern  = regexp.MustCompile(`toShare:\s*([^,)]+)`)
+	healthDataTypePattern  = regexp.MustCompile(`(HKQuantitySample|HKCategorySample|HKWorkout\b|HKSample|HKHealthStore|HKQuantityType|healthStore\.)`)
+	healthICloudPattern    = regexp.MustCompile(`(NSUbiquitousKeyValueStore|CKContainer|CKRecord\b|NSPersistentCloudKitContainer|ubiquityContainerIdentifier|forUbiquityContainerIdentifier)`)
+	// Writes into a CloudKit record or the iCloud key-value store.
+	cloudWritePattern = regexp.MustCompile(`(\w*[Rr]ecord\s*\[[^\]]*\]\s*=[^=]|\.setValue\(|\.set\(|\.save\(|CKModifyRecordsOperation)`)
+	// Names bound to health data: typed as an HK class, assigned from one,
+	// or closure parameters of a HealthKit query.
+	healthTypedIdentPattern    = regexp.MustCompile(`\b(\w+)\s*:\s*\[?\s*HK\w+`)
+	healthAssignedIdentPattern = regexp.MustCompile(`\b(?:let|var|const)\s+(\w+)\s*=.*(\bHK\w+|healthStore\.)`)
+	healthClosureParamsPattern = regexp.MustCompile(`\{\s*\[?[^\]{]*?\]?\s*\(?\s*(\w+(?:\s*,\s*\w+)*)\s*\)?\s+in\b`)
+	healthAdsPattern           = regexp.MustCompile(`(?i)(GADMobileAds|GADBannerView|AppLovin|IronSource|UnityAds|FBSDK|AppsFlyer|Adjust\.track|Analytics\.logEvent|Mixpanel|Amplitude|\.track\(\s*["'])`)
+)
+
+// HealthDataRule is the guideline 5.1.3 rule pack for HealthKit, ResearchKit and
+// CareKit apps: purpose strings and entitlement, no health data in iCloud, and
+// no health data flowing into advertising or analytics SDKs.
+type HealthDataRule struct {
+	id string
+}
+
+func (r *HealthDataRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "plist", "json":
+		return true
+	}
+	return false
+}
+
+func (r *HealthDataRule) Check(fc FileContext) []Finding { return nil }
+
+// healthEvidence is what one file contributes to HealthDataRule: the purpose
+// strings and entitlement a config declares, or code's first HealthKit use
+// and write and its own data flow findings.
+type healthEvidence struct {
+	root            string
+	share, update   bool
+	entitlement     bool
+	usage, writeHit *Finding
+	findings        []Finding
+}
+
+func (r *HealthDataRule) Collect(fc FileContext) any {
+	ev := &healthEvidence{root: strings.TrimSuffix(fc.Path, fc.RelPath)}
+	if fc.Language == "plist" || fc.Language == "json" {
+		content := strings.Join(fc.Lines, "\n")
+		ev.share = strings.Contains(content, "NSHealthShareUsageDescription")
+		ev.update = strings.Contains(content, "NSHealthUpdateUsageDescription")
+		ev.entitlement = strings.Contains(content, "com.apple.developer.healthkit")
+		if !ev.share && !ev.update && !ev.entitlement {
+			return nil
+		}
+		return ev
+	}
+
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		if ev.usage == nil && healthFrameworkPattern.MatchString(line) {
+			ev.usage = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+		if ev.writeHit == nil && (healthWritePattern.MatchString(line) || sharesHealthTypes(line)) {
+			ev.writeHit = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+	}
+	if ev.usage != nil {
+		ev.findings = r.checkHealthDataFlow(fc)
+	} else if ev.writeHit == nil {
+		return nil
+	}
+	return ev
+}
+
+func (r *HealthDataRule) CheckProject(evidence []any) []Finding {
+	var (
+		findings      []Finding
+		usage         *Finding
+		writeHit      *Finding
+		share, update bool
+		entitlement   bool
+		root          string
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*healthEvidence)
+		if root == "" {
+			root = ev.root
+		}
+		share = share || ev.share
+		update = update || ev.update
+		entitlement = entitlement || ev.entitlement
+		if usage == nil {
+			usage = ev.usage
+		}
+		if writeHit == nil {
+			writeHit = ev.writeHit
+		}
+		findings = append(findings, ev.findings...)
+	}
+
+	if usage == nil {
+		return nil
+	}
+
+	generated := map[string]bool{}
+	if root != "" {
+		generated = generatedInfoPlistKeys(root)
+	}
+	declared := func(key string, inConfig bool) bool { return inConfig || generated[key] }
+	if !declared("NSHealthShareUsageDescription", share) {
+		findings = append(findings, Finding{
+			Severity:  SeverityCritical,
+			Guideline: "5.1.3",
+			Title:     "HealthKit used without NSHealthShareUsageDescription",
+			Detail:    "Reading health data requires a purpose string. Without it the authorization request crashes and the app is rejected.",
+			Fix:       "Add NSHealthShareUsageDescription to Info.plist (INFOPLIST_KEY_NSHealthShareUsageDescription in build settings when the Info.plist is generated) explaining exactly which health data is read and why.",
+			File:      usage.File,
+			Line:      usage.Line,
+			Code:      usage.Code,
+		})
+	}
+	if writeHit != nil && !declared("NSHealthUpdateUsageDescription", update) {
+		findings = append(findings, Finding{
+			Severity:  SeverityCritical,
+			Guideline: "5.1.3",
+			Title:     "HealthKit writes without NSHealthUpdateUsageDescription",
+			Detail:    "The app saves or requests write access to health data but has no update purpose string.",
+			Fix:       "Add NSHealthUpdateUsageDescription to Info.plist (INFOPLIST_KEY_NSHealthUpdateUsageDescription in build settings when the Info.plist is generated) explaining what the app writes to the Health app.",
+			File:      writeHit.File,
+			Line:      writeHit.Line,
+			Code:      writeHit.Code,
+		})
+	}
+	if !entitlement {
+		findings = append(findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "5.1.3",
+			Title:     "HealthKit used without the HealthKit entitlement",
+			Detail:    "No com.apple.developer.healthkit entitlement was found. HealthKit calls fail at runtime without it, which reviewers see as a broken feature.",
+			Fix:       "Enable the HealthKit capability for the app target (or the HealthKit config plugin for Expo).",
+			File:      usage.File,
+			Line:      usage.Line,
+			Code:      usage.Code,
+		})
+	}
+
+	return findings
+}
+
+// generatedInfoPlistKeys returns the Info.plist keys that app targets under
+// root set through build settings (INFOPLIST_KEY_<key> with
+// GENERATE_INFOPLIST_FILE), which never appear in a plist on disk.
+func generatedInfoPlistKeys(root string) map[string]bool {
+	keys := map[string]bool{}
+	for _, path := range xcodeproj.Find(root) {
+		proj, err := xcodeproj.Load(path)
+		if err != nil {
+			continue
+		}
+		for _, t := range proj.Targets {
+			if !t.IsApp() {
+				continue
+			}
+			for _, c := range t.Configs {
+				settings := proj.Effective(t, c.Name)
+				if v, _ := settings.Setting("GENERATE_INFOPLIST_FILE"); v != "YES" {
+					continue
+				}
+				for k := range settings.Settings {
+					if key, ok := strings.CutPrefix(k, "INFOPLIST_KEY_"); ok {
+						if v, _ := settings.Setting(k); strings.TrimSpace(v) != "" {
+							keys[key] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// checkHealthDataFlow flags iCloud storage and ad/analytics calls in a file that
+// handles health data. iCloud storage is CRITICAL only when a name bound to
+// health data reaches an iCloud API or record write; a file that merely uses
+// both is a WARN.
+func (r *HealthDataRule) checkHealthDataFlow(fc FileContext) []Finding {
+	var findings []Finding
+	handlesData := false
+	healthNames := make(map[string]bool)
+	for _, line := range fc.Lines {
+		if !healthDataTypePattern.MatchString(line) {
+			continue
+		}
+		handlesData = true
+		// The store itself holds no data.
+		if strings.Contains(line, "HKHealthStore") {
+			continue
+		}
+		for _, m := range healthTypedIdentPattern.FindAllStringSubmatch(line, -1) {
+			healthNames[m[1]] = true
+		}
+		if m := healthAssignedIdentPattern.FindStringSubmatch(line); m != nil {
+			healthNames[m[1]] = true
+		}
+	}
+	if !handlesData {
+		return nil
+	}
+	// Results arrive in the query's completion handler, often on the line
+	// after the HKSampleQuery.
+	for i, line := range fc.Lines {
+		if !healthDataTypePattern.MatchString(line) && (i == 0 || !healthDataTypePattern.MatchString(fc.Lines[i-1])) {
+			continue
+		}
+		if m := healthClosureParamsPattern.FindStringSubmatch(line); m != nil {
+			for _, name := range strings.Split(m[1], ",") {
+				healthNames[strings.TrimSpace(name)] = true
+			}
+		}
+	}
+	for _, name := range []string{"_", "error", "err", "query", "self"} {
+		delete(healthNames, name)
+	}
+
+	var icloud, flow *Finding
+	var adsDone bool
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		isICloud := healthICloudPattern.MatchString(line)
+		if icloud == nil && isICloud {
+			icloud = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+		if flow == nil && (isICloud || cloudWritePattern.MatchString(line)) && usesName(line, healthNames) {
+			flow = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+		if !adsDone && healthAdsPattern.MatchString(line) {
+			adsDone = true
+			findings = append(findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "5.1.3",
+				Title:     "Health data handled alongside advertising/analytics SDK",
+				Detail:    "This file handles HealthKit data and calls an ad or analytics SDK. Health data may not be used for advertising, marketing, or data mining.",
+				Fix:       "Make sure no health values are passed to ad/analytics events, and move tracking calls out of HealthKit code paths.",
+				File:      fc.RelPath,
+				Line:      lineNum + 1,
+				Code:      trimmed,
+			})
+		}
+	}
+
+	switch {
+	case icloud != nil && flow != nil:
+		findings = append(findings, Finding{
+			Severity:  SeverityCritical,
+			Guideline: "5.1.3",
+			Title:     "Health data stored in iCloud",
+			Detail:    "HealthKit data in this file is written to iCloud (CloudKit / key-value store). Apps may not store personal health information in iCloud.",
+			Fix:       "Keep HealthKit data on device or on your own HIPAA-appropriate backend; do not sync it through CloudKit or iCloud key-value storage.",
+			File:      flow.File,
+			Line:      flow.Line,
+			Code:      flow.Code,
+		})
+	case icloud != nil:
+		findings = append(findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "5.1.3",
+			Title:     "Health data may be stored in iCloud",
+			Detail:    "This file handles HealthKit data and also uses iCloud (CloudKit / key-value store). No health value was seen reaching the iCloud calls, but apps may not store personal health information in iCloud.",
+			Fix:       "Make sure no HealthKit values are synced through CloudKit or iCloud key-value storage; keep them on device or on your own HIPAA-appropriate backend.",
+			File:      icloud.File,
+			Line:      icloud.Line,
+			Code:      icloud.Code,
+		})
+	}
+	return findings
+}
+
+// usesName reports whether line mentions any of names as a whole identifier.
+func usesName(line string, names map[string]bool) bool {
+	for _, w := range strings.FieldsFunc(line, func(r rune) bool {
+		return !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	}) {
+		if names[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// sharesHealthTypes reports whether a requestAuthorization call asks for write access.
+func sharesHealthTypes(line string) bool {
+	m := healthShareSetPattern.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	switch strings.TrimSpace(m[1]) {
+	case "nil", "[]", "Set()", "[:]":
+		return false
+	}
+	return true
+}
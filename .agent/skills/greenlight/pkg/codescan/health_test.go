@@ -0,0 +1,85 @@
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const generatedPlistProject = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	objects = {
+		ROOT = {
+			isa = PBXProject;
+			buildConfigurationList = PROJECTCONFIGS;
+			targets = (APP);
+		};
+		PROJECTCONFIGS = {
+			isa = XCConfigurationList;
+			buildConfigurations = (PROJECTRELEASE);
+		};
+		PROJECTRELEASE = {
+			isa = XCBuildConfiguration;
+			name = Release;
+			buildSettings = {
+				GENERATE_INFOPLIST_FILE = YES;
+			};
+		};
+		APP = {
+			isa = PBXNativeTarget;
+			name = LifeFlow;
+			productType = "com.apple.product-type.application";
+			buildConfigurationList = APPCONFIGS;
+			buildPhases = ();
+		};
+		APPCONFIGS = {
+			isa = XCConfigurationList;
+			buildConfigurations = (APPRELEASE);
+		};
+		APPRELEASE = {
+			isa = XCBuildConfiguration;
+			name = Release;
+			buildSettings = {
+				INFOPLIST_KEY_NSHealthShareUsageDescription = "LifeFlow reads your workouts to plan recovery.";
+				INFOPLIST_KEY_NSHealthUpdateUsageDescription = "LifeFlow saves the workouts you log.";
+			};
+		};
+	};
+	rootObject = ROOT;
+}
+`
+
+const healthSource = `import HealthKit
+
+let healthStore = HKHealthStore()
+func log(_ workout: HKWorkout) {
+    healthStore.save(workout) { _, _ in }
+}`
+
+func runHealthRule(t *testing.T, root string) []Finding {
+	t.Helper()
+	return checkProject(&HealthDataRule{id: "health-data"},
+		FileContext{Path: filepath.Join(root, "App/App.entitlements"), RelPath: "App/App.entitlements", Lines: []string{"<key>com.apple.developer.healthkit</key><true/>"}, Language: "plist"},
+		FileContext{Path: filepath.Join(root, "App/Health.swift"), RelPath: "App/Health.swift", Lines: strings.Split(healthSource, "\n"), Language: "swift"},
+	)
+}
+
+func TestHealthPurposeStringsFromGeneratedInfoPlist(t *testing.T) {
+	root := t.TempDir()
+	if got := runHealthRule(t, root); len(got) != 2 {
+		t.Fatalf("without purpose strings: got %d findings, want share and update: %+v", len(got), got)
+	}
+
+	pbxproj := filepath.Join(root, "LifeFlow.xcodeproj", "project.pbxproj")
+	if err := os.MkdirAll(filepath.Dir(pbxproj), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pbxproj, []byte(generatedPlistProject), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := runHealthRule(t, root); len(got) != 0 {
+		t.Errorf("purpose strings set with INFOPLIST_KEY_ build settings were reported: %+v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Start of the code that runs before the first frame: the app delegate's
+	// launch callbacks and a SwiftUI App's initializer.
+	launchEntryPattern = regexp.MustCompile(`(func\s+application\s*\(\s*_\s+\w+\s*:\s*UIApplication\s*,\s*(did|will)FinishLaunchingWithOptions|-\s*\(BOOL\)\s*application\s*:\s*\(UIApplication\s*\*\)\s*\w+\s+(did|will)FinishLaunchingWithOptions)`)
+	swiftUIAppPattern  = regexp.MustCompile(`struct\s+\w+\s*:\s*App\b`)
+	swiftUIInitPattern = regexp.MustCompile(`^\s*init\s*\(\s*\)`)
+
+	// Calls that block the main thread on the network or a wait.
+	launchBlockingPattern = regexp.MustCompile(`(Data\s*\(\s*contentsOf:|String\s*\(\s*contentsOf:|dataWithContentsOfURL|stringWithContentsOfURL|NSData\s+dataWithContentsOfURL|sendSynchronousRequest|\.wait\s*\(\s*\)|\.wait\s*\(\s*timeout:|dispatch_semaphore_wait|dispatch_group_wait|Thread\.sleep|\bsleep\s*\(|\busleep\s*\()`)
+
+	// Data(contentsOf:) on bundled or local files is not a network call.
+	localFilePattern = regexp.MustCompile(`(?i)(Bundle\.main|mainBundle|fileURL|FileManager|documentDirectory|NSDocumentDirectory|\.path\b|pathForResource|url\(forResource)`)
+)
+
+// LaunchBlockingRule flags synchronous network calls and waits inside
+// didFinishLaunchingWithOptions (or a SwiftUI App initializer). The watchdog
+// terminates apps that take too long to launch, and slow launches on review
+// devices are rejected under 2.1.
+type LaunchBlockingRule struct {
+	id string
+}
+
+func (r *LaunchBlockingRule) Applies(fc FileContext) bool {
+	return fc.Language == "swift" || fc.Language == "objc"
+}
+
+func (r *LaunchBlockingRule) Check(fc FileContext) []Finding {
+	var findings []Finding
+	isSwiftUIApp := false
+	for _, line := range fc.Lines {
+		if swiftUIAppPattern.MatchString(line) {
+			isSwiftUIApp = true
+			break
+		}
+	}
+
+	depth := 0 // brace depth inside the launch function
+	inLaunch := false
+	for i := range fc.Lines {
+		line := fc.codeLine(i)
+		if !inLaunch {
+			if launchEntryPattern.MatchString(line) || (isSwiftUIApp && swiftUIInitPattern.MatchString(line)) {
+				inLaunch = true
+				depth = 0
+			} else {
+				continue
+			}
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "//") && launchBlockingPattern.MatchString(line) && !localFilePattern.MatchString(line) {
+			findings = append(findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "2.1",
+				Title:     "Blocking call during app launch",
+				Detail:    "This runs synchronously on the main thread before the first frame. A slow or unreachable network on the review device stalls launch, and the watchdog kills apps that take too long to start — a common 2.1 crash-on-launch rejection.",
+				Fix:       "Move network and waiting work off the launch path: load asynchronously (URLSession, async/await) after the first screen is shown, and start from cached or bundled data.",
+				File:      fc.RelPath,
+				Line:      i + 1,
+				Code:      strings.TrimSpace(fc.Lines[i]),
+			})
+		}
+
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 && strings.Contains(line, "}") {
+			inLaunch = false
+		}
+	}
+	return findings
+}
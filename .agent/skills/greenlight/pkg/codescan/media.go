@@ -0,0 +1,147 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Downloading or converting media from other services (5.2.3).
+	mediaDownloadPattern    = regexp.MustCompile(`(?i)\b((youtube|video|music|instagram|tiktok|soundcloud|vimeo|spotify) (downloader|download(s|ing)?|saver|to mp3)|download (videos|music|songs|movies) from|save (videos|music|songs) from|youtube[- ]?to[- ]?mp3|torrents?)\b`)
+	mediaDownloadLibPattern = regexp.MustCompile(`(yt-dlp|youtube-dl|ytdl-core|react-native-ytdl|XCDYouTubeKit|YoutubeDL|libtorrent|webtorrent|TorrentKit)`)
+	// Streaming catalogs of movies, TV, sports or music (5.2.2).
+	mediaStreamPattern    = regexp.MustCompile(`(?i)\b(stream(ing)? (movies|films|tv|tv shows|series|anime|live tv|live sports|sports|music|songs)|watch (free )?(movies|films|tv shows|series|anime|live tv|live sports)|free movies|movies online|live tv channels|iptv|m3u playlists?)\b`)
+	mediaStreamLibPattern = regexp.MustCompile(`(?i)(\bM3UParser\b|\bm3u-parser\b|\biptv-?\w*|\.m3u["'\x60])`)
+
+	// mediaLicensePattern matches notes that account for the rights to the
+	// content.
+	mediaLicensePattern = regexp.MustCompile(`(?i)\b(licen[cs](e|es|ed|ing)|rights ?(holders?|owners?)|distribution (agreements?|rights)|content (agreements?|partners?|providers?)|authori[sz](ation|ed) (by|from)|permission (from|of) the|public domain|creative commons|we (own|produce) (the|all|our)|our own (content|catalog|productions?))\b`)
+)
+
+// MediaContent is a use of third-party media: streaming catalogs (5.2.2)
+// or downloading from other services (5.2.3).
+type MediaContent struct {
+	Text      string
+	Guideline string
+	Download  bool
+}
+
+// FindMediaContent returns the first third-party media feature described in
+// text, downloads first, or nil.
+func FindMediaContent(text string) *MediaContent {
+	if m := mediaDownloadPattern.FindString(text); m != "" {
+		return &MediaContent{Text: m, Guideline: "5.2.3", Download: true}
+	}
+	if m := mediaStreamPattern.FindString(text); m != "" {
+		return &MediaContent{Text: m, Guideline: "5.2.2"}
+	}
+	return nil
+}
+
+// MentionsMediaLicense reports whether text accounts for the rights to the
+// app's content: licenses, distribution agreements, public domain.
+func MentionsMediaLicense(text string) bool {
+	return mediaLicensePattern.MatchString(text)
+}
+
+// MediaRightsFix is what App Review asks media apps for.
+const MediaRightsFix = "Put the licensing in the App Review notes: who supplies the content, the agreements or written authorization from the rights holders covering each territory the app is sold in, and a contact at the content provider. Attach the documentation, or state that the content is your own or in the public domain. Apps that stream other people's content without this are rejected under 5.2.2, and the app may be removed on a rights holder's complaint."
+
+// MediaRightsRule finds apps that stream movies, TV, sports or music from
+// third-party sources, or download media from other services, which
+// reviewers reject unless the developer documents the rights to the
+// content.
+type MediaRightsRule struct {
+	id string
+}
+
+func (r *MediaRightsRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "json":
+		return !isToolingConfig(fc.RelPath)
+	}
+	return false
+}
+
+func (r *MediaRightsRule) Check(fc FileContext) []Finding { return nil }
+
+// mediaEvidence is a file's first streaming and first download hit, with
+// the matched text in Title.
+type mediaEvidence struct {
+	stream, download *Finding
+}
+
+func (r *MediaRightsRule) Collect(fc FileContext) any {
+	var stream, download *Finding
+	for lineNum, line := range fc.Lines {
+		if stream != nil && download != nil {
+			break
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		hit := &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		if download == nil {
+			if m := mediaDownloadLibPattern.FindString(line); m != "" {
+				hit.Title = m
+				download = hit
+				continue
+			}
+			if mc := FindMediaContent(line); mc != nil && mc.Download {
+				hit.Title = mc.Text
+				download = hit
+				continue
+			}
+		}
+		if stream == nil {
+			if m := mediaStreamLibPattern.FindString(line); m != "" {
+				hit.Title = m
+				stream = hit
+			} else if mc := FindMediaContent(line); mc != nil && !mc.Download {
+				hit.Title = mc.Text
+				stream = hit
+			}
+		}
+	}
+	if stream == nil && download == nil {
+		return nil
+	}
+	return &mediaEvidence{stream: stream, download: download}
+}
+
+func (r *MediaRightsRule) CheckProject(evidence []any) []Finding {
+	var stream, download *Finding
+	for _, ev := range evidence {
+		ev := ev.(*mediaEvidence)
+		if stream == nil {
+			stream = ev.stream
+		}
+		if download == nil {
+			download = ev.download
+		}
+	}
+
+	var findings []Finding
+	if download != nil {
+		f := *download
+		f.RuleID = r.id
+		f.Severity = SeverityCritical
+		f.Guideline = "5.2.3"
+		f.Title = "Downloads media from third-party services"
+		f.Detail = "Found \"" + download.Title + "\". Apps may not save, convert or download media from sources such as YouTube, SoundCloud, Vimeo or Apple Music, or facilitate file sharing, without explicit authorization from the service."
+		f.Fix = "Remove the download feature, or get written authorization from the service and include it in the App Review notes."
+		findings = append(findings, f)
+	}
+	if stream != nil {
+		f := *stream
+		f.RuleID = r.id
+		f.Severity = SeverityWarn
+		f.Guideline = "5.2.2"
+		f.Title = "Streams third-party media: document your licensing"
+		f.Detail = "Found \"" + stream.Title + "\". Reviewers ask streaming apps for proof of the rights to the movies, TV, sports or music they offer, and reject apps that can't show it."
+		f.Fix = MediaRightsFix
+		findings = append(findings, f)
+	}
+	return findings
+}
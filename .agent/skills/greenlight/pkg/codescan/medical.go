@@ -0,0 +1,251 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MedicalCategory is a kind of health feature that reviewers expect a
+// disclaimer for, with the guidance that applies to it.
+type MedicalCategory struct {
+	Name      string
+	Guideline string
+	// Disclaimer is what the app should tell users.
+	Disclaimer string
+	Fix        string
+
+	keywords *regexp.Regexp
+	// disclaimer matches text that covers this category.
+	disclaimer *regexp.Regexp
+}
+
+// Match returns the first keyword of the category in text, or "".
+func (c MedicalCategory) Match(text string) string {
+	return c.keywords.FindString(text)
+}
+
+// Disclaimed reports whether text contains a disclaimer for the category.
+func (c MedicalCategory) Disclaimed(text string) bool {
+	return c.disclaimer.MatchString(text)
+}
+
+var (
+	medicalAdviceDisclaimer = regexp.MustCompile(`(?i)(not (a substitute|intended to (replace|diagnose|treat|provide medical))|not medical advice|does not (provide|replace|constitute) (medical|professional)|for (informational|educational) purposes only|consult (a|your) (doctor|physician|pharmacist|healthcare|health care|medical)|seek (professional )?medical (advice|attention|help))`)
+	emergencyDisclaimer     = regexp.MustCompile(`(?i)(in (case of )?(an? )?emergency,? (call|dial|contact)|call (911|999|112|000|your local emergency)|(contact|call) (local )?emergency services|not (for|intended for) (use in )?emergencies|(call|text|dial) 988\b)`)
+)
+
+// Dotted names in code and string literals: SF Symbols
+// ("waveform.path.ecg"), asset and bundle identifiers, property chains and
+// Swift enum cases. A health term inside one is not copy the user reads.
+var dottedIdentPattern = regexp.MustCompile(`(\b[A-Za-z_][\w-]*)?(\.[A-Za-z_][\w-]*)+`)
+
+// medicalCopy returns line with dotted identifiers blanked, so health terms
+// only match as words of user-facing text.
+func medicalCopy(line string) string {
+	return dottedIdentPattern.ReplaceAllString(line, " ")
+}
+
+// Wording that presents a feature as medical care rather than information,
+// which makes a missing disclaimer more serious, and its negations ("does
+// not diagnose"), which are disclaimers instead.
+var (
+	healthClaimPattern  = regexp.MustCompile(`(?i)\b(diagnos(e|es|ing)|treats?|treating|cures?|curing|heals?|healing)\b`)
+	negatedClaimPattern = regexp.MustCompile(`(?i)\b(not|never|doesn'?t|won'?t|cannot|can'?t)\s+(intended to\s+|meant to\s+|designed to\s+)?(diagnos\w*|treat\w*|cure\w*|heal\w*)((,?\s+(or|and)\s+|,\s*)(diagnos\w*|treat\w*|cure\w*|heal\w*))*`)
+)
+
+// MedicalCategories are the health features checked for disclaimers, in
+// code and in App Store metadata.
+var MedicalCategories = []MedicalCategory{
+	{
+		Name:       "telehealth",
+		Guideline:  "5.1.3",
+		Disclaimer: "that consultations don't replace in-person care or emergency services",
+		Fix:        "State that the service is not for emergencies, show that providers are licensed where the user is, and explain how consultation notes and health data are stored and shared.",
+		keywords:   regexp.MustCompile(`(?i)\b(tele-?health|tele-?medicine|virtual (visit|consultation|care)|video (visit|consultation)|(talk|chat|speak) (to|with) an? (doctor|physician|nurse|clinician))\b`),
+		disclaimer: regexp.MustCompile(medicalAdviceDisclaimer.String() + `|` + emergencyDisclaimer.String()),
+	},
+	{
+		Name:       "medication",
+		Guideline:  "1.4.2",
+		Disclaimer: "to follow the instructions of their doctor or pharmacist",
+		Fix:        "Present doses as what the prescriber set, not as advice, and tell users to follow their doctor's or pharmacist's instructions. Drug dosage calculators must come from a drug manufacturer, hospital, university, health insurer or pharmacy, or have FDA (or equivalent) approval.",
+		keywords:   regexp.MustCompile(`(?i)\b(medications?|dosages?|prescriptions?|pill reminders?|refill reminders?|drug interactions?)\b`),
+		disclaimer: medicalAdviceDisclaimer,
+	},
+	{
+		Name:       "symptoms and diagnosis",
+		Guideline:  "1.4.1",
+		Disclaimer: "that the app provides information, not a diagnosis",
+		Fix:        "Say the app does not diagnose and that users should consult a doctor. For readings such as blood pressure or blood oxygen, disclose the method and validation data, and any regulatory clearance, in the description and in App Review notes.",
+		keywords:   regexp.MustCompile(`(?i)\b(symptom checker|symptoms|diagnos(is|es|e)|blood pressure|blood (glucose|sugar)|oxygen saturation|SpO2|ECG|EKG)\b`),
+		disclaimer: medicalAdviceDisclaimer,
+	},
+	{
+		Name:       "emergency",
+		Guideline:  "1.4.1",
+		Disclaimer: "how to reach emergency services",
+		Fix:        "Tell users the app does not replace emergency services and how to reach them (911 or the local emergency number; 988 for crisis support in the US), and don't rely on the app to contact help on the user's behalf.",
+		keywords:   regexp.MustCompile(`(?i)\b(emergency services|medical emergenc(y|ies)|crisis (line|support|hotline)|suicid(e|al)|overdose|first aid|CPR)\b`),
+		disclaimer: emergencyDisclaimer,
+	},
+}
+
+// MedicalClaim is a health claim that guideline 1.4 rejects.
+type MedicalClaim struct {
+	Text      string
+	Guideline string
+	Reason    string
+}
+
+var medicalClaims = []struct {
+	guideline string
+	reason    string
+	pattern   *regexp.Regexp
+}{
+	{"1.4.2", "Drug dosage calculators must come from a drug manufacturer, hospital, university, health insurer or pharmacy, or have FDA (or equivalent) approval.", regexp.MustCompile(`(?i)\b(dos(e|age|ing)\s*calculator|calculates? (your |the )?(dose|dosage)|how much (insulin|medication) to take)\b`)},
+	{"1.4.1", "Apps may not claim to measure blood pressure, body temperature, blood glucose or blood oxygen, or take x-rays, using only the device's sensors.", regexp.MustCompile(`(?i)\b(measures?|checks?|reads?|tracks?) (your )?(blood pressure|body temperature|blood (glucose|sugar)|blood oxygen|oxygen saturation|SpO2)\b[^"'\x60.]*\b(with|using|through|from) (just |only )?(your |the )?(phone|iphone|camera|finger|flashlight)\b|\bx-?ray (scanner|vision|camera)\b`)},
+	{"1.4.1", "Medical apps that claim to diagnose, cure or detect conditions are rejected unless the claims are validated and cleared by the relevant regulator.", regexp.MustCompile(`(?i)\b(diagnoses? (your|any|all)|(cures?|heals?) (cancer|diabetes|covid|depression|anxiety|autism|disease)|detects? (cancer|melanoma|covid|diabetes|heart attacks?|strokes?)|replaces? your doctor|no need (for|to see) a doctor|100% accurate (diagnosis|results))\b`)},
+}
+
+// MakesHealthClaim reports whether text says the app diagnoses, treats or
+// cures something. A health feature described that way needs its disclaimer
+// more than one that only mentions medications or symptoms.
+func MakesHealthClaim(text string) bool {
+	return healthClaimPattern.MatchString(negatedClaimPattern.ReplaceAllString(text, ""))
+}
+
+// DisclaimerSeverity is the severity of a missing disclaimer: INFO when the
+// feature is only mentioned, WARN when it is presented as diagnosing,
+// treating or curing.
+func DisclaimerSeverity(claimed bool) Severity {
+	if claimed {
+		return SeverityWarn
+	}
+	return SeverityInfo
+}
+
+// FindMedicalClaims returns the claims in text that guideline 1.4 rejects.
+func FindMedicalClaims(text string) []MedicalClaim {
+	var out []MedicalClaim
+	for _, c := range medicalClaims {
+		if m := c.pattern.FindString(text); m != "" {
+			out = append(out, MedicalClaim{Text: m, Guideline: c.guideline, Reason: c.reason})
+		}
+	}
+	return out
+}
+
+// MedicalDisclaimerRule finds health features (telehealth, medication,
+// symptoms and diagnosis, emergencies) in app code and checks that the
+// app shows the matching disclaimer, and flags medical claims that
+// guideline 1.4 rejects, such as dosage calculators or diagnosis promises.
+type MedicalDisclaimerRule struct {
+	id string
+}
+
+func (r *MedicalDisclaimerRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript":
+		return !isToolingConfig(fc.RelPath)
+	}
+	return false
+}
+
+func (r *MedicalDisclaimerRule) Check(fc FileContext) []Finding { return nil }
+
+// medicalEvidence is what one file contributes to MedicalDisclaimerRule, per
+// category indexed like MedicalCategories: the first keyword hit and whether
+// the file disclaims it. findings are the file's prohibited claims.
+type medicalEvidence struct {
+	hits       []*Finding
+	keywords   []string
+	disclaimed []bool
+	claimed    bool
+	findings   []Finding
+}
+
+func (r *MedicalDisclaimerRule) Collect(fc FileContext) any {
+	ev := &medicalEvidence{
+		hits:       make([]*Finding, len(MedicalCategories)),
+		keywords:   make([]string, len(MedicalCategories)),
+		disclaimed: make([]bool, len(MedicalCategories)),
+	}
+	found := false
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		text := medicalCopy(line)
+		if !ev.claimed && MakesHealthClaim(text) {
+			ev.claimed, found = true, true
+		}
+		for i, c := range MedicalCategories {
+			if ev.hits[i] == nil {
+				if kw := c.Match(text); kw != "" {
+					ev.hits[i] = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+					ev.keywords[i] = kw
+					found = true
+				}
+			}
+			if !ev.disclaimed[i] && c.Disclaimed(text) {
+				ev.disclaimed[i], found = true, true
+			}
+		}
+		for _, claim := range FindMedicalClaims(text) {
+			ev.findings = append(ev.findings, Finding{
+				RuleID:    r.id,
+				Severity:  SeverityCritical,
+				Guideline: claim.Guideline,
+				Title:     "Prohibited medical claim: \"" + claim.Text + "\"",
+				Detail:    claim.Reason,
+				Fix:       "Remove the claim, or back it with regulatory clearance and the data behind it, documented in App Review notes.",
+				File:      fc.RelPath,
+				Line:      lineNum + 1,
+				Code:      trimmed,
+			})
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return ev
+}
+
+func (r *MedicalDisclaimerRule) CheckProject(evidence []any) []Finding {
+	var (
+		findings   []Finding
+		hits       = make([]*Finding, len(MedicalCategories))
+		keywords   = make([]string, len(MedicalCategories))
+		disclaimed = make([]bool, len(MedicalCategories))
+		claimed    bool
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*medicalEvidence)
+		claimed = claimed || ev.claimed
+		for i := range MedicalCategories {
+			if hits[i] == nil && ev.hits[i] != nil {
+				hits[i], keywords[i] = ev.hits[i], ev.keywords[i]
+			}
+			disclaimed[i] = disclaimed[i] || ev.disclaimed[i]
+		}
+		findings = append(findings, ev.findings...)
+	}
+
+	for i, c := range MedicalCategories {
+		if hits[i] == nil || disclaimed[i] {
+			continue
+		}
+		f := *hits[i]
+		f.RuleID = r.id
+		f.Severity = DisclaimerSeverity(claimed)
+		f.Guideline = c.Guideline
+		f.Title = "No " + c.Name + " disclaimer"
+		f.Detail = "The app has " + c.Name + " features (\"" + keywords[i] + "\") but no text telling users " + c.Disclaimer + ". Reviewers reject health apps that could put users at risk without one."
+		f.Fix = c.Fix
+		findings = append(findings, f)
+	}
+	return findings
+}
@@ -0,0 +1,26 @@
+package codescan
+
+import "testing"
+
+func TestMedicalTermsInUserFacingCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"SF Symbol", `Image(systemName: "waveform.path.ecg")`, 0},
+		{"asset and enum", `Image("icons.medication")
+case .symptoms:
+let rows = store.medications`, 0},
+		{"copy", `Text("Record an ECG from your watch")`, 1},
+	}
+	rule := &MedicalDisclaimerRule{id: "medical-disclaimer"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkProject(rule, swiftFile("App/Vitals.swift", tt.src))
+			if len(got) != tt.want {
+				t.Errorf("got %d findings, want %d: %+v", len(got), tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !unix
+
+package codescan
+
+import (
+	"os"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// mapLines falls back to reading the whole file where memory mappings are
+// not available.
+func mapLines(path string, bundle bool) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bundle {
+		return strings.Split(string(data), "\n"), nil
+	}
+	if sourcefile.IsBinary(data) {
+		return nil, sourcefile.ErrBinary
+	}
+	return splitLines(data), nil
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package codescan
+
+import (
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// mapLines reads path through a read-only memory mapping. Lines are copied
+// out before the mapping is released; binary files return
+// sourcefile.ErrBinary. Bundles keep every "\n"-separated segment, as
+// os.ReadFile-based reads do.
+func mapLines(path string, bundle bool) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		if bundle {
+			return []string{""}, nil
+		}
+		return nil, nil
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Munmap(data)
+
+	if bundle {
+		return strings.Split(string(data), "\n"), nil
+	}
+	if sourcefile.IsBinary(data) {
+		return nil, sourcefile.ErrBinary
+	}
+	return splitLines(data), nil
+}
@@ -0,0 +1,332 @@
+package codescan
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// otaFramework is an over-the-air update SDK that replaces the JS bundle or
+// web assets without a new App Store build.
+type otaFramework struct {
+	name    string
+	pattern *regexp.Regexp
+	// signing matches the config that enables update signature checks.
+	signing *regexp.Regexp
+	// hosted reports whether an update URL points at the vendor's service.
+	hosted func(host string) bool
+}
+
+var (
+	otaFrameworks = []otaFramework{
+		{
+			name:    "CodePush",
+			pattern: regexp.MustCompile(`(react-native-code-push|@revopush/react-native-code-push|import\s+codePush|codePush\s*\(|CodePush\.sync|codePush\.sync|CodePush\s+bundleURL|CodePush\.bundleURL|CodePushDeploymentKey)`),
+			signing: regexp.MustCompile(`(CodePushPublicKey|CodePushSigningPublicKey|"publicKey"|publicKey\s*:)`),
+			hosted: func(host string) bool {
+				return strings.HasSuffix(host, "appcenter.ms") || strings.HasSuffix(host, "revopush.org")
+			},
+		},
+		{
+			name:    "expo-updates",
+			pattern: regexp.MustCompile(`("expo-updates"|from\s+['"]expo-updates['"]|Updates\.(checkForUpdateAsync|fetchUpdateAsync|reloadAsync)|EXUpdatesURL)`),
+			signing: regexp.MustCompile(`(codeSigningCertificate|EXUpdatesCodeSigningCertificate)`),
+			hosted: func(host string) bool {
+				return host == "u.expo.dev" || strings.HasSuffix(host, ".expo.dev") || host == "exp.host"
+			},
+		},
+		{
+			name:    "Capacitor live updates",
+			pattern: regexp.MustCompile(`(@capgo/capacitor-updater|@capacitor/live-updates|cordova-plugin-ionic|@capawesome/capacitor-live-update|CapacitorUpdater\.)`),
+			signing: regexp.MustCompile(`(publicKey|privateKey|signatureVerification|encryption)`),
+			hosted: func(host string) bool {
+				return strings.HasSuffix(host, "capgo.app") || strings.HasSuffix(host, "ionicframework.com") || strings.HasSuffix(host, "ionic.io")
+			},
+		},
+		{
+			name:    "Hot update",
+			pattern: regexp.MustCompile(`(cordova-hot-code-push|react-native-ota-hot-update|@hot-updater/|react-native-update\b|react-native-pushy)`),
+			signing: regexp.MustCompile(`(publicKey|signature|checksum)`),
+			hosted:  func(host string) bool { return false },
+		},
+	}
+
+	// Native hot-patching: replaces compiled code at runtime, never allowed.
+	nativePatchPattern = regexp.MustCompile(`(JSPatch|JPEngine|import\s+Rollout\b|Rollout\.setup|ROLLOUT|WaxPatch|DynamicCocoa|import\s+Hotfix\b)`)
+
+	// Applying an update by restarting the app under the user.
+	otaRestartPattern = regexp.MustCompile(`(InstallMode\.IMMEDIATE|restartApp\s*\(|Updates\.reloadAsync\s*\(|reloadAsync\s*\(|HotUpdater\.reload\s*\(|CapacitorUpdater\.(set|reload)\s*\()`)
+	otaFetchPattern   = regexp.MustCompile(`(codePush\.sync|CodePush\.sync|codePush\s*\(|fetchUpdateAsync|checkForUpdateAsync|CapacitorUpdater\.download|checkForUpdate)`)
+	otaPromptPattern  = regexp.MustCompile(`(updateDialog|Alert\.alert|confirm\s*\(|onPress|showModal|InstallMode\.ON_NEXT_(RESTART|RESUME|SUSPEND))`)
+
+	// Update server URLs: Expo.plist/Info.plist keys, JS SDK options, and the
+	// "url" of an app.json "updates" block (matched separately).
+	otaURLPattern         = regexp.MustCompile(`(EXUpdatesURL|CodePushServerURL|serverUrl|updateUrl|updateURL)["']?\s*(</key>\s*<string>|[:=])\s*["']?(https?://[^"'<\s]+)`)
+	expoUpdatesURLPattern = regexp.MustCompile(`"url"\s*:\s*"(https?://[^"]+)"`)
+
+	// expo-updates compatibility pinning between an update and the binary.
+	otaRuntimeVersionPattern = regexp.MustCompile(`(runtimeVersion|EXUpdatesRuntimeVersion)`)
+)
+
+// OTAUpdateRule checks over-the-air update frameworks against the Developer
+// Program License Agreement §3.3.1(B) limits behind guideline 2.5.2: updates
+// may change interpreted code (JS, web assets) but not native code or the
+// app's primary purpose, must not force restarts on the user, and should
+// come from a trusted, signed source.
+type OTAUpdateRule struct {
+	id string
+}
+
+func (r *OTAUpdateRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript", "json", "plist":
+		return true
+	}
+	return false
+}
+
+func (r *OTAUpdateRule) Check(fc FileContext) []Finding { return nil }
+
+// otaEvidence is what one file contributes to OTAUpdateRule: its own
+// findings, the first use of each framework, the update URLs it configures,
+// and for config files, which frameworks it configures signing for and
+// whether it sets a runtimeVersion.
+type otaEvidence struct {
+	findings       []Finding
+	used           map[string]*Finding
+	urls           []otaURL
+	signed         map[string]bool
+	runtimeVersion bool
+}
+
+func (r *OTAUpdateRule) Collect(fc FileContext) any {
+	ev := &otaEvidence{used: map[string]*Finding{}, signed: map[string]bool{}}
+	content := strings.Join(fc.Lines, "\n")
+	if fc.Language == "json" || fc.Language == "plist" {
+		for _, fw := range otaFrameworks {
+			if fw.signing.MatchString(content) {
+				ev.signed[fw.name] = true
+			}
+		}
+		ev.runtimeVersion = otaRuntimeVersionPattern.MatchString(content)
+	}
+	ev.urls = findOTAURLs(fc, content)
+
+	var fetches, restarts, prompts bool
+	restartLine := 0
+	for i := range fc.Lines {
+		line := fc.codeLine(i)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		for _, fw := range otaFrameworks {
+			if ev.used[fw.name] == nil && fw.pattern.MatchString(line) {
+				ev.used[fw.name] = &Finding{File: fc.RelPath, Line: i + 1, Code: trimmed}
+			}
+		}
+		if fc.Language != "json" && fc.Language != "plist" && nativePatchPattern.MatchString(line) {
+			ev.findings = append(ev.findings, Finding{
+				Severity:  SeverityCritical,
+				Guideline: "2.5.2",
+				Title:     "Native hot-patching framework detected",
+				Detail:    "JSPatch, Rollout and similar frameworks rewrite native methods at runtime. Apple rejects and removes apps that can change native code after review (guideline 2.5.2, DPLA §3.3.1(B)).",
+				Fix:       "Remove the hot-patching SDK and ship native fixes through App Store updates.",
+				File:      fc.RelPath,
+				Line:      i + 1,
+				Code:      trimmed,
+			})
+		}
+		if otaFetchPattern.MatchString(line) {
+			fetches = true
+		}
+		if otaPromptPattern.MatchString(line) {
+			prompts = true
+		}
+		if restartLine == 0 && otaRestartPattern.MatchString(line) {
+			restarts = true
+			restartLine = i + 1
+		}
+	}
+
+	if fetches && restarts && !prompts {
+		ev.findings = append(ev.findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "2.5.2",
+			Title:     "OTA update forces an app restart",
+			Detail:    "An update is downloaded and applied immediately by reloading the app, without asking the user. Forced restarts interrupt the user (and the reviewer), and a bad update reloaded on every launch becomes a restart loop that reads as a crash.",
+			Fix:       "Apply updates on the next launch or resume (InstallMode.ON_NEXT_RESTART, or reloadAsync only after the user confirms), and roll back updates that fail to start.",
+			File:      fc.RelPath,
+			Line:      restartLine,
+			Code:      strings.TrimSpace(fc.Lines[restartLine-1]),
+		})
+	}
+
+	if len(ev.findings) == 0 && len(ev.used) == 0 && len(ev.urls) == 0 && len(ev.signed) == 0 && !ev.runtimeVersion {
+		return nil
+	}
+	return ev
+}
+
+func (r *OTAUpdateRule) CheckProject(evidence []any) []Finding {
+	var findings []Finding
+	used := map[string]*Finding{}
+	var urls []otaURL
+	signed := map[string]bool{}
+	runtimeVersion := false
+	for _, ev := range evidence {
+		ev := ev.(*otaEvidence)
+		findings = append(findings, ev.findings...)
+		for name, hit := range ev.used {
+			if used[name] == nil {
+				used[name] = hit
+			}
+		}
+		urls = append(urls, ev.urls...)
+		for name := range ev.signed {
+			signed[name] = true
+		}
+		runtimeVersion = runtimeVersion || ev.runtimeVersion
+	}
+
+	if len(used) == 0 {
+		return findings
+	}
+
+	for _, u := range urls {
+		fw, ok := otaFrameworkNamed(u.framework)
+		if !ok || used[fw.name] == nil {
+			continue
+		}
+		parsed, err := url.Parse(u.url)
+		if err != nil || parsed.Host == "" || parsed.Hostname() == "localhost" || parsed.Hostname() == "127.0.0.1" {
+			continue
+		}
+		if parsed.Scheme == "http" {
+			findings = append(findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "2.5.2",
+				Title:     "OTA update URL is not HTTPS: " + u.url,
+				Detail:    fw.name + " downloads code from " + u.url + " over plain HTTP. Anyone on the network can replace the bundle the app executes.",
+				Fix:       "Serve updates over HTTPS and enable update signing.",
+				File:      u.file,
+				Line:      u.line,
+			})
+		} else if !fw.hosted(parsed.Hostname()) && !signed[fw.name] {
+			findings = append(findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "2.5.2",
+				Title:     "Self-hosted OTA updates without code signing",
+				Detail:    fw.name + " pulls updates from " + parsed.Host + " and no signing key is configured, so the app runs whatever bundle that server returns. A compromised server ships arbitrary behavior that never went through review.",
+				Fix:       "Configure update code signing (expo-updates codeSigningCertificate, CodePush CodePushPublicKey) so the app rejects unsigned bundles.",
+				File:      u.file,
+				Line:      u.line,
+			})
+		}
+	}
+
+	for _, fw := range otaFrameworks {
+		hit := used[fw.name]
+		if hit == nil {
+			continue
+		}
+
+		if fw.name == "expo-updates" && !runtimeVersion {
+			findings = append(findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "2.5.2",
+				Title:     "expo-updates without a runtimeVersion",
+				Detail:    "Without runtimeVersion an update can be delivered to binaries built with different native code. Updates must only change JS and assets (DPLA §3.3.1(B)); mismatched native code crashes on launch.",
+				Fix:       "Set \"runtimeVersion\": { \"policy\": \"fingerprint\" } (or \"appVersion\") in app.json so updates only reach compatible builds.",
+				File:      hit.File,
+				Line:      hit.Line,
+				Code:      hit.Code,
+			})
+		}
+
+		findings = append(findings, Finding{
+			Severity:  SeverityInfo,
+			Guideline: "2.5.2",
+			Title:     "OTA updates via " + fw.name,
+			Detail:    "Over-the-air updates may only change interpreted code and assets, must not change the app's primary purpose or add features that bypass review, and must not alter native code (DPLA §3.3.1(B)).",
+			Fix:       "Keep OTA releases to JS/asset fixes; ship native and feature changes through App Store review.",
+			File:      hit.File,
+			Line:      hit.Line,
+			Code:      hit.Code,
+		})
+	}
+
+	return findings
+}
+
+// otaURL is an update server URL found in config or code.
+type otaURL struct {
+	framework string
+	url       string
+	file      string
+	line      int
+}
+
+func otaFrameworkNamed(name string) (otaFramework, bool) {
+	for _, fw := range otaFrameworks {
+		if fw.name == name {
+			return fw, true
+		}
+	}
+	return otaFramework{}, false
+}
+
+// findOTAURLs returns the update server URLs configured in a file, with the
+// framework each one belongs to.
+func findOTAURLs(fc FileContext, content string) []otaURL {
+	var urls []otaURL
+	lineAt := func(offset int) int { return strings.Count(content[:offset], "\n") + 1 }
+
+	for _, m := range otaURLPattern.FindAllStringSubmatchIndex(content, -1) {
+		key, u := content[m[2]:m[3]], content[m[6]:m[7]]
+		framework := "CodePush"
+		switch {
+		case key == "EXUpdatesURL":
+			framework = "expo-updates"
+		case key != "CodePushServerURL" && strings.Contains(content, "Capacitor"):
+			framework = "Capacitor live updates"
+		}
+		urls = append(urls, otaURL{framework, u, fc.RelPath, lineAt(m[0])})
+	}
+
+	// app.json / app.config: expo.updates.url
+	if fc.Language == "json" {
+		if start, end, ok := jsonObject(content, `"updates"`); ok {
+			for _, m := range expoUpdatesURLPattern.FindAllStringSubmatchIndex(content[start:end], -1) {
+				urls = append(urls, otaURL{"expo-updates", content[start+m[2] : start+m[3]], fc.RelPath, lineAt(start + m[0])})
+			}
+		}
+	}
+	return urls
+}
+
+// jsonObject returns the bounds of the object value following key.
+func jsonObject(content, key string) (int, int, bool) {
+	i := strings.Index(content, key)
+	if i < 0 {
+		return 0, 0, false
+	}
+	open := strings.IndexByte(content[i:], '{')
+	if open < 0 || strings.Trim(content[i+len(key):i+open], " \t\r\n:") != "" {
+		return 0, 0, false
+	}
+	start := i + open
+	depth := 0
+	for j := start; j < len(content); j++ {
+		switch content[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return start, j + 1, true
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,147 @@
+package codescan
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// Subscription call-to-action: StoreKit / RevenueCat / react-native-iap purchase flows
+	// or user-facing "subscribe" / "start trial" copy.
+	paywallCTAPattern = regexp.MustCompile(`(?i)(SubscriptionStoreView|Product\.SubscriptionInfo|purchasePackage|requestSubscription|Purchases\.shared\.purchase|"[^"]*\b(subscribe now|start (your )?free trial|start trial|go premium|unlock premium)\b[^"]*"|'[^']*\b(subscribe now|start (your )?free trial|start trial|go premium|unlock premium)\b[^']*')`)
+
+	// Price shown to the user.
+	paywallPricePattern = regexp.MustCompile(`(?i)(displayPrice|localizedPrice|priceString|price_string|localizedPriceString|priceFormatter|priceLocale|\.price\b|SubscriptionStoreView)`)
+
+	paywallTermsPattern   = regexp.MustCompile(`(?i)(terms of (use|service)|\bEULA\b|apple\.com/legal/internet-services/itunes/dev/stdeula|termsOfUse|termsURL|terms_url|subscriptionStorePolicyDestination)`)
+	paywallPrivacyPattern = regexp.MustCompile(`(?i)(privacy policy|privacyPolicy|privacyURL|privacy_url|subscriptionStorePolicyDestination)`)
+
+	// "Free trial" in a string literal, and the trial lengths that make it compliant.
+	freeTrialLiteralPattern = regexp.MustCompile(`(?i)["'\x60][^"'\x60]*\bfree[- ]trial\b[^"'\x60]*["'\x60]`)
+	trialDurationPattern    = regexp.MustCompile(`(?i)(\d+|one|two|three|seven|fourteen|thirty)[- ]?(day|week|month|year)|\b(weekly|monthly|annual|yearly)\b|%@|%d|\$\{|\\\(`)
+)
+
+// PaywallRule flags subscription paywalls that omit the disclosures guideline
+// 3.1.2 requires next to the purchase button: price, Terms of Use, and privacy
+// policy links, plus free-trial claims that don't state the trial length.
+// Paywalls are often split across views, so the disclosures are looked for
+// across the project rather than in the file with the purchase button.
+type PaywallRule struct {
+	id string
+}
+
+func (r *PaywallRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript":
+		return true
+	}
+	return false
+}
+
+func (r *PaywallRule) Check(fc FileContext) []Finding { return nil }
+
+// paywallEvidence is a file's first purchase call-to-action, the
+// disclosures it shows, and its own free trial findings.
+type paywallEvidence struct {
+	findings   []Finding
+	cta        *Finding
+	hasPrice   bool
+	hasTerms   bool
+	hasPrivacy bool
+}
+
+func (r *PaywallRule) Collect(fc FileContext) any {
+	ev := &paywallEvidence{}
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+
+		if ev.cta == nil && paywallCTAPattern.MatchString(line) {
+			ev.cta = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+		}
+		if paywallPricePattern.MatchString(line) {
+			ev.hasPrice = true
+		}
+		if paywallTermsPattern.MatchString(line) {
+			ev.hasTerms = true
+		}
+		if paywallPrivacyPattern.MatchString(line) {
+			ev.hasPrivacy = true
+		}
+
+		if lit := freeTrialLiteralPattern.FindString(line); lit != "" && !trialDurationPattern.MatchString(lit) {
+			ev.findings = append(ev.findings, Finding{
+				Severity:  SeverityWarn,
+				Guideline: "3.1.2",
+				Title:     "Free trial offer without trial length",
+				Detail:    "Free trial copy must state how long the trial lasts and what the user is charged when it ends.",
+				Fix:       "State the duration and renewal price, e.g. \"Start your 7-day free trial, then $4.99/month\".",
+				File:      fc.RelPath,
+				Line:      lineNum + 1,
+				Code:      trimmed,
+			})
+		}
+	}
+	if ev.findings == nil && ev.cta == nil && !ev.hasPrice && !ev.hasTerms && !ev.hasPrivacy {
+		return nil
+	}
+	return ev
+}
+
+func (r *PaywallRule) CheckProject(evidence []any) []Finding {
+	var (
+		findings   []Finding
+		cta        *Finding
+		hasPrice   bool
+		hasTerms   bool
+		hasPrivacy bool
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*paywallEvidence)
+		findings = append(findings, ev.findings...)
+		if cta == nil {
+			cta = ev.cta
+		}
+		hasPrice = hasPrice || ev.hasPrice
+		hasTerms = hasTerms || ev.hasTerms
+		hasPrivacy = hasPrivacy || ev.hasPrivacy
+	}
+
+	if cta == nil {
+		return findings
+	}
+
+	missing := func(title, detail, fix string) {
+		findings = append(findings, Finding{
+			Severity:  SeverityWarn,
+			Guideline: "3.1.2",
+			Title:     title,
+			Detail:    detail,
+			Fix:       fix,
+			File:      cta.File,
+			Line:      cta.Line,
+			Code:      cta.Code,
+		})
+	}
+
+	if !hasPrice {
+		missing("Subscription paywall without visible price",
+			"No code in the project appears to display the localized price for the subscription call-to-action. Apple requires the price and billing period to be clearly shown before purchase.",
+			"Show product.displayPrice (StoreKit 2) or the package's localized price string next to the subscribe button.")
+	}
+	if !hasTerms {
+		missing("Subscription paywall without Terms of Use link",
+			"Auto-renewable subscription screens must link to the Terms of Use (EULA), and no Terms of Use link was found in the project.",
+			"Add a tappable Terms of Use link on the paywall (Apple's standard EULA or your own).")
+	}
+	if !hasPrivacy {
+		missing("Subscription paywall without privacy policy link",
+			"Auto-renewable subscription screens must link to the privacy policy, and no privacy policy link was found in the project.",
+			"Add a tappable privacy policy link on the paywall.")
+	}
+
+	return findings
+}
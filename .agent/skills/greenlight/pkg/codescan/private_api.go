@@ -0,0 +1,423 @@
+package codescan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// Runtime lookups that take a selector, class or symbol name as a string.
+	dynamicLookupPattern = regexp.MustCompile(`\b(NSSelectorFromString|NSClassFromString|NSProtocolFromString|sel_registerName|sel_getUid|objc_getClass|objc_lookUpClass|dlsym|dlopen|Selector)\s*\(`)
+
+	// @selector(_name:) names the selector directly.
+	atSelectorPattern = regexp.MustCompile(`@selector\s*\(\s*([A-Za-z_][\w:]*)\s*\)`)
+
+	// Assignments whose right-hand side may build a string:
+	// NSString *a = @"_set";  let a = "_set"  a += "Style"
+	stringAssignPattern = regexp.MustCompile(`\b([A-Za-z_]\w*)\s*(?::\s*[\w.?!]+\s*)?(\+?=)\s*([^=].*?)\s*;?\s*$`)
+
+	// [a appendString:@"Style"]
+	appendStringPattern = regexp.MustCompile(`\[\s*([A-Za-z_]\w*)\s+appendString:\s*(.+)\]\s*;?\s*$`)
+
+	formatVerbPattern = regexp.MustCompile(`%(?:@|s|d|i|ld|lu|u)`)
+
+	// Public system libraries and frameworks, which dlopen may load.
+	publicSystemPathPattern = regexp.MustCompile(`^(/usr/lib/|/System/Library/Frameworks/)`)
+)
+
+// privateRuntimeNames are private classes and selectors without a leading
+// underscore that are commonly looked up dynamically.
+var privateRuntimeNames = map[string]bool{
+	"LSApplicationWorkspace":                     true,
+	"LSApplicationProxy":                         true,
+	"UIKeyboardImpl":                             true,
+	"UIStatusBarServer":                          true,
+	"SBApplication":                              true,
+	"MCMContainer":                               true,
+	"FBSSystemService":                           true,
+	"allApplications":                            true,
+	"defaultWorkspace":                           true,
+	"openApplicationWithBundleID:":               true,
+	"setBackgroundStyle:":                        true,
+	"statusBarWindow":                            true,
+	"setStatusBarHidden:withAnimation:duration:": true,
+}
+
+// PrivateAPIRule finds private API use through the Objective-C runtime and
+// dlopen/dlsym, resolving string literals, variables, concatenation,
+// stringWithFormat and joined arrays so obfuscated names are caught. It owns
+// dlopen: loading a private framework is private API use, loading a public
+// system library is fine, and loading anything else is dynamic code loading.
+type PrivateAPIRule struct {
+	id string
+}
+
+func (r *PrivateAPIRule) Applies(fc FileContext) bool {
+	return fc.Language == "swift" || fc.Language == "objc"
+}
+
+func (r *PrivateAPIRule) Check(fc FileContext) []Finding {
+	var (
+		findings []Finding
+		vars     = make(map[string]string)
+		built    = make(map[string]bool) // vars assembled from pieces
+	)
+
+	for lineNum := range fc.Lines {
+		line := fc.codeLine(lineNum)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+
+		if f, ok := r.checkLine(fc, lineNum, line, vars, built); ok {
+			findings = append(findings, f)
+		}
+		trackStringVars(trimmed, vars, built)
+	}
+	return findings
+}
+
+// checkLine reports at most one finding per line.
+func (r *PrivateAPIRule) checkLine(fc FileContext, lineNum int, line string, vars map[string]string, builtVars map[string]bool) (Finding, bool) {
+	finding := func(sev Severity, title, detail string) (Finding, bool) {
+		return Finding{
+			Severity:  sev,
+			Guideline: "2.5.1",
+			Title:     title,
+			Detail:    detail,
+			Fix:       "Replace with public API equivalents.",
+			File:      fc.RelPath,
+			Line:      lineNum + 1,
+			Code:      strings.TrimSpace(fc.Lines[lineNum]),
+		}, true
+	}
+	dynamicLoading := func(detail string) (Finding, bool) {
+		f, ok := finding(SeverityCritical, "Dynamic code loading detected", detail)
+		f.Guideline = "2.5.2"
+		return f, ok
+	}
+
+	for _, m := range atSelectorPattern.FindAllStringSubmatch(line, -1) {
+		if isPrivateRuntimeName(m[1]) {
+			return finding(SeverityCritical, "Private API usage detected",
+				fmt.Sprintf("@selector(%s) names a private method. Using private/undocumented Apple APIs will cause immediate rejection.", m[1]))
+		}
+	}
+
+	for _, loc := range dynamicLookupPattern.FindAllStringSubmatchIndex(line, -1) {
+		fn := line[loc[2]:loc[3]]
+		args := splitTop(balancedArgs(line[loc[1]:]), ',')
+		if len(args) == 0 {
+			continue
+		}
+		expr := args[0]
+		if fn == "dlsym" {
+			if len(args) < 2 {
+				continue
+			}
+			expr = args[1]
+		}
+		expr = strings.TrimSpace(expr)
+		if fn == "Selector" {
+			// Swift: Selector(("_foo")) — the double parens silence the warning.
+			expr = strings.TrimSpace(trimParens(expr))
+		}
+
+		name, resolved := evalStringExpr(expr, vars)
+		built := isBuiltString(expr) || builtVars[expr]
+
+		switch fn {
+		case "dlopen":
+			switch {
+			case resolved && strings.Contains(name, "PrivateFrameworks"):
+				return finding(SeverityCritical, "Private API usage detected",
+					fmt.Sprintf("dlopen loads %q, a private framework. Using private/undocumented Apple APIs will cause immediate rejection.", name))
+			case resolved && publicSystemPathPattern.MatchString(name):
+				continue
+			case resolved:
+				return dynamicLoading(fmt.Sprintf("dlopen loads %q at runtime. Apps may not download, install, or execute code that changes app behavior.", name))
+			}
+			return dynamicLoading("dlopen loads code at runtime from a path that isn't known statically. Apps may not download, install, or execute code that changes app behavior.")
+		case "dlsym":
+			detail := "dlsym resolves a symbol at runtime, hiding the API from static review."
+			if resolved {
+				detail = fmt.Sprintf("dlsym resolves %q at runtime, hiding the API from static review.", name)
+			}
+			return finding(SeverityCritical, "Private API usage detected", detail)
+		}
+
+		if resolved && isPrivateRuntimeName(name) {
+			detail := fmt.Sprintf("%s(%q) names a private API.", fn, name)
+			if built {
+				detail = fmt.Sprintf("%s builds %q from pieces at runtime — an obfuscated private API call.", fn, name)
+			}
+			return finding(SeverityCritical, "Private API usage detected",
+				detail+" Using private/undocumented Apple APIs will cause immediate rejection.")
+		}
+		if !resolved && built {
+			return finding(SeverityWarn, "Obfuscated selector or class lookup",
+				fn+" is given a name assembled at runtime. Building selectors and class names from fragments is a common way to hide private API use, and reviewers flag it.")
+		}
+	}
+	return Finding{}, false
+}
+
+// trackStringVars records string values assigned on this line.
+func trackStringVars(line string, vars map[string]string, built map[string]bool) {
+	if m := appendStringPattern.FindStringSubmatch(line); m != nil {
+		if prev, ok := vars[m[1]]; ok {
+			if s, ok := evalStringExpr(m[2], vars); ok {
+				vars[m[1]] = prev + s
+				built[m[1]] = true
+			}
+		}
+		return
+	}
+	m := stringAssignPattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	s, ok := evalStringExpr(m[3], vars)
+	if !ok {
+		delete(vars, m[1])
+		delete(built, m[1])
+		return
+	}
+	if m[2] == "+=" {
+		vars[m[1]] += s
+		built[m[1]] = true
+		return
+	}
+	vars[m[1]] = s
+	built[m[1]] = isBuiltString(m[3]) || built[strings.TrimSpace(m[3])]
+}
+
+// evalStringExpr resolves a Swift or Objective-C string expression built from
+// literals, known variables, +, adjacent ObjC literals, stringByAppendingString,
+// stringWithFormat / String(format:) and joined arrays.
+func evalStringExpr(expr string, vars map[string]string) (string, bool) {
+	expr = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(expr), ";"))
+	if expr == "" {
+		return "", false
+	}
+
+	if parts := splitTop(expr, '+'); len(parts) > 1 {
+		return evalAll(parts, vars)
+	}
+	if parts := splitTop(expr, ' '); len(parts) > 1 && allLiterals(parts) {
+		return evalAll(parts, vars) // @"_set" @"Style"
+	}
+
+	if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && balancedArgs(expr[1:]) == expr[1:len(expr)-1] {
+		return evalStringExpr(expr[1:len(expr)-1], vars)
+	}
+	if s, ok := stringLiteral(expr); ok {
+		return s, true
+	}
+	if s, ok := vars[expr]; ok {
+		return s, true
+	}
+
+	// ObjC message sends: [recv selector:arg ...]
+	if strings.HasPrefix(expr, "[") && strings.HasSuffix(expr, "]") {
+		body := strings.TrimSpace(expr[1 : len(expr)-1])
+		switch {
+		case strings.HasPrefix(body, "NSString stringWithFormat:"):
+			return evalFormat(strings.TrimPrefix(body, "NSString stringWithFormat:"), vars)
+		case strings.Contains(body, " stringByAppendingString:"):
+			i := strings.LastIndex(body, " stringByAppendingString:")
+			return evalAll([]string{body[:i], body[i+len(" stringByAppendingString:"):]}, vars)
+		case strings.Contains(body, " componentsJoinedByString:"):
+			i := strings.LastIndex(body, " componentsJoinedByString:")
+			return evalJoin(body[:i], body[i+len(" componentsJoinedByString:"):], vars)
+		}
+		return "", false
+	}
+
+	// Swift: String(format: "%@%@", a, b)
+	if strings.HasPrefix(expr, "String(format:") && strings.HasSuffix(expr, ")") {
+		return evalFormat(expr[len("String(format:"):len(expr)-1], vars)
+	}
+	// Swift: ["_set", "Style"].joined() / .joined(separator: "")
+	if i := strings.LastIndex(expr, ".joined("); i > 0 && strings.HasSuffix(expr, ")") {
+		sep := strings.TrimSpace(strings.TrimPrefix(expr[i+len(".joined("):len(expr)-1], "separator:"))
+		if sep == "" {
+			sep = `""`
+		}
+		return evalJoin(expr[:i], sep, vars)
+	}
+	return "", false
+}
+
+func evalAll(parts []string, vars map[string]string) (string, bool) {
+	var b strings.Builder
+	for _, p := range parts {
+		s, ok := evalStringExpr(p, vars)
+		if !ok {
+			return "", false
+		}
+		b.WriteString(s)
+	}
+	return b.String(), true
+}
+
+func evalFormat(args string, vars map[string]string) (string, bool) {
+	parts := splitTop(args, ',')
+	if len(parts) == 0 {
+		return "", false
+	}
+	format, ok := stringLiteral(strings.TrimSpace(parts[0]))
+	if !ok {
+		return "", false
+	}
+	values := parts[1:]
+	failed := false
+	out := formatVerbPattern.ReplaceAllStringFunc(format, func(string) string {
+		if len(values) == 0 {
+			failed = true
+			return ""
+		}
+		s, ok := evalStringExpr(values[0], vars)
+		values = values[1:]
+		if !ok {
+			failed = true
+		}
+		return s
+	})
+	return out, !failed
+}
+
+func evalJoin(array, sep string, vars map[string]string) (string, bool) {
+	array = strings.TrimSpace(array)
+	array = strings.TrimPrefix(array, "@")
+	if !strings.HasPrefix(array, "[") || !strings.HasSuffix(array, "]") {
+		return "", false
+	}
+	s, ok := evalStringExpr(sep, vars)
+	if !ok {
+		return "", false
+	}
+	var items []string
+	for _, p := range splitTop(array[1:len(array)-1], ',') {
+		v, ok := evalStringExpr(p, vars)
+		if !ok {
+			return "", false
+		}
+		items = append(items, v)
+	}
+	return strings.Join(items, s), true
+}
+
+// stringLiteral parses "..." or @"..." with simple escapes.
+func stringLiteral(expr string) (string, bool) {
+	expr = strings.TrimPrefix(expr, "@")
+	if len(expr) < 2 || expr[0] != '"' || expr[len(expr)-1] != '"' {
+		return "", false
+	}
+	body := expr[1 : len(expr)-1]
+	if strings.Contains(strings.ReplaceAll(body, `\"`, ""), `"`) || strings.Contains(body, `\(`) {
+		return "", false // not a single literal, or Swift interpolation
+	}
+	r := strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n", `\t`, "\t")
+	return r.Replace(body), true
+}
+
+func allLiterals(parts []string) bool {
+	for _, p := range parts {
+		if _, ok := stringLiteral(p); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isBuiltString reports whether an expression assembles a string rather than
+// passing a single literal or variable.
+func isBuiltString(expr string) bool {
+	if len(splitTop(expr, '+')) > 1 || strings.Contains(expr, "stringWithFormat") ||
+		strings.Contains(expr, "String(format") || strings.Contains(expr, "stringByAppendingString") ||
+		strings.Contains(expr, "joined(") || strings.Contains(expr, "componentsJoinedByString") {
+		return true
+	}
+	parts := splitTop(expr, ' ')
+	return len(parts) > 1 && allLiterals(parts)
+}
+
+func isPrivateRuntimeName(name string) bool {
+	return strings.HasPrefix(name, "_") || privateRuntimeNames[name]
+}
+
+func trimParens(s string) string {
+	s = strings.TrimSpace(s)
+	for strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	return s
+}
+
+// balancedArgs returns the text up to the parenthesis that closes an already
+// opened call, e.g. `a, b) + x` -> `a, b`.
+func balancedArgs(s string) string {
+	depth := 0
+	inStr := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inStr:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inStr = false
+			}
+		case c == '"':
+			inStr = true
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			if depth == 0 {
+				return s[:i]
+			}
+			depth--
+		}
+	}
+	return s
+}
+
+// splitTop splits s on sep outside string literals and brackets, dropping
+// empty parts.
+func splitTop(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		inStr bool
+		start int
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inStr:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inStr = false
+			}
+		case c == '"':
+			inStr = true
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			if p := strings.TrimSpace(s[start:i]); p != "" {
+				parts = append(parts, p)
+			}
+			start = i + 1
+		}
+	}
+	if p := strings.TrimSpace(s[start:]); p != "" {
+		parts = append(parts, p)
+	}
+	return parts
+}
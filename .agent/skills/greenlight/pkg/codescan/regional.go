@@ -0,0 +1,87 @@
+package codescan
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+var (
+	regionalNetworkPattern  = regexp.MustCompile(`(URLSession|NSURLSession|NSURLConnection|Alamofire|\bMoya\b|URLRequest|\bfetch\(|\baxios\b|XMLHttpRequest|WebSocket|ApolloClient|FirebaseFirestore|@react-native-firebase/|WKWebView|react-native-webview)`)
+	regionalPurchasePattern = regexp.MustCompile(`(import\s+StoreKit|SKPaymentQueue|Product\.purchase|react-native-iap|react-native-purchases|RevenueCat|Purchases\.shared|expo-in-app-purchases)`)
+	// Korean purchase disclosures: withdrawal of subscription (청약철회),
+	// refunds, and the seller's business information.
+	regionalKoreaDisclosurePattern = regexp.MustCompile(`(청약\s*철회|환불|사업자\s*(등록|정보)|통신판매업)`)
+	regionalTrackingPattern        = regexp.MustCompile(`(FirebaseAnalytics|Analytics\.logEvent|@react-native-firebase/analytics|GoogleMobileAds|GADMobileAds|react-native-google-mobile-ads|FBSDKCoreKit|FacebookCore|react-native-fbsdk|AppsFlyer|AdjustSdk|ADJConfig|react-native-adjust|Mixpanel|Amplitude|@segment/|AppLovin|IronSource|UnityAds)`)
+	regionalConsentPattern         = regexp.MustCompile(`(UserMessagingPlatform|UMPConsentInformation|ConsentInformation\.shared|requestConsentInfoUpdate|AdsConsent|OneTrust|OTPublishersHeadlessSDK|\bDidomi\b|Usercentrics|Sourcepoint|ConsentManager|@iabtcf/|IABTCF_|(?i:gdpr\w*consent|consent\w*gdpr|cookie\s*consent))`)
+)
+
+// RegionalSignals is what territory-specific rules need to know about a
+// project.
+type RegionalSignals struct {
+	// Networked reports whether the app talks to a server.
+	Networked bool
+	// Purchases reports whether the app sells in-app purchases.
+	Purchases bool
+	// KoreaDisclosure reports whether the app shows Korean refund,
+	// withdrawal or seller information.
+	KoreaDisclosure bool
+	// Tracking names the first analytics or advertising SDK found.
+	Tracking string
+	// Consent reports whether the app has a consent management flow.
+	Consent bool
+}
+
+// ReadRegionalSignals walks the code, plists and string files under
+// projectPath that paths includes for the signals territory-specific rules
+// use.
+func ReadRegionalSignals(projectPath string, paths scan.Paths) RegionalSignals {
+	var s RegionalSignals
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		if info.IsDir() {
+			if rel != "." && skippedDirs[info.Name()] || paths.SkipDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !paths.Includes(rel) {
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		lang := detectLanguage(path)
+		if lang == "" && ext != ".strings" && ext != ".xcstrings" || lang == "jsbundle" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || sourcefile.IsBinary(data) {
+			return nil
+		}
+		content := string(data)
+		code := lang != "plist" && ext != ".strings" && ext != ".xcstrings"
+		if code && !s.Networked && regionalNetworkPattern.MatchString(content) {
+			s.Networked = true
+		}
+		if code && !s.Purchases && regionalPurchasePattern.MatchString(content) {
+			s.Purchases = true
+		}
+		if !s.KoreaDisclosure && regionalKoreaDisclosurePattern.MatchString(content) {
+			s.KoreaDisclosure = true
+		}
+		if code && s.Tracking == "" {
+			s.Tracking = regionalTrackingPattern.FindString(content)
+		}
+		if code && !s.Consent && regionalConsentPattern.MatchString(content) {
+			s.Consent = true
+		}
+		return nil
+	})
+	return s
+}
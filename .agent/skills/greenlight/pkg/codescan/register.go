@@ -0,0 +1,28 @@
+package codescan
+
+import (
+	"context"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() { scan.Register(scanner{}) }
+
+// scanner runs the code scan as part of every multi-scanner command.
+type scanner struct{}
+
+func (scanner) Name() string { return "codescan" }
+
+func (scanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	s := NewScanner(t.ProjectPath)
+	s.SetPaths(t.Paths)
+	findings, err := s.Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]scan.Finding, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, f.ScanFinding())
+	}
+	return out, nil
+}
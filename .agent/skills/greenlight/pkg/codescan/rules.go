@@ -4,41 +4,18 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 // AllRules returns every registered code scan rule.
 func AllRules() []Rule {
 	return []Rule{
 		// CRITICAL - Immediate rejection
-		&PatternRule{
-			id:        "private-api",
-			title:     "Private API usage detected",
-			guideline: "2.5.1",
-			severity:  SeverityCritical,
-			detail:    "Using private/undocumented Apple APIs will cause immediate rejection.",
-			fix:       "Replace with public API equivalents.",
-			languages: []string{"swift", "objc"},
-			patterns: []*regexp.Regexp{
-				regexp.MustCompile(`NSSelectorFromString\s*\(\s*"_`),
-				regexp.MustCompile(`performSelector.*"_`),
-				regexp.MustCompile(`dlopen\s*\(`),
-				regexp.MustCompile(`dlsym\s*\(`),
-			},
+		&PrivateAPIRule{
+			id: "private-api",
 		},
-		&PatternRule{
-			id:        "hardcoded-secrets",
-			title:     "Hardcoded secret/API key detected",
-			guideline: "1.6",
-			severity:  SeverityCritical,
-			detail:    "Hardcoded secrets in source code is a security vulnerability and review risk.",
-			fix:       "Move secrets to environment variables or a secure keychain.",
-			languages: []string{"swift", "objc", "typescript", "javascript"},
-			patterns: []*regexp.Regexp{
-				regexp.MustCompile(`(?i)(sk_live_|sk_test_|pk_live_|pk_test_)[a-zA-Z0-9]{20,}`),
-				regexp.MustCompile(`(?i)(api[_-]?key|api[_-]?secret|secret[_-]?key)\s*[:=]\s*["'][a-zA-Z0-9]{20,}["']`),
-				regexp.MustCompile(`(?i)AKIA[0-9A-Z]{16}`), // AWS access key
-				regexp.MustCompile(`(?i)ghp_[a-zA-Z0-9]{36}`), // GitHub token
-			},
+		&SecretsRule{
+			id: "hardcoded-secrets",
 		},
 		&PatternRule{
 			id:        "external-payment-digital",
@@ -55,10 +32,13 @@ func AllRules() []Rule {
 				regexp.MustCompile(`(?i)checkout\.redirect.*url`),
 			},
 		},
+		&ExternalPurchaseRule{
+			id: "external-purchase-link",
+		},
 		&PatternRule{
 			id:        "crypto-mining",
 			title:     "Cryptocurrency mining detected",
-			guideline: "3.1.5",
+			guideline: "3.1.5(ii)",
 			severity:  SeverityCritical,
 			detail:    "On-device cryptocurrency mining is explicitly prohibited.",
 			fix:       "Remove all mining functionality.",
@@ -68,8 +48,15 @@ func AllRules() []Rule {
 				regexp.MustCompile(`(?i)hash\s*rate`),
 				regexp.MustCompile(`(?i)mining\s*pool`),
 				regexp.MustCompile(`(?i)stratum\+tcp`),
+				regexp.MustCompile(`(?i)\b(coinhive|cryptoloot|xmrig|webminepool)\b`),
 			},
 		},
+		&CryptoRule{
+			id: "crypto-features",
+		},
+		&OTAUpdateRule{
+			id: "ota-updates",
+		},
 		&PatternRule{
 			id:        "dynamic-code-exec",
 			title:     "Dynamic code execution detected",
@@ -80,7 +67,7 @@ func AllRules() []Rule {
 			languages: []string{"swift", "objc"},
 			patterns: []*regexp.Regexp{
 				regexp.MustCompile(`JSContext\s*\(\s*\).*evaluateScript`),
-				regexp.MustCompile(`dlopen\s*\(`),
+				// dlopen is reported by the private-api rule.
 				regexp.MustCompile(`NSBundle.*load\b`),
 			},
 		},
@@ -104,6 +91,9 @@ func AllRules() []Rule {
 			},
 			antiPatternsGlobal: true,
 		},
+		&ATTTimingRule{
+			id: "att-timing",
+		},
 		&PatternRule{
 			id:        "social-login-no-apple",
 			title:     "Social login without Sign in with Apple",
@@ -136,6 +126,21 @@ func AllRules() []Rule {
 			},
 			antiPatternsGlobal: true,
 		},
+		&PaywallRule{
+			id: "subscription-paywall",
+		},
+		&HealthDataRule{
+			id: "health-data",
+		},
+		&MedicalDisclaimerRule{
+			id: "medical-disclaimer",
+		},
+		&CapabilityRule{
+			id: "capability-mismatch",
+		},
+		&ApplePayRule{
+			id: "apple-pay",
+		},
 		&PatternRule{
 			id:        "account-no-delete",
 			title:     "Account creation without account deletion",
@@ -153,6 +158,20 @@ func AllRules() []Rule {
 			antiPatternsGlobal: true,
 		},
 
+		&PatternRule{
+			id:        "gambling-mechanics",
+			title:     "Gambling or loot-box mechanics detected",
+			guideline: "5.3",
+			severity:  SeverityWarn,
+			detail:    "Simulated gambling requires a 17+ age rating, real-money gaming requires licensing and geo-restriction, and loot boxes must disclose odds before purchase.",
+			fix:       "Confirm the age rating questionnaire declares gambling content, and show drop odds for any randomized paid items.",
+			languages: []string{"swift", "objc", "typescript", "javascript"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`(?i)["'\x60][^"'\x60]*\b(slot machine|roulette|blackjack|place your bets?|loot ?box|gacha|mystery box|spin the wheel|raffle|sweepstakes|real[- ]money)\b[^"'\x60]*["'\x60]`),
+				regexp.MustCompile(`(?i)\b(LootBox|Gacha|SlotMachine|Roulette)(View|Controller|Screen|Manager)\b`),
+			},
+		},
+
 		// MEDIUM - May cause issues
 		&PatternRule{
 			id:        "platform-reference",
@@ -200,6 +219,54 @@ func AllRules() []Rule {
 			},
 			countThreshold: 5, // Flag files with more than 5 log statements
 		},
+		&PatternRule{
+			id:        "dev-endpoint",
+			title:     "Development or staging endpoint in release code",
+			guideline: "2.1",
+			severity:  SeverityWarn,
+			detail:    "A localhost, tunnel or staging URL is used outside #if DEBUG / __DEV__, so the store build talks to a server reviewers can't reach or that has test data, which shows up as broken features or empty screens.",
+			fix:       "Select endpoints per build configuration (xcconfig, #if DEBUG, __DEV__ or an env variable) so release builds only use production URLs.",
+			languages: []string{"swift", "objc", "typescript", "javascript"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`https?://(localhost|127\.0\.0\.1|0\.0\.0\.0|10\.0\.2\.2)\b`),
+				regexp.MustCompile(`https?://[\w-]+\.local\b`),
+				regexp.MustCompile(`https?://[\w.-]+\.(ngrok\.io|ngrok-free\.app|ngrok\.app|loca\.lt|trycloudflare\.com)\b`),
+				regexp.MustCompile(`(?i)https?://[\w.-]*\b(staging|stage|stg|qa|uat|dev)\b[\w.-]*\.[a-z]{2,}`),
+			},
+			releaseOnly: true,
+		},
+		&PatternRule{
+			id:        "debug-flag-forced",
+			title:     "Debug flag forced on in release code",
+			guideline: "2.1",
+			severity:  SeverityWarn,
+			detail:    "A debug or development flag is hard-coded to true outside #if DEBUG / __DEV__, so debug menus, verbose logging or test behavior ship in the store build. Reviewers reject visible debug features as unfinished.",
+			fix:       "Derive the flag from the build configuration (#if DEBUG, __DEV__) instead of hard-coding it, or set it to false.",
+			languages: []string{"swift", "objc", "typescript", "javascript"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`__DEV__\s*=\s*(true|!0)\b`),
+				regexp.MustCompile(`(?i)\b(is_?debug|debug_?mode|debug_?enabled|enable_?debug\w*|show_?debug\w*|is_?dev_?mode|is_?development)\s*[:=]\s*(true|YES)\b`),
+				regexp.MustCompile(`\b(let|var|const)\s+DEBUG\s*(:\s*Bool\s*)?=\s*(true|1)\b`),
+				regexp.MustCompile(`^\s*#\s*define\s+DEBUG\s+1\b`),
+			},
+			releaseOnly: true,
+		},
+		&PatternRule{
+			id:        "pii-logging",
+			title:     "Personal data written to logs",
+			guideline: "1.6",
+			severity:  SeverityWarn,
+			detail:    "A release-build log statement includes a password, token, email address, phone number or payment detail. Device logs are readable by anyone with the device and a Mac, and end up in crash reports and log-collection SDKs.",
+			fix:       "Remove the value from the log, or log it only in debug builds. With os.Logger, interpolate it with privacy: .private.",
+			languages: []string{"swift", "objc", "typescript", "javascript"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`(?i)\b(print|debugPrint|NSLog|os_log|console\.(log|info|debug|warn|error)|(logger|log)\.(debug|info|notice|trace|verbose|log))\s*\(.*(\\\(|\$\{|[,+(]\s*)[\w.?!]*\b(password|passwd|email|user_?email|phone|phone_?number|ssn|social_?security_?number|credit_?card(_?number)?|card_?number|cvv|cvc|date_?of_?birth|access_?token|refresh_?token|id_?token|auth_?token)\b`),
+			},
+			ignorePatterns: []*regexp.Regexp{
+				regexp.MustCompile(`(?i)(privacy:\s*\.(private|sensitive)|redact|mask)`),
+			},
+			releaseOnly: true,
+		},
 		&PatternRule{
 			id:        "hardcoded-ipv4",
 			title:     "Hardcoded IPv4 address",
@@ -215,6 +282,38 @@ func AllRules() []Rule {
 				regexp.MustCompile(`(?i)(version|0\.0\.0|127\.0\.0\.1|localhost)`), // ignore version strings and localhost
 			},
 		},
+		&PatternRule{
+			id:        "ipv4-reachability",
+			title:     "Reachability pinned to IPv4",
+			guideline: "2.1",
+			severity:  SeverityWarn,
+			detail:    "Reachability built on an IPv4 sockaddr_in (the classic Reachability zero-address check) reports no connection on Apple's IPv6-only review network, so the app shows an offline state or fails on launch.",
+			fix:       "Use NWPathMonitor, or SCNetworkReachabilityCreateWithName with a hostname. If you need an address, use sockaddr_in6 / AF_INET6.",
+			languages: []string{"swift", "objc"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`struct\s+sockaddr_in\b`),
+				regexp.MustCompile(`\bsockaddr_in\s*\(\s*\)`),
+				regexp.MustCompile(`\bsin_family\s*=\s*(sa_family_t\s*\(\s*)?AF_INET\b`),
+				regexp.MustCompile(`\bINADDR_ANY\b`),
+			},
+			ignorePatterns: []*regexp.Regexp{
+				regexp.MustCompile(`(AF_INET6|sockaddr_in6|sin6_)`),
+			},
+		},
+		&PatternRule{
+			id:        "ipv4-socket-api",
+			title:     "IPv4-only socket API",
+			guideline: "2.1",
+			severity:  SeverityWarn,
+			detail:    "gethostbyname, inet_addr, inet_ntoa and AF_INET sockets only handle IPv4. On Apple's IPv6-only (NAT64) review network they fail to resolve or connect — a recurring \"app crashes on launch\" or \"no content\" 2.1 rejection.",
+			fix:       "Use URLSession or Network.framework with hostnames. For BSD sockets use getaddrinfo with AF_UNSPEC and inet_pton/inet_ntop.",
+			languages: []string{"swift", "objc"},
+			patterns: []*regexp.Regexp{
+				regexp.MustCompile(`\b(gethostbyname2?|gethostbyaddr|inet_addr|inet_aton|inet_ntoa)\s*\(`),
+				regexp.MustCompile(`\bsocket\s*\(\s*(AF_INET|PF_INET)\b`),
+				regexp.MustCompile(`\bai_family\s*=\s*(AF_INET|PF_INET)\b`),
+			},
+		},
 		&PatternRule{
 			id:        "http-not-https",
 			title:     "Insecure HTTP URL",
@@ -232,17 +331,17 @@ func AllRules() []Rule {
 				regexp.MustCompile(`(?i)(w3\.org|xmlns|DTD|doctype)`), // XML/SVG namespace URIs and DTD references are identifiers, not network requests
 			},
 		},
-		&PatternRule{
-			id:        "webview-only",
-			title:     "WebView-only app pattern detected",
-			guideline: "4.2",
-			severity:  SeverityWarn,
-			detail:    "Apps that are primarily WebView wrappers may be rejected for minimum functionality.",
-			fix:       "Add native features beyond just loading a web page.",
-			languages: []string{"swift", "objc", "typescript", "javascript"},
-			patterns: []*regexp.Regexp{
-				regexp.MustCompile(`(?i)(WKWebView|UIWebView|WebView|react-native-webview).*loadRequest.*https?://`),
-			},
+		&WebViewWrapperRule{
+			id: "webview-only",
+		},
+		&UGCModerationRule{
+			id: "ugc-moderation",
+		},
+		&MediaRightsRule{
+			id: "media-rights",
+		},
+		&LaunchBlockingRule{
+			id: "launch-blocking",
 		},
 		&PatternRule{
 			id:        "vague-purpose-string",
@@ -257,6 +356,9 @@ func AllRules() []Rule {
 				regexp.MustCompile(`(?i)<string>\s*(needed|required|for the app|to function|for functionality)\s*\.?\s*</string>`),
 			},
 		},
+		&ExportComplianceRule{
+			id: "export-compliance",
+		},
 		&PlistKeyRule{
 			id:        "missing-privacy-keys",
 			title:     "Info.plist missing required privacy keys",
@@ -266,6 +368,9 @@ func AllRules() []Rule {
 		&ExpoConfigRule{
 			id: "expo-config-check",
 		},
+		&JSBundleRule{
+			id: "js-bundle",
+		},
 	}
 }
 
@@ -283,6 +388,36 @@ type PatternRule struct {
 	antiPatternsGlobal bool             // Check anti-patterns across all files, not just current
 	ignorePatterns     []*regexp.Regexp // Lines matching these are skipped
 	countThreshold     int              // Only report if count exceeds this
+	releaseOnly        bool             // Skip code that only runs in debug builds (#if DEBUG, __DEV__)
+
+	// Each pattern list compiled into one alternation, so a line is matched
+	// once per list instead of once per pattern.
+	compileOnce sync.Once
+	patternSet  *regexp.Regexp
+	antiSet     *regexp.Regexp
+	ignoreSet   *regexp.Regexp
+}
+
+// compile builds the rule's combined pattern sets on first use.
+func (r *PatternRule) compile() {
+	r.compileOnce.Do(func() {
+		r.patternSet = regexpSet(r.patterns)
+		r.antiSet = regexpSet(r.antiPatterns)
+		r.ignoreSet = regexpSet(r.ignorePatterns)
+	})
+}
+
+// regexpSet joins patterns into one regexp matching wherever any of them
+// does, or nil for an empty list. Inline flags stay scoped to their pattern.
+func regexpSet(patterns []*regexp.Regexp) *regexp.Regexp {
+	if len(patterns) == 0 {
+		return nil
+	}
+	parts := make([]string, len(patterns))
+	for i, p := range patterns {
+		parts[i] = "(?:" + p.String() + ")"
+	}
+	return regexp.MustCompile(strings.Join(parts, "|"))
 }
 
 func (r *PatternRule) RuleID() string { return r.id }
@@ -292,11 +427,13 @@ func (r *PatternRule) HasGlobalAntiPatterns() bool {
 }
 
 func (r *PatternRule) AntiPatternMatched(fc FileContext) bool {
-	for _, line := range fc.Lines {
-		for _, ap := range r.antiPatterns {
-			if ap.MatchString(line) {
-				return true
-			}
+	r.compile()
+	if r.antiSet == nil {
+		return false
+	}
+	for i := range fc.Lines {
+		if r.antiSet.MatchString(fc.codeLine(i)) {
+			return true
 		}
 	}
 	return false
@@ -312,41 +449,46 @@ func (r *PatternRule) Applies(fc FileContext) bool {
 }
 
 func (r *PatternRule) Check(fc FileContext) []Finding {
+	r.compile()
+	if r.patternSet == nil {
+		return nil
+	}
 	var findings []Finding
+	var debugOnly []bool
+	if r.releaseOnly {
+		debugOnly = debugOnlyLines(fc)
+	}
 
 	for lineNum, line := range fc.Lines {
-		// Skip comment lines
+		if debugOnly != nil && debugOnly[lineNum] {
+			continue
+		}
+		// Skip comment lines; with syntax, match only the code on the line
+		if fc.Syntax != nil {
+			line = fc.codeLine(lineNum)
+		}
 		trimmed := strings.TrimSpace(line)
 		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
 			continue
 		}
 
 		// Skip lines matching ignore patterns
-		ignored := false
-		for _, ip := range r.ignorePatterns {
-			if ip.MatchString(line) {
-				ignored = true
-				break
-			}
-		}
-		if ignored {
+		if r.ignoreSet != nil && r.ignoreSet.MatchString(line) {
 			continue
 		}
 
-		for _, pattern := range r.patterns {
-			if pattern.MatchString(line) {
-				findings = append(findings, Finding{
-					Severity:  r.severity,
-					Guideline: r.guideline,
-					Title:     r.title,
-					Detail:    r.detail,
-					Fix:       r.fix,
-					File:      fc.RelPath,
-					Line:      lineNum + 1,
-					Code:      strings.TrimSpace(line),
-				})
-				break // One finding per line per rule
-			}
+		// One finding per line per rule
+		if r.patternSet.MatchString(line) {
+			findings = append(findings, Finding{
+				Severity:  r.severity,
+				Guideline: r.guideline,
+				Title:     r.title,
+				Detail:    r.detail,
+				Fix:       r.fix,
+				File:      fc.RelPath,
+				Line:      lineNum + 1,
+				Code:      strings.TrimSpace(fc.Lines[lineNum]),
+			})
 		}
 	}
 
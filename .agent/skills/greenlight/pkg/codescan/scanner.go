@@ -0,0 +1,598 @@
+// Package codescan finds App Store rejection risks in iOS, React Native and
+// Expo source code: private APIs, hardcoded secrets, missing ATT, external
+// payment links and 30+ other patterns.
+//
+//	findings, err := codescan.NewScanner("./my-app").Scan(ctx)
+package codescan
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/codeowners"
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/internal/triage"
+	"github.com/RevylAI/greenlight/pkg/codescan/swiftsyntax"
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+// Scanner walks a project directory and runs pattern-based checks.
+type Scanner struct {
+	root  string
+	rules []Rule
+	swift swiftsyntax.Backend
+
+	workers     int
+	maxFileSize int64
+	mmap        bool
+	paths       scan.Paths
+
+	// Stats of the last Scan.
+	statsMu sync.Mutex
+	stats   ScanStats
+
+	// What ScanFile reuses from the last Scan: rules suppressed by a
+	// project-wide anti-pattern, project rule findings by file, and the
+	// CODEOWNERS file.
+	lastMu     sync.Mutex
+	suppressed map[string]bool
+	project    map[string][]Finding
+	owners     *codeowners.File
+}
+
+// DefaultMaxFileSize is the size above which source files are skipped.
+const DefaultMaxFileSize = sourcefile.DefaultMaxSize
+
+// SkipCounts tallies files a scan skipped by reason: "binary", "minified"
+// or "too-large".
+type SkipCounts = sourcefile.Counts
+
+// ScanStats describes what the last Scan read.
+type ScanStats struct {
+	Files   int
+	Skipped SkipCounts
+	// Retained counts, by project-wide rule, the files the rule kept
+	// evidence from.
+	Retained map[string]int
+}
+
+// FileContext holds a scanned file and its lines for pattern matching.
+type FileContext struct {
+	Path     string
+	RelPath  string
+	Lines    []string
+	Language string // "swift", "objc", "typescript", "javascript", "json", "plist", "jsbundle"
+
+	// Syntax is set for Swift files when an AST backend is enabled.
+	Syntax *swiftsyntax.File
+}
+
+// codeLine returns line i for pattern matching: with comments blanked when
+// syntax is available, otherwise the raw line.
+func (fc FileContext) codeLine(i int) string {
+	if fc.Syntax != nil && i < len(fc.Syntax.CodeLines) {
+		return fc.Syntax.CodeLines[i]
+	}
+	return fc.Lines[i]
+}
+
+// NewScanner returns a scanner for the project at root with every built-in
+// rule enabled.
+func NewScanner(root string) *Scanner {
+	s := &Scanner{
+		root:        root,
+		workers:     runtime.NumCPU(),
+		maxFileSize: DefaultMaxFileSize,
+	}
+	s.rules = AllRules()
+	return s
+}
+
+// SetSwiftBackend enables syntax-aware analysis of Swift files. Rules then
+// ignore matches inside comments and can reason about call nesting.
+func (s *Scanner) SetSwiftBackend(b swiftsyntax.Backend) {
+	s.swift = b
+}
+
+// SetMaxFileSize skips source files larger than n bytes; n <= 0 removes the
+// limit. Shipped JS bundles are always read.
+func (s *Scanner) SetMaxFileSize(n int64) {
+	s.maxFileSize = n
+}
+
+// Stats returns how many files the last Scan read and skipped.
+func (s *Scanner) Stats() ScanStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	return s.stats
+}
+
+// skip records a file skipped for reason.
+func (s *Scanner) skip(ctx context.Context, rel, reason string) {
+	slog.DebugContext(ctx, "codescan file skipped", "file", rel, "reason", reason)
+	s.statsMu.Lock()
+	s.stats.Skipped[reason]++
+	s.statsMu.Unlock()
+}
+
+// SetPaths narrows the files Scan and ScanFile read.
+func (s *Scanner) SetPaths(p scan.Paths) {
+	s.paths = p
+}
+
+// SetMmap reads files through memory mappings instead of buffered reads,
+// where the platform supports it.
+func (s *Scanner) SetMmap(on bool) {
+	s.mmap = on
+}
+
+// fileRef is a file found by the walk, not yet read.
+type fileRef struct {
+	path, rel, lang string
+}
+
+// Scan walks the project and runs all rules against matching files. It
+// stops early and returns ctx.Err() when ctx is cancelled.
+//
+// Files stream from the walk through a bounded worker pool; each worker
+// reads one file, runs the per-file rules on it, hands it to project-wide
+// rules to collect their evidence from, and drops it.
+func (s *Scanner) Scan(ctx context.Context) ([]Finding, error) {
+	start := time.Now()
+	s.statsMu.Lock()
+	s.stats = ScanStats{Skipped: SkipCounts{}}
+	s.statsMu.Unlock()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	refs := make(chan fileRef, s.workers)
+	var walkErr error
+	go func() {
+		defer close(refs)
+		walkErr = s.walk(ctx, refs)
+	}()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		hits     = make(map[Rule][]Finding)
+		evidence = make(map[Rule][]collected)
+		// ignores holds the greenlight:ignore comments of files that
+		// project rules collected from, for filtering their findings.
+		ignores = make(map[string]map[int]string)
+		trace   = slog.Default().Enabled(ctx, slog.LevelDebug)
+		stats   = make(map[Rule]*ruleStats)
+		// suppressed holds rules whose global anti-pattern matched somewhere
+		// in the project; their hits are dropped once every file is seen.
+		suppressed = make(map[string]bool)
+	)
+
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ref := range refs {
+				if ctx.Err() != nil {
+					continue // drain
+				}
+				fc, ok := s.load(ctx, ref)
+				if !ok {
+					continue
+				}
+				directives := ignoreDirectives(fc)
+				retain := false
+				for _, rule := range s.rules {
+					if !rule.Applies(fc) {
+						continue
+					}
+					if pr, ok := rule.(ProjectRule); ok {
+						// Run once below, on what each file contributed.
+						if ev := pr.Collect(fc); ev != nil {
+							mu.Lock()
+							evidence[rule] = append(evidence[rule], collected{fc.RelPath, ev})
+							mu.Unlock()
+							retain = true
+						}
+						continue
+					}
+					if gar, ok := rule.(GlobalAntiPatternRule); ok && gar.HasGlobalAntiPatterns() {
+						mu.Lock()
+						done := suppressed[gar.RuleID()]
+						mu.Unlock()
+						if done {
+							continue
+						}
+						if gar.AntiPatternMatched(fc) {
+							mu.Lock()
+							suppressed[gar.RuleID()] = true
+							mu.Unlock()
+							slog.DebugContext(ctx, "codescan rule suppressed by project-wide pattern", "rule", gar.RuleID(), "file", fc.RelPath)
+							continue
+						}
+					}
+					var ruleStart time.Time
+					if trace {
+						ruleStart = time.Now()
+					}
+					found := filterSuppressed(rule, rule.Check(fc), map[string]map[int]string{fc.RelPath: directives})
+					if len(found) > 0 || trace {
+						mu.Lock()
+						hits[rule] = append(hits[rule], found...)
+						if trace {
+							stats[rule] = stats[rule].add(len(found), time.Since(ruleStart))
+						}
+						mu.Unlock()
+					}
+				}
+				if retain && directives != nil {
+					mu.Lock()
+					ignores[fc.RelPath] = directives
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+	read := s.Stats()
+	slog.DebugContext(ctx, "codescan files scanned", "root", s.root, "files", read.Files,
+		"skipped", read.Skipped.Total(), "duration", time.Since(start))
+
+	var findings, projectFindings []Finding
+	for _, rule := range s.rules {
+		if gar, ok := rule.(GlobalAntiPatternRule); ok && gar.HasGlobalAntiPatterns() && suppressed[gar.RuleID()] {
+			continue
+		}
+		findings = append(findings, hits[rule]...)
+		if st := stats[rule]; st != nil {
+			slog.DebugContext(ctx, "codescan rule", "rule", ruleName(rule), "files", st.files,
+				"hits", st.hits, "duration", st.duration)
+		}
+	}
+
+	// Project-wide rules see the evidence of every file at once, in path order.
+	retained := make(map[string]int)
+	for _, rule := range s.rules {
+		pr, ok := rule.(ProjectRule)
+		if !ok {
+			continue
+		}
+		files := evidence[rule]
+		retained[ruleName(rule)] = len(files)
+		sort.Slice(files, func(i, j int) bool { return files[i].rel < files[j].rel })
+		evs := make([]any, len(files))
+		for i, f := range files {
+			evs[i] = f.evidence
+		}
+		ruleStart := time.Now()
+		found := filterSuppressed(rule, pr.CheckProject(evs), ignores)
+		projectFindings = append(projectFindings, found...)
+		slog.DebugContext(ctx, "codescan project rule", "rule", ruleName(rule), "files", len(files),
+			"hits", len(found), "duration", time.Since(ruleStart))
+	}
+
+	s.statsMu.Lock()
+	s.stats.Retained = retained
+	s.statsMu.Unlock()
+
+	owners, err := codeowners.Load(s.root)
+	if err != nil {
+		slog.DebugContext(ctx, "codescan CODEOWNERS not read", "err", err)
+	}
+	annotate(findings, owners)
+	annotate(projectFindings, owners)
+
+	s.lastMu.Lock()
+	s.suppressed, s.owners = suppressed, owners
+	s.project = make(map[string][]Finding)
+	for _, f := range projectFindings {
+		s.project[f.File] = append(s.project[f.File], f)
+	}
+	s.lastMu.Unlock()
+
+	findings = append(findings, projectFindings...)
+	SortFindings(findings)
+	return findings, nil
+}
+
+// ScanFile checks one file's content, such as an unsaved editor buffer,
+// without walking the project. relPath is the file's path relative to the
+// root. Per-file rules run on content; findings of project-wide rules, and
+// rules suppressed by a project-wide anti-pattern, come from the last Scan.
+// Files codescan doesn't read (unknown types, minified sources) have no
+// findings.
+func (s *Scanner) ScanFile(relPath string, content []byte) []Finding {
+	lang := detectLanguage(relPath)
+	if lang == "" || !s.paths.Includes(relPath) {
+		return nil
+	}
+	lines := splitLines(content)
+	if (lang == "javascript" || lang == "typescript") && sourcefile.IsMinified(relPath, lines) {
+		return nil
+	}
+	fc := FileContext{
+		Path:     filepath.Join(s.root, relPath),
+		RelPath:  relPath,
+		Lines:    lines,
+		Language: lang,
+	}
+	if lang == "swift" && s.swift != nil {
+		if syn, err := s.swift.Parse(fc.Path, string(content)); err == nil {
+			fc.Syntax = syn
+		}
+	}
+
+	s.lastMu.Lock()
+	suppressed, owners := s.suppressed, s.owners
+	findings := append([]Finding(nil), s.project[relPath]...)
+	s.lastMu.Unlock()
+
+	var hits []Finding
+	directives := ignoreDirectives(fc)
+	for _, rule := range s.rules {
+		if _, ok := rule.(ProjectRule); ok || !rule.Applies(fc) {
+			continue
+		}
+		if gar, ok := rule.(GlobalAntiPatternRule); ok && gar.HasGlobalAntiPatterns() &&
+			(suppressed[gar.RuleID()] || gar.AntiPatternMatched(fc)) {
+			continue
+		}
+		hits = append(hits, filterSuppressed(rule, rule.Check(fc), map[string]map[int]string{relPath: directives})...)
+	}
+	annotate(hits, owners)
+	findings = append(findings, hits...)
+	SortFindings(findings)
+	return findings
+}
+
+// annotate fills in what every finding carries besides the rule's own
+// output: owners, the guideline reference, IDs, risk and triage hints.
+func annotate(findings []Finding, owners *codeowners.File) {
+	for i := range findings {
+		f := &findings[i]
+		f.Owners = owners.Owners(f.File)
+		f.GuidelineTitle, f.GuidelineURL = guidelines.Reference(f.Guideline)
+		f.RuleID = findingid.RuleID("codescan", f.RuleID, f.Title)
+		f.Fingerprint = findingid.Fingerprint(f.RuleID, f.File, f.Code, f.Title)
+		f.Risk = risk.Score(f.Severity, f.Guideline)
+		hint := triage.For("codescan", f.RuleID, "", f.Guideline)
+		f.Effort, f.Owner = string(hint.Effort), string(hint.Owner)
+	}
+}
+
+// ruleStats accumulates a rule's per-file runs for debug traces.
+type ruleStats struct {
+	files    int
+	hits     int
+	duration time.Duration
+}
+
+func (st *ruleStats) add(hits int, d time.Duration) *ruleStats {
+	if st == nil {
+		st = &ruleStats{}
+	}
+	st.files++
+	st.hits += hits
+	st.duration += d
+	return st
+}
+
+// ruleName is the rule's ID, or its Go type for rules without one.
+func ruleName(rule Rule) string {
+	if d, ok := rule.(DescribedRule); ok {
+		return d.Info().ID
+	}
+	return fmt.Sprintf("%T", rule)
+}
+
+// collected is the evidence a ProjectRule took from one file.
+type collected struct {
+	rel      string
+	evidence any
+}
+
+// filterSuppressed tags each finding with its rule ID and drops findings
+// silenced by an inline `greenlight:ignore` comment. directives holds each
+// file's comments, as returned by ignoreDirectives.
+func filterSuppressed(rule Rule, hits []Finding, directives map[string]map[int]string) []Finding {
+	d, ok := rule.(DescribedRule)
+	if !ok {
+		return hits
+	}
+	id := d.Info().ID
+	kept := hits[:0]
+	for _, h := range hits {
+		h.RuleID = id
+		if h.Line > 0 && inlineSuppressed(directives[h.File], h.Line, id) {
+			continue
+		}
+		kept = append(kept, h)
+	}
+	return kept
+}
+
+// skippedDirs are the default excludes, for the walks rules make to find
+// one kind of file.
+var skippedDirs = func() map[string]bool {
+	dirs := make(map[string]bool, len(scan.DefaultExclude))
+	for _, name := range scan.DefaultExclude {
+		dirs[name] = true
+	}
+	return dirs
+}()
+
+// walk sends every scannable file under the root to refs, skipping
+// dependency directories and oversized sources.
+func (s *Scanner) walk(ctx context.Context, refs chan<- fileRef) error {
+	// Build output is only searched for shipped JS bundles.
+	buildDirs := map[string]bool{
+		"build": true, "dist": true, "DerivedData": true,
+	}
+
+	return sourcefile.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return nil // skip errors
+		}
+
+		relPath, _ := filepath.Rel(s.root, path)
+		if info.IsDir() {
+			if s.paths.SkipDir(relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		lang := detectLanguage(path)
+		if lang == "" || (lang != "jsbundle" && inDirs(relPath, buildDirs)) || !s.paths.Includes(relPath) {
+			return nil
+		}
+		if lang != "jsbundle" && s.maxFileSize > 0 && info.Size() > s.maxFileSize {
+			s.skip(ctx, relPath, sourcefile.TooLarge)
+			return nil
+		}
+
+		select {
+		case refs <- fileRef{path: path, rel: relPath, lang: lang}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	})
+}
+
+// load reads ref into a FileContext, parsing Swift when a backend is set.
+// Unreadable, binary and minified files are skipped. Shipped bundles are
+// minified (or Hermes bytecode) by design and always read.
+func (s *Scanner) load(ctx context.Context, ref fileRef) (FileContext, bool) {
+	var (
+		lines []string
+		err   error
+	)
+	switch {
+	case s.mmap:
+		lines, err = mapLines(ref.path, ref.lang == "jsbundle")
+	case ref.lang == "jsbundle":
+		// Bundles are minified onto lines far longer than readLines allows.
+		var data []byte
+		if data, err = os.ReadFile(ref.path); err == nil {
+			lines = strings.Split(string(data), "\n")
+		}
+	default:
+		lines, err = sourcefile.ReadLines(ref.path)
+	}
+	if err == sourcefile.ErrBinary {
+		s.skip(ctx, ref.rel, sourcefile.Binary)
+		return FileContext{}, false
+	}
+	if err != nil {
+		slog.DebugContext(ctx, "codescan file unreadable", "file", ref.rel, "error", err)
+		return FileContext{}, false
+	}
+	if (ref.lang == "javascript" || ref.lang == "typescript") && sourcefile.IsMinified(ref.path, lines) {
+		s.skip(ctx, ref.rel, sourcefile.Minified)
+		return FileContext{}, false
+	}
+	s.statsMu.Lock()
+	s.stats.Files++
+	s.statsMu.Unlock()
+
+	fc := FileContext{
+		Path:     ref.path,
+		RelPath:  ref.rel,
+		Lines:    lines,
+		Language: ref.lang,
+	}
+	if ref.lang == "swift" && s.swift != nil {
+		// Fall back to line matching if the backend fails on this file.
+		if syn, err := s.swift.Parse(ref.path, strings.Join(lines, "\n")); err == nil {
+			fc.Syntax = syn
+		}
+	}
+	return fc, true
+}
+
+// inDirs reports whether any directory in relPath is in dirs.
+func inDirs(relPath string, dirs map[string]bool) bool {
+	for _, part := range strings.Split(filepath.Dir(relPath), string(filepath.Separator)) {
+		if dirs[part] {
+			return true
+		}
+	}
+	return false
+}
+
+func detectLanguage(path string) string {
+	if IsJSBundle(path) {
+		return "jsbundle"
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	base := strings.ToLower(filepath.Base(path))
+
+	switch ext {
+	case ".swift":
+		return "swift"
+	case ".m", ".h":
+		return "objc"
+	case ".ts", ".tsx":
+		return "typescript"
+	case ".js", ".jsx":
+		return "javascript"
+	case ".plist":
+		return "plist"
+	case ".entitlements":
+		return "plist"
+	case ".xcprivacy":
+		return "plist"
+	}
+
+	switch base {
+	case "package.json", "app.json", "app.config.js", "app.config.ts":
+		return "json"
+	case "info.plist":
+		return "plist"
+	case "privacyinfo.xcprivacy":
+		return "plist"
+	}
+
+	return ""
+}
+
+// splitLines splits data the way sourcefile.ReadLines does (no trailing empty line,
+// "\r\n" endings trimmed), copying each line out of data.
+func splitLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		line := data
+		if i >= 0 {
+			line, data = data[:i], data[i+1:]
+		} else {
+			data = nil
+		}
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+		lines = append(lines, string(line))
+	}
+	return lines
+}
@@ -0,0 +1,75 @@
+package codescan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// checkProject runs rule over files the way Scan does: evidence from each
+// file, in order, then one CheckProject.
+func checkProject(rule ProjectRule, files ...FileContext) []Finding {
+	var evidence []any
+	for _, fc := range files {
+		if ev := rule.Collect(fc); ev != nil {
+			evidence = append(evidence, ev)
+		}
+	}
+	return rule.CheckProject(evidence)
+}
+
+func TestScanRetainsOnlyEvidence(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"App/Health.swift":     healthSource,
+		"App/Chat.swift":       "import StreamChat\nlet chat = ChatView()\n",
+		"App/App.entitlements": "<plist><dict><key>com.apple.developer.healthkit</key><true/></dict></plist>\n",
+	}
+	for i := 0; i < 40; i++ {
+		files[fmt.Sprintf("App/Views/View%02d.swift", i)] = fmt.Sprintf("import SwiftUI\n\nstruct View%02d: View {\n    var body: some View { Text(\"%d\") }\n}\n", i, i)
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewScanner(root)
+	findings, err := s.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := s.Stats()
+	if stats.Files != len(files) {
+		t.Fatalf("scanned %d files, want %d", stats.Files, len(files))
+	}
+
+	// Only the web view rule, which counts lines of code, keeps something
+	// from every source file.
+	want := map[string]int{
+		"health-data":    2,
+		"ugc-moderation": 1,
+		"webview-only":   len(files) - 1,
+	}
+	for rule, n := range stats.Retained {
+		if n != want[rule] {
+			t.Errorf("%s retained %d files, want %d", rule, n, want[rule])
+		}
+	}
+
+	found := map[string]bool{}
+	for _, f := range findings {
+		found[f.RuleID] = true
+	}
+	for _, rule := range []string{"health-data", "ugc-moderation"} {
+		if !found[rule] {
+			t.Errorf("no %s finding from the retained evidence", rule)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package swiftsyntax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Backend parses a Swift file into its syntax view.
+type Backend interface {
+	Name() string
+	Parse(path, src string) (*File, error)
+}
+
+// NewBackend returns the backend for a --swift-ast mode: "builtin" (the
+// bundled lexer), "sourcekitten" (requires sourcekitten on PATH), or "auto"
+// (sourcekitten when installed, builtin otherwise). "off" or "" returns nil.
+func NewBackend(mode string) (Backend, error) {
+	switch strings.ToLower(mode) {
+	case "", "off":
+		return nil, nil
+	case "builtin":
+		return Builtin{}, nil
+	case "sourcekitten":
+		bin, err := exec.LookPath("sourcekitten")
+		if err != nil {
+			return nil, fmt.Errorf("sourcekitten not found on PATH (brew install sourcekitten, or use --swift-ast builtin)")
+		}
+		return SourceKitten{Bin: bin}, nil
+	case "auto":
+		if bin, err := exec.LookPath("sourcekitten"); err == nil {
+			return SourceKitten{Bin: bin}, nil
+		}
+		return Builtin{}, nil
+	}
+	return nil, fmt.Errorf("unknown --swift-ast mode %q (use off, auto, builtin or sourcekitten)", mode)
+}
+
+// Builtin is the bundled pure-Go lexer and call extractor.
+type Builtin struct{}
+
+func (Builtin) Name() string { return "builtin" }
+
+func (Builtin) Parse(path, src string) (*File, error) {
+	return Parse(src), nil
+}
+
+// SourceKitten uses `sourcekitten structure` for call ranges, which follows
+// the real Swift parser. Comments and strings still come from the lexer.
+type SourceKitten struct {
+	Bin string
+}
+
+func (SourceKitten) Name() string { return "sourcekitten" }
+
+// skNode is one entry of `sourcekitten structure` output.
+type skNode struct {
+	Kind         string   `json:"key.kind"`
+	Name         string   `json:"key.name"`
+	Offset       int      `json:"key.offset"`
+	Length       int      `json:"key.length"`
+	Substructure []skNode `json:"key.substructure"`
+}
+
+func (s SourceKitten) Parse(path, src string) (*File, error) {
+	out, err := exec.Command(s.Bin, "structure", "--file", path).Output()
+	if err != nil {
+		return nil, fmt.Errorf("sourcekitten structure %s: %w", path, err)
+	}
+	var root skNode
+	if err := json.Unmarshal(out, &root); err != nil {
+		return nil, fmt.Errorf("sourcekitten structure %s: %w", path, err)
+	}
+
+	toks := Tokenize(src)
+	f := &File{Tokens: toks, CodeLines: codeLines(src, toks)}
+
+	var walk func(nodes []skNode)
+	walk = func(nodes []skNode) {
+		for _, n := range nodes {
+			if n.Kind == "source.lang.swift.expr.call" && n.Offset+n.Length <= len(src) {
+				name := n.Name
+				if i := strings.LastIndex(name, "."); i >= 0 {
+					name = name[i+1:]
+				}
+				before := strings.TrimSpace(src[:n.Offset])
+				before = strings.TrimSpace(strings.TrimSuffix(before, "try"))
+				f.Calls = append(f.Calls, Call{
+					Callee:  n.Name,
+					Name:    name,
+					Line:    strings.Count(src[:n.Offset], "\n") + 1,
+					Start:   n.Offset,
+					End:     n.Offset + n.Length,
+					Awaited: strings.HasSuffix(before, "await"),
+				})
+			}
+			walk(n.Substructure)
+		}
+	}
+	walk(root.Substructure)
+	return f, nil
+}
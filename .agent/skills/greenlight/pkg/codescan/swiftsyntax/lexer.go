@@ -0,0 +1,191 @@
+// Package swiftsyntax is a lightweight Swift front end for codescan. It
+// tokenizes Swift source (comments, string literals, identifiers) and extracts
+// function calls with their argument and trailing-closure ranges, so rules can
+// match real syntax instead of raw lines.
+package swiftsyntax
+
+import "strings"
+
+// TokenKind classifies a token.
+type TokenKind int
+
+const (
+	TokIdent TokenKind = iota
+	TokString
+	TokComment
+	TokNumber
+	TokPunct
+)
+
+// Token is a lexical token. Offset is the byte offset into the source and
+// Line is 1-indexed.
+type Token struct {
+	Kind   TokenKind
+	Text   string
+	Offset int
+	Line   int
+}
+
+// End returns the byte offset just past the token.
+func (t Token) End() int { return t.Offset + len(t.Text) }
+
+// Tokenize splits Swift source into tokens. It understands line and nested
+// block comments, string literals (including multi-line and raw strings with
+// interpolation), identifiers, numbers and punctuation.
+func Tokenize(src string) []Token {
+	var (
+		toks []Token
+		line = 1
+		i    = 0
+		n    = len(src)
+	)
+
+	emit := func(kind TokenKind, start, end int) {
+		text := src[start:end]
+		toks = append(toks, Token{Kind: kind, Text: text, Offset: start, Line: line})
+		line += strings.Count(text, "\n")
+	}
+
+	for i < n {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '/' && i+1 < n && src[i+1] == '/':
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = n - i
+			}
+			emit(TokComment, i, i+end)
+			i += end
+		case c == '/' && i+1 < n && src[i+1] == '*':
+			end := blockCommentEnd(src, i)
+			emit(TokComment, i, end)
+			i = end
+		case c == '"' || (c == '#' && rawStringStart(src, i)):
+			end := stringEnd(src, i)
+			emit(TokString, i, end)
+			i = end
+		case isIdentStart(c) || c == '`' || c == '$':
+			start := i
+			if c == '`' {
+				if end := strings.IndexByte(src[i+1:], '`'); end >= 0 {
+					i += end + 2
+				} else {
+					i++
+				}
+			} else {
+				i++
+				for i < n && isIdentPart(src[i]) {
+					i++
+				}
+			}
+			emit(TokIdent, start, i)
+		case c >= '0' && c <= '9':
+			start := i
+			for i < n && (isIdentPart(src[i]) || src[i] == '.' && i+1 < n && src[i+1] >= '0' && src[i+1] <= '9') {
+				i++
+			}
+			emit(TokNumber, start, i)
+		default:
+			emit(TokPunct, i, i+1)
+			i++
+		}
+	}
+	return toks
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// blockCommentEnd returns the offset after a (possibly nested) /* */ comment.
+func blockCommentEnd(src string, i int) int {
+	depth := 0
+	for i < len(src) {
+		switch {
+		case strings.HasPrefix(src[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(src[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+// rawStringStart reports whether src[i:] begins a raw string like #"..."#.
+func rawStringStart(src string, i int) bool {
+	for i < len(src) && src[i] == '#' {
+		i++
+	}
+	return i < len(src) && src[i] == '"'
+}
+
+// stringEnd returns the offset after the string literal starting at i.
+func stringEnd(src string, i int) int {
+	hashes := 0
+	for i < len(src) && src[i] == '#' {
+		hashes++
+		i++
+	}
+	closing := strings.Repeat("#", hashes)
+	escape := "\\" + closing
+
+	multiline := strings.HasPrefix(src[i:], `"""`)
+	if multiline {
+		i += 3
+	} else {
+		i++
+	}
+
+	for i < len(src) {
+		switch {
+		case strings.HasPrefix(src[i:], escape+"("):
+			i = interpolationEnd(src, i+len(escape)+1)
+		case strings.HasPrefix(src[i:], escape) && len(escape) > 0 && hashes == 0:
+			i += 2 // skip escaped character
+		case multiline && strings.HasPrefix(src[i:], `"""`+closing):
+			return i + 3 + hashes
+		case !multiline && strings.HasPrefix(src[i:], `"`+closing):
+			return i + 1 + hashes
+		case !multiline && src[i] == '\n':
+			return i // unterminated
+		default:
+			i++
+		}
+	}
+	return len(src)
+}
+
+// interpolationEnd skips a \( ... ) interpolation, which may contain nested
+// parentheses and string literals.
+func interpolationEnd(src string, i int) int {
+	depth := 1
+	for i < len(src) && depth > 0 {
+		switch c := src[i]; {
+		case c == '"':
+			i = stringEnd(src, i)
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+		i++
+	}
+	return i
+}
@@ -0,0 +1,219 @@
+package swiftsyntax
+
+import "strings"
+
+// File is the syntax view of one Swift source file.
+type File struct {
+	Tokens []Token
+	Calls  []Call
+
+	// CodeLines are the source lines with comments blanked out, so line
+	// patterns only match code and string literals.
+	CodeLines []string
+}
+
+// Call is a function or method call, e.g. AppsFlyerLib.shared().start().
+type Call struct {
+	// Callee is the receiver chain and name without the final argument list,
+	// e.g. "AppsFlyerLib.shared().start".
+	Callee string
+	// Name is the last identifier of the callee, e.g. "start".
+	Name string
+	Line int
+	// Start and End are byte offsets of the whole call, including arguments
+	// and any trailing closure.
+	Start, End int
+	// Awaited is true when the call is preceded by `await`, so code after it
+	// in the same scope runs once it completes.
+	Awaited bool
+}
+
+// Contains reports whether the byte offset falls inside the call's arguments
+// or trailing closure.
+func (c Call) Contains(offset int) bool {
+	return offset > c.Start && offset < c.End
+}
+
+// Parse tokenizes src and extracts its calls.
+func Parse(src string) *File {
+	toks := Tokenize(src)
+	return &File{
+		Tokens:    toks,
+		Calls:     extractCalls(toks),
+		CodeLines: codeLines(src, toks),
+	}
+}
+
+// CallsMatching returns calls whose callee contains any of the substrings.
+func (f *File) CallsMatching(substrs ...string) []Call {
+	var out []Call
+	for _, c := range f.Calls {
+		for _, s := range substrs {
+			if strings.Contains(c.Callee, s) {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Enclosing returns the calls whose arguments or trailing closure contain the
+// offset, innermost last.
+func (f *File) Enclosing(offset int) []Call {
+	var out []Call
+	for _, c := range f.Calls {
+		if c.Contains(offset) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// declKeywords introduce a declaration or statement rather than a call when
+// followed by an identifier and a brace.
+var declKeywords = map[string]bool{
+	"func": true, "class": true, "struct": true, "enum": true, "extension": true,
+	"protocol": true, "var": true, "let": true, "if": true, "guard": true,
+	"while": true, "for": true, "switch": true, "case": true, "else": true,
+	"in": true, "return": true, "init": true, "actor": true, "import": true,
+	"some": true, "any": true, "where": true, "repeat": true, "do": true,
+	"catch": true, "get": true, "set": true, "willSet": true, "didSet": true,
+}
+
+// codeTokens drops comments so call extraction sees only code.
+func codeTokens(toks []Token) []Token {
+	out := make([]Token, 0, len(toks))
+	for _, t := range toks {
+		if t.Kind != TokComment {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func extractCalls(all []Token) []Call {
+	toks := codeTokens(all)
+	var calls []Call
+
+	for i := 0; i < len(toks); i++ {
+		t := toks[i]
+		if t.Kind != TokIdent || declKeywords[t.Text] {
+			continue
+		}
+		if i > 0 && isPunct(toks[i-1], ".") {
+			continue // middle of a chain; handled from its head
+		}
+		if i > 0 && toks[i-1].Kind == TokIdent && declKeywords[toks[i-1].Text] && toks[i-1].Text != "return" && toks[i-1].Text != "in" && toks[i-1].Text != "else" {
+			continue // declaration name, e.g. func foo(
+		}
+
+		awaited := precededByAwait(toks, i)
+		cond := inCondition(toks, i)
+		var chain strings.Builder
+		chain.WriteString(t.Text)
+		name := t.Text
+		start := t.Offset
+		j := i + 1
+
+		for j < len(toks) {
+			switch {
+			case isPunct(toks[j], ".") && j+1 < len(toks) && toks[j+1].Kind == TokIdent:
+				chain.WriteString("." + toks[j+1].Text)
+				name = toks[j+1].Text
+				j += 2
+				continue
+			case isPunct(toks[j], "?") || isPunct(toks[j], "!"):
+				j++
+				continue
+			case isPunct(toks[j], "(") && toks[j].Offset == toks[j-1].End():
+				closeIdx := matching(toks, j, "(", ")")
+				end := toks[closeIdx].End()
+				next := closeIdx + 1
+				if !cond && next < len(toks) && isPunct(toks[next], "{") {
+					next = matching(toks, next, "{", "}")
+					end = toks[next].End()
+					next++
+				}
+				calls = append(calls, Call{Callee: chain.String(), Name: name, Line: t.Line, Start: start, End: end, Awaited: awaited})
+				chain.WriteString("()")
+				j = next
+				continue
+			case !cond && isPunct(toks[j], "{") && strings.Contains(chain.String(), ".") && !strings.HasSuffix(chain.String(), ")"):
+				// Trailing-closure-only call: DispatchQueue.main.async { ... }
+				closeIdx := matching(toks, j, "{", "}")
+				calls = append(calls, Call{Callee: chain.String(), Name: name, Line: t.Line, Start: start, End: toks[closeIdx].End(), Awaited: awaited})
+				j = closeIdx + 1
+			}
+			break
+		}
+	}
+	return calls
+}
+
+func precededByAwait(toks []Token, i int) bool {
+	for k := i - 1; k >= 0 && k >= i-3; k-- {
+		switch toks[k].Text {
+		case "await":
+			return true
+		case "try", "?", "!":
+			continue
+		}
+		return false
+	}
+	return false
+}
+
+// inCondition reports whether the token at i is part of an if/guard/while
+// condition on the same line, where a following brace opens the statement
+// body rather than a trailing closure.
+func inCondition(toks []Token, i int) bool {
+	for k := i - 1; k >= 0 && toks[k].Line == toks[i].Line; k-- {
+		switch toks[k].Text {
+		case "{", "}", ";":
+			return false
+		case "if", "guard", "while", "switch", "for", "where":
+			return toks[k].Kind == TokIdent
+		}
+	}
+	return false
+}
+
+func isPunct(t Token, s string) bool {
+	return t.Kind == TokPunct && t.Text == s
+}
+
+// matching returns the index of the token closing the bracket at open, or
+// the last token if the source is unbalanced.
+func matching(toks []Token, open int, o, c string) int {
+	depth := 0
+	for k := open; k < len(toks); k++ {
+		switch {
+		case isPunct(toks[k], o):
+			depth++
+		case isPunct(toks[k], c):
+			depth--
+			if depth == 0 {
+				return k
+			}
+		}
+	}
+	return len(toks) - 1
+}
+
+// codeLines blanks every comment in src (keeping newlines) and splits it
+// into lines.
+func codeLines(src string, toks []Token) []string {
+	b := []byte(src)
+	for _, t := range toks {
+		if t.Kind != TokComment {
+			continue
+		}
+		for k := t.Offset; k < t.End(); k++ {
+			if b[k] != '\n' {
+				b[k] = ' '
+			}
+		}
+	}
+	return strings.Split(string(b), "\n")
+}
@@ -0,0 +1,161 @@
+package codescan
+
+import (
+	"github.com/RevylAI/greenlight/internal/findingorder"
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Severity is how likely a finding is to cause rejection.
+type Severity = severity.Level
+
+const (
+	SeverityInfo     = severity.Info     // Best practice
+	SeverityWarn     = severity.Warn     // High risk
+	SeverityCritical = severity.Critical // Almost certain rejection
+)
+
+// Finding is a single issue found in code.
+type Finding struct {
+	RuleID    string   `json:"rule_id,omitempty"`
+	Severity  Severity `json:"severity"`
+	Guideline string   `json:"guideline"`
+	Title     string   `json:"title"`
+	Detail    string   `json:"detail"`
+	Fix       string   `json:"fix,omitempty"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"` // 1-indexed
+	Code      string   `json:"code,omitempty"`
+	Secret    string   `json:"-"` // matched credential, for redaction
+	// SecretType is the provider name for provider-fingerprinted secrets.
+	SecretType string `json:"-"`
+	// Verification is the --verify-secrets outcome: "live", "invalid",
+	// "unverifiable" or "error".
+	Verification string `json:"verification,omitempty"`
+	// GuidelineTitle and GuidelineURL resolve Guideline for reports.
+	GuidelineTitle string `json:"guideline_title,omitempty"`
+	GuidelineURL   string `json:"guideline_url,omitempty"`
+	// Fingerprint identifies this occurrence across runs (rule, file and
+	// normalized line).
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Risk is the estimated likelihood, from 0 to 100, that the finding
+	// gets the app rejected (see internal/risk).
+	Risk int `json:"risk,omitempty"`
+	// Effort and Owner suggest the size of the fix and the team it belongs
+	// to (see internal/triage).
+	Effort string `json:"effort,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+	// Owners are the CODEOWNERS owners of File.
+	Owners []string `json:"owners,omitempty"`
+}
+
+// ScanFinding converts f to scan.Finding, with "codescan" as its source.
+func (f Finding) ScanFinding() scan.Finding {
+	return scan.Finding{
+		Source:         "codescan",
+		RuleID:         f.RuleID,
+		Severity:       f.Severity,
+		Guideline:      f.Guideline,
+		GuidelineTitle: f.GuidelineTitle,
+		GuidelineURL:   f.GuidelineURL,
+		Title:          f.Title,
+		Detail:         f.Detail,
+		Fix:            f.Fix,
+		File:           f.File,
+		Line:           f.Line,
+		Code:           f.Code,
+		Secret:         f.Secret,
+		Fingerprint:    f.Fingerprint,
+		Risk:           f.Risk,
+		Effort:         f.Effort,
+		Owner:          f.Owner,
+		Owners:         f.Owners,
+	}
+}
+
+// Rule is a code pattern check.
+type Rule interface {
+	// Applies returns true if this rule should run on the given file.
+	Applies(fc FileContext) bool
+	// Check runs the rule and returns any findings.
+	Check(fc FileContext) []Finding
+}
+
+// GlobalAntiPatternRule is implemented by rules that suppress findings when
+// anti-patterns are found anywhere in the project (not just the current file).
+type GlobalAntiPatternRule interface {
+	Rule
+	// HasGlobalAntiPatterns returns true if this rule uses project-wide anti-pattern suppression.
+	HasGlobalAntiPatterns() bool
+	// AntiPatternMatched returns true if any anti-pattern matches the given file.
+	AntiPatternMatched(fc FileContext) bool
+	// RuleID returns the rule identifier.
+	RuleID() string
+}
+
+// ProjectRule is implemented by rules that correlate evidence across several
+// files (e.g. code usage vs. entitlements) instead of checking one file at a time.
+// Applies selects the files handed to Collect; Check is not called.
+type ProjectRule interface {
+	Rule
+	// Collect extracts what the rule needs from one applicable file — the
+	// first matching lines, flags, per-file findings — or returns nil when
+	// the file has nothing for it. It runs while the file is scanned; the
+	// file's lines are dropped afterwards, so evidence must not hold them.
+	Collect(fc FileContext) any
+	// CheckProject runs the rule once against the evidence collected from
+	// every applicable file, in path order.
+	CheckProject(evidence []any) []Finding
+}
+
+// Summary holds aggregate results.
+type Summary struct {
+	Total     int  `json:"total"`
+	Critical  int  `json:"critical"`
+	Warns     int  `json:"warns"`
+	Infos     int  `json:"infos"`
+	FilesRead int  `json:"files_scanned"`
+	Passed    bool `json:"passed"`
+	// Skipped counts files left out as binary, minified or too large.
+	Skipped SkipCounts `json:"files_skipped,omitempty"`
+	// RiskScore is the estimated likelihood, from 0 to 100, that the app is
+	// rejected for any of the findings; Grade rates it from A to F.
+	RiskScore int    `json:"risk_score"`
+	Grade     string `json:"grade"`
+}
+
+// SortFindings puts findings in report order: severity, then file, line and
+// title.
+func SortFindings(findings []Finding) {
+	findingorder.Sort(findings, func(f Finding) findingorder.Key {
+		return findingorder.Key{
+			Severity: f.Severity,
+			File:     f.File,
+			Line:     f.Line,
+			Title:    f.Title,
+			Detail:   f.Detail,
+		}
+	})
+}
+
+func ComputeSummary(findings []Finding, filesScanned int) Summary {
+	s := Summary{FilesRead: filesScanned}
+	var scores []int
+	for _, f := range findings {
+		s.Total++
+		scores = append(scores, risk.Score(f.Severity, f.Guideline))
+		switch f.Severity {
+		case SeverityCritical:
+			s.Critical++
+		case SeverityWarn:
+			s.Warns++
+		case SeverityInfo:
+			s.Infos++
+		}
+	}
+	s.Passed = s.Critical == 0
+	s.RiskScore = risk.Overall(scores)
+	s.Grade = risk.Grade(s.RiskScore)
+	return s
+}
@@ -0,0 +1,161 @@
+package codescan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ugcFeatures are the ways users publish content other users see. Chat is
+// recognized from chat SDKs and chat screens; the rest are heuristic, matched
+// on method and view names, and never raise the finding above WARN. Bare
+// method names shared with system APIs (WCSession.sendMessage, Firebase
+// Storage putData) are not evidence.
+var ugcFeatures = []struct {
+	name      string
+	pattern   *regexp.Regexp
+	heuristic bool
+}{
+	{"chat", regexp.MustCompile(`\b(ChatView|ChatScreen|ChatViewController|MessageKit|ExyteChat|StreamChat|GiftedChat|SendbirdChat|TwilioConversations|CometChat)\b|react-native-gifted-chat|stream-chat|@sendbird/`), false},
+	{"comments", regexp.MustCompile(`\b(addComment|postComment|createComment|submitComment|CommentsView|CommentList|CommentSection|CommentComposer)\b`), true},
+	{"posts", regexp.MustCompile(`\b(createPost|submitPost|publishPost|ComposePost\w*|PostComposer|NewPostView|CreatePostScreen)\b`), true},
+	{"uploads", regexp.MustCompile(`\b(uploadPhoto|uploadVideo|uploadImage|uploadMedia|uploadAvatar)\b`), true},
+}
+
+// ugcSafeguards are what guideline 1.2 requires of apps with user-generated
+// content, in checklist order.
+var ugcSafeguards = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"report/flag mechanism", regexp.MustCompile(`(?i)(\b(report|flag)(Content|Post|User|Comment|Message|Abuse)\b|["'>]\s*(Report|Flag)( (post|comment|user|message|content|abuse))?\s*(\.\.\.|…)?\s*["'<]|report\s+(abuse|inappropriate))`)},
+	{"block users", regexp.MustCompile(`(?i)(\b(un)?blockUser\b|\bblocked(Users|UserIds|UserIDs)\b|\bblock_user\b|["'>]\s*Block( user)?\s*["'<])`)},
+	{"EULA / terms link", regexp.MustCompile(`(?i)(\bEULA\b|terms of (use|service)|\bterms(Of(Use|Service))?(URL|Url|Link)\b|/terms\b|\b(accept|agreedTo|hasAccepted)Terms\b)`)},
+	{"moderation contact", regexp.MustCompile(`(?i)(mailto:|\b(support|abuse|safety|moderation|trust|report)@[\w-]+\.[\w.]+|contact (us|support)|\bcontactSupport\b|/contact\b|/support\b)`)},
+}
+
+// UGCModerationRule detects user-generated content (chat, comments, posts,
+// uploads) and checks for the guideline 1.2 safeguards: a way to report
+// content, to block abusive users, terms users agree to, and published
+// contact information. The finding is a checklist with the file and line
+// behind each item.
+type UGCModerationRule struct {
+	id string
+}
+
+func (r *UGCModerationRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript":
+		return !isToolingConfig(fc.RelPath)
+	}
+	return false
+}
+
+func (r *UGCModerationRule) Check(fc FileContext) []Finding { return nil }
+
+// ugcEvidence is a file's first hit for each feature and safeguard, indexed
+// like ugcFeatures and ugcSafeguards, and its first feature hit overall.
+type ugcEvidence struct {
+	features  []*Finding
+	safeguard []*Finding
+	first     *Finding
+}
+
+func (r *UGCModerationRule) Collect(fc FileContext) any {
+	ev := &ugcEvidence{
+		features:  make([]*Finding, len(ugcFeatures)),
+		safeguard: make([]*Finding, len(ugcSafeguards)),
+	}
+	found := false
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		hit := func() *Finding { return &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed} }
+		for i, f := range ugcFeatures {
+			if ev.features[i] == nil && f.pattern.MatchString(line) {
+				ev.features[i] = hit()
+				if ev.first == nil {
+					ev.first = ev.features[i]
+				}
+				found = true
+			}
+		}
+		for i, s := range ugcSafeguards {
+			if ev.safeguard[i] == nil && s.pattern.MatchString(line) {
+				ev.safeguard[i] = hit()
+				found = true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return ev
+}
+
+func (r *UGCModerationRule) CheckProject(evidence []any) []Finding {
+	var (
+		features  = make([]*Finding, len(ugcFeatures))
+		safeguard = make([]*Finding, len(ugcSafeguards))
+		first     *Finding
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*ugcEvidence)
+		for i, f := range ev.features {
+			if features[i] == nil {
+				features[i] = f
+			}
+		}
+		for i, s := range ev.safeguard {
+			if safeguard[i] == nil {
+				safeguard[i] = s
+			}
+		}
+		if first == nil {
+			first = ev.first
+		}
+	}
+
+	if first == nil {
+		return nil
+	}
+	var found, checklist, missing []string
+	heuristic := true
+	for i, f := range ugcFeatures {
+		if features[i] != nil {
+			found = append(found, fmt.Sprintf("%s (%s:%d)", f.name, features[i].File, features[i].Line))
+			heuristic = heuristic && f.heuristic
+		}
+	}
+	for i, s := range ugcSafeguards {
+		if safeguard[i] != nil {
+			checklist = append(checklist, fmt.Sprintf("✓ %s (%s:%d)", s.name, safeguard[i].File, safeguard[i].Line))
+		} else {
+			checklist = append(checklist, "✗ "+s.name+" — not found")
+			missing = append(missing, s.name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Reporting and blocking are what reviewers test on device.
+	sev := SeverityWarn
+	if !heuristic && (safeguard[0] == nil || safeguard[1] == nil) {
+		sev = SeverityCritical
+	}
+	return []Finding{{
+		RuleID:    r.id,
+		Severity:  sev,
+		Guideline: "1.2",
+		Title:     "User-generated content without " + strings.Join(missing, ", "),
+		Detail:    "The app has user-generated content: " + strings.Join(found, ", ") + ". Guideline 1.2 checklist: " + strings.Join(checklist, "; ") + ".",
+		Fix:       "Let users report objectionable content and block abusive users from the content itself, require agreement to terms (EULA) with no tolerance for objectionable content before posting, and publish contact information for moderation. Act on reports within 24 hours, and mention the moderation flow in App Review notes with a demo account.",
+		File:      first.File,
+		Line:      first.Line,
+		Code:      first.Code,
+	}}
+}
@@ -0,0 +1,74 @@
+package codescan
+
+import (
+	"strings"
+	"testing"
+)
+
+func swiftFile(relPath, src string) FileContext {
+	return FileContext{RelPath: relPath, Lines: strings.Split(src, "\n"), Language: "swift"}
+}
+
+func TestUGCModeration(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want Severity // -1 for no finding
+	}{
+		{
+			name: "watch connectivity sendMessage",
+			src: `if session.isReachable {
+    session.sendMessage(context, replyHandler: nil) { error in
+        print("WatchConnectivity sendMessage failed: \(error)")
+    }
+}
+WCSession.default.sendMessage(["ping": 1], replyHandler: nil)`,
+			want: -1,
+		},
+		{
+			name: "firebase storage upload",
+			src:  `storage.child("exports/\(id).json").putData(data)`,
+			want: -1,
+		},
+		{
+			name: "chat screen",
+			src:  `ChatView(messages: messages)`,
+			want: SeverityCritical,
+		},
+		{
+			name: "chat SDK",
+			src:  `import StreamChat`,
+			want: SeverityCritical,
+		},
+		{
+			name: "heuristic only",
+			src:  `func uploadPhoto(_ image: UIImage) {}`,
+			want: SeverityWarn,
+		},
+		{
+			name: "heuristic with chat",
+			src: `import StreamChat
+func createPost() {}`,
+			want: SeverityCritical,
+		},
+	}
+
+	rule := &UGCModerationRule{id: "ugc-moderation"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkProject(rule, swiftFile("App/Feature.swift", tt.src))
+			if tt.want < 0 {
+				if len(got) != 0 {
+					t.Fatalf("findings = %+v, want none", got)
+				}
+				return
+			}
+			if len(got) != 1 {
+				t.Fatalf("got %d findings, want 1: %+v", len(got), got)
+			}
+			if got[0].Severity != tt.want {
+				t.Errorf("severity = %v, want %v", got[0].Severity, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package codescan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Verification outcomes.
+const (
+	VerifyLive         = "live"
+	VerifyInvalid      = "invalid"
+	VerifyUnverifiable = "unverifiable"
+	VerifyError        = "error"
+)
+
+// secretVerifier makes a read-only authenticated request that reveals whether
+// a credential is accepted, without changing anything on the provider side.
+type secretVerifier func(ctx context.Context, client *http.Client, secret string) (string, error)
+
+// Firebase Cloud Messaging server keys have no verifier: the legacy API they
+// authenticate against is shut down, and the HTTP v1 API only accepts OAuth
+// tokens from a service account, so such keys are reported as unverifiable.
+var secretVerifiers = map[string]secretVerifier{
+	"Stripe live secret key": verifyStripe,
+	"Stripe test secret key": verifyStripe,
+	"GitHub token":           verifyBearer("https://api.github.com/user"),
+	"OpenAI API key":         verifyBearer("https://api.openai.com/v1/models"),
+	"Slack token":            verifySlack,
+}
+
+// VerifySecrets checks each provider-fingerprinted secret against its
+// provider and records the outcome in Finding.Verification. Live keys stay at
+// their severity and are marked as confirmed; keys the provider rejects are
+// downgraded to INFO so teams can triage real leaks first. Each distinct
+// secret is sent only to its own provider, and only once. When ctx is
+// cancelled, requests in flight are aborted and the remaining findings are
+// left unverified.
+func VerifySecrets(ctx context.Context, findings []Finding) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	cache := make(map[string]string)
+
+	for i := range findings {
+		if ctx.Err() != nil {
+			return
+		}
+		f := &findings[i]
+		if f.Secret == "" {
+			continue
+		}
+		verify, ok := secretVerifiers[f.SecretType]
+		if !ok {
+			f.Verification = VerifyUnverifiable
+			continue
+		}
+
+		status, seen := cache[f.Secret]
+		if !seen {
+			var err error
+			status, err = verify(ctx, client, f.Secret)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				status = VerifyError
+			}
+			cache[f.Secret] = status
+		}
+		f.Verification = status
+
+		switch status {
+		case VerifyLive:
+			f.Title += " (verified live)"
+			f.Detail += " The provider accepted this credential — treat it as leaked."
+		case VerifyInvalid:
+			f.Severity = SeverityInfo
+			f.Title += " (rejected by provider)"
+			f.Detail += " The provider rejected this credential; it is revoked, expired or a stale test key. Remove it from source anyway."
+		}
+	}
+}
+
+// statusFor maps an HTTP status from an authenticated no-op call.
+func statusFor(code int) (string, error) {
+	switch {
+	case code >= 200 && code < 300:
+		return VerifyLive, nil
+	case code == http.StatusUnauthorized:
+		return VerifyInvalid, nil
+	case code == http.StatusForbidden:
+		// Authenticated but lacking scope: the key itself is valid.
+		return VerifyLive, nil
+	}
+	return "", fmt.Errorf("unexpected status %d", code)
+}
+
+func verifyStripe(ctx context.Context, client *http.Client, secret string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.stripe.com/v1/balance", nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(secret, "")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return statusFor(resp.StatusCode)
+}
+
+func verifyBearer(url string) secretVerifier {
+	return func(ctx context.Context, client *http.Client, secret string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+secret)
+		req.Header.Set("User-Agent", "greenlight")
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+		return statusFor(resp.StatusCode)
+	}
+}
+
+func verifySlack(ctx context.Context, client *http.Client, secret string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://slack.com/api/auth.test", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+secret)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.OK {
+		return VerifyLive, nil
+	}
+	if body.Error == "invalid_auth" || body.Error == "account_inactive" || body.Error == "token_revoked" {
+		return VerifyInvalid, nil
+	}
+	return "", fmt.Errorf("slack: %s", body.Error)
+}
@@ -0,0 +1,215 @@
+package codescan
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// A web view, and a load of a remote page into one.
+	webViewPattern     = regexp.MustCompile(`(WKWebView|UIWebView|SFSafariViewController|react-native-webview|<WebView\b|WebView\()`)
+	webViewLoadPattern = regexp.MustCompile(`(\.load\(\s*URLRequest|loadRequest:|\.loadRequest\(|source=\{\{\s*uri:|uri:\s*['"]https?://|SFSafariViewController\(url:)`)
+	webViewURLPattern  = regexp.MustCompile(`https?://[^\s"'\x60)<>]+`)
+	// Capacitor and Cordova apps that load a remote site instead of their
+	// bundled web assets.
+	capacitorServerURLPattern = regexp.MustCompile(`(?s)server\s*:\s*\{[^}]*url\s*:\s*['"](https?://[^'"]+)`)
+
+	// Offline handling: connectivity checks and web view load failures.
+	webViewOfflinePattern = regexp.MustCompile(`(NWPathMonitor|SCNetworkReachability|Reachability|NetInfo|@react-native-community/netinfo|didFailProvisionalNavigation|didFailNavigation|didFail\s+navigation|NSURLErrorNotConnectedToInternet|\.notConnectedToInternet|onError=|renderError=|onHttpError=|@capacitor/network)`)
+	// Native navigation around or beside the web content.
+	webViewNavPattern = regexp.MustCompile(`(UITabBarController|UINavigationController|TabView\b|NavigationStack|NavigationView|NavigationSplitView|createBottomTabNavigator|createNativeStackNavigator|createStackNavigator|createDrawerNavigator|expo-router|<Tabs\b)`)
+
+	// webViewNativeFeatures are capabilities a website can't offer, which
+	// reviewers look for in apps built around web content.
+	webViewNativeFeatures = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"push notifications", regexp.MustCompile(`(UNUserNotificationCenter|registerForRemoteNotifications|expo-notifications|@react-native-firebase/messaging|@capacitor/push-notifications|PushNotificationIOS)`)},
+		{"location", regexp.MustCompile(`(CLLocationManager|expo-location|react-native-geolocation|@capacitor/geolocation)`)},
+		{"camera", regexp.MustCompile(`(AVCaptureSession|UIImagePickerController|PHPickerViewController|expo-camera|expo-image-picker|react-native-vision-camera|@capacitor/camera)`)},
+		{"in-app purchase", regexp.MustCompile(`(import\s+StoreKit|SKPaymentQueue|Product\.purchase|react-native-iap|react-native-purchases|expo-in-app-purchases|RevenueCat)`)},
+		{"biometrics", regexp.MustCompile(`(LAContext|expo-local-authentication|react-native-biometrics)`)},
+		{"widgets", regexp.MustCompile(`(import\s+WidgetKit|WidgetConfiguration|ActivityKit)`)},
+		{"maps", regexp.MustCompile(`(MKMapView|import\s+MapKit|react-native-maps)`)},
+		{"local storage", regexp.MustCompile(`(NSPersistentContainer|import\s+SwiftData|@Model\b|import\s+CoreData|expo-sqlite|AsyncStorage|react-native-mmkv|WatermelonDB|RealmSwift)`)},
+		{"Sign in with Apple", regexp.MustCompile(`(ASAuthorizationAppleIDProvider|expo-apple-authentication|@invertase/react-native-apple-authentication)`)},
+		{"sharing", regexp.MustCompile(`(UIActivityViewController|ShareLink|Share\.share\(|expo-sharing)`)},
+		{"haptics", regexp.MustCompile(`(UIImpactFeedbackGenerator|UINotificationFeedbackGenerator|expo-haptics|@capacitor/haptics)`)},
+	}
+)
+
+// webViewThinApp is the number of lines of app code under which an app
+// built around a web view is considered a thin wrapper.
+const webViewThinApp = 600
+
+// WebViewWrapperRule flags apps that are essentially a web view loading a
+// remote site, which guideline 4.2 rejects as minimum functionality. It
+// weighs how much app code there is, the native features beyond the web
+// content, offline handling and native navigation, and reports what is
+// missing.
+type WebViewWrapperRule struct {
+	id string
+}
+
+func (r *WebViewWrapperRule) Applies(fc FileContext) bool {
+	switch fc.Language {
+	case "swift", "objc", "typescript", "javascript":
+		return !isToolingConfig(fc.RelPath)
+	}
+	return false
+}
+
+func (r *WebViewWrapperRule) Check(fc FileContext) []Finding { return nil }
+
+// webViewEvidence is what one file contributes to WebViewWrapperRule: its
+// first remote page load, how many lines of code it has, and the offline
+// handling, navigation and native features it shows. Every code file has
+// some, as the line count decides whether the app is thin.
+type webViewEvidence struct {
+	load       *Finding
+	site       string
+	codeLines  int
+	offline    bool
+	navigation bool
+	features   []string
+}
+
+func (r *WebViewWrapperRule) Collect(fc FileContext) any {
+	ev := &webViewEvidence{}
+	if m := capacitorServerURLPattern.FindStringSubmatch(strings.Join(fc.Lines, "\n")); m != nil {
+		ev.load = &Finding{File: fc.RelPath, Line: lineOf(fc.Lines, m[1]), Code: strings.TrimSpace(fc.Lines[lineOf(fc.Lines, m[1])-1])}
+		ev.site = m[1]
+	}
+
+	hasWebView := false
+	for _, line := range fc.Lines {
+		if webViewPattern.MatchString(line) {
+			hasWebView = true
+			break
+		}
+	}
+
+	features := map[string]bool{}
+	for lineNum, line := range fc.Lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		ev.codeLines++
+		if hasWebView && ev.load == nil && webViewLoadPattern.MatchString(line) {
+			ev.load = &Finding{File: fc.RelPath, Line: lineNum + 1, Code: trimmed}
+			ev.site = webViewURLPattern.FindString(line)
+		}
+		if webViewOfflinePattern.MatchString(line) {
+			ev.offline = true
+		}
+		if webViewNavPattern.MatchString(line) {
+			ev.navigation = true
+		}
+		for _, f := range webViewNativeFeatures {
+			if !features[f.name] && f.pattern.MatchString(line) {
+				features[f.name] = true
+				ev.features = append(ev.features, f.name)
+			}
+		}
+	}
+	if ev.codeLines == 0 {
+		return nil
+	}
+	return ev
+}
+
+func (r *WebViewWrapperRule) CheckProject(evidence []any) []Finding {
+	var (
+		load       *Finding
+		site       string
+		codeLines  int
+		offline    bool
+		navigation bool
+		features   = map[string]bool{}
+	)
+
+	for _, ev := range evidence {
+		ev := ev.(*webViewEvidence)
+		if load == nil && ev.load != nil {
+			load, site = ev.load, ev.site
+		}
+		codeLines += ev.codeLines
+		offline = offline || ev.offline
+		navigation = navigation || ev.navigation
+		for _, name := range ev.features {
+			features[name] = true
+		}
+	}
+
+	if load == nil {
+		return nil
+	}
+	thin := codeLines < webViewThinApp
+	if !thin && len(features) > 0 {
+		return nil
+	}
+
+	what := "a remote site"
+	if site != "" {
+		what = site
+	}
+	var gaps []string
+	if thin {
+		gaps = append(gaps, fmt.Sprintf("only %d lines of app code", codeLines))
+	}
+	if len(features) == 0 {
+		gaps = append(gaps, "no native features (push notifications, offline storage, camera, in-app purchase, widgets…)")
+	} else {
+		names := make([]string, 0, len(features))
+		for name := range features {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		gaps = append(gaps, "few native features ("+strings.Join(names, ", ")+")")
+	}
+	if !offline {
+		gaps = append(gaps, "no offline handling, so a reviewer on a poor connection sees a blank or error page")
+	}
+	if !navigation {
+		gaps = append(gaps, "no native navigation (tab bar or navigation stack)")
+	}
+
+	sev := SeverityWarn
+	if thin && len(features) == 0 && !offline && !navigation {
+		sev = SeverityCritical
+	}
+	return []Finding{{
+		RuleID:    r.id,
+		Severity:  sev,
+		Guideline: "4.2",
+		Title:     "App is a thin WebView wrapper",
+		Detail:    "The app loads " + what + " in a web view with " + strings.Join(gaps, "; ") + ". Apps that repackage a website are rejected under 4.2 Minimum Functionality at a very high rate.",
+		Fix:       "Add functionality a website can't offer: native screens and navigation, push notifications, offline content with a retry screen when the network is down, and device features. Load only the parts of the site that need to stay web content.",
+		File:      load.File,
+		Line:      load.Line,
+		Code:      load.Code,
+	}}
+}
+
+// isToolingConfig reports whether relPath is build tooling config rather
+// than app code (babel.config.js, metro.config.js, jest.config.ts...).
+// capacitor.config.ts stays, as it can point the app at a remote site.
+func isToolingConfig(relPath string) bool {
+	base := strings.ToLower(filepath.Base(relPath))
+	return strings.Contains(base, ".config.") && !strings.HasPrefix(base, "capacitor.config.")
+}
+
+// lineOf returns the 1-indexed line of the first line containing s, or 1.
+func lineOf(lines []string, s string) int {
+	for i, line := range lines {
+		if strings.Contains(line, s) {
+			return i + 1
+		}
+	}
+	return 1
+}
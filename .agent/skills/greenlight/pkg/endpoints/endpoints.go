@@ -0,0 +1,377 @@
+// Package endpoints finds the API base URLs an app talks to, in its env
+// files, xcconfigs and code, and probes them from this machine: a backend
+// that is down, resolves to a private network or fails TLS during review
+// leaves the reviewer with a broken app, a classic 2.1 rejection.
+package endpoints
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Endpoint is an API base URL and where the project sets it.
+type Endpoint struct {
+	URL string
+	// File is relative to the project, and Line the line there.
+	File string
+	Line int
+}
+
+const (
+	// maxEndpoints bounds how many base URLs one run probes.
+	maxEndpoints = 10
+	// slowResponse is the response time above which a backend is reported
+	// as slow; App Review runs on ordinary Wi-Fi and gives up quickly.
+	slowResponse = 5 * time.Second
+	// certExpiry is how soon before its expiry a certificate is reported.
+	certExpiry = 30 * 24 * time.Hour
+)
+
+var (
+	// envKeyRe matches env and xcconfig keys that hold a backend address.
+	envKeyRe  = regexp.MustCompile(`(?i)(API|BASE_?URL|BACKEND|SERVER|ENDPOINT|GRAPHQL|HOST)`)
+	envLineRe = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*["']?(https?:/(?:\$\(\))?/[^"'\s#]+)`)
+	// codeURLRe matches a base URL assigned to an API-ish name in Swift,
+	// Objective-C, TypeScript or JavaScript: apiBaseURL = "https://…",
+	// baseURL: 'https://…', let server = URL(string: "https://…").
+	codeURLRe = regexp.MustCompile(`(?i)\b\w*(api|base_?url|backend|server|graphql|endpoint)\w*\s*[:=]\s*(?:URL\(string:\s*|@)?["'\x60](https?://[^"'\x60\s$]+)["'\x60]`)
+)
+
+// skipDirs hold dependencies and build output rather than app code.
+var skipDirs = map[string]bool{
+	"node_modules": true, ".git": true, "Pods": true,
+	"build": true, "dist": true, ".expo": true,
+	"DerivedData": true, "vendor": true, "Carthage": true,
+}
+
+// Extract lists the API base URLs set under root, env files first, each
+// once and at most maxEndpoints. Development, test and local env files and
+// xcconfigs are left out, as are loopback, private and documentation hosts.
+func Extract(root string, paths scan.Paths) []Endpoint {
+	var env, code []Endpoint
+	seen := map[string]bool{}
+	add := func(list *[]Endpoint, raw, path string, line int) {
+		u, ok := baseURL(raw)
+		if !ok || seen[u] {
+			return
+		}
+		seen[u] = true
+		rel, _ := filepath.Rel(root, path)
+		*list = append(*list, Endpoint{URL: u, File: filepath.ToSlash(rel), Line: line})
+	}
+
+	sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if info.IsDir() {
+			if skipDirs[info.Name()] || paths.SkipDir(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !paths.Includes(rel) || nonRelease(rel) {
+			return nil
+		}
+		name := info.Name()
+		switch {
+		case name == ".env" || strings.HasPrefix(name, ".env.") || strings.HasSuffix(name, ".xcconfig"):
+			eachLine(path, func(n int, line string) {
+				if m := envLineRe.FindStringSubmatch(line); m != nil && envKeyRe.MatchString(m[1]) {
+					add(&env, m[2], path, n)
+				}
+			})
+		case isCode(name):
+			eachLine(path, func(n int, line string) {
+				trimmed := strings.TrimSpace(line)
+				if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") {
+					return
+				}
+				for _, m := range codeURLRe.FindAllStringSubmatch(line, -1) {
+					add(&code, m[2], path, n)
+				}
+			})
+		}
+		return nil
+	})
+
+	all := append(env, code...)
+	if len(all) > maxEndpoints {
+		all = all[:maxEndpoints]
+	}
+	return all
+}
+
+// nonRelease reports whether rel is a development, test or sample file
+// whose URLs the reviewed build doesn't use.
+func nonRelease(rel string) bool {
+	lower := strings.ToLower(filepath.ToSlash(rel))
+	for _, seg := range strings.Split(lower, "/") {
+		if seg == "test" || seg == "tests" || seg == "__tests__" || seg == "__mocks__" || seg == "e2e" || strings.HasSuffix(seg, "tests") || strings.HasPrefix(seg, "example") {
+			return true
+		}
+	}
+	base := filepath.Base(lower)
+	if strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") {
+		return true
+	}
+	if base == ".env" || strings.HasPrefix(base, ".env.") || strings.HasSuffix(base, ".xcconfig") {
+		for _, w := range []string{"dev", "debug", "test", "local", "staging", "example", "sample", "template"} {
+			if strings.Contains(base, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isCode(name string) bool {
+	switch filepath.Ext(name) {
+	case ".swift", ".m", ".mm", ".ts", ".tsx", ".js", ".jsx":
+		return !strings.HasSuffix(name, ".min.js") && !strings.HasSuffix(name, ".d.ts")
+	}
+	return false
+}
+
+// eachLine calls fn with each 1-indexed line of the file at path.
+func eachLine(path string, fn func(n int, line string)) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 64*1024), 1<<20)
+	for n := 1; s.Scan(); n++ {
+		fn(n, s.Text())
+	}
+}
+
+// baseURL normalizes raw, an absolute URL from the project, to the base
+// URL to probe, without query, fragment or trailing slash. It reports
+// false for hosts the public can't or shouldn't reach.
+func baseURL(raw string) (string, bool) {
+	// xcconfig files escape "//" as "/$()/".
+	raw = strings.ReplaceAll(raw, "$()", "")
+	u, err := url.Parse(strings.TrimRight(raw, ".,;)"))
+	if err != nil || u.Host == "" || strings.ContainsAny(u.Host, "${}") {
+		return "", false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") || !strings.Contains(host, ".") && net.ParseIP(host) == nil {
+		return "", false
+	}
+	for _, doc := range []string{"example.com", "example.org", "example.net"} {
+		if host == doc || strings.HasSuffix(host, "."+doc) {
+			return "", false
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return "", false
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return strings.TrimSuffix(u.String(), "/"), true
+}
+
+func publicIP(ip net.IP) bool {
+	return !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsUnspecified()
+}
+
+// Probe requests each endpoint and reports the ones App Review couldn't
+// use: hosts that don't resolve or resolve only to private addresses,
+// connections that fail or time out, rejected TLS certificates, server
+// errors, slow responses and certificates about to expire.
+// With no endpoints, or no internet connection here, it reports that
+// nothing was probed instead.
+func Probe(ctx context.Context, client *http.Client, endpoints []Endpoint) []scan.Finding {
+	if len(endpoints) == 0 {
+		return []scan.Finding{{
+			Source:   "endpoints",
+			Severity: severity.Info,
+			Title:    "No API base URL found to probe",
+			Detail:   "No env file, xcconfig or code assigns a backend URL to an API, base URL, server or endpoint name, so the backend was not checked.",
+		}}
+	}
+	if !online(ctx) {
+		return []scan.Finding{{
+			Source:   "endpoints",
+			Severity: severity.Info,
+			Title:    "API endpoints not probed: no internet connection",
+			Detail:   fmt.Sprintf("%s could not be resolved from this machine, so the %d API base URLs found were not checked.", onlineHost, len(endpoints)),
+			Fix:      "Run the probe from a machine with internet access.",
+		}}
+	}
+	results := make([][]scan.Finding, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = probe(ctx, client, e)
+		}()
+	}
+	wg.Wait()
+	var findings []scan.Finding
+	for _, r := range results {
+		findings = append(findings, r...)
+	}
+	return findings
+}
+
+// onlineHost is resolved to tell an unreachable backend from a machine
+// without an internet connection.
+const onlineHost = "apple.com"
+
+func online(ctx context.Context) bool {
+	_, err := net.DefaultResolver.LookupIPAddr(ctx, onlineHost)
+	return err == nil
+}
+
+func probe(ctx context.Context, client *http.Client, e Endpoint) []scan.Finding {
+	finding := func(sev severity.Level, title, detail, fix string) []scan.Finding {
+		return []scan.Finding{{
+			Source:    "endpoints",
+			Severity:  sev,
+			Guideline: "2.1",
+			Title:     title,
+			Detail:    detail,
+			Fix:       fix,
+			File:      e.File,
+			Line:      e.Line,
+			Code:      e.URL,
+		}}
+	}
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return nil
+	}
+	host := u.Hostname()
+
+	if net.ParseIP(host) == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return finding(severity.Critical,
+				fmt.Sprintf("API host %q does not resolve", host),
+				fmt.Sprintf("The app's backend at %s has no DNS record (%v). Every request the app makes fails during review.", e.URL, unwrapDNS(err)),
+				"Point the release build at the production backend, or publish the DNS record before submitting.")
+		}
+		public := false
+		for _, a := range addrs {
+			if publicIP(a.IP) {
+				public = true
+			}
+		}
+		if !public {
+			return finding(severity.Critical,
+				fmt.Sprintf("API host %q resolves only to private addresses", host),
+				fmt.Sprintf("%s resolves to %s, which is reachable only inside your network or VPN. App Review tests from the public internet, so the app can't reach its backend.", host, addrs[0].IP),
+				"Expose the backend publicly, or point the release build at the production server.")
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.URL, nil)
+	if err != nil {
+		return nil
+	}
+	req.Header.Set("User-Agent", "greenlight-endpoint-probe")
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if reason, ok := certProblem(err); ok {
+			return finding(severity.Critical,
+				fmt.Sprintf("TLS certificate for %q is rejected", host),
+				fmt.Sprintf("%s fails certificate validation: %s. iOS refuses the connection under App Transport Security, so the app can't reach its backend.", e.URL, reason),
+				"Install a valid certificate for the host from a public certificate authority, including the intermediate certificates.")
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return finding(severity.Critical,
+				fmt.Sprintf("API server %q did not respond", host),
+				fmt.Sprintf("%s did not respond within %s. \"Server was down during review\" is one of the most common 2.1 rejections.", e.URL, client.Timeout),
+				"Make sure the backend is up and reachable from the public internet for the whole review, and monitor it while the app is in review.")
+		}
+		return finding(severity.Critical,
+			fmt.Sprintf("API server %q is unreachable", host),
+			fmt.Sprintf("Requesting %s failed: %v. \"Server was down during review\" is one of the most common 2.1 rejections.", e.URL, errors.Unwrap(err)),
+			"Make sure the backend is up and reachable from the public internet for the whole review, and monitor it while the app is in review.")
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	var findings []scan.Finding
+	if resp.StatusCode >= 500 {
+		findings = append(findings, finding(severity.Warn,
+			fmt.Sprintf("API server %q returns HTTP %d", host, resp.StatusCode),
+			fmt.Sprintf("%s answered %s. A backend returning server errors while the app is in review gets it rejected for crashes or empty screens.", e.URL, resp.Status),
+			"Check the server logs and health checks; the base URL of a healthy API usually answers 200, 401 or 404.")...)
+	}
+	if elapsed > slowResponse {
+		findings = append(findings, finding(severity.Warn,
+			fmt.Sprintf("API server %q is slow to respond", host),
+			fmt.Sprintf("%s took %s to answer. Reviewers give up on apps that sit on a spinner, and launch requests this slow risk the launch watchdog.", e.URL, elapsed.Round(100*time.Millisecond)),
+			"Speed up the backend or put a CDN in front of it, and show content from a cache while requests load.")...)
+	}
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		if expiry := resp.TLS.PeerCertificates[0].NotAfter; time.Until(expiry) < certExpiry {
+			findings = append(findings, finding(severity.Warn,
+				fmt.Sprintf("TLS certificate for %q expires soon", host),
+				fmt.Sprintf("The certificate of %s expires on %s. Once it does, iOS refuses every connection to the backend, possibly while the app is in review.", e.URL, expiry.Format("2006-01-02")),
+				"Renew the certificate, and automate renewal (for example with ACME).")...)
+		}
+	}
+	return findings
+}
+
+// certProblem describes why err is a certificate validation failure.
+func certProblem(err error) (string, bool) {
+	var invalid x509.CertificateInvalidError
+	var unknown x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var verify *tls.CertificateVerificationError
+	switch {
+	case errors.As(err, &invalid):
+		return invalid.Error(), true
+	case errors.As(err, &unknown):
+		return "it is signed by an unknown authority (self-signed or missing intermediates)", true
+	case errors.As(err, &hostname):
+		return hostname.Error(), true
+	case errors.As(err, &verify):
+		return verify.Err.Error(), true
+	}
+	return "", false
+}
+
+// unwrapDNS is the resolver's own message, without the lookup prefix.
+func unwrapDNS(err error) string {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.Err
+	}
+	return err.Error()
+}
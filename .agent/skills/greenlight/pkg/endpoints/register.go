@@ -0,0 +1,27 @@
+package endpoints
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() { scan.Register(scanner{}) }
+
+// scanner probes the app's backend. It only runs when the target enables
+// endpoint probing, as it sends requests to the app's servers.
+type scanner struct{}
+
+func (scanner) Name() string { return "endpoints" }
+
+func (scanner) Applies(t scan.Target) bool { return t.ProbeEndpoints }
+
+func (s scanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	if !s.Applies(t) {
+		return nil, nil
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	return Probe(ctx, client, Extract(t.ProjectPath, t.Paths)), nil
+}
@@ -0,0 +1,172 @@
+package ipa
+
+import (
+	"debug/macho"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+const loadCmdUUID = 0x1b // LC_UUID
+
+// IsArchive reports whether path is an Xcode archive (.xcarchive directory).
+func IsArchive(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir() && strings.HasSuffix(strings.TrimSuffix(path, "/"), ".xcarchive")
+}
+
+// InspectArchive checks an .xcarchive for crash-symbolication readiness: the
+// app and every embedded framework need a dSYM whose UUIDs match the shipped
+// binary, and no binary may still carry bitcode.
+func InspectArchive(archivePath string) (*InspectResult, error) {
+	apps, _ := filepath.Glob(filepath.Join(archivePath, "Products", "Applications", "*.app"))
+	if len(apps) == 0 {
+		return nil, fmt.Errorf("no .app found in %s/Products/Applications", archivePath)
+	}
+	appPath := apps[0]
+
+	result := &InspectResult{
+		IPAPath: archivePath,
+		AppName: strings.TrimSuffix(filepath.Base(appPath), ".app"),
+	}
+	filepath.Walk(appPath, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			result.Size += info.Size()
+		}
+		return nil
+	})
+
+	dsymDir := filepath.Join(archivePath, "dSYMs")
+	dsyms, _ := filepath.Glob(filepath.Join(dsymDir, "*.dSYM"))
+	if len(dsyms) == 0 {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "Archive contains no dSYMs",
+			Detail:    "Crash logs from App Review and TestFlight cannot be symbolicated without dSYMs, which makes it hard to answer 2.1 crash rejections and performance follow-ups.",
+			Fix:       "Set DEBUG_INFORMATION_FORMAT = dwarf-with-dsym for Release and archive again.",
+		})
+		return result, nil
+	}
+
+	// The app executable, then embedded frameworks.
+	bundles := []string{appPath}
+	frameworks, _ := filepath.Glob(filepath.Join(appPath, "Frameworks", "*.framework"))
+	sort.Strings(frameworks)
+	bundles = append(bundles, frameworks...)
+
+	for _, bundle := range bundles {
+		name := filepath.Base(bundle)
+		exe := filepath.Join(bundle, strings.TrimSuffix(name, filepath.Ext(name)))
+		binUUIDs, bitcode, err := machoInfo(exe)
+		if err != nil {
+			continue
+		}
+
+		if bitcode {
+			result.Findings = append(result.Findings, Finding{
+				Severity:  severity.Warn,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("%s still contains bitcode", name),
+				Detail:    "App Store Connect no longer accepts bitcode (ITMS-90482), and a binary stripped after archiving no longer matches the vendor's dSYM.",
+				Fix:       "Use a framework build without bitcode, or strip it with `xcrun bitcode_strip -r` in a build phase before the dSYM is generated.",
+			})
+		}
+
+		dsym := filepath.Join(dsymDir, name+".dSYM")
+		dwarf := filepath.Join(dsym, "Contents", "Resources", "DWARF", strings.TrimSuffix(name, filepath.Ext(name)))
+		if _, err := os.Stat(dsym); err != nil {
+			result.Findings = append(result.Findings, Finding{
+				Severity:  severity.Warn,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("No dSYM for %s", name),
+				Detail:    fmt.Sprintf("The archive has no %s.dSYM, so crashes in %s will show as unsymbolicated addresses.", name, name),
+				Fix:       dsymFix(bundle == appPath, name),
+			})
+			continue
+		}
+		dsymUUIDs, _, err := machoInfo(dwarf)
+		if err != nil {
+			continue
+		}
+		if missing := missingUUIDs(binUUIDs, dsymUUIDs); len(missing) > 0 {
+			result.Findings = append(result.Findings, Finding{
+				Severity:  severity.Warn,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("dSYM for %s does not match the binary", name),
+				Detail:    fmt.Sprintf("The shipped binary has UUID %s, which the dSYM does not contain. The dSYM is from a different build (common when a prebuilt framework is rebuilt or has its bitcode stripped).", strings.Join(missing, ", ")),
+				Fix:       dsymFix(bundle == appPath, name),
+			})
+		}
+	}
+
+	SortFindings(result.Findings)
+	return result, nil
+}
+
+func dsymFix(app bool, name string) string {
+	if app {
+		return "Set DEBUG_INFORMATION_FORMAT = dwarf-with-dsym for Release and archive again."
+	}
+	return "Use the dSYM shipped with " + name + " (or build it from source with dwarf-with-dsym) and copy it into the archive's dSYMs folder."
+}
+
+// machoInfo returns the LC_UUIDs of every slice of a Mach-O file and whether
+// any slice embeds bitcode (an __LLVM segment).
+func machoInfo(path string) ([]string, bool, error) {
+	var files []*macho.File
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer fat.Close()
+		for _, arch := range fat.Arches {
+			files = append(files, arch.File)
+		}
+	} else {
+		f, err := macho.Open(path)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+
+	var uuids []string
+	bitcode := false
+	for _, f := range files {
+		if f.Segment("__LLVM") != nil {
+			bitcode = true
+		}
+		for _, l := range f.Loads {
+			raw := l.Raw()
+			if len(raw) < 24 || f.ByteOrder.Uint32(raw) != loadCmdUUID {
+				continue
+			}
+			uuids = append(uuids, formatUUID(raw[8:24]))
+		}
+	}
+	return uuids, bitcode, nil
+}
+
+func formatUUID(b []byte) string {
+	return fmt.Sprintf("%X-%X-%X-%X-%X", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func missingUUIDs(want, have []string) []string {
+	var missing []string
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(w, h) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, w)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,155 @@
+package ipa
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Read limits for archive entries loaded into memory.
+const (
+	maxPlistRead  = 16 << 20
+	maxBundleRead = 512 << 20
+)
+
+// bundleIndex is what Inspect needs from the app bundle's entries, gathered
+// in one pass over the central directory without keeping every entry.
+type bundleIndex struct {
+	appName string
+	entries int
+
+	infoPlist   *zip.File
+	privacyInfo *zip.File
+	hasLaunchSB bool
+	hasAppIcon  bool
+	iconCount   int
+
+	// frameworks in archive order, and the framework directories that carry
+	// their own PrivacyInfo.xcprivacy.
+	frameworks []string
+	seen       map[string]bool
+	manifests  map[string]bool
+
+	bundles []bundleEntry
+
+	// envFiles are .env files copied into the bundle.
+	envFiles []string
+
+	payload      map[string]*PayloadItem
+	payloadTotal int64
+}
+
+type bundleEntry struct {
+	rel  string
+	file *zip.File
+}
+
+func newBundleIndex(appName string) *bundleIndex {
+	return &bundleIndex{
+		appName:   appName,
+		seen:      make(map[string]bool),
+		manifests: make(map[string]bool),
+		payload:   make(map[string]*PayloadItem),
+	}
+}
+
+// add records one entry; rel is its path inside the .app directory.
+func (idx *bundleIndex) add(rel string, f *zip.File) {
+	idx.entries++
+
+	if dir, ok := strings.CutSuffix(rel, "/PrivacyInfo.xcprivacy"); ok {
+		idx.manifests[dir] = true
+		idx.manifests[strings.TrimPrefix(dir, "Frameworks/")] = true
+	}
+
+	switch {
+	case rel == "Info.plist":
+		idx.infoPlist = f
+	case rel == "PrivacyInfo.xcprivacy":
+		idx.privacyInfo = f
+	case strings.Contains(rel, "LaunchScreen") || strings.Contains(rel, "LaunchStoryboard"):
+		idx.hasLaunchSB = true
+	case strings.HasPrefix(rel, "AppIcon") || strings.Contains(rel, "AppIcon"):
+		idx.hasAppIcon = true
+		if strings.HasSuffix(rel, ".png") {
+			idx.iconCount++
+		}
+	case strings.Contains(rel, ".framework/"):
+		fw := strings.SplitN(rel, ".framework/", 2)[0] + ".framework"
+		if !idx.seen[fw] {
+			idx.seen[fw] = true
+			idx.frameworks = append(idx.frameworks, fw)
+		}
+	}
+
+	if sourcefile.IsEnvFile(path.Base(rel)) {
+		idx.envFiles = append(idx.envFiles, rel)
+	}
+
+	if codescan.IsJSBundle(rel) {
+		idx.bundles = append(idx.bundles, bundleEntry{rel: rel, file: f})
+	}
+
+	if strings.HasSuffix(rel, "/") {
+		return
+	}
+	key, kind := rel, "resources"
+	switch {
+	case strings.HasPrefix(rel, "Frameworks/"):
+		key, kind = strings.SplitN(strings.TrimPrefix(rel, "Frameworks/"), "/", 2)[0], "framework"
+	case strings.HasPrefix(rel, "PlugIns/"):
+		key, kind = strings.SplitN(strings.TrimPrefix(rel, "PlugIns/"), "/", 2)[0], "extension"
+	case rel == idx.appName:
+		kind = "executable"
+	case strings.HasSuffix(rel, ".car"):
+		kind = "assets"
+	case codescan.IsJSBundle(rel):
+		kind = "jsbundle"
+	default:
+		key = "Other resources"
+	}
+	item, ok := idx.payload[key]
+	if !ok {
+		item = &PayloadItem{Name: key, Kind: kind}
+		idx.payload[key] = item
+	}
+	item.Bytes += int64(f.UncompressedSize64)
+	idx.payloadTotal += int64(f.UncompressedSize64)
+}
+
+// readEntry reads a whole archive entry. Unlike a single Read it never
+// returns a short buffer: a truncated or corrupt entry, a checksum mismatch
+// or an entry over limit bytes is an error.
+func readEntry(f *zip.File, limit int64) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("entry is larger than %d MB", limit>>20)
+	}
+	return data, nil
+}
+
+// unreadable records that an entry could not be read, so checks depending
+// on it were skipped rather than run on partial data.
+func (r *InspectResult) unreadable(rel string, err error) {
+	r.Findings = append(r.Findings, Finding{
+		Severity: severity.Warn,
+		Title:    fmt.Sprintf("Could not read %s from the IPA", rel),
+		Detail:   fmt.Sprintf("%s: %v. Checks on this file were skipped, so the report is incomplete.", rel, err),
+		Fix:      "Re-export the IPA. A corrupt or truncated archive will also fail App Store Connect upload validation.",
+	})
+}
@@ -0,0 +1,509 @@
+// Package ipa inspects built .ipa files (and .xcarchive bundles) for issues
+// App Store Connect rejects at upload or review: Info.plist gaps, privacy
+// manifests, icons, size and symbolication.
+//
+//	result, err := ipa.Inspect(ctx, "build/MyApp.ipa")
+package ipa
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/internal/findingorder"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Finding from IPA inspection.
+type Finding struct {
+	Severity  severity.Level `json:"severity"`
+	Guideline string `json:"guideline,omitempty"`
+	Title     string `json:"title"`
+	Detail    string `json:"detail"`
+	Fix       string `json:"fix,omitempty"`
+	// GuidelineTitle and GuidelineURL resolve Guideline for reports.
+	GuidelineTitle string `json:"guideline_title,omitempty"`
+	GuidelineURL   string `json:"guideline_url,omitempty"`
+	// RuleID names the kind of finding; Fingerprint identifies this
+	// occurrence across runs.
+	RuleID      string `json:"rule_id,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Risk is the estimated likelihood, from 0 to 100, that the finding
+	// gets the app rejected (see internal/risk).
+	Risk int `json:"risk,omitempty"`
+	// Effort and Owner suggest the size of the fix and the team it belongs
+	// to (see internal/triage).
+	Effort string `json:"effort,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+}
+
+// ScanFinding converts f to scan.Finding, with "ipa" as its source.
+func (f Finding) ScanFinding() scan.Finding {
+	return scan.Finding{
+		Source:         "ipa",
+		RuleID:         f.RuleID,
+		Severity:       f.Severity,
+		Guideline:      f.Guideline,
+		GuidelineTitle: f.GuidelineTitle,
+		GuidelineURL:   f.GuidelineURL,
+		Title:          f.Title,
+		Detail:         f.Detail,
+		Fix:            f.Fix,
+		Fingerprint:    f.Fingerprint,
+		Risk:           f.Risk,
+		Effort:         f.Effort,
+		Owner:          f.Owner,
+	}
+}
+
+// InspectResult holds the full IPA inspection output.
+type InspectResult struct {
+	IPAPath  string `json:"ipa_path"`
+	AppName  string `json:"app_name"`
+	BundleID string `json:"bundle_id,omitempty"`
+	Size     int64  `json:"size_bytes"`
+	// Version and BuildNumber are read from XML Info.plists only.
+	Version     string `json:"version,omitempty"`
+	BuildNumber string `json:"build_number,omitempty"`
+	// Payload is the uncompressed app bundle broken down by component,
+	// largest first.
+	Payload  []PayloadItem `json:"payload,omitempty"`
+	Findings []Finding     `json:"findings"`
+}
+
+// PayloadItem is one component of the app bundle and its uncompressed size.
+type PayloadItem struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"` // executable, framework, extension, assets, jsbundle, resources
+	Bytes int64  `json:"bytes"`
+}
+
+// Inspect analyzes an IPA file for App Store compliance issues. An
+// .xcarchive is checked for symbolication readiness instead. Cancelling ctx
+// abandons the inspection with ctx.Err().
+func Inspect(ctx context.Context, ipaPath string) (*InspectResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if IsArchive(ipaPath) {
+		return InspectArchive(ipaPath)
+	}
+	start := time.Now()
+
+	info, err := os.Stat(ipaPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access IPA: %w", err)
+	}
+
+	result := &InspectResult{
+		IPAPath: ipaPath,
+		Size:    info.Size(),
+	}
+
+	// zip.OpenReader reads only the central directory (ZIP64 included, so
+	// IPAs over 4 GB work); entries are indexed in one pass and just the few
+	// the checks read are opened later.
+	r, err := zip.OpenReader(ipaPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open IPA (not a valid zip): %w", err)
+	}
+	defer r.Close()
+
+	appDir := ""
+	for _, f := range r.File {
+		// Find the .app directory
+		parts := strings.SplitN(f.Name, "/", 3)
+		if len(parts) >= 2 && strings.HasSuffix(parts[1], ".app") {
+			appDir = parts[0] + "/" + parts[1] + "/"
+			result.AppName = strings.TrimSuffix(parts[1], ".app")
+			break
+		}
+	}
+
+	if appDir == "" {
+		result.Findings = append(result.Findings, Finding{
+			Severity: severity.Critical,
+			Title:    "Invalid IPA structure",
+			Detail:   "No .app bundle found inside the IPA.",
+			Fix:      "Ensure you're inspecting a valid IPA built for distribution.",
+		})
+		return result, nil
+	}
+
+	idx := newBundleIndex(result.AppName)
+	for i, f := range r.File {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if strings.HasPrefix(f.Name, appDir) {
+			idx.add(strings.TrimPrefix(f.Name, appDir), f)
+		}
+	}
+
+	// --- Run checks ---
+
+	// 1. Info.plist
+	if idx.infoPlist == nil {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Critical,
+			Guideline: "2.1",
+			Title:     "Missing Info.plist",
+			Detail:    "The app bundle does not contain an Info.plist file.",
+			Fix:       "This indicates a broken build. Rebuild your app.",
+		})
+	} else {
+		// Parse Info.plist and check contents
+		result.checkInfoPlist(idx.infoPlist)
+	}
+
+	// 2. PrivacyInfo.xcprivacy (required since Spring 2024)
+	if idx.privacyInfo == nil {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Critical,
+			Guideline: "5.1.1",
+			Title:     "Missing PrivacyInfo.xcprivacy",
+			Detail:    "Privacy manifest is required since May 2024. Apps without it receive ITMS-91061 rejection.",
+			Fix:       "Add a PrivacyInfo.xcprivacy file to your app target. See: developer.apple.com/documentation/bundleresources/privacy-manifest-files",
+		})
+	} else {
+		result.checkPrivacyManifest(idx.privacyInfo)
+	}
+
+	// 3. Launch storyboard
+	if !idx.hasLaunchSB {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "4.2",
+			Title:     "No launch storyboard detected",
+			Detail:    "Apps must use a launch storyboard (not a static launch image) for all device sizes.",
+			Fix:       "Add a LaunchScreen.storyboard to your project.",
+		})
+	}
+
+	// 4. App icon
+	if !idx.hasAppIcon {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Critical,
+			Guideline: "2.3",
+			Title:     "No app icon found in bundle",
+			Detail:    "The IPA does not contain any AppIcon assets.",
+			Fix:       "Add a 1024x1024 app icon to your asset catalog.",
+		})
+	} else if idx.iconCount < 2 {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "2.3",
+			Title:     fmt.Sprintf("Only %d app icon size(s) found", idx.iconCount),
+			Detail:    "Multiple icon sizes are typically required for different devices.",
+			Fix:       "Ensure your asset catalog includes icons for all required sizes.",
+		})
+	}
+
+	// 5. App size
+	sizeMB := float64(result.Size) / (1024 * 1024)
+	if sizeMB > 200 {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "2.4",
+			Title:     fmt.Sprintf("App size is %.0fMB — exceeds cellular download limit", sizeMB),
+			Detail:    "Apps over 200MB cannot be downloaded over cellular data without user confirmation.",
+			Fix:       "Consider using On Demand Resources, app thinning, or reducing asset sizes.",
+		})
+	} else if sizeMB > 150 {
+		result.Findings = append(result.Findings, Finding{
+			Severity: severity.Info,
+			Title:    fmt.Sprintf("App size is %.0fMB — approaching cellular limit", sizeMB),
+			Detail:   "The 200MB cellular download limit may impact conversion rates.",
+		})
+	}
+
+	// 6. Check embedded frameworks for their own privacy manifests
+	for _, fw := range idx.frameworks {
+		if !idx.manifests[fw] {
+			result.Findings = append(result.Findings, Finding{
+				Severity:  severity.Warn,
+				Guideline: "5.1.1",
+				Title:     fmt.Sprintf("Framework '%s' missing privacy manifest", filepath.Base(fw)),
+				Detail:    "Third-party frameworks must include their own PrivacyInfo.xcprivacy.",
+				Fix:       "Update the framework to a version that includes a privacy manifest, or contact the vendor.",
+			})
+		}
+	}
+
+	// 6b. Payload composition and dynamic framework count (cold launch)
+	result.checkPayload(idx.payload, idx.payloadTotal)
+
+	// 6c. Environment files copied into the bundle
+	if len(idx.envFiles) > 0 {
+		result.Findings = append(result.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "1.6",
+			Title:     fmt.Sprintf("Environment file bundled in the app: %s", strings.Join(idx.envFiles, ", ")),
+			Detail:    "Anyone who downloads the app can unzip it and read the file, including the API keys, secrets and internal endpoints it usually holds.",
+			Fix:       "Remove the file from the target's Copy Bundle Resources phase (or the copied web assets), inline only the public values at build time, and rotate any secrets it contained.",
+		})
+	}
+
+	// 7. React Native bundles shipped in the app
+	for _, b := range idx.bundles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result.checkJSBundle(b.file, b.rel)
+	}
+
+	SortFindings(result.Findings)
+	slog.DebugContext(ctx, "ipa inspected", "ipa", ipaPath, "entries", idx.entries, "findings", len(result.Findings), "duration", time.Since(start))
+	return result, nil
+}
+
+// SortFindings puts findings in report order: severity, then title.
+func SortFindings(findings []Finding) {
+	findingorder.Sort(findings, func(f Finding) findingorder.Key {
+		return findingorder.Key{
+			Severity: f.Severity,
+			Title:    f.Title,
+			Detail:   f.Detail,
+		}
+	})
+}
+
+// checkPayload breaks the app bundle down by component and flags launch
+// costs: many dynamic frameworks (each is loaded by dyld before main) and a
+// single component dominating the download.
+func (r *InspectResult) checkPayload(sizes map[string]*PayloadItem, total int64) {
+	dynamic := 0
+	for _, item := range sizes {
+		r.Payload = append(r.Payload, *item)
+		if item.Kind == "framework" && strings.HasSuffix(item.Name, ".framework") {
+			dynamic++
+		}
+	}
+	sort.Slice(r.Payload, func(i, j int) bool {
+		if r.Payload[i].Bytes != r.Payload[j].Bytes {
+			return r.Payload[i].Bytes > r.Payload[j].Bytes
+		}
+		return r.Payload[i].Name < r.Payload[j].Name
+	})
+
+	if dynamic > 12 {
+		r.Findings = append(r.Findings, Finding{
+			Severity:  severity.Info,
+			Guideline: "2.1",
+			Title:     fmt.Sprintf("%d dynamic frameworks embedded", dynamic),
+			Detail:    "dyld loads and binds every embedded dynamic framework before main() runs, so each one adds to cold-launch time. Slow launches on review devices risk 2.1 performance rejections.",
+			Fix:       "Link dependencies statically where possible (CocoaPods use_frameworks! :linkage => :static, SPM static products) or merge frameworks (mergeable libraries).",
+		})
+	}
+
+	if len(r.Payload) > 0 && total >= 100<<20 {
+		top := r.Payload[0]
+		if top.Kind != "executable" && top.Bytes*2 > total {
+			r.Findings = append(r.Findings, Finding{
+				Severity:  severity.Info,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("%s is %.0f%% of the app payload", top.Name, float64(top.Bytes)*100/float64(total)),
+				Detail:    fmt.Sprintf("%s accounts for %.1fMB of %.1fMB uncompressed. Shrinking it has the biggest effect on download size and install time.", top.Name, float64(top.Bytes)/(1024*1024), float64(total)/(1024*1024)),
+				Fix:       "Check whether the component ships unused architectures, debug symbols or assets, and strip or slim it.",
+			})
+		}
+	}
+}
+
+func (r *InspectResult) checkJSBundle(f *zip.File, rel string) {
+	data, err := readEntry(f, maxBundleRead)
+	if err != nil {
+		r.unreadable(rel, err)
+		return
+	}
+	for _, cf := range codescan.ScanBundle(rel, data) {
+		r.Findings = append(r.Findings, Finding{
+			Severity:  cf.Severity,
+			Guideline: cf.Guideline,
+			Title:     cf.Title,
+			Detail:    cf.Detail + " (" + rel + ")",
+			Fix:       cf.Fix,
+		})
+	}
+}
+
+func (r *InspectResult) checkInfoPlist(f *zip.File) {
+	// Read as bytes — Info.plist can be binary or XML
+	buf, err := readEntry(f, maxPlistRead)
+	if err != nil {
+		r.unreadable("Info.plist", err)
+		return
+	}
+	content := string(buf)
+
+	// Check for required keys (works for XML plists; binary plists will have partial matches)
+	requiredKeys := map[string]struct {
+		guideline string
+		title     string
+	}{
+		"CFBundleDisplayName":   {"2.3", "Missing CFBundleDisplayName"},
+		"CFBundleVersion":      {"2.1", "Missing CFBundleVersion (build number)"},
+		"CFBundleShortVersionString": {"2.1", "Missing CFBundleShortVersionString (version)"},
+	}
+
+	for key, info := range requiredKeys {
+		if !strings.Contains(content, key) {
+			r.Findings = append(r.Findings, Finding{
+				Severity:  severity.Warn,
+				Guideline: info.guideline,
+				Title:     info.title,
+				Detail:    fmt.Sprintf("Info.plist should contain %s.", key),
+				Fix:       "Add the missing key to your Info.plist.",
+			})
+		}
+	}
+
+	// Version and build number must be accepted by App Store Connect
+	versionKeys := []struct {
+		key string
+		dst *string
+	}{
+		{"CFBundleShortVersionString", &r.Version},
+		{"CFBundleVersion", &r.BuildNumber},
+	}
+	for _, vk := range versionKeys {
+		key, dst := vk.key, vk.dst
+		m := regexp.MustCompile(key + `</key>\s*<string>([^<]*)</string>`).FindStringSubmatch(content)
+		if m == nil {
+			continue
+		}
+		*dst = strings.TrimSpace(m[1])
+		if problem := appversion.Validate(*dst); problem != "" {
+			r.Findings = append(r.Findings, Finding{
+				Severity:  severity.Critical,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("%s \"%s\" will be rejected at upload", key, *dst),
+				Detail:    "App Store Connect only accepts up to three period-separated integers (e.g. 1.4.2) as the version and build number; " + problem + ".",
+				Fix:       "Change " + key + " to a value like 1.4.2 and rebuild.",
+			})
+		}
+	}
+
+	// Check for NSAppTransportSecurity exceptions
+	if strings.Contains(content, "NSAllowsArbitraryLoads") {
+		if strings.Contains(content, "<true/>") {
+			r.Findings = append(r.Findings, Finding{
+				Severity:  severity.Warn,
+				Guideline: "1.6",
+				Title:     "App Transport Security disabled (NSAllowsArbitraryLoads = true)",
+				Detail:    "Disabling ATS allows insecure HTTP connections. Apple may require justification.",
+				Fix:       "Use HTTPS for all connections instead of disabling ATS globally. Use per-domain exceptions if needed.",
+			})
+		}
+	}
+
+	// Check for purpose strings (they should exist if capabilities are used)
+	purposeStrings := []struct {
+		key  string
+		name string
+	}{
+		{"NSCameraUsageDescription", "Camera"},
+		{"NSMicrophoneUsageDescription", "Microphone"},
+		{"NSPhotoLibraryUsageDescription", "Photo Library"},
+		{"NSLocationWhenInUseUsageDescription", "Location (When In Use)"},
+		{"NSLocationAlwaysUsageDescription", "Location (Always)"},
+		{"NSBluetoothAlwaysUsageDescription", "Bluetooth"},
+		{"NSMotionUsageDescription", "Motion"},
+		{"NSFaceIDUsageDescription", "Face ID"},
+		{"NSUserTrackingUsageDescription", "User Tracking (ATT)"},
+		{"NSHealthShareUsageDescription", "HealthKit"},
+		{"NSContactsUsageDescription", "Contacts"},
+		{"NSCalendarsUsageDescription", "Calendars"},
+		{"NSRemindersUsageDescription", "Reminders"},
+		{"NSSpeechRecognitionUsageDescription", "Speech Recognition"},
+	}
+
+	// Check for empty purpose strings
+	for _, ps := range purposeStrings {
+		if strings.Contains(content, ps.key) {
+			// Check for empty or very short value
+			emptyPattern := regexp.MustCompile(ps.key + `</key>\s*<string>\s*</string>`)
+			shortPattern := regexp.MustCompile(ps.key + `</key>\s*<string>.{1,15}</string>`)
+			if emptyPattern.Match(buf) {
+				r.Findings = append(r.Findings, Finding{
+					Severity:  severity.Critical,
+					Guideline: "5.1.1",
+					Title:     fmt.Sprintf("%s purpose string is empty", ps.name),
+					Detail:    fmt.Sprintf("%s is declared but has no description.", ps.key),
+					Fix:       fmt.Sprintf("Add a specific, user-facing description for why your app needs %s access.", ps.name),
+				})
+			} else if shortPattern.Match(buf) {
+				r.Findings = append(r.Findings, Finding{
+					Severity:  severity.Warn,
+					Guideline: "5.1.1",
+					Title:     fmt.Sprintf("%s purpose string may be too vague", ps.name),
+					Detail:    fmt.Sprintf("%s has a very short description. Apple rejects vague purpose strings.", ps.key),
+					Fix:       "Write a specific description: 'Take photos to attach to support tickets' NOT 'Camera access needed'.",
+				})
+			}
+		}
+	}
+}
+
+func (r *InspectResult) checkPrivacyManifest(f *zip.File) {
+	buf, err := readEntry(f, maxPlistRead)
+	if err != nil {
+		r.unreadable("PrivacyInfo.xcprivacy", err)
+		return
+	}
+	content := string(buf)
+
+	// Check if it's basically empty
+	if len(strings.TrimSpace(content)) < 100 {
+		r.Findings = append(r.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "5.1.1",
+			Title:     "PrivacyInfo.xcprivacy appears to be minimal/empty",
+			Detail:    "The privacy manifest exists but may not declare any API usage or tracking.",
+			Fix:       "Populate the privacy manifest with your app's actual API usage and tracking declarations.",
+		})
+	}
+
+	// Check for NSPrivacyTracking declaration
+	if !strings.Contains(content, "NSPrivacyTracking") {
+		r.Findings = append(r.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "5.1.2",
+			Title:     "Privacy manifest missing NSPrivacyTracking declaration",
+			Detail:    "The privacy manifest should declare whether the app tracks users.",
+			Fix:       "Add NSPrivacyTracking (boolean) to your PrivacyInfo.xcprivacy.",
+		})
+	}
+
+	// Check for NSPrivacyAccessedAPITypes
+	if !strings.Contains(content, "NSPrivacyAccessedAPITypes") {
+		r.Findings = append(r.Findings, Finding{
+			Severity:  severity.Warn,
+			Guideline: "5.1.1",
+			Title:     "Privacy manifest missing NSPrivacyAccessedAPITypes",
+			Detail:    "Required Reason APIs must be declared in the privacy manifest.",
+			Fix:       "Declare all Required Reason API usage in NSPrivacyAccessedAPITypes.",
+		})
+	}
+
+	// Check for NSPrivacyCollectedDataTypes
+	if !strings.Contains(content, "NSPrivacyCollectedDataTypes") {
+		r.Findings = append(r.Findings, Finding{
+			Severity:  severity.Info,
+			Guideline: "5.1.1",
+			Title:     "Privacy manifest does not declare collected data types",
+			Detail:    "NSPrivacyCollectedDataTypes should list what data your app collects.",
+			Fix:       "Declare collected data types to match your App Store privacy nutrition labels.",
+		})
+	}
+}
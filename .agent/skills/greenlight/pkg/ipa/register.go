@@ -0,0 +1,40 @@
+package ipa
+
+import (
+	"context"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() { scan.Register(scanner{}) }
+
+// scanner inspects the target's IPA, if it has one. The app name and bundle
+// ID it reads win over the project's, as they are what actually ships.
+type scanner struct{}
+
+func (scanner) Name() string { return "ipa" }
+
+func (scanner) Applies(t scan.Target) bool { return t.IPAPath != "" }
+
+func (s scanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	if !s.Applies(t) {
+		return nil, nil
+	}
+	result, err := Inspect(ctx, t.IPAPath)
+	if err != nil {
+		return nil, err
+	}
+	t.Facts.Update(func(facts *scan.Facts) {
+		if result.AppName != "" {
+			facts.AppName = result.AppName
+		}
+		if result.BundleID != "" {
+			facts.BundleID = result.BundleID
+		}
+	})
+	out := make([]scan.Finding, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		out = append(out, f.ScanFinding())
+	}
+	return out, nil
+}
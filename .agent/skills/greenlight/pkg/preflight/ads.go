@@ -0,0 +1,191 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/adnetworks"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+var (
+	// gadAppIDPlistRe and gadAppIDJSONRe read the AdMob app ID from
+	// Info.plist, or from app.json's infoPlist and the Google Mobile Ads
+	// config plugins.
+	gadAppIDPlistRe = regexp.MustCompile(`<key>GADApplicationIdentifier</key>\s*<string>([^<]*)</string>`)
+	gadAppIDJSONRe  = regexp.MustCompile(`"(?:GADApplicationIdentifier|ios_app_id|iosAppId|googleMobileAdsAppId)"\s*:\s*"([^"]*)"`)
+	// gadAppIDRe is the AdMob app ID format: ca-app-pub-<publisher>~<app>.
+	gadAppIDRe = regexp.MustCompile(`^ca-app-pub-\d{16}~\d{10}$`)
+	// skAdNetworkIDRe matches an SKAdNetwork identifier anywhere in a
+	// config.
+	skAdNetworkIDRe = regexp.MustCompile(`(?i)\b[a-z0-9]{10}\.skadnetwork\b`)
+	// trackingPurposeRe matches the App Tracking Transparency purpose
+	// string, set directly or by the Expo and Google Mobile Ads config
+	// plugins.
+	trackingPurposeRe = regexp.MustCompile(`NSUserTrackingUsageDescription|expo-tracking-transparency|user_tracking_usage_description`)
+)
+
+// gadSamplePublisher is the publisher ID of Google's sample AdMob app IDs,
+// which only ever serve test ads.
+const gadSamplePublisher = "ca-app-pub-3940256099942544~"
+
+// adConfig is the ad-related configuration of a project's Info.plists and
+// Expo config.
+type adConfig struct {
+	// gadAppIDs maps each AdMob app ID set to the file setting it.
+	gadAppIDs map[string]string
+	// skan holds the declared SKAdNetwork identifiers.
+	skan skAdNetworkConfig
+	// trackingPurpose is the file setting NSUserTrackingUsageDescription.
+	trackingPurpose string
+	// plist is the first app Info.plist, where missing keys belong.
+	plist string
+}
+
+// checkAdNetworks finds the ad SDKs the app bundles and checks the
+// Info.plist entries they depend on: the AdMob app ID, without which the
+// Google Mobile Ads SDK crashes at launch, and each network's SKAdNetwork
+// identifiers, without which its installs go unattributed (see
+// checkSKAdNetwork).
+func checkAdNetworks(projectPath string) []Finding {
+	networks := adnetworks.Detect(projectPath)
+	if len(networks) == 0 {
+		return nil
+	}
+	cfg := readAdConfig(projectPath)
+
+	findings := checkSKAdNetwork(projectPath, networks, cfg)
+	for _, n := range networks {
+		if n.Name == adnetworks.Google {
+			findings = append(findings, checkGADAppID(n, cfg)...)
+		}
+
+		// With no SKAdNetworkItems at all, checkSKAdNetwork reports the
+		// array itself.
+		if !cfg.skan.declared {
+			continue
+		}
+		var missing []string
+		for _, id := range n.SKAdNetworkIDs {
+			if _, ok := cfg.skan.ids[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		file, line := cfg.plist, 0
+		if file == "" {
+			file, line = n.File, n.Line
+		}
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleSKAdNetworkIDMissing,
+			Severity:  severity.Info,
+			Guideline: "5.1.2",
+			Title:     fmt.Sprintf("SKAdNetwork ID missing for %q", n.Name),
+			Detail:    fmt.Sprintf("%s attributes installs from its ads with SKAdNetwork, but SKAdNetworkItems in Info.plist doesn't list %s. StoreKit only sends install postbacks to the networks an app declares, so the network can't attribute installs and bids less for the app's ad inventory.", n.Name, strings.Join(missing, ", ")),
+			Fix:       fmt.Sprintf("Add %s to SKAdNetworkItems, along with the partner identifiers %s publishes at %s.", strings.Join(missing, ", "), n.Name, n.ListURL),
+			File:      file,
+			Line:      line,
+		})
+	}
+	return findings
+}
+
+// checkGADAppID checks the AdMob app ID the Google Mobile Ads SDK reads at
+// start.
+func checkGADAppID(n adnetworks.Detected, cfg adConfig) []Finding {
+	if len(cfg.gadAppIDs) == 0 {
+		file := cfg.plist
+		if file == "" {
+			file = n.File
+		}
+		return []Finding{{
+			Source:    "metadata",
+			RuleID:    ruleGADAppIDMissing,
+			Severity:  severity.Critical,
+			Guideline: "2.1",
+			Title:     "GADApplicationIdentifier missing from Info.plist",
+			Detail:    "The app bundles the Google Mobile Ads SDK (" + n.File + "), which terminates the app at launch when Info.plist has no GADApplicationIdentifier.",
+			Fix:       "Add GADApplicationIdentifier with the app ID from AdMob → Apps → App settings (ca-app-pub-…~…), or set ios_app_id in the react-native-google-mobile-ads config.",
+			File:      file,
+		}}
+	}
+
+	var findings []Finding
+	for id, file := range cfg.gadAppIDs {
+		switch {
+		case strings.Contains(id, "$"):
+			// Set per configuration through a build setting.
+		case strings.HasPrefix(id, gadSamplePublisher):
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleGADSampleAppID,
+				Severity:  severity.Warn,
+				Guideline: "2.1",
+				Title:     "GADApplicationIdentifier is Google's sample app ID",
+				Detail:    id + " is the app ID from Google's samples. It only serves test ads, which earn nothing and which reviewers flag as an unfinished app.",
+				Fix:       "Use the app's own AdMob app ID for release builds; keep the sample ID to Debug through a build setting.",
+				File:      file,
+			})
+		case !gadAppIDRe.MatchString(id):
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleGADAppIDInvalid,
+				Severity:  severity.Critical,
+				Guideline: "2.1",
+				Title:     "Invalid GADApplicationIdentifier",
+				Detail:    fmt.Sprintf("GADApplicationIdentifier is %q, not an AdMob app ID (ca-app-pub-<16 digits>~<10 digits>). The Google Mobile Ads SDK terminates the app at launch with an invalid ID; an ad unit ID (with / instead of ~) is a common mix-up.", id),
+				Fix:       "Copy the app ID from AdMob → Apps → App settings.",
+				File:      file,
+			})
+		}
+	}
+	return findings
+}
+
+// readAdConfig reads the AdMob app IDs, SKAdNetwork identifiers and
+// tracking purpose string set in the project's Info.plists and Expo config.
+func readAdConfig(projectPath string) adConfig {
+	cfg := adConfig{
+		gadAppIDs: map[string]string{},
+		skan:      skAdNetworkConfig{ids: map[string]string{}, problems: map[string][]string{}},
+	}
+	files := findInfoPlists(projectPath)
+	for _, name := range append([]string{"app.json"}, expoDynamicConfigs...) {
+		files = append(files, filepath.Join(projectPath, name))
+	}
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		rel = filepath.ToSlash(rel)
+		content := string(data)
+		if strings.HasSuffix(path, ".plist") && cfg.plist == "" {
+			cfg.plist = rel
+		}
+		for _, re := range []*regexp.Regexp{gadAppIDPlistRe, gadAppIDJSONRe} {
+			for _, m := range re.FindAllStringSubmatch(content, -1) {
+				if id := strings.TrimSpace(m[1]); id != "" {
+					cfg.gadAppIDs[id] = rel
+				}
+			}
+		}
+		if strings.HasSuffix(path, ".plist") {
+			cfg.skan.parseSKAdNetworkPlist(content, rel)
+		} else {
+			cfg.skan.parseSKAdNetworkJSON(content, rel)
+		}
+		if cfg.trackingPurpose == "" && trackingPurposeRe.MatchString(content) {
+			cfg.trackingPurpose = rel
+		}
+	}
+	return cfg
+}
@@ -0,0 +1,145 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+const (
+	// Asset catalogs above this size noticeably slow installs and first
+	// launch (Assets.car is memory-mapped and indexed on start-up).
+	assetCatalogWarnBytes = 100 << 20
+	// Bundled fonts above this total are usually full CJK or icon fonts
+	// registered at launch.
+	fontTotalWarnBytes = 10 << 20
+	fontLargeBytes     = 4 << 20
+)
+
+type sizedFile struct {
+	path string
+	size int64
+}
+
+// checkLaunchAssets estimates cold-launch and download cost from the assets
+// the project ships: oversized asset catalogs and heavy bundled fonts.
+func checkLaunchAssets(projectPath string) []Finding {
+	var findings []Finding
+
+	skipDirs := map[string]bool{
+		"node_modules": true, ".git": true, "Pods": true,
+		"build": true, "dist": true, ".expo": true,
+		"DerivedData": true, "vendor": true,
+	}
+
+	catalogs := map[string]int64{}
+	catalogImages := map[string][]sizedFile{}
+	var fonts []sizedFile
+	var fontTotal int64
+
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+
+		if i := strings.Index(rel, ".xcassets"+string(filepath.Separator)); i >= 0 {
+			catalog := rel[:i+len(".xcassets")]
+			catalogs[catalog] += info.Size()
+			if ext := strings.ToLower(filepath.Ext(path)); ext == ".png" || ext == ".jpg" || ext == ".jpeg" || ext == ".pdf" || ext == ".heic" {
+				catalogImages[catalog] = append(catalogImages[catalog], sizedFile{rel, info.Size()})
+			}
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".ttf", ".otf", ".ttc":
+			fonts = append(fonts, sizedFile{rel, info.Size()})
+			fontTotal += info.Size()
+		}
+		return nil
+	})
+
+	var names []string
+	for name := range catalogs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if catalogs[name] < assetCatalogWarnBytes {
+			continue
+		}
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleLargeAssetCatalog,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     fmt.Sprintf("Asset catalog %s is %s", filepath.Base(name), formatMB(catalogs[name])),
+			Detail:    "Very large asset catalogs slow installs and cold launch and push the app toward the cellular download limit. Largest images: " + largest(catalogImages[name], 3) + ".",
+			Fix:       "Compress images (HEIC, lossy PNG), drop unused @1x/@2x variants, and move rarely used assets to On-Demand Resources.",
+			File:      name,
+		})
+	}
+
+	if fontTotal >= fontTotalWarnBytes {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleLargeFontTotal,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     fmt.Sprintf("Bundled fonts total %s", formatMB(fontTotal)),
+			Detail:    "Fonts listed in UIAppFonts are registered at launch, so heavy fonts add to cold-launch time and download size. Largest: " + largest(fonts, 3) + ".",
+			Fix:       "Subset fonts to the glyphs you use, ship only the weights you need, and prefer system fonts (including system CJK fonts) where possible.",
+		})
+	} else {
+		for _, f := range fonts {
+			if f.size < fontLargeBytes {
+				continue
+			}
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleLargeFont,
+				Severity:  severity.Info,
+				Guideline: "2.1",
+				Title:     fmt.Sprintf("Large font %s (%s)", filepath.Base(f.path), formatMB(f.size)),
+				Detail:    "Large fonts (usually full CJK or icon sets) are registered at launch and add to download size.",
+				Fix:       "Subset the font to the glyphs you use, or use the system font.",
+				File:      f.path,
+			})
+		}
+	}
+
+	return findings
+}
+
+// largest lists the n biggest files as "name (size)".
+func largest(files []sizedFile, n int) string {
+	sorted := append([]sizedFile(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].size > sorted[j].size })
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	var parts []string
+	for _, f := range sorted {
+		parts = append(parts, fmt.Sprintf("%s (%s)", filepath.Base(f.path), formatMB(f.size)))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatMB(bytes int64) string {
+	return fmt.Sprintf("%.1fMB", float64(bytes)/(1024*1024))
+}
@@ -0,0 +1,204 @@
+package preflight
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// easConfig is the relevant part of eas.json.
+type easConfig struct {
+	CLI *struct {
+		AppVersionSource string `json:"appVersionSource"`
+	} `json:"cli"`
+	Build  map[string]easBuildProfile `json:"build"`
+	Submit map[string]json.RawMessage `json:"submit"`
+}
+
+type easBuildProfile struct {
+	Extends           string      `json:"extends"`
+	DevelopmentClient *bool       `json:"developmentClient"`
+	Distribution      string      `json:"distribution"`
+	AutoIncrement     interface{} `json:"autoIncrement"` // bool, or "buildNumber"/"version"
+	IOS               *struct {
+		Simulator          *bool       `json:"simulator"`
+		BuildConfiguration string      `json:"buildConfiguration"`
+		AutoIncrement      interface{} `json:"autoIncrement"`
+	} `json:"ios"`
+}
+
+// resolvedEASProfile is a build profile with its extends chain applied.
+type resolvedEASProfile struct {
+	developmentClient  bool
+	distribution       string
+	autoIncrement      bool
+	simulator          bool
+	buildConfiguration string
+}
+
+// checkEASConfig flags eas.json build profiles used for store submission that
+// would produce a development, internal or simulator build, or reuse a build
+// number.
+func checkEASConfig(projectPath string) []Finding {
+	data, err := os.ReadFile(filepath.Join(projectPath, "eas.json"))
+	if err != nil {
+		return nil
+	}
+
+	var cfg easConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return []Finding{{
+			Source:    "metadata",
+			RuleID:    ruleEASInvalidJSON,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "eas.json is not valid JSON",
+			Detail:    err.Error(),
+			Fix:       "Fix the syntax so EAS Build can read your build profiles.",
+			File:      "eas.json",
+		}}
+	}
+
+	// Profiles that produce store builds: production, plus every profile
+	// that has a submit profile of the same name.
+	storeProfiles := map[string]bool{}
+	if _, ok := cfg.Build["production"]; ok {
+		storeProfiles["production"] = true
+	}
+	for name := range cfg.Submit {
+		if _, ok := cfg.Build[name]; ok {
+			storeProfiles[name] = true
+		}
+	}
+
+	if len(storeProfiles) == 0 {
+		return []Finding{{
+			Source:    "metadata",
+			RuleID:    ruleEASNoProductionProfile,
+			Severity:  severity.Info,
+			Guideline: "2.1",
+			Title:     "No production build profile in eas.json",
+			Detail:    "eas.json has no \"production\" build profile and no build profile matching a submit profile, so store builds fall back to defaults.",
+			Fix:       "Add build.production (and submit.production) to eas.json.",
+			File:      "eas.json",
+		}}
+	}
+
+	names := make([]string, 0, len(storeProfiles))
+	for name := range storeProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	remoteVersion := cfg.CLI != nil && cfg.CLI.AppVersionSource == "remote"
+	versionHint := " (ideally with cli.appVersionSource \"remote\")"
+	if remoteVersion {
+		versionHint = ""
+	}
+
+	var findings []Finding
+	add := func(rule string, sev severity.Level, title, detail, fix string) {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    rule,
+			Severity:  sev,
+			Guideline: "2.1",
+			Title:     title,
+			Detail:    detail,
+			Fix:       fix,
+			File:      "eas.json",
+		})
+	}
+
+	for _, name := range names {
+		p := resolveEASProfile(cfg.Build, name)
+		where := "build." + name
+
+		if p.developmentClient {
+			add(ruleEASDevelopmentClient, severity.Critical,
+				"Development client enabled in store build profile '"+name+"'",
+				where+".developmentClient is true, so the build contains expo-dev-client and opens the developer launcher instead of your app. Reviewers reject it as incomplete.",
+				"Remove developmentClient from "+where+" (keep it on a separate development profile).")
+		}
+		if p.distribution == "internal" {
+			add(ruleEASInternalDistribution, severity.Critical,
+				"Internal distribution on store build profile '"+name+"'",
+				where+".distribution is \"internal\", which produces an ad hoc/enterprise-signed build that App Store Connect will not accept.",
+				"Set distribution to \"store\" (the default) for profiles you submit.")
+		}
+		if p.simulator {
+			add(ruleEASSimulatorBuild, severity.Critical,
+				"Simulator build configured for store profile '"+name+"'",
+				where+".ios.simulator is true, so EAS produces a simulator .app that cannot be uploaded to App Store Connect.",
+				"Remove ios.simulator from "+where+".")
+		}
+		if p.buildConfiguration == "Debug" {
+			add(ruleEASDebugConfiguration, severity.Warn,
+				"Debug build configuration on store profile '"+name+"'",
+				where+".ios.buildConfiguration is \"Debug\". Debug builds are unoptimized and may include development-only code paths.",
+				"Use the Release configuration for store builds.")
+		}
+		if !p.autoIncrement {
+			add(ruleEASNoAutoIncrement, severity.Warn,
+				"Build number not auto-incremented in profile '"+name+"'",
+				where+" has no autoIncrement. Uploading a build number that App Store Connect has already seen fails, a common cause of failed EAS submissions.",
+				"Set \"autoIncrement\": true on "+where+versionHint+".")
+		}
+	}
+	return findings
+}
+
+// resolveEASProfile applies a profile's extends chain, nearest values winning.
+func resolveEASProfile(profiles map[string]easBuildProfile, name string) resolvedEASProfile {
+	var chain []easBuildProfile
+	seen := map[string]bool{}
+	for name != "" && !seen[name] {
+		p, ok := profiles[name]
+		if !ok {
+			break
+		}
+		seen[name] = true
+		chain = append(chain, p)
+		name = p.Extends
+	}
+
+	var r resolvedEASProfile
+	for i := len(chain) - 1; i >= 0; i-- {
+		p := chain[i]
+		if p.DevelopmentClient != nil {
+			r.developmentClient = *p.DevelopmentClient
+		}
+		if p.Distribution != "" {
+			r.distribution = p.Distribution
+		}
+		if p.AutoIncrement != nil {
+			r.autoIncrement = easAutoIncrement(p.AutoIncrement)
+		}
+		if p.IOS != nil {
+			if p.IOS.Simulator != nil {
+				r.simulator = *p.IOS.Simulator
+			}
+			if p.IOS.BuildConfiguration != "" {
+				r.buildConfiguration = p.IOS.BuildConfiguration
+			}
+			if p.IOS.AutoIncrement != nil {
+				r.autoIncrement = easAutoIncrement(p.IOS.AutoIncrement)
+			}
+		}
+	}
+	return r
+}
+
+// easAutoIncrement interprets autoIncrement: true, "buildNumber" or "version".
+func easAutoIncrement(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "buildNumber" || v == "version"
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package preflight
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// expoDynamicConfigs are the JS/TS config files Expo evaluates at build time.
+var expoDynamicConfigs = []string{"app.config.js", "app.config.ts"}
+
+// expoEvalTimeout bounds `npx expo config`, which loads the project's config
+// plugins.
+const expoEvalTimeout = 60 * time.Second
+
+// evaluateExpoConfig resolves the project's dynamic Expo config by running
+// `npx expo config --json --type public` with the project's own expo package,
+// and returns it wrapped as {"expo": {...}} so it can be checked like app.json.
+func evaluateExpoConfig(projectPath string) ([]byte, error) {
+	if _, err := os.Stat(filepath.Join(projectPath, "node_modules", "expo")); err != nil {
+		return nil, fmt.Errorf("expo is not installed in node_modules")
+	}
+	npx, err := exec.LookPath("npx")
+	if err != nil {
+		return nil, fmt.Errorf("npx not found on PATH")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), expoEvalTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, npx, "--no-install", "expo", "config", "--json", "--type", "public")
+	cmd.Dir = projectPath
+	cmd.Env = append(os.Environ(), "CI=1", "EXPO_NO_TELEMETRY=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("npx expo config timed out after %s", expoEvalTimeout)
+	}
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if i := strings.IndexByte(msg, '\n'); i >= 0 {
+			msg = msg[:i]
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("npx expo config failed: %s", msg)
+	}
+
+	// Config plugins may log before the JSON document.
+	start := bytes.IndexByte(out, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("npx expo config printed no JSON")
+	}
+	var resolved map[string]interface{}
+	if err := json.Unmarshal(out[start:], &resolved); err != nil {
+		return nil, fmt.Errorf("npx expo config printed invalid JSON: %w", err)
+	}
+	return json.Marshal(map[string]interface{}{"expo": resolved})
+}
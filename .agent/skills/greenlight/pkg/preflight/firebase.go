@@ -0,0 +1,465 @@
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/deps"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Firebase products whose configuration is checked.
+const (
+	firebaseCore      = "core"
+	firebaseAnalytics = "analytics"
+	firebaseMessaging = "messaging"
+)
+
+// firebasePods and firebaseNPM map dependencies to the Firebase product
+// they bring in. Swift packages are matched by the product names the Xcode
+// project links (firebaseProductRe), as firebase-ios-sdk is one package.
+var (
+	firebasePods = map[string]string{
+		"Firebase":             firebaseCore,
+		"FirebaseCore":         firebaseCore,
+		"FirebaseAnalytics":    firebaseAnalytics,
+		"GoogleAppMeasurement": firebaseAnalytics,
+		"FirebaseMessaging":    firebaseMessaging,
+	}
+	firebaseNPM = map[string]string{
+		"@react-native-firebase/app":       firebaseCore,
+		"@react-native-firebase/analytics": firebaseAnalytics,
+		"@react-native-firebase/messaging": firebaseMessaging,
+	}
+	firebaseProductRe = regexp.MustCompile(`productName = "?(Firebase\w*)`)
+)
+
+var (
+	// googleServicesValueRe matches a string or boolean entry of a
+	// GoogleService-Info.plist.
+	googleServicesValueRe = regexp.MustCompile(`<key>([^<]+)</key>\s*(?:<string>([^<]*)</string>|<(true|false)\s*/>)`)
+	// googleAppIDRe is the iOS app ID format FirebaseApp.configure()
+	// accepts: "1:<project number>:ios:<hex>".
+	googleAppIDRe = regexp.MustCompile(`^1:\d+:ios:[0-9a-f]+$`)
+	// firebaseAPIKeyRe is the format of a Google Cloud API key.
+	firebaseAPIKeyRe = regexp.MustCompile(`^AIza[0-9A-Za-z_-]{35}$`)
+	// firebasePlaceholderRe matches template values left in a config.
+	firebasePlaceholderRe = regexp.MustCompile(`(?i)^(your[_-]|placeholder|changeme|todo)|x{4,}|example\.com`)
+
+	firebaseEnableAnalyticsRe = regexp.MustCompile(`setAnalyticsCollectionEnabled`)
+	firebaseAPNSTokenRe       = regexp.MustCompile(`\.apnsToken\s*=|setAPNSToken|\.APNSToken\s*=`)
+	firebaseOptionsRe         = regexp.MustCompile(`FirebaseOptions\(|FIROptions alloc|initializeApp\(`)
+	trackingRequestRe         = regexp.MustCompile(`requestTrackingAuthorization|requestTrackingPermissionsAsync|requestTrackingPermission\(`)
+)
+
+// googleServicesFile is a GoogleService-Info.plist (or a per-environment
+// copy such as GoogleService-Info-Staging.plist).
+type googleServicesFile struct {
+	Rel    string
+	Values map[string]string
+}
+
+// firebaseProject is what the Firebase checks know about a project.
+type firebaseProject struct {
+	products     map[string]bool
+	productFiles map[string]string // the manifest that added each product
+	configs      []googleServicesFile
+	androidKeys  map[string]string // google-services.json API keys and files
+	settings     []string          // Info.plist, app.json and firebase.json contents
+	entitlements []string
+	pbxprojs     []string
+	expoConfig   string
+
+	enablesAnalytics bool // code turns collection on at runtime
+	setsAPNSToken    bool // code hands the APNs token to Messaging
+	configuresInCode bool // code builds FirebaseOptions instead of the plist
+	requestsTracking bool // code shows the App Tracking Transparency prompt
+}
+
+// checkFirebaseConfig validates GoogleService-Info.plist against the app:
+// the bundle ID it was issued for, template values, the API key, Analytics
+// collection flags against App Tracking Transparency, and the push setup
+// Firebase Messaging needs. Misconfigured Firebase mostly fails silently,
+// so these surface before users do.
+func checkFirebaseConfig(projectPath string, xcode *xcodeBuilds, meta AppMeta) []Finding {
+	p := scanFirebaseProject(projectPath)
+	if len(p.products) == 0 && len(p.configs) == 0 {
+		return nil
+	}
+
+	var findings []Finding
+	if p.products[firebaseCore] && len(p.configs) == 0 && !p.configuresInCode && !strings.Contains(p.expoConfig, "googleServicesFile") {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebasePlistMissing,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "Firebase is a dependency but GoogleService-Info.plist is missing",
+			Detail:    "FirebaseApp.configure() raises an exception at launch when the app bundle has no GoogleService-Info.plist, which reviewers see as a crash.",
+			Fix:       "Download GoogleService-Info.plist for this app from the Firebase console and add it to the app target (or set ios.googleServicesFile in app.json). If a build script copies it in, make sure the release build runs it.",
+			File:      p.productFiles[firebaseCore],
+		})
+	}
+
+	if len(p.configs) > 0 && len(p.pbxprojs) > 0 && !anyContains(p.pbxprojs, "GoogleService-Info") {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebasePlistNotInProject,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "GoogleService-Info.plist is not in the Xcode project",
+			Detail:    "No Xcode project references the file or copies it in a build phase, so it is not bundled and FirebaseApp.configure() raises an exception at launch.",
+			Fix:       "Add GoogleService-Info.plist to the app target's Copy Bundle Resources phase.",
+			File:      p.configs[0].Rel,
+		})
+	}
+
+	bundleIDs := appBundleIDs(xcode, meta)
+	for _, c := range p.configs {
+		findings = append(findings, checkGoogleServicesFile(c, bundleIDs, p.androidKeys)...)
+	}
+
+	// Analytics: ad personalization signals are tracking under Apple's
+	// definition, and collection that starts disabled has to be enabled.
+	deactivated := settingIs(p.settings, "FIREBASE_ANALYTICS_COLLECTION_DEACTIVATED", true) ||
+		settingIs(p.settings, "analytics_collection_deactivated", true)
+	if p.products[firebaseAnalytics] && !deactivated {
+		disabled := settingIs(p.settings, "FIREBASE_ANALYTICS_COLLECTION_ENABLED", false) ||
+			settingIs(p.settings, "analytics_auto_collection_enabled", false)
+		noAdSignals := settingIs(p.settings, "GOOGLE_ANALYTICS_DEFAULT_ALLOW_AD_PERSONALIZATION_SIGNALS", false) ||
+			settingIs(p.settings, "google_analytics_default_allow_ad_personalization_signals", false) ||
+			settingIs(p.settings, "analytics_default_allow_ad_personalization_signals", false)
+		tracking := p.requestsTracking || anyContains(p.settings, "NSUserTrackingUsageDescription")
+		if !noAdSignals && !tracking {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleFirebaseAdPersonalization,
+				Severity:  severity.Warn,
+				Guideline: "5.1.2",
+				Title:     "Firebase Analytics allows ad personalization without App Tracking Transparency",
+				Detail:    "Analytics data is used for ad personalization by default once the Firebase project is linked to Google Ads or AdMob, which Apple counts as tracking. The app never asks for tracking permission, so it must not share that data.",
+				Fix:       "Set GOOGLE_ANALYTICS_DEFAULT_ALLOW_AD_PERSONALIZATION_SIGNALS to NO in Info.plist (google_analytics_default_allow_ad_personalization_signals in firebase.json for React Native), or request App Tracking Transparency and declare tracking in the privacy label.",
+				File:      p.productFiles[firebaseAnalytics],
+			})
+		}
+		if disabled && !p.enablesAnalytics {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleFirebaseAnalyticsDisabled,
+				Severity:  severity.Info,
+				Guideline: "5.1.1",
+				Title:     "Firebase Analytics collection is disabled and never enabled",
+				Detail:    "FIREBASE_ANALYTICS_COLLECTION_ENABLED is off, which is right for collecting only after consent, but no code calls setAnalyticsCollectionEnabled(true), so Analytics never collects anything.",
+				Fix:       "Call Analytics.setAnalyticsCollectionEnabled(true) once the user consents, or remove FirebaseAnalytics if it's unused.",
+				File:      p.productFiles[firebaseAnalytics],
+			})
+		}
+	}
+
+	// Messaging: FCM maps its registration token to the APNs token, which
+	// the app only gets with the push entitlement.
+	if p.products[firebaseMessaging] {
+		if len(p.pbxprojs) > 0 && !anyContains(p.entitlements, "aps-environment") {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleFirebaseMessagingNoPush,
+				Severity:  severity.Warn,
+				Guideline: "4.5.4",
+				Title:     "Firebase Messaging without the Push Notifications entitlement",
+				Detail:    "No .entitlements file declares aps-environment, so the app never receives an APNs device token. FCM registration fails silently and no notifications are delivered.",
+				Fix:       "Enable the Push Notifications capability for the app target and upload an APNs key to the Firebase project's Cloud Messaging settings.",
+				File:      p.productFiles[firebaseMessaging],
+			})
+		}
+		if settingIs(p.settings, "FirebaseAppDelegateProxyEnabled", false) && !p.setsAPNSToken {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleFirebaseAPNsToken,
+				Severity:  severity.Warn,
+				Guideline: "4.5.4",
+				Title:     "Firebase Messaging swizzling is off but the APNs token is never passed",
+				Detail:    "FirebaseAppDelegateProxyEnabled is NO, so Messaging no longer picks up the APNs token itself, and no code sets Messaging.messaging().apnsToken. FCM tokens never map to the device and notifications are not delivered.",
+				Fix:       "Set Messaging.messaging().apnsToken = deviceToken in application(_:didRegisterForRemoteNotificationsWithDeviceToken:), or remove FirebaseAppDelegateProxyEnabled.",
+				File:      p.productFiles[firebaseMessaging],
+			})
+		}
+	}
+	return findings
+}
+
+// checkGoogleServicesFile validates one GoogleService-Info.plist. bundleIDs
+// are the app's resolved bundle IDs; androidKeys the API keys of the
+// project's google-services.json files.
+func checkGoogleServicesFile(c googleServicesFile, bundleIDs []string, androidKeys map[string]string) []Finding {
+	var findings []Finding
+
+	if appID, ok := c.Values["GOOGLE_APP_ID"]; ok && !googleAppIDRe.MatchString(appID) {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebaseInvalidAppID,
+			Severity:  severity.Critical,
+			Guideline: "2.1",
+			Title:     "Invalid GOOGLE_APP_ID in GoogleService-Info.plist",
+			Detail:    fmt.Sprintf("GOOGLE_APP_ID is %q, not an iOS app ID (1:<project number>:ios:<id>). FirebaseApp.configure() raises an exception for it at launch.", appID),
+			Fix:       "Download GoogleService-Info.plist for the iOS app from the Firebase console instead of editing it or reusing another platform's config.",
+			File:      c.Rel,
+		})
+	}
+
+	var placeholders []string
+	for _, key := range []string{"API_KEY", "PROJECT_ID", "GCM_SENDER_ID", "BUNDLE_ID", "STORAGE_BUCKET", "CLIENT_ID"} {
+		if v := c.Values[key]; v != "" && firebasePlaceholderRe.MatchString(v) {
+			placeholders = append(placeholders, key)
+		}
+	}
+	if len(placeholders) > 0 {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebasePlaceholders,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "GoogleService-Info.plist contains placeholder values",
+			Detail:    "Template values are left in " + strings.Join(placeholders, ", ") + ", so Firebase connects to no project and every service fails.",
+			Fix:       "Replace the file with the one the Firebase console generates for this app.",
+			File:      c.Rel,
+		})
+	}
+
+	if id := c.Values["BUNDLE_ID"]; id != "" && len(bundleIDs) > 0 && !containsFold(bundleIDs, id) && !firebasePlaceholderRe.MatchString(id) {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebaseBundleIDMismatch,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "GoogleService-Info.plist is for a different bundle ID",
+			Detail:    fmt.Sprintf("The file was issued for %s, but the app builds as %s. Analytics, Auth, Dynamic Links and Messaging check the bundle ID and fail silently on a mismatch.", id, strings.Join(bundleIDs, ", ")),
+			Fix:       "Register the app's bundle ID in the Firebase project and download its GoogleService-Info.plist.",
+			File:      c.Rel,
+		})
+	}
+
+	key := c.Values["API_KEY"]
+	switch {
+	case key == "" || firebasePlaceholderRe.MatchString(key):
+	case !firebaseAPIKeyRe.MatchString(key):
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebaseMalformedAPIKey,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "Malformed API_KEY in GoogleService-Info.plist",
+			Detail:    "API_KEY is not a Google Cloud API key (AIza followed by 35 characters), so every Firebase request is rejected.",
+			Fix:       "Copy API_KEY from a freshly downloaded GoogleService-Info.plist.",
+			File:      c.Rel,
+		})
+	case androidKeys[key] != "":
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleFirebaseSharedAPIKey,
+			Severity:  severity.Info,
+			Guideline: "1.6",
+			Title:     "Firebase API key is shared with the Android app",
+			Detail:    "GoogleService-Info.plist and " + androidKeys[key] + " use the same API key. A key takes one kind of application restriction, so this one can't be limited to the iOS bundle ID, and anyone who extracts it from the app can use it from anywhere.",
+			Fix:       "Create an iOS key in the Google Cloud console restricted to the app's bundle ID and the Firebase APIs it uses, and set it as API_KEY.",
+			File:      c.Rel,
+		})
+	}
+	return findings
+}
+
+// scanFirebaseProject collects the Firebase configuration, dependencies and
+// code evidence under projectPath in one walk.
+func scanFirebaseProject(projectPath string) firebaseProject {
+	p := firebaseProject{
+		products:     map[string]bool{},
+		productFiles: map[string]string{},
+		androidKeys:  map[string]string{},
+	}
+	codeExts := map[string]bool{
+		".swift": true, ".m": true, ".mm": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	}
+	addProduct := func(product, rel string) {
+		p.products[product] = true
+		if p.productFiles[product] == "" {
+			p.productFiles[product] = filepath.ToSlash(rel)
+		}
+		// Every product needs the core SDK configured.
+		if !p.products[firebaseCore] {
+			p.products[firebaseCore] = true
+			p.productFiles[firebaseCore] = filepath.ToSlash(rel)
+		}
+	}
+
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if licenseSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := info.Name()
+		rel, _ := filepath.Rel(projectPath, path)
+		ext := strings.ToLower(filepath.Ext(name))
+		lowerRel := strings.ToLower(filepath.ToSlash(rel))
+		if strings.Contains(lowerRel, "test") || strings.Contains(lowerRel, "example") {
+			return nil
+		}
+
+		switch {
+		case name == "Podfile.lock":
+			pods, _ := deps.Pods(path)
+			for _, d := range pods {
+				if product, ok := firebasePods[d.Name]; ok {
+					addProduct(product, rel)
+				}
+			}
+			return nil
+		case name == "package.json":
+			pkgs, _ := deps.NPM(path)
+			for _, d := range pkgs {
+				if product, ok := firebaseNPM[d.Name]; ok {
+					addProduct(product, rel)
+				}
+			}
+			return nil
+		case name == "project.pbxproj":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			p.pbxprojs = append(p.pbxprojs, string(data))
+			for _, m := range firebaseProductRe.FindAllStringSubmatch(string(data), -1) {
+				switch {
+				case strings.HasPrefix(m[1], "FirebaseAnalytics"):
+					addProduct(firebaseAnalytics, rel)
+				case m[1] == "FirebaseMessaging":
+					addProduct(firebaseMessaging, rel)
+				default:
+					addProduct(firebaseCore, rel)
+				}
+			}
+			return nil
+		case strings.HasPrefix(name, "GoogleService-Info") && ext == ".plist":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			c := googleServicesFile{Rel: filepath.ToSlash(rel), Values: map[string]string{}}
+			for _, m := range googleServicesValueRe.FindAllStringSubmatch(string(data), -1) {
+				c.Values[strings.TrimSpace(m[1])] = strings.TrimSpace(m[2] + m[3])
+			}
+			p.configs = append(p.configs, c)
+			return nil
+		case name == "google-services.json":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			var gs struct {
+				Client []struct {
+					APIKey []struct {
+						CurrentKey string `json:"current_key"`
+					} `json:"api_key"`
+				} `json:"client"`
+			}
+			if json.Unmarshal(data, &gs) == nil {
+				for _, c := range gs.Client {
+					for _, k := range c.APIKey {
+						p.androidKeys[k.CurrentKey] = filepath.ToSlash(rel)
+					}
+				}
+			}
+			return nil
+		case strings.EqualFold(name, "Info.plist"), name == "app.json", name == "firebase.json", ext == ".entitlements":
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			if ext == ".entitlements" {
+				p.entitlements = append(p.entitlements, string(data))
+				return nil
+			}
+			p.settings = append(p.settings, string(data))
+			if name == "app.json" {
+				p.expoConfig += string(data)
+			}
+			return nil
+		case codeExts[ext] && info.Size() <= 1<<20:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			p.enablesAnalytics = p.enablesAnalytics || firebaseEnableAnalyticsRe.Match(data)
+			p.setsAPNSToken = p.setsAPNSToken || firebaseAPNSTokenRe.Match(data)
+			p.configuresInCode = p.configuresInCode || firebaseOptionsRe.Match(data)
+			p.requestsTracking = p.requestsTracking || trackingRequestRe.Match(data)
+		}
+		return nil
+	})
+	return p
+}
+
+// appBundleIDs lists the bundle IDs the app's targets build with, resolved
+// from the Xcode build settings, or the one read from the project's config.
+func appBundleIDs(xcode *xcodeBuilds, meta AppMeta) []string {
+	seen := map[string]bool{}
+	if xcode != nil {
+		for _, c := range xcode.plists {
+			if id, _ := c.Expand("$(PRODUCT_BUNDLE_IDENTIFIER)"); id != "" && !strings.Contains(id, "$") {
+				seen[id] = true
+			}
+		}
+	}
+	if meta.BundleID != "" && !strings.Contains(meta.BundleID, "$") {
+		seen[meta.BundleID] = true
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// settingIs reports whether any of the contents (Info.plist XML or JSON
+// config) sets key to the boolean want. Plist strings YES/NO count as
+// booleans, as Firebase reads them that way.
+func settingIs(contents []string, key string, want bool) bool {
+	re := regexp.MustCompile(`(?i)(<key>` + regexp.QuoteMeta(key) + `</key>\s*(<(true|false)\s*/>|<string>(YES|NO|true|false)</string>)|"` + regexp.QuoteMeta(key) + `"\s*:\s*"?(true|false|YES|NO)"?)`)
+	for _, content := range contents {
+		for _, m := range re.FindAllStringSubmatch(content, -1) {
+			v := strings.ToLower(m[3] + m[4] + m[5])
+			if (v == "true" || v == "yes") == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyContains(contents []string, s string) bool {
+	for _, c := range contents {
+		if strings.Contains(c, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
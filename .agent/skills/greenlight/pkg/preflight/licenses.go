@@ -0,0 +1,264 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/deps"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// license terms that matter for App Store distribution.
+type licenseTerms int
+
+const (
+	licenseUnknown licenseTerms = iota
+	// licenseNoNotice needs nothing shipped with the app (Unlicense, CC0,
+	// 0BSD, zlib).
+	licenseNoNotice
+	// licenseNotice requires shipping the copyright notice and license
+	// text with the app (MIT, BSD, Apache, ISC, MPL).
+	licenseNotice
+	// licenseWeakCopyleft (LGPL) requires that users can relink the app
+	// against a modified library, which a statically linked iOS app can't
+	// offer without extra work.
+	licenseWeakCopyleft
+	// licenseCopyleft (GPL, AGPL) forbids the further restrictions the App
+	// Store terms place on distributed apps.
+	licenseCopyleft
+)
+
+// licensedDep is a dependency with the license read from its installed
+// sources.
+type licensedDep struct {
+	Name    string
+	License string // SPDX identifier or expression
+	Terms   licenseTerms
+	File    string // the lock file or manifest pinning it
+	Line    int
+}
+
+// checkLicenses reads the licenses of the app's pods, Swift packages and npm
+// dependencies from their installed sources, and flags copyleft licenses
+// that conflict with App Store distribution and attribution licenses when
+// the app has no acknowledgements screen.
+func checkLicenses(projectPath string) []Finding {
+	var manifests, checkouts []string
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			switch {
+			case deps.IsCheckouts(path):
+				checkouts = append(checkouts, path)
+				return filepath.SkipDir
+			case info.Name() == "build" || info.Name() == "DerivedData":
+				checkouts = append(checkouts, deps.BuildCheckouts(path)...)
+				return filepath.SkipDir
+			case licenseSkipDirs[info.Name()]:
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch info.Name() {
+		case "Podfile.lock", "Package.resolved", "package.json":
+			manifests = append(manifests, path)
+		}
+		return nil
+	})
+
+	var licensed []licensedDep
+	generated := false // an acknowledgements library is a dependency
+	for _, manifest := range manifests {
+		var pins []deps.Dep
+		switch filepath.Base(manifest) {
+		case "Podfile.lock":
+			pins, _ = deps.Pods(manifest)
+		case "Package.resolved":
+			pins, _ = deps.Packages(manifest)
+		default:
+			pins, _ = deps.NPM(manifest)
+		}
+		rel, _ := filepath.Rel(projectPath, manifest)
+		for _, pin := range pins {
+			if acknowledgementLibs[strings.ToLower(pin.Name)] {
+				generated = true
+			}
+			dir := deps.Source(manifest, pin, checkouts)
+			if dir == "" {
+				continue
+			}
+			id := deps.License(dir)
+			licensed = append(licensed, licensedDep{
+				Name:    pin.Name,
+				License: id,
+				Terms:   classifyLicense(id),
+				File:    filepath.ToSlash(rel),
+				Line:    pin.Line,
+			})
+		}
+	}
+
+	var findings []Finding
+	var notice []licensedDep
+	for _, d := range licensed {
+		switch d.Terms {
+		case licenseCopyleft:
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleCopyleftDependency,
+				Severity:  severity.Warn,
+				Guideline: "5.2",
+				Title:     fmt.Sprintf("Copyleft dependency %q (%s)", d.Name, d.License),
+				Detail:    d.License + " forbids adding restrictions to redistribution, which the App Store's usage rules (DRM, device limits) do. Its copyright holders can have the app removed from the store, as happened to VLC in 2011.",
+				Fix:       "Replace " + d.Name + " with a permissively licensed alternative, or get the copyright holders' written permission or a commercial license.",
+				File:      d.File,
+				Line:      d.Line,
+			})
+		case licenseWeakCopyleft:
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleWeakCopyleftDependency,
+				Severity:  severity.Info,
+				Guideline: "5.2",
+				Title:     fmt.Sprintf("Weak-copyleft dependency %q (%s)", d.Name, d.License),
+				Detail:    d.License + " requires that users can relink the app against a modified copy of the library. iOS apps link it statically, so you must make the app's object files available on request.",
+				Fix:       "Confirm you can meet the relinking requirement, or replace " + d.Name + ".",
+				File:      d.File,
+				Line:      d.Line,
+			})
+		}
+		if d.Terms >= licenseNotice {
+			notice = append(notice, d)
+		}
+	}
+
+	if len(notice) > 0 && !generated && !hasAcknowledgements(projectPath) {
+		var names []string
+		for _, d := range notice[:min(len(notice), 5)] {
+			names = append(names, d.Name+" ("+strings.Trim(d.License, "()")+")")
+		}
+		list := strings.Join(names, ", ")
+		if len(notice) > 5 {
+			list += fmt.Sprintf(" and %d more", len(notice)-5)
+		}
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleNoAcknowledgements,
+			Severity:  severity.Warn,
+			Guideline: "5.2",
+			Title:     "No open-source acknowledgements in the app",
+			Detail:    fmt.Sprintf("%d dependencies are under licenses that require shipping their copyright notice with the app: %s. No acknowledgements screen, licenses screen or Settings.bundle entry was found.", len(notice), list),
+			Fix:       "Add an Acknowledgements page to Settings.bundle (CocoaPods generates Pods-<App>-acknowledgements.plist; LicensePlist covers Swift packages), or an in-app licenses screen (AcknowList, react-native-legal).",
+			File:      notice[0].File,
+		})
+	}
+	return findings
+}
+
+// licenseSkipDirs are never searched for manifests or acknowledgements;
+// dependencies are read from their lock files instead.
+var licenseSkipDirs = map[string]bool{
+	"node_modules": true, ".git": true, "Pods": true,
+	"build": true, "dist": true, ".expo": true,
+	"DerivedData": true, "vendor": true,
+}
+
+// acknowledgementLibs generate an acknowledgements screen or Settings.bundle
+// page from the app's dependencies.
+var acknowledgementLibs = map[string]bool{
+	"acknowlist":                       true,
+	"licenseplist":                     true,
+	"vtacknowledgementsviewcontroller": true,
+	"cpdacknowledgements":              true,
+	"react-native-legal":               true,
+	"react-native-oss-license":         true,
+	"react-native-acknowledgements":    true,
+}
+
+// acknowledgementsRe matches a screen or settings page listing open-source
+// licenses.
+var acknowledgementsRe = regexp.MustCompile(`(?i)acknowledge?ments|open[- ]?source licen[cs]es|third[- ]party (licen[cs]es|notices|software)|oss[-_ ]?licen[cs]es`)
+
+// hasAcknowledgements reports whether the app ships its dependencies'
+// notices: a Settings.bundle page or a screen whose source, storyboard or
+// strings mention acknowledgements or open-source licenses.
+func hasAcknowledgements(projectPath string) bool {
+	exts := map[string]bool{
+		".swift": true, ".m": true, ".mm": true, ".js": true, ".jsx": true,
+		".ts": true, ".tsx": true, ".storyboard": true, ".xib": true,
+		".strings": true, ".plist": true,
+	}
+	found := false
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if licenseSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		inSettings := strings.Contains(filepath.ToSlash(rel), "Settings.bundle/")
+		if (inSettings && acknowledgementsRe.MatchString(info.Name())) || info.Name() == "license_plist.yml" {
+			found = true
+			return filepath.SkipAll
+		}
+		if !exts[strings.ToLower(filepath.Ext(path))] || info.Size() > 1<<20 {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err == nil && acknowledgementsRe.Match(data) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// classifyLicense maps an SPDX identifier or expression to its terms. For
+// "A OR B" the app may pick the least restrictive; for "A AND B" it must
+// meet both.
+func classifyLicense(id string) licenseTerms {
+	expr := strings.Trim(strings.TrimSpace(id), "()")
+	if expr == "" {
+		return licenseUnknown
+	}
+	if alts := strings.Split(expr, " OR "); len(alts) > 1 {
+		terms := licenseCopyleft
+		for _, alt := range alts {
+			if t := classifyLicense(alt); t != licenseUnknown && t < terms {
+				terms = t
+			}
+		}
+		return terms
+	}
+	if parts := strings.Split(expr, " AND "); len(parts) > 1 {
+		terms := licenseUnknown
+		for _, part := range parts {
+			terms = max(terms, classifyLicense(part))
+		}
+		return terms
+	}
+	upper := strings.ToUpper(expr)
+	switch {
+	case strings.HasPrefix(upper, "LGPL"):
+		return licenseWeakCopyleft
+	case strings.HasPrefix(upper, "GPL"), strings.HasPrefix(upper, "AGPL"):
+		return licenseCopyleft
+	case upper == "UNLICENSE", upper == "CC0-1.0", upper == "0BSD", upper == "WTFPL", upper == "ZLIB":
+		return licenseNoNotice
+	case strings.HasPrefix(upper, "MIT"), strings.HasPrefix(upper, "BSD"), strings.HasPrefix(upper, "APACHE"),
+		upper == "ISC", strings.HasPrefix(upper, "MPL"), strings.HasPrefix(upper, "CC-BY"), upper == "BSL-1.0":
+		return licenseNotice
+	}
+	return licenseUnknown
+}
@@ -0,0 +1,612 @@
+package preflight
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// AppMeta holds metadata extracted from project config files.
+type AppMeta struct {
+	AppName     string
+	BundleID    string
+	Version     string
+	BuildNumber string
+	Source      string // "app.json", "app.config.js", "Info.plist", "pbxproj"
+}
+
+// CheckLocalMetadata reads project config files and flags issues that
+// would normally require App Store Connect to detect. xcode supplies the
+// build settings used to resolve $(VAR) references in Info.plists; it may be
+// nil.
+func CheckLocalMetadata(projectPath string, xcode *xcodeBuilds) ([]Finding, AppMeta) {
+	var findings []Finding
+	var meta AppMeta
+
+	// Dynamic Expo config (app.config.js/ts) takes app.json as input and
+	// supersedes it, so evaluate it first and only fall back to app.json.
+	for _, name := range expoDynamicConfigs {
+		if _, err := os.Stat(filepath.Join(projectPath, name)); err != nil {
+			continue
+		}
+		data, err := evaluateExpoConfig(projectPath)
+		if err != nil {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleExpoConfigNotEvaluated,
+				Severity:  severity.Info,
+				Guideline: "2.1",
+				Title:     "Could not evaluate " + name,
+				Detail:    "Dynamic Expo config was not validated: " + err.Error(),
+				Fix:       "Install dependencies (npm install) so `npx expo config` can run, then re-run greenlight.",
+				File:      name,
+			})
+			break
+		}
+		f, m := checkAppJSON(data, name)
+		findings = append(findings, f...)
+		meta = m
+		meta.Source = name
+		break
+	}
+
+	// Try Expo/React Native static config (app.json)
+	appJSON := filepath.Join(projectPath, "app.json")
+	if data, err := os.ReadFile(appJSON); err == nil && meta.Source == "" {
+		f, m := checkAppJSON(data, "app.json")
+		findings = append(findings, f...)
+		meta = m
+		meta.Source = "app.json"
+	}
+
+	// EAS build profiles used for store submission
+	findings = append(findings, checkEASConfig(projectPath)...)
+
+	// Try native iOS Info.plist locations
+	plistPaths := findInfoPlists(projectPath)
+	for _, ppath := range plistPaths {
+		if data, err := os.ReadFile(ppath); err == nil {
+			settings, _ := xcode.settingsFor(ppath)
+			f, m := checkInfoPlistLocal(data, ppath, projectPath, settings)
+			findings = append(findings, f...)
+			if meta.AppName == "" && m.AppName != "" {
+				meta.AppName = m.AppName
+			}
+			if meta.BundleID == "" && m.BundleID != "" {
+				meta.BundleID = m.BundleID
+			}
+			if meta.Version == "" && m.Version != "" {
+				meta.Version = m.Version
+			}
+			if meta.BuildNumber == "" && m.BuildNumber != "" {
+				meta.BuildNumber = m.BuildNumber
+			}
+		}
+	}
+
+	// Check for privacy policy file or URL in config
+	findings = append(findings, checkPrivacyPolicy(projectPath)...)
+	findings = append(findings, checkHealthPrivacyPolicy(projectPath)...)
+
+	// Asset catalogs and fonts that slow cold launch
+	findings = append(findings, checkLaunchAssets(projectPath)...)
+
+	// Dependency licenses and the acknowledgements that ship with them
+	findings = append(findings, checkLicenses(projectPath)...)
+
+	// GoogleService-Info.plist against the app and the Firebase SDKs it links
+	findings = append(findings, checkFirebaseConfig(projectPath, xcode, meta)...)
+
+	// Ad SDKs and the Info.plist entries they need
+	findings = append(findings, checkAdNetworks(projectPath)...)
+
+	return findings, meta
+}
+
+// expoConfig represents the relevant parts of app.json.
+type expoConfig struct {
+	Expo *struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Version     string `json:"version"`
+		IOS         *struct {
+			BundleIdentifier string                 `json:"bundleIdentifier"`
+			BuildNumber      string                 `json:"buildNumber"`
+			SupportsTablet   *bool                  `json:"supportsTablet"`
+			Icon             string                 `json:"icon"`
+			InfoPlist        map[string]interface{} `json:"infoPlist"`
+			PrivacyManifests interface{}            `json:"privacyManifests"`
+		} `json:"ios"`
+		Icon    string `json:"icon"`
+		Plugins []interface{} `json:"plugins"`
+	} `json:"expo"`
+}
+
+// checkAppJSON validates an Expo config. source names the file it came from
+// (app.json, or app.config.js when evaluated) for titles and locations.
+func checkAppJSON(data []byte, source string) ([]Finding, AppMeta) {
+	var findings []Finding
+	var meta AppMeta
+
+	var cfg expoConfig
+	if err := json.Unmarshal(data, &cfg); err != nil || cfg.Expo == nil {
+		return findings, meta
+	}
+
+	expo := cfg.Expo
+	meta.AppName = expo.Name
+	meta.Version = expo.Version
+
+	if expo.IOS != nil {
+		meta.BundleID = expo.IOS.BundleIdentifier
+		meta.BuildNumber = expo.IOS.BuildNumber
+	}
+
+	// Check: app name
+	if expo.Name == "" {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleAppNameMissing,
+			Severity:  severity.Critical,
+			Guideline: "2.3",
+			Title:     "App name is missing in " + source,
+			Detail:    "expo.name is empty. An app name is required for submission.",
+			Fix:       "Set \"name\" in your " + source + " expo config.",
+			File:      source,
+		})
+	}
+
+	// Check: description
+	if expo.Description == "" {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleAppDescriptionMissing,
+			Severity:  severity.Warn,
+			Guideline: "2.3",
+			Title:     "App description is missing in " + source,
+			Detail:    "expo.description is empty. While not strictly required in " + source + ", having no description makes it likely you'll forget it in App Store Connect too.",
+			Fix:       "Add a \"description\" field in your " + source + " for reference.",
+			File:      source,
+		})
+	}
+
+	// Check: version
+	if expo.Version == "" {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleAppVersionMissing,
+			Severity:  severity.Critical,
+			Guideline: "2.1",
+			Title:     "App version is missing in " + source,
+			Detail:    "expo.version is empty. A version string is required.",
+			Fix:       "Set \"version\" (e.g. \"1.0.0\") in your " + source + ".",
+			File:      source,
+		})
+	}
+
+	findings = append(findings, checkVersionFormat("expo.version", expo.Version, source)...)
+	findings = append(findings, checkVersionFormat("expo.ios.buildNumber", meta.BuildNumber, source)...)
+
+	// Check: bundle identifier
+	if expo.IOS != nil {
+		if expo.IOS.BundleIdentifier == "" {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleBundleIDMissing,
+				Severity:  severity.Critical,
+				Guideline: "2.1",
+				Title:     "iOS bundle identifier is missing",
+				Detail:    "expo.ios.bundleIdentifier is empty. Required for App Store submission.",
+				Fix:       "Set \"bundleIdentifier\" under expo.ios (e.g. \"com.company.appname\").",
+				File:      source,
+			})
+		} else {
+			// Validate bundle ID format
+			bundleIDPattern := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9]*(\.[a-zA-Z][a-zA-Z0-9]*)+$`)
+			if !bundleIDPattern.MatchString(expo.IOS.BundleIdentifier) {
+				findings = append(findings, Finding{
+					Source:    "metadata",
+					RuleID:    ruleBundleIDFormat,
+					Severity:  severity.Warn,
+					Guideline: "2.1",
+					Title:     "Bundle identifier format may be invalid",
+					Detail:    "\"" + expo.IOS.BundleIdentifier + "\" — bundle IDs should be reverse-domain notation (e.g. com.company.app).",
+					Fix:       "Use reverse-domain notation with only letters, numbers, and dots.",
+					File:      source,
+				})
+			}
+		}
+
+		// Check: app icon
+		icon := expo.IOS.Icon
+		if icon == "" {
+			icon = expo.Icon
+		}
+		if icon == "" {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleNoAppIcon,
+				Severity:  severity.Critical,
+				Guideline: "2.3",
+				Title:     "No app icon configured",
+				Detail:    "Neither expo.ios.icon nor expo.icon is set. An app icon is required.",
+				Fix:       "Set \"icon\" in your " + source + " to a 1024x1024 PNG image path.",
+				File:      source,
+			})
+		}
+		// Check: vague purpose strings in infoPlist
+		if expo.IOS.InfoPlist != nil {
+			vaguePurposeRe := regexp.MustCompile(`(?i)^(camera needed|location needed|microphone needed|photo access|access needed|needed|required|for the app|to function|for functionality)\.?$`)
+			shortPurposeMinLen := 20 // Purpose strings should explain WHY, not just WHAT
+			purposeKeys := []string{
+				"NSCameraUsageDescription",
+				"NSMicrophoneUsageDescription",
+				"NSPhotoLibraryUsageDescription",
+				"NSLocationWhenInUseUsageDescription",
+				"NSLocationAlwaysUsageDescription",
+				"NSUserTrackingUsageDescription",
+				"NSFaceIDUsageDescription",
+				"NSContactsUsageDescription",
+				"NSCalendarsUsageDescription",
+				"NSHealthShareUsageDescription",
+				"NSBluetoothAlwaysUsageDescription",
+				"NSMotionUsageDescription",
+			}
+			for _, key := range purposeKeys {
+				if val, ok := expo.IOS.InfoPlist[key]; ok {
+					if str, ok := val.(string); ok {
+						if vaguePurposeRe.MatchString(str) || len(str) < shortPurposeMinLen {
+							findings = append(findings, Finding{
+								Source:    "metadata",
+								RuleID:    ruleVaguePurposeString,
+								Severity:  severity.Warn,
+								Guideline: "5.1.1",
+								Title:     "Vague permission purpose string: " + key,
+								Detail:    "\"" + str + "\" is too vague. Apple requires specific, user-facing descriptions explaining why your app needs this permission.",
+								Fix:       "Rewrite the purpose string to explain specifically why your app needs this permission and how the data will be used.",
+								File:      source,
+							})
+						}
+					}
+				}
+			}
+		}
+
+	} else {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleNoIOSConfig,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "No iOS configuration in " + source,
+			Detail:    "expo.ios section is missing. iOS-specific settings are needed for App Store submission.",
+			Fix:       "Add an \"ios\" section to your expo config with at least bundleIdentifier and icon.",
+			File:      source,
+		})
+	}
+
+	return findings, meta
+}
+
+func checkInfoPlistLocal(data []byte, plistPath, projectPath string, settings xcodeproj.Configuration) ([]Finding, AppMeta) {
+	var findings []Finding
+	var meta AppMeta
+
+	content := string(data)
+	relPath, _ := filepath.Rel(projectPath, plistPath)
+
+	// Extract app name
+	nameRe := regexp.MustCompile(`CFBundleDisplayName</key>\s*<string>([^<]*)</string>`)
+	if m := nameRe.FindStringSubmatch(content); len(m) > 1 {
+		meta.AppName = m[1]
+	}
+
+	// Extract bundle ID
+	bundleRe := regexp.MustCompile(`CFBundleIdentifier</key>\s*<string>([^<]*)</string>`)
+	if m := bundleRe.FindStringSubmatch(content); len(m) > 1 {
+		meta.BundleID = m[1]
+	}
+
+	// Extract version
+	versionRe := regexp.MustCompile(`CFBundleShortVersionString</key>\s*<string>([^<]*)</string>`)
+	if m := versionRe.FindStringSubmatch(content); len(m) > 1 {
+		meta.Version = m[1]
+	}
+
+	// Extract build number
+	buildRe := regexp.MustCompile(`CFBundleVersion</key>\s*<string>([^<]*)</string>`)
+	if m := buildRe.FindStringSubmatch(content); len(m) > 1 {
+		meta.BuildNumber = m[1]
+	}
+
+	// Literal values are checked here; $(MARKETING_VERSION) and
+	// $(CURRENT_PROJECT_VERSION) are checked with the build settings.
+	if !strings.Contains(meta.Version, "$") {
+		findings = append(findings, checkVersionFormat("CFBundleShortVersionString", meta.Version, relPath)...)
+	}
+	if !strings.Contains(meta.BuildNumber, "$") {
+		findings = append(findings, checkVersionFormat("CFBundleVersion", meta.BuildNumber, relPath)...)
+	}
+
+	// Check for missing CFBundleDisplayName
+	if !strings.Contains(content, "CFBundleDisplayName") {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleDisplayNameMissing,
+			Severity:  severity.Warn,
+			Guideline: "2.3",
+			Title:     "CFBundleDisplayName missing from Info.plist",
+			Detail:    "The display name shown under the app icon is not set.",
+			Fix:       "Add CFBundleDisplayName to your Info.plist.",
+			File:      relPath,
+		})
+	}
+
+	// Resolve build-time substitutions ($(PRODUCT_BUNDLE_IDENTIFIER),
+	// $(PRODUCT_NAME), ...) with the selected configuration's settings, so
+	// checks see the values that actually ship.
+	if settings.Settings != nil {
+		undefined := map[string]bool{}
+		for _, v := range []*string{&meta.AppName, &meta.BundleID, &meta.Version, &meta.BuildNumber} {
+			if !strings.Contains(*v, "$") {
+				continue
+			}
+			resolved, missing := settings.Expand(*v)
+			for _, name := range missing {
+				undefined[name] = true
+			}
+			*v = resolved
+		}
+		for name := range undefined {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleUndefinedBuildSetting,
+				Severity:  severity.Warn,
+				Guideline: "2.1",
+				Title:     "Info.plist references undefined build setting $(" + name + ")",
+				Detail:    "$(" + name + ") is not defined for the " + settings.Name + " configuration in the project or its xcconfig files, so it expands to an empty string in the built app.",
+				Fix:       "Define " + name + " in the target's build settings or xcconfig, or replace the reference with a literal value.",
+				File:      relPath,
+			})
+		}
+		bundleIDPattern := regexp.MustCompile(`^[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)+$`)
+		if meta.BundleID != "" && !strings.Contains(meta.BundleID, "YOUR_") && !bundleIDPattern.MatchString(meta.BundleID) {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleInvalidBundleID,
+				Severity:  severity.Critical,
+				Guideline: "2.1",
+				Title:     "Invalid bundle identifier: " + meta.BundleID,
+				Detail:    "CFBundleIdentifier resolves to \"" + meta.BundleID + "\" for the " + settings.Name + " configuration. Bundle IDs may only contain letters, digits, hyphens and periods.",
+				Fix:       "Fix PRODUCT_BUNDLE_IDENTIFIER for the " + settings.Name + " configuration.",
+				File:      relPath,
+			})
+		}
+	}
+
+	// Check for empty or template values. Build setting references are fine
+	// for Xcode projects (build-time substitution); only flag placeholders
+	// left in the file or in the values they resolve to.
+	placeholder := strings.Contains(content, "YOUR_")
+	for _, v := range []string{meta.AppName, meta.BundleID, meta.Version} {
+		placeholder = placeholder || strings.Contains(v, "YOUR_")
+	}
+	if placeholder {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    rulePlistPlaceholder,
+			Severity:  severity.Warn,
+			Guideline: "2.1",
+			Title:     "Info.plist contains placeholder value: YOUR_",
+			Detail:    "Unreplaced template values will cause submission issues.",
+			Fix:       "Replace placeholder values with actual configuration.",
+			File:      relPath,
+		})
+	}
+
+	// Check purpose strings quality
+	purposeStrings := map[string]string{
+		"NSCameraUsageDescription":              "Camera",
+		"NSMicrophoneUsageDescription":          "Microphone",
+		"NSPhotoLibraryUsageDescription":        "Photo Library",
+		"NSLocationWhenInUseUsageDescription":   "Location",
+		"NSLocationAlwaysUsageDescription":      "Location (Always)",
+		"NSUserTrackingUsageDescription":        "User Tracking",
+		"NSFaceIDUsageDescription":              "Face ID",
+		"NSContactsUsageDescription":            "Contacts",
+		"NSCalendarsUsageDescription":           "Calendars",
+		"NSHealthShareUsageDescription":         "HealthKit",
+		"NSBluetoothAlwaysUsageDescription":     "Bluetooth",
+	}
+
+	for key, name := range purposeStrings {
+		if strings.Contains(content, key) {
+			emptyRe := regexp.MustCompile(key + `</key>\s*<string>\s*</string>`)
+			if emptyRe.MatchString(content) {
+				findings = append(findings, Finding{
+					Source:    "metadata",
+					RuleID:    ruleEmptyPurposeString,
+					Severity:  severity.Critical,
+					Guideline: "5.1.1",
+					Title:     name + " purpose string is empty in Info.plist",
+					Detail:    key + " is declared but has no description. Apple will reject this.",
+					Fix:       "Add a specific, user-facing description for why your app needs " + name + " access.",
+					File:      relPath,
+				})
+			}
+		}
+	}
+
+	return findings, meta
+}
+
+func findInfoPlists(projectPath string) []string {
+	var results []string
+	skipDirs := map[string]bool{
+		"node_modules": true, ".git": true, "Pods": true,
+		"build": true, "dist": true, ".expo": true,
+		"DerivedData": true, "vendor": true,
+	}
+
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.ToLower(info.Name()) == "info.plist" {
+			// Skip test/example plists
+			rel, _ := filepath.Rel(projectPath, path)
+			lower := strings.ToLower(rel)
+			if !strings.Contains(lower, "test") && !strings.Contains(lower, "example") {
+				results = append(results, path)
+			}
+		}
+		return nil
+	})
+
+	return results
+}
+
+func checkPrivacyPolicy(projectPath string) []Finding {
+	var findings []Finding
+
+	// Check app.json for privacy policy
+	appJSON := filepath.Join(projectPath, "app.json")
+	if data, err := os.ReadFile(appJSON); err == nil {
+		content := string(data)
+		// Expo doesn't have a direct privacyPolicy field, but check for it in plugins or extra
+		hasPrivacyURL := strings.Contains(content, "privacyPolicyUrl") ||
+			strings.Contains(content, "privacy_policy") ||
+			strings.Contains(content, "privacyUrl")
+
+		if !hasPrivacyURL {
+			findings = append(findings, Finding{
+				Source:    "metadata",
+				RuleID:    ruleNoPrivacyPolicyURL,
+				Severity:  severity.Warn,
+				Guideline: "5.1.1",
+				Title:     "No privacy policy URL found in project config",
+				Detail:    "A privacy policy URL is required for App Store submission. It wasn't found in app.json. You'll need to set it in App Store Connect.",
+				Fix:       "Create a privacy policy page and add the URL in App Store Connect → App Information.",
+			})
+		}
+	}
+
+	return findings
+}
+
+// checkHealthPrivacyPolicy verifies that apps using HealthKit disclose health
+// data handling in any privacy policy document shipped with the project.
+func checkHealthPrivacyPolicy(projectPath string) []Finding {
+	var findings []Finding
+	usesHealth := false
+	var policies []string
+
+	skipDirs := map[string]bool{
+		"node_modules": true, ".git": true, "Pods": true,
+		"build": true, "dist": true, ".expo": true,
+		"DerivedData": true, "vendor": true,
+	}
+
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := strings.ToLower(info.Name())
+		ext := filepath.Ext(name)
+		switch {
+		case ext == ".entitlements" || name == "info.plist" || name == "app.json":
+			if data, err := os.ReadFile(path); err == nil {
+				content := string(data)
+				if strings.Contains(content, "com.apple.developer.healthkit") || strings.Contains(content, "NSHealthShareUsageDescription") {
+					usesHealth = true
+				}
+			}
+		case strings.Contains(name, "privacy") && (ext == ".md" || ext == ".html" || ext == ".txt"):
+			policies = append(policies, path)
+		}
+		return nil
+	})
+
+	if !usesHealth {
+		return nil
+	}
+
+	if len(policies) == 0 {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleHealthPrivacyPolicy,
+			Severity:  severity.Info,
+			Guideline: "5.1.3",
+			Title:     "HealthKit app: confirm privacy policy covers health data",
+			Detail:    "The app uses HealthKit but no privacy policy document was found in the project to verify. Apple requires the privacy policy to describe how health data is collected, used, and shared.",
+			Fix:       "Make sure the privacy policy linked in App Store Connect explicitly covers health and fitness data.",
+		})
+		return findings
+	}
+
+	for _, p := range policies {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		lower := strings.ToLower(string(data))
+		if strings.Contains(lower, "health") {
+			return findings
+		}
+	}
+
+	relPath, _ := filepath.Rel(projectPath, policies[0])
+	findings = append(findings, Finding{
+		Source:    "metadata",
+		RuleID:    ruleHealthPrivacyPolicyMissing,
+		Severity:  severity.Warn,
+		Guideline: "5.1.3",
+		Title:     "Privacy policy does not mention health data",
+		Detail:    "The app uses HealthKit, but the project's privacy policy never mentions health data. Apple rejects health apps whose privacy policy doesn't disclose health data use.",
+		Fix:       "Add a section to the privacy policy describing what health data is read or written, why, and that it is not used for advertising or stored in iCloud.",
+		File:      relPath,
+	})
+	return findings
+}
+
+// checkVersionFormat flags a version or build number that App Store Connect
+// rejects at upload. Empty values are reported by the missing-key checks.
+func checkVersionFormat(key, value, file string) []Finding {
+	if value == "" {
+		return nil
+	}
+	problem := appversion.Validate(value)
+	if problem == "" {
+		return nil
+	}
+	return []Finding{{
+		Source:    "metadata",
+		RuleID:    ruleInvalidVersion,
+		Severity:  severity.Critical,
+		Guideline: "2.1",
+		Title:     key + " \"" + value + "\" will be rejected at upload",
+		Detail:    "App Store Connect only accepts up to three period-separated integers (e.g. 1.4.2) as the version and build number; " + problem + ".",
+		Fix:       "Change " + key + " to a value like 1.4.2.",
+		File:      file,
+	}}
+}
@@ -0,0 +1,89 @@
+package preflight
+
+import (
+	"context"
+
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() {
+	scan.Register(metadataScanner{})
+	scan.Register(xcodeScanner{})
+}
+
+// metadataScanner checks the app name, bundle ID, version and icons in the
+// project's config files.
+type metadataScanner struct{}
+
+func (metadataScanner) Name() string { return "metadata" }
+
+func (metadataScanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	xcode, err := targetXcode(t)
+	if err != nil {
+		return nil, err
+	}
+	findings, meta := CheckLocalMetadata(t.ProjectPath, xcode)
+	t.Facts.Update(func(facts *scan.Facts) {
+		if facts.AppName == "" {
+			facts.AppName = meta.AppName
+		}
+		if facts.BundleID == "" {
+			facts.BundleID = meta.BundleID
+		}
+	})
+	return findings, nil
+}
+
+// xcodeScanner checks the build settings of the selected Xcode builds.
+type xcodeScanner struct{}
+
+func (xcodeScanner) Name() string { return "xcode" }
+
+func (xcodeScanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	xcode, err := targetXcode(t)
+	if err != nil {
+		return nil, err
+	}
+	// A project that fails to check doesn't stop the others; the first
+	// error is returned with the findings of the rest.
+	var (
+		out      []scan.Finding
+		firstErr error
+	)
+	for i, proj := range xcode.projects {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		findings, err := xcodeproj.Check(proj, t.ProjectPath, xcode.sels[i])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, f := range findings {
+			out = append(out, scan.Finding{
+				Source:    "xcode",
+				Severity:  f.Severity,
+				Guideline: f.Guideline,
+				Title:     f.Title,
+				Detail:    f.Detail,
+				Fix:       f.Fix,
+				File:      f.File,
+			})
+		}
+	}
+	return out, firstErr
+}
+
+// targetXcode loads the target's Xcode builds once per run.
+func targetXcode(t scan.Target) (*xcodeBuilds, error) {
+	v, err := t.Cache.Load("preflight.xcode", func() (any, error) {
+		return loadXcodeBuilds(t.ProjectPath, t.Build)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*xcodeBuilds), nil
+}
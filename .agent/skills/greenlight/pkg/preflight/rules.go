@@ -0,0 +1,115 @@
+package preflight
+
+// Rule IDs of the metadata scanner's findings. They name the kind of finding
+// only; bundle IDs, keys, file names and other values stay in the title and
+// detail, so baselines and suppressions match every occurrence.
+const (
+	ruleAppDescriptionMissing      = "metadata/app-description-missing"
+	ruleAppNameMissing             = "metadata/app-name-missing"
+	ruleAppVersionMissing          = "metadata/app-version-missing"
+	ruleATTWithoutPurposeString    = "metadata/att-without-purpose-string"
+	ruleBundleIDFormat             = "metadata/bundle-id-format"
+	ruleBundleIDMissing            = "metadata/bundle-id-missing"
+	ruleCopyleftDependency         = "metadata/copyleft-dependency"
+	ruleDeprecatedConversionValue  = "metadata/deprecated-conversion-value-api"
+	ruleDisplayNameMissing         = "metadata/display-name-missing"
+	ruleEASDebugConfiguration      = "metadata/eas-debug-configuration"
+	ruleEASDevelopmentClient       = "metadata/eas-development-client"
+	ruleEASInternalDistribution    = "metadata/eas-internal-distribution"
+	ruleEASInvalidJSON             = "metadata/eas-invalid-json"
+	ruleEASNoAutoIncrement         = "metadata/eas-no-auto-increment"
+	ruleEASNoProductionProfile     = "metadata/eas-no-production-profile"
+	ruleEASSimulatorBuild          = "metadata/eas-simulator-build"
+	ruleEmptyPurposeString         = "metadata/empty-purpose-string"
+	ruleExpoConfigNotEvaluated     = "metadata/expo-config-not-evaluated"
+	ruleFirebaseAdPersonalization  = "metadata/firebase-ad-personalization-without-att"
+	ruleFirebaseAnalyticsDisabled  = "metadata/firebase-analytics-never-enabled"
+	ruleFirebaseAPNsToken          = "metadata/firebase-apns-token-not-passed"
+	ruleFirebaseBundleIDMismatch   = "metadata/firebase-bundle-id-mismatch"
+	ruleFirebaseInvalidAppID       = "metadata/firebase-invalid-app-id"
+	ruleFirebaseMalformedAPIKey    = "metadata/firebase-malformed-api-key"
+	ruleFirebaseMessagingNoPush    = "metadata/firebase-messaging-without-push"
+	ruleFirebasePlaceholders       = "metadata/firebase-placeholder-values"
+	ruleFirebasePlistMissing       = "metadata/firebase-plist-missing"
+	ruleFirebasePlistNotInProject  = "metadata/firebase-plist-not-in-project"
+	ruleFirebaseSharedAPIKey       = "metadata/firebase-shared-api-key"
+	ruleGADAppIDInvalid            = "metadata/gad-app-id-invalid"
+	ruleGADAppIDMissing            = "metadata/gad-app-id-missing"
+	ruleGADSampleAppID             = "metadata/gad-sample-app-id"
+	ruleHealthPrivacyPolicy        = "metadata/health-privacy-policy"
+	ruleHealthPrivacyPolicyMissing = "metadata/health-privacy-policy-missing-health"
+	ruleInvalidBundleID            = "metadata/invalid-bundle-id"
+	ruleInvalidVersion             = "metadata/invalid-version"
+	ruleLargeAssetCatalog          = "metadata/large-asset-catalog"
+	ruleLargeFont                  = "metadata/large-font"
+	ruleLargeFontTotal             = "metadata/large-font-total"
+	ruleNoAcknowledgements         = "metadata/no-acknowledgements"
+	ruleNoAppIcon                  = "metadata/no-app-icon"
+	ruleNoIOSConfig                = "metadata/no-ios-config"
+	ruleNoPrivacyPolicyURL         = "metadata/no-privacy-policy-url"
+	rulePlistPlaceholder           = "metadata/plist-placeholder"
+	ruleSKAdNetworkIDMissing       = "metadata/skadnetwork-id-missing"
+	ruleSKAdNetworkItemsMalformed  = "metadata/skadnetwork-items-malformed"
+	ruleSKAdNetworkItemsMissing    = "metadata/skadnetwork-items-missing"
+	ruleTrackingPurposeUnused      = "metadata/tracking-purpose-without-request"
+	ruleUndefinedBuildSetting      = "metadata/undefined-build-setting"
+	ruleVaguePurposeString         = "metadata/vague-purpose-string"
+	ruleWeakCopyleftDependency     = "metadata/weak-copyleft-dependency"
+)
+
+// RuleIDs lists the rule IDs of the metadata scanner's findings.
+func RuleIDs() []string {
+	return []string{
+		ruleAppDescriptionMissing,
+		ruleAppNameMissing,
+		ruleAppVersionMissing,
+		ruleATTWithoutPurposeString,
+		ruleBundleIDFormat,
+		ruleBundleIDMissing,
+		ruleCopyleftDependency,
+		ruleDeprecatedConversionValue,
+		ruleDisplayNameMissing,
+		ruleEASDebugConfiguration,
+		ruleEASDevelopmentClient,
+		ruleEASInternalDistribution,
+		ruleEASInvalidJSON,
+		ruleEASNoAutoIncrement,
+		ruleEASNoProductionProfile,
+		ruleEASSimulatorBuild,
+		ruleEmptyPurposeString,
+		ruleExpoConfigNotEvaluated,
+		ruleFirebaseAdPersonalization,
+		ruleFirebaseAnalyticsDisabled,
+		ruleFirebaseAPNsToken,
+		ruleFirebaseBundleIDMismatch,
+		ruleFirebaseInvalidAppID,
+		ruleFirebaseMalformedAPIKey,
+		ruleFirebaseMessagingNoPush,
+		ruleFirebasePlaceholders,
+		ruleFirebasePlistMissing,
+		ruleFirebasePlistNotInProject,
+		ruleFirebaseSharedAPIKey,
+		ruleGADAppIDInvalid,
+		ruleGADAppIDMissing,
+		ruleGADSampleAppID,
+		ruleHealthPrivacyPolicy,
+		ruleHealthPrivacyPolicyMissing,
+		ruleInvalidBundleID,
+		ruleInvalidVersion,
+		ruleLargeAssetCatalog,
+		ruleLargeFont,
+		ruleLargeFontTotal,
+		ruleNoAcknowledgements,
+		ruleNoAppIcon,
+		ruleNoIOSConfig,
+		ruleNoPrivacyPolicyURL,
+		rulePlistPlaceholder,
+		ruleSKAdNetworkIDMissing,
+		ruleSKAdNetworkItemsMalformed,
+		ruleSKAdNetworkItemsMissing,
+		ruleTrackingPurposeUnused,
+		ruleUndefinedBuildSetting,
+		ruleVaguePurposeString,
+		ruleWeakCopyleftDependency,
+	}
+}
@@ -0,0 +1,270 @@
+// Package preflight runs every registered scanner (see package scan) —
+// metadata, codescan, privacy, Xcode build settings and optionally an IPA —
+// and merges the results into one report with stable finding IDs.
+//
+//	result, err := preflight.Run(ctx, "./my-app", "", preflight.BuildSelection{})
+//	if err == nil && !result.Summary.Passed { ... }
+package preflight
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/codeowners"
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/internal/findingid"
+	"github.com/RevylAI/greenlight/internal/guidelines"
+	"github.com/RevylAI/greenlight/internal/risk"
+	"github.com/RevylAI/greenlight/internal/triage"
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+	"github.com/RevylAI/greenlight/pkg/codescan"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+
+	// Register the App Store Connect, endpoints, ipa, privacy and vulns
+	// scanners.
+	_ "github.com/RevylAI/greenlight/internal/checks"
+	_ "github.com/RevylAI/greenlight/pkg/endpoints"
+	_ "github.com/RevylAI/greenlight/pkg/ipa"
+	_ "github.com/RevylAI/greenlight/pkg/privacy"
+	_ "github.com/RevylAI/greenlight/pkg/vulns"
+)
+
+// Finding is the unified finding type across all scanners.
+type Finding = scan.Finding
+
+// Result holds the combined output from all scanners.
+type Result struct {
+	ProjectPath string    `json:"project_path"`
+	IPAPath     string    `json:"ipa_path,omitempty"`
+	Findings    []Finding `json:"findings"`
+	Summary     Summary   `json:"summary"`
+	Elapsed     time.Duration `json:"elapsed"`
+
+	// Extra context from sub-scanners
+	AppName        string   `json:"app_name,omitempty"`
+	BundleID       string   `json:"bundle_id,omitempty"`
+	HasPrivacyInfo bool     `json:"has_privacy_info"`
+	DetectedAPIs   []string `json:"detected_apis,omitempty"`
+	TrackingSDKs   []string `json:"tracking_sdks,omitempty"`
+}
+
+// Summary provides aggregate counts.
+type Summary struct {
+	Total    int  `json:"total"`
+	Critical int  `json:"critical"`
+	Warns    int  `json:"warns"`
+	Infos    int  `json:"infos"`
+	Passed   bool `json:"passed"` // true if zero CRITICALs
+	// RiskScore is the estimated likelihood, from 0 to 100, that the app is
+	// rejected for any of the findings; Grade rates it from A to F.
+	RiskScore int    `json:"risk_score"`
+	Grade     string `json:"grade"`
+}
+
+// BuildSelection picks the Xcode scheme and build configuration whose
+// settings preflight checks.
+type BuildSelection = xcodeproj.Selection
+
+// Run executes all scanners and returns a unified result. build selects the
+// Xcode scheme and configuration whose settings are checked; leave it empty
+// to check every Release-like configuration. Cancelling ctx stops the
+// scanners and Run returns ctx.Err().
+func Run(ctx context.Context, projectPath string, ipaPath string, build BuildSelection) (*Result, error) {
+	return RunTarget(ctx, scan.Target{
+		ProjectPath: projectPath,
+		IPAPath:     ipaPath,
+		Build:       build,
+	})
+}
+
+// RunTarget is Run for a full scan target, so App Store Connect checks can
+// run alongside the local ones when target has a Client and AppID.
+func RunTarget(ctx context.Context, target scan.Target) (*Result, error) {
+	result := &Result{
+		ProjectPath: target.ProjectPath,
+		IPAPath:     target.IPAPath,
+	}
+
+	projectCfg, err := config.LoadProject(target.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	overrides, _ := projectCfg.RuleOverrides()
+	scanners := enabledScanners(target, projectCfg)
+	configured, err := scan.NewPaths(projectCfg.Paths.Include, projectCfg.Paths.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	configured.FirstParty = projectCfg.Paths.FirstParty
+	target.Paths = configured.Merge(target.Paths)
+
+	if target.Facts == nil {
+		target.Facts = &scan.Facts{}
+	}
+	if target.Cache == nil {
+		target.Cache = &scan.Cache{}
+	}
+	facts := target.Facts
+	// Load the Xcode builds up front: a scheme or configuration that
+	// matches nothing is an error, not a scanner warning.
+	if _, err := targetXcode(target); err != nil {
+		return nil, err
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, s := range scanners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer logScanner(ctx, s.Name(), time.Now())
+			findings, err := s.Run(ctx, target)
+			if err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "preflight scanner failed", "scanner", s.Name(), "error", err)
+			}
+			mu.Lock()
+			result.Findings = append(result.Findings, findings...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result.AppName = facts.AppName
+	result.BundleID = facts.BundleID
+	result.HasPrivacyInfo = facts.HasPrivacyInfo
+	result.DetectedAPIs = facts.DetectedAPIs
+	result.TrackingSDKs = facts.TrackingSDKs
+
+	result.Findings = config.ApplyOverrides(result.Findings, overrides, func(f *Finding) (string, *severity.Level) {
+		return f.RuleID, &f.Severity
+	})
+
+	// Deduplicate findings with the same title from different scanners;
+	// sorting first makes the kept copy the same on every run.
+	SortFindings(result.Findings)
+	result.Findings = dedup(result.Findings)
+	owners, err := codeowners.Load(target.ProjectPath)
+	if err != nil {
+		slog.DebugContext(ctx, "preflight CODEOWNERS not read", "err", err)
+	}
+	kept := result.Findings[:0]
+	for _, f := range result.Findings {
+		if f.Owners == nil {
+			f.Owners = owners.Owners(f.File)
+		}
+		f.GuidelineTitle, f.GuidelineURL = guidelines.Reference(f.Guideline)
+		f.RuleID = findingid.RuleID(f.Source, f.RuleID, f.Title)
+		f.Fingerprint = findingid.Fingerprint(f.RuleID, f.File, f.Code, f.Title)
+		f.Risk = risk.Score(f.Severity, f.Guideline)
+		hint := triage.For(f.Source, f.RuleID, f.Check, f.Guideline)
+		f.Effort, f.Owner = string(hint.Effort), string(hint.Owner)
+		if target.Selection.Keeps(f.Guideline, f.Source, f.Check, f.RuleID) {
+			kept = append(kept, f)
+		}
+	}
+	result.Findings = kept
+
+	// Compute summary
+	result.Summary = computeSummary(result.Findings)
+
+	return result, nil
+}
+
+// Scanners returns the scanners RunTarget would run for target: those that
+// apply to it, are not turned off in the project's .greenlight.yml and are
+// selected by target.Selection.
+func Scanners(target scan.Target) ([]scan.Scanner, error) {
+	projectCfg, err := config.LoadProject(target.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	return enabledScanners(target, projectCfg), nil
+}
+
+func enabledScanners(target scan.Target, projectCfg *config.ProjectConfig) []scan.Scanner {
+	var names []string
+	for _, s := range scan.All() {
+		names = append(names, s.Name())
+	}
+	var scanners []scan.Scanner
+	for _, s := range scan.For(target) {
+		if projectCfg.ScannerEnabled(s.Name()) && target.Selection.Runs(s.Name(), names) {
+			scanners = append(scanners, s)
+		}
+	}
+	return scanners
+}
+
+// SortFindings puts findings in report order: severity, then source, file,
+// line and title.
+func SortFindings(findings []Finding) {
+	scan.SortFindings(findings)
+}
+
+// logScanner traces how long one of Run's scanners took.
+func logScanner(ctx context.Context, name string, start time.Time) {
+	slog.DebugContext(ctx, "preflight scanner finished", "scanner", name, "duration", time.Since(start))
+}
+
+func computeSummary(findings []Finding) Summary {
+	s := Summary{}
+	var scores []int
+	for _, f := range findings {
+		s.Total++
+		scores = append(scores, f.Risk)
+		switch f.Severity {
+		case severity.Critical:
+			s.Critical++
+		case severity.Warn:
+			s.Warns++
+		case severity.Info:
+			s.Infos++
+		}
+	}
+	s.Passed = s.Critical == 0
+	s.RiskScore = risk.Overall(scores)
+	s.Grade = risk.Grade(s.RiskScore)
+	return s
+}
+
+// dedup removes findings with the same title, keeping the highest severity.
+func dedup(findings []Finding) []Finding {
+	seen := make(map[string]int) // title -> index in result
+	var result []Finding
+
+	for _, f := range findings {
+		if idx, ok := seen[f.Title]; ok {
+			// Keep higher severity
+			if f.Severity > result[idx].Severity {
+				result[idx] = f
+			}
+			continue
+		}
+		seen[f.Title] = len(result)
+		result = append(result, f)
+	}
+	return result
+}
+
+// RedactSecrets masks matched credentials in every finding's code snippet.
+func (r *Result) RedactSecrets() {
+	var secrets []string
+	for _, f := range r.Findings {
+		if f.Secret != "" {
+			secrets = append(secrets, f.Secret)
+		}
+	}
+	for i := range r.Findings {
+		for _, secret := range secrets {
+			r.Findings[i].Code = codescan.Redact(r.Findings[i].Code, secret)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/adnetworks"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+var (
+	// skAdNetworkItemsRe captures the value of SKAdNetworkItems, or of
+	// AdNetworkIdentifiers, the AdAttributionKit equivalent.
+	skAdNetworkItemsRe = regexp.MustCompile(`(?s)<key>(SKAdNetworkItems|AdNetworkIdentifiers)</key>\s*(<array>(.*?)</array>|<array\s*/>|<[a-z]+)`)
+	// skAdNetworkEntryRe matches an array element: a dict or a bare string.
+	skAdNetworkEntryRe = regexp.MustCompile(`(?s)<dict>(.*?)</dict>|<string>([^<]*)</string>`)
+	skAdNetworkKeyRe   = regexp.MustCompile(`<key>SKAdNetworkIdentifier</key>\s*<string>([^<]*)</string>`)
+	// validSKAdNetworkIDRe is the identifier format StoreKit matches
+	// against: ten lowercase alphanumerics and the .skadnetwork suffix.
+	validSKAdNetworkIDRe = regexp.MustCompile(`^[a-z0-9]{10}\.skadnetwork$`)
+
+	// deprecatedSKANRe matches SKAdNetwork calls Apple has deprecated.
+	deprecatedSKANRe = regexp.MustCompile(`registerAppForAdNetworkAttribution|\bupdateConversionValue\(`)
+	// adConsentFlowRe matches ad SDK consent flows that show the App
+	// Tracking Transparency prompt on the app's behalf.
+	adConsentFlowRe = regexp.MustCompile(`termsAndPrivacyPolicyFlowSettings|consentFlowSettings|UMPConsentInformation|ConsentInformation\.shared|AdsConsent\.|requestInfoUpdate`)
+)
+
+// skAdNetworkConfig is the SKAdNetworkItems (and AdNetworkIdentifiers) an
+// app declares.
+type skAdNetworkConfig struct {
+	// declared is set when either key is present in some config.
+	declared bool
+	// ids maps each identifier (lowercase) to the file declaring it.
+	ids map[string]string
+	// problems maps files to their malformed entries.
+	problems map[string][]string
+}
+
+// parseSKAdNetworkPlist reads the SKAdNetwork identifiers an Info.plist
+// declares, noting entries StoreKit can't use.
+func (c *skAdNetworkConfig) parseSKAdNetworkPlist(content, rel string) {
+	for _, m := range skAdNetworkItemsRe.FindAllStringSubmatch(content, -1) {
+		c.declared = true
+		key := m[1]
+		if !strings.HasPrefix(m[2], "<array") {
+			c.problems[rel] = append(c.problems[rel], key+" is not an array")
+			continue
+		}
+		for _, e := range skAdNetworkEntryRe.FindAllStringSubmatch(m[3], -1) {
+			switch {
+			case key == "AdNetworkIdentifiers":
+				// An array of identifier strings.
+				if strings.HasPrefix(e[0], "<dict") {
+					c.problems[rel] = append(c.problems[rel], "AdNetworkIdentifiers entries must be strings")
+					continue
+				}
+				c.add(e[2], rel)
+			case strings.HasPrefix(e[0], "<string"):
+				c.problems[rel] = append(c.problems[rel], fmt.Sprintf("%q is a bare string, not a dictionary with an SKAdNetworkIdentifier key", strings.TrimSpace(e[2])))
+			default:
+				id := skAdNetworkKeyRe.FindStringSubmatch(e[1])
+				if id == nil {
+					c.problems[rel] = append(c.problems[rel], "an SKAdNetworkItems entry has no SKAdNetworkIdentifier key")
+					continue
+				}
+				c.add(id[1], rel)
+			}
+		}
+	}
+}
+
+// parseSKAdNetworkJSON reads the identifiers in an Expo config:
+// ios.infoPlist.SKAdNetworkItems, and the plain list the Google Mobile Ads
+// config plugin takes (sk_ad_network_items). Dynamic configs can't be
+// parsed, so any identifiers they mention count as declared.
+func (c *skAdNetworkConfig) parseSKAdNetworkJSON(content, rel string) {
+	var cfg struct {
+		Expo struct {
+			IOS struct {
+				InfoPlist map[string]interface{} `json:"infoPlist"`
+			} `json:"ios"`
+		} `json:"expo"`
+	}
+	if !strings.HasSuffix(rel, ".json") || json.Unmarshal([]byte(content), &cfg) != nil {
+		if strings.Contains(content, "SKAdNetworkItems") || strings.Contains(content, "sk_ad_network_items") {
+			c.declared = true
+			for _, id := range skAdNetworkIDRe.FindAllString(content, -1) {
+				c.add(id, rel)
+			}
+		}
+		return
+	}
+	if items, ok := cfg.Expo.IOS.InfoPlist["SKAdNetworkItems"]; ok {
+		c.declared = true
+		list, _ := items.([]interface{})
+		if list == nil {
+			c.problems[rel] = append(c.problems[rel], "SKAdNetworkItems is not an array")
+		}
+		for _, item := range list {
+			switch v := item.(type) {
+			case map[string]interface{}:
+				id, ok := v["SKAdNetworkIdentifier"].(string)
+				if !ok {
+					c.problems[rel] = append(c.problems[rel], "an SKAdNetworkItems entry has no SKAdNetworkIdentifier key")
+					continue
+				}
+				c.add(id, rel)
+			case string:
+				c.problems[rel] = append(c.problems[rel], fmt.Sprintf("%q is a bare string, not an object with an SKAdNetworkIdentifier key", v))
+			}
+		}
+	}
+	// The plugin's list sits at the top level of app.json.
+	if strings.Contains(content, `"sk_ad_network_items"`) {
+		c.declared = true
+		for _, id := range skAdNetworkIDRe.FindAllString(content, -1) {
+			c.add(id, rel)
+		}
+	}
+}
+
+// add records an identifier, noting duplicates and malformed ones.
+func (c *skAdNetworkConfig) add(id, rel string) {
+	switch {
+	case validSKAdNetworkIDRe.MatchString(id):
+	case validSKAdNetworkIDRe.MatchString(strings.ToLower(strings.TrimSpace(id))):
+		c.problems[rel] = append(c.problems[rel], fmt.Sprintf("%q must be lowercase without surrounding whitespace", id))
+	default:
+		c.problems[rel] = append(c.problems[rel], fmt.Sprintf("%q is not an SKAdNetwork identifier (ten lowercase letters or digits, then .skadnetwork)", id))
+		return
+	}
+	id = strings.ToLower(strings.TrimSpace(id))
+	if prev, ok := c.ids[id]; ok {
+		if prev == rel {
+			c.problems[rel] = append(c.problems[rel], id+" is listed more than once")
+		}
+		return
+	}
+	c.ids[id] = rel
+}
+
+// checkSKAdNetwork checks that an app bundling ad SDKs is ready for
+// SKAdNetwork and AdAttributionKit attribution: the identifiers are
+// declared and well-formed, the App Tracking Transparency prompt and its
+// purpose string come together, and conversion values aren't updated
+// through deprecated APIs.
+func checkSKAdNetwork(projectPath string, networks []adnetworks.Detected, cfg adConfig) []Finding {
+	var findings []Finding
+
+	if !cfg.skan.declared {
+		var names []string
+		for _, n := range networks {
+			names = append(names, n.Name)
+		}
+		file, line := cfg.plist, 0
+		if file == "" {
+			file, line = networks[0].File, networks[0].Line
+		}
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleSKAdNetworkItemsMissing,
+			Severity:  severity.Info,
+			Guideline: "5.1.2",
+			Title:     "SKAdNetworkItems missing from Info.plist",
+			Detail:    "The app bundles " + strings.Join(names, ", ") + " but declares no SKAdNetwork identifiers. SKAdNetwork and AdAttributionKit only attribute installs to the ad networks an app declares, and without App Tracking Transparency consent that is the only attribution the networks get, so they bid far less for the app's ad inventory.",
+			Fix:       "Run `greenlight skadnetwork` to generate SKAdNetworkItems for the detected networks, add it to Info.plist (or expo.ios.infoPlist), and complete it with each network's partner list.",
+			File:      file,
+			Line:      line,
+		})
+	}
+
+	files := make([]string, 0, len(cfg.skan.problems))
+	for file := range cfg.skan.problems {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		problems := cfg.skan.problems[file]
+		list := strings.Join(problems[:min(len(problems), 5)], "; ")
+		if len(problems) > 5 {
+			list += fmt.Sprintf("; and %d more", len(problems)-5)
+		}
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleSKAdNetworkItemsMalformed,
+			Severity:  severity.Info,
+			Guideline: "5.1.2",
+			Title:     "Malformed SKAdNetworkItems entries",
+			Detail:    "StoreKit ignores entries it can't read, so installs from those networks go unattributed: " + list + ".",
+			Fix:       "Make each entry a dictionary with an SKAdNetworkIdentifier string in lowercase, or regenerate the list with `greenlight skadnetwork`.",
+			File:      file,
+		})
+	}
+
+	code := scanAdCode(projectPath)
+	purpose := cfg.trackingPurpose != ""
+	switch {
+	case code.requestsTracking != "" && !purpose:
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleATTWithoutPurposeString,
+			Severity:  severity.Critical,
+			Guideline: "5.1.1",
+			Title:     "App Tracking Transparency requested without NSUserTrackingUsageDescription",
+			Detail:    "The app asks for tracking permission, but Info.plist has no NSUserTrackingUsageDescription, so the prompt can't be shown and the app is rejected for the missing purpose string.",
+			Fix:       "Add NSUserTrackingUsageDescription explaining what tracking the ads do, e.g. \"Your data will be used to show you more relevant ads.\"",
+			File:      code.requestsTracking,
+		})
+	case purpose && code.requestsTracking == "" && !code.consentFlow:
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleTrackingPurposeUnused,
+			Severity:  severity.Warn,
+			Guideline: "5.1.2",
+			Title:     "NSUserTrackingUsageDescription set but tracking permission is never requested",
+			Detail:    "Info.plist describes why the app tracks, but no code calls requestTrackingAuthorization and no ad SDK consent flow is configured. Reviewers reject apps whose App Tracking Transparency prompt they can't find, and the ad SDKs never get the IDFA.",
+			Fix:       "Request tracking permission (ATTrackingManager.requestTrackingAuthorization, expo-tracking-transparency) before the ad SDKs start, or enable the SDK's consent flow; remove the purpose string if the app doesn't track.",
+			File:      cfg.trackingPurpose,
+		})
+	}
+
+	if code.deprecatedSKAN != "" {
+		findings = append(findings, Finding{
+			Source:    "metadata",
+			RuleID:    ruleDeprecatedConversionValue,
+			Severity:  severity.Info,
+			Guideline: "2.5.1",
+			Title:     "Deprecated SKAdNetwork conversion value API",
+			Detail:    "registerAppForAdNetworkAttribution() and updateConversionValue(_:) are deprecated and only report SKAdNetwork 3 postbacks, without the coarse values and later conversion windows of SKAdNetwork 4 and AdAttributionKit.",
+			Fix:       "Use SKAdNetwork.updatePostbackConversionValue(_:coarseValue:lockWindow:), or Postback.updateConversionValue in AdAttributionKit (iOS 17.4+).",
+			File:      code.deprecatedSKAN,
+			Line:      code.deprecatedLine,
+		})
+	}
+	return findings
+}
+
+// adCode is what the app's code does about tracking and attribution.
+type adCode struct {
+	requestsTracking string // a file requesting tracking permission
+	consentFlow      bool   // an ad SDK consent flow shows the prompt
+	deprecatedSKAN   string // a file calling deprecated SKAdNetwork APIs
+	deprecatedLine   int
+}
+
+func scanAdCode(projectPath string) adCode {
+	exts := map[string]bool{
+		".swift": true, ".m": true, ".mm": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	}
+	var code adCode
+	sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if licenseSkipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !exts[strings.ToLower(filepath.Ext(path))] || info.Size() > 1<<20 {
+			return nil
+		}
+		lines, err := sourcefile.ReadLines(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(projectPath, path)
+		rel = filepath.ToSlash(rel)
+		for i, line := range lines {
+			if strings.HasPrefix(strings.TrimSpace(line), "//") {
+				continue
+			}
+			if code.requestsTracking == "" && trackingRequestRe.MatchString(line) {
+				code.requestsTracking = rel
+			}
+			if adConsentFlowRe.MatchString(line) {
+				code.consentFlow = true
+			}
+			if code.deprecatedSKAN == "" && deprecatedSKANRe.MatchString(line) {
+				code.deprecatedSKAN, code.deprecatedLine = rel, i+1
+			}
+		}
+		return nil
+	})
+	return code
+}
+
+// SKAdNetworkIDs returns the SKAdNetwork identifiers the project under
+// projectPath declares in its Info.plists and Expo config.
+func SKAdNetworkIDs(projectPath string) []string {
+	cfg := readAdConfig(projectPath)
+	ids := make([]string, 0, len(cfg.skan.ids))
+	for id := range cfg.skan.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
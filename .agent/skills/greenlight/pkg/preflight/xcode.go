@@ -0,0 +1,99 @@
+package preflight
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+)
+
+// xcodeBuilds is the Xcode side of a preflight run: the projects to check,
+// each with the selection that applies to it, and the effective build
+// settings keyed by the Info.plist they are used with.
+type xcodeBuilds struct {
+	projects []*xcodeproj.Project
+	sels     []xcodeproj.Selection
+	plists   map[string]xcodeproj.Configuration
+}
+
+// loadXcodeBuilds loads the Xcode projects under projectPath and resolves the
+// build settings for sel. A scheme only has to exist in one of the projects;
+// projects without it are skipped. An explicit scheme or configuration that
+// matches nothing is an error, so a typo doesn't silently check the wrong
+// build.
+func loadXcodeBuilds(projectPath string, sel xcodeproj.Selection) (*xcodeBuilds, error) {
+	xb := &xcodeBuilds{plists: map[string]xcodeproj.Configuration{}}
+	var schemes []string
+
+	for _, path := range xcodeproj.Find(projectPath) {
+		proj, err := xcodeproj.Load(path)
+		if err != nil {
+			continue
+		}
+		if sel.Scheme != "" && !contains(proj.SchemeNames(), sel.Scheme) {
+			schemes = append(schemes, proj.SchemeNames()...)
+			continue
+		}
+		builds, err := proj.Builds(sel)
+		if err != nil {
+			return nil, err
+		}
+		xb.projects = append(xb.projects, proj)
+		xb.sels = append(xb.sels, sel)
+		for _, b := range builds {
+			plist := b.Config.InfoPlistPath()
+			if _, seen := xb.plists[plist]; plist != "" && !seen {
+				xb.plists[plist] = b.Config
+			}
+		}
+	}
+
+	if sel.Scheme != "" && len(xb.projects) == 0 {
+		if len(schemes) == 0 {
+			return nil, fmt.Errorf("scheme '%s' not found: no Xcode project with shared or user schemes under %s", sel.Scheme, projectPath)
+		}
+		sort.Strings(schemes)
+		return nil, fmt.Errorf("scheme '%s' not found (available: %s)", sel.Scheme, strings.Join(schemes, ", "))
+	}
+	return xb, nil
+}
+
+// settingsFor returns the build settings used with an Info.plist.
+func (xb *xcodeBuilds) settingsFor(plistPath string) (xcodeproj.Configuration, bool) {
+	if xb == nil {
+		return xcodeproj.Configuration{}, false
+	}
+	abs, err := filepath.Abs(plistPath)
+	if err != nil {
+		abs = plistPath
+	}
+	for path, c := range xb.plists {
+		if p, err := filepath.Abs(path); err == nil && p == abs {
+			return c, true
+		}
+	}
+	return xcodeproj.Configuration{}, false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadAppMeta extracts the app name, bundle ID, version and build number the
+// project would ship, resolving build setting references with its
+// Release-like configurations.
+func ReadAppMeta(projectPath string) (AppMeta, error) {
+	xcode, err := loadXcodeBuilds(projectPath, xcodeproj.Selection{})
+	if err != nil {
+		return AppMeta{}, err
+	}
+	_, meta := CheckLocalMetadata(projectPath, xcode)
+	return meta, nil
+}
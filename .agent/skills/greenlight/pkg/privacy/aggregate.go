@@ -0,0 +1,287 @@
+package privacy
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+)
+
+// Manifest is one parsed PrivacyInfo.xcprivacy.
+type Manifest struct {
+	Source          string              `json:"source"`
+	Tracking        bool                `json:"tracking"`
+	TrackingDomains []string            `json:"tracking_domains,omitempty"`
+	AccessedAPIs    map[string][]string `json:"accessed_apis,omitempty"` // API category -> reasons
+	CollectedData   []CollectedData     `json:"collected_data,omitempty"`
+	Error           string              `json:"error,omitempty"`
+}
+
+// CollectedData is one NSPrivacyCollectedDataTypes entry.
+type CollectedData struct {
+	Type     string   `json:"type"`
+	Linked   bool     `json:"linked"`
+	Tracking bool     `json:"tracking"`
+	Purposes []string `json:"purposes,omitempty"`
+}
+
+// AggregateReport merges every privacy manifest in an app — the app's own plus
+// each embedded framework and resource bundle — the way App Store Connect
+// combines them into the app's privacy report.
+type AggregateReport struct {
+	Path            string           `json:"path"`
+	Manifests       []Manifest       `json:"manifests"`
+	Tracking        bool             `json:"tracking"`
+	TrackingSources []string         `json:"tracking_sources,omitempty"`
+	TrackingDomains []AggregatedItem `json:"tracking_domains,omitempty"`
+	AccessedAPIs    []AggregatedAPI  `json:"accessed_apis,omitempty"`
+	CollectedData   []AggregatedData `json:"collected_data,omitempty"`
+}
+
+// AggregatedItem is a value and the manifests that declare it.
+type AggregatedItem struct {
+	Value   string   `json:"value"`
+	Sources []string `json:"sources"`
+}
+
+// AggregatedAPI is a Required Reason API category with the union of reasons.
+type AggregatedAPI struct {
+	Type    string   `json:"type"`
+	Reasons []string `json:"reasons"`
+	Sources []string `json:"sources"`
+}
+
+// AggregatedData is a collected data type with linkage, tracking and purposes
+// OR-ed across every manifest that declares it.
+type AggregatedData struct {
+	Type     string   `json:"type"`
+	Linked   bool     `json:"linked"`
+	Tracking bool     `json:"tracking"`
+	Purposes []string `json:"purposes"`
+	Sources  []string `json:"sources"`
+}
+
+// Aggregate collects every PrivacyInfo.xcprivacy from an .ipa or a project
+// directory (including Pods, SPM checkouts and node_modules) and merges them.
+func Aggregate(path string) (*AggregateReport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access path: %w", err)
+	}
+
+	var manifests []Manifest
+	if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".ipa") {
+		manifests, err = manifestsFromIPA(path)
+	} else if info.IsDir() {
+		manifests, err = manifestsFromDir(path)
+	} else {
+		return nil, fmt.Errorf("path must be a directory or .ipa file: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifestRank(manifests[i].Source) < manifestRank(manifests[j].Source) ||
+			(manifestRank(manifests[i].Source) == manifestRank(manifests[j].Source) && manifests[i].Source < manifests[j].Source)
+	})
+
+	report := &AggregateReport{Path: path, Manifests: manifests}
+	report.merge()
+	return report, nil
+}
+
+func manifestsFromIPA(ipaPath string) ([]Manifest, error) {
+	r, err := zip.OpenReader(ipaPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open IPA (not a valid zip): %w", err)
+	}
+	defer r.Close()
+
+	var manifests []Manifest
+	for _, f := range r.File {
+		if !strings.EqualFold(filepath.Base(f.Name), "PrivacyInfo.xcprivacy") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, parseManifest(ipaManifestSource(f.Name), data))
+	}
+	return manifests, nil
+}
+
+// ipaManifestSource names a manifest by its owning bundle inside the IPA.
+func ipaManifestSource(name string) string {
+	parts := strings.Split(name, "/")
+	owner := ""
+	for _, p := range parts[:len(parts)-1] {
+		if strings.HasSuffix(p, ".app") || strings.HasSuffix(p, ".framework") ||
+			strings.HasSuffix(p, ".bundle") || strings.HasSuffix(p, ".appex") {
+			owner = p
+		}
+	}
+	if owner == "" {
+		return name
+	}
+	return owner
+}
+
+func manifestsFromDir(root string) ([]Manifest, error) {
+	var manifests []Manifest
+	skipDirs := map[string]bool{".git": true, "build": true, "DerivedData": true, ".expo": true}
+
+	err := sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(info.Name(), "PrivacyInfo.xcprivacy") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		manifests = append(manifests, parseManifest(filepath.ToSlash(rel), data))
+		return nil
+	})
+	return manifests, err
+}
+
+// manifestRank sorts the app's own manifest first, then dependencies.
+func manifestRank(source string) int {
+	s := strings.ToLower(source)
+	switch {
+	case strings.HasSuffix(s, ".app"):
+		return 0
+	case strings.Contains(s, "pods/"), strings.Contains(s, "node_modules/"),
+		strings.Contains(s, "checkouts/"), strings.Contains(s, "sourcepackages/"),
+		strings.HasSuffix(s, ".framework"), strings.HasSuffix(s, ".bundle"):
+		return 2
+	}
+	return 1
+}
+
+func parseManifest(source string, data []byte) Manifest {
+	m := Manifest{Source: source, AccessedAPIs: make(map[string][]string)}
+
+	root, err := decodePlist(data)
+	if err != nil {
+		m.Error = err.Error()
+		return m
+	}
+	dict := plistDict(root)
+
+	m.Tracking = plistBool(dict["NSPrivacyTracking"])
+	m.TrackingDomains = plistStrings(dict["NSPrivacyTrackingDomains"])
+
+	for _, item := range plistArray(dict["NSPrivacyAccessedAPITypes"]) {
+		entry := plistDict(item)
+		apiType := plistString(entry["NSPrivacyAccessedAPIType"])
+		if apiType == "" {
+			continue
+		}
+		m.AccessedAPIs[apiType] = append(m.AccessedAPIs[apiType], plistStrings(entry["NSPrivacyAccessedAPITypeReasons"])...)
+	}
+
+	for _, item := range plistArray(dict["NSPrivacyCollectedDataTypes"]) {
+		entry := plistDict(item)
+		dataType := plistString(entry["NSPrivacyCollectedDataType"])
+		if dataType == "" {
+			continue
+		}
+		m.CollectedData = append(m.CollectedData, CollectedData{
+			Type:     dataType,
+			Linked:   plistBool(entry["NSPrivacyCollectedDataTypeLinked"]),
+			Tracking: plistBool(entry["NSPrivacyCollectedDataTypeTracking"]),
+			Purposes: plistStrings(entry["NSPrivacyCollectedDataTypePurposes"]),
+		})
+	}
+
+	return m
+}
+
+func (r *AggregateReport) merge() {
+	domains := make(map[string]*AggregatedItem)
+	apis := make(map[string]*AggregatedAPI)
+	collected := make(map[string]*AggregatedData)
+
+	for _, m := range r.Manifests {
+		if m.Tracking {
+			r.Tracking = true
+			r.TrackingSources = appendUnique(r.TrackingSources, m.Source)
+		}
+		for _, d := range m.TrackingDomains {
+			if domains[d] == nil {
+				domains[d] = &AggregatedItem{Value: d}
+			}
+			domains[d].Sources = appendUnique(domains[d].Sources, m.Source)
+		}
+		for apiType, reasons := range m.AccessedAPIs {
+			if apis[apiType] == nil {
+				apis[apiType] = &AggregatedAPI{Type: apiType}
+			}
+			a := apis[apiType]
+			for _, reason := range reasons {
+				a.Reasons = appendUnique(a.Reasons, reason)
+			}
+			a.Sources = appendUnique(a.Sources, m.Source)
+		}
+		for _, c := range m.CollectedData {
+			if collected[c.Type] == nil {
+				collected[c.Type] = &AggregatedData{Type: c.Type}
+			}
+			a := collected[c.Type]
+			a.Linked = a.Linked || c.Linked
+			a.Tracking = a.Tracking || c.Tracking
+			for _, p := range c.Purposes {
+				a.Purposes = appendUnique(a.Purposes, p)
+			}
+			a.Sources = appendUnique(a.Sources, m.Source)
+		}
+	}
+
+	for _, d := range domains {
+		r.TrackingDomains = append(r.TrackingDomains, *d)
+	}
+	sort.Slice(r.TrackingDomains, func(i, j int) bool { return r.TrackingDomains[i].Value < r.TrackingDomains[j].Value })
+
+	for _, a := range apis {
+		sort.Strings(a.Reasons)
+		r.AccessedAPIs = append(r.AccessedAPIs, *a)
+	}
+	sort.Slice(r.AccessedAPIs, func(i, j int) bool { return r.AccessedAPIs[i].Type < r.AccessedAPIs[j].Type })
+
+	for _, c := range collected {
+		sort.Strings(c.Purposes)
+		r.CollectedData = append(r.CollectedData, *c)
+	}
+	sort.Slice(r.CollectedData, func(i, j int) bool { return r.CollectedData[i].Type < r.CollectedData[j].Type })
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, existing := range list {
+		if existing == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
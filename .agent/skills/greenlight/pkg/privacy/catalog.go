@@ -0,0 +1,177 @@
+package privacy
+
+import (
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// CheckInfo describes a privacy scanner check for `greenlight rules`.
+type CheckInfo struct {
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Severity    severity.Level `json:"severity"`
+	Guideline   string         `json:"guideline"`
+	Description string         `json:"description"`
+	Fix         string         `json:"fix"`
+	Examples    []string       `json:"examples,omitempty"`
+}
+
+// Catalog returns every privacy check, including one per Required Reason API.
+func Catalog() []CheckInfo {
+	checks := []CheckInfo{
+		{
+			ID:          "privacy-manifest-missing",
+			Title:       "No PrivacyInfo.xcprivacy in project",
+			Severity:    severity.Critical,
+			Guideline:   "5.1.1",
+			Description: "Privacy manifests are required since May 2024; uploads without one get ITMS-91061.",
+			Fix:         "Create PrivacyInfo.xcprivacy (greenlight privacy generate scaffolds one).",
+		},
+	}
+
+	for _, api := range requiredReasonAPIs {
+		checks = append(checks, CheckInfo{
+			ID:          requiredReasonCheckID(api.APIType),
+			Title:       "Required Reason API not declared: " + api.Name,
+			Severity:    severity.Critical,
+			Guideline:   "5.1.1",
+			Description: api.Description + ". Using it without declaring " + api.APIType + " with an approved reason is rejected.",
+			Fix:         "Add " + api.APIType + " to NSPrivacyAccessedAPITypes with the reason that matches your usage.",
+			Examples:    []string{patternExample(api.APIType)},
+		})
+	}
+
+	checks = append(checks,
+		CheckInfo{
+			ID:          "tracking-without-att",
+			Title:       "Tracking SDKs without ATT implementation",
+			Severity:    severity.Critical,
+			Guideline:   "5.1.2",
+			Description: "A known tracking or advertising SDK is present but the app never calls requestTrackingAuthorization.",
+			Fix:         "Request ATT before initializing tracking SDKs.",
+			Examples:    []string{"import FBSDKCoreKit", "import AppsFlyerLib"},
+		},
+		CheckInfo{
+			ID:          "tracking-domain-undeclared",
+			Title:       "Tracking endpoint not in NSPrivacyTrackingDomains",
+			Severity:    severity.Warn,
+			Guideline:   "5.1.2",
+			Description: "A known tracking SDK endpoint is referenced in code but not declared in the manifest's NSPrivacyTrackingDomains.",
+			Fix:         "Declare the domain in NSPrivacyTrackingDomains.",
+			Examples:    []string{`URL(string: "https://api2.amplitude.com/2/httpapi")`},
+		},
+		CheckInfo{
+			ID:          "tracking-declared-unused",
+			Title:       "Manifest declares tracking but no tracking SDKs detected",
+			Severity:    severity.Info,
+			Description: "NSPrivacyTracking is true but no known tracking SDK was found.",
+			Fix:         "Set NSPrivacyTracking to false if the app does not track.",
+		},
+		CheckInfo{
+			ID:          "sdk-privacy-manifest-missing",
+			Title:       "Commonly used SDK without a privacy manifest",
+			Severity:    severity.Critical,
+			Guideline:   "5.1.1",
+			Description: "A pod or Swift package on Apple's commonly used SDK list is pinned to a release without PrivacyInfo.xcprivacy; uploads get ITMS-91061.",
+			Fix:         "Upgrade the SDK to the first release that ships a privacy manifest, or later.",
+			Examples:    []string{"  - Alamofire (5.8.1)", `"identity" : "sdwebimage"`},
+		},
+		CheckInfo{
+			ID:          "sdk-signature-missing",
+			Title:       "Commonly used SDK binary is not signed",
+			Severity:    severity.Critical,
+			Guideline:   "5.1.1",
+			Description: "An XCFramework from an SDK on Apple's commonly used list has no _CodeSignature; uploads get ITMS-91065.",
+			Fix:         "Upgrade to a release distributed as signed XCFrameworks.",
+		},
+		CheckInfo{
+			ID:          "crash-data-undeclared",
+			Title:       "Crash reporter without crash data in any privacy manifest",
+			Severity:    severity.Warn,
+			Guideline:   "5.1.1",
+			Description: "A crash reporter (Sentry, Crashlytics, Bugsnag, Datadog, Instabug, ...) is set up, but neither the app's nor the SDKs' manifests declare NSPrivacyCollectedDataTypeCrashData.",
+			Fix:         "Declare Crash Data in PrivacyInfo.xcprivacy and the App Store Connect privacy label.",
+			Examples:    []string{"SentrySDK.start { options in ... }", "import FirebaseCrashlytics"},
+		},
+		CheckInfo{
+			ID:          "crash-pii-undeclared",
+			Title:       "Crash reporter collects undeclared user data",
+			Severity:    severity.Warn,
+			Guideline:   "5.1.1",
+			Description: "Crash reporter settings that send user data (Sentry sendDefaultPii, user IDs and emails attached to reports, session replays, performance traces) without the matching data type in any privacy manifest.",
+			Fix:         "Turn the setting off, or declare the data type in PrivacyInfo.xcprivacy and the privacy label.",
+			Examples:    []string{"options.sendDefaultPii = true", "Sentry.setUser({ email: user.email })", "Crashlytics.crashlytics().setUserID(user.id)"},
+		},
+		CheckInfo{
+			ID:          "crash-replay-unmasked",
+			Title:       "Session replay captures screens without masking",
+			Severity:    severity.Warn,
+			Guideline:   "5.1.1",
+			Description: "Text or image masking is turned off for session replays or screenshots, so personal data on screen is uploaded.",
+			Fix:         "Keep maskAllText and maskAllImages on.",
+			Examples:    []string{"options.sessionReplay.maskAllText = false"},
+		},
+		CheckInfo{
+			ID:          "crashlytics-collection-never-enabled",
+			Title:       "Crashlytics collection disabled and never enabled",
+			Severity:    severity.Info,
+			Description: "FirebaseCrashlyticsCollectionEnabled is false in Info.plist and no code turns collection on after consent, so no crash is ever reported.",
+			Fix:         "Call setCrashlyticsCollectionEnabled(true) once the user opts in.",
+		},
+		CheckInfo{
+			ID:          "crash-symbols-not-uploaded",
+			Title:       "Crash reporter dSYMs not uploaded",
+			Severity:    severity.Info,
+			Guideline:   "2.1",
+			Description: "Crashlytics or Sentry is set up natively, but no build phase or config plugin uploads dSYMs, so crash reports are unsymbolicated.",
+			Fix:         "Add the SDK's dSYM upload Run Script build phase.",
+		},
+		CheckInfo{
+			ID:          "crash-reporters-multiple",
+			Title:       "Several crash reporters installed",
+			Severity:    severity.Info,
+			Description: "Crash reporters replace each other's signal and exception handlers, so each crash reaches only one of them, and each needs its own dSYM upload.",
+			Fix:         "Keep one crash reporter.",
+		},
+	)
+	return checks
+}
+
+// requiredReasonCheckID maps an API category to its check ID, e.g.
+// NSPrivacyAccessedAPICategoryUserDefaults -> required-reason-user-defaults.
+func requiredReasonCheckID(apiType string) string {
+	return "required-reason-" + kebab(strings.TrimPrefix(apiType, "NSPrivacyAccessedAPICategory"))
+}
+
+// patternExample gives a representative API call per Required Reason category.
+func patternExample(apiType string) string {
+	switch apiType {
+	case "NSPrivacyAccessedAPICategoryFileTimestamp":
+		return "attrs[.modificationDate]"
+	case "NSPrivacyAccessedAPICategorySystemBootTime":
+		return "ProcessInfo.processInfo.systemUptime"
+	case "NSPrivacyAccessedAPICategoryDiskSpace":
+		return "values.volumeAvailableCapacity"
+	case "NSPrivacyAccessedAPICategoryActiveKeyboards":
+		return "UITextInputMode.activeInputModes"
+	case "NSPrivacyAccessedAPICategoryUserDefaults":
+		return "UserDefaults.standard.set(true, forKey: \"onboarded\")"
+	}
+	return ""
+}
+
+// kebab converts "FileTimestamp" to "file-timestamp".
+func kebab(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
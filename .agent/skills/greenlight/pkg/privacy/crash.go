@@ -0,0 +1,279 @@
+package privacy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// crashSDK is a crash reporter detected from its imports and setup calls.
+type crashSDK struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+var crashSDKPatterns = []crashSDK{
+	{"Sentry", regexp.MustCompile(`(import\s+Sentry\b|#import\s+<Sentry/|SentrySDK\.start|@sentry/(react-native|capacitor)|\bSentry\.init\s*\()`)},
+	{"Firebase Crashlytics", regexp.MustCompile(`(import\s+FirebaseCrashlytics|Crashlytics\.crashlytics\(\)|\[FIRCrashlytics|@react-native-firebase/crashlytics)`)},
+	{"Bugsnag", regexp.MustCompile(`(import\s+Bugsnag\b|Bugsnag\.start|\[Bugsnag start|@bugsnag/(react-native|expo))`)},
+	{"Datadog", regexp.MustCompile(`(import\s+DatadogCrashReporting|CrashReporting\.enable|@datadog/mobile-react-native)`)},
+	{"Instabug", regexp.MustCompile(`(import\s+Instabug\b|Instabug\.start|Instabug\.init|instabug-reactnative)`)},
+	{"Embrace", regexp.MustCompile(`(import\s+EmbraceIO|Embrace\.setup|@embrace-io/)`)},
+	{"New Relic", regexp.MustCompile(`(import\s+NewRelic\b|NewRelic\.start|newrelic-react-native-agent)`)},
+	{"App Center Crashes", regexp.MustCompile(`(AppCenterCrashes|appcenter-crashes)`)},
+	{"Raygun", regexp.MustCompile(`(raygun4apple|RaygunClient|raygun4reactnative)`)},
+}
+
+// crashDataSignal is crash reporter configuration that sends a kind of user
+// data, and the manifest data type that discloses it. Scoped signals only
+// count on lines that name a crash reporter, as analytics SDKs share the
+// method names.
+type crashDataSignal struct {
+	what     string
+	dataType string
+	pattern  *regexp.Regexp
+	scoped   bool
+}
+
+var crashDataSignals = []crashDataSignal{
+	{"the user's IP address and identity on every event (sendDefaultPii)", "NSPrivacyCollectedDataTypeUserID", regexp.MustCompile(`\bsendDefaultPii\s*[:=]\s*(true|YES)\b`), false},
+	{"email addresses attached to reports", "NSPrivacyCollectedDataTypeEmailAddress", regexp.MustCompile(`(?i)\b(setUser\w*|identifyUser|setCustomValue|setCustomKey)\s*\(.*\bemail\b`), true},
+	{"user IDs attached to reports", "NSPrivacyCollectedDataTypeUserID", regexp.MustCompile(`\b(setUserID|setUserId|setUserIdentifier|identifyUser)\s*\(|\bsetUser\s*\(\s*(\{\s*id\b|User\(userId:|\[)`), true},
+	{"session replays and screenshots of the app's screens", "NSPrivacyCollectedDataTypeProductInteraction", regexp.MustCompile(`\b(attachScreenshot|attachViewHierarchy|replaysSessionSampleRate|replaysOnErrorSampleRate|mobileReplayIntegration|sessionReplay)\b\s*[:=(.]`), false},
+	{"performance traces", "NSPrivacyCollectedDataTypePerformanceData", regexp.MustCompile(`\btracesSampleRate\s*[:=]\s*(0?\.\d*[1-9]|1(\.0)?)\b`), false},
+}
+
+// crashDataLabels name the data types as the App Store Connect privacy
+// label does.
+var crashDataLabels = map[string]string{
+	"NSPrivacyCollectedDataTypeUserID":             "User ID",
+	"NSPrivacyCollectedDataTypeEmailAddress":       "Email Address",
+	"NSPrivacyCollectedDataTypeProductInteraction": "Product Interaction",
+	"NSPrivacyCollectedDataTypePerformanceData":    "Performance Data",
+}
+
+var (
+	// crashReporterRef names a crash reporter's API on a line.
+	crashReporterRef = regexp.MustCompile(`(Sentry|Crashlytics|crashlytics\(\)|Bugsnag|Datadog|Instabug|Embrace|NewRelic|Raygun|Crashes)`)
+	// crashReplayUnmaskedPattern turns off the masking of text and images
+	// in session replays and screenshots.
+	crashReplayUnmaskedPattern = regexp.MustCompile(`\b(maskAllText|maskAllImages|redactAllText|redactAllImages)\s*[:=]\s*(false|NO)\b|\bdefaultPrivacyLevel\s*[:=]\s*\S*(allow|ALLOW)`)
+	// crashlyticsEnablePattern turns Crashlytics collection on at runtime,
+	// usually after the user consents.
+	crashlyticsEnablePattern = regexp.MustCompile(`(setCrashlyticsCollectionEnabled|isCrashlyticsCollectionEnabled)\s*[(=:]\s*(true|YES|[a-zA-Z_])`)
+	crashlyticsDisabledPlist = regexp.MustCompile(`<key>FirebaseCrashlyticsCollectionEnabled</key>\s*<false\s*/>`)
+	// Build phases and config plugins that upload dSYMs.
+	crashlyticsUploadPattern = regexp.MustCompile(`(upload-symbols|FirebaseCrashlytics/run|Crashlytics/run|@react-native-firebase/crashlytics)`)
+	sentryUploadPattern      = regexp.MustCompile(`(sentry-cli|sentry-xcode|debug-files upload|@sentry/react-native/expo)`)
+)
+
+// crashScan is what the privacy walk learns about crash reporters.
+type crashScan struct {
+	sdks     map[string]FileHit
+	signals  map[int]FileHit // crashDataSignals index -> first hit
+	unmasked *FileHit
+	enabled  bool // Crashlytics collection turned on in code
+
+	disabledPlist string // Info.plist turning Crashlytics collection off
+	hasXcode      bool
+	crashlyticsUp bool
+	sentryUp      bool
+	nativeSDKs    map[string]bool // SDKs set up from Swift or Objective-C
+}
+
+func newCrashScan() *crashScan {
+	return &crashScan{sdks: map[string]FileHit{}, signals: map[int]FileHit{}, nativeSDKs: map[string]bool{}}
+}
+
+// scanCode records crash reporters and their data settings in one source
+// file.
+func (c *crashScan) scanCode(relPath, lang string, lines []string) {
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		hit := FileHit{File: relPath, Line: i + 1, Code: trimmed}
+		for _, sdk := range crashSDKPatterns {
+			if sdk.Pattern.MatchString(line) {
+				if _, ok := c.sdks[sdk.Name]; !ok {
+					c.sdks[sdk.Name] = hit
+				}
+				if lang == "swift" || lang == "objc" {
+					c.nativeSDKs[sdk.Name] = true
+				}
+			}
+		}
+		for n, s := range crashDataSignals {
+			if _, ok := c.signals[n]; !ok && s.pattern.MatchString(line) && (!s.scoped || crashReporterRef.MatchString(line)) {
+				c.signals[n] = hit
+			}
+		}
+		if c.unmasked == nil && crashReplayUnmaskedPattern.MatchString(line) {
+			c.unmasked = &hit
+		}
+		if crashlyticsEnablePattern.MatchString(line) {
+			c.enabled = true
+		}
+	}
+}
+
+// scanConfig records Crashlytics collection settings and dSYM upload steps
+// from Info.plist, Xcode projects, Podfiles and Expo configs.
+func (c *crashScan) scanConfig(path, relPath, name string) {
+	switch {
+	case name == "Info.plist", name == "project.pbxproj", name == "Podfile",
+		name == "app.json", strings.HasPrefix(name, "app.config."):
+	default:
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	content := string(data)
+	if name == "project.pbxproj" {
+		c.hasXcode = true
+	}
+	if name == "Info.plist" && c.disabledPlist == "" && crashlyticsDisabledPlist.MatchString(content) {
+		c.disabledPlist = relPath
+	}
+	if crashlyticsUploadPattern.MatchString(content) {
+		c.crashlyticsUp = true
+	}
+	if sentryUploadPattern.MatchString(content) {
+		c.sentryUp = true
+	}
+}
+
+// findings checks the crash reporters found against the data types the
+// project's privacy manifests declare (declared is nil without a manifest),
+// and their collection and symbol upload setup.
+func (c *crashScan) findings(declared map[string]bool) []Finding {
+	if len(c.sdks) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(c.sdks))
+	for name := range c.sdks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	first := c.sdks[names[0]]
+
+	var findings []Finding
+	if declared != nil {
+		if !declared["NSPrivacyCollectedDataTypeCrashData"] {
+			findings = append(findings, Finding{
+				ID:        "crash-data-undeclared",
+				Severity:  severity.Warn,
+				Guideline: "5.1.1",
+				Title:     "Crash reporter collects crash data not declared in any privacy manifest",
+				Detail:    fmt.Sprintf("%s %s crash reports, but neither the app's PrivacyInfo.xcprivacy nor the SDKs' manifests declare NSPrivacyCollectedDataTypeCrashData. The App Privacy section in App Store Connect must list Crash Data under Diagnostics.", strings.Join(names, ", "), plural(len(names), "sends", "send")),
+				Fix:       "Add NSPrivacyCollectedDataTypeCrashData (purpose App Functionality or Analytics) to PrivacyInfo.xcprivacy, and Crash Data to the app's privacy nutrition label.",
+				File:      first.File,
+				Line:      first.Line,
+			})
+		}
+		// One finding per undeclared data type, at its first signal.
+		var types []string
+		whats := map[string][]string{}
+		hits := map[string]FileHit{}
+		for n, s := range crashDataSignals {
+			hit, ok := c.signals[n]
+			if !ok || declared[s.dataType] {
+				continue
+			}
+			if _, seen := whats[s.dataType]; !seen {
+				types = append(types, s.dataType)
+				hits[s.dataType] = hit
+			}
+			whats[s.dataType] = append(whats[s.dataType], s.what)
+		}
+		for _, t := range types {
+			findings = append(findings, Finding{
+				ID:        "crash-pii-undeclared",
+				Severity:  severity.Warn,
+				Guideline: "5.1.1",
+				Title:     "Crash reporter collects " + crashDataLabels[t] + " data not declared in the privacy manifest",
+				Detail:    fmt.Sprintf("The crash reporter is configured to send %s, but no privacy manifest declares %s. Undisclosed collection makes the app's privacy label inaccurate, which App Review rejects under 5.1.1.", strings.Join(whats[t], ", and "), t),
+				Fix:       fmt.Sprintf("Turn the setting off if you don't need the data, or declare %s in PrivacyInfo.xcprivacy (linked to the user when it is) and %s in the App Store Connect privacy label.", t, crashDataLabels[t]),
+				File:      hits[t].File,
+				Line:      hits[t].Line,
+			})
+		}
+	}
+
+	if c.unmasked != nil {
+		findings = append(findings, Finding{
+			ID:        "crash-replay-unmasked",
+			Severity:  severity.Warn,
+			Guideline: "5.1.1",
+			Title:     "Session replay captures screens without masking",
+			Detail:    "Text or image masking is turned off for session replays or screenshots, so passwords, messages, payment details and other personal data on screen are uploaded to the crash reporter.",
+			Fix:       "Keep maskAllText and maskAllImages on, and unmask only views known to hold no personal data.",
+			File:      c.unmasked.File,
+			Line:      c.unmasked.Line,
+		})
+	}
+
+	if _, ok := c.sdks["Firebase Crashlytics"]; ok && c.disabledPlist != "" && !c.enabled {
+		findings = append(findings, Finding{
+			ID:       "crashlytics-collection-never-enabled",
+			Severity: severity.Info,
+			Title:    "Crashlytics collection is disabled and never enabled",
+			Detail:   c.disabledPlist + " sets FirebaseCrashlyticsCollectionEnabled to false, and no code calls setCrashlyticsCollectionEnabled(true) after the user opts in, so crash reports are never sent, including crashes from App Review.",
+			Fix:      "Call Crashlytics.crashlytics().setCrashlyticsCollectionEnabled(true) once the user consents, or remove the opt-out key.",
+			File:     c.disabledPlist,
+		})
+	}
+
+	if c.hasXcode {
+		for _, sdk := range []struct {
+			name     string
+			uploaded bool
+			fix      string
+		}{
+			{"Firebase Crashlytics", c.crashlyticsUp, "Add a Run Script build phase that runs \"${BUILD_DIR%/Build/*}/SourcePackages/checkouts/firebase-ios-sdk/Crashlytics/run\" (or Pods/FirebaseCrashlytics/run), with the dSYM as an input file."},
+			{"Sentry", c.sentryUp, "Upload debug files with sentry-cli debug-files upload in a Run Script build phase, or the sentry-xcode script the Sentry wizard adds."},
+		} {
+			hit, ok := c.sdks[sdk.name]
+			if !ok || !c.nativeSDKs[sdk.name] || sdk.uploaded {
+				continue
+			}
+			findings = append(findings, Finding{
+				ID:        "crash-symbols-not-uploaded",
+				Severity:  severity.Info,
+				Guideline: "2.1",
+				Title:     sdk.name + " dSYMs are not uploaded",
+				Detail:    "No build phase uploads dSYMs to " + sdk.name + ", so crashes, including the ones App Review hits, show up as unsymbolicated addresses.",
+				Fix:       sdk.fix,
+				File:      hit.File,
+				Line:      hit.Line,
+			})
+		}
+	}
+
+	if len(names) > 1 {
+		findings = append(findings, Finding{
+			ID:       "crash-reporters-multiple",
+			Severity: severity.Info,
+			Title:    fmt.Sprintf("%d crash reporters installed", len(names)),
+			Detail:   fmt.Sprintf("%s all install signal and exception handlers. The last one to start replaces the others, so crashes are reported to one of them at random, each needs its own dSYM upload, and every one collects crash data you must disclose.", strings.Join(names, ", ")),
+			Fix:      "Keep one crash reporter, or turn off crash reporting in all but one of the SDKs.",
+			File:     first.File,
+			Line:     first.Line,
+		})
+	}
+	return findings
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,124 @@
+package privacy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Known tracking/advertising endpoints, keyed by registrable domain.
+var knownTrackingDomains = map[string]string{
+	"app-measurement.com":   "Firebase Analytics",
+	"google-analytics.com":  "Google Analytics",
+	"googleadservices.com":  "Google Ads",
+	"doubleclick.net":       "Google Ads/AdMob",
+	"googlesyndication.com": "Google Ads/AdMob",
+	"graph.facebook.com":    "Facebook SDK",
+	"connect.facebook.net":  "Facebook SDK",
+	"appsflyer.com":         "AppsFlyer",
+	"appsflyersdk.com":      "AppsFlyer",
+	"adjust.com":            "Adjust",
+	"adjust.io":             "Adjust",
+	"branch.io":             "Branch",
+	"app.link":              "Branch",
+	"mixpanel.com":          "Mixpanel",
+	"amplitude.com":         "Amplitude",
+	"segment.io":            "Segment",
+	"segment.com":           "Segment",
+	"applovin.com":          "AppLovin",
+	"applvn.com":            "AppLovin",
+	"unityads.unity3d.com":  "Unity Ads",
+	"supersonicads.com":     "ironSource",
+	"ironsrc.mobi":          "ironSource",
+	"kochava.com":           "Kochava",
+	"singular.net":          "Singular",
+	"tiktokv.com":           "TikTok",
+	"analytics.tiktok.com":  "TikTok",
+	"ads-api.twitter.com":   "X/Twitter Ads",
+	"ads.linkedin.com":      "LinkedIn Ads",
+}
+
+var urlHostPattern = regexp.MustCompile(`https?://([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// DomainHit is a tracking endpoint referenced in code.
+type DomainHit struct {
+	Host string
+	SDK  string
+	File string
+	Line int
+}
+
+// trackingHostsInLine returns the known tracking hosts referenced by URLs in a line.
+func trackingHostsInLine(line string) []DomainHit {
+	var hits []DomainHit
+	for _, m := range urlHostPattern.FindAllStringSubmatch(line, -1) {
+		host := strings.ToLower(m[1])
+		if sdk := trackingSDKForHost(host); sdk != "" {
+			hits = append(hits, DomainHit{Host: host, SDK: sdk})
+		}
+	}
+	return hits
+}
+
+func trackingSDKForHost(host string) string {
+	for domain, sdk := range knownTrackingDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return sdk
+		}
+	}
+	return ""
+}
+
+// domainDeclared reports whether host is covered by a declared tracking
+// domain. Apple matches subdomains of a declared domain.
+func domainDeclared(host string, declared []string) bool {
+	for _, d := range declared {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" {
+			continue
+		}
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTrackingDomains flags tracking endpoints contacted in code that are not
+// listed in the manifest's NSPrivacyTrackingDomains. iOS blocks undeclared
+// tracking domains when the user hasn't granted ATT, and App Review compares
+// them against observed traffic.
+func checkTrackingDomains(manifestPath, content string, hosts map[string][]DomainHit) []Finding {
+	if len(hosts) == 0 {
+		return nil
+	}
+	declared := parseManifest(manifestPath, []byte(content)).TrackingDomains
+
+	var names []string
+	for host := range hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+
+	var findings []Finding
+	for _, host := range names {
+		if domainDeclared(host, declared) {
+			continue
+		}
+		hit := hosts[host][0]
+		findings = append(findings, Finding{
+			ID:        "tracking-domain-undeclared",
+			Severity:  severity.Warn,
+			Guideline: "5.1.2",
+			Title:     "Tracking endpoint not in NSPrivacyTrackingDomains: " + host,
+			Detail:    fmt.Sprintf("%s endpoint %s is referenced in code but not declared as a tracking domain. Undeclared tracking domains are blocked when the user hasn't granted ATT.", hit.SDK, host),
+			Fix:       "Add " + host + " to NSPrivacyTrackingDomains in PrivacyInfo.xcprivacy, or stop contacting it for tracking.",
+			File:      hit.File,
+			Line:      hit.Line,
+		})
+	}
+	return findings
+}
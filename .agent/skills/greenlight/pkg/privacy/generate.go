@@ -0,0 +1,115 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// defaultReasons is the most common approved reason per Required Reason API.
+// Generated entries use it as a starting point; developers must confirm it.
+var defaultReasons = map[string]string{
+	"NSPrivacyAccessedAPICategoryFileTimestamp":   "C617.1",
+	"NSPrivacyAccessedAPICategorySystemBootTime":  "35F9.1",
+	"NSPrivacyAccessedAPICategoryDiskSpace":       "E174.1",
+	"NSPrivacyAccessedAPICategoryActiveKeyboards": "54BD.1",
+	"NSPrivacyAccessedAPICategoryUserDefaults":    "CA92.1",
+}
+
+// GenerateResult is a scaffolded or updated privacy manifest.
+type GenerateResult struct {
+	Path         string   `json:"path"`
+	Existing     bool     `json:"existing"`
+	AddedAPIs    []string `json:"added_apis,omitempty"`
+	AddedDomains []string `json:"added_domains,omitempty"`
+	// TrackingOff is set when tracking domains are declared but the existing
+	// manifest has NSPrivacyTracking = false.
+	TrackingOff bool   `json:"tracking_off,omitempty"`
+	Content     []byte `json:"-"`
+}
+
+// Generate builds a PrivacyInfo.xcprivacy for projectPath from what Scan
+// detects: Required Reason APIs and tracking SDK domains. An existing manifest
+// is updated in place — every manual entry is kept, only missing APIs and
+// domains are added. If outPath is empty the existing manifest path is used,
+// or PrivacyInfo.xcprivacy in the project root. Nothing is written to disk.
+func Generate(ctx context.Context, projectPath, outPath string) (*GenerateResult, error) {
+	scan, err := Scan(ctx, projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GenerateResult{Path: outPath}
+	root := map[string]interface{}{}
+
+	if scan.PrivacyInfoPath != "" {
+		data, err := os.ReadFile(scan.PrivacyInfoPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %w", scan.PrivacyInfoPath, err)
+		}
+		decoded, err := decodePlist(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", scan.PrivacyInfoPath, err)
+		}
+		if d := plistDict(decoded); d != nil {
+			root = d
+		}
+		res.Existing = true
+		if res.Path == "" {
+			res.Path = scan.PrivacyInfoPath
+		}
+	}
+	if res.Path == "" {
+		res.Path = filepath.Join(projectPath, "PrivacyInfo.xcprivacy")
+	}
+
+	// Required Reason APIs
+	apis := plistArray(root["NSPrivacyAccessedAPITypes"])
+	declared := make(map[string]bool)
+	for _, item := range apis {
+		declared[plistString(plistDict(item)["NSPrivacyAccessedAPIType"])] = true
+	}
+	detected := append([]string(nil), scan.DetectedAPITypes...)
+	sort.Strings(detected)
+	for _, apiType := range detected {
+		if declared[apiType] {
+			continue
+		}
+		var reasons []interface{}
+		if reason, ok := defaultReasons[apiType]; ok {
+			reasons = append(reasons, reason)
+		}
+		apis = append(apis, map[string]interface{}{
+			"NSPrivacyAccessedAPIType":        apiType,
+			"NSPrivacyAccessedAPITypeReasons": reasons,
+		})
+		res.AddedAPIs = append(res.AddedAPIs, apiType)
+	}
+	root["NSPrivacyAccessedAPITypes"] = apis
+
+	// Tracking domains
+	domains := plistArray(root["NSPrivacyTrackingDomains"])
+	declaredDomains := plistStrings(root["NSPrivacyTrackingDomains"])
+	for _, host := range scan.TrackingHosts {
+		if domainDeclared(host, declaredDomains) {
+			continue
+		}
+		domains = append(domains, host)
+		declaredDomains = append(declaredDomains, host)
+		res.AddedDomains = append(res.AddedDomains, host)
+	}
+	root["NSPrivacyTrackingDomains"] = domains
+
+	if _, ok := root["NSPrivacyTracking"]; !ok {
+		root["NSPrivacyTracking"] = len(domains) > 0 || len(scan.TrackingSDKs) > 0
+	}
+	res.TrackingOff = len(domains) > 0 && !plistBool(root["NSPrivacyTracking"])
+	if _, ok := root["NSPrivacyCollectedDataTypes"]; !ok {
+		root["NSPrivacyCollectedDataTypes"] = []interface{}{}
+	}
+
+	res.Content = encodePlist(root)
+	return res, nil
+}
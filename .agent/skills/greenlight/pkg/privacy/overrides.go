@@ -0,0 +1,23 @@
+package privacy
+
+import (
+	"github.com/RevylAI/greenlight/internal/config"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// RuleOverride turns a check off or changes its severity, as parsed from the
+// rules section of .greenlight.yml.
+type RuleOverride = config.RuleOverride
+
+// ApplyOverrides drops findings from checks disabled in .greenlight.yml and
+// re-grades the rest according to any per-check severity override.
+func ApplyOverrides(findings []Finding, overrides map[string]RuleOverride) []Finding {
+	if len(overrides) == 0 {
+		return findings
+	}
+	kept := config.ApplyOverrides(findings, overrides, func(f *Finding) (string, *severity.Level) {
+		return f.ID, &f.Severity
+	})
+	SortFindings(kept) // re-graded findings move with their severity
+	return kept
+}
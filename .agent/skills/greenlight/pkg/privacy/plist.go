@@ -0,0 +1,188 @@
+package privacy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// decodePlist parses an XML property list into maps, slices, strings and
+// bools. Binary plists are not supported; privacy manifests are copied into
+// the bundle as XML.
+func decodePlist(data []byte) (interface{}, error) {
+	if bytes.HasPrefix(data, []byte("bplist")) {
+		return nil, fmt.Errorf("binary plist not supported")
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("no plist root element")
+			}
+			return nil, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local != "plist" {
+			return decodePlistValue(dec, se)
+		}
+	}
+}
+
+func decodePlistValue(dec *xml.Decoder, se xml.StartElement) (interface{}, error) {
+	switch se.Name.Local {
+	case "dict":
+		dict := make(map[string]interface{})
+		var key string
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					var k string
+					if err := dec.DecodeElement(&k, &t); err != nil {
+						return nil, err
+					}
+					key = strings.TrimSpace(k)
+					continue
+				}
+				v, err := decodePlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = v
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		var arr []interface{}
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				v, err := decodePlistValue(dec, t)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, v)
+			case xml.EndElement:
+				return arr, nil
+			}
+		}
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, err
+		}
+		return se.Name.Local == "true", nil
+	default:
+		var s string
+		if err := dec.DecodeElement(&s, &se); err != nil {
+			return nil, err
+		}
+		if se.Name.Local != "string" {
+			return plistScalar{Kind: se.Name.Local, Value: strings.TrimSpace(s)}, nil
+		}
+		return strings.TrimSpace(s), nil
+	}
+}
+
+// plistScalar keeps non-string scalars (integer, real, date, data) intact so
+// a decoded plist can be written back unchanged.
+type plistScalar struct {
+	Kind  string
+	Value string
+}
+
+func plistString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func plistBool(v interface{}) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func plistArray(v interface{}) []interface{} {
+	a, _ := v.([]interface{})
+	return a
+}
+
+func plistDict(v interface{}) map[string]interface{} {
+	d, _ := v.(map[string]interface{})
+	return d
+}
+
+func plistStrings(v interface{}) []string {
+	var out []string
+	for _, item := range plistArray(v) {
+		if s := plistString(item); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// encodePlist writes v as an XML property list with dictionary keys sorted.
+func encodePlist(v interface{}) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n")
+	encodePlistValue(&b, v, 0)
+	b.WriteString("</plist>\n")
+	return b.Bytes()
+}
+
+func encodePlistValue(b *bytes.Buffer, v interface{}, depth int) {
+	indent := strings.Repeat("\t", depth)
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString(indent + "<dict>\n")
+		for _, k := range keys {
+			b.WriteString(indent + "\t<key>" + xmlEscape(k) + "</key>\n")
+			encodePlistValue(b, t[k], depth+1)
+		}
+		b.WriteString(indent + "</dict>\n")
+	case []interface{}:
+		if len(t) == 0 {
+			b.WriteString(indent + "<array/>\n")
+			return
+		}
+		b.WriteString(indent + "<array>\n")
+		for _, item := range t {
+			encodePlistValue(b, item, depth+1)
+		}
+		b.WriteString(indent + "</array>\n")
+	case bool:
+		if t {
+			b.WriteString(indent + "<true/>\n")
+		} else {
+			b.WriteString(indent + "<false/>\n")
+		}
+	case plistScalar:
+		b.WriteString(indent + "<" + t.Kind + ">" + xmlEscape(t.Value) + "</" + t.Kind + ">\n")
+	default:
+		b.WriteString(indent + "<string>" + xmlEscape(fmt.Sprint(t)) + "</string>\n")
+	}
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package privacy
+
+import (
+	"context"
+
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() { scan.Register(scanner{}) }
+
+// scanner runs the privacy scan as part of every multi-scanner command and
+// records the manifest, detected APIs and tracking SDKs as facts.
+type scanner struct{}
+
+func (scanner) Name() string { return "privacy" }
+
+func (scanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	result, err := ScanWithOptions(ctx, t.ProjectPath, Options{Paths: t.Paths})
+	if err != nil {
+		return nil, err
+	}
+	t.Facts.Update(func(facts *scan.Facts) {
+		facts.HasPrivacyInfo = result.HasPrivacyInfo
+		facts.DetectedAPIs = result.DetectedAPIs
+		facts.TrackingSDKs = result.TrackingSDKs
+	})
+	out := make([]scan.Finding, 0, len(result.Findings))
+	for _, f := range result.Findings {
+		out = append(out, f.ScanFinding())
+	}
+	return out, nil
+}
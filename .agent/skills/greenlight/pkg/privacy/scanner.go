@@ -1,17 +1,31 @@
+// Package privacy checks a project's PrivacyInfo.xcprivacy against the
+// Required Reason APIs, tracking SDKs and tracking domains its code uses,
+// and can generate a manifest that declares them.
+//
+//	result, err := privacy.Scan(ctx, "./my-app")
 package privacy
 
 import (
-	"bufio"
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/findingorder"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
 )
 
 // Finding from privacy scan.
 type Finding struct {
-	Severity  string `json:"severity"`
+	ID        string `json:"id,omitempty"`
+	Severity  severity.Level `json:"severity"`
 	Guideline string `json:"guideline,omitempty"`
 	Title     string `json:"title"`
 	Detail    string `json:"detail"`
@@ -20,6 +34,21 @@ type Finding struct {
 	Line      int    `json:"line,omitempty"`
 }
 
+// ScanFinding converts f to scan.Finding, with "privacy" as its source.
+func (f Finding) ScanFinding() scan.Finding {
+	return scan.Finding{
+		Source:    "privacy",
+		RuleID:    f.ID,
+		Severity:  f.Severity,
+		Guideline: f.Guideline,
+		Title:     f.Title,
+		Detail:    f.Detail,
+		Fix:       f.Fix,
+		File:      f.File,
+		Line:      f.Line,
+	}
+}
+
 // RequiredReasonAPI represents an Apple Required Reason API category.
 type RequiredReasonAPI struct {
 	Name        string           // Human-readable name
@@ -33,10 +62,35 @@ type RequiredReasonAPI struct {
 type ScanResult struct {
 	ProjectPath     string    `json:"project_path"`
 	HasPrivacyInfo  bool      `json:"has_privacy_info"`
+	PrivacyInfoPath string    `json:"privacy_info_path,omitempty"`
 	DetectedAPIs    []string  `json:"detected_apis"`
+	DetectedAPITypes []string `json:"detected_api_types,omitempty"`
 	DeclaredAPIs    []string  `json:"declared_apis"`
 	TrackingSDKs    []string  `json:"tracking_sdks,omitempty"`
+	TrackingHosts   []string  `json:"tracking_hosts,omitempty"`
 	Findings        []Finding `json:"findings"`
+
+	// FilesScanned and Skipped count the source files read and those left
+	// out as binary, minified or too large.
+	FilesScanned int        `json:"files_scanned"`
+	Skipped      SkipCounts `json:"files_skipped,omitempty"`
+}
+
+// SkipCounts tallies files a scan skipped by reason: "binary", "minified"
+// or "too-large".
+type SkipCounts = sourcefile.Counts
+
+// DefaultMaxFileSize is the size above which source files are skipped.
+const DefaultMaxFileSize = sourcefile.DefaultMaxSize
+
+// Options tunes ScanWithOptions.
+type Options struct {
+	// MaxFileSize skips source files larger than this many bytes: 0 means
+	// DefaultMaxFileSize, negative means no limit.
+	MaxFileSize int64
+	// Paths narrows the files read, including the privacy manifest
+	// lookup.
+	Paths scan.Paths
 }
 
 var requiredReasonAPIs = []RequiredReasonAPI{
@@ -113,21 +167,35 @@ var trackingSDKPatterns = []struct {
 	{regexp.MustCompile(`(?i)(ironSource|IronSource)`), "ironSource"},
 }
 
-// Scan runs the privacy analysis on a project directory.
-func Scan(projectPath string) (*ScanResult, error) {
+// Scan runs the privacy analysis on a project directory. It stops early and
+// returns ctx.Err() when ctx is cancelled.
+func Scan(ctx context.Context, projectPath string) (*ScanResult, error) {
+	return ScanWithOptions(ctx, projectPath, Options{})
+}
+
+// ScanWithOptions is Scan with tuning options.
+func ScanWithOptions(ctx context.Context, projectPath string, opts Options) (*ScanResult, error) {
+	start := time.Now()
 	result := &ScanResult{
 		ProjectPath: projectPath,
+		Skipped:     SkipCounts{},
+	}
+	maxSize := opts.MaxFileSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxFileSize
 	}
 
 	// 1. Find PrivacyInfo.xcprivacy
-	privacyInfoPath, privacyContent := findPrivacyManifest(projectPath)
+	privacyInfoPath, privacyContent := findPrivacyManifest(projectPath, opts.Paths)
 	result.HasPrivacyInfo = privacyInfoPath != ""
+	result.PrivacyInfoPath = privacyInfoPath
 
 	if result.HasPrivacyInfo {
 		result.DeclaredAPIs = parsePrivacyManifest(privacyContent)
 	} else {
 		result.Findings = append(result.Findings, Finding{
-			Severity:  "CRITICAL",
+			ID:        "privacy-manifest-missing",
+			Severity:  severity.Critical,
 			Guideline: "5.1.1",
 			Title:     "No PrivacyInfo.xcprivacy found in project",
 			Detail:    "Privacy manifests are required since May 2024. Missing it triggers ITMS-91061.",
@@ -138,32 +206,48 @@ func Scan(projectPath string) (*ScanResult, error) {
 	// 2. Scan code for Required Reason API usage
 	detectedAPIs := make(map[string][]FileHit)
 	trackingSDKsFound := make(map[string]bool)
+	trackingHosts := make(map[string][]DomainHit)
 	hasATT := false
+	crash := newCrashScan()
 
-	skipDirs := map[string]bool{
-		"node_modules": true, ".git": true, "Pods": true,
-		"build": true, "dist": true, ".expo": true,
-		"DerivedData": true, "vendor": true,
-	}
-
-	filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+	walkErr := sourcefile.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		relPath, _ := filepath.Rel(projectPath, path)
 		if err != nil || info.IsDir() {
-			if info != nil && info.IsDir() && skipDirs[info.Name()] {
+			if info != nil && info.IsDir() && (buildDirs[info.Name()] || opts.Paths.SkipDir(relPath)) {
 				return filepath.SkipDir
 			}
 			return nil
 		}
 
+		if !opts.Paths.Includes(relPath) {
+			return nil
+		}
 		lang := detectLang(path)
 		if lang == "" {
+			crash.scanConfig(path, relPath, info.Name())
 			return nil
 		}
 
-		relPath, _ := filepath.Rel(projectPath, path)
-		lines, err := readFileLines(path)
+		if maxSize > 0 && info.Size() > maxSize {
+			result.Skipped[sourcefile.TooLarge]++
+			return nil
+		}
+		lines, err := sourcefile.ReadLines(path)
+		if err == sourcefile.ErrBinary {
+			result.Skipped[sourcefile.Binary]++
+			return nil
+		}
 		if err != nil {
 			return nil
 		}
+		if (lang == "javascript" || lang == "typescript") && sourcefile.IsMinified(path, lines) {
+			result.Skipped[sourcefile.Minified]++
+			return nil
+		}
+		result.FilesScanned++
 
 		fullContent := strings.Join(lines, "\n")
 
@@ -179,6 +263,16 @@ func Scan(projectPath string) (*ScanResult, error) {
 			}
 		}
 
+		crash.scanCode(relPath, lang, lines)
+
+		// Collect tracking endpoints referenced in code
+		for lineNum, line := range lines {
+			for _, hit := range trackingHostsInLine(line) {
+				hit.File, hit.Line = relPath, lineNum+1
+				trackingHosts[hit.Host] = append(trackingHosts[hit.Host], hit)
+			}
+		}
+
 		// Check for Required Reason API usage
 		for _, api := range requiredReasonAPIs {
 			if !langMatch(lang, api.Languages) {
@@ -205,11 +299,21 @@ func Scan(projectPath string) (*ScanResult, error) {
 
 		return nil
 	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
 
 	// 3. Cross-reference detected vs declared
-	for apiType, hits := range detectedAPIs {
+	apiTypes := make([]string, 0, len(detectedAPIs))
+	for apiType := range detectedAPIs {
+		apiTypes = append(apiTypes, apiType)
+	}
+	sort.Strings(apiTypes)
+	for _, apiType := range apiTypes {
+		hits := detectedAPIs[apiType]
 		apiName := hits[0].API
 		result.DetectedAPIs = append(result.DetectedAPIs, apiName)
+		result.DetectedAPITypes = append(result.DetectedAPITypes, apiType)
 
 		declared := false
 		for _, d := range result.DeclaredAPIs {
@@ -221,7 +325,8 @@ func Scan(projectPath string) (*ScanResult, error) {
 
 		if !declared && result.HasPrivacyInfo {
 			result.Findings = append(result.Findings, Finding{
-				Severity:  "CRITICAL",
+				ID:        requiredReasonCheckID(apiType),
+				Severity:  severity.Critical,
 				Guideline: "5.1.1",
 				Title:     "Required Reason API used but not declared: " + apiName,
 				Detail:    apiType + " usage detected in code but not in PrivacyInfo.xcprivacy. Found in " + formatHits(hits),
@@ -229,7 +334,8 @@ func Scan(projectPath string) (*ScanResult, error) {
 			})
 		} else if !declared && !result.HasPrivacyInfo {
 			result.Findings = append(result.Findings, Finding{
-				Severity:  "CRITICAL",
+				ID:        requiredReasonCheckID(apiType),
+				Severity:  severity.Critical,
 				Guideline: "5.1.1",
 				Title:     "Required Reason API used without privacy manifest: " + apiName,
 				Detail:    apiType + " detected in code. Found in " + formatHits(hits),
@@ -242,11 +348,13 @@ func Scan(projectPath string) (*ScanResult, error) {
 	for sdk := range trackingSDKsFound {
 		result.TrackingSDKs = append(result.TrackingSDKs, sdk)
 	}
+	sort.Strings(result.TrackingSDKs)
 
 	if len(trackingSDKsFound) > 0 && !hasATT {
 		sdkList := strings.Join(result.TrackingSDKs, ", ")
 		result.Findings = append(result.Findings, Finding{
-			Severity:  "CRITICAL",
+			ID:        "tracking-without-att",
+			Severity:  severity.Critical,
 			Guideline: "5.1.2",
 			Title:     "Tracking SDKs detected without ATT implementation",
 			Detail:    "Found: " + sdkList + ". App Tracking Transparency prompt is required before any tracking.",
@@ -254,19 +362,63 @@ func Scan(projectPath string) (*ScanResult, error) {
 		})
 	}
 
-	// 5. Check if privacy manifest declares tracking but no tracking SDKs found
+	for host := range trackingHosts {
+		result.TrackingHosts = append(result.TrackingHosts, host)
+	}
+	sort.Strings(result.TrackingHosts)
+
+	// 5. Check tracking endpoints in code vs NSPrivacyTrackingDomains
+	if result.HasPrivacyInfo {
+		result.Findings = append(result.Findings, checkTrackingDomains(privacyInfoPath, privacyContent, trackingHosts)...)
+	}
+
+	// 6. Check if privacy manifest declares tracking but no tracking SDKs found
 	if result.HasPrivacyInfo && strings.Contains(privacyContent, "NSPrivacyTracking") && strings.Contains(privacyContent, "<true/>") && len(trackingSDKsFound) == 0 {
 		result.Findings = append(result.Findings, Finding{
-			Severity: "INFO",
+			ID:       "tracking-declared-unused",
+			Severity: severity.Info,
 			Title:    "Privacy manifest declares tracking but no tracking SDKs detected",
 			Detail:   "NSPrivacyTracking is set to true but no known tracking SDKs were found in code.",
 			Fix:      "Verify if your app actually tracks users. If not, set NSPrivacyTracking to false.",
 		})
 	}
 
+	// 7. Check third-party SDKs on Apple's commonly used list
+	result.Findings = append(result.Findings, auditSDKs(projectPath, opts.Paths)...)
+
+	// 8. Check crash reporters' data collection against the manifests
+	var declared map[string]bool
+	if result.HasPrivacyInfo && len(crash.sdks) > 0 {
+		declared = map[string]bool{}
+		manifests, _ := manifestsFromDir(projectPath)
+		for _, m := range manifests {
+			for _, d := range m.CollectedData {
+				declared[d.Type] = true
+			}
+		}
+	}
+	result.Findings = append(result.Findings, crash.findings(declared)...)
+
+	SortFindings(result.Findings)
+	slog.DebugContext(ctx, "privacy scan finished", "root", projectPath, "manifest", result.PrivacyInfoPath,
+		"files", result.FilesScanned, "skipped", result.Skipped.Total(), "required_reason_apis", len(result.DetectedAPIs), "tracking_sdks", len(result.TrackingSDKs), "duration", time.Since(start))
 	return result, nil
 }
 
+// SortFindings puts findings in report order: severity, then file, line and
+// title.
+func SortFindings(findings []Finding) {
+	findingorder.Sort(findings, func(f Finding) findingorder.Key {
+		return findingorder.Key{
+			Severity: f.Severity,
+			File:     f.File,
+			Line:     f.Line,
+			Title:    f.Title,
+			Detail:   f.Detail,
+		}
+	})
+}
+
 type FileHit struct {
 	File string
 	Line int
@@ -297,22 +449,23 @@ func formatHits(hits []FileHit) string {
 	return s
 }
 
-func findPrivacyManifest(root string) (string, string) {
+// buildDirs are build output directories, which are never scanned.
+var buildDirs = map[string]bool{"build": true, "dist": true, "DerivedData": true}
+
+func findPrivacyManifest(root string, paths scan.Paths) (string, string) {
 	var found string
 	var content string
 
-	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	sourcefile.Walk(root, func(path string, info os.FileInfo, err error) error {
+		rel, _ := filepath.Rel(root, path)
 		if err != nil || info.IsDir() {
-			if info != nil && info.IsDir() {
-				name := info.Name()
-				if name == "node_modules" || name == ".git" || name == "Pods" || name == "build" || name == "DerivedData" {
-					return filepath.SkipDir
-				}
+			if info != nil && info.IsDir() && (buildDirs[info.Name()] || paths.SkipDir(rel)) {
+				return filepath.SkipDir
 			}
 			return nil
 		}
 
-		if strings.ToLower(info.Name()) == "privacyinfo.xcprivacy" {
+		if strings.ToLower(info.Name()) == "privacyinfo.xcprivacy" && paths.Includes(rel) {
 			found = path
 			data, _ := os.ReadFile(path)
 			content = string(data)
@@ -358,19 +511,3 @@ func langMatch(lang string, languages []string) bool {
 	}
 	return false
 }
-
-func readFileLines(path string) ([]string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var lines []string
-	scanner := bufio.NewScanner(f)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-	}
-	return lines, scanner.Err()
-}
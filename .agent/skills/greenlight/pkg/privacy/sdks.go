@@ -0,0 +1,291 @@
+package privacy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/internal/deps"
+	"github.com/RevylAI/greenlight/internal/sourcefile"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// commonSDK is an SDK on Apple's list of commonly used third-party SDKs,
+// which must ship a privacy manifest, and a signature when used as a binary
+// dependency, or App Store Connect rejects the upload (ITMS-91061,
+// ITMS-91065). See developer.apple.com/support/third-party-SDK-requirements.
+type commonSDK struct {
+	Name     string
+	Pods     []string // CocoaPods names
+	Packages []string // Swift package identities
+	// Min is the first release with a privacy manifest, where known.
+	Min string
+}
+
+var commonSDKs = []commonSDK{
+	{"Abseil", []string{"abseil"}, []string{"abseil-cpp-binary", "abseil-cpp-swiftpm"}, "1.20240116.1"},
+	{"AFNetworking", []string{"AFNetworking"}, []string{"afnetworking"}, ""},
+	{"Alamofire", []string{"Alamofire"}, []string{"alamofire"}, "5.9.0"},
+	{"AppAuth", []string{"AppAuth"}, []string{"appauth-ios"}, "1.7.0"},
+	{"BoringSSL", []string{"BoringSSL-GRPC"}, []string{"boringssl-swiftpm"}, "0.0.32"},
+	{"Capacitor", []string{"Capacitor", "CapacitorCordova"}, []string{"capacitor-swift-pm"}, ""},
+	{"Charts", []string{"Charts", "DGCharts"}, []string{"charts"}, "5.1.0"},
+	{"DKImagePickerController", []string{"DKImagePickerController"}, []string{"dkimagepickercontroller"}, ""},
+	{"DKPhotoGallery", []string{"DKPhotoGallery"}, []string{"dkphotogallery"}, ""},
+	{"Facebook SDK", []string{"FBAEMKit", "FBSDKCoreKit", "FBSDKCoreKit_Basics", "FBSDKLoginKit", "FBSDKShareKit"}, []string{"facebook-ios-sdk"}, "17.0.0"},
+	{"Firebase", []string{"FirebaseABTesting", "FirebaseAuth", "FirebaseCore", "FirebaseCoreDiagnostics", "FirebaseCoreExtension", "FirebaseCoreInternal", "FirebaseCrashlytics", "FirebaseDynamicLinks", "FirebaseFirestore", "FirebaseInstallations", "FirebaseMessaging", "FirebaseRemoteConfig"}, []string{"firebase-ios-sdk"}, "10.22.0"},
+	{"FMDB", []string{"FMDB"}, []string{"fmdb"}, "2.7.10"},
+	{"GoogleDataTransport", []string{"GoogleDataTransport"}, []string{"googledatatransport"}, "9.3.0"},
+	{"GoogleSignIn", []string{"GoogleSignIn"}, []string{"googlesignin-ios"}, "7.1.0"},
+	{"GoogleToolboxForMac", []string{"GoogleToolboxForMac"}, []string{"google-toolbox-for-mac"}, ""},
+	{"GoogleUtilities", []string{"GoogleUtilities"}, []string{"googleutilities"}, "7.12.0"},
+	{"gRPC", []string{"gRPC-C++", "gRPC-Core"}, []string{"grpc-binary", "grpc-ios"}, "1.62.0"},
+	{"GTMAppAuth", []string{"GTMAppAuth"}, []string{"gtmappauth"}, "4.1.1"},
+	{"GTMSessionFetcher", []string{"GTMSessionFetcher"}, []string{"gtm-session-fetcher"}, "3.3.0"},
+	{"Hermes", []string{"hermes-engine"}, nil, ""},
+	{"IQKeyboardManager", []string{"IQKeyboardManager", "IQKeyboardManagerSwift"}, []string{"iqkeyboardmanager"}, "6.5.16"},
+	{"Kingfisher", []string{"Kingfisher"}, []string{"kingfisher"}, "7.10.0"},
+	{"leveldb", []string{"leveldb-library"}, []string{"leveldb"}, "1.22.5"},
+	{"Lottie", []string{"lottie-ios"}, []string{"lottie-ios", "lottie-spm"}, "4.4.0"},
+	{"MBProgressHUD", []string{"MBProgressHUD"}, []string{"mbprogresshud"}, ""},
+	{"nanopb", []string{"nanopb"}, []string{"nanopb"}, "2.30910.0"},
+	{"OneSignal", []string{"OneSignal", "OneSignalXCFramework", "OneSignalCore", "OneSignalExtension", "OneSignalOutcomes"}, []string{"onesignal-xcframework", "onesignal-ios-sdk"}, ""},
+	{"OpenSSL", []string{"OpenSSL-Universal"}, []string{"openssl"}, ""},
+	{"OrderedSet", []string{"OrderedSet"}, nil, ""},
+	{"Promises", []string{"PromisesObjC", "PromisesSwift"}, []string{"promises"}, "2.4.0"},
+	{"Protobuf", []string{"Protobuf", "SwiftProtobuf"}, []string{"swift-protobuf"}, ""},
+	{"Reachability", []string{"Reachability"}, nil, ""},
+	{"ReachabilitySwift", []string{"ReachabilitySwift"}, []string{"reachability.swift"}, "5.2.0"},
+	{"Realm", []string{"Realm", "RealmSwift"}, []string{"realm-swift"}, ""},
+	{"RxSwift", []string{"RxSwift", "RxCocoa", "RxRelay"}, []string{"rxswift"}, "6.7.0"},
+	{"SDWebImage", []string{"SDWebImage"}, []string{"sdwebimage"}, "5.18.0"},
+	{"SnapKit", []string{"SnapKit"}, []string{"snapkit"}, "5.7.0"},
+	{"Starscream", []string{"Starscream"}, []string{"starscream"}, "4.0.8"},
+	{"SVProgressHUD", []string{"SVProgressHUD"}, []string{"svprogresshud"}, "2.3.0"},
+	{"SwiftyGif", []string{"SwiftyGif"}, []string{"swiftygif"}, ""},
+	{"SwiftyJSON", []string{"SwiftyJSON"}, []string{"swiftyjson"}, "5.0.2"},
+	{"Toast", []string{"Toast"}, nil, ""},
+	{"Toast-Swift", []string{"Toast-Swift"}, []string{"toast-swift"}, "5.1.0"},
+}
+
+// resolvedDep is a dependency pinned in Podfile.lock or Package.resolved.
+type resolvedDep struct {
+	Name    string // pod name or package identity
+	Version string
+	SDK     commonSDK
+	// File and Line locate the pin; Dir is the installed sources, or "".
+	File string
+	Line int
+	Dir  string
+}
+
+// auditSDKs checks the CocoaPods and Swift packages the project resolves
+// against Apple's list: each listed SDK must ship a privacy manifest, and
+// binary XCFrameworks a signature. Installed sources (Pods/,
+// SourcePackages/checkouts, .build/checkouts) are inspected; without them,
+// the pinned version is compared with the first compliant release.
+func auditSDKs(root string, paths scan.Paths) []Finding {
+	var locks, checkouts []string
+	sourcefile.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		if info.IsDir() {
+			switch {
+			case deps.IsCheckouts(p):
+				checkouts = append(checkouts, p)
+				return filepath.SkipDir
+			case buildDirs[info.Name()]:
+				checkouts = append(checkouts, deps.BuildCheckouts(p)...)
+				return filepath.SkipDir
+			case paths.SkipDir(rel):
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if (info.Name() == "Podfile.lock" || info.Name() == "Package.resolved") && paths.Includes(rel) {
+			locks = append(locks, p)
+		}
+		return nil
+	})
+
+	var pinned []resolvedDep
+	for _, lock := range locks {
+		spm := filepath.Base(lock) == "Package.resolved"
+		var pins []deps.Dep
+		if spm {
+			pins, _ = deps.Packages(lock)
+		} else {
+			pins, _ = deps.Pods(lock)
+		}
+		rel, _ := filepath.Rel(root, lock)
+		for _, pin := range pins {
+			sdk, ok := lookupSDK(pin.Name, spm)
+			if !ok {
+				continue
+			}
+			pinned = append(pinned, resolvedDep{
+				Name:    pin.Name,
+				Version: pin.Version,
+				SDK:     sdk,
+				File:    filepath.ToSlash(rel),
+				Line:    pin.Line,
+				Dir:     deps.Source(lock, pin, checkouts),
+			})
+		}
+	}
+
+	// The pods of one SDK (FirebaseAuth, FirebaseCore, ...) share findings.
+	var order []string
+	groups := map[string][]resolvedDep{}
+	for _, d := range pinned {
+		key := d.SDK.Name + "\x00" + d.File
+		if groups[key] == nil {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], d)
+	}
+	var findings []Finding
+	for _, key := range order {
+		findings = append(findings, sdkFindings(root, groups[key])...)
+	}
+	return findings
+}
+
+// sdkFindings checks the pinned pods or package of one listed SDK. A
+// dependency lacks a privacy manifest if its installed sources have none
+// or, when they aren't installed, its version predates the first release
+// with one.
+func sdkFindings(root string, pins []resolvedDep) []Finding {
+	sdk := pins[0].SDK
+	var stale []resolvedDep
+	var sources bool
+	for _, d := range pins {
+		switch {
+		case d.Dir != "":
+			sources = true
+			if !hasManifest(d.Dir) {
+				stale = append(stale, d)
+			}
+		case sdk.Min != "" && d.Version != "" && appversion.Compare(d.Version, sdk.Min) < 0:
+			stale = append(stale, d)
+		}
+	}
+
+	var findings []Finding
+	if len(stale) > 0 {
+		detail := fmt.Sprintf("%s is on Apple's list of commonly used SDKs, which must include a privacy manifest; App Store Connect rejects uploads without one (ITMS-91061).", sdk.Name)
+		if sources {
+			detail += " No PrivacyInfo.xcprivacy was found in the installed sources of " + pinList(stale) + "."
+		} else {
+			detail += fmt.Sprintf(" %s predates the first release with one (%s).", pinList(stale), sdk.Min)
+		}
+		findings = append(findings, Finding{
+			ID:        "sdk-privacy-manifest-missing",
+			Severity:  severity.Critical,
+			Guideline: "5.1.1",
+			Title:     sdk.Name + " has no privacy manifest: " + pinList(stale),
+			Detail:    detail,
+			Fix:       upgradeFix(stale, "a release that ships a privacy manifest"),
+			File:      stale[0].File,
+			Line:      stale[0].Line,
+		})
+	}
+
+	for _, d := range pins {
+		if d.Dir == "" {
+			continue
+		}
+		for _, fw := range unsignedXCFrameworks(d.Dir) {
+			rel, _ := filepath.Rel(root, fw)
+			findings = append(findings, Finding{
+				ID:        "sdk-signature-missing",
+				Severity:  severity.Critical,
+				Guideline: "5.1.1",
+				Title:     fmt.Sprintf("%s binary is not signed: %s", sdk.Name, filepath.Base(fw)),
+				Detail:    fmt.Sprintf("%s is on Apple's list of commonly used SDKs, whose XCFrameworks must be signed by their developer; App Store Connect rejects unsigned copies (ITMS-91065). %s %s ships %s without a signature.", sdk.Name, d.Name, d.Version, filepath.ToSlash(rel)),
+				Fix:       upgradeFix([]resolvedDep{d}, "a release that ships signed XCFrameworks"),
+				File:      d.File,
+				Line:      d.Line,
+			})
+		}
+	}
+	return findings
+}
+
+// pinList names pins with their versions: "FirebaseAuth 10.18.0, FirebaseCore 10.18.0".
+func pinList(pins []resolvedDep) string {
+	names := make([]string, len(pins))
+	for i, d := range pins {
+		names[i] = strings.TrimSpace(d.Name + " " + d.Version)
+	}
+	return strings.Join(names, ", ")
+}
+
+// upgradeFix tells the user what to upgrade pins, all of one SDK, to.
+func upgradeFix(pins []resolvedDep, release string) string {
+	sdk := pins[0].SDK
+	how := "File → Packages → Update to Latest Package Versions in Xcode"
+	if !strings.HasSuffix(pins[0].File, "Package.resolved") {
+		names := make([]string, len(pins))
+		for i, d := range pins {
+			names[i] = d.Name
+		}
+		how = "pod update " + strings.Join(names, " ")
+	}
+	if sdk.Min != "" && appversion.Compare(pins[0].Version, sdk.Min) < 0 {
+		return fmt.Sprintf("Upgrade %s to %s or later (%s).", sdk.Name, sdk.Min, how)
+	}
+	return fmt.Sprintf("Upgrade %s to %s (%s), or replace it if none exists.", sdk.Name, release, how)
+}
+
+// lookupSDK finds the listed SDK a pod name or package identity belongs to.
+func lookupSDK(name string, pkg bool) (commonSDK, bool) {
+	for _, sdk := range commonSDKs {
+		names := sdk.Pods
+		if pkg {
+			names = sdk.Packages
+		}
+		for _, n := range names {
+			if strings.EqualFold(n, name) {
+				return sdk, true
+			}
+		}
+	}
+	return commonSDK{}, false
+}
+
+// hasManifest reports whether dir has a PrivacyInfo.xcprivacy anywhere.
+func hasManifest(dir string) bool {
+	found := false
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && strings.EqualFold(info.Name(), "PrivacyInfo.xcprivacy") {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// unsignedXCFrameworks lists the XCFrameworks under dir without a code
+// signature (_CodeSignature at the XCFramework's root).
+func unsignedXCFrameworks(dir string) []string {
+	var unsigned []string
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.IsDir() || !strings.HasSuffix(info.Name(), ".xcframework") {
+			return nil
+		}
+		if sig, err := os.Stat(filepath.Join(p, "_CodeSignature")); err != nil || !sig.IsDir() {
+			unsigned = append(unsigned, p)
+		}
+		return filepath.SkipDir
+	})
+	sort.Strings(unsigned)
+	return unsigned
+}
@@ -0,0 +1,181 @@
+package scan
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/RevylAI/greenlight/internal/pathglob"
+)
+
+// DefaultExclude are the dependency and tooling directories that are never
+// scanned unless an --include pattern names a path inside them
+// ("Pods/MySDK/**"), or, for node_modules, the package is first-party.
+var DefaultExclude = []string{"node_modules", ".git", "Pods", ".expo", ".next", "vendor"}
+
+// Paths narrows the files a scan reads with --include and --exclude
+// patterns: gitignore-style globs relative to the project ("ios/**",
+// "examples", "**/*.generated.swift"). A pattern naming a directory covers
+// everything under it. With includes, only files matching one are read;
+// files matching an exclude are never read. The zero Paths reads the whole
+// project less DefaultExclude.
+type Paths struct {
+	Include []string
+	Exclude []string
+	// FirstParty are npm packages ("@acme/ui") that are the team's own code
+	// and scanned inside node_modules, including pnpm's .pnpm store.
+	FirstParty []string
+}
+
+// NewPaths builds Paths from flag or config values, splitting
+// comma-separated patterns and rejecting malformed ones.
+func NewPaths(include, exclude []string) (Paths, error) {
+	var p Paths
+	var err error
+	if p.Include, err = pathPatterns("--include", include); err != nil {
+		return Paths{}, err
+	}
+	if p.Exclude, err = pathPatterns("--exclude", exclude); err != nil {
+		return Paths{}, err
+	}
+	return p, nil
+}
+
+func pathPatterns(flag string, values []string) ([]string, error) {
+	var patterns []string
+	for _, v := range values {
+		for _, p := range strings.Split(v, ",") {
+			p = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(p)), "./")
+			if p == "" {
+				continue
+			}
+			if !pathglob.Valid(p) {
+				return nil, fmt.Errorf("%s %q: %w", flag, p, path.ErrBadPattern)
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns, nil
+}
+
+// SkipDir reports whether the walk should leave out the directory at rel,
+// a path relative to the project root: it is excluded, or no include
+// pattern can match anything under it.
+func (p Paths) SkipDir(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if rel == "." || rel == "" {
+		return false
+	}
+	if p.excluded(rel) {
+		return true
+	}
+	if len(p.Include) == 0 {
+		return false
+	}
+	for _, inc := range p.Include {
+		if pathglob.Reaches(inc, rel) {
+			return false
+		}
+	}
+	return true
+}
+
+// Includes reports whether the file at rel, a path relative to the project
+// root, should be read.
+func (p Paths) Includes(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if p.excluded(rel) {
+		return false
+	}
+	if len(p.Include) == 0 {
+		return true
+	}
+	for _, inc := range p.Include {
+		if pathglob.Match(inc, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// excluded reports whether rel matches an exclude, or a default exclude no
+// include reopens.
+func (p Paths) excluded(rel string) bool {
+	for _, ex := range p.Exclude {
+		if pathglob.Match(ex, rel) {
+			return true
+		}
+	}
+	for _, ex := range DefaultExclude {
+		if pathglob.Match(ex, rel) && !p.reopens(rel) && (ex != "node_modules" || !p.firstParty(rel)) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstParty reports whether rel is a first-party package in its closest
+// node_modules, is inside one, or leads to one.
+func (p Paths) firstParty(rel string) bool {
+	segs := strings.Split(rel, "/")
+	i := slices.Index(segs, "node_modules")
+	if i < 0 {
+		return false
+	}
+	for j := len(segs) - 1; j > i; j-- {
+		if segs[j] == "node_modules" {
+			i = j
+			break
+		}
+	}
+	rest := segs[i+1:]
+	for _, name := range p.FirstParty {
+		pkg := strings.Split(name, "/")
+		if len(rest) > 0 && rest[0] == ".pnpm" {
+			// pnpm keeps packages in node_modules/.pnpm/@acme+ui@1.2.0/node_modules/@acme/ui.
+			if len(rest) == 1 || strings.HasPrefix(rest[1], strings.Join(pkg, "+")+"@") {
+				return true
+			}
+			continue
+		}
+		n := min(len(rest), len(pkg))
+		if slices.Equal(rest[:n], pkg[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+// reopens reports whether an include pattern spells out a path into rel,
+// starting with a literal directory name, which lifts the default excludes
+// along that path.
+func (p Paths) reopens(rel string) bool {
+	for _, inc := range p.Include {
+		first, _, _ := strings.Cut(strings.TrimPrefix(inc, "/"), "/")
+		if pathglob.Anchored(inc) && !strings.ContainsAny(first, "*?[") && pathglob.Reaches(inc, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge layers flag values over p, a project's configured paths: the
+// flags' includes replace p's, and their excludes add to p's.
+func (p Paths) Merge(flags Paths) Paths {
+	out := Paths{
+		Include:    p.Include,
+		Exclude:    append(append([]string(nil), p.Exclude...), flags.Exclude...),
+		FirstParty: append(append([]string(nil), p.FirstParty...), flags.FirstParty...),
+	}
+	if len(flags.Include) > 0 {
+		out.Include = flags.Include
+	}
+	return out
+}
+
+// Empty reports whether p reads the whole project less DefaultExclude.
+func (p Paths) Empty() bool {
+	return len(p.Include) == 0 && len(p.Exclude) == 0 && len(p.FirstParty) == 0
+}
@@ -0,0 +1,218 @@
+// Package scan is the common interface behind every greenlight scanner.
+// Each scanner — codescan, privacy, ipa, local metadata, Xcode settings and
+// the App Store Connect checks — registers itself here, and preflight, the
+// CLI and the reporters run whatever is registered, so a new scanner only
+// has to call Register.
+//
+// Finding is the adapter layer between the scanners and their consumers,
+// not the type scanners build. Each scanner keeps its own finding type,
+// with what only it reports (an App Store Connect check's tier, a secret's
+// verification, the file an Xcode setting is in), and converts it to
+// Finding in Run; commands, reporters and embedders read only Finding.
+//
+//	type licenses struct{}
+//
+//	func (licenses) Name() string { return "licenses" }
+//	func (licenses) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) { ... }
+//
+//	func init() { scan.Register(licenses{}) }
+package scan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/RevylAI/greenlight/internal/findingorder"
+	"github.com/RevylAI/greenlight/internal/xcodeproj"
+	"github.com/RevylAI/greenlight/pkg/asc"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Finding is a single issue reported by any scanner, converted from the
+// scanner's own finding type.
+type Finding struct {
+	Source    string         `json:"source"` // scanner name: "codescan", "privacy", "ipa", "metadata", "xcode", "asc"
+	RuleID    string         `json:"rule_id,omitempty"`
+	Severity  severity.Level `json:"severity"`
+	Guideline string         `json:"guideline,omitempty"`
+	Title     string         `json:"title"`
+	Detail    string         `json:"detail"`
+	Fix       string         `json:"fix,omitempty"`
+	File      string         `json:"file,omitempty"`
+	Line      int            `json:"line,omitempty"`
+	Code      string         `json:"code,omitempty"`
+	Secret    string         `json:"-"` // matched credential, for redaction
+	// GuidelineTitle and GuidelineURL resolve Guideline for reports.
+	GuidelineTitle string `json:"guideline_title,omitempty"`
+	GuidelineURL   string `json:"guideline_url,omitempty"`
+	// Fingerprint identifies this occurrence across runs (rule, file and
+	// normalized line).
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Risk is the estimated likelihood, from 0 to 100, that the finding
+	// gets the app rejected (see internal/risk).
+	Risk int `json:"risk,omitempty"`
+	// Effort and Owner suggest the size of the fix and the team it belongs
+	// to (see internal/triage).
+	Effort string `json:"effort,omitempty"`
+	Owner  string `json:"owner,omitempty"`
+	// Owners are the CODEOWNERS owners of File.
+	Owners []string `json:"owners,omitempty"`
+	// Check is the App Store Connect check that reported the finding.
+	Check string `json:"check,omitempty"`
+}
+
+// SortFindings puts findings in report order: severity, then source, file,
+// line and title.
+func SortFindings(findings []Finding) {
+	findingorder.Sort(findings, func(f Finding) findingorder.Key {
+		return findingorder.Key{
+			Severity: f.Severity,
+			Source:   f.Source,
+			File:     f.File,
+			Line:     f.Line,
+			Title:    f.Title,
+			Detail:   f.Detail,
+		}
+	})
+}
+
+// Target is what a run scans. Scanners that need more than a project (the
+// ipa scanner an IPAPath, asc a Client and AppID) implement Applier.
+type Target struct {
+	ProjectPath string
+	IPAPath     string
+	// Build selects the Xcode scheme and configuration to check; empty
+	// checks every Release-like configuration.
+	Build xcodeproj.Selection
+
+	// Client and AppID identify the App Store Connect record to check.
+	Client *asc.Client
+	AppID  string
+
+	// Selection narrows the scanners, checks and findings of the run.
+	Selection Selection
+	// Paths narrows the project files scanners read. preflight layers them
+	// over the paths section of the project's .greenlight.yml.
+	Paths Paths
+	// Advisories lets scanners look dependencies up in online advisory
+	// databases (OSV), which sends the dependency list over the network.
+	Advisories bool
+	// ProbeEndpoints lets scanners request the app's API base URLs to
+	// check that its backend is reachable.
+	ProbeEndpoints bool
+
+	// Facts collects what scanners learn about the app; nil discards it.
+	Facts *Facts
+	// Cache shares expensive inputs between the scanners of one run; nil
+	// disables sharing.
+	Cache *Cache
+}
+
+// Scanner is one source of findings.
+type Scanner interface {
+	// Name identifies the scanner and is the Source of its findings.
+	Name() string
+	// Run scans target. Cancelling ctx stops the scan and Run returns
+	// ctx.Err().
+	Run(ctx context.Context, target Target) ([]Finding, error)
+}
+
+// Applier is implemented by scanners that only apply to some targets.
+type Applier interface {
+	Applies(target Target) bool
+}
+
+// Applies reports whether s has anything to check in target.
+func Applies(s Scanner, target Target) bool {
+	a, ok := s.(Applier)
+	return !ok || a.Applies(target)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Scanner{}
+)
+
+// Register makes s available to every command. It panics if s is nil or a
+// scanner with the same name is already registered.
+func Register(s Scanner) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if s == nil {
+		panic("scan: Register scanner is nil")
+	}
+	if _, dup := registry[s.Name()]; dup {
+		panic(fmt.Sprintf("scan: Register called twice for scanner %q", s.Name()))
+	}
+	registry[s.Name()] = s
+}
+
+// All returns the registered scanners sorted by name.
+func All() []Scanner {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	all := make([]Scanner, 0, len(registry))
+	for _, s := range registry {
+		all = append(all, s)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
+
+// For returns the registered scanners that apply to target, sorted by name.
+func For(target Target) []Scanner {
+	var scanners []Scanner
+	for _, s := range All() {
+		if Applies(s, target) {
+			scanners = append(scanners, s)
+		}
+	}
+	return scanners
+}
+
+// Lookup returns the scanner registered under name.
+func Lookup(name string) (Scanner, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := registry[name]
+	return s, ok
+}
+
+// Facts is what scanners learn about the app besides findings. Scanners run
+// concurrently, so they change it through Update.
+type Facts struct {
+	mu sync.Mutex
+
+	AppName        string
+	BundleID       string
+	HasPrivacyInfo bool
+	DetectedAPIs   []string
+	TrackingSDKs   []string
+}
+
+// Update calls fn with f locked. It does nothing when f is nil.
+func (f *Facts) Update(fn func(*Facts)) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f)
+}
+
+// Cache holds values shared by the scanners of one run.
+type Cache struct {
+	m sync.Map // key -> func() (any, error)
+}
+
+// Load returns the value for key, calling load the first time it is
+// requested. A nil Cache calls load every time.
+func (c *Cache) Load(key string, load func() (any, error)) (any, error) {
+	if c == nil {
+		return load()
+	}
+	once, _ := c.m.LoadOrStore(key, sync.OnceValues(load))
+	return once.(func() (any, error))()
+}
@@ -0,0 +1,138 @@
+package scan
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Selection narrows a run with --only and --skip patterns. A pattern is
+// either a name — a scanner ("codescan"), an App Store Connect check
+// ("url-reachability") or a rule ID ("hardcoded-secrets"), with * and ?
+// wildcards — or a guideline section ("5.1", "5.1.*", "§2.3"), which also
+// matches its subsections. The zero Selection selects everything.
+type Selection struct {
+	Only []string
+	Skip []string
+}
+
+// NewSelection builds a Selection from flag values, splitting
+// comma-separated patterns and rejecting malformed ones.
+func NewSelection(only, skip []string) (Selection, error) {
+	var s Selection
+	var err error
+	if s.Only, err = selectionPatterns("--only", only); err != nil {
+		return Selection{}, err
+	}
+	if s.Skip, err = selectionPatterns("--skip", skip); err != nil {
+		return Selection{}, err
+	}
+	return s, nil
+}
+
+func selectionPatterns(flag string, values []string) ([]string, error) {
+	var patterns []string
+	for _, v := range values {
+		for _, p := range strings.Split(v, ",") {
+			p = strings.ToLower(strings.TrimSpace(p))
+			if p == "" {
+				continue
+			}
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("%s %q: %w", flag, p, err)
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns, nil
+}
+
+// Empty reports whether s selects everything.
+func (s Selection) Empty() bool {
+	return len(s.Only) == 0 && len(s.Skip) == 0
+}
+
+// Runs reports whether the scanner or check called name should run. units
+// names everything at the same level; an --only pattern that matches none
+// of them selects rules or guidelines inside every unit, so it doesn't rule
+// any out.
+func (s Selection) Runs(name string, units []string) bool {
+	for _, p := range s.Skip {
+		if !guidelinePattern(p) && matchName(p, name) {
+			return false
+		}
+	}
+	if len(s.Only) == 0 {
+		return true
+	}
+	for _, p := range s.Only {
+		if guidelinePattern(p) || matchName(p, name) || !matchAny(p, units) {
+			return true
+		}
+	}
+	return false
+}
+
+// Keeps reports whether a finding with the given guideline, reported under
+// names (its scanner, check and rule ID), is selected.
+func (s Selection) Keeps(guideline string, names ...string) bool {
+	for _, p := range s.Skip {
+		if s.matches(p, guideline, names) {
+			return false
+		}
+	}
+	if len(s.Only) == 0 {
+		return true
+	}
+	for _, p := range s.Only {
+		if s.matches(p, guideline, names) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Selection) matches(p, guideline string, names []string) bool {
+	if guidelinePattern(p) {
+		return matchGuideline(p, guideline)
+	}
+	return matchAny(p, names)
+}
+
+// guidelinePattern reports whether p names a guideline section rather than
+// a scanner, check or rule.
+func guidelinePattern(p string) bool {
+	p = strings.TrimPrefix(p, "§")
+	return p != "" && p[0] >= '0' && p[0] <= '9'
+}
+
+// matchGuideline matches "5.1", "5.1.*" and "§5.1" against section 5.1 and
+// its subsections; other wildcards match the section as a glob.
+func matchGuideline(p, guideline string) bool {
+	if guideline == "" {
+		return false
+	}
+	p = strings.TrimSuffix(strings.TrimPrefix(p, "§"), ".*")
+	if strings.ContainsAny(p, "*?[") {
+		ok, _ := path.Match(p, guideline)
+		return ok
+	}
+	return guideline == p || strings.HasPrefix(guideline, p+".")
+}
+
+func matchName(p, name string) bool {
+	if name == "" {
+		return false
+	}
+	ok, _ := path.Match(p, strings.ToLower(name))
+	return ok
+}
+
+func matchAny(p string, names []string) bool {
+	for _, name := range names {
+		if matchName(p, name) {
+			return true
+		}
+	}
+	return false
+}
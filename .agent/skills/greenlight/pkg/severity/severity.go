@@ -0,0 +1,85 @@
+// Package severity is the severity scale shared by every greenlight scanner
+// and report: INFO < WARN < CRITICAL. Levels encode as their names in JSON
+// and YAML.
+//
+//	if f.Severity >= severity.Warn { ... }
+package severity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Level is how likely a finding is to cause rejection. Levels compare in
+// order of importance.
+type Level int
+
+const (
+	Info     Level = iota // Best practice recommendation
+	Warn                  // High risk of rejection
+	Critical              // Will almost certainly be rejected
+)
+
+// Levels lists every level, least severe first.
+var Levels = []Level{Info, Warn, Critical}
+
+func (l Level) String() string {
+	switch l {
+	case Info:
+		return "INFO"
+	case Warn:
+		return "WARN"
+	case Critical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Parse reads a level name, case-insensitively. BLOCK and ERROR are accepted
+// for CRITICAL and WARNING for WARN, as older reports and SARIF use them.
+func Parse(s string) (Level, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "INFO", "NOTE":
+		return Info, nil
+	case "WARN", "WARNING":
+		return Warn, nil
+	case "CRITICAL", "BLOCK", "ERROR":
+		return Critical, nil
+	}
+	return Info, fmt.Errorf("unknown severity %q (want info, warn or critical)", s)
+}
+
+// MarshalText encodes l as its name.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText decodes a level name.
+func (l *Level) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// UnmarshalJSON accepts a level name or, from reports written before levels
+// were encoded by name, its number.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		if n < int(Info) || n > int(Critical) {
+			return fmt.Errorf("unknown severity %d", n)
+		}
+		*l = Level(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return l.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,27 @@
+package vulns
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/osv"
+	"github.com/RevylAI/greenlight/pkg/scan"
+)
+
+func init() { scan.Register(scanner{}) }
+
+// scanner reports vulnerable dependencies in every multi-scanner command.
+// The OSV lookup only runs when the target enables advisories, as it sends
+// the dependency list to osv.dev.
+type scanner struct{}
+
+func (scanner) Name() string { return "vulns" }
+
+func (scanner) Run(ctx context.Context, t scan.Target) ([]scan.Finding, error) {
+	var client *osv.Client
+	if t.Advisories {
+		client = osv.NewClient(&http.Client{Timeout: 30 * time.Second})
+	}
+	return Check(ctx, t.ProjectPath, client)
+}
@@ -0,0 +1,232 @@
+// Package vulns reports bundled third-party code with known
+// vulnerabilities, which Apple has started flagging in submitted apps:
+// end-of-life OpenSSL builds, and, when advisories are enabled, pinned
+// dependency versions with advisories in the OSV database.
+package vulns
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RevylAI/greenlight/internal/appversion"
+	"github.com/RevylAI/greenlight/internal/osv"
+	"github.com/RevylAI/greenlight/internal/sbom"
+	"github.com/RevylAI/greenlight/pkg/scan"
+	"github.com/RevylAI/greenlight/pkg/severity"
+)
+
+// Check reads the dependencies pinned under root and reports the
+// vulnerable ones. With a nil client only the offline checks run.
+func Check(ctx context.Context, root string, client *osv.Client) ([]scan.Finding, error) {
+	bom, err := sbom.Collect(root)
+	if err != nil {
+		return nil, err
+	}
+	findings := checkOpenSSL(bom.Components, time.Now())
+	if client == nil {
+		return findings, nil
+	}
+
+	var comps []sbom.Component
+	var pkgs []osv.Package
+	for _, c := range bom.Components {
+		if p, ok := osvPackage(c); ok {
+			comps = append(comps, c)
+			pkgs = append(pkgs, p)
+		}
+	}
+	if len(pkgs) == 0 {
+		return findings, nil
+	}
+	results, err := client.Query(ctx, pkgs)
+	if err != nil {
+		return findings, err
+	}
+	for i, vulns := range results {
+		if len(vulns) > 0 {
+			findings = append(findings, advisoryFinding(comps[i], pkgs[i], vulns))
+		}
+	}
+	return findings, nil
+}
+
+// exactVersion matches a pinned version, not an npm range.
+var exactVersion = regexp.MustCompile(`^v?\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?$`)
+
+// podRepos are the repositories of pods that are also Swift packages, so
+// their advisories are found under OSV's SwiftURL ecosystem. CocoaPods has
+// no OSV ecosystem of its own.
+var podRepos = map[string]string{
+	"Alamofire":     "github.com/Alamofire/Alamofire",
+	"CryptoSwift":   "github.com/krzyzanowskim/CryptoSwift",
+	"GRDB.swift":    "github.com/groue/GRDB.swift",
+	"Kingfisher":    "github.com/onevcat/Kingfisher",
+	"Moya":          "github.com/Moya/Moya",
+	"Realm":         "github.com/realm/realm-swift",
+	"RealmSwift":    "github.com/realm/realm-swift",
+	"SDWebImage":    "github.com/SDWebImage/SDWebImage",
+	"Starscream":    "github.com/daltoniam/Starscream",
+	"SwiftProtobuf": "github.com/apple/swift-protobuf",
+	"SwiftyJSON":    "github.com/SwiftyJSON/SwiftyJSON",
+}
+
+// osvPackage names c the way OSV does, if OSV can know it.
+func osvPackage(c sbom.Component) (osv.Package, bool) {
+	if !exactVersion.MatchString(c.Version) {
+		return osv.Package{}, false
+	}
+	version := strings.TrimPrefix(c.Version, "v")
+	switch c.Ecosystem {
+	case sbom.NPM:
+		return osv.Package{Ecosystem: osv.NPM, Name: c.Name, Version: version}, true
+	case sbom.Swift:
+		u, err := url.Parse(c.Repository)
+		if err != nil || u.Host == "" {
+			return osv.Package{}, false
+		}
+		name := u.Host + "/" + strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+		return osv.Package{Ecosystem: osv.SwiftURL, Name: name, Version: version}, true
+	case sbom.CocoaPods:
+		if repo, ok := podRepos[c.Name]; ok {
+			return osv.Package{Ecosystem: osv.SwiftURL, Name: repo, Version: version}, true
+		}
+	}
+	return osv.Package{}, false
+}
+
+// advisoryFinding reports the advisories affecting one component.
+func advisoryFinding(c sbom.Component, pkg osv.Package, vulns []*osv.Vuln) scan.Finding {
+	sev := severity.Info
+	var lines []string
+	upgrade := ""
+	unfixed := false
+	for _, v := range vulns {
+		rating := strings.ToUpper(v.DatabaseSpecific.Severity)
+		if rating == "HIGH" || rating == "CRITICAL" {
+			sev = severity.Warn
+		}
+		if len(lines) < 5 {
+			line := advisoryName(v)
+			if v.Summary != "" {
+				line += ": " + v.Summary
+			}
+			if rating != "" {
+				line += " [" + rating + "]"
+			}
+			lines = append(lines, line)
+		}
+		// The first release after the pinned version that fixes v.
+		fix := ""
+		for _, f := range v.Fixed(pkg) {
+			if appversion.Compare(f, pkg.Version) > 0 && (fix == "" || appversion.Compare(f, fix) < 0) {
+				fix = f
+			}
+		}
+		switch {
+		case fix == "":
+			unfixed = true
+		case upgrade == "" || appversion.Compare(fix, upgrade) > 0:
+			upgrade = fix
+		}
+	}
+	if len(vulns) > len(lines) {
+		lines = append(lines, fmt.Sprintf("and %d more", len(vulns)-len(lines)))
+	}
+
+	noun := "vulnerability"
+	if len(vulns) > 1 {
+		noun = "vulnerabilities"
+	}
+	fix := fmt.Sprintf("Upgrade %s to %s or later.", c.Name, upgrade)
+	switch {
+	case upgrade == "":
+		fix = fmt.Sprintf("No fixed release of %s is known; replace it or check the advisories for mitigations.", c.Name)
+	case unfixed:
+		fix += " Some advisories have no fix yet; check them for mitigations."
+	}
+	return scan.Finding{
+		Source:    "vulns",
+		RuleID:    "known-vulnerability",
+		Severity:  sev,
+		Guideline: "1.6",
+		Title:     fmt.Sprintf("%s %s has %d known %s", c.Name, c.Version, len(vulns), noun),
+		Detail:    "Advisories in the OSV database affect this version. " + strings.Join(lines, "; ") + ".",
+		Fix:       fix,
+		File:      c.File,
+		Line:      c.Line,
+	}
+}
+
+// advisoryName is the advisory's ID, with its CVE when it has one.
+func advisoryName(v *osv.Vuln) string {
+	i := slices.IndexFunc(v.Aliases, func(a string) bool { return strings.HasPrefix(a, "CVE-") })
+	if i < 0 || strings.HasPrefix(v.ID, "CVE-") {
+		return v.ID
+	}
+	return v.ID + " (" + v.Aliases[i] + ")"
+}
+
+// openSSLEndOfLife is when each OpenSSL release line stopped getting
+// security fixes (openssl-library.org/policies/releasestrat).
+var openSSLEndOfLife = map[string]time.Time{
+	"1.0": date(2019, 12, 31),
+	"1.1": date(2023, 9, 11),
+	"3.0": date(2026, 9, 7),
+	"3.1": date(2025, 3, 14),
+	"3.2": date(2025, 11, 23),
+	"3.3": date(2026, 4, 9),
+	"3.4": date(2026, 10, 22),
+}
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// isOpenSSL reports whether c packages OpenSSL's libcrypto and libssl.
+func isOpenSSL(c sbom.Component) bool {
+	switch c.Ecosystem {
+	case sbom.CocoaPods:
+		return c.Name == "OpenSSL-Universal" || c.Name == "OpenSSL"
+	case sbom.Swift:
+		return strings.Contains(strings.ToLower(c.Repository), "/openssl")
+	}
+	return false
+}
+
+// checkOpenSSL flags OpenSSL builds whose release line no longer gets
+// security fixes. The packages are versioned after the OpenSSL release
+// they build: OpenSSL-Universal 1.1.2200 is OpenSSL 1.1.1v.
+func checkOpenSSL(comps []sbom.Component, now time.Time) []scan.Finding {
+	var findings []scan.Finding
+	for _, c := range comps {
+		if !isOpenSSL(c) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(c.Version, "v"), ".", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		line := parts[0] + "." + parts[1]
+		eol, ok := openSSLEndOfLife[line]
+		if !ok || now.Before(eol) {
+			continue
+		}
+		findings = append(findings, scan.Finding{
+			Source:    "vulns",
+			RuleID:    "openssl-end-of-life",
+			Severity:  severity.Warn,
+			Guideline: "1.6",
+			Title:     fmt.Sprintf("%s %s bundles end-of-life OpenSSL %s", c.Name, c.Version, line),
+			Detail:    fmt.Sprintf("OpenSSL %s stopped receiving security fixes on %s, and Apple flags apps that embed outdated OpenSSL builds.", line, eol.Format("January 2, 2006")),
+			Fix:       "Upgrade to a build of OpenSSL 3.5 (LTS, supported until April 2030), or move to CryptoKit and Network.framework.",
+			File:      c.File,
+			Line:      c.Line,
+		})
+	}
+	return findings
+}